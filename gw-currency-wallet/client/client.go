@@ -0,0 +1,324 @@
+// Package client - Go-клиент HTTP API кошелька
+//
+// Клиент выполняет вход по логину и паролю, сам обновляет JWT-токен перед истечением
+// срока действия (и повторно входит при ответе 401), повторяет идемпотентные запросы
+// при сетевых ошибках и временной недоступности сервиса. Операции с деньгами (POST)
+// не повторяются: повтор мог бы провести операцию дважды.
+//
+//	c, err := client.New(client.Config{
+//		BaseURL:  "http://localhost:8080/api/v1",
+//		Username: "alice",
+//		Password: "secret123",
+//	})
+//	balance, err := c.Balance(ctx)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Значения конфигурации по умолчанию
+const (
+	defaultTimeout      = 10 * time.Second       // Таймаут одного HTTP-запроса
+	defaultMaxRetries   = 3                      // Повторы идемпотентного запроса
+	defaultRetryBackoff = 200 * time.Millisecond // Пауза перед первым повтором (удваивается)
+	tokenRefreshMargin  = 30 * time.Second       // Токен обновляется заранее, за это время до истечения
+)
+
+// Config - параметры клиента
+type Config struct {
+	BaseURL      string        // Адрес API с префиксом версии (например, http://localhost:8080/api/v1)
+	Username     string        // Логин для автоматического входа и обновления токена
+	Password     string        // Пароль для автоматического входа и обновления токена
+	Token        string        // Готовый JWT-токен (если логин не задан, токен не обновляется)
+	Tenant       string        // Арендатор (бренд) в заголовке X-Tenant-ID (пусто - по хосту BaseURL)
+	HTTPClient   *http.Client  // HTTP-клиент (по умолчанию - с таймаутом 10 секунд)
+	MaxRetries   int           // Повторы идемпотентных запросов (0 - по умолчанию 3, <0 - без повторов)
+	RetryBackoff time.Duration // Пауза перед первым повтором, далее удваивается (0 - 200 мс)
+}
+
+// Client - клиент HTTP API кошелька; безопасен для использования из нескольких горутин
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	username     string
+	password     string
+	tenant       string
+	maxRetries   int
+	retryBackoff time.Duration
+
+	mu           sync.Mutex // Защищает токены и время истечения
+	token        string
+	expiresAt    time.Time
+	refreshToken string // Токен обновления из ответа входа (пусто - токен обновляется повторным входом)
+}
+
+// New создает клиент API кошелька
+// Параметры:
+//   - cfg: адрес API, учетные данные и параметры повторов
+//
+// Возвращает:
+//   - *Client: клиент (вход выполняется при первом запросе)
+//   - error: ошибка в конфигурации
+func New(cfg Config) (*Client, error) {
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("некорректный адрес API: %q", cfg.BaseURL)
+	}
+
+	c := &Client{
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		httpClient:   cfg.HTTPClient,
+		username:     cfg.Username,
+		password:     cfg.Password,
+		tenant:       cfg.Tenant,
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	switch {
+	case c.maxRetries == 0:
+		c.maxRetries = defaultMaxRetries
+	case c.maxRetries < 0:
+		c.maxRetries = 0
+	}
+	if c.retryBackoff <= 0 {
+		c.retryBackoff = defaultRetryBackoff
+	}
+	if cfg.Token != "" {
+		c.setToken(cfg.Token)
+	}
+	return c, nil
+}
+
+// Login выполняет вход и сохраняет полученный токен для следующих запросов
+// Обычно вызывать не требуется: клиент входит сам при первом запросе
+func (c *Client) Login(ctx context.Context) error {
+	if c.username == "" {
+		return errors.New("логин и пароль не заданы")
+	}
+
+	var response LoginResponse
+	err := c.do(ctx, http.MethodPost, "/login", LoginRequest{Username: c.username, Password: c.password}, &response, false)
+	if err != nil {
+		return err
+	}
+	c.setTokens(response.Token, response.RefreshToken)
+	return nil
+}
+
+// refresh получает новый JWT-токен по токену обновления
+// Неудачное обновление сбрасывает токен обновления (следующий раз - вход по паролю)
+func (c *Client) refresh(ctx context.Context, refreshToken string) error {
+	var response LoginResponse
+	err := c.do(ctx, http.MethodPost, "/token/refresh", RefreshTokenRequest{RefreshToken: refreshToken}, &response, false)
+	if err != nil {
+		c.mu.Lock()
+		if c.refreshToken == refreshToken {
+			c.refreshToken = ""
+		}
+		c.mu.Unlock()
+		return err
+	}
+	c.setTokens(response.Token, response.RefreshToken)
+	return nil
+}
+
+// Token возвращает текущий JWT-токен (пустая строка, если вход еще не выполнен)
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// setToken сохраняет токен и время его истечения из claim exp
+func (c *Client) setToken(token string) {
+	c.setTokens(token, "")
+}
+
+// setTokens сохраняет JWT-токен и токен обновления
+func (c *Client) setTokens(token, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.expiresAt = tokenExpiry(token)
+	c.refreshToken = refreshToken
+}
+
+// authorize возвращает действующий токен, при необходимости обновляя его токеном обновления или входом
+func (c *Client) authorize(ctx context.Context, force bool) (string, error) {
+	c.mu.Lock()
+	token, expiresAt, refreshToken := c.token, c.expiresAt, c.refreshToken
+	c.mu.Unlock()
+
+	expiring := !expiresAt.IsZero() && time.Until(expiresAt) < tokenRefreshMargin
+	if token != "" && !force && !expiring {
+		return token, nil
+	}
+	if refreshToken != "" && c.refresh(ctx, refreshToken) == nil {
+		return c.Token(), nil
+	}
+	if c.username == "" {
+		if token == "" {
+			return "", errors.New("не задан ни токен, ни логин и пароль")
+		}
+		return token, nil // Обновить нечем - используем имеющийся токен
+	}
+	if err := c.Login(ctx); err != nil {
+		return "", fmt.Errorf("ошибка входа: %w", err)
+	}
+	return c.Token(), nil
+}
+
+// do выполняет запрос к API
+// Параметры:
+//   - method, path: метод и путь относительно BaseURL (с параметрами запроса)
+//   - body: тело запроса для кодирования в JSON (nil - без тела)
+//   - out: структура для декодирования успешного ответа (nil - ответ не читается)
+//   - auth: добавлять ли JWT-токен (при 401 выполняется повторный вход и запрос повторяется один раз)
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}, auth bool) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("ошибка кодирования запроса: %w", err)
+		}
+	}
+
+	reauthorized := false
+	for {
+		token := ""
+		if auth {
+			var err error
+			if token, err = c.authorize(ctx, reauthorized); err != nil {
+				return err
+			}
+		}
+
+		err := c.doWithRetries(ctx, method, path, payload, token, out)
+		var apiErr *APIError
+		if auth && !reauthorized && c.username != "" && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+			reauthorized = true // Токен отозван или истек раньше срока - входим заново
+			continue
+		}
+		return err
+	}
+}
+
+// doWithRetries отправляет запрос, повторяя идемпотентные запросы при временных ошибках
+func (c *Client) doWithRetries(ctx context.Context, method, path string, payload []byte, token string, out interface{}) error {
+	retries := 0
+	if idempotent(method) {
+		retries = c.maxRetries
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, method, path, payload, token, out)
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send выполняет одну попытку запроса
+func (c *Client) send(ctx context.Context, method, path string, payload []byte, token string, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.tenant != "" {
+		req.Header.Set("X-Tenant-ID", c.tenant)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := newAPIError(resp.StatusCode, data)
+		if apiErr.TraceID == "" {
+			apiErr.TraceID = resp.Header.Get("X-Request-ID") // Ответ не в формате JSON (например, от прокси)
+		}
+		return apiErr
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		if pending := parsePending(data); pending != nil {
+			return pending
+		}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("ошибка разбора ответа %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// idempotent сообщает, можно ли безопасно повторить запрос с этим методом
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// tokenExpiry возвращает время истечения JWT-токена из claim exp (подпись не проверяется)
+// Для токена без exp или в неизвестном формате возвращает нулевое время
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.ExpiresAt, 0)
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// APIError - ответ API с кодом ошибки (4xx, 5xx)
+type APIError struct {
+	StatusCode int    // HTTP-код ответа
+	Message    string // Текст ошибки из поля error (или тело ответа)
+	Code       string // Код ошибки из поля code (например, token_expired в ответе 401)
+	TraceID    string // Идентификатор запроса из поля trace_id (для обращения в поддержку)
+}
+
+// Error реализует интерфейс error
+func (e *APIError) Error() string {
+	if e.TraceID != "" {
+		return fmt.Sprintf("API вернул %d: %s (trace_id=%s)", e.StatusCode, e.Message, e.TraceID)
+	}
+	return fmt.Sprintf("API вернул %d: %s", e.StatusCode, e.Message)
+}
+
+// PendingError возвращается, если операция отправлена антифродом на проверку (ответ 202)
+// Операция будет проведена или отклонена после решения администратора
+type PendingError struct {
+	ReviewID int    // Номер проверки в очереди
+	Message  string // Сообщение API
+}
+
+// Error реализует интерфейс error
+func (e *PendingError) Error() string {
+	return fmt.Sprintf("операция отправлена на проверку (№%d)", e.ReviewID)
+}
+
+// transportError - сетевая ошибка: запрос не отправлен или ответ не получен
+type transportError struct {
+	err error
+}
+
+// Error реализует интерфейс error
+func (e *transportError) Error() string {
+	return "ошибка соединения с API: " + e.err.Error()
+}
+
+// Unwrap возвращает исходную ошибку
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// newAPIError формирует ошибку из тела ответа {"error": "...", "code": "...", "trace_id": "..."}
+func newAPIError(status int, body []byte) *APIError {
+	var response struct {
+		Error   string `json:"error"`
+		Code    string `json:"code"`
+		TraceID string `json:"trace_id"`
+	}
+	message := string(body)
+	if err := json.Unmarshal(body, &response); err == nil && response.Error != "" {
+		message = response.Error
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return &APIError{StatusCode: status, Message: message, Code: response.Code, TraceID: response.TraceID}
+}
+
+// parsePending разбирает ответ 202 об отправке операции на проверку
+// Возвращает nil, если ответ не содержит номера проверки
+func parsePending(body []byte) *PendingError {
+	var response struct {
+		Message  string `json:"message"`
+		ReviewID int    `json:"review_id"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || response.ReviewID == 0 {
+		return nil
+	}
+	return &PendingError{ReviewID: response.ReviewID, Message: response.Message}
+}
+
+// retryable сообщает, имеет ли смысл повторить запрос после ошибки
+func retryable(err error) bool {
+	switch e := err.(type) {
+	case *transportError:
+		return true
+	case *APIError:
+		switch e.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
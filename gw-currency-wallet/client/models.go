@@ -0,0 +1,41 @@
+package client
+
+import "gw-currency-wallet/internal/models"
+
+// Типы запросов и ответов API; совпадают с моделями сервиса кошелька
+type (
+	LoginRequest             = models.LoginRequest
+	LoginResponse            = models.LoginResponse
+	RefreshTokenRequest      = models.RefreshTokenRequest
+	CreateUserRequest        = models.CreateUserRequest
+	Balance                  = models.Balance
+	BalanceDisplay           = models.BalanceDisplay
+	BalanceTotal             = models.BalanceTotal
+	BalanceValuation         = models.BalanceValuation
+	DepositRequest           = models.DepositRequest
+	WithdrawRequest          = models.WithdrawRequest
+	TransferRequest          = models.TransferRequest
+	ExchangeRequest          = models.ExchangeRequest
+	ExchangeResponse         = models.ExchangeResponse
+	Transaction              = models.Transaction
+	TransactionHistory       = models.TransactionHistoryResponse
+	UserPreferences          = models.UserPreferences
+	UpdatePreferencesRequest = models.UpdatePreferencesRequest
+	LoyaltyStatus            = models.LoyaltyStatus
+	PromoRedemption          = models.PromoRedemption
+	RedeemPromoRequest       = models.RedeemPromoRequest
+	KYCStatus                = models.KYCStatusResponse
+)
+
+// OperationResult - результат операции с балансом (пополнение, снятие, перевод)
+type OperationResult struct {
+	Message    string   `json:"message"`         // Сообщение API
+	NewBalance *Balance `json:"new_balance"`     // Баланс после операции
+	Bonus      float64  `json:"bonus,omitempty"` // Бонус по промокоду (только пополнение с промокодом)
+}
+
+// BalanceResponse - баланс и его оформление по настройкам пользователя
+type BalanceResponse struct {
+	Balance *Balance        `json:"balance"`           // Баланс по валютам
+	Display *BalanceDisplay `json:"display,omitempty"` // Итог в валюте отображения (нет, если курсы недоступны)
+}
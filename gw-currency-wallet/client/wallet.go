@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Register регистрирует нового пользователя
+// Возвращает идентификатор созданного пользователя
+func (c *Client) Register(ctx context.Context, request CreateUserRequest) (int, error) {
+	var response struct {
+		UserID int `json:"user_id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/register", request, &response, false); err != nil {
+		return 0, err
+	}
+	return response.UserID, nil
+}
+
+// Balance возвращает баланс пользователя и его оформление по настройкам
+func (c *Client) Balance(ctx context.Context) (*BalanceResponse, error) {
+	var response BalanceResponse
+	if err := c.do(ctx, http.MethodGet, "/balance", nil, &response, true); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// BalanceTotal возвращает стоимость всех балансов в валюте currency
+// Пустая валюта - валюта отображения из настроек пользователя
+func (c *Client) BalanceTotal(ctx context.Context, currency string) (*BalanceTotal, error) {
+	path := "/balance/total"
+	if currency != "" {
+		path += "?in=" + url.QueryEscape(currency)
+	}
+
+	var total BalanceTotal
+	if err := c.do(ctx, http.MethodGet, path, nil, &total, true); err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
+
+// Deposit пополняет баланс (с промокодом в поле PromoCode - с бонусом)
+func (c *Client) Deposit(ctx context.Context, request DepositRequest) (*OperationResult, error) {
+	return c.operation(ctx, "/wallet/deposit", request)
+}
+
+// Withdraw снимает средства с баланса
+// Если антифрод отправил операцию на проверку, возвращается *PendingError
+func (c *Client) Withdraw(ctx context.Context, currency string, amount float64) (*OperationResult, error) {
+	return c.operation(ctx, "/wallet/withdraw", WithdrawRequest{Currency: currency, Amount: amount})
+}
+
+// Transfer переводит средства другому пользователю
+// Если антифрод отправил операцию на проверку, возвращается *PendingError
+func (c *Client) Transfer(ctx context.Context, toUsername, currency string, amount float64) (*OperationResult, error) {
+	return c.operation(ctx, "/wallet/transfer", TransferRequest{ToUsername: toUsername, Currency: currency, Amount: amount})
+}
+
+// operation выполняет операцию с балансом
+func (c *Client) operation(ctx context.Context, path string, request interface{}) (*OperationResult, error) {
+	var result OperationResult
+	if err := c.do(ctx, http.MethodPost, path, request, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Rates возвращает текущие курсы валют
+func (c *Client) Rates(ctx context.Context) (map[string]float64, error) {
+	var response struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/exchange/rates", nil, &response, true); err != nil {
+		return nil, err
+	}
+	return response.Rates, nil
+}
+
+// Exchange обменивает amount валюты from на валюту to по текущему курсу
+func (c *Client) Exchange(ctx context.Context, from, to string, amount float64) (*ExchangeResponse, error) {
+	request := ExchangeRequest{FromCurrency: from, ToCurrency: to, Amount: amount}
+
+	var response ExchangeResponse
+	if err := c.do(ctx, http.MethodPost, "/exchange", request, &response, true); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Transactions возвращает историю операций за период
+// Нулевые границы и limit - значения по умолчанию сервиса (последние 30 дней, 50 записей)
+func (c *Client) Transactions(ctx context.Context, from, to time.Time, limit int) (*TransactionHistory, error) {
+	query := url.Values{}
+	if !from.IsZero() {
+		query.Set("from", from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.Format(time.RFC3339))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	path := "/transactions"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var history TransactionHistory
+	if err := c.do(ctx, http.MethodGet, path, nil, &history, true); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// Preferences возвращает настройки пользователя
+func (c *Client) Preferences(ctx context.Context) (*UserPreferences, error) {
+	var prefs UserPreferences
+	if err := c.do(ctx, http.MethodGet, "/preferences", nil, &prefs, true); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// UpdatePreferences изменяет заданные в запросе настройки пользователя
+func (c *Client) UpdatePreferences(ctx context.Context, request UpdatePreferencesRequest) (*UserPreferences, error) {
+	var prefs UserPreferences
+	if err := c.do(ctx, http.MethodPut, "/preferences", request, &prefs, true); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// Loyalty возвращает уровень лояльности и комиссию обмена
+func (c *Client) Loyalty(ctx context.Context) (*LoyaltyStatus, error) {
+	var status LoyaltyStatus
+	if err := c.do(ctx, http.MethodGet, "/loyalty", nil, &status, true); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// RedeemPromo активирует фиксированный промокод
+// Возвращает активацию и новый баланс
+func (c *Client) RedeemPromo(ctx context.Context, code string) (*PromoRedemption, *Balance, error) {
+	var response struct {
+		Redemption *PromoRedemption `json:"redemption"`
+		NewBalance *Balance         `json:"new_balance"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/promo/redeem", RedeemPromoRequest{Code: code}, &response, true); err != nil {
+		return nil, nil, err
+	}
+	return response.Redemption, response.NewBalance, nil
+}
+
+// KYCStatus возвращает статус верификации и поданные документы
+func (c *Client) KYCStatus(ctx context.Context) (*KYCStatus, error) {
+	var status KYCStatus
+	if err := c.do(ctx, http.MethodGet, "/kyc", nil, &status, true); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"time"
+)
+
+// defaultBudget - бюджет производительности из budget.yaml, встроенный в утилиту
+//
+//go:embed budget.yaml
+var defaultBudget []byte
+
+// Budget - пороги производительности одного сценария
+type Budget struct {
+	P95       time.Duration `yaml:"p95"`        // Допустимый 95-й перцентиль задержки
+	P99       time.Duration `yaml:"p99"`        // Допустимый 99-й перцентиль задержки
+	ErrorRate float64       `yaml:"error_rate"` // Допустимая доля неуспешных запросов
+}
+
+// loadBudgets читает бюджеты сценариев из файла (пустой путь - встроенный budget.yaml)
+func loadBudgets(path string) (map[string]Budget, error) {
+	data := defaultBudget
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("ошибка чтения бюджета: %w", err)
+		}
+	}
+
+	budgets := make(map[string]Budget)
+	if err := yaml.Unmarshal(data, &budgets); err != nil {
+		return nil, fmt.Errorf("ошибка разбора бюджета: %w", err)
+	}
+	return budgets, nil
+}
+
+// check сравнивает результаты сценария с бюджетом
+// Возвращает список нарушений (пустой - бюджет соблюден)
+func (b Budget) check(r *Result) []string {
+	var violations []string
+	if b.P95 > 0 && r.P95 > b.P95 {
+		violations = append(violations, fmt.Sprintf("p95 %s > %s", r.P95, b.P95))
+	}
+	if b.P99 > 0 && r.P99 > b.P99 {
+		violations = append(violations, fmt.Sprintf("p99 %s > %s", r.P99, b.P99))
+	}
+	if rate := r.ErrorRate(); rate > b.ErrorRate {
+		violations = append(violations, fmt.Sprintf("ошибки %.2f%% > %.2f%%", rate*100, b.ErrorRate*100))
+	}
+	return violations
+}
@@ -0,0 +1,184 @@
+// loadtest - нагрузочный прогон горячих путей с проверкой бюджета производительности
+//
+// Сценарии:
+//   - balance  - GET /api/v1/balance
+//   - exchange - POST /api/v1/exchange (обмен 1 USD на EUR)
+//   - getrate  - gRPC GetExchangeRateForCurrency в сервисе обмена
+//
+// Перед прогоном утилита регистрирует отдельного пользователя и пополняет его баланс,
+// затем нагружает каждый сценарий по очереди с постоянной частотой и сравнивает
+// перцентили задержки и долю ошибок с бюджетом (budget.yaml).
+// Код завершения: 0 - бюджет соблюден, 1 - бюджет превышен, 2 - ошибка подготовки.
+//
+//	go run ./cmd/loadtest -rate 50 -duration 30s
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"gw-currency-wallet/client"
+	"gw-currency-wallet/internal/services"
+	pb "gw-proto/proto"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// depositAmount - пополнение баланса пользователя нагрузки в USD (хватает на обмены по 1 USD)
+const depositAmount = 9000
+
+func main() {
+	api := flag.String("api", "http://localhost:8080/api/v1", "адрес API кошелька")
+	exchanger := flag.String("exchanger", "localhost:50051", "адрес gRPC сервиса обмена")
+	token := flag.String("token", os.Getenv("EXCHANGE_API_TOKEN"), "API токен сервиса обмена (по умолчанию EXCHANGE_API_TOKEN)")
+	rate := flag.Int("rate", 50, "запросов в секунду на сценарий")
+	duration := flag.Duration("duration", 30*time.Second, "длительность прогона каждого сценария")
+	timeout := flag.Duration("timeout", 5*time.Second, "таймаут одного запроса")
+	maxInFlight := flag.Int("max-in-flight", 1000, "максимум одновременных запросов")
+	scenarios := flag.String("scenarios", "balance,exchange,getrate", "сценарии через запятую")
+	budgetFile := flag.String("budget", "", "файл бюджета (по умолчанию встроенный budget.yaml)")
+	flag.Parse()
+
+	if *rate <= 0 || *duration <= 0 {
+		log.Println("rate и duration должны быть положительными")
+		os.Exit(2)
+	}
+	budgets, err := loadBudgets(*budgetFile)
+	if err != nil {
+		log.Println(err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	targets, cleanup, err := prepareTargets(ctx, strings.Split(*scenarios, ","), *api, *exchanger, *token)
+	if err != nil {
+		log.Printf("Ошибка подготовки: %v", err)
+		os.Exit(2)
+	}
+	defer cleanup()
+
+	failed := false
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "СЦЕНАРИЙ\tЗАПРОСОВ\tRPS\tОШИБКИ\tP50\tP95\tP99\tMAX\tБЮДЖЕТ")
+	for _, target := range targets {
+		log.Printf("Сценарий %s: %d запросов/с в течение %s", target.Name, *rate, *duration)
+		result := run(ctx, target, *rate, *duration, *timeout, *maxInFlight)
+
+		verdict := "нет бюджета"
+		if budget, ok := budgets[target.Name]; ok {
+			verdict = "OK"
+			if violations := budget.check(result); len(violations) > 0 {
+				verdict = "ПРЕВЫШЕН: " + strings.Join(violations, ", ")
+				failed = true
+			}
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%.2f%%\t%s\t%s\t%s\t%s\t%s\n",
+			result.Name, result.Requests, result.Throughput(), result.ErrorRate()*100,
+			result.P50.Round(time.Microsecond), result.P95.Round(time.Microsecond),
+			result.P99.Round(time.Microsecond), result.Max.Round(time.Microsecond), verdict)
+		if result.LastError != nil {
+			log.Printf("Сценарий %s, последняя ошибка: %v", target.Name, result.LastError)
+		}
+	}
+	w.Flush()
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// prepareTargets создает сценарии: пользователя нагрузки для API и gRPC-клиент сервиса обмена
+// Возвращает функцию освобождения ресурсов
+func prepareTargets(ctx context.Context, names []string, api, exchanger, token string) ([]Target, func(), error) {
+	var targets []Target
+	var wallet *client.Client
+	var conn *grpc.ClientConn
+	cleanup := func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}
+
+	for _, name := range names {
+		switch name = strings.TrimSpace(name); name {
+		case "balance", "exchange":
+			if wallet == nil {
+				var err error
+				if wallet, err = newLoadUser(ctx, api); err != nil {
+					return nil, cleanup, err
+				}
+			}
+			if name == "balance" {
+				targets = append(targets, Target{Name: name, Hit: func(ctx context.Context) error {
+					_, err := wallet.Balance(ctx)
+					return err
+				}})
+			} else {
+				targets = append(targets, Target{Name: name, Hit: func(ctx context.Context) error {
+					_, err := wallet.Exchange(ctx, "USD", "EUR", 1)
+					return err
+				}})
+			}
+		case "getrate":
+			if conn == nil {
+				var err error
+				conn, err = grpc.NewClient(exchanger,
+					grpc.WithTransportCredentials(insecure.NewCredentials()),
+					services.WithAPIToken(token),
+				)
+				if err != nil {
+					return nil, cleanup, fmt.Errorf("ошибка создания gRPC клиента: %w", err)
+				}
+			}
+			rates := pb.NewExchangeServiceClient(conn)
+			targets = append(targets, Target{Name: name, Hit: func(ctx context.Context) error {
+				_, err := rates.GetExchangeRateForCurrency(ctx, &pb.CurrencyRequest{FromCurrency: "USD", ToCurrency: "EUR"})
+				return err
+			}})
+		default:
+			return nil, cleanup, fmt.Errorf("неизвестный сценарий %q", name)
+		}
+	}
+	return targets, cleanup, nil
+}
+
+// newLoadUser регистрирует пользователя нагрузки и пополняет его баланс
+// Повторы запросов отключены: каждая ошибка должна попасть в статистику
+func newLoadUser(ctx context.Context, api string) (*client.Client, error) {
+	username := fmt.Sprintf("loadtest_%d", time.Now().UnixNano())
+	password := "loadtest-password"
+
+	wallet, err := client.New(client.Config{
+		BaseURL:    api,
+		Username:   username,
+		Password:   password,
+		MaxRetries: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := wallet.Register(ctx, client.CreateUserRequest{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: password,
+	}); err != nil {
+		return nil, fmt.Errorf("ошибка регистрации пользователя нагрузки: %w", err)
+	}
+	// Кошелек создается при первом запросе баланса
+	if _, err := wallet.Balance(ctx); err != nil {
+		return nil, fmt.Errorf("ошибка создания кошелька пользователя нагрузки: %w", err)
+	}
+	if _, err := wallet.Deposit(ctx, client.DepositRequest{Currency: "USD", Amount: depositAmount}); err != nil {
+		return nil, fmt.Errorf("ошибка пополнения баланса пользователя нагрузки: %w", err)
+	}
+	return wallet, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Target - сценарий нагрузки: один вызов проверяемого пути
+type Target struct {
+	Name string                          // Имя сценария (ключ в бюджете)
+	Hit  func(ctx context.Context) error // Один запрос
+}
+
+// Result - итог прогона сценария
+type Result struct {
+	Name      string        // Имя сценария
+	Requests  int           // Отправлено запросов
+	Errors    int           // Неуспешных запросов
+	Duration  time.Duration // Фактическая длительность прогона
+	P50       time.Duration // Медиана задержки
+	P95       time.Duration // 95-й перцентиль задержки
+	P99       time.Duration // 99-й перцентиль задержки
+	Max       time.Duration // Максимальная задержка
+	LastError error         // Последняя ошибка (для диагностики)
+}
+
+// ErrorRate возвращает долю неуспешных запросов
+func (r *Result) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// Throughput возвращает фактическое количество запросов в секунду
+func (r *Result) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Duration.Seconds()
+}
+
+// run нагружает сценарий с постоянной частотой (открытая модель, как у vegeta):
+// запросы отправляются по расписанию независимо от времени ответа, поэтому
+// медленный сервис не снижает нагрузку, а накапливает задержку
+// Параметры:
+//   - target: сценарий
+//   - rate: запросов в секунду
+//   - duration: длительность прогона
+//   - timeout: таймаут одного запроса
+//   - maxInFlight: максимум одновременных запросов (защита самой утилиты)
+func run(ctx context.Context, target Target, rate int, duration, timeout time.Duration, maxInFlight int) *Result {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		latencies []time.Duration
+		result    = &Result{Name: target.Name}
+	)
+	slots := make(chan struct{}, maxInFlight)
+
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+	deadline := time.After(duration)
+	started := time.Now()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			// Все слоты заняты: запрос не отправлен и считается ошибкой
+			mu.Lock()
+			result.Requests++
+			result.Errors++
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			reqCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			begin := time.Now()
+			err := target.Hit(reqCtx)
+			latency := time.Since(begin)
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Requests++
+			latencies = append(latencies, latency)
+			if err != nil {
+				result.Errors++
+				result.LastError = err
+			}
+		}()
+	}
+	wg.Wait()
+	result.Duration = time.Since(started)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.P50 = percentile(latencies, 0.50)
+	result.P95 = percentile(latencies, 0.95)
+	result.P99 = percentile(latencies, 0.99)
+	if len(latencies) > 0 {
+		result.Max = latencies[len(latencies)-1]
+	}
+	return result
+}
+
+// percentile возвращает перцентиль p отсортированных задержек
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted))*p+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
@@ -2,11 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
+	"google.golang.org/grpc"
 	_ "gw-currency-wallet/docs" // Импорт сгенерированной документации Swagger (важно оставить подчеркивание для side-effect импорта)
+	"gw-currency-wallet/internal/captcha"
+	"gw-currency-wallet/internal/chaos"
 	"gw-currency-wallet/internal/config"
+	"gw-currency-wallet/internal/drain"
+	"gw-currency-wallet/internal/geoip"
+	"gw-currency-wallet/internal/jobs"
+	"gw-currency-wallet/internal/metrics"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/notify"
+	"gw-currency-wallet/internal/risk"
+	"gw-currency-wallet/internal/server"
 	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/storage/files"
+	"gw-currency-wallet/internal/storage/memory"
 	"gw-currency-wallet/internal/storage/postgres"
+	"gw-currency-wallet/internal/storage/redis"
 	"gw-currency-wallet/internal/telegram"
+	"gw-currency-wallet/internal/telemetry"
 	"gw-currency-wallet/routes"
 	"log"
 	"net/http"
@@ -14,13 +32,26 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Встроенная база часовых поясов: в образе alpine ее нет, а она нужна для настроек пользователей
 )
 
 // Аннотации Swagger для генерации документации API
+// Документация пересобирается из аннотаций командой go generate ./docs (make docs)
 
 // @title Валютный Кошелек
+// @version 1.0
 // @description API для управления пользовательскими кошельками и обмена валют
-// @tagsOrder Auth, Wallet, Exchange
+// @tagsOrder Auth, Wallet, Exchange, KYC, Admin
+// @tag.name Auth
+// @tag.description Операции аутентификации
+// @tag.name Wallet
+// @tag.description Управление кошельком
+// @tag.name Exchange
+// @tag.description Операции обмена валют
+// @tag.name KYC
+// @tag.description Верификация пользователей
+// @tag.name Admin
+// @tag.description Администрирование (требуется роль admin)
 // @host localhost:8080
 // @BasePath /api/v1
 // @securityDefinitions.apikey BearerAuth
@@ -30,15 +61,82 @@ import (
 // @tokenUrl /login
 func main() {
 	// 1. Загрузка конфигурации приложения
-	// Используется файл config2.env для загрузки параметров (JWT секрет, настройки БД и т.д.)
-	cfg, err := config.LoadConfig("config2.env")
+	// По умолчанию используется файл config2.env (JWT секрет, настройки БД и т.д.),
+	// значения из него перекрываются переменными окружения и флагами командной строки
+	cfg, err := config.LoadConfig("config2.env", os.Args[1:])
 	if err != nil {
 		log.Fatalf("Ошибка загрузки конфигурации: %v", err) // Критическая ошибка - выход приложения
 	}
 
+	// Корневой контекст приложения: отменяется при завершении работы
+	// и прерывает фоновые задачи и незавершенные запросы к БД
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Трассировки и SLO-метрики OpenTelemetry (если задан адрес OpenTelemetry Collector)
+	shutdownTelemetry, err := telemetry.Setup(ctx, telemetry.Options{
+		Endpoint:        cfg.OTelEndpoint,
+		ServiceName:     cfg.OTelServiceName,
+		SampleRatio:     cfg.OTelSampleRatio,
+		MetricsInterval: cfg.OTelMetricsInterval,
+	})
+	if err != nil {
+		log.Fatalf("Ошибка настройки OpenTelemetry: %v", err) // Критическая ошибка
+	}
+
+	// Режим chaos: задержки и ошибки в обращениях к PostgreSQL, кэшу и сервису обмена
+	// Включается только явно (CHAOS_ENABLED) для проверки устойчивости в тестовых окружениях
+	faults := newChaosInjectors(cfg)
+
+	// Кэш курсов валют, счетчиков и балансов: Redis или in-memory, если REDIS_ADDR не задан
+	// Недоступный Redis не мешает запуску: сервис работает без кэша до восстановления подключения
+	backend, err := newCache(cfg)
+	if err != nil {
+		log.Fatalf("Ошибка создания кэша: %v", err) // Критическая ошибка
+	}
+	cache := backend.cache
+	defer cache.Close()
+	counters := backend.counters // Счетчики попыток входа и квот запросов
+	if counters != backend.cache {
+		defer counters.Close()
+	}
+	if backend.redis != nil {
+		go backend.redis.Watch(ctx, cfg.RedisHealthInterval) // Обнаружение сбоя и восстановления Redis
+	}
+	if faults.cache != nil {
+		log.Printf("ВНИМАНИЕ: режим chaos для кэша (%s)", faults.cache)
+		cache = chaos.Cache(cache, faults.cache)
+		counters = chaos.Cache(counters, faults.cache)
+	}
+
+	// Сброс групп записей кэша администратором; с Redis сброс рассылается всем экземплярам
+	cacheService := services.NewCacheService(cache, backend.broadcast)
+	go cacheService.Listen(ctx)
+
+	// Кэш балансов кошельков (BALANCE_CACHE_ENABLED): только общий кэш Redis, проверяется при загрузке конфигурации
+	var balanceCache storage.Cache
+	if cfg.BalanceCacheEnabled {
+		balanceCache = cache
+	}
+
 	// 2. Инициализация подключения к базе данных PostgreSQL
-	// Используется строка подключения из конфигурации
-	db, err := postgres.NewPostgresStorage(cfg.GetDBConnString())
+	// Используется строка подключения, таймауты запросов и параметры пула из конфигурации
+	txIsolation, _ := cfg.TxIsolation() // Проверен при загрузке конфигурации
+	db, err := postgres.NewPostgresStorage(ctx, cfg.GetDBConnString(), postgres.Options{
+		QueryTimeout:         cfg.DBQueryTimeout,
+		MigrationTimeout:     cfg.DBMigrationTimeout,
+		MaxOpenConns:         cfg.DBMaxOpenConns,
+		MaxIdleConns:         cfg.DBMaxIdleConns,
+		ConnMaxLifetime:      cfg.DBConnMaxLifetime,
+		AMLThresholds:        cfg.AMLThresholds,
+		Chaos:                faults.db,
+		BalanceCache:         balanceCache,
+		BalanceCacheTTL:      cfg.BalanceCacheTTL,
+		TxIsolation:          txIsolation,
+		TxMaxAttempts:        cfg.DBTxMaxAttempts,
+		NotificationChannels: cfg.NotifyChannels,
+		NotificationDefaults: cfg.NotifyDefaults(),
+	})
 	if err != nil {
 		log.Fatalf("Ошибка подключения к базе данных: %v", err) // Критическая ошибка - выход
 	}
@@ -48,71 +146,610 @@ func main() {
 
 	// Сервис аутентификации (JWT)
 	// Использует репозиторий пользователей и параметры из конфига
-	authService := services.NewAuthService(db.GetUserRepository(), cfg.JWTSecret, cfg.TokenExpiration)
+	authService := services.NewAuthService(
+		db.GetUserRepository(),
+		cfg.JWTSecret,
+		cfg.TokenExpiration,
+		cfg.AdminUsernames, // Пользователи, получающие роль администратора
+	)
+
+	// Сессии: токены обновления со скользящим и абсолютным сроком жизни
+	sessionService := services.NewSessionService(db.GetRefreshTokenRepository(), db.GetUserRepository(), authService, services.SessionOptions{
+		IdleTTL:     cfg.RefreshTokenIdleTTL,
+		MaxLifetime: cfg.RefreshSessionMaxLifetime,
+	})
 
 	// Сервис обмена валют
-	// Подключается к внешнему сервису обмена и использует Redis для кэширования
+	// Подключается к внешнему сервису обмена и использует кэш для курсов
+	exchangeClient := exchangeClientConfig(cfg)
+	exchangeOpts := services.ClientOptions(exchangeClient)
+	if faults.exchange != nil {
+		log.Printf("ВНИМАНИЕ: режим chaos для сервиса обмена (%s)", faults.exchange)
+		exchangeOpts = append(exchangeOpts, grpc.WithChainUnaryInterceptor(chaos.UnaryClientInterceptor(faults.exchange)))
+	}
 	exchangeService, err := services.NewExchangeService(
 		cfg.ExchangeServiceAddr, // Адрес сервиса обмена валют
-		cfg.RedisAddr,           // Адрес Redis из конфига
+		cfg.ExchangeAPIToken,    // API токен кошелька для сервиса обмена
+		cache,                   // Кэш курсов
 		cfg.CacheTTL,            // Время жизни кэша
+		exchangeOpts...,         // Keepalive, балансировка, внедрение сбоев (режим chaos)
 	)
 	if err != nil {
 		log.Fatalf("Ошибка создания сервиса обмена валют: %v", err) // Критическая ошибка
 	}
 	defer exchangeService.Close() // Закрытие соединений при завершении
+	if cfg.ExchangeRateWatch {
+		go exchangeService.WatchRateUpdates(ctx) // Сброс кэша курсов сразу после их изменения
+	}
+	// Недоступный сервис обмена не мешает запуску: кошелек работает в деградированном режиме (degraded в /readyz)
+	if err := exchangeService.CheckHealth(ctx, cfg.ExchangeHealthTimeout); err != nil {
+		log.Printf("Сервис обмена %s недоступен при запуске: %v", cfg.ExchangeServiceAddr, err)
+	}
+	if cfg.ExchangeHealthInterval > 0 {
+		go exchangeService.MonitorHealth(ctx, cfg.ExchangeHealthInterval, cfg.ExchangeHealthTimeout)
+	}
+
+	// Арендаторы (бренды) с хостами, валютами и комиссией обмена
+	// Файл арендаторов и уровни комиссии уже проверены при валидации конфигурации
+	tenants, err := cfg.Tenants()
+	if err != nil {
+		log.Fatalf("Ошибка загрузки арендаторов: %v", err) // Критическая ошибка
+	}
+	tradingHours, err := cfg.TradingHours()
+	if err != nil {
+		log.Fatalf("Ошибка загрузки окон исполнения обменов: %v", err) // Критическая ошибка
+	}
+
+	// Сервис уровней лояльности: скидка на комиссию обмена по объему за 30 дней
+	loyaltyService := services.NewLoyaltyService(
+		db.GetTransactionRepository(),
+		exchangeService,
+		tenants, // Комиссия и уровни каждого арендатора
+	)
+
+	// Многошаговые денежные операции (обмен) с компенсацией выполненных шагов при ошибке
+	operationService := services.NewOperationService(db.GetOperationRepository(), services.OperationOptions{
+		StaleAfter:  cfg.OperationStaleAfter,
+		MaxAttempts: cfg.OperationMaxAttempts,
+		BatchSize:   100,
+	})
+
+	// Приостановки операций в валютах: хранятся в БД, экземпляр перечитывает их раз в CURRENCY_FREEZE_REFRESH
+	freezeService := services.NewCurrencyFreezeService(db.GetCurrencyFreezeRepository(), cfg.CurrencyFreezeRefresh)
 
 	// Сервис работы с кошельками
-	// Использует репозиторий кошельков и сервис обмена валют
-	walletService := services.NewWalletService(db.GetWalletRepository(), exchangeService)
+	// Использует репозиторий кошельков, сервис обмена валют и антифрод для снятий и переводов
+	walletService := services.NewWalletService(
+		db.GetWalletRepository(),
+		db.GetUserRepository(),
+		exchangeService,
+		newRiskEvaluator(cfg, cache, db.GetTransactionRepository(), db.GetUserRepository()),
+		db.GetReviewRepository(),
+		loyaltyService,   // Комиссия обмена с учетом уровня лояльности
+		tenants,          // Валюты, доступные арендатору пользователя
+		operationService, // Сторнирование обмена при ошибке последующих шагов
+		freezeService,    // Приостановки операций в валютах администраторами
+		tradingHours,     // Окна исполнения обменов по парам
+	)
 
-	// 4. Настройка маршрутизатора HTTP
-	// Передаем все сервисы и JWT секрет для middleware аутентификации
-	router := routes.SetupRouter(authService, walletService, exchangeService, cfg.JWTSecret)
+	// Сервис верификации пользователей (KYC)
+	// Файлы документов хранятся на локальном диске, метаданные - в БД
+	documentStore, err := files.NewLocalStore(cfg.KYCDocumentsDir)
+	if err != nil {
+		log.Fatalf("Ошибка инициализации хранилища документов: %v", err) // Критическая ошибка
+	}
+	kycService := services.NewKYCService(
+		db.GetKYCRepository(),
+		db.GetUserRepository(),
+		documentStore,
+		int64(cfg.KYCMaxDocumentSize),
+	)
+
+	// Сервис отчетности о крупных операциях (записи создаются хранилищем кошельков)
+	complianceService := services.NewComplianceService(db.GetComplianceRepository())
+
+	// Сервис корректировок баланса администраторами
+	adjustmentService := services.NewAdjustmentService(
+		db.GetAdjustmentRepository(),
+		db.GetUserRepository(),
+		cfg.AdjustmentApprovalRequired, // Подтверждение вторым администратором
+	)
+
+	// Сервис промокодов (бонусы зачисляются через журнал операций)
+	promoService := services.NewPromoService(db.GetPromoRepository())
+
+	// Сервис настроек пользователя: валюта отображения, локаль и часовой пояс
+	activityService := services.NewActivityService(db.GetActivityRepository())
+	preferencesService := services.NewPreferencesService(db.GetPreferencesRepository(), exchangeService, activityService)
+
+	// Сервис истории операций и обслуживания секционированного журнала
+	historyService := services.NewHistoryService(
+		db.GetTransactionRepository(),
+		cfg.LedgerRetentionMonths,
+		cfg.LedgerPartitionsAhead,
+	)
 
-	// 5. Запуск Telegram бота (если указан токен в конфиге)
+	// Сервис налогового отчета: стоимость приобретения валюты по истории операций и снимкам курсов
+	taxService := services.NewTaxService(db.GetTransactionRepository(), exchangeService, cfg.TaxReportCurrency, cfg.TaxAccountingMethod)
+
+	// Сервис валютных позиций: средний курс приобретения по истории операций и доход по текущим курсам
+	portfolioService := services.NewPortfolioService(db.GetTransactionRepository(), db.GetWalletRepository(), exchangeService, exchangeService)
+
+	// Привязка аккаунтов Telegram к пользователям: обмен валют из бота по котировке с подтверждением
+	telegramLinkService := services.NewTelegramLinkService(db.GetTelegramLinkRepository(), cache, cfg.TelegramLinkCodeTTL)
+
+	// Telegram бот (если указан токен в конфиге): курсы, графики, уведомления о курсах и сводки в группах
+	alertService := services.NewAlertService(db.GetAlertRepository(), exchangeService, cfg.AlertLimit)
+	var bot *telegram.Bot
 	if cfg.TelegramToken != "" {
-		bot, err := telegram.New(telegram.Config{
+		bot, err = telegram.New(telegram.Config{
 			Token:               cfg.TelegramToken,
 			ExchangeServiceAddr: cfg.ExchangeServiceAddr,
+			ExchangeAPIToken:    cfg.ExchangeBotAPIToken,
+			ExchangeClient:      exchangeClient,
 			UpdateTimeout:       60 * time.Second,
+			Debug:               cfg.TelegramDebug,
+			Charts:              exchangeService,
+			ChartCache:          cache,
+			ChartCacheTTL:       cfg.TelegramChartTTL,
+			Alerts:              alertService,
+			Favorites:           services.NewFavoritesService(db.GetFavoritesRepository()),
+			Chats:               services.NewChatService(db.GetChatRepository()),
+			CommandLimit:        cfg.TelegramCommandLimit,
+			CommandWindow:       cfg.TelegramCommandWindow,
+			AdminChatID:         cfg.TelegramAdminChatID,
+			Links:               telegramLinkService,
+			Quotes:              services.NewQuoteService(walletService, cache, cfg.ExchangeQuoteTTL),
 		})
 		if err != nil {
 			log.Printf("Ошибка создания Telegram бота: %v", err) // Не критическая ошибка
-		} else {
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
-
-			// Запуск бота в отдельной горутине
-			go func() {
-				if err := bot.Start(ctx); err != nil {
-					log.Printf("Ошибка в работе Telegram бота: %v", err)
-				}
-			}()
 		}
 	}
 
+	// Оповещения дежурных в служебный чат бота (TELEGRAM_ADMIN_CHAT_ID)
+	var opsAlert services.OpsAlertFunc
+	if bot != nil && cfg.TelegramAdminChatID != 0 {
+		opsAlert = bot.NotifyAdmin
+	}
+
+	// Сервис сверки балансов с журналом операций
+	reconciliationService := services.NewReconciliationService(
+		db.GetTransactionRepository(),
+		db.GetWalletRepository(),
+		cfg.ReconciliationQuarantine, // Блокировать кошельки с расхождениями
+		opsAlert,                     // Оповещение дежурных о расхождениях
+	)
+
+	// Уведомления пользователям по email (без SMTP_ADDR письма пишутся в журнал)
+	var notifier notify.Notifier = notify.Log{}
+	if cfg.SMTPAddr != "" {
+		notifier = notify.NewSMTP(notify.SMTPOptions{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.SMTPFrom,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		})
+	}
+
+	// Push уведомления мобильного приложения: FCM и APNs (платформы настраиваются ключами PUSH_*)
+	var pushProviders []notify.PushProvider
+	if cfg.PushFCMCredentialsFile != "" {
+		fcm, err := notify.NewFCM(cfg.PushFCMCredentialsFile, cfg.PushTimeout)
+		if err != nil {
+			log.Fatalf("Ошибка настройки FCM: %v", err) // Критическая ошибка
+		}
+		pushProviders = append(pushProviders, fcm)
+	}
+	if cfg.PushAPNsKeyFile != "" {
+		apns, err := notify.NewAPNs(notify.APNsOptions{
+			KeyFile: cfg.PushAPNsKeyFile,
+			KeyID:   cfg.PushAPNsKeyID,
+			TeamID:  cfg.PushAPNsTeamID,
+			Topic:   cfg.PushAPNsTopic,
+			Sandbox: cfg.PushAPNsSandbox,
+			Timeout: cfg.PushTimeout,
+		})
+		if err != nil {
+			log.Fatalf("Ошибка настройки APNs: %v", err) // Критическая ошибка
+		}
+		pushProviders = append(pushProviders, apns)
+	}
+	var pushChannel *notify.PushChannel
+	if len(pushProviders) > 0 {
+		pushChannel = notify.NewPushChannel(models.NotificationChannelPush, db.GetPushTokenRepository(), pushProviders...)
+	}
+	pushService := services.NewPushService(db.GetPushTokenRepository(), db.GetTelegramLinkRepository(), pushChannel)
+
+	// Очередь обменов до открытия окна исполнения или публикации курса; о результате исполнения
+	// пользователь уведомляется по email и push
+	exchangeQueueService := services.NewExchangeQueueService(
+		db.GetQueuedExchangeRepository(),
+		walletService,
+		pushService.QueuedExchangeNotifier(services.EmailQueuedExchangeNotifier(db.GetUserRepository(), notifier)),
+	)
+	if cfg.ExchangeRateWatch {
+		exchangeQueueService.WatchRates(exchangeService) // Обмены по следующему курсу исполняются по событиям изменения курсов
+	}
+
+	// Планы регулярных обменов; о пропущенных и невыполненных обменах пользователь уведомляется по email и push
+	dcaService := services.NewDCAService(
+		db.GetDCARepository(),
+		walletService,
+		preferencesService, // Часовой пояс плана по умолчанию
+		pushService.DCANotifier(services.EmailDCANotifier(db.GetUserRepository(), notifier)),
+	)
+
+	// Каналы доставки уведомлений об операциях из outbox (подключаются по NOTIFY_CHANNELS)
+	notificationChannels := notify.NewRegistry()
+	for _, channel := range cfg.NotifyChannels {
+		switch channel {
+		case models.NotificationChannelEmail:
+			notificationChannels.Register(notify.NewEmailChannel(channel, notifier))
+		case models.NotificationChannelTelegram:
+			if bot != nil {
+				notificationChannels.Register(bot.NotificationChannel(channel))
+			}
+		case models.NotificationChannelPush:
+			if pushChannel != nil {
+				notificationChannels.Register(pushChannel)
+			}
+		case models.NotificationChannelWebhook:
+			notificationChannels.Register(notify.NewWebhookChannel(channel, notify.WebhookOptions{
+				Secret:       cfg.NotifyWebhookSecret,
+				Timeout:      cfg.NotifyWebhookTimeout,
+				AllowPrivate: cfg.NotifyWebhookAllowPrivate,
+			}))
+		}
+	}
+	notificationService := services.NewNotificationService(
+		db.GetNotificationRepository(),
+		db.GetPreferencesRepository(),
+		notificationChannels,
+		services.NotificationOptions{
+			MaxAttempts: cfg.NotifyMaxAttempts,
+			RetryDelay:  cfg.NotifyRetryDelay,
+			Retention:   cfg.NotifyRetention,
+		},
+	)
+
+	// Фоновые задачи: обслуживание секций журнала, плановая сверка балансов, компенсация прерванных операций,
+	// уведомления о курсах и сводки, отправка уведомлений об операциях, проверка получения курсов для оповещения дежурных
+	// С Redis задачи выполняются только на одном экземпляре сервиса (распределенная блокировка)
+	scheduler := jobs.NewScheduler(backend.locker)
+	scheduler.Add(jobs.Job{
+		Name:     "ledger-maintenance",
+		Interval: cfg.LedgerMaintenanceInterval,
+		Run:      historyService.MaintainLedger,
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "balance-reconciliation",
+		Interval: cfg.ReconciliationInterval,
+		Run:      reconciliationService.RunJob,
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "queued-exchanges",
+		Interval: cfg.ExchangeQueueInterval,
+		Run:      exchangeQueueService.ExecuteDue,
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "dca-plans",
+		Interval: cfg.DCAInterval,
+		Run:      dcaService.RunDue,
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "operation-recovery",
+		Interval: cfg.OperationRecoveryInterval,
+		Run: func(ctx context.Context) error {
+			return operationService.Recover(ctx, opsAlert)
+		},
+	})
+	if len(cfg.NotifyChannels) > 0 {
+		scheduler.Add(jobs.Job{
+			Name:     "notification-dispatch",
+			Interval: cfg.NotifyDispatchInterval,
+			Run:      notificationService.Dispatch,
+		})
+	}
+	if bot != nil {
+		scheduler.Add(jobs.Job{
+			Name:     "rate-alerts",
+			Interval: cfg.AlertCheckInterval,
+			Run: func(ctx context.Context) error {
+				return alertService.Check(ctx, pushService.AlertNotifier(bot.NotifyAlert))
+			},
+		})
+		scheduler.Add(jobs.Job{
+			Name:     "telegram-digest",
+			Interval: cfg.TelegramDigestInterval,
+			Run:      bot.SendDigests,
+		})
+	}
+	if opsAlert != nil {
+		scheduler.Add(jobs.Job{
+			Name:     "rate-monitor",
+			Interval: cfg.OpsCheckInterval,
+			Run:      services.NewRateMonitor(exchangeService, cfg.OpsFailureThreshold, opsAlert).Check,
+		})
+	}
+	scheduler.Start(ctx)
+
+	// Вывод экземпляра из балансировки перед остановкой (POST /admin/drain или SIGUSR1):
+	// /readyz отвечает 503, затем ожидаются выполняющиеся запросы и текущие запуски задач
+	drainer := drain.New(cfg.DrainDelay, cfg.DrainTimeout, func() {
+		scheduler.Stop()
+		scheduler.Wait()
+	})
+
+	// Публикация метрик Prometheus (если задан адрес)
+	go metrics.Serve(cfg.MetricsAddr)
+
+	// 4. Настройка маршрутизатора HTTP
+	// Передаем все сервисы, JWT секрет для middleware аутентификации
+	// и дату отключения устаревшей версии API (проверена при валидации конфигурации)
+	v1Sunset, _ := cfg.V1SunsetDate()
+	adminNetworks, _ := cfg.AdminNetworks()
+	var requestLog *middleware.RequestLogOptions
+	if cfg.HTTPLogEnabled {
+		requestLog = &middleware.RequestLogOptions{
+			SamplePercent: cfg.HTTPLogSamplePercent,
+			LogErrors:     cfg.HTTPLogErrors,
+			MaxBodyBytes:  cfg.HTTPLogMaxBody,
+			LogHeaders:    cfg.HTTPLogHeaders,
+		}
+	}
+	// Проверка CAPTCHA при регистрации и входе (если задан провайдер; настройки проверены при валидации)
+	var captchaVerifier *captcha.Verifier
+	if cfg.CaptchaProvider != "" {
+		captchaVerifier, err = captcha.New(cfg.CaptchaProvider, cfg.CaptchaSecret, cfg.CaptchaVerifyURL)
+		if err != nil {
+			log.Fatalf("Ошибка настройки CAPTCHA: %v", err) // Критическая ошибка
+		}
+	}
+
+	// Геолокация для уведомлений о входе с новых устройств
+	locator, err := geoip.Open(cfg.GeoIPDBPath)
+	if err != nil {
+		log.Fatalf("Ошибка настройки GeoIP: %v", err) // Критическая ошибка
+	}
+	defer locator.Close()
+
+	router := routes.SetupRouter(routes.Services{
+		Auth:           authService,
+		Sessions:       sessionService,
+		Wallet:         walletService,
+		Exchange:       exchangeService,
+		History:        historyService,
+		Reconciliation: reconciliationService,
+		KYC:            kycService,
+		Compliance:     complianceService,
+		Adjustment:     adjustmentService,
+		Promo:          promoService,
+		Loyalty:        loyaltyService,
+		Preferences:    preferencesService,
+		BruteForce: services.NewBruteForceService(counters, backend.bans, services.BruteForceOptions{
+			Limit:       cfg.BruteForceLimit,
+			Window:      cfg.BruteForceWindow,
+			BanDuration: cfg.BruteForceBanDuration,
+		}),
+		Captcha: services.NewCaptchaService(captchaVerifier, cache, services.CaptchaOptions{
+			LoginAfterFailures: cfg.CaptchaLoginAfterFailures,
+			FailureWindow:      cfg.CaptchaFailureWindow,
+		}),
+		Device: services.NewDeviceService(db.GetDeviceRepository(), db.GetUserRepository(), cache, locator, notifier, activityService, services.DeviceOptions{
+			RequireConfirmation: cfg.DeviceConfirmation,
+			CodeTTL:             cfg.DeviceCodeTTL,
+			MaxCodeAttempts:     cfg.DeviceMaxCodeAttempts,
+		}),
+		Activity:      activityService,
+		Users:         services.NewUserService(db.GetUserRepository()),
+		Tax:           taxService,
+		Portfolio:     portfolioService,
+		TelegramLink:  telegramLinkService,
+		Notifications: notificationService,
+		Push:          pushService,
+		Cache:         cacheService,
+		Freezes:       freezeService,
+		ExchangeQueue: exchangeQueueService,
+		DCA:           dcaService,
+		Quota: services.NewQuotaService(counters, db.GetUserRepository(), services.QuotaOptions{
+			Limits: cfg.PlanQuotas(),
+			Window: cfg.QuotaWindow,
+		}),
+		Tenants: tenants,
+		Drain:   drainer,
+		Config:  cfg.Effective(),
+		PaymentWebhook: services.NewPaymentWebhookService(db.GetPaymentRepository(), cache, services.PaymentWebhookOptions{
+			Secret:    cfg.PaymentWebhookSecret,
+			Tolerance: cfg.PaymentWebhookTolerance,
+		}),
+		Receipts: services.NewReceiptService(db.GetTransactionRepository(), services.ReceiptOptions{
+			Secret:  cfg.ReceiptSecret,
+			BaseURL: cfg.ReceiptBaseURL,
+		}),
+	}, middleware.JWTOptions{Secret: cfg.JWTSecret, Leeway: cfg.JWTLeeway}, v1Sunset, requestLog, cfg.HTTPTimeout, cfg.HTTPRouteTimeouts, cfg.TrustedProxies, adminNetworks)
+
+	// 5. Запуск Telegram бота (создан вместе с фоновыми задачами)
+	if bot != nil {
+		// Запуск бота в отдельной горутине
+		go func() {
+			if err := bot.Start(ctx); err != nil {
+				log.Printf("Ошибка в работе Telegram бота: %v", err)
+			}
+		}()
+	}
+
 	// 6. Настройка graceful shutdown
 	// Канал для получения сигналов завершения работы
 	quit := make(chan os.Signal, 1)
 	// Регистрация обработчиков сигналов SIGINT (Ctrl+C) и SIGTERM (kill)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	// 7. Запуск HTTP сервера в отдельной горутине
+	// 7. Запуск HTTP(S) сервера в отдельной горутине
+	// Режим TLS (none, file, autocert) выбирается в конфигурации
+	srv, err := server.New(cfg, router)
+	if err != nil {
+		log.Fatalf("Ошибка настройки сервера: %v", err) // Критическая ошибка
+	}
 	go func() {
-		log.Printf("Запуск сервера на %s", cfg.ServerAddress)
-		if err := http.ListenAndServe(cfg.ServerAddress, router); err != nil {
+		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("Ошибка запуска сервера: %v", err) // Критическая ошибка
 		}
 	}()
 
+	// SIGUSR1 начинает вывод экземпляра из балансировки (как POST /admin/drain)
+	drainSignal := make(chan os.Signal, 1)
+	signal.Notify(drainSignal, syscall.SIGUSR1)
+	go func() {
+		for range drainSignal {
+			drainer.Start()
+		}
+	}()
+
 	// Ожидание сигнала завершения
 	<-quit
 	log.Println("Завершение работы сервера...")
 
+	// Начатый вывод из балансировки завершается до остановки сервера
+	if !drainer.Ready() {
+		<-drainer.Done()
+	}
+
 	// Создание контекста с таймаутом для graceful shutdown
-	_, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Ошибка остановки сервера: %v", err)
+	}
+	cancel()         // Останавливаем фоновые задачи
+	scheduler.Wait() // Дожидаемся завершения текущих запусков задач
+
+	// Отправка накопленных трассировок и метрик
+	if err := shutdownTelemetry(shutdownCtx); err != nil {
+		log.Printf("Ошибка остановки OpenTelemetry: %v", err)
+	}
 
 	log.Println("Сервер остановлен")
 }
+
+// exchangeClientConfig возвращает параметры соединения с сервисом обмена из конфигурации
+func exchangeClientConfig(cfg *config.Config) services.ClientConfig {
+	return services.ClientConfig{
+		KeepaliveTime:                cfg.ExchangeKeepaliveTime,
+		KeepaliveTimeout:             cfg.ExchangeKeepaliveTimeout,
+		KeepalivePermitWithoutStream: cfg.ExchangeKeepaliveWithoutStream,
+		WaitForReady:                 cfg.ExchangeWaitForReady,
+	}
+}
+
+// cacheBackend - хранилища приложения на основе Redis или памяти процесса
+type cacheBackend struct {
+	cache     storage.Cache          // Кэш курсов валют и счетчики
+	counters  storage.Cache          // Счетчики попыток входа и квот запросов (с Redis - с резервным in-memory кэшем)
+	locker    jobs.Locker            // Блокировка фоновых задач (nil без Redis)
+	bans      storage.BanList        // Блокировки IP адресов за подбор паролей
+	broadcast storage.CacheBroadcast // Рассылка сброса кэша экземплярам (nil без Redis)
+	redis     *redis.Client          // Подключение к Redis (nil - in-memory кэш)
+}
+
+// newCache создает кэш приложения, блокировку фоновых задач и список блокировок адресов
+// Если адрес Redis не задан, используются ограниченный in-memory кэш и список без блокировки задач:
+// этого достаточно для разработки и установок с одним экземпляром сервиса.
+// С Redis счетчики попыток входа и квот запросов и список блокировок адресов на время его недоступности
+// переключаются на in-memory кэш и список, чтобы ограничения не отключались
+func newCache(cfg *config.Config) (cacheBackend, error) {
+	if cfg.RedisAddr == "" {
+		log.Printf("ВНИМАНИЕ: REDIS_ADDR не задан, используется in-memory кэш (до %d записей). "+
+			"При запуске нескольких экземпляров кэш и лимиты запросов не будут согласованы между ними",
+			cfg.CacheMaxEntries)
+		cache := memory.NewCache(cfg.CacheMaxEntries)
+		return cacheBackend{cache: cache, counters: cache, bans: memory.NewBanList()}, nil
+	}
+
+	// Недоступный Redis отключает кэширование, а не останавливает сервис (переподключение - Client.Watch)
+	client := redis.Dial(redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	locker, err := redis.NewLocker(client)
+	if err != nil {
+		client.Close()
+		return cacheBackend{}, err
+	}
+	cache := redis.NewCache(client)
+	return cacheBackend{
+		cache:     cache,
+		counters:  redis.NewFallbackCache(cache, memory.NewCache(cfg.CacheMaxEntries)),
+		locker:    locker,
+		bans:      redis.NewFallbackBanList(redis.NewBanList(client), memory.NewBanList()),
+		broadcast: redis.NewCacheBroadcast(client),
+		redis:     client,
+	}, nil
+}
+
+// chaosInjectors - источники сбоев режима chaos по зависимостям (nil - сбои не внедряются)
+type chaosInjectors struct {
+	db       *chaos.Injector // Запросы к PostgreSQL
+	cache    *chaos.Injector // Обращения к кэшу (Redis или in-memory)
+	exchange *chaos.Injector // gRPC вызовы сервиса обмена
+}
+
+// newChaosInjectors создает источники сбоев из конфигурации
+// Если режим chaos выключен, все источники пустые
+func newChaosInjectors(cfg *config.Config) chaosInjectors {
+	if !cfg.ChaosEnabled {
+		return chaosInjectors{}
+	}
+
+	log.Println("ВНИМАНИЕ: включен режим chaos (CHAOS_ENABLED=true), не используйте его в production")
+	return chaosInjectors{
+		db: chaos.NewInjector("db", chaos.Fault{
+			LatencyPercent: cfg.ChaosDBLatencyPercent,
+			Latency:        cfg.ChaosLatency,
+			ErrorPercent:   cfg.ChaosDBErrorPercent,
+		}),
+		cache: chaos.NewInjector("redis", chaos.Fault{
+			LatencyPercent: cfg.ChaosRedisLatencyPercent,
+			Latency:        cfg.ChaosLatency,
+			ErrorPercent:   cfg.ChaosRedisErrorPercent,
+		}),
+		exchange: chaos.NewInjector("grpc", chaos.Fault{
+			LatencyPercent: cfg.ChaosGRPCLatencyPercent,
+			Latency:        cfg.ChaosLatency,
+			ErrorPercent:   cfg.ChaosGRPCErrorPercent,
+		}),
+	}
+}
+
+// newRiskEvaluator собирает движок антифрода из правил, включенных в конфигурации
+// Лимиты для неверифицированных пользователей применяются и при отключенном антифроде
+// Возвращает nil, если ни одно правило не включено
+func newRiskEvaluator(
+	cfg *config.Config,
+	cache storage.Cache,
+	history risk.TransferHistory,
+	users risk.UserDirectory,
+) risk.Evaluator {
+	var rules []risk.Rule
+	if len(cfg.KYCUnverifiedLimits) > 0 {
+		rules = append(rules, risk.NewKYCRule(users, cfg.KYCUnverifiedLimits))
+	}
+
+	if cfg.RiskEnabled {
+		rules = append(rules,
+			risk.NewAmountRule(cfg.RiskReviewThresholds, cfg.RiskDenyThresholds),
+			risk.NewNewRecipientRule(history, cfg.RiskNewRecipientThresholds),
+		)
+		if cfg.RiskVelocityLimit > 0 {
+			rules = append(rules, risk.NewVelocityRule(cache, cfg.RiskVelocityLimit, cfg.RiskVelocityWindow))
+		}
+	} else {
+		log.Println("ВНИМАНИЕ: антифрод отключен (RISK_ENABLED=false)")
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+	return risk.NewEngine(rules...)
+}
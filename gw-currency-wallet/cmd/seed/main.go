@@ -0,0 +1,78 @@
+// seed - генератор демонстрационных данных сервиса кошелька
+//
+// Создает пользователей со случайными балансами и историей операций (пополнения, снятия,
+// обмены, переводы) для нагрузочного тестирования и демонстрационных стендов.
+// Подключается к БД по той же конфигурации, что и сервис (config2.env, переменные окружения).
+//
+//	go run ./cmd/seed -users 100 -operations 50
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"gw-currency-wallet/internal/config"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage/postgres"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	var opts services.SeedOptions
+	var configFile string
+	flag.IntVar(&opts.Users, "users", 10, "количество пользователей")
+	flag.IntVar(&opts.OperationsPerUser, "operations", 20, "операций на пользователя")
+	flag.StringVar(&opts.Prefix, "prefix", "demo", "префикс имен пользователей")
+	flag.StringVar(&opts.Password, "password", "demo-password", "пароль всех создаваемых пользователей")
+	flag.Int64Var(&opts.RandomSeed, "seed", 0, "начальное значение генератора для воспроизводимых данных (0 - случайное)")
+	flag.StringVar(&configFile, "config", "", "файл конфигурации (по умолчанию config2.env)")
+	flag.Parse()
+
+	var args []string
+	if configFile != "" {
+		args = []string{"-config", configFile}
+	}
+	cfg, err := config.LoadConfig("config2.env", args)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+
+	// Ctrl+C прерывает генерацию; уже созданные данные остаются
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := postgres.NewPostgresStorage(ctx, cfg.GetDBConnString(), postgres.Options{
+		QueryTimeout:     cfg.DBQueryTimeout,
+		MigrationTimeout: cfg.DBMigrationTimeout,
+		MaxOpenConns:     cfg.DBMaxOpenConns,
+		MaxIdleConns:     cfg.DBMaxIdleConns,
+		ConnMaxLifetime:  cfg.DBConnMaxLifetime,
+		AMLThresholds:    cfg.AMLThresholds,
+	})
+	if err != nil {
+		log.Fatalf("Ошибка подключения к базе данных: %v", err)
+	}
+	defer db.Close()
+
+	// Обмены проводятся по демонстрационным курсам: сервис обмена для генерации не нужен
+	seeder := services.NewSeedService(db.GetUserRepository(), db.GetWalletRepository(), nil)
+
+	started := time.Now()
+	report, err := seeder.Seed(ctx, opts)
+	if report != nil {
+		fmt.Printf("Создано пользователей: %d, операций: %d, не проведено: %d, за %s\n",
+			len(report.Usernames), report.Operations, report.Failed, time.Since(started).Round(time.Millisecond))
+		if len(report.Usernames) > 0 {
+			fmt.Printf("Пользователи %s ... %s, пароль: %s\n",
+				report.Usernames[0], report.Usernames[len(report.Usernames)-1], opts.Password)
+		}
+	}
+	if err != nil {
+		log.Printf("Генерация прервана: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gw-currency-wallet/internal/config"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/storage/postgres"
+	"gw-currency-wallet/internal/storage/redis"
+	"gw-currency-wallet/internal/tenant"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// migrateCommand применяет миграции схемы БД
+func migrateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Применить миграции базы данных",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withStorage(cmd, func(context.Context, *config.Config, *postgres.PostgresStorage) error {
+				fmt.Println("Миграции применены")
+				return nil
+			})
+		},
+	}
+}
+
+// userCommand - управление пользователями
+func userCommand() *cobra.Command {
+	var request models.CreateUserRequest
+	var admin bool
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Создать пользователя",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if len(request.Username) < 3 || request.Email == "" || len(request.Password) < 8 {
+				return errors.New("нужны --username (от 3 символов), --email и --password (от 8 символов)")
+			}
+			if admin && tenantID != tenant.DefaultID {
+				return errors.New("администраторы управляют всей установкой и создаются только у арендатора default")
+			}
+			return withStorage(cmd, func(ctx context.Context, cfg *config.Config, db *postgres.PostgresStorage) error {
+				users := db.GetUserRepository()
+				auth := services.NewAuthService(users, cfg.JWTSecret, cfg.TokenExpiration, cfg.AdminUsernames)
+
+				user, err := auth.Register(ctx, request)
+				if err != nil {
+					return err
+				}
+				if admin {
+					if err := users.SetRole(ctx, user.ID, models.RoleAdmin); err != nil {
+						return fmt.Errorf("пользователь %d создан, но роль не назначена: %w", user.ID, err)
+					}
+					user.Role = models.RoleAdmin
+				}
+				fmt.Printf("Создан пользователь %s (id %d, роль %s)\n", user.Username, user.ID, user.Role)
+				return nil
+			})
+		},
+	}
+	create.Flags().StringVar(&request.Username, "username", "", "логин")
+	create.Flags().StringVar(&request.Email, "email", "", "email")
+	create.Flags().StringVar(&request.Password, "password", "", "пароль")
+	create.Flags().BoolVar(&admin, "admin", false, "назначить роль администратора")
+
+	user := &cobra.Command{Use: "user", Short: "Управление пользователями"}
+	user.AddCommand(create)
+	return user
+}
+
+// balanceCommand - корректировки баланса
+func balanceCommand() *cobra.Command {
+	var username, adminName string
+	var request models.AdjustmentRequest
+
+	adjust := &cobra.Command{
+		Use:   "adjust",
+		Short: "Скорректировать баланс пользователя (зачисление или списание с обоснованием)",
+		Long: "Корректировка проходит тот же путь, что и через API администратора: запись в журнал операций " +
+			"и журнал действий администраторов. Если включено ADJUSTMENT_APPROVAL_REQUIRED, корректировка " +
+			"создается в статусе pending и ждет подтверждения другим администратором.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if username == "" || adminName == "" {
+				return errors.New("нужны --user и --admin")
+			}
+			return withStorage(cmd, func(ctx context.Context, cfg *config.Config, db *postgres.PostgresStorage) error {
+				users := db.GetUserRepository()
+				target, err := findUser(ctx, users, tenantID, username)
+				if err != nil {
+					return err
+				}
+				admin, err := findUser(ctx, users, tenant.DefaultID, adminName) // Администраторы - у арендатора default
+				if err != nil {
+					return err
+				}
+				if admin.Role != models.RoleAdmin {
+					return fmt.Errorf("пользователь %q не администратор", adminName)
+				}
+
+				request.UserID = target.ID
+				adjustments := services.NewAdjustmentService(db.GetAdjustmentRepository(), users, cfg.AdjustmentApprovalRequired)
+				adj, balance, err := adjustments.Request(ctx, admin.ID, request)
+				if err != nil {
+					return err
+				}
+				if balance == nil {
+					fmt.Printf("Корректировка №%d ожидает подтверждения\n", adj.ID)
+					return nil
+				}
+				fmt.Printf("Корректировка №%d проведена. Баланс: USD %.2f, EUR %.2f, RUB %.2f\n",
+					adj.ID, balance.USD, balance.EUR, balance.RUB)
+				return nil
+			})
+		},
+	}
+	adjust.Flags().StringVar(&username, "user", "", "пользователь")
+	adjust.Flags().StringVar(&request.Currency, "currency", "", "валюта (USD, EUR, RUB)")
+	adjust.Flags().Float64Var(&request.Amount, "amount", 0, "сумма со знаком: положительная - зачисление, отрицательная - списание")
+	adjust.Flags().StringVar(&request.Reason, "reason", "", "обоснование")
+	adjust.Flags().StringVar(&adminName, "admin", "", "администратор, от имени которого проводится корректировка")
+
+	balance := &cobra.Command{Use: "balance", Short: "Операции с балансом"}
+	balance.AddCommand(adjust)
+	return balance
+}
+
+// ledgerCommand выводит журнал операций пользователя
+func ledgerCommand() *cobra.Command {
+	var username, from, to string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "ledger",
+		Short: "Показать журнал операций пользователя",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if username == "" {
+				return errors.New("нужен --user")
+			}
+			fromTime, err := parseDate(from)
+			if err != nil {
+				return fmt.Errorf("некорректный --from: %w", err)
+			}
+			toTime, err := parseDate(to)
+			if err != nil {
+				return fmt.Errorf("некорректный --to: %w", err)
+			}
+
+			return withStorage(cmd, func(ctx context.Context, cfg *config.Config, db *postgres.PostgresStorage) error {
+				user, err := findUser(ctx, db.GetUserRepository(), tenantID, username)
+				if err != nil {
+					return err
+				}
+
+				history := services.NewHistoryService(db.GetTransactionRepository(), cfg.LedgerRetentionMonths, cfg.LedgerPartitionsAhead)
+				result, err := history.GetHistory(ctx, user.ID, fromTime, toTime, limit)
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "ID\tВРЕМЯ\tТИП\tВАЛЮТА\tСУММА\tОПЕРАЦИЯ")
+				for _, t := range result.Transactions {
+					fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%.2f\t%s\n",
+						t.ID, t.CreatedAt.Format(time.DateTime), t.Type, t.Currency, t.Amount, t.OperationID)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+				fmt.Printf("Записей: %d (%s - %s)\n", len(result.Transactions),
+					result.From.Format(time.DateOnly), result.To.Format(time.DateOnly))
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&username, "user", "", "пользователь")
+	cmd.Flags().StringVar(&from, "from", "", "начало периода (YYYY-MM-DD, по умолчанию - 30 дней назад)")
+	cmd.Flags().StringVar(&to, "to", "", "конец периода, не включительно (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "количество записей (1-500, по умолчанию 50)")
+	return cmd
+}
+
+// ratesCommand - курсы валют
+func ratesCommand() *cobra.Command {
+	refresh := &cobra.Command{
+		Use:   "refresh",
+		Short: "Запросить курсы у сервиса обмена в обход кэша и обновить кэш",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			if cfg.RedisAddr == "" {
+				// Кэш в памяти принадлежит процессу сервиса, утилита до него не дотянется
+				return errors.New("REDIS_ADDR не задан: in-memory кэш сервиса нельзя обновить извне")
+			}
+
+			client, err := redis.New(redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB})
+			if err != nil {
+				return fmt.Errorf("ошибка подключения к Redis: %w", err)
+			}
+			cache := redis.NewCache(client)
+			defer cache.Close()
+
+			return refreshRates(cmd.Context(), cfg, cache)
+		},
+	}
+
+	rates := &cobra.Command{Use: "rates", Short: "Курсы валют"}
+	rates.AddCommand(refresh)
+	return rates
+}
+
+// refreshRates обновляет курсы в кэше и выводит их
+func refreshRates(ctx context.Context, cfg *config.Config, cache storage.Cache) error {
+	exchange, err := services.NewExchangeService(cfg.ExchangeServiceAddr, cfg.ExchangeAPIToken, cache, cfg.CacheTTL,
+		services.ClientOptions(services.ClientConfig{WaitForReady: cfg.ExchangeWaitForReady})...)
+	if err != nil {
+		return err
+	}
+	defer exchange.Close()
+
+	rates, err := exchange.RefreshRates(ctx)
+	if err != nil {
+		return err
+	}
+
+	currencies := make([]string, 0, len(rates))
+	for currency := range rates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		fmt.Printf("%s\t%.4f\n", currency, rates[currency])
+	}
+	return nil
+}
+
+// parseDate разбирает дату YYYY-MM-DD (пустая строка - нулевое время)
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
@@ -0,0 +1,117 @@
+// walletctl - утилита оператора кошелька
+//
+// Команды работают напрямую со слоем хранения и сервисами (как сам сервис кошелька)
+// и используют ту же конфигурацию: файл config2.env, переменные окружения.
+//
+//	walletctl migrate
+//	walletctl user create --username alice --email alice@example.com --password secret123
+//	walletctl --tenant acme user create --username bob --email bob@example.com --password secret123
+//	walletctl balance adjust --user alice --currency USD --amount 100 --reason "компенсация по обращению 42" --admin root
+//	walletctl ledger --user alice --from 2025-01-01 --limit 100
+//	walletctl rates refresh
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gw-currency-wallet/internal/config"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/storage/postgres"
+	"gw-currency-wallet/internal/tenant"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// configFile - путь к файлу конфигурации (флаг --config)
+var configFile string
+
+// tenantID - арендатор пользователей команды (флаг --tenant)
+var tenantID string
+
+func main() {
+	root := &cobra.Command{
+		Use:           "walletctl",
+		Short:         "Утилита оператора сервиса кошелька",
+		SilenceUsage:  true, // Справка выводится только при ошибке в аргументах
+		SilenceErrors: true, // Ошибку выводит main
+	}
+	root.PersistentFlags().StringVar(&configFile, "config", "", "файл конфигурации (по умолчанию config2.env)")
+	root.PersistentFlags().StringVar(&tenantID, "tenant", tenant.DefaultID, "арендатор пользователей (из TENANTS_FILE)")
+
+	root.AddCommand(
+		migrateCommand(),
+		userCommand(),
+		balanceCommand(),
+		ledgerCommand(),
+		ratesCommand(),
+	)
+
+	// Ctrl+C прерывает незавершенные запросы к БД и сервису курсов
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := root.ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+}
+
+// loadConfig загружает конфигурацию сервиса кошелька
+// Явно указанный файл обязан существовать, файл по умолчанию - нет
+func loadConfig() (*config.Config, error) {
+	var args []string
+	if configFile != "" {
+		args = []string{"-config", configFile}
+	}
+	return config.LoadConfig("config2.env", args)
+}
+
+// openStorage подключается к базе данных (при подключении применяются миграции)
+func openStorage(ctx context.Context, cfg *config.Config) (*postgres.PostgresStorage, error) {
+	return postgres.NewPostgresStorage(ctx, cfg.GetDBConnString(), postgres.Options{
+		QueryTimeout:     cfg.DBQueryTimeout,
+		MigrationTimeout: cfg.DBMigrationTimeout,
+		MaxOpenConns:     2, // Утилите достаточно пары соединений
+		MaxIdleConns:     2,
+		ConnMaxLifetime:  cfg.DBConnMaxLifetime,
+		AMLThresholds:    cfg.AMLThresholds,
+	})
+}
+
+// withStorage загружает конфигурацию, подключается к БД и выполняет fn
+// Арендатор --tenant передается в fn через контекст (как арендатор запроса в сервисе)
+func withStorage(cmd *cobra.Command, fn func(ctx context.Context, cfg *config.Config, db *postgres.PostgresStorage) error) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	tenants, err := cfg.Tenants()
+	if err != nil {
+		return err
+	}
+	if _, ok := tenants.Get(tenantID); !ok {
+		return fmt.Errorf("%w: %q", tenant.ErrUnknownTenant, tenantID)
+	}
+	db, err := openStorage(cmd.Context(), cfg)
+	if err != nil {
+		return fmt.Errorf("ошибка подключения к базе данных: %w", err)
+	}
+	defer db.Close()
+
+	return fn(tenant.WithID(cmd.Context(), tenantID), cfg, db)
+}
+
+// findUser находит пользователя арендатора по имени
+func findUser(ctx context.Context, users storage.UserRepository, tenantID, username string) (*models.User, error) {
+	user, err := users.GetUserByUsername(ctx, tenantID, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("пользователь %q не найден", username)
+	}
+	return user, nil
+}
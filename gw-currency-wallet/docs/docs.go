@@ -14,66 +14,130 @@ const docTemplate = `{
     },
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
-	"tags": [
-    {
-        "name": "Auth",
-        "description": "Операции аутентификации"
-    },
-    {
-        "name": "Wallet",
-        "description": "Управление кошельком"
-    },
-    {
-        "name": "Exchange",
-        "description": "Операции обмена валют"
-    }
-],
     "paths": {
-        "/balance": {
+        "/activity": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает баланс пользователя по всем валютам",
+                "description": "Возвращает входы в систему, изменения учетной записи и настроек и операции с деньгами\n(записи журнала операций, включая архив) от новых к старым. Следующая страница\nзапрашивается с курсором next_cursor предыдущей",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Wallet"
                 ],
-                "summary": "Получить баланс",
+                "summary": "Лента активности",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество событий (1-200, по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.Balance"
+                            "$ref": "#/definitions/models.ActivityFeed"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный курсор или limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/exchange": {
+        "/admin/adjustments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает корректировки баланса, от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Корректировки баланса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статус (pending, applied, rejected), по умолчанию все",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BalanceAdjustment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Обменивает указанную сумму из одной валюты в другую по текущему курсу",
+                "description": "Зачисляет (положительная сумма) или списывает (отрицательная) средства пользователя с обязательным обоснованием. Если включено подтверждение вторым администратором, корректировка ожидает подтверждения (202)",
                 "consumes": [
                     "application/json"
                 ],
@@ -81,88 +145,120 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange"
+                    "Admin"
                 ],
-                "summary": "Обмен валют",
+                "summary": "Корректировка баланса",
                 "parameters": [
                     {
-                        "description": "Данные для обмена",
+                        "description": "Корректировка",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ExchangeRequest"
+                            "$ref": "#/definitions/models.AdjustmentRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Корректировка проведена",
+                        "schema": {
+                            "$ref": "#/definitions/models.BalanceAdjustment"
+                        }
+                    },
+                    "202": {
+                        "description": "Корректировка ожидает подтверждения",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ExchangeResponse"
+                            "$ref": "#/definitions/models.BalanceAdjustment"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Некорректные данные или недостаточно средств",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/exchange/rates": {
-            "get": {
+        "/admin/adjustments/{id}/approve": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает текущие курсы обмена валют",
+                "description": "Подтверждает и проводит корректировку баланса (при обязательном подтверждении - только другой администратор)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange"
+                    "Admin"
+                ],
+                "summary": "Подтверждение корректировки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID корректировки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получить курсы валют",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ExchangeRatesResponse"
+                            "$ref": "#/definitions/models.BalanceAdjustment"
+                        }
+                    },
+                    "400": {
+                        "description": "Недостаточно средств",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Подтверждение собственной корректировки",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Корректировка не найдена или уже обработана",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/register": {
+        "/admin/adjustments/{id}/reject": {
             "post": {
-                "description": "Создает нового пользователя в системе",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отклоняет корректировку, ожидающую подтверждения",
                 "consumes": [
                     "application/json"
                 ],
@@ -170,392 +266,6145 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Регистрация нового пользователя",
+                "summary": "Отклонение корректировки",
                 "parameters": [
                     {
-                        "description": "Данные для регистрации",
+                        "type": "integer",
+                        "description": "ID корректировки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отклонения",
                         "name": "input",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.CreateUserRequest"
+                            "$ref": "#/definitions/models.RejectAdjustmentRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.SuccessMessage"
+                            "$ref": "#/definitions/models.BalanceAdjustment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Корректировка не найдена или уже обработана",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-		"/login": {
-            "post": {
-                "description": "Вход в систему с получением JWT токена",
-                "consumes": [
-                    "application/json"
+        "/admin/aml/report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Выгружает в CSV операции с суммой не меньше порога отчетности за период (по умолчанию - с начала прошлого месяца)",
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Аутентификация пользователя",
+                "summary": "Отчет о крупных операциях (AML)",
                 "parameters": [
                     {
-                        "description": "Данные для входа",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Начало периода (RFC3339 или YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV отчет",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный период",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.LoginRequest"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/admin/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает последние действия администраторов, от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Журнал действий администраторов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.LoginResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/wallet/deposit": {
-            "post": {
+        "/admin/balances": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Пополнение баланса пользователя в указанной валюте",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает балансы пользователей из списка user_ids или всех пользователей страницами\nпо возрастанию ID (следующая страница запрашивается с after_id = next_after_id)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Admin"
                 ],
-                "summary": "Пополнить баланс",
+                "summary": "Балансы пользователей",
                 "parameters": [
                     {
-                        "description": "Данные для пополнения",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.DepositRequest"
-                        }
+                        "type": "string",
+                        "description": "ID пользователей через запятую (не более 500)",
+                        "name": "user_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя, после которого продолжается список",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 100, не более 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.TransactionResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserBalance"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/wallet/withdraw": {
-            "post": {
+        "/admin/bans": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Снятие средств с баланса пользователя",
-                "consumes": [
+                "description": "Возвращает действующие блокировки адресов за подбор паролей, от новых к старым",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Заблокированные IP адреса",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.IPBan"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/bans/{ip}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Досрочно снимает блокировку адреса; счетчик попыток продолжает действовать",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Admin"
                 ],
-                "summary": "Снять средства",
+                "summary": "Снять блокировку IP адреса",
                 "parameters": [
                     {
-                        "description": "Данные для снятия",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.WithdrawRequest"
-                        }
+                        "type": "string",
+                        "description": "IP адрес",
+                        "name": "ip",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.TransactionResponse"
+                            "$ref": "#/definitions/models.SuccessMessage"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Некорректный IP адрес",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Адрес не заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "gw-currency-wallet_internal_models.Balance": {
-            "type": "object",
-            "properties": {
-                "EUR": {
-                    "description": "Пример: 85.20",
-                    "type": "number"
-                },
-                "RUB": {
-                    "description": "Пример: 12500.50",
-                    "type": "number"
-                },
-                "USD": {
-                    "description": "Пример: 150.75",
-                    "type": "number"
-                }
-            }
         },
-        "gw-currency-wallet_internal_models.CreateUserRequest": {
-            "type": "object",
-            "required": [
+        "/admin/cache/flush": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет записи кэша выбранных групп: rates - курсы, справочник валют и графики курсов,\nbalances - балансы кошельков, limits - тарифные планы и счетчики квот запросов.\nС Redis сброс рассылается всем экземплярам сервиса. Используется после ручной установки курса\nили исправления данных в БД",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Сброс кэша",
+                "parameters": [
+                    {
+                        "description": "Группы записей",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CacheFlushRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CacheFlushResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестная группа записей",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает значения всех параметров экземпляра после применения файла, окружения и флагов\nв формате переменных окружения, значения по умолчанию и отметку измененных параметров.\nСекреты (JWT_SECRET, DB_PASSWORD, токены) не раскрываются: заданное значение заменяется на [REDACTED].\nfeatures - состояние функций экземпляра (флаги конфигурации и функции, включенные заданием параметров)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Действующая конфигурация",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EffectiveConfig"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/currencies/freezes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает валюты, операции в которых приостановлены администраторами, с причиной и временем приостановки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Приостановленные валюты",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CurrencyFreeze"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/currencies/{currency}/freeze": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Приостанавливает пополнение, снятие, перевод и обмен в валюте (например, при резких колебаниях курса).\nОперации отклоняются с ответом 503 \"валюта временно недоступна\" и причиной; на других экземплярах\nприостановка вступает в силу в течение CURRENCY_FREEZE_REFRESH. Повторный запрос заменяет причину",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Приостановка операций в валюте",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Валюта (USD, RUB, EUR)",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.FreezeCurrencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CurrencyFreeze"
+                        }
+                    },
+                    "400": {
+                        "description": "Неподдерживаемая валюта или слишком длинная причина",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Снимает приостановку операций в валюте",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Возобновление операций в валюте",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Валюта",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Операции в валюте не приостановлены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drain": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает состояние экземпляра (serving, draining, drained) и количество выполняющихся запросов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Состояние вывода из балансировки",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrainStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переводит /readyz в 503, после паузы DRAIN_DELAY ожидает завершения выполняющихся запросов\n(не дольше DRAIN_TIMEOUT) и текущих запусков фоновых задач, новые запуски не начинаются.\nВыполняется в фоне; завершение - состояние drained в GET /admin/drain, после чего экземпляр можно останавливать.\nДействует на экземпляр, принявший запрос (то же делает сигнал SIGUSR1)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Вывод экземпляра из балансировки",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrainStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/kyc": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает пользователей в указанном статусе верификации, от давно ожидающих к недавним",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Заявки на верификацию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статус (none, pending, verified, rejected), по умолчанию pending",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/kyc/{user_id}/documents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает метаданные документов, поданных пользователем на верификацию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Документы пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.KYCDocument"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/kyc/{user_id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Верифицирует пользователя или отклоняет его документы (заявка должна быть в статусе pending)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Решение по верификации",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Решение",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResolveKYCRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Заявка не ожидает проверки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/notifications/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает уведомления об операциях, не доставленные за отведенные попытки или из-за недоступного получателя (dead letter), от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Недоставленные уведомления",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество уведомлений (по умолчанию 50, не больше 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Notification"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/notifications/{id}/requeue": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает недоставленное уведомление в очередь с новым счетчиком попыток",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Повторная доставка уведомления",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID уведомления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Недоставленного уведомления нет",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/promos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает промокоды с количеством активаций, от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Промокоды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PromoCode"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает промокод: fixed - фиксированная сумма при активации, percent - процент от пополнения (не больше max_bonus)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Создание промокода",
+                "parameters": [
+                    {
+                        "description": "Промокод",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePromoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Промокод уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/promos/{code}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отключает промокод; выполненные активации сохраняются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Отключение промокода",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Промокод",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Промокод не найден или уже отключен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reconciliation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает отчет последней сверки балансов (плановой или запущенной вручную)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Результат последней сверки",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReconciliationReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сверка еще не выполнялась",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пересчитывает балансы всех кошельков по журналу операций и возвращает найденные расхождения",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Запустить сверку балансов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReconciliationReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Требуется роль администратора",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Сверка уже выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает операции, отложенные антифродом, от старых к новым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Очередь проверки антифрода",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статус (pending, approved, rejected, failed), по умолчанию pending",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RiskReview"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Одобряет (операция выполняется) или отклоняет операцию из очереди проверки антифрода",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Решение по отложенной операции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проверки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Решение",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResolveReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Итоговый статус (failed, если одобренную операцию не удалось выполнить)",
+                        "schema": {
+                            "$ref": "#/definitions/models.RiskReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Проверка не найдена или уже обработана",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ищет пользователей по префиксу имени или email (без учета регистра) с фильтрами по статусу верификации,\nроли, тарифному плану, арендатору и периоду регистрации. По умолчанию новые пользователи выводятся первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Поиск пользователей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Префикс имени пользователя или email",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус верификации (none, pending, verified, rejected)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Роль (user, admin)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тарифный план (free, premium)",
+                        "name": "plan",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Арендатор",
+                        "name": "tenant",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода регистрации (RFC3339 или YYYY-MM-DD, включительно)",
+                        "name": "registered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода регистрации (RFC3339 или YYYY-MM-DD, не включительно)",
+                        "name": "registered_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поле сортировки: id, created_at, username, email; с префиксом - по убыванию (по умолчанию -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, не более 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение страницы",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{user_id}/plan": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Назначает пользователю тарифный план, определяющий квоту запросов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Назначить тарифный план",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Тарифный план",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный тарифный план",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallets/{user_id}/quarantine": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Разблокирует кошелек, заблокированный по итогам сверки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Снять блокировку кошелька",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает баланс пользователя по всем валютам и итог в валюте отображения из настроек пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Получить баланс",
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ с балансом",
+                        "schema": {
+                            "$ref": "#/definitions/models.Balance"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка аутентификации",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/balance/total": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пересчитывает балансы во всех валютах в одну валюту по текущим курсам и возвращает итог с разбивкой по валютам.\nБез параметра in используется валюта отображения из настроек пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Суммарная стоимость баланса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Валюта оценки (USD, RUB, EUR)",
+                        "name": "in",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BalanceTotal"
+                        }
+                    },
+                    "400": {
+                        "description": "Неподдерживаемая валюта",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Курсы валют недоступны",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/devices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает устройства, с которых выполнялся вход, начиная с последнего",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Устройства пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Device"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обменивает указанную сумму из одной валюты в другую по текущему курсу.\nС dry_run=true курс, комиссия и достаточность средств проверяются без обмена.\nВне окна исполнения пары обмен отклоняется (409) или с outside_hours=queue ставится в очередь\nдо открытия окна (202) и исполняется по курсу на момент открытия.\nС execute=next_rate обмен ставится в очередь (202) и исполняется по следующему курсу, опубликованному сервисом обмена",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Обмен валют",
+                "parameters": [
+                    {
+                        "description": "Данные для обмена",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ExchangeRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Пробный режим: все проверки и расчеты без изменения баланса",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат обмена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExchangeResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Обмен поставлен в очередь до открытия окна или публикации курса",
+                        "schema": {
+                            "$ref": "#/definitions/models.QueuedExchange"
+                        }
+                    },
+                    "400": {
+                        "description": "Недостаточно средств/некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Обмен пары вне окна исполнения",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много обменов в очереди",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Операции в валюте временно приостановлены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/chart": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает свечи курса пары валют (открытие, максимум, минимум, закрытие) за период до текущего момента по истории курсов сервиса обмена",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "График курса валют",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Пара валют (например, USD/RUB)",
+                        "name": "pair",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Длительность свечи: 15m, 1h, 1d (по умолчанию 1h)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Период графика: 24h, 7d, 30d (по умолчанию 7d)",
+                        "name": "range",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RateChart"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры графика",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Нет истории курса за период",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис обмена недоступен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/dca": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает планы пользователя от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Планы регулярных обменов",
+                "responses": {
+                    "200": {
+                        "description": "Планы (plans)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.DCAPlan"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает план обмена фиксированной суммы по расписанию (например, 50 USD в EUR каждый день в 12:00, 30 обменов).\nОбмен при недостатке средств пропускается с уведомлением",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Создание плана регулярных обменов",
+                "parameters": [
+                    {
+                        "description": "Параметры плана",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateDCAPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.DCAPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры плана",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много активных планов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/dca/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает план, его обмены с результатами, количество оставшихся обменов, итоги и средний курс",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Ход выполнения плана регулярных обменов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плана",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DCAPlanProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "План не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменяет активный план; выполненные обмены не отменяются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Отмена плана регулярных обменов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плана",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отмененный план",
+                        "schema": {
+                            "$ref": "#/definitions/models.DCAPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "План не найден или уже завершен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает обмены пользователя за период (по умолчанию - последние 30 дней, не более 366 дней)\nс валютной парой, списанной суммой, примененным курсом, комиссией и зачисленной суммой.\nВключает архив журнала операций (для налоговой отчетности)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "История обменов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339 или YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество обменов (1-500, по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExchangeHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный период или limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/queued": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает обмены пользователя, отложенные до открытия окна исполнения пары или публикации курса,\nс результатом исполнения",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Обмены в очереди",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.QueuedExchange"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/queued/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменяет обмен, ожидающий открытия окна исполнения или публикации курса. Исполняющийся обмен не отменяется",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Отмена обмена в очереди",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID обмена в очереди",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отмененный обмен",
+                        "schema": {
+                            "$ref": "#/definitions/models.QueuedExchange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Обмен не найден или уже исполняется",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает текущие курсы обмена валют",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Получить курсы валют",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExchangeRatesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Сервис обмена недоступен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/kyc": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает статус верификации (none, pending, verified, rejected), комментарий администратора и поданные документы",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KYC"
+                ],
+                "summary": "Статус верификации",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KYCStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/kyc/documents": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Загружает документ (JPEG, PNG или PDF) и переводит пользователя в статус pending. После отказа документы можно подать повторно",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "KYC"
+                ],
+                "summary": "Подача документа на верификацию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип документа (passport, id_card, driver_license, proof_of_address, selfie)",
+                        "name": "type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл документа",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.KYCDocument"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный тип или формат документа",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь уже верифицирован",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Вход в систему с получением JWT токена и токена обновления (см. /token/refresh).\nПри входе с нового устройства пользователю отправляется уведомление; если включено\nподтверждение (DEVICE_CONFIRMATION), вместо токена возвращается 202 с идентификатором\nподтверждения, а код отправляется на email пользователя (см. /login/confirm)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Аутентификация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для входа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ответ на CAPTCHA (после CAPTCHA_LOGIN_AFTER_FAILURES неудачных входов)",
+                        "name": "X-Captcha-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор установки клиента (уточняет отпечаток устройства)",
+                        "name": "X-Device-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Арендатор (бренд); по умолчанию определяется по хосту",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ с токеном",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Вход с нового устройства требует подтверждения",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceConfirmationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка аутентификации",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Требуется или не пройдена проверка CAPTCHA",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много попыток с IP адреса (заголовок Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Не удалось отправить код подтверждения",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/confirm": {
+            "post": {
+                "description": "Проверяет код из письма и выдает JWT токен и токен обновления; устройство запоминается как доверенное.\nПосле нескольких неверных кодов подтверждение аннулируется, нужно войти заново",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Подтверждение входа с нового устройства",
+                "parameters": [
+                    {
+                        "description": "Идентификатор подтверждения и код",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ConfirmDeviceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ с токеном",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код или подтверждение истекло",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много попыток с IP адреса (заголовок Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает уровень пользователя по объему обменов за 30 дней, скидку на комиссию и прогресс до следующего уровня",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Уровень лояльности",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoyaltyStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает уведомления пользователя об операциях с состоянием доставки (pending, sent, failed) от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Уведомления об операциях",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество уведомлений (по умолчанию 50, не больше 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Notification"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/routes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает правила доставки уведомлений об операциях по каналам (пусто - каналы по умолчанию)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Правила доставки уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NotificationRoute"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/routes/{channel}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает или заменяет правило для канала: адрес (URL https для webhook, email), типы записей журнала\nи наименьшую сумму. С первым правилом уведомления доставляются только по правилам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Правило доставки уведомлений в канал",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Канал (email, telegram, webhook)",
+                        "name": "channel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Правило",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SaveNotificationRouteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationRoute"
+                        }
+                    },
+                    "400": {
+                        "description": "Канал не подключен или правило некорректно",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет правило для канала; без правил уведомления доставляются в каналы по умолчанию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Удаление правила доставки уведомлений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Канал",
+                        "name": "channel",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правила для канала нет",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Для каждой валюты баланса возвращает средний курс приобретения (по всей истории операций, методом средней стоимости),\nтекущую стоимость и нереализованный доход или убыток по текущим курсам.\nБез параметра in используется валюта отображения из настроек пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Валютные позиции и нереализованный доход",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Валюта оценки (USD, RUB, EUR)",
+                        "name": "in",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Portfolio"
+                        }
+                    },
+                    "400": {
+                        "description": "Неподдерживаемая валюта",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Курсы валют недоступны",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает валюту отображения, локаль и часовой пояс пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Настройки пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPreferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменяет заданные поля: валюту отображения (USD, RUB, EUR), локаль (ru, en), часовой пояс IANA",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Изменение настроек пользователя",
+                "parameters": [
+                    {
+                        "description": "Новые значения настроек",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Недопустимое значение",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo/redeem": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Зачисляет бонус по фиксированному промокоду. Процентные промокоды применяются при пополнении (поле promo_code)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Активация промокода",
+                "parameters": [
+                    {
+                        "description": "Промокод",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RedeemPromoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoRedemption"
+                        }
+                    },
+                    "400": {
+                        "description": "Промокод не подходит к операции",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Промокод недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Промокод уже использован",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/push/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает зарегистрированные токены устройств пользователя от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Устройства для push уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PushToken"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сохраняет токен FCM или APNs установки мобильного приложения; приложение вызывает метод при каждом запуске",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Регистрация устройства для push уведомлений",
+                "parameters": [
+                    {
+                        "description": "Платформа и токен устройства",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterPushTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PushToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Платформа не настроена или токен некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/push/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет токен устройства (выход из приложения на устройстве)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Отключение push уведомлений устройства",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID регистрации токена",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Токен не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quota": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает тарифный план пользователя, квоту запросов и ее использование в текущем окне (запрос не расходует квоту)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Квота запросов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuotaUsage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/receipts/{code}": {
+            "get": {
+                "description": "Проверяет код квитанции об операции и возвращает данные операции по журналу (без второй стороны перевода).\nНе требует аутентификации: код проверяет получатель квитанции",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Проверка квитанции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Код проверки из квитанции",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Receipt"
+                        }
+                    },
+                    "404": {
+                        "description": "Код проверки неверен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Создает нового пользователя в системе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Регистрация нового пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для регистрации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ответ на CAPTCHA (если включена CAPTCHA_PROVIDER)",
+                        "name": "X-Captcha-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Арендатор (бренд); по умолчанию определяется по хосту",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Проверка CAPTCHA не пройдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Имя пользователя или email уже заняты",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много попыток с IP адреса (заголовок Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/tax": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выгружает в CSV обмены за календарный год (UTC), в которых продана валюта, отличная от валюты отчетности,\nс выручкой, стоимостью приобретения (FIFO или средняя стоимость - настройка сервера) и курсовым доходом или убытком.\nСтоимость приобретения рассчитывается по всей истории операций, включая архив журнала",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Налоговый отчет о курсовых доходах",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Год отчета (по умолчанию - прошлый год)",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV отчет",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный год",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/telegram/link": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдает одноразовый код привязки аккаунта Telegram; код отправляется боту командой /link в личном чате, после чего из бота доступен обмен валют",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Код привязки Telegram",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TelegramLinkCode"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Снимает привязку аккаунта Telegram: обмен из бота становится недоступен",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Отвязка Telegram",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Аккаунт Telegram не привязан",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/refresh": {
+            "post": {
+                "description": "Выдает новый JWT токен по токену обновления без повторного входа. Токен обновления одноразовый:\nв ответе следующий токен той же сессии, срок которого продлевается на REFRESH_TOKEN_IDLE_TTL,\nно не дальше REFRESH_SESSION_MAX_LIFETIME от входа по паролю. Повторное использование\nзамененного токена отзывает сессию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Обновление JWT токена",
+                "parameters": [
+                    {
+                        "description": "Токен обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Арендатор (бренд); по умолчанию определяется по хосту",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новый JWT токен и следующий токен обновления",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Токен недействителен (code refresh_token_invalid) или сессия истекла (code session_expired)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает операции пользователя за период (по умолчанию - последние 30 дней, не более 366 дней).\nВремя и суммы дополнительно оформляются по настройкам пользователя (часовой пояс, локаль, валюта отображения)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "История операций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339 или YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (1-500, по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный период или limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выгружает операции пользователя за период (по умолчанию - последние 30 дней, не более 366 дней) от старых\nк новым, без ограничения количества: csv (по умолчанию), xlsx (книга Excel с оформлением и итогами\nпо валютам) или jsonl (запись журнала на строку, передается потоком по мере чтения)",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Выгрузка истории операций",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "Формат выгрузки",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339 или YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Выгрузка операций",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат или период",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/receipt": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает квитанцию об операции, в которую входит запись журнала, в JSON или PDF.\nКвитанция содержит записи операции пользователя и подписанный код проверки для публичной проверки",
+                "produces": [
+                    "application/json",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Квитанция об операции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID записи журнала операций",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "Формат квитанции",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Receipt"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Запись не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/deposit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пополнение баланса пользователя в указанной валюте. С процентным промокодом (promo_code) бонус зачисляется в той же операции.\nС dry_run=true пополнение и промокод проверяются, но не выполняются: ответ содержит баланс, который получился бы после операции",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Пополнить баланс",
+                "parameters": [
+                    {
+                        "description": "Данные для пополнения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DepositRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Пробный режим: все проверки и расчеты без изменения баланса",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ответ с новым балансом",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос или промокод не подходит к пополнению",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка аутентификации",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Промокод недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Промокод уже использован",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Операции в валюте временно приостановлены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Перевод средств другому пользователю по имени пользователя. Крупные и подозрительные переводы могут быть отправлены на проверку (202) или отклонены (403).\nС dry_run=true выполняются все проверки без перевода; перевод, который был бы отправлен на проверку, в очередь не ставится",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Перевод средств",
+                "parameters": [
+                    {
+                        "description": "Данные для перевода",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Пробный режим: все проверки и расчеты без изменения баланса",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Операция отправлена на проверку",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Недостаточно средств/некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Операция отклонена системой безопасности",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Операции в валюте временно приостановлены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Снятие средств с баланса пользователя. С dry_run=true выполняются все проверки (средства, антифрод) без снятия",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Снять средства",
+                "parameters": [
+                    {
+                        "description": "Данные для снятия",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WithdrawRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Пробный режим: все проверки и расчеты без изменения баланса",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Недостаточно средств/некорректная валюта",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Операции в валюте временно приостановлены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/payments": {
+            "post": {
+                "description": "Принимает событие платежного провайдера и зачисляет платеж (payment.succeeded).\nЗапрос подписывается HMAC-SHA256 общим секретом (PAYMENT_WEBHOOK_SECRET) от строки\n\"timestamp.nonce.тело\"; время отправки должно быть в окне PAYMENT_WEBHOOK_TOLERANCE,\nnonce - новым для каждой доставки. Повторная доставка события с тем же event_id\nне зачисляется и возвращает первое зачисление с duplicate=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Webhook платежного провайдера",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Время отправки (Unix, секунды)",
+                        "name": "X-Webhook-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Одноразовое значение запроса",
+                        "name": "X-Webhook-Nonce",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подпись HMAC-SHA256 в hex",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Событие провайдера",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Платеж зачислен (или уже был зачислен)",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentCredit"
+                        }
+                    },
+                    "202": {
+                        "description": "Событие без зачисления принято",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное событие",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверная подпись или время отправки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Повторное использование nonce",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Кошелек не найден или заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка (провайдер должен повторить доставку)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.ActivityEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Действие (для операций - тип записи журнала)",
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Категория: login, profile, operation",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Время события",
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Подробности (для операций - валюта, сумма, операция)",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "ip": {
+                    "description": "IP адрес клиента",
+                    "type": "string"
+                },
+                "user_agent": {
+                    "description": "User-Agent клиента",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ActivityFeed": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "События, от новых к старым",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ActivityEvent"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Курсор следующей страницы (пусто - страница последняя)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "reason",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Сумма: положительная - зачисление, отрицательная - списание",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Валюта",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                },
+                "reason": {
+                    "description": "Обоснование (не короче 10 символов)",
+                    "type": "string",
+                    "minLength": 10
+                },
+                "user_id": {
+                    "description": "Владелец кошелька",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Действие (например adjustment.applied)",
+                    "type": "string"
+                },
+                "admin_id": {
+                    "description": "Администратор",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Время действия",
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Подробности действия",
+                    "type": "object"
+                },
+                "id": {
+                    "description": "Идентификатор записи",
+                    "type": "integer"
+                },
+                "target_user_id": {
+                    "description": "Пользователь, которого касается действие",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Balance": {
+            "type": "object",
+            "properties": {
+                "EUR": {
+                    "description": "Сумма в евро",
+                    "type": "number"
+                },
+                "RUB": {
+                    "description": "Сумма в рублях",
+                    "type": "number"
+                },
+                "USD": {
+                    "description": "Сумма в долларах",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "description": "Время последнего изменения кошелька",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BalanceAdjustment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Сумма со знаком: положительная - зачисление, отрицательная - списание",
+                    "type": "number"
+                },
+                "comment": {
+                    "description": "Комментарий при отклонении",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Время создания",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Валюта",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор корректировки",
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "Обязательное обоснование",
+                    "type": "string"
+                },
+                "requested_by": {
+                    "description": "Администратор, создавший корректировку",
+                    "type": "integer"
+                },
+                "resolved_at": {
+                    "description": "Время решения",
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "description": "Администратор, подтвердивший или отклонивший корректировку",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Статус корректировки",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Владелец кошелька",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BalanceMismatch": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Валюта",
+                    "type": "string"
+                },
+                "difference": {
+                    "description": "Разница: wallet_balance - ledger_balance",
+                    "type": "number"
+                },
+                "ledger_balance": {
+                    "description": "Баланс, пересчитанный по журналу (включая архив)",
+                    "type": "number"
+                },
+                "user_id": {
+                    "description": "Владелец кошелька",
+                    "type": "integer"
+                },
+                "wallet_balance": {
+                    "description": "Баланс в таблице кошельков",
+                    "type": "number"
+                }
+            }
+        },
+        "models.BalanceTotal": {
+            "type": "object",
+            "properties": {
+                "breakdown": {
+                    "description": "Оценка по каждой валюте",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BalanceValuation"
+                    }
+                },
+                "currency": {
+                    "description": "Валюта оценки",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Сумма всех балансов в валюте оценки",
+                    "type": "number"
+                },
+                "valued_at": {
+                    "description": "Момент оценки (курсы на это время)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BalanceValuation": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Баланс в этой валюте",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Валюта баланса",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Курс к валюте оценки",
+                    "type": "number"
+                },
+                "value": {
+                    "description": "Баланс в валюте оценки",
+                    "type": "number"
+                }
+            }
+        },
+        "models.CacheFlushRequest": {
+            "type": "object",
+            "required": [
+                "scopes"
+            ],
+            "properties": {
+                "scopes": {
+                    "description": "Группы записей: rates, balances, limits",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CacheFlushResult": {
+            "type": "object",
+            "properties": {
+                "broadcast": {
+                    "description": "Сброс разослан остальным экземплярам (Redis)",
+                    "type": "boolean"
+                },
+                "deleted": {
+                    "description": "Удалено записей по группам",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                }
+            }
+        },
+        "models.ConfigEntry": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "Значение по умолчанию",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Имя переменной окружения (SERVER_ADDRESS)",
+                    "type": "string"
+                },
+                "overridden": {
+                    "description": "Значение отличается от значения по умолчанию",
+                    "type": "boolean"
+                },
+                "secret": {
+                    "description": "Параметр содержит секрет, значение скрыто",
+                    "type": "boolean"
+                },
+                "value": {
+                    "description": "Действующее значение в формате переменной окружения ([REDACTED] для заданных секретов)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConfirmDeviceRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "confirmation_id"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Код подтверждения из письма",
+                    "type": "string"
+                },
+                "confirmation_id": {
+                    "description": "Идентификатор из ответа /login",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateDCAPlanRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "from_currency",
+                "legs",
+                "time",
+                "to_currency"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Сумма одного обмена",
+                    "type": "number"
+                },
+                "from_currency": {
+                    "description": "Исходная валюта",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                },
+                "legs": {
+                    "description": "Количество обменов (например, 30)",
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "Периодичность: daily (по умолчанию), weekly",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Время обмена ЧЧ:ММ",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Часовой пояс IANA (по умолчанию - из настроек пользователя)",
+                    "type": "string"
+                },
+                "to_currency": {
+                    "description": "Целевая валюта",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                }
+            }
+        },
+        "models.CreatePromoRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "currency",
+                "kind",
+                "value"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Код (буквы, цифры, дефис, подчеркивание)",
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                },
+                "currency": {
+                    "description": "Валюта",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                },
+                "expires_at": {
+                    "description": "Срок действия (RFC3339)",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Вид промокода",
+                    "type": "string",
+                    "enum": [
+                        "fixed",
+                        "percent"
+                    ]
+                },
+                "max_bonus": {
+                    "description": "Максимальный бонус для percent",
+                    "type": "number"
+                },
+                "max_uses": {
+                    "description": "Максимум активаций",
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "Сумма или процент (для percent - не больше 100)",
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateUserRequest": {
+            "type": "object",
+            "required": [
                 "email",
                 "password",
                 "username"
             ],
             "properties": {
-                "email": {
-                    "description": "Обязательное: да\nФормат: email\nПример: user@example.com",
+                "email": {
+                    "description": "Валидный email",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Пароль (мин. 8 символов)",
+                    "type": "string",
+                    "minLength": 8
+                },
+                "username": {
+                    "description": "Логин (3-50 символов)",
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "models.CurrencyFreeze": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Валюта",
+                    "type": "string"
+                },
+                "frozen_at": {
+                    "description": "Время приостановки",
+                    "type": "string"
+                },
+                "frozen_by": {
+                    "description": "Администратор, приостановивший операции",
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "Причина (показывается пользователям)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DCALeg": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Причина пропуска или неисполнения",
+                    "type": "string"
+                },
+                "exchanged_amount": {
+                    "description": "Полученная сумма (за вычетом комиссии)",
+                    "type": "number"
+                },
+                "executed_at": {
+                    "description": "Время выполнения",
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Номер обмена в плане (с 1)",
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "Примененный курс",
+                    "type": "number"
+                },
+                "scheduled_at": {
+                    "description": "Время по расписанию",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Результат: executing, executed, skipped, failed",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DCAPlan": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Сумма одного обмена в исходной валюте",
+                    "type": "number"
+                },
+                "created_at": {
+                    "description": "Время создания",
+                    "type": "string"
+                },
+                "executed": {
+                    "description": "Выполнено обменов",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Не выполнено по другим причинам",
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "description": "Время завершения или отмены",
+                    "type": "string"
+                },
+                "from_currency": {
+                    "description": "Исходная валюта",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор плана",
+                    "type": "integer"
+                },
+                "legs": {
+                    "description": "Количество обменов плана",
+                    "type": "integer"
+                },
+                "next_run_at": {
+                    "description": "Время следующего обмена (для активного плана)",
+                    "type": "string"
+                },
+                "period": {
+                    "description": "Периодичность: daily, weekly",
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Пропущено из-за недостатка средств",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Состояние: active, completed, cancelled",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Время обмена ЧЧ:ММ",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Часовой пояс IANA времени обмена",
+                    "type": "string"
+                },
+                "to_currency": {
+                    "description": "Целевая валюта",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DCAPlanProgress": {
+            "type": "object",
+            "properties": {
+                "average_rate": {
+                    "description": "Средний курс: получено / списано (0 - обменов не было)",
+                    "type": "number"
+                },
+                "legs": {
+                    "description": "Обмены плана от первого к последнему",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DCALeg"
+                    }
+                },
+                "plan": {
+                    "description": "План",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DCAPlan"
+                        }
+                    ]
+                },
+                "remaining": {
+                    "description": "Осталось обменов",
+                    "type": "integer"
+                },
+                "total_received": {
+                    "description": "Получено в целевой валюте по выполненным обменам",
+                    "type": "number"
+                },
+                "total_spent": {
+                    "description": "Списано в исходной валюте по выполненным обменам",
+                    "type": "number"
+                }
+            }
+        },
+        "models.DepositRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Сумма пополнения (\u003e0)",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Валюта (USD/RUB/EUR)",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                },
+                "promo_code": {
+                    "description": "Промокод на бонус к пополнению (необязательно)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Device": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "description": "Город последнего входа (GeoIP)",
+                    "type": "string"
+                },
+                "confirmed": {
+                    "description": "Вход с устройства подтвержден кодом",
+                    "type": "boolean"
+                },
+                "country": {
+                    "description": "Страна последнего входа (GeoIP)",
+                    "type": "string"
+                },
+                "first_seen": {
+                    "description": "Первый вход",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор устройства",
+                    "type": "integer"
+                },
+                "last_ip": {
+                    "description": "IP адрес последнего входа",
+                    "type": "string"
+                },
+                "last_seen": {
+                    "description": "Последний вход",
+                    "type": "string"
+                },
+                "user_agent": {
+                    "description": "User-Agent последнего входа",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeviceConfirmationResponse": {
+            "type": "object",
+            "properties": {
+                "confirmation_id": {
+                    "description": "Идентификатор подтверждения для /login/confirm",
+                    "type": "string"
+                },
+                "confirmation_required": {
+                    "description": "Всегда true",
+                    "type": "boolean"
+                },
+                "expires_in": {
+                    "description": "Время действия кода в секундах",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DrainStatus": {
+            "type": "object",
+            "properties": {
+                "drained_at": {
+                    "description": "Завершение вывода",
+                    "type": "string"
+                },
+                "in_flight": {
+                    "description": "Выполняющиеся HTTP запросы",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "Начало вывода из балансировки",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "Состояние: serving, draining, drained",
+                    "type": "string"
+                },
+                "timed_out": {
+                    "description": "Запросы не завершились за DRAIN_TIMEOUT",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.EffectiveConfig": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Параметры в порядке объявления",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConfigEntry"
+                    }
+                },
+                "features": {
+                    "description": "Включенные и выключенные функции (balance_cache, risk, telegram_bot...)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Код ошибки аутентификации в ответах 401 (token_expired, token_malformed и др.)",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Описание ошибки",
+                    "type": "string"
+                },
+                "trace_id": {
+                    "description": "Идентификатор запроса (заголовок X-Request-ID) для поиска в журнале",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExchangeHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "exchanges": {
+                    "description": "Обмены, от новых к старым",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExchangeRecord"
+                    }
+                },
+                "from": {
+                    "description": "Начало периода (включительно)",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Конец периода (не включительно)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "description": "Карта курсов (например: {\"USD\":1,\"RUB\":75.5})",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
+        "models.ExchangeRecord": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Списанная сумма в исходной валюте",
+                    "type": "number"
+                },
+                "created_at": {
+                    "description": "Время обмена",
+                    "type": "string"
+                },
+                "exchanged_amount": {
+                    "description": "Сумма по курсу в целевой валюте до комиссии",
+                    "type": "number"
+                },
+                "fee": {
+                    "description": "Комиссия в целевой валюте",
+                    "type": "number"
+                },
+                "from_currency": {
+                    "description": "Исходная валюта",
+                    "type": "string"
+                },
+                "operation_id": {
+                    "description": "Идентификатор операции в журнале",
+                    "type": "string"
+                },
+                "quote_id": {
+                    "description": "Котировка, подтвержденная пользователем (пусто - обмен по текущему курсу)",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Примененный курс (целевая валюта за единицу исходной)",
+                    "type": "number"
+                },
+                "rate_source": {
+                    "description": "Источники курсов валют пары у сервиса обмена (cbr, ecb, manual; через запятую)",
+                    "type": "string"
+                },
+                "rate_timestamp": {
+                    "description": "Время получения курсов от сервиса обмена",
+                    "type": "string"
+                },
+                "received_amount": {
+                    "description": "Зачисленная сумма в целевой валюте за вычетом комиссии",
+                    "type": "number"
+                },
+                "to_currency": {
+                    "description": "Целевая валюта",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExchangeRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "from_currency",
+                "to_currency"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Сумма для обмена (\u003e0)",
+                    "type": "number"
+                },
+                "execute": {
+                    "description": "Момент исполнения: now (по умолчанию) или next_rate - по следующему опубликованному курсу",
+                    "type": "string",
+                    "enum": [
+                        "now",
+                        "next_rate"
+                    ]
+                },
+                "from_currency": {
+                    "description": "Исходная валюта",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                },
+                "outside_hours": {
+                    "description": "Вне окна исполнения пары: reject (по умолчанию) или queue",
+                    "type": "string",
+                    "enum": [
+                        "reject",
+                        "queue"
+                    ]
+                },
+                "to_currency": {
+                    "description": "Целевая валюта",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                }
+            }
+        },
+        "models.ExchangeResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Пробный режим: обмен рассчитан, но не выполнен",
+                    "type": "boolean"
+                },
+                "exchanged_amount": {
+                    "description": "Полученная сумма (за вычетом комиссии)",
+                    "type": "number"
+                },
+                "fee": {
+                    "description": "Комиссия в целевой валюте",
+                    "type": "number"
+                },
+                "fee_percent": {
+                    "description": "Примененная комиссия в процентах (с учетом скидки уровня)",
+                    "type": "number"
+                },
+                "message": {
+                    "description": "Сообщение о результате",
+                    "type": "string"
+                },
+                "new_balance": {
+                    "description": "Обновленный баланс",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Balance"
+                        }
+                    ]
+                },
+                "rate": {
+                    "description": "Примененный курс обмена",
+                    "type": "number"
+                },
+                "rate_source": {
+                    "description": "Источники курсов валют пары у сервиса обмена (cbr, ecb, manual; через запятую)",
+                    "type": "string"
+                },
+                "rate_timestamp": {
+                    "description": "Время получения курсов от сервиса обмена",
+                    "type": "string"
+                },
+                "tier": {
+                    "description": "Уровень лояльности пользователя",
+                    "type": "string"
+                }
+            }
+        },
+        "models.FreezeCurrencyRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Причина (до 256 символов, необязательно)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.IPBan": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Количество попыток в окне на момент блокировки",
+                    "type": "integer"
+                },
+                "banned_at": {
+                    "description": "Время блокировки",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Время автоматического снятия блокировки",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "Заблокированный адрес",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Причина блокировки",
+                    "type": "string"
+                }
+            }
+        },
+        "models.KYCDocument": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "description": "MIME-тип файла",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Время подачи",
+                    "type": "string"
+                },
+                "file_name": {
+                    "description": "Исходное имя файла",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор документа",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Размер файла в байтах",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Тип документа (passport, id_card, ...)",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Владелец документа",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.KYCStatusResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "description": "Комментарий администратора (например, причина отказа)",
+                    "type": "string"
+                },
+                "documents": {
+                    "description": "Поданные документы",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KYCDocument"
+                    }
+                },
+                "status": {
+                    "description": "Статус верификации",
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "description": "Пароль пользователя",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Логин пользователя",
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "refresh_expires_at": {
+                    "description": "Срок действия токена обновления",
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "description": "Токен обновления: новый JWT без повторного входа (POST /token/refresh)",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "JWT токен для авторизации",
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoyaltyStatus": {
+            "type": "object",
+            "properties": {
+                "discount_percent": {
+                    "description": "Скидка на комиссию обмена, %",
+                    "type": "number"
+                },
+                "fee_percent": {
+                    "description": "Комиссия обмена с учетом скидки, %",
+                    "type": "number"
+                },
+                "next_tier": {
+                    "description": "Следующий уровень (пусто для максимального)",
+                    "type": "string"
+                },
+                "next_tier_volume": {
+                    "description": "Объем, с которого действует следующий уровень",
+                    "type": "number"
+                },
+                "tier": {
+                    "description": "Текущий уровень",
+                    "type": "string"
+                },
+                "volume_30d": {
+                    "description": "Объем обменов за 30 дней в USD",
+                    "type": "number"
+                },
+                "volume_to_next_tier": {
+                    "description": "Сколько осталось до следующего уровня",
+                    "type": "number"
+                }
+            }
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Попыток отправки",
+                    "type": "integer"
+                },
+                "channel": {
+                    "description": "Канал (email, telegram, webhook, push)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Время операции",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор уведомления",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "Ошибка последней попытки",
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "Время следующей попытки",
+                    "type": "string"
+                },
+                "operation_id": {
+                    "description": "Операция в журнале",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Записи журнала операции, относящиеся к получателю ([]Transaction)",
+                    "type": "object"
+                },
+                "sent_at": {
+                    "description": "Время доставки",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Состояние (pending, sent, failed)",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "Адрес из правила пользователя (пусто - адрес по умолчанию канала)",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Получатель",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NotificationRoute": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "description": "Канал (email, telegram, webhook, push)",
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Правило действует (выключенное правило отключает канал)",
+                    "type": "boolean"
+                },
+                "min_amount": {
+                    "description": "Наименьшая сумма записи в ее валюте (0 - любая)",
+                    "type": "number"
+                },
+                "target": {
+                    "description": "Адрес в канале: URL для webhook, email (пусто - email профиля)",
+                    "type": "string"
+                },
+                "types": {
+                    "description": "Типы записей журнала (deposit, transfer_in, ...; пусто - все)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "description": "Время изменения",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentCredit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Зачисленная сумма",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Валюта зачисления",
+                    "type": "string"
+                },
+                "duplicate": {
+                    "description": "Событие уже было обработано, повторного зачисления нет",
+                    "type": "boolean"
+                },
+                "event_id": {
+                    "description": "Идентификатор события у провайдера",
+                    "type": "string"
+                },
+                "processed_at": {
+                    "description": "Время первой обработки события",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Пользователь",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PaymentEvent": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "event_id",
+                "type",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Сумма платежа",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Валюта платежа",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                },
+                "event_id": {
+                    "description": "Идентификатор события у провайдера (зачисление выполняется один раз)",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "type": {
+                    "description": "Тип события (payment.succeeded)",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Пользователь, пополняющий кошелек",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Portfolio": {
+            "type": "object",
+            "properties": {
+                "cost_basis": {
+                    "description": "Суммарная стоимость приобретения",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Валюта оценки",
+                    "type": "string"
+                },
+                "market_value": {
+                    "description": "Суммарная текущая стоимость",
+                    "type": "number"
+                },
+                "positions": {
+                    "description": "Позиции по валютам",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PortfolioPosition"
+                    }
+                },
+                "unrealized_pnl": {
+                    "description": "Суммарный нереализованный доход",
+                    "type": "number"
+                },
+                "valued_at": {
+                    "description": "Момент оценки (курсы на это время)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PortfolioPosition": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Баланс в этой валюте",
+                    "type": "number"
+                },
+                "average_rate": {
+                    "description": "Средний курс приобретения в валюте оценки",
+                    "type": "number"
+                },
+                "cost_basis": {
+                    "description": "Стоимость приобретения в валюте оценки",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Валюта позиции",
+                    "type": "string"
+                },
+                "current_rate": {
+                    "description": "Текущий курс к валюте оценки",
+                    "type": "number"
+                },
+                "market_value": {
+                    "description": "Текущая стоимость в валюте оценки",
+                    "type": "number"
+                },
+                "unrealized_pnl": {
+                    "description": "Нереализованный доход (убыток - отрицательный)",
+                    "type": "number"
+                },
+                "unrealized_pnl_percent": {
+                    "description": "Нереализованный доход в процентах от стоимости приобретения",
+                    "type": "number"
+                }
+            }
+        },
+        "models.PromoCode": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Промокод не отключен администратором",
+                    "type": "boolean"
+                },
+                "code": {
+                    "description": "Код (хранится в верхнем регистре)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Время создания",
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "Администратор, создавший промокод",
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "Валюта бонуса (для percent - валюта пополнения)",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Срок действия (nil - бессрочный)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор промокода",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "Вид: fixed или percent",
+                    "type": "string"
+                },
+                "max_bonus": {
+                    "description": "Максимальный бонус для percent",
+                    "type": "number"
+                },
+                "max_uses": {
+                    "description": "Максимум активаций (nil - без ограничения)",
+                    "type": "integer"
+                },
+                "uses": {
+                    "description": "Количество активаций",
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "Сумма (fixed) или процент (percent)",
+                    "type": "number"
+                }
+            }
+        },
+        "models.PromoRedemption": {
+            "type": "object",
+            "properties": {
+                "bonus": {
+                    "description": "Зачисленный бонус",
+                    "type": "number"
+                },
+                "code": {
+                    "description": "Промокод",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Время активации",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Валюта бонуса",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор активации",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "Пользователь",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PushToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Первая регистрация",
+                    "type": "string"
+                },
+                "device_name": {
+                    "description": "Название устройства, заданное приложением",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор регистрации",
+                    "type": "integer"
+                },
+                "platform": {
+                    "description": "Платформа (fcm, apns)",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Токен устройства у платформы",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Последняя регистрация (приложение обновляет токен при запуске)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.QueuedExchange": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Сумма для обмена",
+                    "type": "number"
+                },
+                "created_at": {
+                    "description": "Время постановки в очередь",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Причина неисполнения",
+                    "type": "string"
+                },
+                "exchanged_amount": {
+                    "description": "Полученная сумма (за вычетом комиссии)",
+                    "type": "number"
+                },
+                "execute_after": {
+                    "description": "Исполнение не раньше этого времени",
+                    "type": "string"
+                },
+                "executed_at": {
+                    "description": "Время исполнения, отказа или отмены",
+                    "type": "string"
+                },
+                "from_currency": {
+                    "description": "Исходная валюта",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор",
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "Примененный курс",
+                    "type": "number"
+                },
+                "status": {
+                    "description": "Состояние: pending, executing, executed, failed, cancelled",
+                    "type": "string"
+                },
+                "to_currency": {
+                    "description": "Целевая валюта",
+                    "type": "string"
+                },
+                "trigger": {
+                    "description": "Условие исполнения: trading_hours, next_rate",
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Запросов за окно (0 - без ограничения)",
+                    "type": "integer"
+                },
+                "plan": {
+                    "description": "Тарифный план пользователя",
+                    "type": "string"
+                },
+                "remaining": {
+                    "description": "Осталось запросов в текущем окне",
+                    "type": "integer"
+                },
+                "reset_at": {
+                    "description": "Начало следующего окна",
+                    "type": "string"
+                },
+                "used": {
+                    "description": "Выполнено запросов в текущем окне",
+                    "type": "integer"
+                },
+                "window": {
+                    "description": "Длительность окна (например, 1h0m0s)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RateCandle": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "description": "Курс на конец интервала",
+                    "type": "number"
+                },
+                "high": {
+                    "description": "Максимальный курс",
+                    "type": "number"
+                },
+                "low": {
+                    "description": "Минимальный курс",
+                    "type": "number"
+                },
+                "open": {
+                    "description": "Курс на начало интервала",
+                    "type": "number"
+                },
+                "time": {
+                    "description": "Начало интервала",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RateChart": {
+            "type": "object",
+            "properties": {
+                "candles": {
+                    "description": "Свечи в порядке времени",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RateCandle"
+                    }
+                },
+                "interval": {
+                    "description": "Длительность свечи (например, 1h)",
+                    "type": "string"
+                },
+                "pair": {
+                    "description": "Пара валют (например, USD/RUB)",
+                    "type": "string"
+                },
+                "range": {
+                    "description": "Период графика до текущего момента (например, 7d)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Receipt": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Время операции",
+                    "type": "string"
+                },
+                "entries": {
+                    "description": "Записи операции владельца по возрастанию ID",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReceiptEntry"
+                    }
+                },
+                "issued_at": {
+                    "description": "Время формирования квитанции",
+                    "type": "string"
+                },
+                "operation_id": {
+                    "description": "Идентификатор операции",
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "description": "Запись журнала, по которой выдана квитанция",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Тип записи, по которой выдана квитанция",
+                    "type": "string"
+                },
+                "verification_code": {
+                    "description": "Подписанный код проверки",
+                    "type": "string"
+                },
+                "verify_url": {
+                    "description": "Адрес публичной проверки квитанции",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReceiptEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Сумма со знаком: положительная - зачисление, отрицательная - списание",
+                    "type": "number"
+                },
+                "counterparty_id": {
+                    "description": "Вторая сторона перевода (не раскрывается при публичной проверке)",
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "Валюта",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор записи",
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "Курс обмена (только для обмена)",
+                    "type": "number"
+                },
+                "type": {
+                    "description": "Тип записи (deposit, exchange_out, fee, ...)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReconciliationReport": {
+            "type": "object",
+            "properties": {
+                "finished_at": {
+                    "description": "Окончание сверки",
+                    "type": "string"
+                },
+                "mismatches": {
+                    "description": "Найденные расхождения",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BalanceMismatch"
+                    }
+                },
+                "quarantined": {
+                    "description": "Кошельки, заблокированные по итогам сверки",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "started_at": {
+                    "description": "Начало сверки",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RedeemPromoRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Промокод",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "description": "Токен обновления из ответа входа или предыдущего обновления",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RegisterPushTokenRequest": {
+            "type": "object",
+            "properties": {
+                "device_name": {
+                    "description": "Название устройства",
                     "type": "string"
                 },
-                "password": {
-                    "description": "Обязательное: да\nМинимальная длина: 8\nПример: securePass123",
-                    "type": "string",
-                    "minLength": 8
+                "platform": {
+                    "description": "Платформа (fcm, apns)",
+                    "type": "string"
                 },
-                "username": {
-                    "description": "Обязательное: да\nМинимальная длина: 3\nМаксимальная длина: 50\nПример: ivan_ivanov",
+                "token": {
+                    "description": "Токен устройства",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RejectAdjustmentRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "description": "Причина отклонения",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResolveKYCRequest": {
+            "type": "object",
+            "required": [
+                "decision"
+            ],
+            "properties": {
+                "comment": {
+                    "description": "Комментарий (причина отказа)",
+                    "type": "string"
+                },
+                "decision": {
+                    "description": "verify - верифицировать, reject - отклонить",
                     "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
+                    "enum": [
+                        "verify",
+                        "reject"
+                    ]
                 }
             }
         },
-        "gw-currency-wallet_internal_models.DepositRequest": {
+        "models.ResolveReviewRequest": {
             "type": "object",
             "required": [
-                "amount",
-                "currency"
+                "decision"
             ],
             "properties": {
-                "amount": {
-                    "description": "Обязательное: да\nМинимальное значение: 0.01\nПример: 100.00",
-                    "type": "number"
+                "comment": {
+                    "description": "Комментарий администратора",
+                    "type": "string"
                 },
-                "currency": {
-                    "description": "Обязательное: да\nДопустимые значения: USD,RUB,EUR\nПример: USD",
+                "decision": {
+                    "description": "approve - выполнить, reject - отклонить",
                     "type": "string",
                     "enum": [
-                        "USD",
-                        "RUB",
-                        "EUR"
+                        "approve",
+                        "reject"
                     ]
                 }
             }
         },
-        "gw-currency-wallet_internal_models.ErrorResponse": {
+        "models.RiskReview": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Пример: \"Произошла ошибка\"",
+                "amount": {
+                    "description": "Сумма",
+                    "type": "number"
+                },
+                "comment": {
+                    "description": "Комментарий администратора или причина ошибки выполнения",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Время постановки в очередь",
                     "type": "string"
+                },
+                "currency": {
+                    "description": "Валюта",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Идентификатор проверки",
+                    "type": "integer"
+                },
+                "operation": {
+                    "description": "Тип операции (withdraw, transfer)",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Пояснение правила",
+                    "type": "string"
+                },
+                "recipient_id": {
+                    "description": "Получатель (только для переводов)",
+                    "type": "integer"
+                },
+                "resolved_at": {
+                    "description": "Время решения",
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "description": "Администратор, принявший решение",
+                    "type": "integer"
+                },
+                "rule": {
+                    "description": "Сработавшее правило",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Статус проверки",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Инициатор операции",
+                    "type": "integer"
                 }
             }
         },
-        "gw-currency-wallet_internal_models.ExchangeRatesResponse": {
+        "models.SaveNotificationRouteRequest": {
             "type": "object",
             "properties": {
-                "rates": {
-                    "description": "Пример: {\"USD\": 1.0, \"RUB\": 75.50, \"EUR\": 0.89}",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "number",
-                        "format": "float64"
+                "enabled": {
+                    "description": "Правило действует (по умолчанию true)",
+                    "type": "boolean"
+                },
+                "min_amount": {
+                    "description": "Наименьшая сумма записи (0 - любая)",
+                    "type": "number"
+                },
+                "target": {
+                    "description": "Адрес в канале (обязателен для webhook)",
+                    "type": "string"
+                },
+                "types": {
+                    "description": "Типы записей журнала (пусто - все)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
         },
-        "gw-currency-wallet_internal_models.ExchangeRequest": {
+        "models.SetPlanRequest": {
             "type": "object",
             "required": [
-                "amount",
-                "from_currency",
-                "to_currency"
+                "plan"
             ],
+            "properties": {
+                "plan": {
+                    "description": "Тарифный план (free, premium или план из QUOTA_LIMITS)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SuccessMessage": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Информационное сообщение",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "ID пользователя (если применимо)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TelegramLinkCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Код привязки",
+                    "type": "string"
+                },
+                "command": {
+                    "description": "Команда, которую нужно отправить боту",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Срок действия кода",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Transaction": {
+            "type": "object",
             "properties": {
                 "amount": {
-                    "description": "Обязательное: да\nМинимальное значение: 0.01\nПример: 100.00",
+                    "description": "Сумма со знаком: положительная - зачисление, отрицательная - списание",
                     "type": "number"
                 },
-                "from_currency": {
-                    "description": "Обязательное: да\nДопустимые значения: USD,RUB,EUR\nПример: USD",
-                    "type": "string",
-                    "enum": [
-                        "USD",
-                        "RUB",
-                        "EUR"
-                    ]
+                "amount_formatted": {
+                    "description": "Сумма, отформатированная по локали",
+                    "type": "string"
                 },
-                "to_currency": {
-                    "description": "Обязательное: да\nДопустимые значения: USD,RUB,EUR\nПример: EUR",
-                    "type": "string",
-                    "enum": [
-                        "USD",
-                        "RUB",
-                        "EUR"
-                    ]
+                "counterparty_id": {
+                    "description": "Вторая сторона перевода (только для переводов)",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Время операции",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Валюта",
+                    "type": "string"
+                },
+                "display_amount": {
+                    "description": "Сумма в валюте отображения по текущему курсу",
+                    "type": "number"
+                },
+                "id": {
+                    "description": "Идентификатор записи",
+                    "type": "integer"
+                },
+                "local_time": {
+                    "description": "Поля, заполняемые по настройкам пользователя (только в истории операций)",
+                    "type": "string"
+                },
+                "operation_id": {
+                    "description": "Идентификатор операции (общий для всех ее записей)",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Курс обмена (только для обмена)",
+                    "type": "number"
+                },
+                "type": {
+                    "description": "Тип записи (deposit, withdraw, transfer_in, ...)",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Владелец кошелька",
+                    "type": "integer"
                 }
             }
         },
-        "gw-currency-wallet_internal_models.ExchangeResponse": {
+        "models.TransactionHistoryResponse": {
             "type": "object",
             "properties": {
-                "exchanged_amount": {
-                    "description": "Пример: 89.00",
-                    "type": "number"
+                "display_currency": {
+                    "description": "Валюта отображения из настроек пользователя",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "Начало периода (включительно)",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Часовой пояс, в котором указано local_time",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Конец периода (не включительно)",
+                    "type": "string"
+                },
+                "transactions": {
+                    "description": "Записи журнала, от новых к старым",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                }
+            }
+        },
+        "models.TransactionResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Пробный режим: операция проверена, но не выполнена",
+                    "type": "boolean"
                 },
                 "message": {
-                    "description": "Пример: \"Обмен выполнен успешно\"",
+                    "description": "Сообщение о результате",
                     "type": "string"
                 },
                 "new_balance": {
-                    "description": "Новый баланс после обмена",
+                    "description": "Обновленный баланс (в пробном режиме - баланс после операции)",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.Balance"
+                            "$ref": "#/definitions/models.Balance"
                         }
                     ]
-                },
-                "rate": {
-                    "description": "Пример: 0.89",
-                    "type": "number"
                 }
             }
         },
-        "gw-currency-wallet_internal_models.LoginRequest": {
+        "models.TransferRequest": {
             "type": "object",
             "required": [
-                "password",
-                "username"
+                "amount",
+                "currency",
+                "to_username"
             ],
             "properties": {
-                "password": {
-                    "description": "Обязательное: да\nПример: securePass123",
+                "amount": {
+                    "description": "Сумма перевода (\u003e0)",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Валюта перевода",
+                    "type": "string",
+                    "enum": [
+                        "USD",
+                        "RUB",
+                        "EUR"
+                    ]
+                },
+                "to_username": {
+                    "description": "Имя пользователя получателя",
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdatePreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "default_currency": {
+                    "description": "Валюта отображения (USD, RUB, EUR)",
                     "type": "string"
                 },
-                "username": {
-                    "description": "Обязательное: да\nПример: ivan_ivanov",
+                "locale": {
+                    "description": "Локаль (ru, en)",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Часовой пояс IANA",
                     "type": "string"
                 }
             }
         },
-        "gw-currency-wallet_internal_models.LoginResponse": {
+        "models.User": {
             "type": "object",
             "properties": {
-                "token": {
-                    "description": "Пример: \"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9...\"",
+                "created_at": {
+                    "description": "Дата создания записи",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Email пользователя (уникальный у арендатора)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Уникальный идентификатор пользователя",
+                    "type": "integer"
+                },
+                "kyc_comment": {
+                    "description": "Комментарий администратора по верификации",
+                    "type": "string"
+                },
+                "kyc_status": {
+                    "description": "Статус верификации (none, pending, verified, rejected)",
+                    "type": "string"
+                },
+                "plan": {
+                    "description": "Тарифный план (free, premium): квота запросов",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Роль пользователя (user, admin)",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "description": "Арендатор (брендированный продукт), которому принадлежит пользователь",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Дата последнего обновления",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Логин пользователя (уникальный у арендатора)",
                     "type": "string"
                 }
             }
         },
-        "gw-currency-wallet_internal_models.SuccessMessage": {
+        "models.UserBalance": {
             "type": "object",
             "properties": {
-                "message": {
-                    "description": "Пример: \"Операция выполнена успешно\"",
+                "EUR": {
+                    "description": "Сумма в евро",
+                    "type": "number"
+                },
+                "RUB": {
+                    "description": "Сумма в рублях",
+                    "type": "number"
+                },
+                "USD": {
+                    "description": "Сумма в долларах",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "description": "Время последнего изменения кошелька",
                     "type": "string"
                 },
                 "user_id": {
-                    "description": "Пример: 42",
+                    "description": "ID пользователя",
                     "type": "integer"
+                },
+                "username": {
+                    "description": "Имя пользователя",
+                    "type": "string"
                 }
             }
         },
-        "gw-currency-wallet_internal_models.TransactionResponse": {
+        "models.UserPreferences": {
             "type": "object",
             "properties": {
-                "message": {
-                    "description": "Пример: \"Операция выполнена успешно\"",
+                "default_currency": {
+                    "description": "Валюта отображения итогов (USD, RUB, EUR)",
                     "type": "string"
                 },
-                "new_balance": {
-                    "description": "Новый баланс после операции",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/gw-currency-wallet_internal_models.Balance"
-                        }
-                    ]
+                "locale": {
+                    "description": "Локаль форматирования (ru, en)",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Часовой пояс IANA (например, Europe/Moscow)",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Время последнего изменения (пусто - настройки по умолчанию)",
+                    "type": "string"
                 }
             }
         },
-        "gw-currency-wallet_internal_models.WithdrawRequest": {
+        "models.WithdrawRequest": {
             "type": "object",
             "required": [
                 "amount",
@@ -563,11 +6412,11 @@ const docTemplate = `{
             ],
             "properties": {
                 "amount": {
-                    "description": "Обязательное: да\nМинимальное значение: 0.01\nПример: 50.00",
+                    "description": "Сумма снятия (\u003e0)",
                     "type": "number"
                 },
                 "currency": {
-                    "description": "Обязательное: да\nДопустимые значения: USD,RUB,EUR\nПример: USD",
+                    "description": "Валюта (USD/RUB/EUR)",
                     "type": "string",
                     "enum": [
                         "USD",
@@ -585,12 +6434,34 @@ const docTemplate = `{
             "name": "Authorization",
             "in": "header"
         }
-    }
+    },
+    "tags": [
+        {
+            "description": "Операции аутентификации",
+            "name": "Auth"
+        },
+        {
+            "description": "Управление кошельком",
+            "name": "Wallet"
+        },
+        {
+            "description": "Операции обмена валют",
+            "name": "Exchange"
+        },
+        {
+            "description": "Верификация пользователей",
+            "name": "KYC"
+        },
+        {
+            "description": "Администрирование (требуется роль admin)",
+            "name": "Admin"
+        }
+    ]
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "",
+	Version:          "1.0",
 	Host:             "localhost:8080",
 	BasePath:         "/api/v1",
 	Schemes:          []string{},
@@ -0,0 +1,7 @@
+package docs
+
+// Документация генерируется из аннотаций обработчиков (swag закреплен в go.mod директивой tool):
+//
+//	go generate ./docs
+//
+//go:generate go tool swag init --dir .. --generalInfo cmd/main.go --output . --packageName docs --parseInternal
@@ -0,0 +1,105 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Поддерживаемые провайдеры CAPTCHA
+const (
+	ProviderHCaptcha  = "hcaptcha"  // hCaptcha
+	ProviderReCaptcha = "recaptcha" // Google reCAPTCHA (v2 и v3)
+)
+
+// Адреса проверки ответа по умолчанию
+var defaultVerifyURLs = map[string]string{
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderReCaptcha: "https://www.google.com/recaptcha/api/siteverify",
+}
+
+// verifyTimeout - максимальное время запроса проверки к провайдеру
+const verifyTimeout = 5 * time.Second
+
+// Verifier проверяет ответ пользователя на CAPTCHA на стороне сервера
+// У hCaptcha и reCAPTCHA одинаковый протокол siteverify: POST формы secret/response/remoteip
+// и JSON ответ с полем success
+type Verifier struct {
+	verifyURL string       // Адрес проверки ответа
+	secret    string       // Секретный ключ сайта
+	client    *http.Client // HTTP клиент с таймаутом
+}
+
+// verifyResponse - ответ siteverify
+type verifyResponse struct {
+	Success    bool     `json:"success"`     // Ответ пользователя верный
+	ErrorCodes []string `json:"error-codes"` // Причины отказа
+}
+
+// New создает проверку ответов для провайдера
+// Параметры:
+//   - provider: hcaptcha или recaptcha
+//   - secret: секретный ключ сайта
+//   - verifyURL: адрес проверки (пусто - адрес провайдера по умолчанию)
+//
+// Возвращает:
+//   - *Verifier: проверка ответов
+//   - error: неизвестный провайдер или пустой секрет
+func New(provider, secret, verifyURL string) (*Verifier, error) {
+	defaultURL, ok := defaultVerifyURLs[provider]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный провайдер CAPTCHA: %q", provider)
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("не задан секретный ключ CAPTCHA")
+	}
+	if verifyURL == "" {
+		verifyURL = defaultURL
+	}
+	return &Verifier{
+		verifyURL: verifyURL,
+		secret:    secret,
+		client:    &http.Client{Timeout: verifyTimeout},
+	}, nil
+}
+
+// Verify проверяет ответ пользователя у провайдера
+// Параметры:
+//   - ctx: контекст выполнения
+//   - token: ответ пользователя, полученный виджетом CAPTCHA
+//   - remoteIP: IP адрес пользователя (передается провайдеру для дополнительной проверки)
+//
+// Возвращает:
+//   - bool: true если ответ верный
+//   - error: ошибка обращения к провайдеру
+func (v *Verifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, fmt.Errorf("ошибка создания запроса проверки CAPTCHA: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("ошибка запроса проверки CAPTCHA: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("провайдер CAPTCHA вернул статус %d", resp.StatusCode)
+	}
+	var result verifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("ошибка разбора ответа проверки CAPTCHA: %w", err)
+	}
+	return result.Success, nil
+}
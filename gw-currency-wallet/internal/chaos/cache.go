@@ -0,0 +1,66 @@
+package chaos
+
+import (
+	"context"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// Cache оборачивает кэш: сбои внедряются во все операции, кроме Close
+func Cache(inner storage.Cache, inj *Injector) storage.Cache {
+	if inj == nil {
+		return inner
+	}
+	return &cache{inner: inner, inj: inj}
+}
+
+// cache - кэш с внедрением сбоев
+type cache struct {
+	inner storage.Cache // Исходный кэш
+	inj   *Injector     // Источник сбоев
+}
+
+// Get возвращает значение по ключу после возможного внедрения сбоя
+func (c *cache) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := c.inj.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.Get(ctx, key)
+}
+
+// Set сохраняет значение после возможного внедрения сбоя
+func (c *cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.inj.Inject(ctx); err != nil {
+		return err
+	}
+	return c.inner.Set(ctx, key, value, ttl)
+}
+
+// Delete удаляет ключи после возможного внедрения сбоя
+func (c *cache) Delete(ctx context.Context, keys ...string) error {
+	if err := c.inj.Inject(ctx); err != nil {
+		return err
+	}
+	return c.inner.Delete(ctx, keys...)
+}
+
+// DeletePrefix удаляет ключи с префиксом после возможного внедрения сбоя
+func (c *cache) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	if err := c.inj.Inject(ctx); err != nil {
+		return 0, err
+	}
+	return c.inner.DeletePrefix(ctx, prefix)
+}
+
+// Incr увеличивает счетчик после возможного внедрения сбоя
+func (c *cache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	if err := c.inj.Inject(ctx); err != nil {
+		return 0, err
+	}
+	return c.inner.Incr(ctx, key, ttl)
+}
+
+// Close закрывает исходный кэш
+func (c *cache) Close() error {
+	return c.inner.Close()
+}
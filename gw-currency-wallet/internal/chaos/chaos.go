@@ -0,0 +1,89 @@
+// Package chaos внедряет задержки и ошибки в обращения к зависимостям (PostgreSQL, Redis, gRPC)
+// Используется только для проверки устойчивости сервиса в тестовых окружениях и включается явно
+// в конфигурации (CHAOS_ENABLED), в production режим должен быть выключен
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/metrics"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrInjected - искусственная ошибка, внедренная режимом chaos
+var ErrInjected = errors.New("chaos: внедренная ошибка")
+
+// Fault описывает сбои, внедряемые в обращения к одной зависимости
+type Fault struct {
+	LatencyPercent float64       // Доля вызовов (в процентах), выполняемых с задержкой
+	Latency        time.Duration // Величина задержки
+	ErrorPercent   float64       // Доля вызовов (в процентах), завершающихся ошибкой
+}
+
+// active сообщает, внедряет ли описание хотя бы один вид сбоев
+func (f Fault) active() bool {
+	return f.ErrorPercent > 0 || (f.LatencyPercent > 0 && f.Latency > 0)
+}
+
+// Injector решает для каждого вызова, внедрять ли сбой
+// Нулевой указатель допустим и означает отсутствие сбоев
+type Injector struct {
+	target string // Имя зависимости (db, redis, grpc) для ошибок и метрик
+	fault  Fault  // Параметры сбоев
+}
+
+// NewInjector создает источник сбоев для зависимости
+// Параметры:
+//   - target: имя зависимости (db, redis, grpc)
+//   - fault: доли вызовов с задержкой и ошибкой
+//
+// Возвращает:
+//   - *Injector: источник сбоев или nil, если сбои не заданы
+func NewInjector(target string, fault Fault) *Injector {
+	if !fault.active() {
+		return nil
+	}
+	return &Injector{target: target, fault: fault}
+}
+
+// Inject вызывается перед обращением к зависимости
+// С заданной вероятностью выдерживает задержку (с учетом отмены контекста)
+// и с заданной вероятностью возвращает ErrInjected
+func (i *Injector) Inject(ctx context.Context) error {
+	if i == nil {
+		return nil
+	}
+
+	if roll(i.fault.LatencyPercent) && i.fault.Latency > 0 {
+		metrics.ChaosFaults.WithLabelValues(i.target, "latency").Inc()
+		timer := time.NewTimer(i.fault.Latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	if roll(i.fault.ErrorPercent) {
+		metrics.ChaosFaults.WithLabelValues(i.target, "error").Inc()
+		return fmt.Errorf("%w (%s)", ErrInjected, i.target)
+	}
+	return nil
+}
+
+// String возвращает описание сбоев для журнала при запуске
+func (i *Injector) String() string {
+	if i == nil {
+		return "без сбоев"
+	}
+	return fmt.Sprintf("%s: задержка %s в %.1f%% вызовов, ошибки в %.1f%% вызовов",
+		i.target, i.fault.Latency, i.fault.LatencyPercent, i.fault.ErrorPercent)
+}
+
+// roll возвращает true с вероятностью percent процентов
+func roll(percent float64) bool {
+	return percent > 0 && rand.Float64()*100 < percent
+}
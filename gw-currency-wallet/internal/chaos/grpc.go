@@ -0,0 +1,22 @@
+package chaos
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor внедряет сбои в исходящие gRPC вызовы
+// Ошибка возвращается с кодом Unavailable, как при недоступности сервера
+func UnaryClientInterceptor(inj *Injector) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := inj.Inject(ctx); err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(err).Err()
+			}
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,112 @@
+package chaos
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// Connector оборачивает коннектор драйвера БД: сбои внедряются в запросы,
+// подготовку выражений и начало транзакций на всех соединениях пула
+func Connector(inner driver.Connector, inj *Injector) driver.Connector {
+	if inj == nil {
+		return inner
+	}
+	return &connector{inner: inner, inj: inj}
+}
+
+// connector создает соединения с внедрением сбоев
+type connector struct {
+	inner driver.Connector // Исходный коннектор драйвера
+	inj   *Injector        // Источник сбоев
+}
+
+// Connect открывает соединение через исходный коннектор
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cn, err := c.inner.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: cn, inj: c.inj}, nil
+}
+
+// Driver возвращает исходный драйвер
+func (c *connector) Driver() driver.Driver {
+	return c.inner.Driver()
+}
+
+// conn - соединение драйвера с внедрением сбоев
+// Контекстные методы делегируются исходному соединению, если оно их поддерживает
+type conn struct {
+	driver.Conn           // Исходное соединение
+	inj         *Injector // Источник сбоев
+}
+
+// QueryContext выполняет запрос после возможного внедрения сбоя
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.inj.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+// ExecContext выполняет команду после возможного внедрения сбоя
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.inj.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+// PrepareContext подготавливает выражение после возможного внедрения сбоя
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.inj.Inject(ctx); err != nil {
+		return nil, err
+	}
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+// BeginTx начинает транзакцию после возможного внедрения сбоя
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.inj.Inject(ctx); err != nil {
+		return nil, err
+	}
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() // Запасной путь для драйверов без BeginTx
+}
+
+// Ping проверяет соединение (без внедрения сбоев, чтобы пул не отбрасывал рабочие соединения)
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession сбрасывает состояние соединения перед повторным использованием
+func (c *conn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid сообщает пулу, можно ли повторно использовать соединение
+func (c *conn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
@@ -1,74 +1,593 @@
 package config
 
 import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/joho/godotenv" // Пакет для загрузки .env файлов
+	"gopkg.in/yaml.v3"         // Разбор YAML конфигурации
+	"gw-currency-wallet/internal/captcha"
+	"gw-currency-wallet/internal/fees"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/tenant"
+	"gw-currency-wallet/internal/tradinghours"
+	"math"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// Режимы работы TLS для HTTP сервера
+const (
+	TLSModeNone     = "none"     // TLS отключен, сервер работает по HTTP
+	TLSModeFile     = "file"     // Сертификат и ключ загружаются из файлов
+	TLSModeAutocert = "autocert" // Сертификаты выпускаются автоматически через Let's Encrypt
+)
+
+// defaultJWTSecret - значение секрета из примеров конфигурации, запуск с ним запрещен
+const defaultJWTSecret = "default-secret"
+
 // Config структура содержит все конфигурационные параметры приложения
+//
+// Каждое поле описывается тегами:
+//   - env: имя переменной окружения (оно же ключ в .env файле)
+//   - default: значение по умолчанию
+//   - secret: значение скрывается в действующей конфигурации (GET /admin/config)
+//
+// Ключ в YAML файле - имя переменной в нижнем регистре (server_address),
+// флаг командной строки - имя в нижнем регистре через дефис (-server-address).
 type Config struct {
-	ServerAddress       string        // Адрес и порт HTTP сервера (например: ":8080")
-	JWTSecret           string        // Секретный ключ для генерации JWT токенов
-	DBHost              string        // Хост PostgreSQL сервера
-	DBPort              string        // Порт PostgreSQL сервера
-	DBUser              string        // Имя пользователя PostgreSQL
-	DBPassword          string        // Пароль пользователя PostgreSQL
-	DBName              string        // Имя базы данных
-	DBSSLMode           string        // Режим SSL для подключения к БД (disable/require/verify-full)
-	ExchangeServiceAddr string        // Адрес gRPC сервиса обмена валют
-	TokenExpiration     time.Duration // Время жизни JWT токена (например: "24h")
-	CacheTTL            time.Duration // Время жизни кэша в Redis (например: "5m")
-	TelegramToken       string        // Токен Telegram бота (если пустой - бот не запускается)
-	RedisAddr           string        // Адрес Redis сервера (host:port)
-	RedisPassword       string        // Пароль Redis (если требуется)
-	RedisDB             int           // Номер базы данных Redis
-}
-
-// LoadConfig загружает конфигурацию из .env файла и возвращает структуру Config
-// Принимает имя файла конфигурации (например "config2.env")
-// Возвращает указатель на Config или ошибку, если загрузка не удалась
-func LoadConfig(filename string) (*Config, error) {
-	// Загружаем переменные окружения из указанного файла
-	err := godotenv.Load("config2.env")
-	if err != nil {
-		return nil, err // Возвращаем ошибку, если файл не найден или нечитаем
+	ServerAddress        string        `env:"SERVER_ADDRESS" default:":8080"`                  // Адрес и порт HTTP сервера (например: ":8080")
+	JWTSecret            string        `env:"JWT_SECRET" secret:"true"`                        // Секретный ключ для генерации JWT токенов
+	AdminUsernames       []string      `env:"ADMIN_USERNAMES"`                                 // Пользователи с ролью администратора (через запятую)
+	AdminAllowedNetworks []string      `env:"ADMIN_ALLOWED_NETWORKS"`                          // Адреса и подсети, из которых доступны /admin маршруты (пусто - любые)
+	DBHost               string        `env:"DB_HOST" default:"localhost"`                     // Хост PostgreSQL сервера
+	DBPort               string        `env:"DB_PORT" default:"5432"`                          // Порт PostgreSQL сервера
+	DBUser               string        `env:"DB_USER" default:"postgres"`                      // Имя пользователя PostgreSQL
+	DBPassword           string        `env:"DB_PASSWORD" secret:"true"`                       // Пароль пользователя PostgreSQL
+	DBName               string        `env:"DB_NAME" default:"wallet_db"`                     // Имя базы данных
+	DBSSLMode            string        `env:"DB_SSLMODE" default:"disable"`                    // Режим SSL для подключения к БД (disable/require/verify-full)
+	DBQueryTimeout       time.Duration `env:"DB_QUERY_TIMEOUT" default:"5s"`                   // Максимальное время выполнения запроса к БД
+	DBMigrationTimeout   time.Duration `env:"DB_MIGRATION_TIMEOUT" default:"1m"`               // Максимальное время применения миграций
+	DBMaxOpenConns       int           `env:"DB_MAX_OPEN_CONNS" default:"25"`                  // Максимум открытых соединений с БД (0 - без ограничений)
+	DBMaxIdleConns       int           `env:"DB_MAX_IDLE_CONNS" default:"25"`                  // Максимум простаивающих соединений в пуле
+	DBConnMaxLifetime    time.Duration `env:"DB_CONN_MAX_LIFETIME" default:"5m"`               // Максимальное время жизни соединения (0 - без ограничений)
+	DBTxIsolation        string        `env:"DB_TX_ISOLATION" default:"read_committed"`        // Уровень изоляции транзакций с балансом (read_committed/repeatable_read/serializable)
+	DBTxMaxAttempts      int           `env:"DB_TX_MAX_ATTEMPTS" default:"3"`                  // Попыток транзакции с балансом при конфликте сериализации или взаимной блокировке
+	ExchangeServiceAddr  string        `env:"EXCHANGE_SERVICE_ADDR" default:"localhost:50051"` // Адрес gRPC сервиса обмена валют (несколько - через запятую)
+	ExchangeAPIToken     string        `env:"EXCHANGE_API_TOKEN" secret:"true"`                // API токен кошелька для сервиса обмена валют
+	ExchangeBotAPIToken  string        `env:"EXCHANGE_BOT_API_TOKEN" secret:"true"`            // API токен Telegram бота для сервиса обмена валют
+	TokenExpiration      time.Duration `env:"TOKEN_EXPIRATION" default:"24h"`                  // Время жизни JWT токена (например: "24h")
+	JWTLeeway            time.Duration `env:"JWT_LEEWAY" default:"30s"`                        // Допустимое расхождение часов при проверке срока действия JWT
+
+	RefreshTokenIdleTTL       time.Duration `env:"REFRESH_TOKEN_IDLE_TTL" default:"168h"`       // Скользящий срок токена обновления, продлевается при использовании (0 - без продления)
+	RefreshSessionMaxLifetime time.Duration `env:"REFRESH_SESSION_MAX_LIFETIME" default:"720h"` // Абсолютный срок жизни сессии от входа по паролю (0 - без ограничения)
+	CacheTTL                  time.Duration `env:"CACHE_TTL" default:"5m"`                      // Время жизни кэша в Redis (например: "5m")
+	TelegramToken             string        `env:"TELEGRAM_TOKEN" secret:"true"`                // Токен Telegram бота (если пустой - бот не запускается)
+	TelegramCommandLimit      int           `env:"TELEGRAM_COMMAND_LIMIT" default:"20"`         // Максимум команд бота в одном чате за TELEGRAM_COMMAND_WINDOW (0 - без ограничений)
+	TelegramCommandWindow     time.Duration `env:"TELEGRAM_COMMAND_WINDOW" default:"1m"`        // Окно ограничения частоты команд бота
+	TelegramAdminChatID       int64         `env:"TELEGRAM_ADMIN_CHAT_ID"`                      // Служебный чат дежурных для оповещений бота (0 - оповещения отключены)
+	OpsCheckInterval          time.Duration `env:"OPS_CHECK_INTERVAL" default:"1m"`             // Интервал проверки получения курсов для оповещений дежурных (0 - не проверять)
+	OpsFailureThreshold       int           `env:"OPS_FAILURE_THRESHOLD" default:"3"`           // Неудачных проверок курсов подряд до оповещения дежурных
+	TelegramLinkCodeTTL       time.Duration `env:"TELEGRAM_LINK_CODE_TTL" default:"10m"`        // Время действия кода привязки аккаунта Telegram
+	ExchangeQuoteTTL          time.Duration `env:"EXCHANGE_QUOTE_TTL" default:"30s"`            // Срок действия котировки обмена из бота
+	TelegramDebug             bool          `env:"TELEGRAM_DEBUG" default:"false"`              // Журнал всех запросов и обновлений бота (содержит данные пользователей, только для отладки)
+	TelegramChartTTL          time.Duration `env:"TELEGRAM_CHART_TTL" default:"5m"`             // Время хранения изображений графиков бота в кэше
+	AlertLimit                int           `env:"ALERT_LIMIT" default:"10"`                    // Максимум уведомлений о курсах в одном чате бота
+	AlertCheckInterval        time.Duration `env:"ALERT_CHECK_INTERVAL" default:"1m"`           // Интервал проверки уведомлений о курсах (0 - не проверять)
+	TelegramDigestInterval    time.Duration `env:"TELEGRAM_DIGEST_INTERVAL" default:"5m"`       // Интервал проверки ежедневных сводок курсов в группах (0 - не отправлять)
+	RedisAddr                 string        `env:"REDIS_ADDR" default:"localhost:6379"`         // Адрес Redis сервера (host:port), пустой - in-memory кэш
+	RedisPassword             string        `env:"REDIS_PASSWORD" secret:"true"`                // Пароль Redis (если требуется)
+	RedisDB                   int           `env:"REDIS_DB" default:"0"`                        // Номер базы данных Redis
+	RedisHealthInterval       time.Duration `env:"REDIS_HEALTH_INTERVAL" default:"5s"`          // Интервал проверки подключения к Redis (восстановление кэширования после сбоя)
+	CacheMaxEntries           int           `env:"CACHE_MAX_ENTRIES" default:"10000"`           // Максимум записей in-memory кэша (если REDIS_ADDR пустой)
+	BalanceCacheEnabled       bool          `env:"BALANCE_CACHE_ENABLED" default:"false"`       // Чтение балансов кошельков через кэш Redis (требует REDIS_ADDR)
+	BalanceCacheTTL           time.Duration `env:"BALANCE_CACHE_TTL" default:"30s"`             // Время жизни баланса в кэше (предел устаревания при сбое сброса)
+
+	LedgerRetentionMonths     int           `env:"LEDGER_RETENTION_MONTHS" default:"12"`      // Сколько месяцев операции хранятся в журнале до архивации (0 - без архивации)
+	LedgerPartitionsAhead     int           `env:"LEDGER_PARTITIONS_AHEAD" default:"3"`       // На сколько месяцев вперед создаются секции журнала
+	LedgerMaintenanceInterval time.Duration `env:"LEDGER_MAINTENANCE_INTERVAL" default:"24h"` // Интервал задачи обслуживания журнала (секции и архивация)
+
+	ReconciliationInterval   time.Duration `env:"RECONCILIATION_INTERVAL" default:"1h"`      // Интервал плановой сверки балансов с журналом (0 - только вручную)
+	ReconciliationQuarantine bool          `env:"RECONCILIATION_QUARANTINE" default:"false"` // Блокировать кошельки с расхождениями
+	MetricsAddr              string        `env:"METRICS_ADDR" default:":9101"`              // Адрес HTTP сервера метрик Prometheus (пустой - метрики не публикуются)
+
+	OTelEndpoint        string        `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`                    // Адрес OpenTelemetry Collector (OTLP/gRPC, например http://otel-collector:4317), пустой - трассировки и метрики OpenTelemetry не экспортируются
+	OTelServiceName     string        `env:"OTEL_SERVICE_NAME" default:"gw-currency-wallet"` // Имя сервиса в трассировках и метриках OpenTelemetry
+	OTelSampleRatio     float64       `env:"OTEL_TRACES_SAMPLE_RATIO" default:"1"`           // Доля трассируемых запросов без входящей трассировки (0-1)
+	OTelMetricsInterval time.Duration `env:"OTEL_METRICS_INTERVAL" default:"15s"`            // Период экспорта метрик OpenTelemetry
+
+	OperationRecoveryInterval time.Duration `env:"OPERATION_RECOVERY_INTERVAL" default:"1m"` // Интервал компенсации незавершенных многошаговых операций (0 - не выполнять)
+	OperationStaleAfter       time.Duration `env:"OPERATION_STALE_AFTER" default:"5m"`       // Операция без изменений дольше считается прерванной сбоем
+	OperationMaxAttempts      int           `env:"OPERATION_MAX_ATTEMPTS" default:"5"`       // Попыток компенсации до перевода операции в failed с оповещением дежурных
+
+	CurrencyFreezeRefresh time.Duration `env:"CURRENCY_FREEZE_REFRESH" default:"10s"` // Период перечитывания приостановок валют экземпляром (задержка вступления в силу на других экземплярах)
+
+	ExchangeKeepaliveTime          time.Duration `env:"EXCHANGE_KEEPALIVE_TIME" default:"5m"`                     // Период ping соединения с сервисом обмена (0 - без ping)
+	ExchangeKeepaliveTimeout       time.Duration `env:"EXCHANGE_KEEPALIVE_TIMEOUT" default:"20s"`                 // Ожидание ответа на ping до переподключения
+	ExchangeKeepaliveWithoutStream bool          `env:"EXCHANGE_KEEPALIVE_PERMIT_WITHOUT_STREAM" default:"false"` // Отправлять ping без активных вызовов
+	ExchangeWaitForReady           bool          `env:"EXCHANGE_WAIT_FOR_READY" default:"true"`                   // Ожидать подключения к сервису обмена в пределах таймаута вызова
+	ExchangeRateWatch              bool          `env:"EXCHANGE_RATE_WATCH" default:"true"`                       // Сбрасывать кэш курсов по событиям сервиса обмена об их изменении
+	ExchangeHealthInterval         time.Duration `env:"EXCHANGE_HEALTH_INTERVAL" default:"30s"`                   // Интервал проверки связи с сервисом обмена (0 - только при запуске)
+	ExchangeHealthTimeout          time.Duration `env:"EXCHANGE_HEALTH_TIMEOUT" default:"5s"`                     // Максимальное время проверки связи с сервисом обмена
+
+	BruteForceLimit       int           `env:"BRUTEFORCE_LIMIT" default:"20"`        // Максимум попыток входа и регистрации с IP адреса за окно (0 - без ограничения)
+	BruteForceWindow      time.Duration `env:"BRUTEFORCE_WINDOW" default:"15m"`      // Скользящее окно подсчета попыток
+	BruteForceBanDuration time.Duration `env:"BRUTEFORCE_BAN_DURATION" default:"1h"` // Длительность блокировки адреса после превышения лимита
+	TrustedProxies        []string      `env:"TRUSTED_PROXIES"`                      // Прокси (адреса и подсети через запятую), которым доверяется X-Forwarded-For
+
+	QuotaLimits map[string]float64 `env:"QUOTA_LIMITS"`              // Запросов за окно по тарифным планам (например, "free:1000,premium:100000"; пусто - без квот)
+	QuotaWindow time.Duration      `env:"QUOTA_WINDOW" default:"1h"` // Окно квоты запросов
+
+	CaptchaProvider           string        `env:"CAPTCHA_PROVIDER"`                         // Провайдер CAPTCHA: hcaptcha, recaptcha (пусто - CAPTCHA выключена)
+	CaptchaSecret             string        `env:"CAPTCHA_SECRET" secret:"true"`             // Секретный ключ сайта у провайдера CAPTCHA
+	CaptchaVerifyURL          string        `env:"CAPTCHA_VERIFY_URL"`                       // Адрес проверки ответа (пусто - адрес провайдера)
+	CaptchaLoginAfterFailures int           `env:"CAPTCHA_LOGIN_AFTER_FAILURES" default:"3"` // Неудачных входов с адреса, после которых вход требует CAPTCHA (0 - всегда)
+	CaptchaFailureWindow      time.Duration `env:"CAPTCHA_FAILURE_WINDOW" default:"1h"`      // Время хранения счетчика неудачных входов
+
+	GeoIPDBPath           string        `env:"GEOIP_DB_PATH"`                        // База MaxMind GeoLite2-City/GeoIP2-City (пусто - местоположение не определяется)
+	SMTPAddr              string        `env:"SMTP_ADDR"`                            // SMTP сервер для уведомлений host:port (пусто - уведомления пишутся в журнал)
+	SMTPFrom              string        `env:"SMTP_FROM"`                            // Адрес отправителя уведомлений
+	SMTPUsername          string        `env:"SMTP_USERNAME"`                        // Имя пользователя SMTP (пусто - без аутентификации)
+	SMTPPassword          string        `env:"SMTP_PASSWORD" secret:"true"`          // Пароль SMTP
+	DeviceConfirmation    bool          `env:"DEVICE_CONFIRMATION" default:"false"`  // Вход с нового устройства требует кода из письма
+	DeviceCodeTTL         time.Duration `env:"DEVICE_CODE_TTL" default:"10m"`        // Время действия кода подтверждения
+	DeviceMaxCodeAttempts int           `env:"DEVICE_MAX_CODE_ATTEMPTS" default:"5"` // Попыток ввода кода до аннулирования подтверждения
+
+	NotifyChannels            []string      `env:"NOTIFY_CHANNELS"`                              // Подключенные каналы уведомлений об операциях: email, telegram, webhook, push (пусто - уведомления выключены)
+	NotifyDefaultChannels     []string      `env:"NOTIFY_DEFAULT_CHANNELS"`                      // Каналы пользователей без правил доставки (пусто - все подключенные, кроме webhook)
+	NotifyDispatchInterval    time.Duration `env:"NOTIFY_DISPATCH_INTERVAL" default:"10s"`       // Интервал отправки уведомлений из outbox
+	NotifyMaxAttempts         int           `env:"NOTIFY_MAX_ATTEMPTS" default:"8"`              // Попыток отправки уведомления до отметки неотправленным
+	NotifyRetryDelay          time.Duration `env:"NOTIFY_RETRY_DELAY" default:"30s"`             // Задержка первой повторной отправки (удваивается с каждой попыткой, не больше часа)
+	NotifyRetention           time.Duration `env:"NOTIFY_RETENTION" default:"720h"`              // Время хранения отправленных и неотправленных уведомлений
+	NotifyWebhookSecret       string        `env:"NOTIFY_WEBHOOK_SECRET" secret:"true"`          // Секрет подписи уведомлений webhook (обязателен для канала webhook)
+	NotifyWebhookTimeout      time.Duration `env:"NOTIFY_WEBHOOK_TIMEOUT" default:"10s"`         // Таймаут запроса webhook
+	NotifyWebhookAllowPrivate bool          `env:"NOTIFY_WEBHOOK_ALLOW_PRIVATE" default:"false"` // Разрешить webhook на адреса внутренней сети (только для разработки)
+
+	PushFCMCredentialsFile string        `env:"PUSH_FCM_CREDENTIALS_FILE"`         // Ключ сервисного аккаунта Google для FCM (пусто - FCM выключен)
+	PushAPNsKeyFile        string        `env:"PUSH_APNS_KEY_FILE"`                // Ключ аутентификации APNs .p8 (пусто - APNs выключен)
+	PushAPNsKeyID          string        `env:"PUSH_APNS_KEY_ID"`                  // Идентификатор ключа APNs
+	PushAPNsTeamID         string        `env:"PUSH_APNS_TEAM_ID"`                 // Идентификатор команды разработчика Apple
+	PushAPNsTopic          string        `env:"PUSH_APNS_TOPIC"`                   // Bundle ID приложения
+	PushAPNsSandbox        bool          `env:"PUSH_APNS_SANDBOX" default:"false"` // Среда разработки APNs
+	PushTimeout            time.Duration `env:"PUSH_TIMEOUT" default:"10s"`        // Таймаут запроса к FCM и APNs
+
+	PaymentWebhookSecret    string        `env:"PAYMENT_WEBHOOK_SECRET" secret:"true"`   // Секрет подписи webhook платежного провайдера (пусто - прием выключен)
+	PaymentWebhookTolerance time.Duration `env:"PAYMENT_WEBHOOK_TOLERANCE" default:"5m"` // Допустимое расхождение времени отправки webhook
+
+	ReceiptSecret  string `env:"RECEIPT_SECRET" secret:"true"` // Ключ подписи кодов проверки квитанций об операциях (пусто - квитанции выключены)
+	ReceiptBaseURL string `env:"RECEIPT_BASE_URL"`             // Публичный адрес сервиса для ссылки проверки в квитанции (https://wallet.example.com; пусто - без ссылки)
+
+	TaxReportCurrency   string `env:"TAX_REPORT_CURRENCY" default:"RUB"`    // Валюта налогового отчета о курсовых доходах
+	TaxAccountingMethod string `env:"TAX_ACCOUNTING_METHOD" default:"fifo"` // Метод учета стоимости приобретения валюты: fifo, average
+
+	RiskEnabled                bool               `env:"RISK_ENABLED" default:"true"`                                          // Проверка снятий и переводов правилами антифрода
+	RiskVelocityLimit          int                `env:"RISK_VELOCITY_LIMIT" default:"20"`                                     // Максимум снятий и переводов пользователя за окно (0 - без ограничения)
+	RiskVelocityWindow         time.Duration      `env:"RISK_VELOCITY_WINDOW" default:"1h"`                                    // Окно подсчета операций для правила частоты
+	RiskReviewThresholds       map[string]float64 `env:"RISK_REVIEW_THRESHOLDS" default:"USD:10000,EUR:10000,RUB:1000000"`     // Суммы, начиная с которых операция отправляется на проверку
+	RiskDenyThresholds         map[string]float64 `env:"RISK_DENY_THRESHOLDS"`                                                 // Суммы, начиная с которых операция отклоняется
+	RiskNewRecipientThresholds map[string]float64 `env:"RISK_NEW_RECIPIENT_THRESHOLDS" default:"USD:1000,EUR:1000,RUB:100000"` // Суммы первого перевода новому получателю, требующие проверки
+
+	KYCDocumentsDir     string             `env:"KYC_DOCUMENTS_DIR" default:"kyc_documents"`                    // Директория для хранения файлов документов верификации
+	KYCMaxDocumentSize  int                `env:"KYC_MAX_DOCUMENT_SIZE" default:"10485760"`                     // Максимальный размер файла документа в байтах
+	KYCUnverifiedLimits map[string]float64 `env:"KYC_UNVERIFIED_LIMITS" default:"USD:1000,EUR:1000,RUB:100000"` // Максимальная сумма снятия или перевода без верификации (пусто - без ограничений)
+
+	AMLThresholds              map[string]float64 `env:"AML_THRESHOLDS" default:"USD:10000,EUR:10000,RUB:1000000"` // Суммы операций, попадающих в отчет AML (пусто - отчет не ведется)
+	AdjustmentApprovalRequired bool               `env:"ADJUSTMENT_APPROVAL_REQUIRED" default:"false"`             // Корректировки баланса проводятся только после подтверждения вторым администратором
+
+	ExchangeFeePercent float64 `env:"EXCHANGE_FEE_PERCENT" default:"0.5"`                                                // Базовая комиссия обмена в процентах от полученной суммы
+	FeeTiers           string  `env:"FEE_TIERS" default:"standard:0:0,silver:10000:25,gold:50000:50,platinum:250000:75"` // Уровни скидок по объему обменов за 30 дней в USD (name:min_volume:discount)
+
+	ExchangeTradingHours  string        `env:"EXCHANGE_TRADING_HOURS"`                      // Окна исполнения обменов по парам и валютам (RUB=mon-fri 00:00-24:00;USD/EUR=mon-fri 08:00-20:00; пусто - круглосуточно)
+	ExchangeTradingTZ     string        `env:"EXCHANGE_TRADING_TZ" default:"Europe/Moscow"` // Часовой пояс окон исполнения обменов
+	ExchangeQueueInterval time.Duration `env:"EXCHANGE_QUEUE_INTERVAL" default:"1m"`        // Интервал исполнения обменов, отложенных до открытия окна (0 - не исполнять)
+	DCAInterval           time.Duration `env:"DCA_INTERVAL" default:"1m"`                   // Интервал выполнения обменов по планам регулярных обменов (0 - не выполнять)
+
+	TenantsFile string `env:"TENANTS_FILE"` // YAML файл арендаторов (брендов) с хостами, валютами и комиссией (пусто - только арендатор default)
+
+	APIV1Sunset string `env:"API_V1_SUNSET"` // Дата отключения /api/v1 в формате YYYY-MM-DD для заголовка Sunset (пусто - не объявлена)
+
+	HTTPTimeout       time.Duration            `env:"HTTP_TIMEOUT" default:"30s"`                                                      // Максимальное время обработки запроса (0 - без ограничения)
+	HTTPRouteTimeouts map[string]time.Duration `env:"HTTP_ROUTE_TIMEOUTS" default:"POST /kyc/documents:2m,GET /transactions/export:0"` // Таймауты отдельных маршрутов ("МЕТОД /путь:длительность", путь без /api/vN; 0 - без ограничения и буферизации ответа)
+
+	DrainDelay   time.Duration `env:"DRAIN_DELAY" default:"5s"`    // Пауза после снятия готовности (/readyz) до ожидания запросов: балансировщик исключает экземпляр
+	DrainTimeout time.Duration `env:"DRAIN_TIMEOUT" default:"30s"` // Максимальное ожидание выполняющихся запросов при выводе из балансировки
+
+	HTTPLogEnabled       bool    `env:"HTTP_LOG_ENABLED" default:"false"`    // Журнал HTTP запросов и ответов с маскированием персональных данных
+	HTTPLogSamplePercent float64 `env:"HTTP_LOG_SAMPLE_PERCENT" default:"1"` // Доля успешных запросов, попадающих в журнал, %
+	HTTPLogErrors        bool    `env:"HTTP_LOG_ERRORS" default:"true"`      // Всегда записывать запросы, завершившиеся ошибкой (4xx/5xx)
+	HTTPLogMaxBody       int     `env:"HTTP_LOG_MAX_BODY" default:"2048"`    // Максимальный размер тела запроса и ответа в журнале, байт
+	HTTPLogHeaders       bool    `env:"HTTP_LOG_HEADERS" default:"false"`    // Записывать заголовки запроса (секреты маскируются)
+
+	ChaosEnabled             bool          `env:"CHAOS_ENABLED" default:"false"`           // Внедрение сбоев в обращения к зависимостям (только для тестовых окружений)
+	ChaosLatency             time.Duration `env:"CHAOS_LATENCY" default:"500ms"`           // Величина внедряемой задержки
+	ChaosDBLatencyPercent    float64       `env:"CHAOS_DB_LATENCY_PERCENT" default:"0"`    // Доля запросов к PostgreSQL с задержкой, %
+	ChaosDBErrorPercent      float64       `env:"CHAOS_DB_ERROR_PERCENT" default:"0"`      // Доля запросов к PostgreSQL с ошибкой, %
+	ChaosRedisLatencyPercent float64       `env:"CHAOS_REDIS_LATENCY_PERCENT" default:"0"` // Доля обращений к кэшу с задержкой, %
+	ChaosRedisErrorPercent   float64       `env:"CHAOS_REDIS_ERROR_PERCENT" default:"0"`   // Доля обращений к кэшу с ошибкой, %
+	ChaosGRPCLatencyPercent  float64       `env:"CHAOS_GRPC_LATENCY_PERCENT" default:"0"`  // Доля вызовов сервиса обмена с задержкой, %
+	ChaosGRPCErrorPercent    float64       `env:"CHAOS_GRPC_ERROR_PERCENT" default:"0"`    // Доля вызовов сервиса обмена с ошибкой, %
+
+	TLSMode             string   `env:"TLS_MODE" default:"none"`                // Режим TLS: none, file или autocert
+	TLSCertFile         string   `env:"TLS_CERT_FILE"`                          // Путь к файлу сертификата (режим file)
+	TLSKeyFile          string   `env:"TLS_KEY_FILE"`                           // Путь к файлу приватного ключа (режим file)
+	TLSAutocertDomains  []string `env:"TLS_AUTOCERT_DOMAINS"`                   // Домены, для которых выпускаются сертификаты (режим autocert)
+	TLSAutocertEmail    string   `env:"TLS_AUTOCERT_EMAIL"`                     // Контактный email для Let's Encrypt
+	TLSAutocertCacheDir string   `env:"TLS_AUTOCERT_CACHE_DIR" default:"certs"` // Директория для хранения выпущенных сертификатов
+	TLSHTTPAddr         string   `env:"TLS_HTTP_ADDR" default:":80"`            // Адрес HTTP сервера для ACME challenge и редиректа на HTTPS
+}
+
+// LoadConfig загружает конфигурацию и возвращает структуру Config
+//
+// Источники применяются в порядке возрастания приоритета:
+//  1. значения по умолчанию из тегов default
+//  2. YAML файл (если файл конфигурации имеет расширение .yaml/.yml)
+//  3. .env файл (переменные из него не перекрывают уже заданные в окружении)
+//  4. переменные окружения
+//  5. флаги командной строки
+//
+// Параметры:
+//   - filename: файл конфигурации по умолчанию (например "config2.env").
+//     Может быть переопределен флагом -config или переменной CONFIG_FILE.
+//     Отсутствие файла по умолчанию не является ошибкой, явно указанного - является
+//   - args: аргументы командной строки без имени программы (os.Args[1:])
+//
+// Возвращает указатель на Config или ошибку, если загрузка или проверка не удалась
+func LoadConfig(filename string, args []string) (*Config, error) {
+	cfg := &Config{}
+
+	// 1. Значения по умолчанию
+	if err := cfg.apply(func(f reflect.StructField) (string, bool) {
+		value, ok := f.Tag.Lookup("default")
+		return value, ok
+	}); err != nil {
+		return nil, err
 	}
 
-	// Парсим продолжительность жизни токена из переменной окружения
-	// По умолчанию 24 часа, если переменная не задана или невалидна
-	tokenExp, err := time.ParseDuration(getEnv("TOKEN_EXPIRATION", "24h"))
+	// Разбираем флаги заранее, чтобы узнать путь к файлу конфигурации
+	fs, configFlag, err := parseFlags(args)
 	if err != nil {
 		return nil, err
 	}
 
-	// Парсим время жизни кэша из переменной окружения
-	// По умолчанию 5 минут, если переменная не задана или невалидна
-	cacheTTL, err := time.ParseDuration(getEnv("CACHE_TTL", "5m"))
-	if err != nil {
+	// Явно указанный файл имеет приоритет над файлом по умолчанию
+	explicit := true
+	switch {
+	case *configFlag != "":
+		filename = *configFlag
+	case os.Getenv("CONFIG_FILE") != "":
+		filename = os.Getenv("CONFIG_FILE")
+	default:
+		explicit = false
+	}
+
+	// 2-3. Файл конфигурации (YAML или .env)
+	if filename != "" {
+		if err := loadFile(filename, cfg); err != nil {
+			if explicit || !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	// 4. Переменные окружения (включая загруженные из .env файла)
+	if err := cfg.apply(func(f reflect.StructField) (string, bool) {
+		return os.LookupEnv(f.Tag.Get("env"))
+	}); err != nil {
+		return nil, err
+	}
+
+	// 5. Флаги командной строки (только явно переданные)
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) { flags[f.Name] = f.Value.String() })
+	if err := cfg.apply(func(f reflect.StructField) (string, bool) {
+		value, ok := flags[flagName(f.Tag.Get("env"))]
+		return value, ok
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Создаем и возвращаем структуру конфигурации
-	// Для каждого параметра используется значение из переменной окружения или значение по умолчанию
-	return &Config{
-		ServerAddress:       getEnv("SERVER_ADDRESS", ":8080"),                  // Адрес сервера
-		JWTSecret:           getEnv("JWT_SECRET", "default-secret"),             // Секрет JWT
-		DBHost:              getEnv("DB_HOST", "localhost"),                     // Хост БД
-		DBPort:              getEnv("DB_PORT", "5432"),                          // Порт БД
-		DBUser:              getEnv("DB_USER", "postgres"),                      // Пользователь БД
-		DBPassword:          getEnv("DB_PASSWORD", "ахха не скажу"),             // Пароль БД
-		DBName:              getEnv("DB_NAME", "wallet_db"),                     // Имя БД
-		DBSSLMode:           getEnv("DB_SSLMODE", "disable"),                    // Режим SSL
-		ExchangeServiceAddr: getEnv("EXCHANGE_SERVICE_ADDR", "localhost:50051"), // Адрес сервиса обмена
-		TokenExpiration:     tokenExp,                                           // Время жизни токена
-		CacheTTL:            cacheTTL,                                           // Время жизни кэша
-		TelegramToken:       getEnv("TELEGRAM_TOKEN", ""),                       // Токен бота
-		RedisAddr:           getEnv("REDIS_ADDR", "localhost:6379"),             // Адрес Redis
-		RedisPassword:       getEnv("REDIS_PASSWORD", ""),                       // Пароль Redis
-		RedisDB:             getEnvAsInt("REDIS_DB", 0),                         // Номер БД Redis
-	}, nil
+	return cfg, nil
+}
+
+// Validate проверяет обязательные параметры и согласованность настроек
+// Возвращает ошибку со списком всех найденных проблем
+func (c *Config) Validate() error {
+	var problems []string
+
+	switch c.JWTSecret {
+	case "":
+		problems = append(problems, "JWT_SECRET не задан")
+	case defaultJWTSecret:
+		problems = append(problems, "JWT_SECRET имеет значение по умолчанию, задайте собственный секрет")
+	}
+
+	required := map[string]string{
+		"SERVER_ADDRESS":        c.ServerAddress,
+		"DB_HOST":               c.DBHost,
+		"DB_PORT":               c.DBPort,
+		"DB_USER":               c.DBUser,
+		"DB_NAME":               c.DBName,
+		"EXCHANGE_SERVICE_ADDR": c.ExchangeServiceAddr,
+	}
+	for _, key := range sortedKeys(required) {
+		if strings.TrimSpace(required[key]) == "" {
+			problems = append(problems, key+" не задан")
+		}
+	}
+
+	if c.TokenExpiration <= 0 {
+		problems = append(problems, "TOKEN_EXPIRATION должен быть положительным")
+	}
+	if c.JWTLeeway < 0 {
+		problems = append(problems, "JWT_LEEWAY не может быть отрицательным")
+	}
+	if c.RefreshTokenIdleTTL < 0 || c.RefreshSessionMaxLifetime < 0 {
+		problems = append(problems, "REFRESH_TOKEN_IDLE_TTL и REFRESH_SESSION_MAX_LIFETIME не могут быть отрицательными")
+	} else if c.RefreshTokenIdleTTL == 0 && c.RefreshSessionMaxLifetime == 0 {
+		problems = append(problems, "REFRESH_TOKEN_IDLE_TTL и REFRESH_SESSION_MAX_LIFETIME не могут быть нулевыми одновременно: сессия не истекала бы")
+	}
+	if c.CacheTTL <= 0 {
+		problems = append(problems, "CACHE_TTL должен быть положительным")
+	}
+	if c.BalanceCacheEnabled {
+		// In-memory кэш не сбрасывается на других экземплярах сервиса: баланс был бы устаревшим
+		if c.RedisAddr == "" {
+			problems = append(problems, "BALANCE_CACHE_ENABLED требует REDIS_ADDR: кэш балансов должен быть общим для всех экземпляров")
+		}
+		if c.BalanceCacheTTL <= 0 {
+			problems = append(problems, "BALANCE_CACHE_TTL должен быть положительным")
+		}
+	}
+	if c.CurrencyFreezeRefresh < 0 {
+		problems = append(problems, "CURRENCY_FREEZE_REFRESH не может быть отрицательным")
+	}
+	if c.LedgerRetentionMonths < 0 || c.LedgerPartitionsAhead < 0 {
+		problems = append(problems, "LEDGER_RETENTION_MONTHS и LEDGER_PARTITIONS_AHEAD не могут быть отрицательными")
+	}
+	if c.DBMaxOpenConns < 0 || c.DBMaxIdleConns < 0 || c.DBConnMaxLifetime < 0 {
+		problems = append(problems, "параметры пула соединений БД не могут быть отрицательными")
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		problems = append(problems, "DB_MAX_IDLE_CONNS не может превышать DB_MAX_OPEN_CONNS")
+	}
+	if _, err := c.TxIsolation(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if c.OperationRecoveryInterval < 0 || c.OperationStaleAfter <= 0 || c.OperationMaxAttempts < 1 {
+		problems = append(problems, "OPERATION_RECOVERY_INTERVAL не может быть отрицательным, "+
+			"OPERATION_STALE_AFTER должен быть положительным, OPERATION_MAX_ATTEMPTS - не меньше 1")
+	}
+	if c.DBTxMaxAttempts < 1 {
+		problems = append(problems, "DB_TX_MAX_ATTEMPTS должен быть не меньше 1")
+	}
+	if c.ExchangeKeepaliveTime < 0 || c.ExchangeKeepaliveTimeout < 0 {
+		problems = append(problems, "EXCHANGE_KEEPALIVE_TIME и EXCHANGE_KEEPALIVE_TIMEOUT не могут быть отрицательными")
+	}
+	if c.RedisAddr != "" && c.RedisHealthInterval <= 0 {
+		problems = append(problems, "REDIS_HEALTH_INTERVAL должен быть положительным")
+	}
+	if c.ExchangeHealthInterval < 0 || c.ExchangeHealthTimeout <= 0 {
+		problems = append(problems, "EXCHANGE_HEALTH_INTERVAL не может быть отрицательным, EXCHANGE_HEALTH_TIMEOUT должен быть положительным")
+	}
+	if c.AlertLimit <= 0 {
+		problems = append(problems, "ALERT_LIMIT должен быть положительным")
+	}
+	if c.TelegramLinkCodeTTL <= 0 || c.ExchangeQuoteTTL <= 0 {
+		problems = append(problems, "TELEGRAM_LINK_CODE_TTL и EXCHANGE_QUOTE_TTL должны быть положительными")
+	}
+	if c.OpsFailureThreshold <= 0 {
+		problems = append(problems, "OPS_FAILURE_THRESHOLD должен быть положительным")
+	}
+	if c.TelegramCommandLimit < 0 {
+		problems = append(problems, "TELEGRAM_COMMAND_LIMIT не может быть отрицательным")
+	}
+	if c.TelegramCommandLimit > 0 && c.TelegramCommandWindow <= 0 {
+		problems = append(problems, "TELEGRAM_COMMAND_WINDOW должен быть положительным")
+	}
+	if c.BruteForceLimit < 0 {
+		problems = append(problems, "BRUTEFORCE_LIMIT не может быть отрицательным")
+	}
+	if c.BruteForceLimit > 0 && (c.BruteForceWindow <= 0 || c.BruteForceBanDuration <= 0) {
+		problems = append(problems, "BRUTEFORCE_WINDOW и BRUTEFORCE_BAN_DURATION должны быть положительными")
+	}
+	for plan, limit := range c.QuotaLimits {
+		if limit <= 0 || limit != math.Trunc(limit) {
+			problems = append(problems, fmt.Sprintf("QUOTA_LIMITS: квота плана %s должна быть целым положительным числом", plan))
+		}
+	}
+	if len(c.QuotaLimits) > 0 && c.QuotaWindow <= 0 {
+		problems = append(problems, "QUOTA_WINDOW должен быть положительным")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES: некорректный адрес %q", proxy))
+			}
+		}
+	}
+	if _, err := c.AdminNetworks(); err != nil {
+		problems = append(problems, fmt.Sprintf("ADMIN_ALLOWED_NETWORKS: %v", err))
+	}
+	if c.CaptchaProvider != "" {
+		if _, err := captcha.New(c.CaptchaProvider, c.CaptchaSecret, c.CaptchaVerifyURL); err != nil {
+			problems = append(problems, fmt.Sprintf("CAPTCHA_PROVIDER: %v", err))
+		}
+		if c.CaptchaLoginAfterFailures < 0 || c.CaptchaFailureWindow <= 0 {
+			problems = append(problems, "CAPTCHA_LOGIN_AFTER_FAILURES не может быть отрицательным, CAPTCHA_FAILURE_WINDOW должен быть положительным")
+		}
+	}
+	if c.SMTPAddr != "" && c.SMTPFrom == "" {
+		problems = append(problems, "SMTP_FROM обязателен при заданном SMTP_ADDR")
+	}
+	if c.DeviceConfirmation {
+		if c.SMTPAddr == "" {
+			problems = append(problems, "DEVICE_CONFIRMATION требует SMTP_ADDR: код подтверждения отправляется по email")
+		}
+		if c.DeviceCodeTTL <= 0 || c.DeviceMaxCodeAttempts <= 0 {
+			problems = append(problems, "DEVICE_CODE_TTL и DEVICE_MAX_CODE_ATTEMPTS должны быть положительными")
+		}
+	}
+	for _, channel := range c.NotifyChannels {
+		switch channel {
+		case models.NotificationChannelEmail:
+		case models.NotificationChannelTelegram:
+			if c.TelegramToken == "" {
+				problems = append(problems, "NOTIFY_CHANNELS: канал telegram требует TELEGRAM_TOKEN")
+			}
+		case models.NotificationChannelWebhook:
+			if len(c.NotifyWebhookSecret) < 32 {
+				problems = append(problems, "NOTIFY_CHANNELS: канал webhook требует NOTIFY_WEBHOOK_SECRET не короче 32 символов")
+			}
+			if c.NotifyWebhookTimeout <= 0 {
+				problems = append(problems, "NOTIFY_WEBHOOK_TIMEOUT должен быть положительным")
+			}
+		case models.NotificationChannelPush:
+			if c.PushFCMCredentialsFile == "" && c.PushAPNsKeyFile == "" {
+				problems = append(problems, "NOTIFY_CHANNELS: канал push требует PUSH_FCM_CREDENTIALS_FILE или PUSH_APNS_KEY_FILE")
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("NOTIFY_CHANNELS: неизвестный канал %q (допустимы email, telegram, webhook, push)", channel))
+		}
+	}
+	if c.PushAPNsKeyFile != "" && (c.PushAPNsKeyID == "" || c.PushAPNsTeamID == "" || c.PushAPNsTopic == "") {
+		problems = append(problems, "PUSH_APNS_KEY_FILE требует PUSH_APNS_KEY_ID, PUSH_APNS_TEAM_ID и PUSH_APNS_TOPIC")
+	}
+	if (c.PushAPNsKeyFile != "" || c.PushFCMCredentialsFile != "") && c.PushTimeout <= 0 {
+		problems = append(problems, "PUSH_TIMEOUT должен быть положительным")
+	}
+	for _, channel := range c.NotifyDefaultChannels {
+		if channel == models.NotificationChannelWebhook || !slices.Contains(c.NotifyChannels, channel) {
+			problems = append(problems, fmt.Sprintf("NOTIFY_DEFAULT_CHANNELS: канал %q не подключен в NOTIFY_CHANNELS или требует адреса из правила", channel))
+		}
+	}
+	if len(c.NotifyChannels) > 0 && (c.NotifyDispatchInterval <= 0 || c.NotifyMaxAttempts <= 0 || c.NotifyRetryDelay <= 0 || c.NotifyRetention <= 0) {
+		problems = append(problems, "NOTIFY_DISPATCH_INTERVAL, NOTIFY_MAX_ATTEMPTS, NOTIFY_RETRY_DELAY и NOTIFY_RETENTION должны быть положительными")
+	}
+	if c.PaymentWebhookSecret != "" {
+		if len(c.PaymentWebhookSecret) < 32 {
+			problems = append(problems, "PAYMENT_WEBHOOK_SECRET должен быть не короче 32 символов")
+		}
+		if c.PaymentWebhookTolerance <= 0 {
+			problems = append(problems, "PAYMENT_WEBHOOK_TOLERANCE должен быть положительным")
+		}
+	}
+	if c.ReceiptSecret != "" && len(c.ReceiptSecret) < 32 {
+		problems = append(problems, "RECEIPT_SECRET должен быть не короче 32 символов")
+	}
+	if c.ReceiptBaseURL != "" {
+		if u, err := url.Parse(c.ReceiptBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, "RECEIPT_BASE_URL должен быть адресом http(s)://хост")
+		}
+	}
+	if c.OTelEndpoint != "" {
+		if u, err := url.Parse(c.OTelEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, "OTEL_EXPORTER_OTLP_ENDPOINT должен быть адресом http(s)://хост:порт")
+		}
+		if c.OTelSampleRatio < 0 || c.OTelSampleRatio > 1 {
+			problems = append(problems, "OTEL_TRACES_SAMPLE_RATIO должен быть от 0 до 1")
+		}
+		if c.OTelMetricsInterval <= 0 {
+			problems = append(problems, "OTEL_METRICS_INTERVAL должен быть положительным")
+		}
+	}
+	switch c.TaxReportCurrency {
+	case "USD", "RUB", "EUR":
+	default:
+		problems = append(problems, fmt.Sprintf("TAX_REPORT_CURRENCY: неподдерживаемая валюта %q", c.TaxReportCurrency))
+	}
+	if c.TaxAccountingMethod != "fifo" && c.TaxAccountingMethod != "average" {
+		problems = append(problems, "TAX_ACCOUNTING_METHOD должен быть fifo или average")
+	}
+	if c.ExchangeFeePercent < 0 || c.ExchangeFeePercent >= 100 {
+		problems = append(problems, "EXCHANGE_FEE_PERCENT должен быть в диапазоне [0, 100)")
+	}
+	if _, err := fees.ParseTiers(c.FeeTiers); err != nil {
+		problems = append(problems, fmt.Sprintf("FEE_TIERS: %v", err))
+	} else if _, err := c.Tenants(); err != nil {
+		problems = append(problems, fmt.Sprintf("TENANTS_FILE: %v", err))
+	}
+	if _, err := c.TradingHours(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if c.ExchangeQueueInterval < 0 || c.DCAInterval < 0 {
+		problems = append(problems, "EXCHANGE_QUEUE_INTERVAL и DCA_INTERVAL не могут быть отрицательными")
+	}
+	if _, err := c.V1SunsetDate(); err != nil {
+		problems = append(problems, "API_V1_SUNSET должен быть датой в формате YYYY-MM-DD")
+	}
+	if c.KYCMaxDocumentSize <= 0 {
+		problems = append(problems, "KYC_MAX_DOCUMENT_SIZE должен быть положительным")
+	}
+	if c.HTTPTimeout < 0 {
+		problems = append(problems, "HTTP_TIMEOUT не может быть отрицательным")
+	}
+	if c.DrainDelay < 0 || c.DrainTimeout <= 0 {
+		problems = append(problems, "DRAIN_DELAY не может быть отрицательным, DRAIN_TIMEOUT должен быть положительным")
+	}
+	for _, route := range sortedKeys(c.HTTPRouteTimeouts) {
+		if c.HTTPRouteTimeouts[route] < 0 {
+			problems = append(problems, "HTTP_ROUTE_TIMEOUTS: таймаут "+route+" не может быть отрицательным")
+		}
+	}
+	if c.HTTPLogEnabled {
+		if c.HTTPLogSamplePercent < 0 || c.HTTPLogSamplePercent > 100 {
+			problems = append(problems, "HTTP_LOG_SAMPLE_PERCENT должен быть в диапазоне [0, 100]")
+		}
+		if c.HTTPLogMaxBody <= 0 {
+			problems = append(problems, "HTTP_LOG_MAX_BODY должен быть положительным")
+		}
+	}
+	if c.ChaosEnabled {
+		chaosPercents := map[string]float64{
+			"CHAOS_DB_LATENCY_PERCENT":    c.ChaosDBLatencyPercent,
+			"CHAOS_DB_ERROR_PERCENT":      c.ChaosDBErrorPercent,
+			"CHAOS_REDIS_LATENCY_PERCENT": c.ChaosRedisLatencyPercent,
+			"CHAOS_REDIS_ERROR_PERCENT":   c.ChaosRedisErrorPercent,
+			"CHAOS_GRPC_LATENCY_PERCENT":  c.ChaosGRPCLatencyPercent,
+			"CHAOS_GRPC_ERROR_PERCENT":    c.ChaosGRPCErrorPercent,
+		}
+		for _, key := range sortedKeys(chaosPercents) {
+			if chaosPercents[key] < 0 || chaosPercents[key] > 100 {
+				problems = append(problems, key+" должен быть в диапазоне [0, 100]")
+			}
+		}
+		if c.ChaosLatency < 0 {
+			problems = append(problems, "CHAOS_LATENCY не может быть отрицательным")
+		}
+	}
+
+	switch c.TLSMode {
+	case TLSModeNone:
+	case TLSModeFile:
+		if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+			problems = append(problems, "для TLS_MODE=file необходимо указать TLS_CERT_FILE и TLS_KEY_FILE")
+		}
+	case TLSModeAutocert:
+		if len(c.TLSAutocertDomains) == 0 {
+			problems = append(problems, "для TLS_MODE=autocert необходимо указать TLS_AUTOCERT_DOMAINS")
+		}
+	default:
+		problems = append(problems, "неизвестный TLS_MODE: "+c.TLSMode)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("некорректная конфигурация: %s", strings.Join(problems, "; "))
+	}
+	return nil
 }
 
 // GetDBConnString формирует строку подключения к PostgreSQL
@@ -82,23 +601,367 @@ func (c *Config) GetDBConnString() string {
 		" sslmode=" + c.DBSSLMode
 }
 
-// getEnv вспомогательная функция для получения переменной окружения
-// Принимает ключ переменной и значение по умолчанию
-// Возвращает значение переменной, если она существует, или значение по умолчанию
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// V1SunsetDate возвращает дату отключения /api/v1 (нулевое время, если дата не объявлена)
+func (c *Config) V1SunsetDate() (time.Time, error) {
+	if c.APIV1Sunset == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, c.APIV1Sunset)
+}
+
+// TxIsolation возвращает уровень изоляции транзакций с балансом из DB_TX_ISOLATION
+func (c *Config) TxIsolation() (sql.IsolationLevel, error) {
+	switch c.DBTxIsolation {
+	case "read_committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable_read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	}
+	return 0, fmt.Errorf("DB_TX_ISOLATION: неизвестный уровень изоляции %q (read_committed, repeatable_read, serializable)", c.DBTxIsolation)
+}
+
+// AdminNetworks возвращает подсети, из которых доступны маршруты администратора (nil - без ограничения)
+func (c *Config) AdminNetworks() ([]*net.IPNet, error) {
+	if len(c.AdminAllowedNetworks) == 0 {
+		return nil, nil
+	}
+	return middleware.ParseNetworks(c.AdminAllowedNetworks)
+}
+
+// Tenants возвращает реестр арендаторов: арендатор default с глобальной комиссией
+// (EXCHANGE_FEE_PERCENT, FEE_TIERS) и арендаторы из TENANTS_FILE
+func (c *Config) Tenants() (*tenant.Registry, error) {
+	tiers, err := fees.ParseTiers(c.FeeTiers)
+	if err != nil {
+		return nil, fmt.Errorf("FEE_TIERS: %w", err)
+	}
+	return tenant.Load(c.TenantsFile, fees.Schedule{BasePercent: c.ExchangeFeePercent, Tiers: tiers})
+}
+
+// TradingHours возвращает окна исполнения обменов из EXCHANGE_TRADING_HOURS в часовом поясе EXCHANGE_TRADING_TZ
+// (nil - обмены исполняются круглосуточно)
+func (c *Config) TradingHours() (*tradinghours.Schedule, error) {
+	loc, err := time.LoadLocation(c.ExchangeTradingTZ)
+	if err != nil {
+		return nil, fmt.Errorf("EXCHANGE_TRADING_TZ: %w", err)
+	}
+	schedule, err := tradinghours.Parse(c.ExchangeTradingHours, loc)
+	if err != nil {
+		return nil, fmt.Errorf("EXCHANGE_TRADING_HOURS: %w", err)
+	}
+	return schedule, nil
+}
+
+// PlanQuotas возвращает квоты запросов по тарифным планам из QUOTA_LIMITS
+func (c *Config) PlanQuotas() map[string]int64 {
+	quotas := make(map[string]int64, len(c.QuotaLimits))
+	for plan, limit := range c.QuotaLimits {
+		quotas[plan] = int64(limit)
 	}
-	return defaultValue
+	return quotas
+}
+
+// redactedValue заменяет заданные секреты в действующей конфигурации
+const redactedValue = "[REDACTED]"
+
+// Effective возвращает действующую конфигурацию для администраторов: значения всех параметров
+// в формате переменных окружения с отметкой измененных относительно значений по умолчанию
+// Значения параметров с тегом secret не раскрываются: заданный секрет заменяется на [REDACTED]
+func (c *Config) Effective() *models.EffectiveConfig {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	effective := &models.EffectiveConfig{
+		Entries:  make([]models.ConfigEntry, 0, t.NumField()),
+		Features: c.Features(),
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		value := formatField(v.Field(i))
+		// Значение по умолчанию приводится к тому же виду, что и действующее (1m -> 1m0s);
+		// без тега default - нулевое значение типа
+		defaultField := reflect.New(f.Type).Elem()
+		if raw, ok := f.Tag.Lookup("default"); ok {
+			_ = setField(defaultField, raw) // Значения по умолчанию разбираются при загрузке конфигурации
+		}
+		defaultValue := formatField(defaultField)
+		entry := models.ConfigEntry{
+			Key:        f.Tag.Get("env"),
+			Value:      value,
+			Default:    defaultValue,
+			Overridden: value != defaultValue,
+			Secret:     f.Tag.Get("secret") == "true",
+		}
+		if entry.Secret && value != "" {
+			entry.Value = redactedValue
+		}
+		effective.Entries = append(effective.Entries, entry)
+	}
+	return effective
+}
+
+// NotifyDefaults возвращает каналы уведомлений пользователей без правил доставки:
+// NOTIFY_DEFAULT_CHANNELS или все подключенные каналы, не требующие адреса из правила (все, кроме webhook)
+func (c *Config) NotifyDefaults() []string {
+	if len(c.NotifyDefaultChannels) > 0 {
+		return c.NotifyDefaultChannels
+	}
+	var defaults []string
+	for _, channel := range c.NotifyChannels {
+		if channel != models.NotificationChannelWebhook {
+			defaults = append(defaults, channel)
+		}
+	}
+	return defaults
+}
+
+// Features возвращает состояние функций экземпляра: флаги конфигурации и функции,
+// включаемые заданием параметров (токен бота, секрет CAPTCHA, адрес SMTP и т.д.)
+func (c *Config) Features() map[string]bool {
+	return map[string]bool{
+		"balance_cache":           c.BalanceCacheEnabled,
+		"risk":                    c.RiskEnabled,
+		"chaos":                   c.ChaosEnabled,
+		"device_confirmation":     c.DeviceConfirmation,
+		"adjustment_approval":     c.AdjustmentApprovalRequired,
+		"wallet_quarantine":       c.ReconciliationQuarantine,
+		"http_request_log":        c.HTTPLogEnabled,
+		"exchange_rate_watch":     c.ExchangeRateWatch,
+		"telegram_bot":            c.TelegramToken != "",
+		"telegram_ops_alerts":     c.TelegramToken != "" && c.TelegramAdminChatID != 0,
+		"captcha":                 c.CaptchaProvider != "",
+		"payment_webhook":         c.PaymentWebhookSecret != "",
+		"receipts":                c.ReceiptSecret != "",
+		"redis":                   c.RedisAddr != "",
+		"smtp":                    c.SMTPAddr != "",
+		"operation_notifications": len(c.NotifyChannels) > 0,
+		"push_fcm":                c.PushFCMCredentialsFile != "",
+		"push_apns":               c.PushAPNsKeyFile != "",
+		"geoip":                   c.GeoIPDBPath != "",
+		"metrics":                 c.MetricsAddr != "",
+		"opentelemetry":           c.OTelEndpoint != "",
+		"tls":                     c.TLSMode != TLSModeNone,
+		"admin_network_filter":    len(c.AdminAllowedNetworks) > 0,
+		"tenants":                 c.TenantsFile != "",
+		"api_v1_sunset":           c.APIV1Sunset != "",
+	}
+}
+
+// formatField приводит значение поля к строковому виду, который принимает setField
+// Ключи словарей упорядочиваются для стабильного вывода
+func formatField(field reflect.Value) string {
+	switch value := field.Interface().(type) {
+	case time.Duration:
+		return value.String()
+	case []string:
+		return strings.Join(value, ",")
+	case map[string]float64:
+		items := make([]string, 0, len(value))
+		for _, key := range sortedKeys(value) {
+			items = append(items, key+":"+strconv.FormatFloat(value[key], 'g', -1, 64))
+		}
+		return strings.Join(items, ",")
+	case map[string]time.Duration:
+		items := make([]string, 0, len(value))
+		for _, key := range sortedKeys(value) {
+			items = append(items, key+":"+value[key].String())
+		}
+		return strings.Join(items, ",")
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return fmt.Sprint(field.Interface())
+}
+
+// parseFlags регистрирует флаг для каждого поля конфигурации и разбирает аргументы
+// Возвращает набор флагов (для получения явно переданных значений) и значение флага -config
+func parseFlags(args []string) (*flag.FlagSet, *string, error) {
+	fs := flag.NewFlagSet("wallet", flag.ContinueOnError)
+	configFile := fs.String("config", "", "путь к файлу конфигурации (.env или .yaml)")
+
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("env")
+		fs.String(flagName(key), "", "переопределяет "+key)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("ошибка разбора флагов: %w", err)
+	}
+	return fs, configFile, nil
+}
+
+// loadFile загружает файл конфигурации в зависимости от расширения:
+// YAML файл применяется напрямую к cfg, .env файл загружается в переменные окружения
+func loadFile(filename string, cfg *Config) error {
+	if _, err := os.Stat(filename); err != nil {
+		return fmt.Errorf("файл конфигурации %s недоступен: %w", filename, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения %s: %w", filename, err)
+		}
+		values := make(map[string]interface{})
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("ошибка разбора YAML %s: %w", filename, err)
+		}
+		return cfg.apply(func(f reflect.StructField) (string, bool) {
+			value, ok := values[strings.ToLower(f.Tag.Get("env"))]
+			if !ok || value == nil {
+				return "", false
+			}
+			return yamlValueToString(value), true
+		})
+	default:
+		if err := godotenv.Load(filename); err != nil {
+			return fmt.Errorf("ошибка загрузки %s: %w", filename, err)
+		}
+		return nil
+	}
+}
+
+// apply записывает в поля конфигурации значения, возвращаемые функцией lookup
+// Для полей, по которым lookup вернул false, значение не изменяется
+func (c *Config) apply(lookup func(f reflect.StructField) (string, bool)) error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		raw, ok := lookup(t.Field(i))
+		if !ok {
+			continue
+		}
+		if err := setField(v.Field(i), raw); err != nil {
+			return fmt.Errorf("некорректное значение %s: %w", t.Field(i).Tag.Get("env"), err)
+		}
+	}
+	return nil
+}
+
+// setField разбирает строковое значение в соответствии с типом поля
+// Поддерживаются string, bool, int, float64, time.Duration, []string (через запятую)
+// и map[string]float64 (формат "USD:1000,EUR:900")
+func setField(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch field.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	case []string:
+		field.Set(reflect.ValueOf(splitList(raw)))
+		return nil
+	case map[string]float64:
+		values := make(map[string]float64)
+		for _, item := range splitList(raw) {
+			key, value, ok := strings.Cut(item, ":")
+			if !ok || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("некорректный элемент %q (ожидается ключ:значение)", item)
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return fmt.Errorf("некорректное значение для %s: %w", key, err)
+			}
+			values[strings.TrimSpace(key)] = f
+		}
+		field.Set(reflect.ValueOf(values))
+		return nil
+	case map[string]time.Duration:
+		// Ключ может содержать двоеточие (параметры маршрута /wallets/:user_id), поэтому
+		// значение отделяется по последнему двоеточию
+		values := make(map[string]time.Duration)
+		for _, item := range splitList(raw) {
+			sep := strings.LastIndex(item, ":")
+			if sep <= 0 || strings.TrimSpace(item[:sep]) == "" {
+				return fmt.Errorf("некорректный элемент %q (ожидается ключ:длительность)", item)
+			}
+			d, err := time.ParseDuration(strings.TrimSpace(item[sep+1:]))
+			if err != nil {
+				return fmt.Errorf("некорректное значение для %s: %w", item[:sep], err)
+			}
+			values[strings.TrimSpace(item[:sep])] = d
+		}
+		field.Set(reflect.ValueOf(values))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("неподдерживаемый тип поля %s", field.Type())
+	}
+	return nil
+}
+
+// yamlValueToString приводит значение из YAML к строковому виду, понятному setField
+// Списки объединяются через запятую, словари - в формат "ключ:значение" через запятую
+func yamlValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		items := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			items = append(items, fmt.Sprintf("%s:%v", key, v[key]))
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// splitList разбивает строку по запятым, отбрасывая пустые элементы и пробелы вокруг них
+// Возвращает nil для пустой строки
+func splitList(raw string) []string {
+	var result []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// flagName преобразует имя переменной окружения в имя флага (SERVER_ADDRESS -> server-address)
+func flagName(envKey string) string {
+	return strings.ReplaceAll(strings.ToLower(envKey), "_", "-")
 }
 
-// getEnvAsInt вспомогательная функция для получения целочисленной переменной окружения
-// Принимает имя переменной и значение по умолчанию
-// Возвращает значение переменной как int, если она существует и валидна, или значение по умолчанию
-func getEnvAsInt(name string, defaultVal int) int {
-	valueStr := getEnv(name, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+// sortedKeys возвращает ключи карты в алфавитном порядке (для стабильных сообщений об ошибках)
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
-	return defaultVal
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,149 @@
+package drain
+
+import (
+	"gw-currency-wallet/internal/models"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pollInterval - период проверки количества выполняющихся запросов
+const pollInterval = 50 * time.Millisecond
+
+// Drainer выводит экземпляр сервиса из балансировки перед остановкой (rolling deploy без простоя):
+// /readyz начинает отвечать 503, после задержки Delay (балансировщик успевает исключить экземпляр)
+// ожидается завершение выполняющихся запросов и текущих запусков фоновых задач.
+// Методы безопасны для nil (вывод из балансировки не поддерживается, экземпляр всегда готов)
+type Drainer struct {
+	delay    time.Duration // Пауза после снятия готовности до ожидания запросов
+	timeout  time.Duration // Максимальное ожидание выполняющихся запросов
+	stopJobs func()        // Останавливает фоновые задачи и дожидается текущих запусков
+
+	inFlight atomic.Int64  // Выполняющиеся запросы
+	once     sync.Once     // Вывод из балансировки запускается один раз
+	done     chan struct{} // Закрывается после завершения вывода
+
+	mu        sync.Mutex // Защищает поля ниже
+	startedAt *time.Time // Начало вывода
+	drainedAt *time.Time // Завершение вывода
+	timedOut  bool       // Запросы не завершились за timeout
+}
+
+// New создает Drainer
+// Параметры:
+//   - delay: пауза после снятия готовности, за которую балансировщик перестает направлять запросы
+//   - timeout: максимальное ожидание выполняющихся запросов
+//   - stopJobs: остановка фоновых задач с ожиданием текущих запусков (nil - задач нет)
+//
+// Возвращает:
+//   - *Drainer: экземпляр в состоянии serving
+func New(delay, timeout time.Duration, stopJobs func()) *Drainer {
+	return &Drainer{delay: delay, timeout: timeout, stopJobs: stopJobs, done: make(chan struct{})}
+}
+
+// Enter учитывает начало обработки запроса
+func (d *Drainer) Enter() {
+	if d != nil {
+		d.inFlight.Add(1)
+	}
+}
+
+// Leave учитывает завершение обработки запроса
+func (d *Drainer) Leave() {
+	if d != nil {
+		d.inFlight.Add(-1)
+	}
+}
+
+// Ready сообщает, готов ли экземпляр принимать запросы (вывод из балансировки не начат)
+func (d *Drainer) Ready() bool {
+	if d == nil {
+		return true
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.startedAt == nil
+}
+
+// Start начинает вывод из балансировки в фоне; повторный вызов ничего не делает
+// Возвращает:
+//   - bool: true, если вывод начат этим вызовом
+func (d *Drainer) Start() bool {
+	if d == nil {
+		return false
+	}
+	started := false
+	d.once.Do(func() {
+		started = true
+		now := time.Now()
+		d.mu.Lock()
+		d.startedAt = &now
+		d.mu.Unlock()
+		log.Printf("Вывод из балансировки: /readyz отвечает 503, ожидание запросов через %s", d.delay)
+		go d.run()
+	})
+	return started
+}
+
+// Done возвращает канал, закрывающийся после завершения вывода из балансировки
+func (d *Drainer) Done() <-chan struct{} {
+	if d == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	return d.done
+}
+
+// Status возвращает состояние вывода из балансировки
+func (d *Drainer) Status() models.DrainStatus {
+	if d == nil {
+		return models.DrainStatus{State: models.DrainStateServing}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status := models.DrainStatus{
+		State:     models.DrainStateServing,
+		InFlight:  d.inFlight.Load(),
+		StartedAt: d.startedAt,
+		DrainedAt: d.drainedAt,
+		TimedOut:  d.timedOut,
+	}
+	switch {
+	case d.drainedAt != nil:
+		status.State = models.DrainStateDrained
+	case d.startedAt != nil:
+		status.State = models.DrainStateDraining
+	}
+	return status
+}
+
+// run ожидает исключения из балансировки, завершения запросов и фоновых задач
+func (d *Drainer) run() {
+	time.Sleep(d.delay)
+
+	timedOut := false
+	deadline := time.Now().Add(d.timeout)
+	for d.inFlight.Load() > 0 {
+		if time.Now().After(deadline) {
+			timedOut = true
+			log.Printf("Вывод из балансировки: за %s не завершились %d запросов", d.timeout, d.inFlight.Load())
+			break
+		}
+		time.Sleep(pollInterval)
+	}
+
+	if d.stopJobs != nil {
+		d.stopJobs()
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	d.drainedAt = &now
+	d.timedOut = timedOut
+	d.mu.Unlock()
+	close(d.done)
+	log.Println("Вывод из балансировки завершен: экземпляр можно останавливать")
+}
@@ -0,0 +1,83 @@
+package fees
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tier - уровень лояльности со скидкой на комиссию обмена
+type Tier struct {
+	Name      string  // Название уровня
+	MinVolume float64 // Объем обменов за 30 дней (в USD), начиная с которого действует уровень
+	Discount  float64 // Скидка на комиссию в процентах (0-100)
+}
+
+// Schedule - базовая комиссия обмена и уровни скидок
+type Schedule struct {
+	BasePercent float64 // Базовая комиссия обмена в процентах от полученной суммы
+	Tiers       []Tier  // Уровни по возрастанию MinVolume; первый уровень начинается с нулевого объема
+}
+
+// ParseTiers разбирает уровни в формате "name:min_volume:discount,..."
+// (например "standard:0:0,silver:10000:25,gold:50000:50")
+// Параметры:
+//   - raw: строка с уровнями
+//
+// Возвращает:
+//   - []Tier: уровни по возрастанию объема (если нет уровня с нулевым объемом, добавляется "standard")
+//   - error: ошибка формата
+func ParseTiers(raw string) ([]Tier, error) {
+	var tiers []Tier
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) != 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("уровень %q: ожидается формат name:min_volume:discount", item)
+		}
+		minVolume, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || minVolume < 0 {
+			return nil, fmt.Errorf("уровень %q: некорректный объем", item)
+		}
+		discount, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		if err != nil || discount < 0 || discount > 100 {
+			return nil, fmt.Errorf("уровень %q: скидка должна быть от 0 до 100", item)
+		}
+		tiers = append(tiers, Tier{Name: strings.TrimSpace(parts[0]), MinVolume: minVolume, Discount: discount})
+	}
+
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinVolume < tiers[j].MinVolume })
+	for i := 1; i < len(tiers); i++ {
+		if tiers[i].MinVolume == tiers[i-1].MinVolume {
+			return nil, fmt.Errorf("уровни %s и %s имеют одинаковый объем", tiers[i-1].Name, tiers[i].Name)
+		}
+	}
+	if len(tiers) == 0 || tiers[0].MinVolume > 0 {
+		tiers = append([]Tier{{Name: "standard"}}, tiers...)
+	}
+	return tiers, nil
+}
+
+// TierFor возвращает уровень для объема обменов и следующий уровень (nil для максимального)
+func (s Schedule) TierFor(volume float64) (Tier, *Tier) {
+	current := 0
+	for i, tier := range s.Tiers {
+		if volume >= tier.MinVolume {
+			current = i
+		}
+	}
+	if current+1 < len(s.Tiers) {
+		next := s.Tiers[current+1]
+		return s.Tiers[current], &next
+	}
+	return s.Tiers[current], nil
+}
+
+// FeePercent возвращает комиссию обмена в процентах с учетом скидки уровня
+func (s Schedule) FeePercent(tier Tier) float64 {
+	return s.BasePercent * (100 - tier.Discount) / 100
+}
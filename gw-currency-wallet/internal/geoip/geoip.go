@@ -0,0 +1,73 @@
+package geoip
+
+import (
+	"fmt"
+	"github.com/oschwald/geoip2-golang" // Чтение баз MaxMind GeoIP2/GeoLite2
+	"net"
+)
+
+// Location - местоположение IP адреса
+type Location struct {
+	Country string // Код страны ISO 3166-1 (например "RU"), пусто - неизвестно
+	City    string // Название города на английском, пусто - неизвестно
+}
+
+// String возвращает местоположение для уведомлений ("Moscow, RU")
+func (l Location) String() string {
+	switch {
+	case l.City != "" && l.Country != "":
+		return l.City + ", " + l.Country
+	case l.Country != "":
+		return l.Country
+	default:
+		return "неизвестно"
+	}
+}
+
+// Locator определяет местоположение IP адресов по базе MaxMind (GeoLite2-City или GeoIP2-City)
+// Нулевой Locator (nil) возвращает пустое местоположение: база не обязательна
+type Locator struct {
+	db *geoip2.Reader // Открытая база
+}
+
+// Open открывает базу MaxMind
+// Параметры:
+//   - path: путь к файлу .mmdb (пустой - геолокация выключена, возвращается nil)
+//
+// Возвращает:
+//   - *Locator: геолокация или nil
+//   - error: ошибка открытия базы
+func Open(path string) (*Locator, error) {
+	if path == "" {
+		return nil, nil
+	}
+	db, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия базы GeoIP: %w", err)
+	}
+	return &Locator{db: db}, nil
+}
+
+// Lookup возвращает местоположение адреса (пустое, если адрес не найден или база не задана)
+func (l *Locator) Lookup(ip string) Location {
+	if l == nil {
+		return Location{}
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return Location{}
+	}
+	record, err := l.db.City(addr)
+	if err != nil {
+		return Location{}
+	}
+	return Location{Country: record.Country.IsoCode, City: record.City.Names["en"]}
+}
+
+// Close закрывает базу
+func (l *Locator) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.db.Close()
+}
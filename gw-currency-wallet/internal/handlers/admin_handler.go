@@ -0,0 +1,1066 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RunReconciliation godoc
+// @Summary Запустить сверку балансов
+// @Description Пересчитывает балансы всех кошельков по журналу операций и возвращает найденные расхождения
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ReconciliationReport
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Требуется роль администратора"
+// @Failure 409 {object} models.ErrorResponse "Сверка уже выполняется"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/reconciliation [post]
+func RunReconciliation(reconciliationService *services.ReconciliationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report, err := reconciliationService.Run(c.Request.Context())
+		if err != nil {
+			if errors.Is(err, services.ErrReconciliationRunning) {
+				middleware.ErrorJSON(c, http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			log.Printf("Ошибка сверки балансов: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка сверки балансов"})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// GetReconciliationReport godoc
+// @Summary Результат последней сверки
+// @Description Возвращает отчет последней сверки балансов (плановой или запущенной вручную)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ReconciliationReport
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Сверка еще не выполнялась"
+// @Router /admin/reconciliation [get]
+func GetReconciliationReport(reconciliationService *services.ReconciliationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := reconciliationService.LastReport()
+		if report == nil {
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": "Сверка еще не выполнялась"})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// ReleaseWallet godoc
+// @Summary Снять блокировку кошелька
+// @Description Разблокирует кошелек, заблокированный по итогам сверки
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path int true "ID пользователя"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Кошелек не найден"
+// @Router /admin/wallets/{user_id}/quarantine [delete]
+func ReleaseWallet(reconciliationService *services.ReconciliationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.Atoi(c.Param("user_id"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID пользователя"})
+			return
+		}
+
+		if err := reconciliationService.ReleaseWallet(c.Request.Context(), userID); err != nil {
+			if errors.Is(err, storage.ErrWalletUnavailable) {
+				middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": "Кошелек не найден"})
+				return
+			}
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Блокировка кошелька снята",
+			"user_id": userID,
+		})
+	}
+}
+
+// ListReviews godoc
+// @Summary Очередь проверки антифрода
+// @Description Возвращает операции, отложенные антифродом, от старых к новым
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Статус (pending, approved, rejected, failed), по умолчанию pending"
+// @Param limit query int false "Количество записей (по умолчанию 100)"
+// @Success 200 {array} models.RiskReview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/reviews [get]
+func ListReviews(walletService *services.WalletService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.DefaultQuery("status", models.ReviewPending)
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		reviews, err := walletService.ListReviews(c.Request.Context(), status, limit)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+	}
+}
+
+// ResolveReview godoc
+// @Summary Решение по отложенной операции
+// @Description Одобряет (операция выполняется) или отклоняет операцию из очереди проверки антифрода
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проверки"
+// @Param input body models.ResolveReviewRequest true "Решение"
+// @Success 200 {object} models.RiskReview "Итоговый статус (failed, если одобренную операцию не удалось выполнить)"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Проверка не найдена или уже обработана"
+// @Router /admin/reviews/{id}/resolve [post]
+func ResolveReview(walletService *services.WalletService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reviewID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID проверки"})
+			return
+		}
+
+		var request models.ResolveReviewRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+		if request.Decision != "approve" && request.Decision != "reject" {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "decision должен быть approve или reject"})
+			return
+		}
+
+		adminID := c.MustGet("userID").(int)
+
+		review, err := walletService.ResolveReview(
+			c.Request.Context(),
+			reviewID,
+			adminID,
+			request.Decision == "approve",
+			request.Comment,
+		)
+		if err != nil {
+			if errors.Is(err, storage.ErrReviewNotFound) {
+				middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			log.Printf("Ошибка обработки проверки %d: %v", reviewID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка обработки проверки"})
+			return
+		}
+
+		c.JSON(http.StatusOK, review)
+	}
+}
+
+// ListBalances godoc
+// @Summary Балансы пользователей
+// @Description Возвращает балансы пользователей из списка user_ids или всех пользователей страницами
+// @Description по возрастанию ID (следующая страница запрашивается с after_id = next_after_id)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param user_ids query string false "ID пользователей через запятую (не более 500)"
+// @Param after_id query int false "ID пользователя, после которого продолжается список"
+// @Param limit query int false "Размер страницы (по умолчанию 100, не более 500)"
+// @Success 200 {array} models.UserBalance
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/balances [get]
+func ListBalances(walletService *services.WalletService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var userIDs []int
+		if raw := c.Query("user_ids"); raw != "" {
+			for _, part := range strings.Split(raw, ",") {
+				id, err := strconv.Atoi(strings.TrimSpace(part))
+				if err != nil || id <= 0 {
+					middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр user_ids"})
+					return
+				}
+				userIDs = append(userIDs, id)
+			}
+			if len(userIDs) > services.MaxBalanceListSize {
+				middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("В запросе не более %d пользователей", services.MaxBalanceListSize),
+				})
+				return
+			}
+		}
+		afterID, _ := strconv.Atoi(c.Query("after_id"))
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		balances, next, err := walletService.ListBalances(c.Request.Context(), userIDs, afterID, limit)
+		if err != nil {
+			log.Printf("Ошибка получения списка балансов: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения балансов"})
+			return
+		}
+
+		response := gin.H{"balances": balances}
+		if next > 0 {
+			response["next_after_id"] = next
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// SearchUsers godoc
+// @Summary Поиск пользователей
+// @Description Ищет пользователей по префиксу имени или email (без учета регистра) с фильтрами по статусу верификации,
+// @Description роли, тарифному плану, арендатору и периоду регистрации. По умолчанию новые пользователи выводятся первыми
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "Префикс имени пользователя или email"
+// @Param status query string false "Статус верификации (none, pending, verified, rejected)"
+// @Param role query string false "Роль (user, admin)"
+// @Param plan query string false "Тарифный план (free, premium)"
+// @Param tenant query string false "Арендатор"
+// @Param registered_from query string false "Начало периода регистрации (RFC3339 или YYYY-MM-DD, включительно)"
+// @Param registered_to query string false "Конец периода регистрации (RFC3339 или YYYY-MM-DD, не включительно)"
+// @Param sort query string false "Поле сортировки: id, created_at, username, email; с префиксом - по убыванию (по умолчанию -created_at)"
+// @Param limit query int false "Размер страницы (по умолчанию 50, не более 200)"
+// @Param offset query int false "Смещение страницы"
+// @Success 200 {array} models.User
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users [get]
+func SearchUsers(userService *services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, err := parseTimeParam(c.Query("registered_from"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр registered_from"})
+			return
+		}
+		to, err := parseTimeParam(c.Query("registered_to"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр registered_to"})
+			return
+		}
+		limit, _ := strconv.Atoi(c.Query("limit"))
+		offset, _ := strconv.Atoi(c.Query("offset"))
+		sort := c.Query("sort")
+
+		users, total, err := userService.Search(c.Request.Context(), models.UserSearch{
+			Query:     strings.TrimSpace(c.Query("q")),
+			TenantID:  c.Query("tenant"),
+			KYCStatus: c.Query("status"),
+			Role:      c.Query("role"),
+			Plan:      c.Query("plan"),
+			From:      from,
+			To:        to,
+			Sort:      strings.TrimPrefix(sort, "-"),
+			Desc:      strings.HasPrefix(sort, "-"),
+			Limit:     limit,
+			Offset:    offset,
+		})
+		switch {
+		case errors.Is(err, services.ErrInvalidUserSearch):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err != nil:
+			log.Printf("Ошибка поиска пользователей: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка поиска пользователей"})
+		default:
+			c.JSON(http.StatusOK, gin.H{"users": users, "total": total})
+		}
+	}
+}
+
+// ListKYCApplicants godoc
+// @Summary Заявки на верификацию
+// @Description Возвращает пользователей в указанном статусе верификации, от давно ожидающих к недавним
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Статус (none, pending, verified, rejected), по умолчанию pending"
+// @Param limit query int false "Количество записей (по умолчанию 100)"
+// @Success 200 {array} models.User
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/kyc [get]
+func ListKYCApplicants(kycService *services.KYCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.DefaultQuery("status", models.KYCPending)
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		users, err := kycService.ListApplicants(c.Request.Context(), status, limit)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"users": users})
+	}
+}
+
+// GetKYCDocuments godoc
+// @Summary Документы пользователя
+// @Description Возвращает метаданные документов, поданных пользователем на верификацию
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param user_id path int true "ID пользователя"
+// @Success 200 {array} models.KYCDocument
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/kyc/{user_id}/documents [get]
+func GetKYCDocuments(kycService *services.KYCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.Atoi(c.Param("user_id"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID пользователя"})
+			return
+		}
+
+		docs, err := kycService.ListDocuments(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка получения документов пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения документов"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"documents": docs})
+	}
+}
+
+// ResolveKYC godoc
+// @Summary Решение по верификации
+// @Description Верифицирует пользователя или отклоняет его документы (заявка должна быть в статусе pending)
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param user_id path int true "ID пользователя"
+// @Param input body models.ResolveKYCRequest true "Решение"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Заявка не ожидает проверки"
+// @Router /admin/kyc/{user_id}/resolve [post]
+func ResolveKYC(kycService *services.KYCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.Atoi(c.Param("user_id"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID пользователя"})
+			return
+		}
+
+		var request models.ResolveKYCRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+		if request.Decision != "verify" && request.Decision != "reject" {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "decision должен быть verify или reject"})
+			return
+		}
+
+		adminID := c.MustGet("userID").(int)
+
+		err = kycService.Resolve(c.Request.Context(), userID, adminID, request.Decision == "verify", request.Comment)
+		if err != nil {
+			if errors.Is(err, storage.ErrKYCTransition) {
+				middleware.ErrorJSON(c, http.StatusConflict, gin.H{"error": "Заявка на верификацию не ожидает проверки"})
+				return
+			}
+			log.Printf("Ошибка решения по верификации пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка решения по верификации"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Решение по верификации сохранено",
+			"user_id": userID,
+		})
+	}
+}
+
+// ExportAMLReport godoc
+// @Summary Отчет о крупных операциях (AML)
+// @Description Выгружает в CSV операции с суммой не меньше порога отчетности за период (по умолчанию - с начала прошлого месяца)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce text/csv
+// @Param from query string false "Начало периода (RFC3339 или YYYY-MM-DD)"
+// @Param to query string false "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)"
+// @Success 200 {file} file "CSV отчет"
+// @Failure 400 {object} models.ErrorResponse "Некорректный период"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/aml/report [get]
+func ExportAMLReport(complianceService *services.ComplianceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, err := parseTimeParam(c.Query("from"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр from"})
+			return
+		}
+		to, err := parseTimeParam(c.Query("to"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр to"})
+			return
+		}
+
+		from, to, err = complianceService.ReportPeriod(from, to)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Отчет формируется целиком до отправки: при ошибке клиент получит код ошибки,
+		// а не обрезанный файл (записей в отчете немного - только операции выше порога)
+		var report bytes.Buffer
+		if err := complianceService.ExportAMLReport(c.Request.Context(), from, to, &report); err != nil {
+			log.Printf("Ошибка выгрузки отчета AML: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка формирования отчета"})
+			return
+		}
+
+		fileName := fmt.Sprintf("aml_report_%s_%s.csv", from.Format("20060102"), to.Format("20060102"))
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", report.Bytes())
+	}
+}
+
+// CreateAdjustment godoc
+// @Summary Корректировка баланса
+// @Description Зачисляет (положительная сумма) или списывает (отрицательная) средства пользователя с обязательным обоснованием. Если включено подтверждение вторым администратором, корректировка ожидает подтверждения (202)
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body models.AdjustmentRequest true "Корректировка"
+// @Success 201 {object} models.BalanceAdjustment "Корректировка проведена"
+// @Success 202 {object} models.BalanceAdjustment "Корректировка ожидает подтверждения"
+// @Failure 400 {object} models.ErrorResponse "Некорректные данные или недостаточно средств"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/adjustments [post]
+func CreateAdjustment(adjustmentService *services.AdjustmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.AdjustmentRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		adminID := c.MustGet("userID").(int)
+
+		adj, balance, err := adjustmentService.Request(c.Request.Context(), adminID, request)
+		if err != nil {
+			respondAdjustmentError(c, err)
+			return
+		}
+
+		if balance == nil {
+			c.JSON(http.StatusAccepted, gin.H{"adjustment": adj})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"adjustment": adj, "new_balance": balance})
+	}
+}
+
+// ApproveAdjustment godoc
+// @Summary Подтверждение корректировки
+// @Description Подтверждает и проводит корректировку баланса (при обязательном подтверждении - только другой администратор)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID корректировки"
+// @Success 200 {object} models.BalanceAdjustment
+// @Failure 400 {object} models.ErrorResponse "Недостаточно средств"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Подтверждение собственной корректировки"
+// @Failure 404 {object} models.ErrorResponse "Корректировка не найдена или уже обработана"
+// @Router /admin/adjustments/{id}/approve [post]
+func ApproveAdjustment(adjustmentService *services.AdjustmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID корректировки"})
+			return
+		}
+
+		adminID := c.MustGet("userID").(int)
+
+		adj, balance, err := adjustmentService.Approve(c.Request.Context(), id, adminID)
+		if err != nil {
+			respondAdjustmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"adjustment": adj, "new_balance": balance})
+	}
+}
+
+// RejectAdjustment godoc
+// @Summary Отклонение корректировки
+// @Description Отклоняет корректировку, ожидающую подтверждения
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID корректировки"
+// @Param input body models.RejectAdjustmentRequest false "Причина отклонения"
+// @Success 200 {object} models.BalanceAdjustment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Корректировка не найдена или уже обработана"
+// @Router /admin/adjustments/{id}/reject [post]
+func RejectAdjustment(adjustmentService *services.AdjustmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID корректировки"})
+			return
+		}
+
+		// Тело запроса необязательно
+		var request models.RejectAdjustmentRequest
+		_ = c.ShouldBindJSON(&request)
+
+		adminID := c.MustGet("userID").(int)
+
+		adj, err := adjustmentService.Reject(c.Request.Context(), id, adminID, request.Comment)
+		if err != nil {
+			respondAdjustmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, adj)
+	}
+}
+
+// ListAdjustments godoc
+// @Summary Корректировки баланса
+// @Description Возвращает корректировки баланса, от новых к старым
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Статус (pending, applied, rejected), по умолчанию все"
+// @Param limit query int false "Количество записей (по умолчанию 100)"
+// @Success 200 {array} models.BalanceAdjustment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/adjustments [get]
+func ListAdjustments(adjustmentService *services.AdjustmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		adjustments, err := adjustmentService.List(c.Request.Context(), c.Query("status"), limit)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"adjustments": adjustments})
+	}
+}
+
+// GetAuditLog godoc
+// @Summary Журнал действий администраторов
+// @Description Возвращает последние действия администраторов, от новых к старым
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Количество записей (по умолчанию 100)"
+// @Success 200 {array} models.AuditEntry
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/audit [get]
+func GetAuditLog(adjustmentService *services.AdjustmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		entries, err := adjustmentService.AuditLog(c.Request.Context(), limit)
+		if err != nil {
+			log.Printf("Ошибка получения журнала действий: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения журнала действий"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"entries": entries})
+	}
+}
+
+// respondAdjustmentError формирует ответ на ошибку корректировки баланса
+func respondAdjustmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrAdjustmentNotFound):
+		middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSelfApproval):
+		middleware.ErrorJSON(c, http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, storage.ErrWalletUnavailable):
+		middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": "Кошелек пользователя не найден"})
+	default:
+		middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// CreatePromo godoc
+// @Summary Создание промокода
+// @Description Создает промокод: fixed - фиксированная сумма при активации, percent - процент от пополнения (не больше max_bonus)
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body models.CreatePromoRequest true "Промокод"
+// @Success 201 {object} models.PromoCode
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Промокод уже существует"
+// @Router /admin/promos [post]
+func CreatePromo(promoService *services.PromoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreatePromoRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		adminID := c.MustGet("userID").(int)
+
+		promo, err := promoService.Create(c.Request.Context(), adminID, request)
+		if err != nil {
+			respondPromoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, promo)
+	}
+}
+
+// ListPromos godoc
+// @Summary Промокоды
+// @Description Возвращает промокоды с количеством активаций, от новых к старым
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Количество записей (по умолчанию 100)"
+// @Success 200 {array} models.PromoCode
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/promos [get]
+func ListPromos(promoService *services.PromoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		promos, err := promoService.List(c.Request.Context(), limit)
+		if err != nil {
+			log.Printf("Ошибка получения промокодов: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения промокодов"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"promos": promos})
+	}
+}
+
+// DeactivatePromo godoc
+// @Summary Отключение промокода
+// @Description Отключает промокод; выполненные активации сохраняются
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param code path string true "Промокод"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Промокод не найден или уже отключен"
+// @Router /admin/promos/{code} [delete]
+func DeactivatePromo(promoService *services.PromoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID := c.MustGet("userID").(int)
+
+		if err := promoService.Deactivate(c.Request.Context(), c.Param("code"), adminID); err != nil {
+			respondPromoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Промокод отключен"})
+	}
+}
+
+// ListIPBans godoc
+// @Summary Заблокированные IP адреса
+// @Description Возвращает действующие блокировки адресов за подбор паролей, от новых к старым
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.IPBan
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/bans [get]
+func ListIPBans(bruteForceService *services.BruteForceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bans, err := bruteForceService.ListBans(c.Request.Context())
+		if err != nil {
+			log.Printf("Ошибка получения блокировок адресов: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения блокировок адресов"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"bans": bans})
+	}
+}
+
+// UnbanIP godoc
+// @Summary Снять блокировку IP адреса
+// @Description Досрочно снимает блокировку адреса; счетчик попыток продолжает действовать
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param ip path string true "IP адрес"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 400 {object} models.ErrorResponse "Некорректный IP адрес"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Адрес не заблокирован"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/bans/{ip} [delete]
+func UnbanIP(bruteForceService *services.BruteForceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID := c.MustGet("userID").(int)
+
+		err := bruteForceService.Unban(c.Request.Context(), adminID, c.Param("ip"))
+		switch {
+		case errors.Is(err, services.ErrInvalidIP):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrBanNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка снятия блокировки адреса: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка снятия блокировки адреса"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Блокировка адреса снята"})
+	}
+}
+
+// SetUserPlan godoc
+// @Summary Назначить тарифный план
+// @Description Назначает пользователю тарифный план, определяющий квоту запросов
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param user_id path int true "ID пользователя"
+// @Param input body models.SetPlanRequest true "Тарифный план"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 400 {object} models.ErrorResponse "Неизвестный тарифный план"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{user_id}/plan [put]
+func SetUserPlan(quotaService *services.QuotaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.Atoi(c.Param("user_id"))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID пользователя"})
+			return
+		}
+
+		var request models.SetPlanRequest
+		if err := c.ShouldBindJSON(&request); err != nil || request.Plan == "" {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		err = quotaService.SetPlan(c.Request.Context(), userID, request.Plan)
+		switch {
+		case errors.Is(err, services.ErrUnknownPlan):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrUserNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка назначения тарифного плана пользователю %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка назначения тарифного плана"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Тарифный план назначен",
+			"user_id": userID,
+			"plan":    request.Plan,
+		})
+	}
+}
+
+// GetEffectiveConfig godoc
+// @Summary Действующая конфигурация
+// @Description Возвращает значения всех параметров экземпляра после применения файла, окружения и флагов
+// @Description в формате переменных окружения, значения по умолчанию и отметку измененных параметров.
+// @Description Секреты (JWT_SECRET, DB_PASSWORD, токены) не раскрываются: заданное значение заменяется на [REDACTED].
+// @Description features - состояние функций экземпляра (флаги конфигурации и функции, включенные заданием параметров)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.EffectiveConfig
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/config [get]
+func GetEffectiveConfig(cfg *models.EffectiveConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, cfg)
+	}
+}
+
+// ListDeadLetters godoc
+// @Summary Недоставленные уведомления
+// @Description Возвращает уведомления об операциях, не доставленные за отведенные попытки или из-за недоступного получателя (dead letter), от новых к старым
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Количество уведомлений (по умолчанию 50, не больше 200)"
+// @Success 200 {array} models.Notification
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/notifications/dead-letters [get]
+func ListDeadLetters(notificationService *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		notifications, err := notificationService.DeadLetters(c.Request.Context(), limit)
+		if err != nil {
+			log.Printf("Ошибка получения недоставленных уведомлений: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения уведомлений"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+	}
+}
+
+// RequeueNotification godoc
+// @Summary Повторная доставка уведомления
+// @Description Возвращает недоставленное уведомление в очередь с новым счетчиком попыток
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID уведомления"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Недоставленного уведомления нет"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/notifications/{id}/requeue [post]
+func RequeueNotification(notificationService *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID уведомления"})
+			return
+		}
+
+		err = notificationService.Requeue(c.Request.Context(), id)
+		switch {
+		case errors.Is(err, services.ErrNotificationNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка возврата уведомления %d в очередь: %v", id, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка возврата уведомления в очередь"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Уведомление возвращено в очередь"})
+	}
+}
+
+// FlushCache godoc
+// @Summary Сброс кэша
+// @Description Удаляет записи кэша выбранных групп: rates - курсы, справочник валют и графики курсов,
+// @Description balances - балансы кошельков, limits - тарифные планы и счетчики квот запросов.
+// @Description С Redis сброс рассылается всем экземплярам сервиса. Используется после ручной установки курса
+// @Description или исправления данных в БД
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body models.CacheFlushRequest true "Группы записей"
+// @Success 200 {object} models.CacheFlushResult
+// @Failure 400 {object} models.ErrorResponse "Неизвестная группа записей"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/cache/flush [post]
+func FlushCache(cacheService *services.CacheService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CacheFlushRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		adminID := c.MustGet("userID").(int)
+
+		result, err := cacheService.Flush(c.Request.Context(), adminID, request.Scopes)
+		switch {
+		case errors.Is(err, services.ErrInvalidCacheScope):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка сброса кэша: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка сброса кэша"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// ListCurrencyFreezes godoc
+// @Summary Приостановленные валюты
+// @Description Возвращает валюты, операции в которых приостановлены администраторами, с причиной и временем приостановки
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.CurrencyFreeze
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/currencies/freezes [get]
+func ListCurrencyFreezes(freezeService *services.CurrencyFreezeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		freezes, err := freezeService.List(c.Request.Context())
+		if err != nil {
+			log.Printf("Ошибка получения приостановок валют: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения приостановок валют"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"freezes": freezes})
+	}
+}
+
+// FreezeCurrency godoc
+// @Summary Приостановка операций в валюте
+// @Description Приостанавливает пополнение, снятие, перевод и обмен в валюте (например, при резких колебаниях курса).
+// @Description Операции отклоняются с ответом 503 "валюта временно недоступна" и причиной; на других экземплярах
+// @Description приостановка вступает в силу в течение CURRENCY_FREEZE_REFRESH. Повторный запрос заменяет причину
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param currency path string true "Валюта (USD, RUB, EUR)"
+// @Param input body models.FreezeCurrencyRequest false "Причина"
+// @Success 200 {object} models.CurrencyFreeze
+// @Failure 400 {object} models.ErrorResponse "Неподдерживаемая валюта или слишком длинная причина"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/currencies/{currency}/freeze [put]
+func FreezeCurrency(freezeService *services.CurrencyFreezeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.FreezeCurrencyRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&request); err != nil {
+				middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+				return
+			}
+		}
+
+		adminID := c.MustGet("userID").(int)
+
+		freeze, err := freezeService.Freeze(c.Request.Context(), adminID, c.Param("currency"), request.Reason)
+		switch {
+		case errors.Is(err, services.ErrInvalidFreeze):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка приостановки операций в валюте %s: %v", c.Param("currency"), err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка приостановки операций в валюте"})
+			return
+		}
+
+		c.JSON(http.StatusOK, freeze)
+	}
+}
+
+// UnfreezeCurrency godoc
+// @Summary Возобновление операций в валюте
+// @Description Снимает приостановку операций в валюте
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param currency path string true "Валюта"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Операции в валюте не приостановлены"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/currencies/{currency}/freeze [delete]
+func UnfreezeCurrency(freezeService *services.CurrencyFreezeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID := c.MustGet("userID").(int)
+
+		err := freezeService.Unfreeze(c.Request.Context(), adminID, c.Param("currency"))
+		switch {
+		case errors.Is(err, services.ErrCurrencyNotFrozen):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка возобновления операций в валюте %s: %v", c.Param("currency"), err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка возобновления операций в валюте"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Операции в валюте возобновлены"})
+	}
+}
@@ -1,22 +1,36 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin" // Веб-фреймворк Gin
+	"gw-currency-wallet/internal/middleware"
 	"gw-currency-wallet/internal/models"
 	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"log"
 	"net/http"
 )
 
+// DeviceIDHeader - необязательный заголовок с идентификатором установки клиента
+// Уточняет отпечаток устройства, когда разные устройства отправляют одинаковый User-Agent
+const DeviceIDHeader = "X-Device-ID"
+
 // Register godoc
 // @Summary Регистрация нового пользователя
 // @Description Создает нового пользователя в системе
-// @Tags Auth - Группа методов в Swagger
-// @Accept json - Ожидаемый Content-Type
-// @Produce json - Возвращаемый Content-Type
+// @Tags Auth
+// @Accept json
+// @Produce json
 // @Param input body models.CreateUserRequest true "Данные для регистрации"
-// @Success 201 {object} models.SuccessMessage - Успешный ответ
-// @Failure 400 {object} models.ErrorResponse - Ошибка валидации
-// @Router /register [post] - Путь и HTTP метод
+// @Param X-Captcha-Token header string false "Ответ на CAPTCHA (если включена CAPTCHA_PROVIDER)"
+// @Param X-Tenant-ID header string false "Арендатор (бренд); по умолчанию определяется по хосту"
+// @Success 201 {object} models.SuccessMessage "Успешный ответ"
+// @Failure 400 {object} models.ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} models.ErrorResponse "Проверка CAPTCHA не пройдена"
+// @Failure 409 {object} models.ErrorResponse "Имя пользователя или email уже заняты"
+// @Failure 429 {object} models.ErrorResponse "Слишком много попыток с IP адреса (заголовок Retry-After)"
+// @Failure 500 {object} models.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /register [post]
 func Register(authService *services.AuthService) gin.HandlerFunc {
 	// Возвращаем функцию-обработчик Gin
 	return func(c *gin.Context) {
@@ -24,15 +38,24 @@ func Register(authService *services.AuthService) gin.HandlerFunc {
 		var req models.CreateUserRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
 			// При ошибке парсинга возвращаем 400 Bad Request
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
 			return
 		}
 
 		// 2. Вызов сервиса регистрации
 		user, err := authService.Register(c.Request.Context(), req)
-		if err != nil {
-			// При ошибке регистрации возвращаем 400 с описанием ошибки
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, storage.ErrUsernameTaken), errors.Is(err, storage.ErrEmailTaken):
+			// Занятое имя или email - конфликт с существующим пользователем
+			middleware.ErrorJSON(c, http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrPasswordTooLong):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			// Ошибки хранилища не передаются клиенту
+			log.Printf("Ошибка регистрации пользователя: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка регистрации"})
 			return
 		}
 
@@ -46,36 +69,176 @@ func Register(authService *services.AuthService) gin.HandlerFunc {
 
 // Login godoc
 // @Summary Аутентификация пользователя
-// @Description Вход в систему с получением JWT токена
+// @Description Вход в систему с получением JWT токена и токена обновления (см. /token/refresh).
+// @Description При входе с нового устройства пользователю отправляется уведомление; если включено
+// @Description подтверждение (DEVICE_CONFIRMATION), вместо токена возвращается 202 с идентификатором
+// @Description подтверждения, а код отправляется на email пользователя (см. /login/confirm)
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param input body models.LoginRequest true "Данные для входа"
-// @Success 200 {object} models.LoginResponse - Успешный ответ с токеном
-// @Failure 401 {object} models.ErrorResponse - Ошибка аутентификации
+// @Param X-Captcha-Token header string false "Ответ на CAPTCHA (после CAPTCHA_LOGIN_AFTER_FAILURES неудачных входов)"
+// @Param X-Device-ID header string false "Идентификатор установки клиента (уточняет отпечаток устройства)"
+// @Param X-Tenant-ID header string false "Арендатор (бренд); по умолчанию определяется по хосту"
+// @Success 200 {object} models.LoginResponse "Успешный ответ с токеном"
+// @Success 202 {object} models.DeviceConfirmationResponse "Вход с нового устройства требует подтверждения"
+// @Failure 401 {object} models.ErrorResponse "Ошибка аутентификации"
+// @Failure 403 {object} models.ErrorResponse "Требуется или не пройдена проверка CAPTCHA"
+// @Failure 429 {object} models.ErrorResponse "Слишком много попыток с IP адреса (заголовок Retry-After)"
+// @Failure 503 {object} models.ErrorResponse "Не удалось отправить код подтверждения"
 // @Router /login [post]
-func Login(authService *services.AuthService) gin.HandlerFunc {
+func Login(authService *services.AuthService, deviceService *services.DeviceService, sessionService *services.SessionService) gin.HandlerFunc {
 	// Возвращаем функцию-обработчик Gin
 	return func(c *gin.Context) {
 		// 1. Парсинг входных данных
 		var req models.LoginRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
 			// При ошибке парсинга возвращаем 400
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
 			return
 		}
 
-		// 2. Вызов сервиса аутентификации
-		token, err := authService.Login(c.Request.Context(), req.Username, req.Password)
+		// 2. Проверка учетных данных
+		user, err := authService.Authenticate(c.Request.Context(), req.Username, req.Password)
 		if err != nil {
 			// При ошибке аутентификации возвращаем 401 Unauthorized
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			middleware.ErrorJSON(c, http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
 
-		// 3. Успешный ответ с JWT токеном
-		c.JSON(http.StatusOK, gin.H{
-			"token": token, // Возвращаем сгенерированный токен
+		// 3. Проверка устройства: вход с нового устройства может требовать подтверждения кодом
+		confirmationID, err := deviceService.CheckLogin(c.Request.Context(), user, services.LoginDevice{
+			UserAgent: c.GetHeader("User-Agent"),
+			DeviceID:  c.GetHeader(DeviceIDHeader),
+			IP:        c.ClientIP(),
 		})
+		if err != nil {
+			log.Printf("Ошибка проверки устройства пользователя %d: %v", user.ID, err)
+			middleware.ErrorJSON(c, http.StatusServiceUnavailable, gin.H{"error": "Не удалось проверить устройство, повторите вход позже"})
+			return
+		}
+		if confirmationID != "" {
+			c.JSON(http.StatusAccepted, models.DeviceConfirmationResponse{
+				ConfirmationRequired: true,
+				ConfirmationID:       confirmationID,
+				ExpiresIn:            int(deviceService.CodeTTL().Seconds()),
+			})
+			return
+		}
+
+		// 4. Успешный ответ с JWT токеном и токеном обновления
+		respondToken(c, sessionService, user)
+	}
+}
+
+// ConfirmLogin godoc
+// @Summary Подтверждение входа с нового устройства
+// @Description Проверяет код из письма и выдает JWT токен и токен обновления; устройство запоминается как доверенное.
+// @Description После нескольких неверных кодов подтверждение аннулируется, нужно войти заново
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body models.ConfirmDeviceRequest true "Идентификатор подтверждения и код"
+// @Success 200 {object} models.LoginResponse "Успешный ответ с токеном"
+// @Failure 400 {object} models.ErrorResponse "Некорректный запрос"
+// @Failure 401 {object} models.ErrorResponse "Неверный код или подтверждение истекло"
+// @Failure 429 {object} models.ErrorResponse "Слишком много попыток с IP адреса (заголовок Retry-After)"
+// @Failure 500 {object} models.ErrorResponse "Внутренняя ошибка"
+// @Router /login/confirm [post]
+func ConfirmLogin(deviceService *services.DeviceService, sessionService *services.SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.ConfirmDeviceRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.ConfirmationID == "" || req.Code == "" {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		user, err := deviceService.ConfirmLogin(c.Request.Context(), req.ConfirmationID, req.Code)
+		switch {
+		case errors.Is(err, services.ErrConfirmationNotFound), errors.Is(err, services.ErrInvalidConfirmationCode):
+			middleware.ErrorJSON(c, http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка подтверждения входа: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка подтверждения входа"})
+			return
+		}
+
+		respondToken(c, sessionService, user)
+	}
+}
+
+// RefreshToken godoc
+// @Summary Обновление JWT токена
+// @Description Выдает новый JWT токен по токену обновления без повторного входа. Токен обновления одноразовый:
+// @Description в ответе следующий токен той же сессии, срок которого продлевается на REFRESH_TOKEN_IDLE_TTL,
+// @Description но не дальше REFRESH_SESSION_MAX_LIFETIME от входа по паролю. Повторное использование
+// @Description замененного токена отзывает сессию
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body models.RefreshTokenRequest true "Токен обновления"
+// @Param X-Tenant-ID header string false "Арендатор (бренд); по умолчанию определяется по хосту"
+// @Success 200 {object} models.LoginResponse "Новый JWT токен и следующий токен обновления"
+// @Failure 400 {object} models.ErrorResponse "Некорректный запрос"
+// @Failure 401 {object} models.ErrorResponse "Токен недействителен (code refresh_token_invalid) или сессия истекла (code session_expired)"
+// @Failure 500 {object} models.ErrorResponse "Внутренняя ошибка"
+// @Router /token/refresh [post]
+func RefreshToken(sessionService *services.SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.RefreshTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		response, err := sessionService.Refresh(c.Request.Context(), req.RefreshToken)
+		switch {
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			middleware.ErrorJSON(c, http.StatusUnauthorized, gin.H{"error": err.Error(), "code": "refresh_token_invalid"})
+		case errors.Is(err, services.ErrSessionExpired):
+			middleware.ErrorJSON(c, http.StatusUnauthorized, gin.H{"error": err.Error(), "code": "session_expired"})
+		case err != nil:
+			log.Printf("Ошибка обновления токена: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка обновления токена"})
+		default:
+			c.JSON(http.StatusOK, response)
+		}
+	}
+}
+
+// ListDevices godoc
+// @Summary Устройства пользователя
+// @Description Возвращает устройства, с которых выполнялся вход, начиная с последнего
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Device
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /devices [get]
+func ListDevices(deviceService *services.DeviceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		devices, err := deviceService.ListDevices(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка получения устройств пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения устройств"})
+			return
+		}
+
+		c.JSON(http.StatusOK, devices)
+	}
+}
+
+// respondToken начинает сессию пользователя: JWT токен и токен обновления
+func respondToken(c *gin.Context, sessionService *services.SessionService, user *models.User) {
+	response, err := sessionService.Start(c.Request.Context(), user)
+	if err != nil {
+		log.Printf("Ошибка выдачи токенов пользователю %d: %v", user.ID, err)
+		middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка генерации токена"})
+		return
 	}
+	c.JSON(http.StatusOK, response)
 }
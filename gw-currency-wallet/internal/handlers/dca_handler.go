@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// CreateDCAPlan godoc
+// @Summary Создание плана регулярных обменов
+// @Description Создает план обмена фиксированной суммы по расписанию (например, 50 USD в EUR каждый день в 12:00, 30 обменов).
+// @Description Обмен при недостатке средств пропускается с уведомлением
+// @Tags Exchange
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body models.CreateDCAPlanRequest true "Параметры плана"
+// @Success 201 {object} models.DCAPlan
+// @Failure 400 {object} models.ErrorResponse "Некорректные параметры плана"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Слишком много активных планов"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /exchange/dca [post]
+func CreateDCAPlan(dcaService *services.DCAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateDCAPlanRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		plan, err := dcaService.Create(c.Request.Context(), userID, request)
+		switch {
+		case errors.Is(err, services.ErrInvalidDCAPlan):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrDCAPlanLimit):
+			middleware.ErrorJSON(c, http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка создания плана регулярных обменов пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка создания плана"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, plan)
+	}
+}
+
+// ListDCAPlans godoc
+// @Summary Планы регулярных обменов
+// @Description Возвращает планы пользователя от новых к старым
+// @Tags Exchange
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string][]models.DCAPlan "Планы (plans)"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /exchange/dca [get]
+func ListDCAPlans(dcaService *services.DCAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		plans, err := dcaService.List(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка получения планов регулярных обменов пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения планов"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"plans": plans})
+	}
+}
+
+// GetDCAPlanProgress godoc
+// @Summary Ход выполнения плана регулярных обменов
+// @Description Возвращает план, его обмены с результатами, количество оставшихся обменов, итоги и средний курс
+// @Tags Exchange
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID плана"
+// @Success 200 {object} models.DCAPlanProgress
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "План не найден"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /exchange/dca/{id} [get]
+func GetDCAPlanProgress(dcaService *services.DCAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID плана"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		progress, err := dcaService.Progress(c.Request.Context(), userID, id)
+		switch {
+		case errors.Is(err, storage.ErrDCAPlanNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": "План регулярных обменов не найден"})
+			return
+		case err != nil:
+			log.Printf("Ошибка получения плана регулярных обменов %d: %v", id, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения плана"})
+			return
+		}
+
+		c.JSON(http.StatusOK, progress)
+	}
+}
+
+// CancelDCAPlan godoc
+// @Summary Отмена плана регулярных обменов
+// @Description Отменяет активный план; выполненные обмены не отменяются
+// @Tags Exchange
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID плана"
+// @Success 200 {object} models.DCAPlan "Отмененный план"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "План не найден или уже завершен"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /exchange/dca/{id} [delete]
+func CancelDCAPlan(dcaService *services.DCAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID плана"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		plan, err := dcaService.Cancel(c.Request.Context(), userID, id)
+		switch {
+		case errors.Is(err, storage.ErrDCAPlanNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка отмены плана регулярных обменов %d пользователя %d: %v", id, userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка отмены плана"})
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
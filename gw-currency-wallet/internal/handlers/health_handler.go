@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/drain"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"net/http"
+)
+
+// Healthz - проверка работоспособности (liveness probe): 200, пока процесс обрабатывает запросы
+func Healthz() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// Readyz - готовность принимать запросы (readiness probe): 503 с состоянием models.ReadinessStatus
+// после начала вывода экземпляра из балансировки
+// Недоступность сервиса обмена не выводит экземпляр из балансировки (остальные операции работают):
+// ответ 200 с degraded=true и результатом последней проверки связи
+func Readyz(drainer *drain.Drainer, exchange *services.ExchangeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := models.ReadinessStatus{DrainStatus: drainer.Status()}
+		if exchange != nil {
+			health := exchange.Health()
+			status.Exchange = &health
+			status.Degraded = exchange.Degraded()
+		}
+		if status.State != models.DrainStateServing {
+			c.JSON(http.StatusServiceUnavailable, status)
+			return
+		}
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// StartDrain godoc
+// @Summary Вывод экземпляра из балансировки
+// @Description Переводит /readyz в 503, после паузы DRAIN_DELAY ожидает завершения выполняющихся запросов
+// @Description (не дольше DRAIN_TIMEOUT) и текущих запусков фоновых задач, новые запуски не начинаются.
+// @Description Выполняется в фоне; завершение - состояние drained в GET /admin/drain, после чего экземпляр можно останавливать.
+// @Description Действует на экземпляр, принявший запрос (то же делает сигнал SIGUSR1)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 202 {object} models.DrainStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/drain [post]
+func StartDrain(drainer *drain.Drainer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		drainer.Start()
+		c.JSON(http.StatusAccepted, drainer.Status())
+	}
+}
+
+// GetDrainStatus godoc
+// @Summary Состояние вывода из балансировки
+// @Description Возвращает состояние экземпляра (serving, draining, drained) и количество выполняющихся запросов
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.DrainStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/drain [get]
+func GetDrainStatus(drainer *drain.Drainer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, drainer.Status())
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GetTransactions godoc
+// @Summary История операций
+// @Description Возвращает операции пользователя за период (по умолчанию - последние 30 дней, не более 366 дней).
+// @Description Время и суммы дополнительно оформляются по настройкам пользователя (часовой пояс, локаль, валюта отображения)
+// @Tags Wallet
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Начало периода (RFC3339 или YYYY-MM-DD)"
+// @Param to query string false "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Количество записей (1-500, по умолчанию 50)"
+// @Success 200 {object} models.TransactionHistoryResponse
+// @Failure 400 {object} models.ErrorResponse "Некорректный период или limit"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transactions [get]
+func GetTransactions(historyService *services.HistoryService, preferencesService *services.PreferencesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, to, limit, ok := historyParams(c)
+		if !ok {
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		history, err := historyService.GetHistory(c.Request.Context(), userID, from, to, limit)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Без оформления история остается корректной (время в UTC, суммы числами)
+		if err := preferencesService.LocalizeHistory(c.Request.Context(), userID, history); err != nil {
+			log.Printf("Ошибка оформления истории пользователя %d: %v", userID, err)
+		}
+
+		c.JSON(http.StatusOK, history)
+	}
+}
+
+// ExportTransactions godoc
+// @Summary Выгрузка истории операций
+// @Description Выгружает операции пользователя за период (по умолчанию - последние 30 дней, не более 366 дней) от старых
+// @Description к новым, без ограничения количества: csv (по умолчанию), xlsx (книга Excel с оформлением и итогами
+// @Description по валютам) или jsonl (запись журнала на строку, передается потоком по мере чтения)
+// @Tags Wallet
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce application/x-ndjson
+// @Param format query string false "Формат выгрузки" Enums(csv, xlsx, jsonl)
+// @Param from query string false "Начало периода (RFC3339 или YYYY-MM-DD)"
+// @Param to query string false "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)"
+// @Success 200 {file} file "Выгрузка операций"
+// @Failure 400 {object} models.ErrorResponse "Некорректный формат или период"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transactions/export [get]
+func ExportTransactions(historyService *services.HistoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.DefaultQuery("format", services.ExportFormatCSV)
+		format, err := services.LookupExportFormat(name)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		from, to, _, ok := historyParams(c)
+		if !ok {
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+		fileName := fmt.Sprintf("transactions_%s.%s", time.Now().UTC().Format("20060102"), format.Extension)
+
+		// JSON Lines передается потоком: ошибка до первой записи возвращается кодом ответа, после - обрывает ответ.
+		// CSV и XLSX формируются целиком до отправки, чтобы клиент не получил обрезанный файл
+		var report bytes.Buffer
+		var out io.Writer = &report
+		stream := &exportStream{c: c, contentType: format.ContentType, fileName: fileName}
+		if name == services.ExportFormatJSONL {
+			out = stream
+		}
+
+		err = historyService.ExportTransactions(c.Request.Context(), userID, from, to, name, out)
+		switch {
+		case err != nil && stream.started:
+			log.Printf("Выгрузка истории операций пользователя %d прервана: %v", userID, err)
+			c.Abort()
+			return
+		case errors.Is(err, services.ErrInvalidExport):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка выгрузки истории операций пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка выгрузки истории операций"})
+			return
+		}
+
+		if _, err := stream.Write(report.Bytes()); err != nil {
+			log.Printf("Ошибка отправки выгрузки истории операций пользователя %d: %v", userID, err)
+		}
+	}
+}
+
+// exportStream пишет выгрузку в ответ, отправляя заголовки ответа при первой записи
+type exportStream struct {
+	c           *gin.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+// Write отправляет часть выгрузки клиенту
+func (s *exportStream) Write(p []byte) (int, error) {
+	if !s.started {
+		s.started = true
+		s.c.Header("Content-Type", s.contentType)
+		s.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, s.fileName))
+		s.c.Status(http.StatusOK)
+	}
+	return s.c.Writer.Write(p)
+}
+
+// GetExchangeHistory godoc
+// @Summary История обменов
+// @Description Возвращает обмены пользователя за период (по умолчанию - последние 30 дней, не более 366 дней)
+// @Description с валютной парой, списанной суммой, примененным курсом, комиссией и зачисленной суммой.
+// @Description Включает архив журнала операций (для налоговой отчетности)
+// @Tags Exchange
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Начало периода (RFC3339 или YYYY-MM-DD)"
+// @Param to query string false "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Количество обменов (1-500, по умолчанию 50)"
+// @Success 200 {object} models.ExchangeHistoryResponse
+// @Failure 400 {object} models.ErrorResponse "Некорректный период или limit"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /exchange/history [get]
+func GetExchangeHistory(historyService *services.HistoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, to, limit, ok := historyParams(c)
+		if !ok {
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		history, err := historyService.GetExchangeHistory(c.Request.Context(), userID, from, to, limit)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, history)
+	}
+}
+
+// GetActivity godoc
+// @Summary Лента активности
+// @Description Возвращает входы в систему, изменения учетной записи и настроек и операции с деньгами
+// @Description (записи журнала операций, включая архив) от новых к старым. Следующая страница
+// @Description запрашивается с курсором next_cursor предыдущей
+// @Tags Wallet
+// @Security BearerAuth
+// @Produce json
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество событий (1-200, по умолчанию 50)"
+// @Success 200 {object} models.ActivityFeed
+// @Failure 400 {object} models.ErrorResponse "Некорректный курсор или limit"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /activity [get]
+func GetActivity(activityService *services.ActivityService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := 0
+		if raw := c.Query("limit"); raw != "" {
+			var err error
+			if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+				middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр limit"})
+				return
+			}
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		feed, err := activityService.Feed(c.Request.Context(), userID, c.Query("cursor"), limit)
+		switch {
+		case errors.Is(err, storage.ErrInvalidCursor):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр cursor"})
+			return
+		case errors.Is(err, services.ErrInvalidActivityLimit):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка получения ленты активности пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения ленты активности"})
+			return
+		}
+
+		c.JSON(http.StatusOK, feed)
+	}
+}
+
+// historyParams разбирает параметры from, to и limit запроса истории
+// При ошибке отправляет ответ 400 и возвращает ok = false
+func historyParams(c *gin.Context) (from, to time.Time, limit int, ok bool) {
+	from, err := parseTimeParam(c.Query("from"))
+	if err != nil {
+		middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр from"})
+		return from, to, 0, false
+	}
+
+	to, err = parseTimeParam(c.Query("to"))
+	if err != nil {
+		middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр to"})
+		return from, to, 0, false
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр limit"})
+			return from, to, 0, false
+		}
+	}
+	return from, to, limit, true
+}
+
+// parseTimeParam разбирает дату из параметра запроса (RFC3339 или YYYY-MM-DD)
+// Пустая строка возвращает нулевое время
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
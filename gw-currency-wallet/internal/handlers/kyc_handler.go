@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"net/http"
+)
+
+// GetKYCStatus godoc
+// @Summary Статус верификации
+// @Description Возвращает статус верификации (none, pending, verified, rejected), комментарий администратора и поданные документы
+// @Tags KYC
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.KYCStatusResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /kyc [get]
+func GetKYCStatus(kycService *services.KYCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		status, err := kycService.GetStatus(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка получения статуса верификации пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения статуса верификации"})
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// SubmitKYCDocument godoc
+// @Summary Подача документа на верификацию
+// @Description Загружает документ (JPEG, PNG или PDF) и переводит пользователя в статус pending. После отказа документы можно подать повторно
+// @Tags KYC
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param type formData string true "Тип документа (passport, id_card, driver_license, proof_of_address, selfie)"
+// @Param file formData file true "Файл документа"
+// @Success 201 {object} models.KYCDocument
+// @Failure 400 {object} models.ErrorResponse "Некорректный тип или формат документа"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Пользователь уже верифицирован"
+// @Failure 413 {object} models.ErrorResponse "Файл слишком большой"
+// @Router /kyc/documents [post]
+func SubmitKYCDocument(kycService *services.KYCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Ограничиваем тело запроса, чтобы не принимать заведомо слишком большие файлы
+		// (запас на служебные поля multipart)
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, kycService.MaxDocumentSize()+1<<20)
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				middleware.ErrorJSON(c, http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrDocumentTooLarge.Error()})
+				return
+			}
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Не передан файл документа"})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Не удалось прочитать файл документа"})
+			return
+		}
+		defer file.Close()
+
+		userID := c.MustGet("userID").(int)
+
+		doc, err := kycService.SubmitDocument(
+			c.Request.Context(),
+			userID,
+			c.PostForm("type"),
+			fileHeader.Filename,
+			file,
+		)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrKYCTransition):
+				middleware.ErrorJSON(c, http.StatusConflict, gin.H{"error": "Пользователь уже верифицирован"})
+			case errors.Is(err, services.ErrDocumentTooLarge):
+				middleware.ErrorJSON(c, http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			default:
+				middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, doc)
+	}
+}
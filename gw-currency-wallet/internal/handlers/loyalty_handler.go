@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"net/http"
+)
+
+// GetLoyaltyStatus godoc
+// @Summary Уровень лояльности
+// @Description Возвращает уровень пользователя по объему обменов за 30 дней, скидку на комиссию и прогресс до следующего уровня
+// @Tags Exchange
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.LoyaltyStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /loyalty [get]
+func GetLoyaltyStatus(loyaltyService *services.LoyaltyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		status, err := loyaltyService.Status(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка расчета уровня лояльности пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Не удалось определить уровень лояльности"})
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// GetNotifications godoc
+// @Summary Уведомления об операциях
+// @Description Возвращает уведомления пользователя об операциях с состоянием доставки (pending, sent, failed) от новых к старым
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Количество уведомлений (по умолчанию 50, не больше 200)"
+// @Success 200 {array} models.Notification
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications [get]
+func GetNotifications(notificationService *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		notifications, err := notificationService.History(c.Request.Context(), userID, limit)
+		if err != nil {
+			log.Printf("Ошибка получения уведомлений пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения уведомлений"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+	}
+}
+
+// GetNotificationRoutes godoc
+// @Summary Правила доставки уведомлений
+// @Description Возвращает правила доставки уведомлений об операциях по каналам (пусто - каналы по умолчанию)
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.NotificationRoute
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/routes [get]
+func GetNotificationRoutes(notificationService *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		routes, err := notificationService.Routes(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка получения правил уведомлений пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения правил уведомлений"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"routes": routes})
+	}
+}
+
+// SaveNotificationRoute godoc
+// @Summary Правило доставки уведомлений в канал
+// @Description Создает или заменяет правило для канала: адрес (URL https для webhook, email), типы записей журнала
+// @Description и наименьшую сумму. С первым правилом уведомления доставляются только по правилам
+// @Tags Account
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param channel path string true "Канал (email, telegram, webhook)"
+// @Param input body models.SaveNotificationRouteRequest true "Правило"
+// @Success 200 {object} models.NotificationRoute
+// @Failure 400 {object} models.ErrorResponse "Канал не подключен или правило некорректно"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/routes/{channel} [put]
+func SaveNotificationRoute(notificationService *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.SaveNotificationRouteRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		route, err := notificationService.SaveRoute(c.Request.Context(), userID, c.Param("channel"), request)
+		if errors.Is(err, services.ErrInvalidNotificationRoute) {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Printf("Ошибка сохранения правила уведомлений пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения правила уведомлений"})
+			return
+		}
+
+		c.JSON(http.StatusOK, route)
+	}
+}
+
+// DeleteNotificationRoute godoc
+// @Summary Удаление правила доставки уведомлений
+// @Description Удаляет правило для канала; без правил уведомления доставляются в каналы по умолчанию
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Param channel path string true "Канал"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Правила для канала нет"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/routes/{channel} [delete]
+func DeleteNotificationRoute(notificationService *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		err := notificationService.DeleteRoute(c.Request.Context(), userID, c.Param("channel"))
+		switch {
+		case errors.Is(err, services.ErrNotificationRouteNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка удаления правила уведомлений пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка удаления правила уведомлений"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Правило уведомлений удалено"})
+	}
+}
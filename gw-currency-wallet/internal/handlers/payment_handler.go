@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"io"
+	"log"
+	"net/http"
+)
+
+// Заголовки подписи webhook платежного провайдера
+const (
+	WebhookTimestampHeader = "X-Webhook-Timestamp" // Время отправки (Unix, секунды)
+	WebhookNonceHeader     = "X-Webhook-Nonce"     // Одноразовое значение запроса
+	WebhookSignatureHeader = "X-Webhook-Signature" // hex(HMAC-SHA256(secret, timestamp.nonce.body))
+)
+
+// maxWebhookBody - максимальный размер тела webhook
+const maxWebhookBody = 64 << 10
+
+// PaymentWebhook godoc
+// @Summary Webhook платежного провайдера
+// @Description Принимает событие платежного провайдера и зачисляет платеж (payment.succeeded).
+// @Description Запрос подписывается HMAC-SHA256 общим секретом (PAYMENT_WEBHOOK_SECRET) от строки
+// @Description "timestamp.nonce.тело"; время отправки должно быть в окне PAYMENT_WEBHOOK_TOLERANCE,
+// @Description nonce - новым для каждой доставки. Повторная доставка события с тем же event_id
+// @Description не зачисляется и возвращает первое зачисление с duplicate=true
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Timestamp header string true "Время отправки (Unix, секунды)"
+// @Param X-Webhook-Nonce header string true "Одноразовое значение запроса"
+// @Param X-Webhook-Signature header string true "Подпись HMAC-SHA256 в hex"
+// @Param input body models.PaymentEvent true "Событие провайдера"
+// @Success 200 {object} models.PaymentCredit "Платеж зачислен (или уже был зачислен)"
+// @Success 202 {object} models.SuccessMessage "Событие без зачисления принято"
+// @Failure 400 {object} models.ErrorResponse "Некорректное событие"
+// @Failure 401 {object} models.ErrorResponse "Неверная подпись или время отправки"
+// @Failure 409 {object} models.ErrorResponse "Повторное использование nonce"
+// @Failure 422 {object} models.ErrorResponse "Кошелек не найден или заблокирован"
+// @Failure 500 {object} models.ErrorResponse "Внутренняя ошибка (провайдер должен повторить доставку)"
+// @Router /webhooks/payments [post]
+func PaymentWebhook(webhookService *services.PaymentWebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Подпись проверяется по телу без изменений, поэтому оно читается целиком до разбора
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBody))
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		err = webhookService.Verify(
+			c.Request.Context(),
+			c.GetHeader(WebhookTimestampHeader),
+			c.GetHeader(WebhookNonceHeader),
+			c.GetHeader(WebhookSignatureHeader),
+			body,
+		)
+		switch {
+		case errors.Is(err, services.ErrWebhookSignature), errors.Is(err, services.ErrWebhookExpired):
+			log.Printf("Webhook платежа с адреса %s отклонен: %v", c.ClientIP(), err)
+			middleware.ErrorJSON(c, http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrWebhookReplay):
+			log.Printf("Webhook платежа с адреса %s отклонен: %v", c.ClientIP(), err)
+			middleware.ErrorJSON(c, http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка проверки webhook платежа: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка проверки webhook"})
+			return
+		}
+
+		var event models.PaymentEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		credit, err := webhookService.Process(c.Request.Context(), event)
+		switch {
+		case errors.Is(err, services.ErrWebhookEvent):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, storage.ErrWalletUnavailable):
+			log.Printf("Платеж по событию %s не зачислен: %v", event.EventID, err)
+			middleware.ErrorJSON(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case err != nil:
+			log.Printf("Ошибка зачисления платежа по событию %s: %v", event.EventID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка зачисления платежа"})
+		case credit == nil:
+			c.JSON(http.StatusAccepted, gin.H{"message": "Событие принято без зачисления"})
+		default:
+			c.JSON(http.StatusOK, credit)
+		}
+	}
+}
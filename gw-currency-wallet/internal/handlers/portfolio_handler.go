@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"net/http"
+)
+
+// GetPortfolio godoc
+// @Summary Валютные позиции и нереализованный доход
+// @Description Для каждой валюты баланса возвращает средний курс приобретения (по всей истории операций, методом средней стоимости),
+// @Description текущую стоимость и нереализованный доход или убыток по текущим курсам.
+// @Description Без параметра in используется валюта отображения из настроек пользователя
+// @Tags Wallet
+// @Security BearerAuth
+// @Produce json
+// @Param in query string false "Валюта оценки (USD, RUB, EUR)"
+// @Success 200 {object} models.Portfolio
+// @Failure 400 {object} models.ErrorResponse "Неподдерживаемая валюта"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Курсы валют недоступны"
+// @Router /portfolio [get]
+func GetPortfolio(portfolioService *services.PortfolioService, preferencesService *services.PreferencesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		currency := c.Query("in")
+		if currency == "" {
+			currency = models.DefaultDisplayCurrency
+			if prefs, err := preferencesService.Get(c.Request.Context(), userID); err != nil {
+				log.Printf("Ошибка получения настроек пользователя %d: %v", userID, err)
+			} else {
+				currency = prefs.DefaultCurrency
+			}
+		}
+
+		portfolio, err := portfolioService.Portfolio(c.Request.Context(), userID, currency)
+		switch {
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrRatesUnavailable):
+			log.Printf("Ошибка оценки позиций пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusServiceUnavailable, gin.H{"error": "Курсы валют недоступны"})
+		case err != nil:
+			log.Printf("Ошибка оценки позиций пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка оценки позиций"})
+		default:
+			c.JSON(http.StatusOK, portfolio)
+		}
+	}
+}
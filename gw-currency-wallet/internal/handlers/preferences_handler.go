@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"net/http"
+)
+
+// GetPreferences godoc
+// @Summary Настройки пользователя
+// @Description Возвращает валюту отображения, локаль и часовой пояс пользователя
+// @Tags Wallet
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.UserPreferences
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /preferences [get]
+func GetPreferences(preferencesService *services.PreferencesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		prefs, err := preferencesService.Get(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка получения настроек пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения настроек"})
+			return
+		}
+
+		c.JSON(http.StatusOK, prefs)
+	}
+}
+
+// UpdatePreferences godoc
+// @Summary Изменение настроек пользователя
+// @Description Изменяет заданные поля: валюту отображения (USD, RUB, EUR), локаль (ru, en), часовой пояс IANA
+// @Tags Wallet
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body models.UpdatePreferencesRequest true "Новые значения настроек"
+// @Success 200 {object} models.UserPreferences
+// @Failure 400 {object} models.ErrorResponse "Недопустимое значение"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /preferences [put]
+func UpdatePreferences(preferencesService *services.PreferencesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.UpdatePreferencesRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		prefs, err := preferencesService.Update(c.Request.Context(), userID, request)
+		if errors.Is(err, services.ErrInvalidPreferences) {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Printf("Ошибка сохранения настроек пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения настроек"})
+			return
+		}
+
+		c.JSON(http.StatusOK, prefs)
+	}
+}
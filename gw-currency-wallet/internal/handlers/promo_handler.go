@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"net/http"
+)
+
+// RedeemPromo godoc
+// @Summary Активация промокода
+// @Description Зачисляет бонус по фиксированному промокоду. Процентные промокоды применяются при пополнении (поле promo_code)
+// @Tags Wallet
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body models.RedeemPromoRequest true "Промокод"
+// @Success 200 {object} models.PromoRedemption
+// @Failure 400 {object} models.ErrorResponse "Промокод не подходит к операции"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Промокод недействителен"
+// @Failure 409 {object} models.ErrorResponse "Промокод уже использован"
+// @Router /promo/redeem [post]
+func RedeemPromo(promoService *services.PromoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.RedeemPromoRequest
+		if err := c.ShouldBindJSON(&request); err != nil || request.Code == "" {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		redemption, balance, err := promoService.Redeem(c.Request.Context(), userID, request.Code)
+		if err != nil {
+			respondPromoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Промокод активирован",
+			"redemption":  redemption,
+			"new_balance": balance,
+		})
+	}
+}
+
+// respondPromoError формирует ответ на ошибку активации промокода
+func respondPromoError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrPromoUnavailable):
+		middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, storage.ErrPromoAlreadyUsed), errors.Is(err, storage.ErrPromoExists):
+		middleware.ErrorJSON(c, http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// RegisterPushToken godoc
+// @Summary Регистрация устройства для push уведомлений
+// @Description Сохраняет токен FCM или APNs установки мобильного приложения; приложение вызывает метод при каждом запуске
+// @Tags Account
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body models.RegisterPushTokenRequest true "Платформа и токен устройства"
+// @Success 200 {object} models.PushToken
+// @Failure 400 {object} models.ErrorResponse "Платформа не настроена или токен некорректен"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /push/tokens [post]
+func RegisterPushToken(pushService *services.PushService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.RegisterPushTokenRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		token, err := pushService.Register(c.Request.Context(), userID, request)
+		if errors.Is(err, services.ErrInvalidPushToken) {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Printf("Ошибка регистрации токена push уведомлений пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка регистрации устройства"})
+			return
+		}
+
+		c.JSON(http.StatusOK, token)
+	}
+}
+
+// ListPushTokens godoc
+// @Summary Устройства для push уведомлений
+// @Description Возвращает зарегистрированные токены устройств пользователя от новых к старым
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.PushToken
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /push/tokens [get]
+func ListPushTokens(pushService *services.PushService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		tokens, err := pushService.List(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка получения токенов push уведомлений пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения устройств"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+	}
+}
+
+// DeletePushToken godoc
+// @Summary Отключение push уведомлений устройства
+// @Description Удаляет токен устройства (выход из приложения на устройстве)
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID регистрации токена"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Токен не найден"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /push/tokens/{id} [delete]
+func DeletePushToken(pushService *services.PushService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID токена"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		err = pushService.Delete(c.Request.Context(), userID, id)
+		switch {
+		case errors.Is(err, services.ErrPushTokenNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка удаления токена push уведомлений пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка удаления устройства"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Push уведомления устройства отключены"})
+	}
+}
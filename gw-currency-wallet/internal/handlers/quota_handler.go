@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"net/http"
+)
+
+// GetQuota godoc
+// @Summary Квота запросов
+// @Description Возвращает тарифный план пользователя, квоту запросов и ее использование в текущем окне (запрос не расходует квоту)
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.QuotaUsage
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /quota [get]
+func GetQuota(quotaService *services.QuotaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		usage, err := quotaService.Usage(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка получения квоты запросов пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Не удалось получить квоту запросов"})
+			return
+		}
+
+		c.JSON(http.StatusOK, usage)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// GetReceipt godoc
+// @Summary Квитанция об операции
+// @Description Возвращает квитанцию об операции, в которую входит запись журнала, в JSON или PDF.
+// @Description Квитанция содержит записи операции пользователя и подписанный код проверки для публичной проверки
+// @Tags Wallet
+// @Security BearerAuth
+// @Produce json
+// @Produce application/pdf
+// @Param id path int true "ID записи журнала операций"
+// @Param format query string false "Формат квитанции" Enums(json, pdf)
+// @Success 200 {object} models.Receipt
+// @Failure 400 {object} models.ErrorResponse "Некорректный ID или формат"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Запись не найдена"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transactions/{id}/receipt [get]
+func GetReceipt(receiptService *services.ReceiptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID записи"})
+			return
+		}
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "pdf" {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Формат квитанции должен быть json или pdf"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		receipt, err := receiptService.Get(c.Request.Context(), userID, id)
+		switch {
+		case errors.Is(err, storage.ErrTransactionNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": "Запись журнала операций не найдена"})
+			return
+		case err != nil:
+			log.Printf("Ошибка формирования квитанции по записи %d пользователя %d: %v", id, userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка формирования квитанции"})
+			return
+		}
+
+		if format == "json" {
+			c.JSON(http.StatusOK, receipt)
+			return
+		}
+
+		var document bytes.Buffer
+		if err := receiptService.WritePDF(receipt, &document); err != nil {
+			log.Printf("Ошибка формирования PDF квитанции по записи %d: %v", id, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка формирования квитанции"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="receipt_%d.pdf"`, id))
+		c.Data(http.StatusOK, "application/pdf", document.Bytes())
+	}
+}
+
+// VerifyReceipt godoc
+// @Summary Проверка квитанции
+// @Description Проверяет код квитанции об операции и возвращает данные операции по журналу (без второй стороны перевода).
+// @Description Не требует аутентификации: код проверяет получатель квитанции
+// @Tags Wallet
+// @Produce json
+// @Param code path string true "Код проверки из квитанции"
+// @Success 200 {object} models.Receipt
+// @Failure 404 {object} models.ErrorResponse "Код проверки неверен"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /receipts/{code} [get]
+func VerifyReceipt(receiptService *services.ReceiptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		receipt, err := receiptService.Verify(c.Request.Context(), c.Param("code"))
+		switch {
+		case errors.Is(err, services.ErrInvalidReceiptCode):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка проверки квитанции: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка проверки квитанции"})
+			return
+		}
+
+		c.JSON(http.StatusOK, receipt)
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ExportTaxReport godoc
+// @Summary Налоговый отчет о курсовых доходах
+// @Description Выгружает в CSV обмены за календарный год (UTC), в которых продана валюта, отличная от валюты отчетности,
+// @Description с выручкой, стоимостью приобретения (FIFO или средняя стоимость - настройка сервера) и курсовым доходом или убытком.
+// @Description Стоимость приобретения рассчитывается по всей истории операций, включая архив журнала
+// @Tags Reports
+// @Security BearerAuth
+// @Produce text/csv
+// @Param year query int false "Год отчета (по умолчанию - прошлый год)"
+// @Success 200 {file} file "CSV отчет"
+// @Failure 400 {object} models.ErrorResponse "Некорректный год"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /reports/tax [get]
+func ExportTaxReport(taxService *services.TaxService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		year := time.Now().UTC().Year() - 1
+		if raw := c.Query("year"); raw != "" {
+			var err error
+			if year, err = strconv.Atoi(raw); err != nil {
+				middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр year"})
+				return
+			}
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		// Отчет формируется целиком до отправки: при ошибке клиент получит код ошибки, а не обрезанный файл
+		var report bytes.Buffer
+		err := taxService.ExportTaxReport(c.Request.Context(), userID, year, &report)
+		switch {
+		case errors.Is(err, services.ErrInvalidTaxYear):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка формирования налогового отчета пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка формирования отчета"})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tax_report_%d.csv"`, year))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", report.Bytes())
+	}
+}
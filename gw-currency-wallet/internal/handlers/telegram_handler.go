@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"net/http"
+)
+
+// CreateTelegramLink godoc
+// @Summary Код привязки Telegram
+// @Description Выдает одноразовый код привязки аккаунта Telegram; код отправляется боту командой /link в личном чате, после чего из бота доступен обмен валют
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Success 201 {object} models.TelegramLinkCode
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /telegram/link [post]
+func CreateTelegramLink(linkService *services.TelegramLinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		code, expiresAt, err := linkService.CreateCode(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка выдачи кода привязки Telegram пользователю %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Не удалось выдать код привязки"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, models.TelegramLinkCode{
+			Code:      code,
+			Command:   "/link " + code,
+			ExpiresAt: expiresAt,
+		})
+	}
+}
+
+// DeleteTelegramLink godoc
+// @Summary Отвязка Telegram
+// @Description Снимает привязку аккаунта Telegram: обмен из бота становится недоступен
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.SuccessMessage
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Аккаунт Telegram не привязан"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /telegram/link [delete]
+func DeleteTelegramLink(linkService *services.TelegramLinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		err := linkService.Unlink(c.Request.Context(), userID)
+		switch {
+		case errors.Is(err, services.ErrTelegramNotLinked):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка отвязки Telegram пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Не удалось отвязать аккаунт Telegram"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Аккаунт Telegram отвязан"})
+	}
+}
@@ -0,0 +1,7 @@
+// Package v2 содержит HTTP-обработчики /api/v2, контракт которых отличается от v1
+//
+// Обработчик появляется здесь, когда изменение нельзя внести в v1 без поломки клиентов
+// (например, формат сумм или структура ответа). Маршрут при этом регистрируется в
+// routes.registerV2 вместо общего набора routes.registerAPI, а v1 продолжает
+// обслуживаться прежним обработчиком из internal/handlers до даты отключения.
+package v2
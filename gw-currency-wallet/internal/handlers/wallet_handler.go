@@ -1,24 +1,30 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
 	"gw-currency-wallet/internal/models"
 	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // GetBalance godoc
 // @Summary Получить баланс
-// @Description Возвращает баланс пользователя по всем валютам
+// @Description Возвращает баланс пользователя по всем валютам и итог в валюте отображения из настроек пользователя
 // @Tags Wallet
-// @Security BearerAuth - Требуется JWT токен
+// @Security BearerAuth
 // @Produce json
-// @Success 200 {object} models.Balance - Успешный ответ с балансом
-// @Failure 401 {object} models.ErrorResponse - Ошибка аутентификации
-// @Failure 500 {object} models.ErrorResponse - Внутренняя ошибка сервера
-// @Router /balance [get] - GET endpoint
-func GetBalance(walletService *services.WalletService) gin.HandlerFunc {
+// @Success 200 {object} models.Balance "Успешный ответ с балансом"
+// @Failure 401 {object} models.ErrorResponse "Ошибка аутентификации"
+// @Failure 500 {object} models.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /balance [get]
+func GetBalance(walletService *services.WalletService, preferencesService *services.PreferencesService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Извлекаем userID из контекста (устанавливается middleware аутентификации)
 		userID := c.MustGet("userID").(int)
@@ -26,59 +32,139 @@ func GetBalance(walletService *services.WalletService) gin.HandlerFunc {
 		// Получаем баланс через сервисный слой
 		balance, err := walletService.GetBalance(c.Request.Context(), userID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения баланса"})
+			log.Printf("Ошибка получения баланса пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения баланса"})
 			return
 		}
 
+		// Оформление по настройкам пользователя не обязательно: без курсов возвращаем только баланс
+		response := gin.H{"balance": balance}
+		display, err := preferencesService.DisplayBalance(c.Request.Context(), userID, balance)
+		if err != nil {
+			log.Printf("Ошибка оформления баланса пользователя %d: %v", userID, err)
+		} else {
+			response["display"] = display
+		}
+
 		// Возвращаем баланс в формате JSON
-		c.JSON(http.StatusOK, gin.H{"balance": balance})
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetBalanceTotal godoc
+// @Summary Суммарная стоимость баланса
+// @Description Пересчитывает балансы во всех валютах в одну валюту по текущим курсам и возвращает итог с разбивкой по валютам.
+// @Description Без параметра in используется валюта отображения из настроек пользователя
+// @Tags Wallet
+// @Security BearerAuth
+// @Produce json
+// @Param in query string false "Валюта оценки (USD, RUB, EUR)"
+// @Success 200 {object} models.BalanceTotal
+// @Failure 400 {object} models.ErrorResponse "Неподдерживаемая валюта"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Курсы валют недоступны"
+// @Router /balance/total [get]
+func GetBalanceTotal(walletService *services.WalletService, preferencesService *services.PreferencesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+
+		currency := c.Query("in")
+		if currency == "" {
+			currency = models.DefaultDisplayCurrency
+			if prefs, err := preferencesService.Get(c.Request.Context(), userID); err != nil {
+				log.Printf("Ошибка получения настроек пользователя %d: %v", userID, err)
+			} else {
+				currency = prefs.DefaultCurrency
+			}
+		}
+
+		total, err := walletService.TotalBalance(c.Request.Context(), userID, currency)
+		switch {
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrRatesUnavailable):
+			log.Printf("Ошибка оценки баланса пользователя %d: %v", userID, err)
+			middleware.ErrorJSON(c, http.StatusServiceUnavailable, gin.H{"error": "Курсы валют недоступны"})
+		case err != nil:
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения баланса"})
+		default:
+			c.JSON(http.StatusOK, total)
+		}
 	}
 }
 
 // Deposit godoc
 // @Summary Пополнить баланс
-// @Description Пополнение баланса пользователя в указанной валюте
+// @Description Пополнение баланса пользователя в указанной валюте. С процентным промокодом (promo_code) бонус зачисляется в той же операции.
+// @Description С dry_run=true пополнение и промокод проверяются, но не выполняются: ответ содержит баланс, который получился бы после операции
 // @Tags Wallet
 // @Security BearerAuth
-// @Accept json - Ожидаем JSON в теле запроса
+// @Accept json
 // @Produce json
 // @Param input body models.DepositRequest true "Данные для пополнения"
-// @Success 200 {object} models.TransactionResponse - Ответ с новым балансом
-// @Failure 400 {object} models.ErrorResponse - Некорректный запрос
-// @Failure 401 {object} models.ErrorResponse - Ошибка аутентификации
-// @Failure 500 {object} models.ErrorResponse - Ошибка сервера
-// @Router /wallet/deposit [post] - POST endpoint
-func Deposit(walletService *services.WalletService) gin.HandlerFunc {
+// @Param dry_run query bool false "Пробный режим: все проверки и расчеты без изменения баланса"
+// @Success 200 {object} models.TransactionResponse "Ответ с новым балансом"
+// @Failure 400 {object} models.ErrorResponse "Некорректный запрос или промокод не подходит к пополнению"
+// @Failure 401 {object} models.ErrorResponse "Ошибка аутентификации"
+// @Failure 404 {object} models.ErrorResponse "Промокод недействителен"
+// @Failure 409 {object} models.ErrorResponse "Промокод уже использован"
+// @Failure 500 {object} models.ErrorResponse "Ошибка сервера"
+// @Failure 503 {object} models.ErrorResponse "Операции в валюте временно приостановлены"
+// @Router /wallet/deposit [post]
+func Deposit(walletService *services.WalletService, promoService *services.PromoService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Структура для парсинга входящего запроса
-		var request struct {
-			Amount   float64 `json:"amount"`   // Сумма пополнения
-			Currency string  `json:"currency"` // Код валюты (USD, EUR и т.д.)
-		}
-
 		// Парсим JSON тело запроса
+		var request models.DepositRequest
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		ctx, dryRun, ok := dryRunContext(c)
+		if !ok {
 			return
 		}
 
 		// Извлекаем userID из контекста
 		userID := c.MustGet("userID").(int)
 
+		// Пополнение с промокодом: бонус зачисляется в той же транзакции
+		if request.PromoCode != "" {
+			redemption, newBalance, err := promoService.DepositWithPromo(
+				ctx,
+				userID,
+				request.Currency,
+				request.Amount,
+				request.PromoCode,
+			)
+			if err != nil {
+				respondPromoError(c, err)
+				return
+			}
+
+			respondOperation(c, dryRun, gin.H{
+				"message":     "Баланс успешно пополнен",
+				"bonus":       redemption.Bonus,
+				"new_balance": newBalance,
+			})
+			return
+		}
+
 		// Вызываем сервис для пополнения баланса
 		newBalance, err := walletService.Deposit(
-			c.Request.Context(),
+			ctx,
 			userID,
 			request.Currency,
 			request.Amount,
 		)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondOperationError(c, err)
 			return
 		}
 
 		// Возвращаем успешный ответ с новым балансом
-		c.JSON(http.StatusOK, gin.H{
+		respondOperation(c, dryRun, gin.H{
 			"message":     "Баланс успешно пополнен",
 			"new_balance": newBalance,
 		})
@@ -87,16 +173,18 @@ func Deposit(walletService *services.WalletService) gin.HandlerFunc {
 
 // Withdraw godoc
 // @Summary Снять средства
-// @Description Снятие средств с баланса пользователя
+// @Description Снятие средств с баланса пользователя. С dry_run=true выполняются все проверки (средства, антифрод) без снятия
 // @Tags Wallet
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param input body models.WithdrawRequest true "Данные для снятия"
+// @Param dry_run query bool false "Пробный режим: все проверки и расчеты без изменения баланса"
 // @Success 200 {object} models.TransactionResponse
-// @Failure 400 {object} models.ErrorResponse - Недостаточно средств/некорректная валюта
+// @Failure 400 {object} models.ErrorResponse "Недостаточно средств/некорректная валюта"
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Операции в валюте временно приостановлены"
 // @Router /wallet/withdraw [post]
 func Withdraw(walletService *services.WalletService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -104,7 +192,12 @@ func Withdraw(walletService *services.WalletService) gin.HandlerFunc {
 		var request models.WithdrawRequest
 
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		ctx, dryRun, ok := dryRunContext(c)
+		if !ok {
 			return
 		}
 
@@ -112,23 +205,130 @@ func Withdraw(walletService *services.WalletService) gin.HandlerFunc {
 
 		// Вызываем сервис для снятия средств
 		newBalance, err := walletService.Withdraw(
-			c.Request.Context(),
+			ctx,
 			userID,
 			request.Currency,
 			request.Amount,
 		)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondOperationError(c, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
+		respondOperation(c, dryRun, gin.H{
 			"message":     "Средства успешно сняты",
 			"new_balance": newBalance,
 		})
 	}
 }
 
+// Transfer godoc
+// @Summary Перевод средств
+// @Description Перевод средств другому пользователю по имени пользователя. Крупные и подозрительные переводы могут быть отправлены на проверку (202) или отклонены (403).
+// @Description С dry_run=true выполняются все проверки без перевода; перевод, который был бы отправлен на проверку, в очередь не ставится
+// @Tags Wallet
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body models.TransferRequest true "Данные для перевода"
+// @Param dry_run query bool false "Пробный режим: все проверки и расчеты без изменения баланса"
+// @Success 200 {object} models.TransactionResponse
+// @Success 202 {object} models.ErrorResponse "Операция отправлена на проверку"
+// @Failure 400 {object} models.ErrorResponse "Недостаточно средств/некорректные данные"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Операция отклонена системой безопасности"
+// @Failure 503 {object} models.ErrorResponse "Операции в валюте временно приостановлены"
+// @Router /wallet/transfer [post]
+func Transfer(walletService *services.WalletService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.TransferRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+
+		ctx, dryRun, ok := dryRunContext(c)
+		if !ok {
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		newBalance, err := walletService.Transfer(
+			ctx,
+			userID,
+			request.ToUsername,
+			request.Currency,
+			request.Amount,
+		)
+		if err != nil {
+			respondOperationError(c, err)
+			return
+		}
+
+		respondOperation(c, dryRun, gin.H{
+			"message":     "Перевод выполнен",
+			"new_balance": newBalance,
+		})
+	}
+}
+
+// respondOperationError формирует ответ на ошибку операции с балансом с учетом решения антифрода:
+// отложенная операция - 202 с номером проверки, отклоненная - 403, в приостановленной валюте - 503,
+// остальные ошибки - 400
+func respondOperationError(c *gin.Context, err error) {
+	var pending *services.ReviewPendingError
+	var closed *services.TradingClosedError
+	switch {
+	case errors.As(err, &pending) && pending.ReviewID == 0:
+		// Пробный режим: операция в очередь не поставлена
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Операция была бы отправлена на проверку",
+			"reason":  pending.Reason,
+			"dry_run": true,
+		})
+	case errors.As(err, &pending):
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":   "Операция отправлена на проверку",
+			"review_id": pending.ReviewID,
+		})
+	case errors.Is(err, services.ErrOperationDenied):
+		middleware.ErrorJSON(c, http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCurrencyFrozen):
+		middleware.ErrorJSON(c, http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.As(err, &closed):
+		middleware.ErrorJSON(c, http.StatusConflict, gin.H{"error": err.Error(), "next_open": closed.NextOpen})
+	default:
+		middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// dryRunContext возвращает контекст запроса, в пробном режиме (?dry_run=true) - с признаком storage.WithDryRun
+// Некорректное значение параметра завершает запрос ответом 400 (ok = false)
+func dryRunContext(c *gin.Context) (ctx context.Context, dryRun bool, ok bool) {
+	ctx = c.Request.Context()
+	if raw := c.Query("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный параметр dry_run"})
+			return nil, false, false
+		}
+	}
+	if dryRun {
+		ctx = storage.WithDryRun(ctx)
+	}
+	return ctx, dryRun, true
+}
+
+// respondOperation отправляет результат операции с балансом; в пробном режиме ответ помечается dry_run
+func respondOperation(c *gin.Context, dryRun bool, response gin.H) {
+	if dryRun {
+		response["message"] = "Пробный режим: проверки пройдены, операция не выполнена"
+		response["dry_run"] = true
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 // GetExchangeRates godoc
 // @Summary Получить курсы валют
 // @Description Возвращает текущие курсы обмена валют
@@ -137,13 +337,13 @@ func Withdraw(walletService *services.WalletService) gin.HandlerFunc {
 // @Produce json
 // @Success 200 {object} models.ExchangeRatesResponse
 // @Failure 401 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse - Сервис обмена недоступен
+// @Failure 500 {object} models.ErrorResponse "Сервис обмена недоступен"
 // @Router /exchange/rates [get]
 func GetExchangeRates(exchangeService *services.ExchangeService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Проверка инициализации сервиса
 		if exchangeService == nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{
 				"error":   "Сервис обмена не инициализирован",
 				"message": "Сервис обмена недоступен",
 			})
@@ -154,7 +354,7 @@ func GetExchangeRates(exchangeService *services.ExchangeService) gin.HandlerFunc
 		rates, err := exchangeService.GetRates(c.Request.Context())
 		if err != nil {
 			log.Printf("Ошибка получения курсов валют: %v", err)
-			c.JSON(http.StatusServiceUnavailable, gin.H{
+			middleware.ErrorJSON(c, http.StatusServiceUnavailable, gin.H{
 				"error":   "Ошибка получения курсов валют",
 				"message": "Сервис обмена недоступен",
 				"details": err.Error(),
@@ -168,41 +368,219 @@ func GetExchangeRates(exchangeService *services.ExchangeService) gin.HandlerFunc
 
 // ExchangeCurrency godoc
 // @Summary Обмен валют
-// @Description Обменивает указанную сумму из одной валюты в другую по текущему курсу
+// @Description Обменивает указанную сумму из одной валюты в другую по текущему курсу.
+// @Description С dry_run=true курс, комиссия и достаточность средств проверяются без обмена.
+// @Description Вне окна исполнения пары обмен отклоняется (409) или с outside_hours=queue ставится в очередь
+// @Description до открытия окна (202) и исполняется по курсу на момент открытия.
+// @Description С execute=next_rate обмен ставится в очередь (202) и исполняется по следующему курсу, опубликованному сервисом обмена
 // @Tags Exchange
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param input body models.ExchangeRequest true "Данные для обмена"
-// @Success 200 {object} models.ExchangeResponse - Результат обмена
-// @Failure 400 {object} models.ErrorResponse - Недостаточно средств/некорректные данные
+// @Param dry_run query bool false "Пробный режим: все проверки и расчеты без изменения баланса"
+// @Success 200 {object} models.ExchangeResponse "Результат обмена"
+// @Success 202 {object} models.QueuedExchange "Обмен поставлен в очередь до открытия окна или публикации курса"
+// @Failure 400 {object} models.ErrorResponse "Недостаточно средств/некорректные данные"
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Обмен пары вне окна исполнения"
+// @Failure 429 {object} models.ErrorResponse "Слишком много обменов в очереди"
 // @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Операции в валюте временно приостановлены"
 // @Router /exchange [post]
 func ExchangeCurrency(
-	walletService *services.WalletService) gin.HandlerFunc {
+	walletService *services.WalletService,
+	queueService *services.ExchangeQueueService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var request models.ExchangeRequest
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный запрос"})
+			return
+		}
+		if request.OutsideHours != "" && request.OutsideHours != models.OutsideHoursReject &&
+			request.OutsideHours != models.OutsideHoursQueue {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "outside_hours должен быть reject или queue"})
+			return
+		}
+		if request.Execute != "" && request.Execute != models.ExecuteNow && request.Execute != models.ExecuteNextRate {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "execute должен быть now или next_rate"})
+			return
+		}
+
+		ctx, dryRun, ok := dryRunContext(c)
+		if !ok {
 			return
 		}
 
 		userID := c.MustGet("userID").(int)
 
+		// Обмен по следующему курсу исполняется после публикации курса, проверки - при исполнении
+		if request.Execute == models.ExecuteNextRate {
+			queueExchange(ctx, c, queueService, userID, request, models.QueuedExchangeTriggerRate, time.Now(), dryRun)
+			return
+		}
+
 		// Выполняем обмен через сервисный слой
 		response, err := walletService.Exchange(
-			c.Request.Context(),
+			ctx,
 			userID,
 			request.FromCurrency,
 			request.ToCurrency,
 			request.Amount,
 		)
+		var closed *services.TradingClosedError
+		if errors.As(err, &closed) && closed.NextOpen != nil && request.OutsideHours == models.OutsideHoursQueue {
+			queueExchange(ctx, c, queueService, userID, request, models.QueuedExchangeTriggerWindow, *closed.NextOpen, dryRun)
+			return
+		}
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondOperationError(c, err)
 			return
 		}
 
+		if dryRun {
+			response.Message = "Пробный режим: обмен рассчитан, но не выполнен"
+			response.DryRun = true
+		}
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+// queueExchange ставит обмен в очередь до наступления условия исполнения и отвечает 202
+func queueExchange(
+	ctx context.Context,
+	c *gin.Context,
+	queueService *services.ExchangeQueueService,
+	userID int,
+	request models.ExchangeRequest,
+	trigger string,
+	executeAfter time.Time,
+	dryRun bool,
+) {
+	queued, err := queueService.Enqueue(ctx, userID, request.FromCurrency, request.ToCurrency, request.Amount,
+		trigger, executeAfter)
+	switch {
+	case errors.Is(err, services.ErrExchangeQueueFull):
+		middleware.ErrorJSON(c, http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrInvalidQueuedExchange):
+		middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("Ошибка постановки обмена в очередь: %v", err)
+		middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка постановки обмена в очередь"})
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":       "Обмен был бы поставлен в очередь",
+			"trigger":       queued.Trigger,
+			"execute_after": queued.ExecuteAfter,
+			"dry_run":       true,
+		})
+		return
+	}
+	c.JSON(http.StatusAccepted, queued)
+}
+
+// GetQueuedExchanges godoc
+// @Summary Обмены в очереди
+// @Description Возвращает обмены пользователя, отложенные до открытия окна исполнения пары или публикации курса,
+// @Description с результатом исполнения
+// @Tags Exchange
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.QueuedExchange
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /exchange/queued [get]
+func GetQueuedExchanges(queueService *services.ExchangeQueueService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		exchanges, err := queueService.List(c.Request.Context(), c.MustGet("userID").(int))
+		if err != nil {
+			log.Printf("Ошибка получения обменов в очереди: %v", err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка получения обменов в очереди"})
+			return
+		}
+		c.JSON(http.StatusOK, exchanges)
+	}
+}
+
+// CancelQueuedExchange godoc
+// @Summary Отмена обмена в очереди
+// @Description Отменяет обмен, ожидающий открытия окна исполнения или публикации курса. Исполняющийся обмен не отменяется
+// @Tags Exchange
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID обмена в очереди"
+// @Success 200 {object} models.QueuedExchange "Отмененный обмен"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Обмен не найден или уже исполняется"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /exchange/queued/{id} [delete]
+func CancelQueuedExchange(queueService *services.ExchangeQueueService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": "Некорректный ID обмена"})
+			return
+		}
+
+		userID := c.MustGet("userID").(int)
+
+		exchange, err := queueService.Cancel(c.Request.Context(), userID, id)
+		switch {
+		case errors.Is(err, storage.ErrQueuedExchangeNotFound):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка отмены обмена %d пользователя %d: %v", id, userID, err)
+			middleware.ErrorJSON(c, http.StatusInternalServerError, gin.H{"error": "Ошибка отмены обмена"})
+			return
+		}
+
+		c.JSON(http.StatusOK, exchange)
+	}
+}
+
+// GetExchangeChart godoc
+// @Summary График курса валют
+// @Description Возвращает свечи курса пары валют (открытие, максимум, минимум, закрытие) за период до текущего момента по истории курсов сервиса обмена
+// @Tags Exchange
+// @Security BearerAuth
+// @Produce json
+// @Param pair query string true "Пара валют (например, USD/RUB)"
+// @Param interval query string false "Длительность свечи: 15m, 1h, 1d (по умолчанию 1h)"
+// @Param range query string false "Период графика: 24h, 7d, 30d (по умолчанию 7d)"
+// @Success 200 {object} models.RateChart
+// @Failure 400 {object} models.ErrorResponse "Некорректные параметры графика"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Нет истории курса за период"
+// @Failure 503 {object} models.ErrorResponse "Сервис обмена недоступен"
+// @Router /exchange/chart [get]
+func GetExchangeChart(exchangeService *services.ExchangeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		chart, err := exchangeService.GetChart(
+			c.Request.Context(),
+			c.Query("pair"),
+			c.DefaultQuery("interval", "1h"),
+			c.DefaultQuery("range", "7d"),
+		)
+		switch {
+		case errors.Is(err, services.ErrInvalidChart):
+			middleware.ErrorJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrNoRateHistory):
+			middleware.ErrorJSON(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			log.Printf("Ошибка получения графика курса: %v", err)
+			middleware.ErrorJSON(c, http.StatusServiceUnavailable, gin.H{"error": "Сервис обмена недоступен"})
+			return
+		}
+
+		c.JSON(http.StatusOK, chart)
+	}
+}
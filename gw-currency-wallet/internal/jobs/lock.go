@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// lockKeyPrefix - префикс ключей блокировок задач
+const lockKeyPrefix = "jobs:lock:"
+
+// Locker - распределенная блокировка задач между экземплярами сервиса
+// Задачу выполняет только экземпляр, владеющий ее блокировкой; пока он работает,
+// блокировка продлевается, и остальные экземпляры пропускают запуски
+type Locker interface {
+	// Acquire захватывает блокировку или продлевает свою; false - блокировкой владеет другой экземпляр
+	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Extend продлевает свою блокировку; false - блокировка потеряна
+	Extend(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Release снимает свою блокировку
+	Release(ctx context.Context, key string) error
+}
+
+// lockTTL возвращает время жизни блокировки задачи
+// Блокировка переживает интервал запуска с запасом, чтобы владелец сохранял ее между запусками;
+// после остановки владельца задачу подхватывает другой экземпляр не позже чем через полтора интервала
+func lockTTL(job Job) time.Duration {
+	return job.Interval + job.Interval/2
+}
+
+// holdLock продлевает блокировку задачи, пока выполняется запуск
+// Если блокировка потеряна (например, Redis был недоступен дольше времени жизни), запуск отменяется,
+// чтобы задача не выполнялась одновременно на двух экземплярах
+// Возвращает контекст запуска и функцию остановки продления
+func holdLock(ctx context.Context, locker Locker, job Job) (context.Context, context.CancelFunc) {
+	runCtx, cancel := context.WithCancel(ctx)
+	ttl := lockTTL(job)
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+				held, err := locker.Extend(runCtx, lockKeyPrefix+job.Name, ttl)
+				if err == nil && !held {
+					log.Printf("Блокировка задачи %s потеряна, запуск прерван", job.Name)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return runCtx, func() {
+		close(done)
+		cancel()
+	}
+}
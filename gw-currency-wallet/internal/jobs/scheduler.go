@@ -0,0 +1,123 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job описывает периодическую фоновую задачу
+type Job struct {
+	Name     string                          // Имя задачи (для логов)
+	Interval time.Duration                   // Интервал между запусками
+	Run      func(ctx context.Context) error // Тело задачи
+}
+
+// Scheduler запускает фоновые задачи с заданными интервалами
+// Каждая задача выполняется в своей горутине; запуски одной задачи не пересекаются.
+// С распределенной блокировкой задача выполняется только на одном из экземпляров сервиса
+type Scheduler struct {
+	jobs     []Job
+	locker   Locker // Блокировка задач между экземплярами (nil - один экземпляр)
+	wg       sync.WaitGroup
+	stop     chan struct{} // Закрывается Stop: новые запуски не начинаются
+	stopOnce sync.Once
+}
+
+// NewScheduler создает пустой планировщик
+// Параметры:
+//   - locker: распределенная блокировка задач (nil - задачи выполняются на каждом экземпляре)
+func NewScheduler(locker Locker) *Scheduler {
+	return &Scheduler{locker: locker, stop: make(chan struct{})}
+}
+
+// Add регистрирует задачу (до вызова Start)
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start запускает все зарегистрированные задачи
+// Первый запуск выполняется сразу, следующие - через Interval.
+// Задачи останавливаются при отмене ctx
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			log.Printf("Задача %s отключена (интервал не задан)", job.Name)
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Stop запрещает новые запуски задач, не прерывая текущие (вывод экземпляра из балансировки)
+// Дождаться текущих запусков можно вызовом Wait
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Wait ожидает завершения всех задач после отмены контекста или вызова Stop
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// loop выполняет задачу до отмены контекста
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		s.runJob(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runJob выполняет один запуск задачи и логирует результат
+// Если задан locker, запуск выполняется только при владении блокировкой задачи
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	if s.locker != nil {
+		key := lockKeyPrefix + job.Name
+		held, err := s.locker.Acquire(ctx, key, lockTTL(job))
+		if err != nil {
+			log.Printf("Задача %s пропущена: ошибка блокировки: %v", job.Name, err)
+			return
+		}
+		if !held {
+			return // Задачу выполняет другой экземпляр
+		}
+
+		runCtx, stop := holdLock(ctx, s.locker, job)
+		defer stop()
+		ctx = runCtx
+	}
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Printf("Ошибка выполнения задачи %s: %v", job.Name, err)
+		if s.locker != nil {
+			// Неудачный запуск освобождает блокировку: следующим может выполнить задачу другой экземпляр
+			if err := s.locker.Release(context.WithoutCancel(ctx), lockKeyPrefix+job.Name); err != nil {
+				log.Printf("Ошибка снятия блокировки задачи %s: %v", job.Name, err)
+			}
+		}
+		return
+	}
+	log.Printf("Задача %s выполнена за %s", job.Name, time.Since(start).Round(time.Millisecond))
+}
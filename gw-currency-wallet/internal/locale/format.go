@@ -0,0 +1,100 @@
+// Package locale форматирует суммы и время по настройкам пользователя
+package locale
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Поддерживаемые локали
+const (
+	RU = "ru" // Русская: 1 234,50 $
+	EN = "en" // Английская: $1,234.50
+)
+
+// currencySymbols - символы поддерживаемых валют
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"RUB": "₽",
+}
+
+// timeLayouts - формат даты и времени для каждой локали
+var timeLayouts = map[string]string{
+	RU: "02.01.2006 15:04:05 MST",
+	EN: "Jan 2, 2006 3:04:05 PM MST",
+}
+
+// Supported сообщает, поддерживается ли локаль
+func Supported(loc string) bool {
+	_, ok := timeLayouts[loc]
+	return ok
+}
+
+// FormatAmount форматирует сумму в валюте по правилам локали
+// Неизвестная локаль форматируется как русская
+// Параметры:
+//   - amount: сумма
+//   - currency: код валюты (USD, EUR, RUB)
+//   - loc: локаль (ru, en)
+//
+// Возвращает:
+//   - string: сумма с разделителями разрядов и символом валюты
+func FormatAmount(amount float64, currency, loc string) string {
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		symbol = currency
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	cents := int64(math.Round(amount * 100))
+	whole := strconv.FormatInt(cents/100, 10)
+	fraction := strconv.FormatInt(100+cents%100, 10)[1:] // Всегда две цифры
+
+	if loc == EN {
+		return sign + symbol + groupDigits(whole, ",") + "." + fraction
+	}
+	return sign + groupDigits(whole, " ") + "," + fraction + " " + symbol
+}
+
+// FormatTime форматирует время в часовом поясе пользователя по правилам локали
+// Параметры:
+//   - t: момент времени
+//   - tz: часовой пояс пользователя
+//   - loc: локаль (ru, en)
+//
+// Возвращает:
+//   - string: дата и время в часовом поясе пользователя
+func FormatTime(t time.Time, tz *time.Location, loc string) string {
+	layout, ok := timeLayouts[loc]
+	if !ok {
+		layout = timeLayouts[RU]
+	}
+	return t.In(tz).Format(layout)
+}
+
+// groupDigits разделяет целую часть числа на группы по три цифры
+func groupDigits(digits, separator string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
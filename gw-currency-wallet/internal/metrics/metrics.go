@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"          // Клиент Prometheus
+	"github.com/prometheus/client_golang/prometheus/promauto" // Автоматическая регистрация метрик
+	"github.com/prometheus/client_golang/prometheus/promhttp" // HTTP обработчик для /metrics
+	"go.opentelemetry.io/otel/trace"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Метрики сервиса кошелька
+var (
+	// ReconciliationMismatches - количество расхождений (кошелек и валюта) в последней сверке
+	ReconciliationMismatches = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "wallet_reconciliation_mismatches",
+		Help: "Количество расхождений балансов с журналом в последней сверке",
+	})
+
+	// ReconciliationDiscrepancy - суммарная абсолютная разница балансов по валютам в последней сверке
+	ReconciliationDiscrepancy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "wallet_reconciliation_discrepancy_amount",
+		Help: "Суммарная абсолютная разница балансов с журналом по валютам в последней сверке",
+	}, []string{"currency"})
+
+	// ReconciliationLastRun - время окончания последней успешной сверки (unix)
+	ReconciliationLastRun = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "wallet_reconciliation_last_run_timestamp_seconds",
+		Help: "Время окончания последней успешной сверки балансов",
+	})
+
+	// ReconciliationFailures - количество неудачных запусков сверки
+	ReconciliationFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "wallet_reconciliation_failures_total",
+		Help: "Количество запусков сверки балансов, завершившихся ошибкой",
+	})
+
+	// QuarantinedWallets - количество кошельков, заблокированных по итогам сверки
+	QuarantinedWallets = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "wallet_reconciliation_quarantined_total",
+		Help: "Количество кошельков, заблокированных по итогам сверки",
+	})
+
+	// ChaosFaults - количество сбоев, внедренных режимом chaos (по зависимости и виду сбоя)
+	ChaosFaults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wallet_chaos_faults_total",
+		Help: "Количество задержек и ошибок, внедренных режимом chaos",
+	}, []string{"target", "kind"})
+
+	// BalanceCacheRequests - обращения к кэшу балансов по результату (hit - баланс из кэша, miss - из БД,
+	// bypass - из БД без обращения к кэшу, пока сброс балансов не доставлен)
+	BalanceCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wallet_balance_cache_requests_total",
+		Help: "Количество чтений баланса через кэш по результату (hit/miss/bypass)",
+	}, []string{"result"})
+
+	// MultiStepOperations - многошаговые денежные операции по виду и конечному состоянию (completed, compensated, failed)
+	MultiStepOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wallet_multistep_operations_total",
+		Help: "Количество многошаговых денежных операций по виду и конечному состоянию",
+	}, []string{"kind", "status"})
+
+	// BalanceTxRetries - повторы транзакций изменения баланса по причине (serialization_failure, deadlock)
+	BalanceTxRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wallet_balance_tx_retries_total",
+		Help: "Количество повторов транзакций изменения баланса после конфликта сериализации или взаимной блокировки",
+	}, []string{"reason"})
+
+	// Notifications - попытки отправки уведомлений об операциях по каналу и результату (sent, retry, failed)
+	Notifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wallet_notifications_total",
+		Help: "Количество попыток отправки уведомлений пользователям об операциях по каналу и результату",
+	}, []string{"channel", "result"})
+)
+
+// SLO-метрики: доля успешных операций с деньгами, их задержка и свежесть курсов обмена
+// Наблюдения сопровождаются exemplar с trace_id (идентификатор трассировки OpenTelemetry,
+// без трассировки - идентификатор запроса X-Request-ID), который связывает выброс на графике с трассировкой
+// и записями журнала запроса
+var (
+	// MoneyOperations - операции с деньгами по исходу: success - выполнена (2xx),
+	// rejected - отклонена из-за запроса или баланса (4xx), error - ошибка сервиса (5xx)
+	MoneyOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wallet_money_operations_total",
+		Help: "Количество операций с деньгами (deposit, withdraw, transfer, exchange) по исходу",
+	}, []string{"operation", "outcome"})
+
+	// MoneyOperationDuration - длительность обработки операций с деньгами (для p99)
+	MoneyOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wallet_money_operation_duration_seconds",
+		Help:    "Длительность обработки операций с деньгами",
+		Buckets: []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"operation"})
+
+	// ExchangeRateAge - возраст курса (время с его получения от сервиса обмена) при расчете обмена
+	ExchangeRateAge = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "wallet_exchange_rate_age_seconds",
+		Help:    "Возраст курса при расчете обмена",
+		Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+	})
+
+	// ExchangeRatesFetched - время получения курсов, использованных последними (unix)
+	// Устаревание курсов: time() - wallet_exchange_rates_fetched_timestamp_seconds
+	ExchangeRatesFetched = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "wallet_exchange_rates_fetched_timestamp_seconds",
+		Help: "Время получения от сервиса обмена курсов, использованных последними",
+	})
+)
+
+// ObserveMoneyOperation учитывает операцию с деньгами: исход и длительность с exemplar trace_id
+// Операция учитывается в метриках Prometheus и OpenTelemetry (см. otel.go)
+// Параметры:
+//   - ctx: контекст запроса (span OpenTelemetry для exemplar)
+//   - operation: операция (deposit, withdraw, transfer, exchange)
+//   - outcome: исход (success, rejected, error)
+//   - duration: длительность обработки
+//   - requestID: идентификатор запроса для exemplar, если запрос не трассируется (пусто - без exemplar)
+func ObserveMoneyOperation(ctx context.Context, operation, outcome string, duration time.Duration, requestID string) {
+	traceID := exemplarTraceID(ctx, requestID)
+	counter := MoneyOperations.WithLabelValues(operation, outcome)
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok && traceID != "" {
+		adder.AddWithExemplar(1, prometheus.Labels{"trace_id": traceID})
+	} else {
+		counter.Inc()
+	}
+	observeWithTrace(MoneyOperationDuration.WithLabelValues(operation), duration.Seconds(), traceID)
+	recordMoneyOperation(ctx, operation, outcome, duration)
+}
+
+// ObserveExchangeRateAge учитывает возраст курса, по которому рассчитан обмен
+func ObserveExchangeRateAge(ctx context.Context, age time.Duration, requestID string) {
+	observeWithTrace(ExchangeRateAge, age.Seconds(), exemplarTraceID(ctx, requestID))
+	recordExchangeRateAge(ctx, age)
+}
+
+// SetExchangeRatesFetched учитывает время получения курсов, использованных последними
+func SetExchangeRatesFetched(ctx context.Context, fetchedAt time.Time) {
+	ExchangeRatesFetched.Set(float64(fetchedAt.Unix()))
+	recordExchangeRatesFetched(ctx, fetchedAt)
+}
+
+// exemplarTraceID возвращает trace_id для exemplar: идентификатор трассировки OpenTelemetry,
+// если запрос трассируется, иначе идентификатор запроса
+func exemplarTraceID(ctx context.Context, requestID string) string {
+	if span := trace.SpanContextFromContext(ctx); span.IsSampled() {
+		return span.TraceID().String()
+	}
+	return requestID
+}
+
+// observeWithTrace записывает наблюдение гистограммы с exemplar trace_id
+func observeWithTrace(observer prometheus.Observer, value float64, traceID string) {
+	if exemplar, ok := observer.(prometheus.ExemplarObserver); ok && traceID != "" {
+		exemplar.ObserveWithExemplar(value, prometheus.Labels{"trace_id": traceID})
+		return
+	}
+	observer.Observe(value)
+}
+
+// Serve запускает HTTP сервер с эндпоинтом /metrics (блокирующая операция)
+// Если адрес пустой, метрики не публикуются
+// Exemplar передаются только в формате OpenMetrics (Accept: application/openmetrics-text),
+// который запрашивают Prometheus с включенным exemplar-storage и OpenTelemetry Collector
+func Serve(addr string) {
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	))
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	log.Printf("Метрики доступны на %s/metrics", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Ошибка сервера метрик: %v", err)
+	}
+}
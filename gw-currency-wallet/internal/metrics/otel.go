@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"log"
+	"time"
+)
+
+// meterName - имя источника SLO-метрик OpenTelemetry
+const meterName = "gw-currency-wallet/internal/metrics"
+
+// SLO-метрики OpenTelemetry (экспортируются в OpenTelemetry Collector, если он настроен, см. пакет telemetry)
+// Повторяют SLO-метрики Prometheus; exemplar со span текущего запроса добавляет SDK
+// Инструменты создаются через глобальный MeterProvider до его настройки и начинают передавать
+// измерения после вызова telemetry.Setup
+var (
+	otelMoneyOperations        metric.Int64Counter
+	otelMoneyOperationDuration metric.Float64Histogram
+	otelExchangeRateAge        metric.Float64Histogram
+	otelExchangeRatesFetched   metric.Int64Gauge
+)
+
+func init() {
+	meter := otel.Meter(meterName)
+	var err error
+	if otelMoneyOperations, err = meter.Int64Counter("wallet.money_operations",
+		metric.WithDescription("Количество операций с деньгами (deposit, withdraw, transfer, exchange) по исходу"),
+		metric.WithUnit("{operation}"),
+	); err != nil {
+		log.Printf("Ошибка создания метрики OpenTelemetry wallet.money_operations: %v", err)
+	}
+	if otelMoneyOperationDuration, err = meter.Float64Histogram("wallet.money_operation.duration",
+		metric.WithDescription("Длительность обработки операций с деньгами"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10),
+	); err != nil {
+		log.Printf("Ошибка создания метрики OpenTelemetry wallet.money_operation.duration: %v", err)
+	}
+	if otelExchangeRateAge, err = meter.Float64Histogram("wallet.exchange_rate.age",
+		metric.WithDescription("Возраст курса при расчете обмена"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600),
+	); err != nil {
+		log.Printf("Ошибка создания метрики OpenTelemetry wallet.exchange_rate.age: %v", err)
+	}
+	if otelExchangeRatesFetched, err = meter.Int64Gauge("wallet.exchange_rates.fetched_timestamp",
+		metric.WithDescription("Время получения от сервиса обмена курсов, использованных последними"),
+		metric.WithUnit("s"),
+	); err != nil {
+		log.Printf("Ошибка создания метрики OpenTelemetry wallet.exchange_rates.fetched_timestamp: %v", err)
+	}
+}
+
+// recordMoneyOperation записывает исход и длительность операции с деньгами в метрики OpenTelemetry
+func recordMoneyOperation(ctx context.Context, operation, outcome string, duration time.Duration) {
+	if otelMoneyOperations != nil {
+		otelMoneyOperations.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("operation", operation),
+			attribute.String("outcome", outcome),
+		))
+	}
+	if otelMoneyOperationDuration != nil {
+		otelMoneyOperationDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attribute.String("operation", operation)))
+	}
+}
+
+// recordExchangeRateAge записывает возраст курса обмена в метрики OpenTelemetry
+func recordExchangeRateAge(ctx context.Context, age time.Duration) {
+	if otelExchangeRateAge != nil {
+		otelExchangeRateAge.Record(ctx, age.Seconds())
+	}
+}
+
+// recordExchangeRatesFetched записывает время получения курсов в метрики OpenTelemetry
+func recordExchangeRatesFetched(ctx context.Context, fetchedAt time.Time) {
+	if otelExchangeRatesFetched != nil {
+		otelExchangeRatesFetched.Record(ctx, fetchedAt.Unix())
+	}
+}
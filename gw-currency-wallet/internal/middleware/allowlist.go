@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseNetworks разбирает список адресов и подсетей CIDR
+// Отдельный адрес ("10.0.0.5") считается подсетью из одного адреса
+// Параметры:
+//   - values: адреса и подсети ("10.0.0.0/8", "192.168.1.10", "2001:db8::/32")
+//
+// Возвращает:
+//   - []*net.IPNet: подсети
+//   - error: некорректный адрес или подсеть
+func ParseNetworks(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("некорректный адрес %q", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная подсеть %q", value)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// AllowNetworks - middleware, пропускающее запросы только с адресов из заданных подсетей
+// Адрес клиента определяется с учетом доверенных прокси (TRUSTED_PROXIES).
+// Пустой список подсетей не ограничивает доступ
+func AllowNetworks(networks []*net.IPNet) gin.HandlerFunc {
+	if len(networks) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		ip := net.ParseIP(c.ClientIP())
+		for _, network := range networks {
+			if ip != nil && network.Contains(ip) {
+				c.Next()
+				return
+			}
+		}
+		log.Printf("Запрос %s %s с адреса %s отклонен: адрес не входит в разрешенные подсети",
+			c.Request.Method, c.Request.URL.Path, c.ClientIP())
+		AbortWithErrorJSON(c, http.StatusForbidden, gin.H{"error": "Доступ с этого адреса запрещен"})
+	}
+}
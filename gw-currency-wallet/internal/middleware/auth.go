@@ -2,118 +2,194 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"     // Веб-фреймворк Gin
 	"github.com/golang-jwt/jwt/v5" // JWT реализация
+	"gw-currency-wallet/internal/tenant"
 	"net/http"
 	"strings"
 	"time"
 )
 
 // JWTClaims - кастомная структура claims для JWT токена
-// Содержит ID пользователя и стандартные зарегистрированные claims
+// Содержит ID, роль и арендатора пользователя и стандартные зарегистрированные claims
 type JWTClaims struct {
-	UserID               int `json:"user_id"` // ID пользователя - основная информация в токене
-	jwt.RegisteredClaims     // Стандартные claims (exp, iat и др.)
+	UserID               int    `json:"user_id"`             // ID пользователя - основная информация в токене
+	Role                 string `json:"role,omitempty"`      // Роль пользователя (user, admin)
+	TenantID             string `json:"tenant_id,omitempty"` // Арендатор пользователя (пусто - арендатор по умолчанию)
+	jwt.RegisteredClaims        // Стандартные claims (exp, iat и др.)
 }
 
+// JWTOptions - параметры проверки JWT токенов
+type JWTOptions struct {
+	Secret string        // Секретный ключ для проверки подписи
+	Leeway time.Duration // Допустимое расхождение часов при проверке срока действия (exp, nbf, iat)
+}
+
+// Коды ошибок аутентификации (поле code ответа 401)
+const (
+	TokenErrorMissing   = "token_missing"           // Нет заголовка Authorization или неверный формат
+	TokenErrorMalformed = "token_malformed"         // Токен поврежден или не является JWT
+	TokenErrorSignature = "token_signature_invalid" // Подпись не совпадает или неожиданный алгоритм подписи
+	TokenErrorExpired   = "token_expired"           // Срок действия истек (с учетом допуска расхождения часов)
+	TokenErrorInvalid   = "token_invalid"           // Прочие нарушения claims (нет exp, токен еще не действует)
+	TokenErrorTenant    = "token_wrong_tenant"      // Токен выдан другому арендатору
+)
+
+// Ошибки проверки JWT токена (parseToken)
+var (
+	ErrTokenMalformed = errors.New("токен поврежден или имеет неверный формат")
+	ErrTokenSignature = errors.New("неверная подпись токена")
+	ErrTokenExpired   = errors.New("срок действия токена истек")
+	ErrTokenInvalid   = errors.New("неверные данные токена")
+)
+
 // JWTAuthMiddleware - middleware для JWT аутентификации
-// Принимает секретный ключ для верификации токенов
+// Принимает секретный ключ и допуск расхождения часов для верификации токенов
 // Возвращает Gin-обработчик, который:
 // 1. Проверяет наличие и формат токена
 // 2. Валидирует подпись и срок действия
-// 3. Добавляет userID в контекст при успешной аутентификации
-func JWTAuthMiddleware(secret string) gin.HandlerFunc {
+// 3. Проверяет, что токен выдан арендатору запроса (см. Tenant)
+// 4. Добавляет userID в контекст при успешной аутентификации
+//
+// Ответ 401 содержит код ошибки (TokenError*): клиент отличает истекший токен (нужен повторный вход)
+// от поврежденного или подписанного чужим ключом
+func JWTAuthMiddleware(opts JWTOptions) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 1. Извлечение токена из заголовка Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Требуется заголовок Authorization",
-			})
+			abortUnauthorized(c, TokenErrorMissing, "Требуется заголовок Authorization")
 			return
 		}
 
 		// 2. Проверка формата: "Bearer <token>"
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Неверный формат заголовка Authorization",
-			})
+			abortUnauthorized(c, TokenErrorMissing, "Неверный формат заголовка Authorization")
 			return
 		}
 
 		tokenString := tokenParts[1] // Сам токен без префикса
 
-		// 3. Парсинг и валидация токена
-		claims, err := parseToken(tokenString, secret)
+		// 3. Парсинг и валидация токена: подпись и срок действия с учетом допуска расхождения часов
+		claims, err := parseToken(tokenString, opts)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Неверный токен: " + err.Error(),
-			})
+			abortUnauthorized(c, tokenErrorCode(err), "Неверный токен: "+err.Error())
 			return
 		}
 
-		// 4. Проверка срока действия токена
-		if time.Now().After(claims.ExpiresAt.Time) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Срок действия токена истек",
-			})
+		// 4. Токен действует только у арендатора, которому выдан (токены без арендатора - у арендатора по умолчанию)
+		tenantID := claims.TenantID
+		if tenantID == "" {
+			tenantID = tenant.DefaultID
+		}
+		if tenantID != tenant.IDFromContext(c.Request.Context()) {
+			abortUnauthorized(c, TokenErrorTenant, "Токен выдан другому арендатору")
 			return
 		}
 
-		// 5. Успешная аутентификация - добавляем userID в контекст
+		// 5. Успешная аутентификация - добавляем userID и роль в контекст
 		c.Set("userID", claims.UserID)
+		c.Set("role", claims.Role)
 
 		// Передаем управление следующему обработчику
 		c.Next()
 	}
 }
 
+// RequireRole - middleware для проверки роли пользователя
+// Подключается после JWTAuthMiddleware, который помещает роль в контекст
+// Возвращает 403, если роль пользователя не совпадает с требуемой
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != role {
+			AbortWithErrorJSON(c, http.StatusForbidden, gin.H{
+				"error": "Недостаточно прав",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// abortUnauthorized прерывает запрос ответом 401 с кодом и описанием ошибки аутентификации
+func abortUnauthorized(c *gin.Context, code, message string) {
+	AbortWithErrorJSON(c, http.StatusUnauthorized, gin.H{
+		"error": message,
+		"code":  code,
+	})
+}
+
+// tokenErrorCode возвращает код ответа 401 для ошибки parseToken
+func tokenErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrTokenMalformed):
+		return TokenErrorMalformed
+	case errors.Is(err, ErrTokenSignature):
+		return TokenErrorSignature
+	case errors.Is(err, ErrTokenExpired):
+		return TokenErrorExpired
+	default:
+		return TokenErrorInvalid
+	}
+}
+
 // parseToken - внутренняя функция для парсинга и валидации JWT токена
+// Срок действия (exp) обязателен и проверяется библиотекой jwt с допуском opts.Leeway
 // Принимает:
 // - tokenString: строка с JWT токеном
-// - secret: секретный ключ для проверки подписи
+// - opts: секретный ключ и допуск расхождения часов
 // Возвращает:
 // - *JWTClaims: распарсенные claims при успехе
-// - error: ошибку при неудачной проверке
-func parseToken(tokenString, secret string) (*JWTClaims, error) {
+// - error: ErrTokenMalformed, ErrTokenSignature, ErrTokenExpired или ErrTokenInvalid
+func parseToken(tokenString string, opts JWTOptions) (*JWTClaims, error) {
 	// Парсим токен с указанием структуры для claims
-	token, err := jwt.ParseWithClaims(
+	claims := &JWTClaims{}
+	_, err := jwt.ParseWithClaims(
 		tokenString,
-		&JWTClaims{},
+		claims,
 		func(token *jwt.Token) (interface{}, error) {
 			// Проверяем, что используется ожидаемый алгоритм подписи
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("неожиданный метод подписи")
+				return nil, fmt.Errorf("неожиданный метод подписи %v", token.Header["alg"])
 			}
-			return []byte(secret), nil // Возвращаем ключ для верификации
+			return []byte(opts.Secret), nil // Возвращаем ключ для верификации
 		},
+		jwt.WithLeeway(opts.Leeway),
+		jwt.WithExpirationRequired(),
 	)
 
-	if err != nil {
-		return nil, err
-	}
-
-	// Проверяем валидность claims
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+	switch {
+	case err == nil:
 		return claims, nil
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return nil, ErrTokenMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return nil, ErrTokenSignature
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrTokenExpired
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrTokenInvalid, err)
 	}
-
-	return nil, errors.New("неверные данные токена")
 }
 
 // GenerateJWTToken - генерирует новый JWT токен
 // Принимает:
 // - userID: идентификатор пользователя
+// - role: роль пользователя
+// - tenantID: арендатор пользователя
 // - secret: секретный ключ для подписи
 // - expiration: время жизни токена
 // Возвращает:
 // - string: подписанный токен
 // - error: ошибку при генерации
-func GenerateJWTToken(userID int, secret string, expiration time.Duration) (string, error) {
-	// Создаем claims с userID и временем expiration
+func GenerateJWTToken(userID int, role, tenantID, secret string, expiration time.Duration) (string, error) {
+	// Создаем claims с userID, ролью, арендатором и временем expiration
 	claims := JWTClaims{
-		UserID: userID,
+		UserID:   userID,
+		Role:     role,
+		TenantID: tenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 		},
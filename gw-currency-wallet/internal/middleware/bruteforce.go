@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AttemptGuard учитывает попытки с IP адреса и сообщает о его блокировке
+type AttemptGuard interface {
+	// Attempt возвращает время снятия блокировки адреса (нулевое значение - попытка разрешена)
+	Attempt(ctx context.Context, ip string) (time.Time, error)
+}
+
+// BruteForce - middleware защиты от подбора паролей по IP адресу клиента
+// Заблокированному адресу возвращается 429 с заголовком Retry-After.
+// Если хранилище счетчиков недоступно, запрос пропускается (защита не должна блокировать вход)
+func BruteForce(guard AttemptGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		bannedUntil, err := guard.Attempt(c.Request.Context(), ip)
+		if err != nil {
+			log.Printf("Ошибка защиты от подбора паролей для %s: %v", ip, err)
+			c.Next()
+			return
+		}
+		if !bannedUntil.IsZero() {
+			retryAfter := int(math.Ceil(time.Until(bannedUntil).Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			AbortWithErrorJSON(c, http.StatusTooManyRequests, gin.H{
+				"error": "Слишком много попыток, повторите позже",
+			})
+			return
+		}
+		c.Next()
+	}
+}
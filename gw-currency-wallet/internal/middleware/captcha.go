@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+// CaptchaHeader - заголовок с ответом пользователя на CAPTCHA (токен виджета hCaptcha/reCAPTCHA)
+const CaptchaHeader = "X-Captcha-Token"
+
+// CaptchaGuard проверяет CAPTCHA и учитывает результаты входа
+type CaptchaGuard interface {
+	// Enabled сообщает, настроена ли проверка CAPTCHA
+	Enabled() bool
+	// RequiredForLogin сообщает, требуется ли CAPTCHA для входа с адреса
+	RequiredForLogin(ctx context.Context, ip string) bool
+	// Verify проверяет ответ пользователя; ошибка - провайдер CAPTCHA недоступен
+	Verify(ctx context.Context, token, ip string) (bool, error)
+	// LoginFailed учитывает неудачный вход с адреса
+	LoginFailed(ctx context.Context, ip string)
+	// LoginSucceeded сбрасывает счетчик неудачных входов адреса
+	LoginSucceeded(ctx context.Context, ip string)
+}
+
+// RequireCaptcha - middleware, требующее верный ответ на CAPTCHA (регистрация)
+// Если CAPTCHA не настроена, запросы пропускаются
+func RequireCaptcha(guard CaptchaGuard) gin.HandlerFunc {
+	if !guard.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		if !verifyCaptcha(c, guard) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// LoginCaptcha - middleware входа: после неудачных попыток с адреса вход требует CAPTCHA
+// Результат входа определяется по статусу ответа обработчика (401 - неудачный вход,
+// 200 и 202 - пароль верный, 202 - требуется подтверждение нового устройства)
+func LoginCaptcha(guard CaptchaGuard) gin.HandlerFunc {
+	if !guard.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		ctx, ip := c.Request.Context(), c.ClientIP()
+		if guard.RequiredForLogin(ctx, ip) && !verifyCaptcha(c, guard) {
+			return
+		}
+
+		c.Next()
+
+		switch c.Writer.Status() {
+		case http.StatusOK, http.StatusAccepted:
+			guard.LoginSucceeded(ctx, ip)
+		case http.StatusUnauthorized:
+			guard.LoginFailed(ctx, ip)
+		}
+	}
+}
+
+// verifyCaptcha проверяет ответ из заголовка X-Captcha-Token и при отказе завершает запрос
+// Возвращает true, если проверка пройдена
+func verifyCaptcha(c *gin.Context, guard CaptchaGuard) bool {
+	token := c.GetHeader(CaptchaHeader)
+	ok, err := guard.Verify(c.Request.Context(), token, c.ClientIP())
+	switch {
+	case err != nil:
+		log.Printf("Ошибка проверки CAPTCHA: %v", err)
+		AbortWithErrorJSON(c, http.StatusServiceUnavailable, gin.H{"error": "Сервис проверки CAPTCHA недоступен"})
+		return false
+	case !ok && token == "":
+		AbortWithErrorJSON(c, http.StatusForbidden, gin.H{"error": "Требуется пройти проверку CAPTCHA", "captcha_required": true})
+		return false
+	case !ok:
+		AbortWithErrorJSON(c, http.StatusForbidden, gin.H{"error": "Проверка CAPTCHA не пройдена", "captcha_required": true})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// Deprecation - middleware для устаревшей версии API
+// Добавляет в ответы заголовки, по которым клиенты узнают о переходе на новую версию:
+//   - Deprecation: true - версия устарела
+//   - Sunset: дата отключения версии (RFC 8594), если она объявлена
+//   - Link: адрес версии-преемника с rel="successor-version"
+//
+// Параметры:
+//   - sunset: дата отключения (нулевое значение - дата не объявлена)
+//   - successor: префикс новой версии API (например, /api/v2)
+func Deprecation(sunset time.Time, successor string) gin.HandlerFunc {
+	sunsetHeader := ""
+	if !sunset.IsZero() {
+		sunsetHeader = sunset.UTC().Format(http.TimeFormat)
+	}
+	link := "<" + successor + `>; rel="successor-version"`
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		if sunsetHeader != "" {
+			c.Header("Sunset", sunsetHeader)
+		}
+		c.Header("Link", link)
+		c.Next()
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/drain"
+)
+
+// InFlight - middleware, учитывающее выполняющиеся запросы для вывода экземпляра из балансировки
+// (Drainer ожидает их завершения перед остановкой)
+func InFlight(d *drain.Drainer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d.Enter()
+		defer d.Leave()
+		c.Next()
+	}
+}
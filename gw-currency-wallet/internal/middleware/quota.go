@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/models"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// QuotaCounter учитывает запросы пользователя в квоте его тарифного плана
+type QuotaCounter interface {
+	// Consume учитывает запрос и сообщает, укладывается ли он в квоту
+	Consume(ctx context.Context, userID int) (*models.QuotaUsage, bool, error)
+}
+
+// Quota - middleware квот запросов по тарифному плану пользователя (подключается после JWTAuthMiddleware)
+// Ответ содержит заголовки X-RateLimit-Limit, X-RateLimit-Remaining и X-RateLimit-Reset (Unix-время).
+// Превышение квоты - 429 с заголовком Retry-After. Если хранилище счетчиков недоступно, запрос пропускается
+func Quota(counter QuotaCounter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int)
+		usage, allowed, err := counter.Consume(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("Ошибка учета квоты запросов пользователя %d: %v", userID, err)
+			c.Next()
+			return
+		}
+		if usage.Limit == 0 {
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.FormatInt(usage.Limit, 10))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(usage.Remaining, 10))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(usage.ResetAt.Unix(), 10))
+		if !allowed {
+			retryAfter := int(math.Ceil(time.Until(usage.ResetAt).Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			AbortWithErrorJSON(c, http.StatusTooManyRequests, gin.H{
+				"error": "Превышена квота запросов тарифного плана, повторите позже",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted - значение, которым заменяются секреты в журнале
+const redacted = "[REDACTED]"
+
+// sensitiveKeys - подстроки имен полей, значения которых не попадают в журнал (пароли, токены, ключи)
+var sensitiveKeys = []string{"password", "token", "secret", "authorization", "api_key", "apikey", "cookie"}
+
+// sensitiveHeaders - заголовки, значения которых не попадают в журнал
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+var (
+	emailPattern  = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._\-]+`)
+	secretPattern = regexp.MustCompile(`(?i)((?:password|token|secret|api_?key)[a-z_]*=)[^&\s]*`)
+)
+
+// isSensitiveKey сообщает, содержит ли имя поля секрет
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskEmail оставляет от адреса первую букву и домен: j***@example.com
+func maskEmail(email string) string {
+	return emailPattern.ReplaceAllString(email, "$1***@$2")
+}
+
+// redactText маскирует секреты и адреса в произвольном тексте
+func redactText(s string) string {
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	s = secretPattern.ReplaceAllString(s, "${1}"+redacted)
+	return maskEmail(s)
+}
+
+// redactValue рекурсивно маскирует значения разобранного JSON
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if isSensitiveKey(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+		return v
+	case string:
+		return redactText(v)
+	default:
+		return v
+	}
+}
+
+// redactBody маскирует тело запроса или ответа
+// JSON разбирается и маскируется по именам полей, остальной текст - по шаблонам
+func redactBody(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return ""
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber() // Числа выводятся без потери точности
+	var parsed any
+	if err := decoder.Decode(&parsed); err == nil && !decoder.More() {
+		if out, err := json.Marshal(redactValue(parsed)); err == nil {
+			return string(out)
+		}
+	}
+	return redactText(string(trimmed))
+}
+
+// redactQuery маскирует параметры строки запроса
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redactText(rawQuery)
+	}
+	for key, vals := range values {
+		for i := range vals {
+			if isSensitiveKey(key) {
+				vals[i] = redacted
+			} else {
+				vals[i] = redactText(vals[i])
+			}
+		}
+	}
+	encoded := values.Encode()
+	if decoded, err := url.QueryUnescape(encoded); err == nil {
+		return decoded // Журнал читает человек, экранирование только мешает
+	}
+	return encoded
+}
+
+// redactHeaders возвращает копию заголовков с замаскированными секретами
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, redacted)
+		}
+	}
+	return out
+}
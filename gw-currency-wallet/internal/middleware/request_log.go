@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// RequestLogOptions - параметры журнала HTTP запросов и ответов
+type RequestLogOptions struct {
+	SamplePercent float64 // Доля успешных запросов (в процентах), попадающих в журнал
+	LogErrors     bool    // Всегда записывать запросы, завершившиеся ошибкой (статус 4xx/5xx)
+	MaxBodyBytes  int     // Максимальный размер тела запроса и ответа в журнале (больше - обрезается)
+	LogHeaders    bool    // Записывать заголовки запроса (секреты маскируются)
+}
+
+// RequestLog - middleware журнала HTTP запросов и ответов для отладки в production
+// Пароли, токены и ключи заменяются на [REDACTED], email маскируются (j***@example.com)
+// Тела записываются только для JSON и текстовых запросов (файлы документов KYC не попадают в журнал)
+func RequestLog(opts RequestLogOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sampled := opts.SamplePercent > 0 && rand.Float64()*100 < opts.SamplePercent
+		if !sampled && !opts.LogErrors {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		reqBody := captureRequestBody(c.Request, opts.MaxBodyBytes)
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer, limit: opts.MaxBodyBytes}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		if !sampled && status < http.StatusBadRequest {
+			return
+		}
+
+		line := fmt.Sprintf("HTTP %s %s %d %s ip=%s trace_id=%s",
+			c.Request.Method, c.Request.URL.Path, status, time.Since(start).Round(time.Millisecond), c.ClientIP(), GetTraceID(c))
+		if userID, ok := c.Get("userID"); ok {
+			line += fmt.Sprintf(" user=%v", userID)
+		}
+		if query := redactQuery(c.Request.URL.RawQuery); query != "" {
+			line += " query=" + query
+		}
+		if opts.LogHeaders {
+			line += fmt.Sprintf(" headers=%v", redactHeaders(c.Request.Header))
+		}
+		if reqBody != "" {
+			line += " request=" + reqBody
+		}
+		if respBody := formatBody(writer.body.Bytes(), writer.Header().Get("Content-Type"), writer.Size(), opts.MaxBodyBytes); respBody != "" {
+			line += " response=" + respBody
+		}
+		log.Println(line)
+	}
+}
+
+// captureRequestBody читает начало тела запроса для журнала и возвращает тело обработчику целиком
+func captureRequestBody(r *http.Request, limit int) string {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ""
+	}
+	contentType := r.Header.Get("Content-Type")
+	if !loggableContent(contentType) {
+		return formatBody(nil, contentType, int(r.ContentLength), limit)
+	}
+
+	head, _ := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	return formatBody(head, contentType, len(head), limit)
+}
+
+// formatBody маскирует тело для журнала; тела нетекстовых типов заменяются описанием
+func formatBody(body []byte, contentType string, size, limit int) string {
+	if !loggableContent(contentType) {
+		if size <= 0 {
+			return ""
+		}
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		return fmt.Sprintf("[%s, %d байт]", mediaType, size)
+	}
+	if len(body) > limit {
+		return redactText(string(body[:limit])) + "...[обрезано]"
+	}
+	return redactBody(body)
+}
+
+// loggableContent сообщает, можно ли записать тело с таким типом в журнал
+func loggableContent(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/json", "application/problem+json", "application/x-www-form-urlencoded", "text/plain":
+		return true
+	}
+	return false
+}
+
+// bodyCaptureWriter сохраняет начало тела ответа для журнала
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer // Начало тела ответа
+	limit int          // Сколько байт сохранять (с запасом в один байт, чтобы отметить обрезку)
+}
+
+// Write передает данные клиенту и сохраняет их начало
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString передает строку клиенту и сохраняет ее начало
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// capture сохраняет не больше limit+1 байт ответа
+func (w *bodyCaptureWriter) capture(b []byte) {
+	if room := w.limit + 1 - w.body.Len(); room > 0 {
+		if len(b) > room {
+			b = b[:room]
+		}
+		w.body.Write(b)
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/metrics"
+	"strconv"
+	"time"
+)
+
+// MoneyOperation - middleware SLO-метрик операции с деньгами: исход по статусу ответа
+// и длительность обработки с exemplar trace_id (трассировка OpenTelemetry или идентификатор запроса)
+// Пробные запросы (?dry_run=true) не учитываются: они не меняют баланс
+// Параметры:
+//   - operation: имя операции в метриках (deposit, withdraw, transfer, exchange)
+func MoneyOperation(operation string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if dryRun, _ := strconv.ParseBool(c.Query("dry_run")); dryRun {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		outcome := "success"
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			outcome = "error"
+		case status >= 400:
+			outcome = "rejected"
+		}
+		metrics.ObserveMoneyOperation(c.Request.Context(), operation, outcome, time.Since(start), GetTraceID(c))
+	}
+}
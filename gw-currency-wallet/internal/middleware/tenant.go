@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/tenant"
+	"net/http"
+)
+
+// tenantIDKey - ключ арендатора в контексте Gin
+const tenantIDKey = "tenantID"
+
+// Tenant - middleware, определяющее арендатора запроса по заголовку X-Tenant-ID или хосту
+// Арендатор передается в контекст Gin и в контекст запроса (для сервисов); JWTAuthMiddleware
+// отклоняет токены, выданные другому арендатору. Неизвестный арендатор в заголовке - ответ 400
+func Tenant(registry *tenant.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, err := registry.Resolve(c.GetHeader(tenant.Header), c.Request.Host)
+		if err != nil {
+			AbortWithErrorJSON(c, http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.Set(tenantIDKey, t.ID)
+		c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), t.ID))
+		c.Next()
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// versionPrefix - префикс версии API, не входящий в ключ таймаута маршрута
+var versionPrefix = regexp.MustCompile(`^/api/v[0-9]+`)
+
+// Timeout - middleware, ограничивающее время обработки запроса
+// Таймаут маршрута ищется в routes по ключу "МЕТОД /путь" (путь без префикса версии, например
+// "POST /exchange" или "DELETE /admin/wallets/:user_id/quarantine"), иначе используется fallback.
+// Нулевой таймаут отключает ограничение
+//
+// Обработчик выполняется с контекстом запроса, у которого установлен срок; запросы к БД и сервису
+// обмена прерываются по нему. Ответ обработчика буферизуется: если срок истек, а обработчик так и не ответил,
+// клиент получает 504 в формате JSON. Ответ, записанный обработчиком, отправляется как есть и после истечения
+// срока: операция могла быть зафиксирована, и 504 вызвал бы ее повтор клиентом
+func Timeout(fallback time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := fallback
+		if d, ok := routes[c.Request.Method+" "+versionPrefix.ReplaceAllString(c.FullPath(), "")]; ok {
+			timeout = d
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		buffer := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = buffer
+		defer func() { c.Writer = original }() // При панике Recovery пишет ответ в исходный writer
+
+		c.Next()
+
+		c.Writer = original
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !buffer.responded() {
+			AbortWithErrorJSON(c, http.StatusGatewayTimeout, gin.H{
+				"error": "Превышено время обработки запроса",
+			})
+			return
+		}
+		buffer.flush()
+	}
+}
+
+// bufferedWriter накапливает ответ обработчика до завершения обработки
+// Заголовки пишутся сразу в исходный writer: при таймауте Content-Type все равно перезаписывается
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status    int          // Статус ответа обработчика
+	statusSet bool         // Обработчик установил статус ответа
+	written   bool         // Обработчик начал ответ
+	body      bytes.Buffer // Тело ответа
+}
+
+// WriteHeader запоминает статус ответа
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status, w.statusSet = code, true
+	}
+}
+
+// WriteHeaderNow отмечает начало ответа
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+// Write накапливает тело ответа
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+// WriteString накапливает тело ответа
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+// Status возвращает статус ответа обработчика
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+// Size возвращает размер накопленного тела (-1, если ответ не начат)
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+// Written сообщает, начал ли обработчик ответ
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// responded сообщает, ответил ли обработчик (статусом или телом)
+func (w *bufferedWriter) responded() bool {
+	return w.written || w.statusSet
+}
+
+// Flush не отправляет данные до завершения обработки (ответ буферизуется целиком)
+func (w *bufferedWriter) Flush() {}
+
+// flush передает накопленный ответ в исходный writer
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.written {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const timeout = 20 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+	}{
+		{
+			name:       "ответ до истечения срока",
+			handler:    func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "обработчик не ответил до истечения срока",
+			handler: func(c *gin.Context) {
+				<-c.Request.Context().Done()
+			},
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name: "операция зафиксирована, ответ записан после истечения срока",
+			handler: func(c *gin.Context) {
+				<-c.Request.Context().Done()
+				c.JSON(http.StatusCreated, gin.H{"ok": true})
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "статус без тела после истечения срока",
+			handler: func(c *gin.Context) {
+				<-c.Request.Context().Done()
+				c.Status(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(Timeout(timeout, nil))
+			router.POST("/api/v1/deposit", tt.handler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/deposit", nil))
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("статус %d, ожидался %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestTimeoutRouteOverride(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(time.Millisecond, map[string]time.Duration{"GET /transactions/export": 0}))
+	router.GET("/api/v2/transactions/export", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); ok {
+			t.Error("у маршрута без таймаута установлен срок")
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v2/transactions/export", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("статус %d, ожидался %d", recorder.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+)
+
+// TraceIDHeader - заголовок с идентификатором запроса (принимается от клиента или прокси и возвращается в ответе)
+const TraceIDHeader = "X-Request-ID"
+
+// traceIDKey - ключ идентификатора запроса в контексте Gin
+const traceIDKey = "traceID"
+
+// traceIDContextKey - ключ идентификатора запроса в контексте запроса (для передачи в сервисы и gRPC)
+type traceIDContextKey struct{}
+
+// validTraceID ограничивает идентификаторы, принимаемые от клиента (защита журнала от мусора)
+var validTraceID = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,64}$`)
+
+// TraceID - middleware, присваивающее запросу идентификатор для поиска в журнале
+// Идентификатор берется из заголовка X-Request-ID или генерируется и возвращается клиенту в том же заголовке
+func TraceID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(TraceIDHeader)
+		if !validTraceID.MatchString(id) {
+			id = newTraceID()
+		}
+		c.Set(traceIDKey, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), traceIDContextKey{}, id))
+		c.Header(TraceIDHeader, id)
+		c.Next()
+	}
+}
+
+// AccessLog - журнал запросов Gin (формат gin.Logger) с идентификатором запроса trace_id
+// Идентификатор присваивается TraceID, подключенным после журнала, и читается по завершении запроса
+func AccessLog() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(p gin.LogFormatterParams) string {
+		traceID, _ := p.Keys[traceIDKey].(string)
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v trace_id=%s\n%s",
+			p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			p.StatusCode,
+			p.Latency.Round(time.Microsecond),
+			p.ClientIP,
+			p.Method,
+			p.Path,
+			traceID,
+			p.ErrorMessage,
+		)
+	})
+}
+
+// GetTraceID возвращает идентификатор текущего запроса (пустая строка, если TraceID не подключен)
+func GetTraceID(c *gin.Context) string {
+	return c.GetString(traceIDKey)
+}
+
+// ErrorJSON отправляет ответ об ошибке body с идентификатором запроса в поле trace_id:
+// по нему служба поддержки находит записи журнала и трассировку запроса
+func ErrorJSON(c *gin.Context, status int, body gin.H) {
+	c.JSON(status, withTraceID(c, body))
+}
+
+// AbortWithErrorJSON прерывает обработку запроса ответом об ошибке body с идентификатором запроса в поле trace_id
+func AbortWithErrorJSON(c *gin.Context, status int, body gin.H) {
+	c.AbortWithStatusJSON(status, withTraceID(c, body))
+}
+
+// withTraceID добавляет в тело ответа идентификатор текущего запроса (если TraceID подключен)
+func withTraceID(c *gin.Context, body gin.H) gin.H {
+	if id := GetTraceID(c); id != "" {
+		body["trace_id"] = id
+	}
+	return body
+}
+
+// TraceIDFromContext возвращает идентификатор запроса из контекста запроса (пустая строка, если его нет)
+func TraceIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDContextKey{}).(string)
+	return id
+}
+
+// newTraceID генерирует случайный идентификатор из 16 байт в hex
+func newTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Recovery - middleware, превращающее панику обработчика в ответ 500 в формате JSON
+// Стек вызовов записывается в журнал вместе с идентификатором запроса, клиент получает только идентификатор
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec) // Штатное прерывание ответа обрабатывает net/http
+			}
+
+			traceID := GetTraceID(c)
+			log.Printf("ПАНИКА при обработке %s %s (trace_id=%s): %v\n%s",
+				c.Request.Method, c.Request.URL.Path, traceID, rec, debug.Stack())
+
+			if c.Writer.Written() {
+				c.Abort() // Ответ уже начат, заменить его нельзя
+				return
+			}
+			AbortWithErrorJSON(c, http.StatusInternalServerError, gin.H{
+				"error": "Внутренняя ошибка сервера",
+			})
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// tracerName - имя трассировщика HTTP-запросов кошелька
+const tracerName = "gw-currency-wallet/internal/middleware"
+
+// Tracing - middleware трассировки OpenTelemetry: span сервера на запрос с именем по шаблону маршрута
+// Контекст трассировки принимается из заголовка traceparent (трассировка клиента или прокси продолжается).
+// Span передается обработчику в контексте запроса: от него наследуются span вызовов сервиса обмена,
+// SLO-метрики получают exemplar с его trace_id.
+// Подключается перед TraceID: идентификатор запроса X-Request-ID записывается в атрибут span
+func Tracing() gin.HandlerFunc {
+	tracer := otel.Tracer(tracerName)
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath() // Пусто для запросов без маршрута (404)
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if id := GetTraceID(c); id != "" {
+			span.SetAttributes(attribute.String("http.request.id", id))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
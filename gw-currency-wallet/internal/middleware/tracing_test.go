@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	var handlerSpan trace.SpanContext
+	router := gin.New()
+	router.Use(Tracing(), TraceID())
+	router.POST("/api/v1/exchange", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.JSON(http.StatusBadGateway, gin.H{"error": "сервис обмена недоступен"})
+	})
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/exchange", nil)
+	request.Header.Set("traceparent", parent)
+	request.Header.Set(TraceIDHeader, "request-1")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("span: %d, ожидался 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "POST /api/v1/exchange" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span %q вида %s", span.Name(), span.SpanKind())
+	}
+	if got := span.Parent().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("трассировка клиента не продолжена: %s", got)
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("span запроса не передан обработчику в контексте")
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("статус span %v, ожидалась ошибка для ответа 502", span.Status())
+	}
+
+	attributes := map[string]string{}
+	for _, attr := range span.Attributes() {
+		attributes[string(attr.Key)] = attr.Value.Emit()
+	}
+	for key, want := range map[string]string{
+		"http.route":                "/api/v1/exchange",
+		"http.response.status_code": "502",
+		"http.request.id":           "request-1",
+	} {
+		if attributes[key] != want {
+			t.Errorf("атрибут %s = %q, ожидалось %q", key, attributes[key], want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+// Категории событий ленты активности
+const (
+	ActivityLogin     = "login"     // Входы в систему
+	ActivityProfile   = "profile"   // Изменения учетной записи и настроек
+	ActivityOperation = "operation" // Операции с деньгами (записи журнала операций)
+)
+
+// Действия журнала активности пользователя
+const (
+	ActionAccountCreated     = "account.created"     // Регистрация (по дате создания пользователя)
+	ActionLogin              = "login"               // Вход в систему
+	ActionLoginConfirmed     = "login.confirmed"     // Вход с нового устройства подтвержден кодом
+	ActionPreferencesUpdated = "preferences.updated" // Изменены настройки
+)
+
+// ActivityEntry - событие для записи в журнал активности пользователя
+type ActivityEntry struct {
+	UserID    int         // Пользователь
+	Category  string      // Категория (ActivityLogin, ActivityProfile)
+	Action    string      // Действие
+	IP        string      // IP адрес клиента (пусто - неизвестен)
+	UserAgent string      // User-Agent клиента
+	Details   interface{} // Подробности (сохраняются в JSON)
+}
+
+// ActivityEvent - событие ленты активности пользователя
+// swagger:model ActivityEvent
+type ActivityEvent struct {
+	Category  string                 `json:"category"`             // Категория: login, profile, operation
+	Action    string                 `json:"action"`               // Действие (для операций - тип записи журнала)
+	Details   map[string]interface{} `json:"details,omitempty"`    // Подробности (для операций - валюта, сумма, операция)
+	IP        string                 `json:"ip,omitempty"`         // IP адрес клиента
+	UserAgent string                 `json:"user_agent,omitempty"` // User-Agent клиента
+	CreatedAt time.Time              `json:"created_at"`           // Время события
+	Cursor    string                 `json:"-"`                    // Позиция события в ленте (для следующей страницы)
+}
+
+// ActivityFeed - страница ленты активности пользователя
+// swagger:model ActivityFeed
+type ActivityFeed struct {
+	Events     []ActivityEvent `json:"events"`                // События, от новых к старым
+	NextCursor string          `json:"next_cursor,omitempty"` // Курсор следующей страницы (пусто - страница последняя)
+}
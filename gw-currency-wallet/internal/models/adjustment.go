@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Статусы корректировок баланса администратором
+const (
+	AdjustmentPending  = "pending"  // Ожидает подтверждения вторым администратором
+	AdjustmentApplied  = "applied"  // Проведена
+	AdjustmentRejected = "rejected" // Отклонена
+)
+
+// BalanceAdjustment - зачисление или списание средств администратором
+// swagger:model BalanceAdjustment
+type BalanceAdjustment struct {
+	ID          int        `json:"id"`                    // Идентификатор корректировки
+	UserID      int        `json:"user_id"`               // Владелец кошелька
+	Currency    string     `json:"currency"`              // Валюта
+	Amount      float64    `json:"amount"`                // Сумма со знаком: положительная - зачисление, отрицательная - списание
+	Reason      string     `json:"reason"`                // Обязательное обоснование
+	Status      string     `json:"status"`                // Статус корректировки
+	RequestedBy int        `json:"requested_by"`          // Администратор, создавший корректировку
+	ResolvedBy  *int       `json:"resolved_by,omitempty"` // Администратор, подтвердивший или отклонивший корректировку
+	Comment     string     `json:"comment,omitempty"`     // Комментарий при отклонении
+	CreatedAt   time.Time  `json:"created_at"`            // Время создания
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"` // Время решения
+}
+
+// AdjustmentRequest - запрос на корректировку баланса
+// swagger:model AdjustmentRequest
+type AdjustmentRequest struct {
+	UserID   int     `json:"user_id" validate:"required"`                    // Владелец кошелька
+	Currency string  `json:"currency" validate:"required,oneof=USD RUB EUR"` // Валюта
+	Amount   float64 `json:"amount" validate:"required,ne=0"`                // Сумма: положительная - зачисление, отрицательная - списание
+	Reason   string  `json:"reason" validate:"required,min=10"`              // Обоснование (не короче 10 символов)
+}
+
+// RejectAdjustmentRequest - отклонение корректировки
+// swagger:model RejectAdjustmentRequest
+type RejectAdjustmentRequest struct {
+	Comment string `json:"comment"` // Причина отклонения
+}
+
+// AuditEntry - запись журнала действий администраторов
+// swagger:model AuditEntry
+type AuditEntry struct {
+	ID           int             `json:"id"`                                     // Идентификатор записи
+	AdminID      int             `json:"admin_id"`                               // Администратор
+	Action       string          `json:"action"`                                 // Действие (например adjustment.applied)
+	TargetUserID *int            `json:"target_user_id,omitempty"`               // Пользователь, которого касается действие
+	Details      json.RawMessage `json:"details,omitempty" swaggertype:"object"` // Подробности действия
+	CreatedAt    time.Time       `json:"created_at"`                             // Время действия
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Условия срабатывания уведомления о курсе
+const (
+	AlertAbove = ">" // Курс поднялся до порога или выше
+	AlertBelow = "<" // Курс опустился до порога или ниже
+)
+
+// RateAlert - уведомление о достижении курсом пары валют порога
+// Уведомление однократное: после срабатывания оно удаляется
+type RateAlert struct {
+	ID           int       `json:"id" db:"id"`                       // Идентификатор уведомления
+	ChatID       int64     `json:"chat_id" db:"chat_id"`             // Чат Telegram, в который отправляется уведомление
+	FromCurrency string    `json:"from_currency" db:"from_currency"` // Исходная валюта пары
+	ToCurrency   string    `json:"to_currency" db:"to_currency"`     // Целевая валюта пары
+	Condition    string    `json:"condition" db:"condition"`         // Условие (> или <)
+	Threshold    float64   `json:"threshold" db:"threshold"`         // Порог курса
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`       // Время создания
+}
+
+// Triggered сообщает, выполняется ли условие уведомления при курсе rate
+func (a RateAlert) Triggered(rate float64) bool {
+	if a.Condition == AlertBelow {
+		return rate <= a.Threshold
+	}
+	return rate >= a.Threshold
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// IPBan - временная блокировка IP адреса за подбор паролей
+// swagger:model IPBan
+type IPBan struct {
+	IP        string    `json:"ip"`         // Заблокированный адрес
+	Reason    string    `json:"reason"`     // Причина блокировки
+	Attempts  int64     `json:"attempts"`   // Количество попыток в окне на момент блокировки
+	BannedAt  time.Time `json:"banned_at"`  // Время блокировки
+	ExpiresAt time.Time `json:"expires_at"` // Время автоматического снятия блокировки
+}
+
+// Active сообщает, действует ли блокировка в момент now
+func (b IPBan) Active(now time.Time) bool {
+	return now.Before(b.ExpiresAt)
+}
@@ -0,0 +1,21 @@
+package models
+
+// Группы записей кэша, сбрасываемые администратором
+const (
+	CacheScopeRates    = "rates"    // Курсы, справочник валют и графики курсов (API и Telegram)
+	CacheScopeBalances = "balances" // Балансы кошельков
+	CacheScopeLimits   = "limits"   // Тарифные планы и счетчики квот запросов
+)
+
+// CacheFlushRequest - сброс групп записей кэша администратором
+// swagger:model CacheFlushRequest
+type CacheFlushRequest struct {
+	Scopes []string `json:"scopes" validate:"required"` // Группы записей: rates, balances, limits
+}
+
+// CacheFlushResult - результат сброса кэша
+// swagger:model CacheFlushResult
+type CacheFlushResult struct {
+	Deleted   map[string]int64 `json:"deleted"`   // Удалено записей по группам
+	Broadcast bool             `json:"broadcast"` // Сброс разослан остальным экземплярам (Redis)
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// RateCandle - свеча курса пары валют за интервал графика
+// swagger:model RateCandle
+type RateCandle struct {
+	Time  time.Time `json:"time"`  // Начало интервала
+	Open  float64   `json:"open"`  // Курс на начало интервала
+	High  float64   `json:"high"`  // Максимальный курс
+	Low   float64   `json:"low"`   // Минимальный курс
+	Close float64   `json:"close"` // Курс на конец интервала
+}
+
+// RateChart - данные графика курса пары валют
+// swagger:model RateChart
+type RateChart struct {
+	Pair     string       `json:"pair"`     // Пара валют (например, USD/RUB)
+	Interval string       `json:"interval"` // Длительность свечи (например, 1h)
+	Range    string       `json:"range"`    // Период графика до текущего момента (например, 7d)
+	Candles  []RateCandle `json:"candles"`  // Свечи в порядке времени
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// AMLReport - запись отчета о крупной операции (сумма не меньше порога отчетности)
+// swagger:model AMLReport
+type AMLReport struct {
+	ID             int       `json:"id"`                        // Идентификатор записи отчета
+	OperationID    string    `json:"operation_id"`              // Операция в журнале (общий для всех ее записей)
+	UserID         int       `json:"user_id"`                   // Клиент
+	Username       string    `json:"username"`                  // Имя пользователя клиента
+	Email          string    `json:"email"`                     // Email клиента
+	Type           string    `json:"type"`                      // Тип записи журнала (deposit, withdraw, transfer_out, ...)
+	Currency       string    `json:"currency"`                  // Валюта
+	Amount         float64   `json:"amount"`                    // Сумма операции (по модулю)
+	Threshold      float64   `json:"threshold"`                 // Порог, действовавший в момент операции
+	CounterpartyID *int      `json:"counterparty_id,omitempty"` // Контрагент перевода
+	CreatedAt      time.Time `json:"created_at"`                // Время операции
+}
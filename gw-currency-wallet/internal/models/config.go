@@ -0,0 +1,16 @@
+package models
+
+// ConfigEntry - параметр действующей конфигурации экземпляра
+type ConfigEntry struct {
+	Key        string `json:"key"`               // Имя переменной окружения (SERVER_ADDRESS)
+	Value      string `json:"value"`             // Действующее значение в формате переменной окружения ([REDACTED] для заданных секретов)
+	Default    string `json:"default,omitempty"` // Значение по умолчанию
+	Overridden bool   `json:"overridden"`        // Значение отличается от значения по умолчанию
+	Secret     bool   `json:"secret,omitempty"`  // Параметр содержит секрет, значение скрыто
+}
+
+// EffectiveConfig - действующая конфигурация экземпляра без секретов и состояние функций
+type EffectiveConfig struct {
+	Entries  []ConfigEntry   `json:"entries"`  // Параметры в порядке объявления
+	Features map[string]bool `json:"features"` // Включенные и выключенные функции (balance_cache, risk, telegram_bot...)
+}
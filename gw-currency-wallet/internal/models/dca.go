@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// Периодичность плана регулярных обменов
+const (
+	DCAPeriodDaily  = "daily"  // Каждый день
+	DCAPeriodWeekly = "weekly" // Каждую неделю в день недели первого обмена
+)
+
+// Состояния плана регулярных обменов
+const (
+	DCAPlanActive    = "active"    // Обмены выполняются по расписанию
+	DCAPlanCompleted = "completed" // Выполнены все обмены плана
+	DCAPlanCancelled = "cancelled" // Отменен пользователем
+)
+
+// Результаты обмена по плану
+const (
+	DCALegExecuting = "executing" // Выполняется
+	DCALegExecuted  = "executed"  // Выполнен
+	DCALegSkipped   = "skipped"   // Пропущен: недостаточно средств
+	DCALegFailed    = "failed"    // Не выполнен (валюта приостановлена, пара вне окна исполнения, сбой)
+)
+
+// DCAPlan - план регулярных обменов фиксированной суммы (усреднение курса)
+// Например, 50 USD в EUR каждый день в 12:00, 30 обменов
+// swagger:model DCAPlan
+type DCAPlan struct {
+	ID           int64      `json:"id"`                    // Идентификатор плана
+	UserID       int        `json:"-"`                     // Владелец
+	TenantID     string     `json:"-"`                     // Арендатор владельца (валюты и комиссия обменов)
+	FromCurrency string     `json:"from_currency"`         // Исходная валюта
+	ToCurrency   string     `json:"to_currency"`           // Целевая валюта
+	Amount       float64    `json:"amount"`                // Сумма одного обмена в исходной валюте
+	Period       string     `json:"period"`                // Периодичность: daily, weekly
+	TimeOfDay    string     `json:"time"`                  // Время обмена ЧЧ:ММ
+	Timezone     string     `json:"timezone"`              // Часовой пояс IANA времени обмена
+	Legs         int        `json:"legs"`                  // Количество обменов плана
+	Executed     int        `json:"executed"`              // Выполнено обменов
+	Skipped      int        `json:"skipped"`               // Пропущено из-за недостатка средств
+	Failed       int        `json:"failed"`                // Не выполнено по другим причинам
+	Status       string     `json:"status"`                // Состояние: active, completed, cancelled
+	NextRunAt    *time.Time `json:"next_run_at,omitempty"` // Время следующего обмена (для активного плана)
+	CreatedAt    time.Time  `json:"created_at"`            // Время создания
+	FinishedAt   *time.Time `json:"finished_at,omitempty"` // Время завершения или отмены
+}
+
+// DCALeg - обмен по плану
+// swagger:model DCALeg
+type DCALeg struct {
+	PlanID          int64      `json:"-"`                          // План
+	Number          int        `json:"number"`                     // Номер обмена в плане (с 1)
+	ScheduledAt     time.Time  `json:"scheduled_at"`               // Время по расписанию
+	Status          string     `json:"status"`                     // Результат: executing, executed, skipped, failed
+	Rate            *float64   `json:"rate,omitempty"`             // Примененный курс
+	ExchangedAmount *float64   `json:"exchanged_amount,omitempty"` // Полученная сумма (за вычетом комиссии)
+	Error           string     `json:"error,omitempty"`            // Причина пропуска или неисполнения
+	ExecutedAt      *time.Time `json:"executed_at,omitempty"`      // Время выполнения
+}
+
+// DCAPlanProgress - ход выполнения плана регулярных обменов
+// swagger:model DCAPlanProgress
+type DCAPlanProgress struct {
+	Plan          DCAPlan  `json:"plan"`           // План
+	Legs          []DCALeg `json:"legs"`           // Обмены плана от первого к последнему
+	Remaining     int      `json:"remaining"`      // Осталось обменов
+	TotalSpent    float64  `json:"total_spent"`    // Списано в исходной валюте по выполненным обменам
+	TotalReceived float64  `json:"total_received"` // Получено в целевой валюте по выполненным обменам
+	AverageRate   float64  `json:"average_rate"`   // Средний курс: получено / списано (0 - обменов не было)
+}
+
+// CreateDCAPlanRequest - создание плана регулярных обменов
+// swagger:model CreateDCAPlanRequest
+type CreateDCAPlanRequest struct {
+	FromCurrency string  `json:"from_currency" validate:"required,oneof=USD RUB EUR"` // Исходная валюта
+	ToCurrency   string  `json:"to_currency" validate:"required,oneof=USD RUB EUR"`   // Целевая валюта
+	Amount       float64 `json:"amount" validate:"required,gt=0"`                     // Сумма одного обмена
+	Period       string  `json:"period,omitempty"`                                    // Периодичность: daily (по умолчанию), weekly
+	Time         string  `json:"time" validate:"required"`                            // Время обмена ЧЧ:ММ
+	Legs         int     `json:"legs" validate:"required,gt=0"`                       // Количество обменов (например, 30)
+	Timezone     string  `json:"timezone,omitempty"`                                  // Часовой пояс IANA (по умолчанию - из настроек пользователя)
+}
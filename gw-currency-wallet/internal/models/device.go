@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// Device - устройство, с которого пользователь входил в систему
+// Устройство определяется по отпечатку (хэш User-Agent и необязательного X-Device-ID)
+// swagger:model Device
+type Device struct {
+	ID          int       `json:"id"`                // Идентификатор устройства
+	UserID      int       `json:"-"`                 // Владелец
+	Fingerprint string    `json:"-"`                 // Отпечаток устройства
+	UserAgent   string    `json:"user_agent"`        // User-Agent последнего входа
+	LastIP      string    `json:"last_ip"`           // IP адрес последнего входа
+	Country     string    `json:"country,omitempty"` // Страна последнего входа (GeoIP)
+	City        string    `json:"city,omitempty"`    // Город последнего входа (GeoIP)
+	Confirmed   bool      `json:"confirmed"`         // Вход с устройства подтвержден кодом
+	FirstSeen   time.Time `json:"first_seen"`        // Первый вход
+	LastSeen    time.Time `json:"last_seen"`         // Последний вход
+}
+
+// DeviceConfirmationResponse - ответ входа с нового устройства, требующего подтверждения
+// swagger:model DeviceConfirmationResponse
+type DeviceConfirmationResponse struct {
+	ConfirmationRequired bool   `json:"confirmation_required"` // Всегда true
+	ConfirmationID       string `json:"confirmation_id"`       // Идентификатор подтверждения для /login/confirm
+	ExpiresIn            int    `json:"expires_in"`            // Время действия кода в секундах
+}
+
+// ConfirmDeviceRequest - подтверждение входа с нового устройства кодом из письма
+// swagger:model ConfirmDeviceRequest
+type ConfirmDeviceRequest struct {
+	ConfirmationID string `json:"confirmation_id" validate:"required"` // Идентификатор из ответа /login
+	Code           string `json:"code" validate:"required"`            // Код подтверждения из письма
+}
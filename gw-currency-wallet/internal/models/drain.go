@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// Состояния экземпляра сервиса при выводе из балансировки
+const (
+	DrainStateServing  = "serving"  // Принимает запросы, /readyz отвечает 200
+	DrainStateDraining = "draining" // /readyz отвечает 503, ожидается завершение запросов и фоновых задач
+	DrainStateDrained  = "drained"  // Запросы и фоновые задачи завершены, экземпляр можно останавливать
+)
+
+// DrainStatus - состояние вывода экземпляра из балансировки
+// swagger:model DrainStatus
+type DrainStatus struct {
+	State     string     `json:"state"`                // Состояние: serving, draining, drained
+	InFlight  int64      `json:"in_flight"`            // Выполняющиеся HTTP запросы
+	StartedAt *time.Time `json:"started_at,omitempty"` // Начало вывода из балансировки
+	DrainedAt *time.Time `json:"drained_at,omitempty"` // Завершение вывода
+	TimedOut  bool       `json:"timed_out,omitempty"`  // Запросы не завершились за DRAIN_TIMEOUT
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// CurrencyFreeze - приостановка операций в валюте администратором (например, при резких колебаниях курса)
+// swagger:model CurrencyFreeze
+type CurrencyFreeze struct {
+	Currency string    `json:"currency"`  // Валюта
+	Reason   string    `json:"reason"`    // Причина (показывается пользователям)
+	FrozenBy int       `json:"frozen_by"` // Администратор, приостановивший операции
+	FrozenAt time.Time `json:"frozen_at"` // Время приостановки
+}
+
+// FreezeCurrencyRequest - приостановка операций в валюте
+// swagger:model FreezeCurrencyRequest
+type FreezeCurrencyRequest struct {
+	Reason string `json:"reason"` // Причина (до 256 символов, необязательно)
+}
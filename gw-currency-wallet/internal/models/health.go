@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Состояния связи с сервисом обмена
+const (
+	ExchangeHealthUnknown     = "unknown"     // Проверка еще не выполнялась
+	ExchangeHealthOK          = "ok"          // Сервис обмена отвечает, курсы актуальны
+	ExchangeHealthStale       = "stale"       // Сервис обмена отвечает, но сообщает об устаревших курсах
+	ExchangeHealthUnavailable = "unavailable" // Сервис обмена недоступен или отвечает ошибкой
+)
+
+// ExchangeHealth - состояние связи с сервисом обмена по результатам последней проверки
+type ExchangeHealth struct {
+	Status     string     `json:"status"`               // Состояние: unknown, ok, stale, unavailable
+	Connection string     `json:"connection"`           // Состояние gRPC соединения (IDLE, CONNECTING, READY, TRANSIENT_FAILURE)
+	CheckedAt  *time.Time `json:"checked_at,omitempty"` // Время последней проверки
+	Failures   int        `json:"failures,omitempty"`   // Неудачных проверок подряд
+	Error      string     `json:"error,omitempty"`      // Ошибка последней проверки
+
+	Updates []ExchangeUpdateStatus `json:"updates,omitempty"` // Состояние обновлений курсов в сервисе обмена (последняя успешная проверка)
+}
+
+// ExchangeUpdateStatus - состояние фонового обновления курсов в сервисе обмена (GetUpdateStatus)
+type ExchangeUpdateStatus struct {
+	Name        string     `json:"name"`                   // Обновление: rates (фиатные валюты), crypto (криптовалюты)
+	LastSuccess *time.Time `json:"last_success,omitempty"` // Время последнего успешного обновления
+	LastError   string     `json:"last_error,omitempty"`   // Ошибка последнего запуска (пусто - успешен)
+	Source      string     `json:"source,omitempty"`       // Источник курсов последнего успешного обновления
+	Currencies  int        `json:"currencies"`             // Валют, полученных при последнем успешном обновлении
+}
+
+// ReadinessStatus - ответ /readyz: вывод из балансировки и состояние зависимостей
+type ReadinessStatus struct {
+	DrainStatus
+	Degraded bool            `json:"degraded"`           // Экземпляр работает без сервиса обмена: курсы и обмен недоступны
+	Exchange *ExchangeHealth `json:"exchange,omitempty"` // Связь с сервисом обмена
+}
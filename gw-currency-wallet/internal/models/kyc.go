@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// Статусы верификации пользователя (KYC)
+const (
+	KYCNone     = "none"     // Документы не подавались
+	KYCPending  = "pending"  // Документы поданы и ожидают проверки
+	KYCVerified = "verified" // Пользователь верифицирован
+	KYCRejected = "rejected" // Документы отклонены (можно подать повторно)
+)
+
+// Типы документов для верификации
+const (
+	KYCDocumentPassport       = "passport"         // Паспорт
+	KYCDocumentIDCard         = "id_card"          // Удостоверение личности
+	KYCDocumentDriverLicense  = "driver_license"   // Водительское удостоверение
+	KYCDocumentProofOfAddress = "proof_of_address" // Подтверждение адреса
+	KYCDocumentSelfie         = "selfie"           // Фото с документом
+)
+
+// KYCDocument - метаданные документа, поданного для верификации
+// Содержимое файла хранится отдельно (см. storage.DocumentStore), в БД - только ключ
+// swagger:model KYCDocument
+type KYCDocument struct {
+	ID          int       `json:"id"`           // Идентификатор документа
+	UserID      int       `json:"user_id"`      // Владелец документа
+	Type        string    `json:"type"`         // Тип документа (passport, id_card, ...)
+	FileName    string    `json:"file_name"`    // Исходное имя файла
+	ContentType string    `json:"content_type"` // MIME-тип файла
+	Size        int64     `json:"size"`         // Размер файла в байтах
+	StorageKey  string    `json:"-"`            // Ключ файла в хранилище документов (не возвращается в API)
+	CreatedAt   time.Time `json:"created_at"`   // Время подачи
+}
+
+// KYCStatusResponse - статус верификации пользователя и поданные документы
+// swagger:model KYCStatusResponse
+type KYCStatusResponse struct {
+	Status    string        `json:"status"`            // Статус верификации
+	Comment   string        `json:"comment,omitempty"` // Комментарий администратора (например, причина отказа)
+	Documents []KYCDocument `json:"documents"`         // Поданные документы
+}
+
+// ResolveKYCRequest - решение администратора по верификации пользователя
+// swagger:model ResolveKYCRequest
+type ResolveKYCRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=verify reject"` // verify - верифицировать, reject - отклонить
+	Comment  string `json:"comment"`                                          // Комментарий (причина отказа)
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Каналы уведомлений пользователей об операциях с балансом
+const (
+	NotificationChannelEmail    = "email"    // Письмо на email пользователя
+	NotificationChannelTelegram = "telegram" // Сообщение в личный чат привязанного аккаунта Telegram
+	NotificationChannelWebhook  = "webhook"  // Подписанный POST запрос на адрес из правила пользователя
+	NotificationChannelPush     = "push"     // Push уведомление на зарегистрированные устройства (FCM, APNs)
+)
+
+// Состояния уведомления в outbox
+const (
+	NotificationPending = "pending" // Ожидает отправки (в том числе повторной)
+	NotificationSent    = "sent"    // Отправлено
+	NotificationFailed  = "failed"  // Не отправлено за отведенные попытки или получатель недоступен (dead letter)
+)
+
+// Notification - намерение уведомить пользователя об операции с балансом (запись outbox)
+// Записывается в одной транзакции с операцией, поэтому уведомление не теряется при сбое
+// и не отправляется об откаченной операции; отправляется фоновой задачей с повторами
+type Notification struct {
+	ID            int64           `json:"id" db:"id"`                                // Идентификатор уведомления
+	UserID        int             `json:"user_id" db:"user_id"`                      // Получатель
+	Channel       string          `json:"channel" db:"channel"`                      // Канал (email, telegram, webhook, push)
+	Target        string          `json:"target,omitempty" db:"target"`              // Адрес из правила пользователя (пусто - адрес по умолчанию канала)
+	OperationID   string          `json:"operation_id" db:"operation_id"`            // Операция в журнале
+	Payload       json.RawMessage `json:"payload" db:"payload" swaggertype:"object"` // Записи журнала операции, относящиеся к получателю ([]Transaction)
+	Status        string          `json:"status" db:"status"`                        // Состояние (pending, sent, failed)
+	Attempts      int             `json:"attempts" db:"attempts"`                    // Попыток отправки
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`      // Время следующей попытки
+	LastError     string          `json:"last_error,omitempty" db:"last_error"`      // Ошибка последней попытки
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`                // Время операции
+	SentAt        *time.Time      `json:"sent_at,omitempty" db:"sent_at"`            // Время доставки
+
+	// Адрес получателя на момент отправки (из профиля и привязки Telegram)
+	Email          string `json:"-" db:"email"`            // Email пользователя
+	TelegramChatID int64  `json:"-" db:"telegram_chat_id"` // Личный чат Telegram (0 - аккаунт не привязан)
+}
+
+// Entries возвращает записи журнала операции из Payload
+func (n Notification) Entries() ([]Transaction, error) {
+	var entries []Transaction
+	if err := json.Unmarshal(n.Payload, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// NotificationRoute - правило пользователя для доставки уведомлений об операциях в канал
+// Пользователю без правил уведомления доставляются в каналы по умолчанию (NOTIFY_DEFAULT_CHANNELS),
+// пользователю с правилами - только в каналы включенных правил, которым соответствует операция
+// swagger:model NotificationRoute
+type NotificationRoute struct {
+	UserID    int       `json:"-" db:"user_id"`               // Владелец правила
+	Channel   string    `json:"channel" db:"channel"`         // Канал (email, telegram, webhook, push)
+	Target    string    `json:"target,omitempty" db:"target"` // Адрес в канале: URL для webhook, email (пусто - email профиля)
+	Types     []string  `json:"types" db:"types"`             // Типы записей журнала (deposit, transfer_in, ...; пусто - все)
+	MinAmount float64   `json:"min_amount" db:"min_amount"`   // Наименьшая сумма записи в ее валюте (0 - любая)
+	Enabled   bool      `json:"enabled" db:"enabled"`         // Правило действует (выключенное правило отключает канал)
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`   // Время изменения
+}
+
+// SaveNotificationRouteRequest - правило доставки уведомлений в канал
+// swagger:model SaveNotificationRouteRequest
+type SaveNotificationRouteRequest struct {
+	Target    string   `json:"target,omitempty"`     // Адрес в канале (обязателен для webhook)
+	Types     []string `json:"types,omitempty"`      // Типы записей журнала (пусто - все)
+	MinAmount float64  `json:"min_amount,omitempty"` // Наименьшая сумма записи (0 - любая)
+	Enabled   *bool    `json:"enabled,omitempty"`    // Правило действует (по умолчанию true)
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Состояния многошаговой денежной операции
+const (
+	OperationPending      = "pending"      // Шаги выполняются
+	OperationCompleted    = "completed"    // Все шаги выполнены
+	OperationCompensating = "compensating" // Шаг не выполнен, выполненные шаги отменяются
+	OperationCompensated  = "compensated"  // Операция отменена: выполненные шаги (если были) компенсированы
+	OperationFailed       = "failed"       // Компенсация не удалась за отведенные попытки, нужен разбор вручную
+)
+
+// Виды многошаговых денежных операций
+const (
+	OperationKindExchange = "exchange" // Обмен валюты: параметры - расчет обмена (ExchangeQuote)
+)
+
+// MoneyOperation - состояние многошаговой денежной операции
+// Номер выполненного шага фиксируется в одной транзакции с изменением баланса, поэтому после сбоя
+// экземпляра сервиса известно, какие шаги нужно компенсировать
+type MoneyOperation struct {
+	ID        int64           `json:"id" db:"id"`                 // Идентификатор операции
+	UserID    int             `json:"user_id" db:"user_id"`       // Пользователь
+	Kind      string          `json:"kind" db:"kind"`             // Вид операции (exchange)
+	Status    string          `json:"status" db:"status"`         // Состояние (pending, completed, compensating, compensated, failed)
+	Step      int             `json:"step" db:"step"`             // Количество выполненных (не компенсированных) шагов
+	Payload   json.RawMessage `json:"payload" db:"payload"`       // Параметры операции, по которым компенсируются шаги
+	Error     string          `json:"error,omitempty" db:"error"` // Ошибка, из-за которой операция отменяется
+	Attempts  int             `json:"attempts" db:"attempts"`     // Попыток компенсации фоновой задачей
+	CreatedAt time.Time       `json:"created_at" db:"created_at"` // Время начала операции
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"` // Время последнего изменения состояния
+}
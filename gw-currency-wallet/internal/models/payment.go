@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Типы событий платежного провайдера
+const (
+	PaymentSucceeded = "payment.succeeded" // Платеж завершен: сумма зачисляется на баланс
+)
+
+// PaymentEvent - событие платежного провайдера, полученное через webhook
+// swagger:model PaymentEvent
+type PaymentEvent struct {
+	EventID  string  `json:"event_id" validate:"required,max=128"`           // Идентификатор события у провайдера (зачисление выполняется один раз)
+	Type     string  `json:"type" validate:"required"`                       // Тип события (payment.succeeded)
+	UserID   int     `json:"user_id" validate:"required,gt=0"`               // Пользователь, пополняющий кошелек
+	Currency string  `json:"currency" validate:"required,oneof=USD RUB EUR"` // Валюта платежа
+	Amount   float64 `json:"amount" validate:"required,gt=0"`                // Сумма платежа
+}
+
+// PaymentCredit - зачисление платежа по событию провайдера
+// swagger:model PaymentCredit
+type PaymentCredit struct {
+	EventID     string    `json:"event_id"`     // Идентификатор события у провайдера
+	UserID      int       `json:"user_id"`      // Пользователь
+	Currency    string    `json:"currency"`     // Валюта зачисления
+	Amount      float64   `json:"amount"`       // Зачисленная сумма
+	Duplicate   bool      `json:"duplicate"`    // Событие уже было обработано, повторного зачисления нет
+	ProcessedAt time.Time `json:"processed_at"` // Время первой обработки события
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// PortfolioPosition - позиция в одной валюте: средний курс приобретения и нереализованный доход
+// swagger:model PortfolioPosition
+type PortfolioPosition struct {
+	Currency             string  `json:"currency"`               // Валюта позиции
+	Amount               float64 `json:"amount"`                 // Баланс в этой валюте
+	AverageRate          float64 `json:"average_rate"`           // Средний курс приобретения в валюте оценки
+	CostBasis            float64 `json:"cost_basis"`             // Стоимость приобретения в валюте оценки
+	CurrentRate          float64 `json:"current_rate"`           // Текущий курс к валюте оценки
+	MarketValue          float64 `json:"market_value"`           // Текущая стоимость в валюте оценки
+	UnrealizedPnL        float64 `json:"unrealized_pnl"`         // Нереализованный доход (убыток - отрицательный)
+	UnrealizedPnLPercent float64 `json:"unrealized_pnl_percent"` // Нереализованный доход в процентах от стоимости приобретения
+}
+
+// Portfolio - валютные позиции пользователя и нереализованный доход по текущим курсам
+// swagger:model Portfolio
+type Portfolio struct {
+	Currency      string              `json:"currency"`       // Валюта оценки
+	Positions     []PortfolioPosition `json:"positions"`      // Позиции по валютам
+	CostBasis     float64             `json:"cost_basis"`     // Суммарная стоимость приобретения
+	MarketValue   float64             `json:"market_value"`   // Суммарная текущая стоимость
+	UnrealizedPnL float64             `json:"unrealized_pnl"` // Суммарный нереализованный доход
+	ValuedAt      time.Time           `json:"valued_at"`      // Момент оценки (курсы на это время)
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Настройки пользователя по умолчанию (до первого сохранения)
+const (
+	DefaultDisplayCurrency = "USD" // Валюта отображения итогов
+	DefaultLocale          = "ru"  // Локаль форматирования сумм и дат
+	DefaultTimezone        = "UTC" // Часовой пояс для времени операций
+)
+
+// UserPreferences - настройки отображения данных пользователя
+// swagger:model UserPreferences
+type UserPreferences struct {
+	UserID          int        `json:"-" db:"user_id"`                         // Владелец настроек
+	DefaultCurrency string     `json:"default_currency" db:"default_currency"` // Валюта отображения итогов (USD, RUB, EUR)
+	Locale          string     `json:"locale" db:"locale"`                     // Локаль форматирования (ru, en)
+	Timezone        string     `json:"timezone" db:"timezone"`                 // Часовой пояс IANA (например, Europe/Moscow)
+	UpdatedAt       *time.Time `json:"updated_at,omitempty" db:"updated_at"`   // Время последнего изменения (пусто - настройки по умолчанию)
+}
+
+// DefaultPreferences возвращает настройки пользователя по умолчанию
+func DefaultPreferences(userID int) *UserPreferences {
+	return &UserPreferences{
+		UserID:          userID,
+		DefaultCurrency: DefaultDisplayCurrency,
+		Locale:          DefaultLocale,
+		Timezone:        DefaultTimezone,
+	}
+}
+
+// UpdatePreferencesRequest - запрос на изменение настроек (незаданные поля не меняются)
+// swagger:model UpdatePreferencesRequest
+type UpdatePreferencesRequest struct {
+	DefaultCurrency *string `json:"default_currency,omitempty"` // Валюта отображения (USD, RUB, EUR)
+	Locale          *string `json:"locale,omitempty"`           // Локаль (ru, en)
+	Timezone        *string `json:"timezone,omitempty"`         // Часовой пояс IANA
+}
+
+// BalanceDisplay - баланс, оформленный по настройкам пользователя
+// swagger:model BalanceDisplay
+type BalanceDisplay struct {
+	Currency       string            `json:"currency"`        // Валюта отображения итога
+	Total          float64           `json:"total"`           // Сумма всех валют в валюте отображения по текущим курсам
+	TotalFormatted string            `json:"total_formatted"` // Итог, отформатированный по локали
+	Formatted      map[string]string `json:"formatted"`       // Баланс по валютам, отформатированный по локали
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Виды промокодов
+const (
+	PromoFixed   = "fixed"   // Фиксированная сумма, зачисляется при активации промокода
+	PromoPercent = "percent" // Процент от пополнения, зачисляется вместе с пополнением
+)
+
+// PromoCode - промокод на бонусное зачисление
+// swagger:model PromoCode
+type PromoCode struct {
+	ID        int        `json:"id"`                   // Идентификатор промокода
+	Code      string     `json:"code"`                 // Код (хранится в верхнем регистре)
+	Kind      string     `json:"kind"`                 // Вид: fixed или percent
+	Currency  string     `json:"currency"`             // Валюта бонуса (для percent - валюта пополнения)
+	Value     float64    `json:"value"`                // Сумма (fixed) или процент (percent)
+	MaxBonus  *float64   `json:"max_bonus,omitempty"`  // Максимальный бонус для percent
+	MaxUses   *int       `json:"max_uses,omitempty"`   // Максимум активаций (nil - без ограничения)
+	Uses      int        `json:"uses"`                 // Количество активаций
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Срок действия (nil - бессрочный)
+	Active    bool       `json:"active"`               // Промокод не отключен администратором
+	CreatedBy int        `json:"created_by"`           // Администратор, создавший промокод
+	CreatedAt time.Time  `json:"created_at"`           // Время создания
+}
+
+// PromoRedemption - активация промокода пользователем
+// swagger:model PromoRedemption
+type PromoRedemption struct {
+	ID        int       `json:"id"`         // Идентификатор активации
+	Code      string    `json:"code"`       // Промокод
+	UserID    int       `json:"user_id"`    // Пользователь
+	Currency  string    `json:"currency"`   // Валюта бонуса
+	Bonus     float64   `json:"bonus"`      // Зачисленный бонус
+	CreatedAt time.Time `json:"created_at"` // Время активации
+}
+
+// CreatePromoRequest - запрос на создание промокода
+// swagger:model CreatePromoRequest
+type CreatePromoRequest struct {
+	Code      string     `json:"code" validate:"required,min=3,max=32"`          // Код (буквы, цифры, дефис, подчеркивание)
+	Kind      string     `json:"kind" validate:"required,oneof=fixed percent"`   // Вид промокода
+	Currency  string     `json:"currency" validate:"required,oneof=USD RUB EUR"` // Валюта
+	Value     float64    `json:"value" validate:"required,gt=0"`                 // Сумма или процент (для percent - не больше 100)
+	MaxBonus  *float64   `json:"max_bonus,omitempty"`                            // Максимальный бонус для percent
+	MaxUses   *int       `json:"max_uses,omitempty"`                             // Максимум активаций
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`                           // Срок действия (RFC3339)
+}
+
+// RedeemPromoRequest - запрос на активацию промокода
+// swagger:model RedeemPromoRequest
+type RedeemPromoRequest struct {
+	Code string `json:"code" validate:"required"` // Промокод
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Платформы push уведомлений
+const (
+	PushPlatformFCM  = "fcm"  // Firebase Cloud Messaging (Android, веб)
+	PushPlatformAPNs = "apns" // Apple Push Notification service (iOS)
+)
+
+// PushToken - токен push уведомлений установки мобильного приложения
+// swagger:model PushToken
+type PushToken struct {
+	ID         int64     `json:"id" db:"id"`                             // Идентификатор регистрации
+	UserID     int       `json:"-" db:"user_id"`                         // Владелец
+	Platform   string    `json:"platform" db:"platform"`                 // Платформа (fcm, apns)
+	Token      string    `json:"token" db:"token"`                       // Токен устройства у платформы
+	DeviceName string    `json:"device_name,omitempty" db:"device_name"` // Название устройства, заданное приложением
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`             // Первая регистрация
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`             // Последняя регистрация (приложение обновляет токен при запуске)
+}
+
+// RegisterPushTokenRequest - регистрация токена push уведомлений
+// swagger:model RegisterPushTokenRequest
+type RegisterPushTokenRequest struct {
+	Platform   string `json:"platform"`              // Платформа (fcm, apns)
+	Token      string `json:"token"`                 // Токен устройства
+	DeviceName string `json:"device_name,omitempty"` // Название устройства
+}
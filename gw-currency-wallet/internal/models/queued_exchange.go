@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Действия с обменом вне окна исполнения пары (ExchangeRequest.OutsideHours)
+const (
+	OutsideHoursReject = "reject" // Отклонить обмен
+	OutsideHoursQueue  = "queue"  // Поставить обмен в очередь до открытия окна
+)
+
+// Момент исполнения обмена (ExchangeRequest.Execute)
+const (
+	ExecuteNow      = "now"       // Сразу по текущему курсу
+	ExecuteNextRate = "next_rate" // По следующему опубликованному курсу пары
+)
+
+// Условия исполнения обмена из очереди
+const (
+	QueuedExchangeTriggerWindow = "trading_hours" // Открытие окна исполнения пары
+	QueuedExchangeTriggerRate   = "next_rate"     // Публикация сервисом обмена нового курса валюты пары
+)
+
+// Состояния обмена в очереди
+const (
+	QueuedExchangePending   = "pending"   // Ожидает исполнения
+	QueuedExchangeExecuting = "executing" // Исполняется
+	QueuedExchangeExecuted  = "executed"  // Исполнен
+	QueuedExchangeFailed    = "failed"    // Не исполнен (недостаточно средств, валюта приостановлена и т.п.)
+	QueuedExchangeCancelled = "cancelled" // Отменен пользователем до исполнения
+)
+
+// QueuedExchange - обмен, отложенный до наступления условия исполнения
+// Курс и комиссия определяются в момент исполнения, средства до него не резервируются
+// swagger:model QueuedExchange
+type QueuedExchange struct {
+	ID              int64      `json:"id"`                         // Идентификатор
+	UserID          int        `json:"-"`                          // Пользователь
+	TenantID        string     `json:"-"`                          // Арендатор пользователя (валюты и комиссия при исполнении)
+	FromCurrency    string     `json:"from_currency"`              // Исходная валюта
+	ToCurrency      string     `json:"to_currency"`                // Целевая валюта
+	Amount          float64    `json:"amount"`                     // Сумма для обмена
+	Trigger         string     `json:"trigger"`                    // Условие исполнения: trading_hours, next_rate
+	Status          string     `json:"status"`                     // Состояние: pending, executing, executed, failed, cancelled
+	ExecuteAfter    time.Time  `json:"execute_after"`              // Исполнение не раньше этого времени
+	Rate            *float64   `json:"rate,omitempty"`             // Примененный курс
+	ExchangedAmount *float64   `json:"exchanged_amount,omitempty"` // Полученная сумма (за вычетом комиссии)
+	Error           string     `json:"error,omitempty"`            // Причина неисполнения
+	CreatedAt       time.Time  `json:"created_at"`                 // Время постановки в очередь
+	ExecutedAt      *time.Time `json:"executed_at,omitempty"`      // Время исполнения, отказа или отмены
+}
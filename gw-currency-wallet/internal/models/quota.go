@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Тарифные планы пользователей (квоты запросов задаются в QUOTA_LIMITS)
+const (
+	PlanFree    = "free"    // Бесплатный план (назначается при регистрации)
+	PlanPremium = "premium" // Платный план с увеличенной квотой
+)
+
+// QuotaUsage - использование квоты запросов пользователя в текущем окне
+// swagger:model QuotaUsage
+type QuotaUsage struct {
+	Plan      string    `json:"plan"`               // Тарифный план пользователя
+	Limit     int64     `json:"limit"`              // Запросов за окно (0 - без ограничения)
+	Used      int64     `json:"used"`               // Выполнено запросов в текущем окне
+	Remaining int64     `json:"remaining"`          // Осталось запросов в текущем окне
+	Window    string    `json:"window"`             // Длительность окна (например, 1h0m0s)
+	ResetAt   time.Time `json:"reset_at,omitempty"` // Начало следующего окна
+}
+
+// SetPlanRequest - назначение тарифного плана пользователю администратором
+// swagger:model SetPlanRequest
+type SetPlanRequest struct {
+	Plan string `json:"plan" validate:"required"` // Тарифный план (free, premium или план из QUOTA_LIMITS)
+}
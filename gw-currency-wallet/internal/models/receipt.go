@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ReceiptEntry - запись журнала операций в квитанции
+type ReceiptEntry struct {
+	ID             int64    `json:"id"`                        // Идентификатор записи
+	Type           string   `json:"type"`                      // Тип записи (deposit, exchange_out, fee, ...)
+	Currency       string   `json:"currency"`                  // Валюта
+	Amount         float64  `json:"amount"`                    // Сумма со знаком: положительная - зачисление, отрицательная - списание
+	Rate           *float64 `json:"rate,omitempty"`            // Курс обмена (только для обмена)
+	CounterpartyID *int     `json:"counterparty_id,omitempty"` // Вторая сторона перевода (не раскрывается при публичной проверке)
+}
+
+// Receipt - квитанция о выполненной операции с кодом проверки
+// swagger:model Receipt
+type Receipt struct {
+	TransactionID    int64          `json:"transaction_id"`       // Запись журнала, по которой выдана квитанция
+	OperationID      string         `json:"operation_id"`         // Идентификатор операции
+	Type             string         `json:"type"`                 // Тип записи, по которой выдана квитанция
+	CreatedAt        time.Time      `json:"created_at"`           // Время операции
+	Entries          []ReceiptEntry `json:"entries"`              // Записи операции владельца по возрастанию ID
+	VerificationCode string         `json:"verification_code"`    // Подписанный код проверки
+	VerifyURL        string         `json:"verify_url,omitempty"` // Адрес публичной проверки квитанции
+	IssuedAt         time.Time      `json:"issued_at"`            // Время формирования квитанции
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// Статусы операций в очереди проверки антифрода
+const (
+	ReviewPending  = "pending"  // Ожидает решения администратора
+	ReviewApproved = "approved" // Одобрена и выполнена
+	ReviewRejected = "rejected" // Отклонена администратором
+	ReviewFailed   = "failed"   // Одобрена, но выполнить не удалось (например, недостаточно средств)
+)
+
+// RiskReview - операция, отложенная антифродом до решения администратора
+// swagger:model RiskReview
+type RiskReview struct {
+	ID          int        `json:"id"`                     // Идентификатор проверки
+	UserID      int        `json:"user_id"`                // Инициатор операции
+	Operation   string     `json:"operation"`              // Тип операции (withdraw, transfer)
+	RecipientID *int       `json:"recipient_id,omitempty"` // Получатель (только для переводов)
+	Currency    string     `json:"currency"`               // Валюта
+	Amount      float64    `json:"amount"`                 // Сумма
+	Rule        string     `json:"rule"`                   // Сработавшее правило
+	Reason      string     `json:"reason"`                 // Пояснение правила
+	Status      string     `json:"status"`                 // Статус проверки
+	ResolvedBy  *int       `json:"resolved_by,omitempty"`  // Администратор, принявший решение
+	Comment     string     `json:"comment,omitempty"`      // Комментарий администратора или причина ошибки выполнения
+	CreatedAt   time.Time  `json:"created_at"`             // Время постановки в очередь
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`  // Время решения
+}
+
+// TransferRequest - запрос на перевод средств другому пользователю
+// swagger:model TransferRequest
+type TransferRequest struct {
+	ToUsername string  `json:"to_username" validate:"required"`                // Имя пользователя получателя
+	Amount     float64 `json:"amount" validate:"required,gt=0"`                // Сумма перевода (>0)
+	Currency   string  `json:"currency" validate:"required,oneof=USD RUB EUR"` // Валюта перевода
+}
+
+// ResolveReviewRequest - решение администратора по отложенной операции
+// swagger:model ResolveReviewRequest
+type ResolveReviewRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=approve reject"` // approve - выполнить, reject - отклонить
+	Comment  string `json:"comment"`                                           // Комментарий администратора
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RefreshToken - токен обновления сессии (в хранилище только SHA-256 токена)
+// Сессия начинается входом по паролю; каждое обновление заменяет токен следующим в той же сессии
+type RefreshToken struct {
+	ID               int64      // Идентификатор записи
+	SessionID        string     // Сессия: цепочка токенов от одного входа
+	UserID           int        // Пользователь
+	TokenHash        string     // SHA-256 токена
+	SessionStartedAt time.Time  // Вход по паролю (отсчет абсолютного срока жизни сессии)
+	ExpiresAt        time.Time  // Срок действия токена (продлевается при обновлении)
+	RevokedAt        *time.Time // Токен заменен следующим или сессия отозвана
+}
+
+// RefreshTokenRequest - запрос нового JWT токена по токену обновления
+// swagger:model RefreshTokenRequest
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"` // Токен обновления из ответа входа или предыдущего обновления
+}
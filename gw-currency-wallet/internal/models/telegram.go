@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TelegramChat - чат Telegram бота (личный или группа) и его настройки
+type TelegramChat struct {
+	ChatID       int64      `json:"chat_id" db:"chat_id"`               // Идентификатор чата
+	Type         string     `json:"type" db:"type"`                     // Тип чата (private, group, supergroup)
+	Title        string     `json:"title" db:"title"`                   // Название группы (пусто для личного чата)
+	DigestHour   *int       `json:"digest_hour" db:"digest_hour"`       // Час ежедневной сводки курсов по UTC (nil - сводка выключена)
+	DigestSentOn *time.Time `json:"digest_sent_on" db:"digest_sent_on"` // Дата последней отправленной сводки
+}
+
+// TelegramLinkCode - одноразовый код привязки аккаунта Telegram к пользователю
+// swagger:model TelegramLinkCode
+type TelegramLinkCode struct {
+	Code      string    `json:"code"`       // Код привязки
+	Command   string    `json:"command"`    // Команда, которую нужно отправить боту
+	ExpiresAt time.Time `json:"expires_at"` // Срок действия кода
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+)
+
+// Типы записей журнала операций (ledger)
+// Каждая операция с балансом порождает одну или несколько записей:
+// перевод и обмен - по записи на каждую сторону
+const (
+	TransactionDeposit          = "deposit"           // Пополнение
+	TransactionWithdraw         = "withdraw"          // Снятие
+	TransactionTransferOut      = "transfer_out"      // Исходящий перевод (списание у отправителя)
+	TransactionTransferIn       = "transfer_in"       // Входящий перевод (зачисление получателю)
+	TransactionExchangeOut      = "exchange_out"      // Списание исходной валюты при обмене
+	TransactionExchangeIn       = "exchange_in"       // Зачисление целевой валюты при обмене
+	TransactionOpening          = "opening"           // Входящий остаток кошелька, созданного до появления журнала
+	TransactionAdminCredit      = "admin_credit"      // Зачисление администратором (корректировка, промо-акция)
+	TransactionAdminDebit       = "admin_debit"       // Списание администратором (корректировка)
+	TransactionPromoBonus       = "promo_bonus"       // Бонус по промокоду
+	TransactionFee              = "fee"               // Комиссия за обмен (в целевой валюте)
+	TransactionExchangeReversal = "exchange_reversal" // Сторнирование обмена при отмене многошаговой операции
+)
+
+// Transaction - запись журнала операций с балансом
+// swagger:model Transaction
+type Transaction struct {
+	ID             int64     `json:"id" db:"id"`                                     // Идентификатор записи
+	OperationID    string    `json:"operation_id" db:"operation_id"`                 // Идентификатор операции (общий для всех ее записей)
+	UserID         int       `json:"user_id" db:"user_id"`                           // Владелец кошелька
+	Type           string    `json:"type" db:"type"`                                 // Тип записи (deposit, withdraw, transfer_in, ...)
+	Currency       string    `json:"currency" db:"currency"`                         // Валюта
+	Amount         float64   `json:"amount" db:"amount"`                             // Сумма со знаком: положительная - зачисление, отрицательная - списание
+	Rate           *float64  `json:"rate,omitempty" db:"rate"`                       // Курс обмена (только для обмена)
+	CounterpartyID *int      `json:"counterparty_id,omitempty" db:"counterparty_id"` // Вторая сторона перевода (только для переводов)
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`                     // Время операции
+
+	// Поля, заполняемые по настройкам пользователя (только в истории операций)
+	LocalTime       string   `json:"local_time,omitempty" db:"-"`       // Время операции в часовом поясе пользователя
+	AmountFormatted string   `json:"amount_formatted,omitempty" db:"-"` // Сумма, отформатированная по локали
+	DisplayAmount   *float64 `json:"display_amount,omitempty" db:"-"`   // Сумма в валюте отображения по текущему курсу
+}
+
+// TransactionHistoryResponse - ответ с историей операций за период
+// swagger:model TransactionHistoryResponse
+type TransactionHistoryResponse struct {
+	From         time.Time     `json:"from"`         // Начало периода (включительно)
+	To           time.Time     `json:"to"`           // Конец периода (не включительно)
+	Transactions []Transaction `json:"transactions"` // Записи журнала, от новых к старым
+
+	DisplayCurrency string `json:"display_currency,omitempty"` // Валюта отображения из настроек пользователя
+	Timezone        string `json:"timezone,omitempty"`         // Часовой пояс, в котором указано local_time
+}
+
+// ExchangeRecord - выполненный обмен валюты: записи exchange_out, exchange_in и fee одной операции
+// swagger:model ExchangeRecord
+type ExchangeRecord struct {
+	OperationID     string    `json:"operation_id"`       // Идентификатор операции в журнале
+	FromCurrency    string    `json:"from_currency"`      // Исходная валюта
+	ToCurrency      string    `json:"to_currency"`        // Целевая валюта
+	Amount          float64   `json:"amount"`             // Списанная сумма в исходной валюте
+	Rate            float64   `json:"rate"`               // Примененный курс (целевая валюта за единицу исходной)
+	ExchangedAmount float64   `json:"exchanged_amount"`   // Сумма по курсу в целевой валюте до комиссии
+	Fee             float64   `json:"fee"`                // Комиссия в целевой валюте
+	ReceivedAmount  float64   `json:"received_amount"`    // Зачисленная сумма в целевой валюте за вычетом комиссии
+	CreatedAt       time.Time `json:"created_at"`         // Время обмена
+	QuoteID         string    `json:"quote_id,omitempty"` // Котировка, подтвержденная пользователем (пусто - обмен по текущему курсу)
+	RateProvenance            // Источники и время получения курса (пусто для обменов до появления аудита)
+}
+
+// ExchangeHistoryResponse - ответ с историей обменов за период
+// swagger:model ExchangeHistoryResponse
+type ExchangeHistoryResponse struct {
+	From      time.Time        `json:"from"`      // Начало периода (включительно)
+	To        time.Time        `json:"to"`        // Конец периода (не включительно)
+	Exchanges []ExchangeRecord `json:"exchanges"` // Обмены, от новых к старым
+}
+
+// BalanceMismatch - расхождение баланса кошелька с суммой записей журнала по одной валюте
+// swagger:model BalanceMismatch
+type BalanceMismatch struct {
+	UserID        int     `json:"user_id"`        // Владелец кошелька
+	Currency      string  `json:"currency"`       // Валюта
+	WalletBalance float64 `json:"wallet_balance"` // Баланс в таблице кошельков
+	LedgerBalance float64 `json:"ledger_balance"` // Баланс, пересчитанный по журналу (включая архив)
+	Difference    float64 `json:"difference"`     // Разница: wallet_balance - ledger_balance
+}
+
+// ReconciliationReport - результат сверки балансов с журналом операций
+// swagger:model ReconciliationReport
+type ReconciliationReport struct {
+	StartedAt   time.Time         `json:"started_at"`            // Начало сверки
+	FinishedAt  time.Time         `json:"finished_at"`           // Окончание сверки
+	Mismatches  []BalanceMismatch `json:"mismatches"`            // Найденные расхождения
+	Quarantined []int             `json:"quarantined,omitempty"` // Кошельки, заблокированные по итогам сверки
+}
+
+// LoyaltyStatus - уровень лояльности пользователя и прогресс до следующего уровня
+// swagger:model LoyaltyStatus
+type LoyaltyStatus struct {
+	Tier             string   `json:"tier"`                          // Текущий уровень
+	DiscountPercent  float64  `json:"discount_percent"`              // Скидка на комиссию обмена, %
+	FeePercent       float64  `json:"fee_percent"`                   // Комиссия обмена с учетом скидки, %
+	Volume30d        float64  `json:"volume_30d"`                    // Объем обменов за 30 дней в USD
+	NextTier         string   `json:"next_tier,omitempty"`           // Следующий уровень (пусто для максимального)
+	NextTierVolume   *float64 `json:"next_tier_volume,omitempty"`    // Объем, с которого действует следующий уровень
+	VolumeToNextTier *float64 `json:"volume_to_next_tier,omitempty"` // Сколько осталось до следующего уровня
+}
@@ -7,12 +7,45 @@ import (
 // User представляет основную модель пользователя в системе
 // swagger:model User
 type User struct {
-	ID           int       `json:"id" db:"id"`                 // Уникальный идентификатор пользователя
-	Username     string    `json:"username" db:"username"`     // Логин пользователя (уникальный)
-	Email        string    `json:"email" db:"email"`           // Email пользователя (уникальный)
-	PasswordHash string    `json:"-" db:"password_hash"`       // Хэш пароля (никогда не возвращается в API)
-	CreatedAt    time.Time `json:"created_at" db:"created_at"` // Дата создания записи
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"` // Дата последнего обновления
+	ID           int       `json:"id" db:"id"`                             // Уникальный идентификатор пользователя
+	TenantID     string    `json:"tenant_id" db:"tenant_id"`               // Арендатор (брендированный продукт), которому принадлежит пользователь
+	Username     string    `json:"username" db:"username"`                 // Логин пользователя (уникальный у арендатора)
+	Email        string    `json:"email" db:"email"`                       // Email пользователя (уникальный у арендатора)
+	PasswordHash string    `json:"-" db:"password_hash"`                   // Хэш пароля (никогда не возвращается в API)
+	Role         string    `json:"role" db:"role"`                         // Роль пользователя (user, admin)
+	Plan         string    `json:"plan" db:"plan"`                         // Тарифный план (free, premium): квота запросов
+	KYCStatus    string    `json:"kyc_status" db:"kyc_status"`             // Статус верификации (none, pending, verified, rejected)
+	KYCComment   string    `json:"kyc_comment,omitempty" db:"kyc_comment"` // Комментарий администратора по верификации
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`             // Дата создания записи
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`             // Дата последнего обновления
+}
+
+// Роли пользователей
+const (
+	RoleUser  = "user"  // Обычный пользователь
+	RoleAdmin = "admin" // Администратор (доступ к /api/v1/admin)
+)
+
+// Поля сортировки при поиске пользователей
+const (
+	UserSortID        = "id"         // По ID пользователя
+	UserSortCreatedAt = "created_at" // По дате регистрации
+	UserSortUsername  = "username"   // По имени пользователя
+	UserSortEmail     = "email"      // По email
+)
+
+// UserSearch - параметры поиска пользователей администратором
+type UserSearch struct {
+	Query         string    // Префикс имени пользователя или email без учета регистра (пусто - все)
+	TenantID      string    // Арендатор (пусто - все арендаторы)
+	KYCStatus     string    // Статус верификации (пусто - любой)
+	Role          string    // Роль (пусто - любая)
+	Plan          string    // Тарифный план (пусто - любой)
+	From          time.Time // Начало периода регистрации, включительно (нулевое - без ограничения)
+	To            time.Time // Конец периода регистрации, не включительно (нулевое - без ограничения)
+	Sort          string    // Поле сортировки (UserSort*)
+	Desc          bool      // Сортировка по убыванию
+	Limit, Offset int       // Страница результатов
 }
 
 // CreateUserRequest - запрос на регистрацию нового пользователя
@@ -33,16 +66,44 @@ type LoginRequest struct {
 // Balance - модель баланса пользователя по валютам
 // swagger:model Balance
 type Balance struct {
-	USD float64 `json:"USD" db:"USD"` // Сумма в долларах
-	RUB float64 `json:"RUB" db:"RUB"` // Сумма в рублях
-	EUR float64 `json:"EUR" db:"EUR"` // Сумма в евро
+	USD       float64    `json:"USD" db:"USD"`                         // Сумма в долларах
+	RUB       float64    `json:"RUB" db:"RUB"`                         // Сумма в рублях
+	EUR       float64    `json:"EUR" db:"EUR"`                         // Сумма в евро
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at"` // Время последнего изменения кошелька
+}
+
+// UserBalance - баланс пользователя в списке балансов (администрирование и отчеты)
+// swagger:model UserBalance
+type UserBalance struct {
+	UserID   int    `json:"user_id"`  // ID пользователя
+	Username string `json:"username"` // Имя пользователя
+	Balance         // Балансы по валютам
+}
+
+// BalanceValuation - оценка баланса в одной валюте
+// swagger:model BalanceValuation
+type BalanceValuation struct {
+	Currency string  `json:"currency"` // Валюта баланса
+	Amount   float64 `json:"amount"`   // Баланс в этой валюте
+	Rate     float64 `json:"rate"`     // Курс к валюте оценки
+	Value    float64 `json:"value"`    // Баланс в валюте оценки
+}
+
+// BalanceTotal - суммарная стоимость всех балансов в одной валюте
+// swagger:model BalanceTotal
+type BalanceTotal struct {
+	Currency  string             `json:"currency"`  // Валюта оценки
+	Total     float64            `json:"total"`     // Сумма всех балансов в валюте оценки
+	Breakdown []BalanceValuation `json:"breakdown"` // Оценка по каждой валюте
+	ValuedAt  time.Time          `json:"valued_at"` // Момент оценки (курсы на это время)
 }
 
 // DepositRequest - запрос на пополнение баланса
 // swagger:model DepositRequest
 type DepositRequest struct {
-	Amount   float64 `json:"amount" validate:"required,gt=0"`                // Сумма пополнения (>0)
-	Currency string  `json:"currency" validate:"required,oneof=USD RUB EUR"` // Валюта (USD/RUB/EUR)
+	Amount    float64 `json:"amount" validate:"required,gt=0"`                // Сумма пополнения (>0)
+	Currency  string  `json:"currency" validate:"required,oneof=USD RUB EUR"` // Валюта (USD/RUB/EUR)
+	PromoCode string  `json:"promo_code,omitempty"`                           // Промокод на бонус к пополнению (необязательно)
 }
 
 // WithdrawRequest - запрос на снятие средств
@@ -61,18 +122,49 @@ type ExchangeRatesResponse struct {
 // ExchangeRequest - запрос на обмен валюты
 // swagger:model ExchangeRequest
 type ExchangeRequest struct {
-	FromCurrency string  `json:"from_currency" validate:"required,oneof=USD RUB EUR"` // Исходная валюта
-	ToCurrency   string  `json:"to_currency" validate:"required,oneof=USD RUB EUR"`   // Целевая валюта
-	Amount       float64 `json:"amount" validate:"required,gt=0"`                     // Сумма для обмена (>0)
+	FromCurrency string  `json:"from_currency" validate:"required,oneof=USD RUB EUR"`             // Исходная валюта
+	ToCurrency   string  `json:"to_currency" validate:"required,oneof=USD RUB EUR"`               // Целевая валюта
+	Amount       float64 `json:"amount" validate:"required,gt=0"`                                 // Сумма для обмена (>0)
+	OutsideHours string  `json:"outside_hours,omitempty" validate:"omitempty,oneof=reject queue"` // Вне окна исполнения пары: reject (по умолчанию) или queue
+	Execute      string  `json:"execute,omitempty" validate:"omitempty,oneof=now next_rate"`      // Момент исполнения: now (по умолчанию) или next_rate - по следующему опубликованному курсу
 }
 
 // ExchangeResponse - результат операции обмена валют
 // swagger:model ExchangeResponse
 type ExchangeResponse struct {
 	Message         string   `json:"message"`          // Сообщение о результате
-	ExchangedAmount float64  `json:"exchanged_amount"` // Полученная сумма
+	ExchangedAmount float64  `json:"exchanged_amount"` // Полученная сумма (за вычетом комиссии)
 	NewBalance      *Balance `json:"new_balance"`      // Обновленный баланс
 	Rate            float64  `json:"rate"`             // Примененный курс обмена
+	Fee             float64  `json:"fee"`              // Комиссия в целевой валюте
+	FeePercent      float64  `json:"fee_percent"`      // Примененная комиссия в процентах (с учетом скидки уровня)
+	Tier            string   `json:"tier,omitempty"`   // Уровень лояльности пользователя
+	RateProvenance           // Источники и время получения примененного курса
+	DryRun          bool     `json:"dry_run,omitempty"` // Пробный режим: обмен рассчитан, но не выполнен
+}
+
+// ExchangeQuote - расчет обмена валют по курсу, действующий до подтверждения или истечения срока
+// swagger:model ExchangeQuote
+type ExchangeQuote struct {
+	ID              string    `json:"id"`                   // Идентификатор котировки
+	UserID          int       `json:"-"`                    // Пользователь, для которого рассчитан обмен
+	FromCurrency    string    `json:"from_currency"`        // Исходная валюта
+	ToCurrency      string    `json:"to_currency"`          // Целевая валюта
+	Amount          float64   `json:"amount"`               // Сумма для обмена
+	Rate            float64   `json:"rate"`                 // Курс обмена
+	Fee             float64   `json:"fee"`                  // Комиссия в целевой валюте
+	FeePercent      float64   `json:"fee_percent"`          // Комиссия в процентах (с учетом скидки уровня)
+	Tier            string    `json:"tier,omitempty"`       // Уровень лояльности пользователя
+	ExchangedAmount float64   `json:"exchanged_amount"`     // Сумма к получению (за вычетом комиссии)
+	ExpiresAt       time.Time `json:"expires_at,omitempty"` // Срок действия котировки
+	RateProvenance            // Источники и время получения примененного курса
+}
+
+// RateProvenance - происхождение курса обмена: по нему разбираются споры о примененном курсе
+// swagger:model RateProvenance
+type RateProvenance struct {
+	RateSource    string     `json:"rate_source,omitempty"`    // Источники курсов валют пары у сервиса обмена (cbr, ecb, manual; через запятую)
+	RateTimestamp *time.Time `json:"rate_timestamp,omitempty"` // Время получения курсов от сервиса обмена
 }
 
 // Wallet - модель кошелька пользователя в БД
@@ -89,7 +181,9 @@ type Wallet struct {
 // ErrorResponse - стандартный ответ при ошибке
 // swagger:model ErrorResponse
 type ErrorResponse struct {
-	Error string `json:"error"` // Описание ошибки
+	Error   string `json:"error"`              // Описание ошибки
+	Code    string `json:"code,omitempty"`     // Код ошибки аутентификации в ответах 401 (token_expired, token_malformed и др.)
+	TraceID string `json:"trace_id,omitempty"` // Идентификатор запроса (заголовок X-Request-ID) для поиска в журнале
 }
 
 // SuccessMessage - стандартный успешный ответ
@@ -102,7 +196,9 @@ type SuccessMessage struct {
 // LoginResponse - ответ с JWT токеном при успешной аутентификации
 // swagger:model LoginResponse
 type LoginResponse struct {
-	Token string `json:"token"` // JWT токен для авторизации
+	Token            string    `json:"token"`              // JWT токен для авторизации
+	RefreshToken     string    `json:"refresh_token"`      // Токен обновления: новый JWT без повторного входа (POST /token/refresh)
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"` // Срок действия токена обновления
 }
 
 // TransactionRequest - обобщенный запрос для операций с балансом
@@ -115,6 +211,7 @@ type TransactionRequest struct {
 // TransactionResponse - обобщенный ответ для операций с балансом
 // swagger:model TransactionResponse
 type TransactionResponse struct {
-	Message    string   `json:"message"`     // Сообщение о результате
-	NewBalance *Balance `json:"new_balance"` // Обновленный баланс
+	Message    string   `json:"message"`           // Сообщение о результате
+	NewBalance *Balance `json:"new_balance"`       // Обновленный баланс (в пробном режиме - баланс после операции)
+	DryRun     bool     `json:"dry_run,omitempty"` // Пробный режим: операция проверена, но не выполнена
 }
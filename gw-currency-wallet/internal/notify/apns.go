@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"gw-currency-wallet/internal/models"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Адреса Apple Push Notification service (HTTP/2)
+const (
+	apnsProductionURL = "https://api.push.apple.com/3/device/"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com/3/device/"
+
+	// apnsTokenLifetime - время использования JWT провайдера: Apple принимает токен не старше часа
+	// и отклоняет обновление чаще, чем раз в 20 минут
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsOptions содержит параметры отправки через APNs
+type APNsOptions struct {
+	KeyFile string        // Ключ аутентификации APNs (.p8)
+	KeyID   string        // Идентификатор ключа
+	TeamID  string        // Идентификатор команды разработчика Apple
+	Topic   string        // Bundle ID приложения
+	Sandbox bool          // Среда разработки (сборки из Xcode и TestFlight - production)
+	Timeout time.Duration // Таймаут запроса
+}
+
+// APNs отправляет push уведомления через Apple Push Notification service
+// Авторизация - JWT провайдера, подписанный ключом аутентификации APNs (ES256)
+type APNs struct {
+	opts   APNsOptions
+	key    *ecdsa.PrivateKey
+	url    string
+	client *http.Client
+
+	mu       sync.Mutex
+	jwt      string    // JWT провайдера
+	issuedAt time.Time // Время выпуска JWT
+}
+
+// NewAPNs создает отправку через APNs
+// Параметры:
+//   - opts: ключ аутентификации, идентификаторы ключа и команды, bundle ID, среда
+//
+// Возвращает:
+//   - *APNs: отправка через APNs
+//   - error: ошибка чтения или разбора ключа
+func NewAPNs(opts APNsOptions) (*APNs, error) {
+	data, err := os.ReadFile(opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ключа APNs: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора ключа APNs: %w", err)
+	}
+	url := apnsProductionURL
+	if opts.Sandbox {
+		url = apnsSandboxURL
+	}
+	// Стандартный транспорт согласует HTTP/2, который требует APNs
+	return &APNs{opts: opts, key: key, url: url, client: &http.Client{Timeout: opts.Timeout}}, nil
+}
+
+// Platform возвращает платформу
+func (a *APNs) Platform() string {
+	return models.PushPlatformAPNs
+}
+
+// Push отправляет уведомление на устройство
+func (a *APNs) Push(ctx context.Context, token string, msg PushMessage) error {
+	providerToken, err := a.token()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range msg.Data {
+		payload[key] = value
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("ошибка формирования уведомления APNs: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url+token, bytes.NewReader(body))
+	if err != nil {
+		return ErrInvalidPushToken // Токен не образует корректный адрес
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", a.opts.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления APNs: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&result)
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		result.Reason == "BadDeviceToken", result.Reason == "DeviceTokenNotForTopic":
+		return ErrInvalidPushToken
+	case result.Reason == "ExpiredProviderToken":
+		a.resetToken()
+	}
+	return fmt.Errorf("APNs ответил %d: %s", resp.StatusCode, result.Reason)
+}
+
+// token возвращает JWT провайдера, выпуская новый после apnsTokenLifetime
+func (a *APNs) token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.jwt != "" && time.Since(a.issuedAt) < apnsTokenLifetime {
+		return a.jwt, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.opts.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = a.opts.KeyID
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("ошибка подписи токена APNs: %w", err)
+	}
+	a.jwt, a.issuedAt = signed, now
+	return signed, nil
+}
+
+// resetToken сбрасывает JWT провайдера, отклоненный APNs
+func (a *APNs) resetToken() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.jwt = ""
+}
@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUndeliverable - уведомление невозможно доставить по каналу (нет адреса получателя, адрес отклонен);
+// такое уведомление не отправляется повторно и попадает в dead letter
+var ErrUndeliverable = errors.New("получатель уведомления недоступен в канале")
+
+// Delivery - уведомление пользователю об операции, доставляемое каналом
+type Delivery struct {
+	ID          int64           // Идентификатор уведомления (ключ идемпотентности у получателя)
+	UserID      int             // Получатель
+	OperationID string          // Операция в журнале
+	Target      string          // Адрес в канале (email, чат Telegram, URL; пусто - адреса нет)
+	Subject     string          // Тема
+	Text        string          // Текст по локали получателя
+	Payload     json.RawMessage // Записи журнала операции, относящиеся к получателю
+}
+
+// Channel - плагин канала доставки уведомлений (email, Telegram, webhook, ...)
+type Channel interface {
+	// Name возвращает название канала (models.NotificationChannel*)
+	Name() string
+
+	// Send доставляет уведомление; ошибка с ErrUndeliverable прекращает повторы
+	Send(ctx context.Context, delivery Delivery) error
+}
+
+// Registry хранит подключенные каналы доставки по названиям
+type Registry struct {
+	channels map[string]Channel
+}
+
+// NewRegistry создает реестр каналов
+func NewRegistry(channels ...Channel) *Registry {
+	r := &Registry{channels: make(map[string]Channel, len(channels))}
+	for _, channel := range channels {
+		r.Register(channel)
+	}
+	return r
+}
+
+// Register подключает канал (канал с тем же названием заменяется)
+func (r *Registry) Register(channel Channel) {
+	r.channels[channel.Name()] = channel
+}
+
+// Channel возвращает подключенный канал по названию
+func (r *Registry) Channel(name string) (Channel, bool) {
+	channel, ok := r.channels[name]
+	return channel, ok
+}
+
+// Names возвращает названия подключенных каналов по алфавиту
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.channels))
+	for name := range r.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EmailChannel доставляет уведомления письмом через Notifier
+type EmailChannel struct {
+	name     string
+	notifier Notifier
+}
+
+// NewEmailChannel создает канал email
+// Параметры:
+//   - name: название канала
+//   - notifier: отправка писем (SMTP или журнал)
+func NewEmailChannel(name string, notifier Notifier) *EmailChannel {
+	return &EmailChannel{name: name, notifier: notifier}
+}
+
+// Name возвращает название канала
+func (c *EmailChannel) Name() string {
+	return c.name
+}
+
+// Send отправляет письмо на адрес доставки
+func (c *EmailChannel) Send(ctx context.Context, delivery Delivery) error {
+	if delivery.Target == "" {
+		return fmt.Errorf("%w: не указан email", ErrUndeliverable)
+	}
+	return c.notifier.Notify(ctx, Message{To: delivery.Target, Subject: delivery.Subject, Body: delivery.Text})
+}
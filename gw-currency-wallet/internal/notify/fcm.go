@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"gw-currency-wallet/internal/models"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Адреса Firebase Cloud Messaging HTTP v1
+const (
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// FCM отправляет push уведомления через Firebase Cloud Messaging HTTP v1 API
+// Авторизация - токен доступа OAuth 2.0, полученный по ключу сервисного аккаунта Google
+type FCM struct {
+	projectID   string
+	clientEmail string
+	tokenURI    string
+	key         *rsa.PrivateKey
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string    // Токен доступа OAuth 2.0
+	expiresAt   time.Time // Время, после которого токен доступа запрашивается заново
+}
+
+// fcmServiceAccount - поля файла ключа сервисного аккаунта Google
+type fcmServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewFCM создает отправку через FCM
+// Параметры:
+//   - credentialsFile: файл ключа сервисного аккаунта Google (JSON) с правом отправки сообщений Firebase
+//   - timeout: таймаут запроса
+//
+// Возвращает:
+//   - *FCM: отправка через FCM
+//   - error: ошибка чтения или разбора ключа
+func NewFCM(credentialsFile string, timeout time.Duration) (*FCM, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ключа FCM: %w", err)
+	}
+	var account fcmServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("ошибка разбора ключа FCM: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, fmt.Errorf("ключ FCM не содержит project_id, client_email или token_uri")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора ключа FCM: %w", err)
+	}
+	return &FCM{
+		projectID:   account.ProjectID,
+		clientEmail: account.ClientEmail,
+		tokenURI:    account.TokenURI,
+		key:         key,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Platform возвращает платформу
+func (f *FCM) Platform() string {
+	return models.PushPlatformFCM
+}
+
+// Push отправляет уведомление на устройство
+func (f *FCM) Push(ctx context.Context, token string, msg PushMessage) error {
+	accessToken, err := f.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"token":        token,
+			"notification": map[string]string{"title": msg.Title, "body": msg.Body},
+			"data":         msg.Data,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка формирования уведомления FCM: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmSendURL, f.projectID), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления FCM: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки уведомления FCM: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || strings.Contains(string(respBody), "UNREGISTERED"):
+		return ErrInvalidPushToken
+	case resp.StatusCode == http.StatusUnauthorized:
+		f.resetToken()
+	}
+	return fmt.Errorf("FCM ответил %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// token возвращает действующий токен доступа OAuth 2.0, при необходимости запрашивая новый
+// по подписанному ключом сервисного аккаунта JWT (grant jwt-bearer)
+func (f *FCM) token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.accessToken != "" && time.Now().Before(f.expiresAt) {
+		return f.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   f.clientEmail,
+		"scope": fcmScope,
+		"aud":   f.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(f.key)
+	if err != nil {
+		return "", fmt.Errorf("ошибка подписи запроса токена FCM: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения токена FCM: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения токена FCM: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ошибка получения токена FCM: ответ %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("ошибка получения токена FCM: некорректный ответ")
+	}
+	f.accessToken = result.AccessToken
+	// Токен обновляется за минуту до истечения
+	f.expiresAt = now.Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return f.accessToken, nil
+}
+
+// resetToken сбрасывает токен доступа, отклоненный FCM
+func (f *FCM) resetToken() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.accessToken = ""
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Message - уведомление пользователю
+type Message struct {
+	To      string // Адрес получателя (email)
+	Subject string // Тема
+	Body    string // Текст
+}
+
+// Notifier отправляет уведомления пользователям
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// SMTPOptions содержит параметры отправки email
+type SMTPOptions struct {
+	Addr     string // Адрес SMTP сервера (host:port)
+	From     string // Адрес отправителя
+	Username string // Имя пользователя SMTP (пусто - без аутентификации)
+	Password string // Пароль SMTP
+}
+
+// SMTP отправляет уведомления по email
+type SMTP struct {
+	opts SMTPOptions
+}
+
+// NewSMTP создает отправку уведомлений по email
+func NewSMTP(opts SMTPOptions) *SMTP {
+	return &SMTP{opts: opts}
+}
+
+// Notify отправляет письмо получателю
+func (s *SMTP) Notify(_ context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.opts.Username != "" {
+		host, _, _ := net.SplitHostPort(s.opts.Addr)
+		auth = smtp.PlainAuth("", s.opts.Username, s.opts.Password, host)
+	}
+
+	body := strings.Join([]string{
+		"From: " + s.opts.From,
+		"To: " + msg.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+	if err := smtp.SendMail(s.opts.Addr, auth, s.opts.From, []string{msg.To}, []byte(body)); err != nil {
+		return fmt.Errorf("ошибка отправки письма: %w", err)
+	}
+	return nil
+}
+
+// Log записывает уведомления в журнал (SMTP не настроен)
+type Log struct{}
+
+// Notify записывает тему уведомления в журнал; текст не пишется, так как может содержать коды подтверждения
+func (Log) Notify(_ context.Context, msg Message) error {
+	log.Printf("Уведомление для %s: %s (SMTP не настроен, письмо не отправлено)", msg.To, msg.Subject)
+	return nil
+}
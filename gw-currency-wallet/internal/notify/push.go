@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"log"
+	"strconv"
+)
+
+// ErrInvalidPushToken - платформа отклонила токен устройства (приложение удалено, токен устарел);
+// такой токен удаляется
+var ErrInvalidPushToken = errors.New("токен push уведомлений недействителен")
+
+// PushMessage - push уведомление на устройство
+type PushMessage struct {
+	Title string            // Заголовок
+	Body  string            // Текст
+	Data  map[string]string // Данные для приложения (операция, уведомление о курсе)
+}
+
+// PushProvider отправляет push уведомления на устройства одной платформы (FCM, APNs)
+type PushProvider interface {
+	// Platform возвращает платформу (models.PushPlatform*)
+	Platform() string
+
+	// Push отправляет уведомление на устройство; ErrInvalidPushToken - токен нужно удалить
+	Push(ctx context.Context, token string, msg PushMessage) error
+}
+
+// PushTokens - хранилище токенов push уведомлений пользователей
+type PushTokens interface {
+	ListPushTokens(ctx context.Context, userID int) ([]models.PushToken, error)
+	RemovePushToken(ctx context.Context, platform, token string) error
+}
+
+// PushChannel доставляет уведомления push на все зарегистрированные устройства пользователя
+type PushChannel struct {
+	name      string
+	tokens    PushTokens
+	providers map[string]PushProvider
+}
+
+// NewPushChannel создает канал push уведомлений
+// Параметры:
+//   - name: название канала
+//   - tokens: хранилище токенов устройств
+//   - providers: настроенные платформы (токены других платформ пропускаются)
+func NewPushChannel(name string, tokens PushTokens, providers ...PushProvider) *PushChannel {
+	c := &PushChannel{name: name, tokens: tokens, providers: make(map[string]PushProvider, len(providers))}
+	for _, provider := range providers {
+		c.providers[provider.Platform()] = provider
+	}
+	return c
+}
+
+// Name возвращает название канала
+func (c *PushChannel) Name() string {
+	return c.name
+}
+
+// Supports сообщает, настроена ли платформа
+func (c *PushChannel) Supports(platform string) bool {
+	_, ok := c.providers[platform]
+	return ok
+}
+
+// Send отправляет уведомление об операции на устройства получателя
+func (c *PushChannel) Send(ctx context.Context, delivery Delivery) error {
+	return c.Push(ctx, delivery.UserID, PushMessage{
+		Title: delivery.Subject,
+		Body:  delivery.Text,
+		Data: map[string]string{
+			"notification_id": strconv.FormatInt(delivery.ID, 10),
+			"operation_id":    delivery.OperationID,
+		},
+	})
+}
+
+// Push отправляет уведомление на все устройства пользователя
+// Уведомление считается доставленным, если его приняла платформа хотя бы одного устройства;
+// токены, отклоненные платформой, удаляются
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: получатель
+//   - msg: уведомление
+//
+// Возвращает:
+//   - error: ErrUndeliverable, если у пользователя нет действующих устройств, или ошибка последней отправки
+func (c *PushChannel) Push(ctx context.Context, userID int, msg PushMessage) error {
+	tokens, err := c.tokens.ListPushTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var delivered int
+	var lastErr error
+	for _, token := range tokens {
+		provider, ok := c.providers[token.Platform]
+		if !ok {
+			continue
+		}
+		err := provider.Push(ctx, token.Token, msg)
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, ErrInvalidPushToken):
+			if err := c.tokens.RemovePushToken(ctx, token.Platform, token.Token); err != nil {
+				log.Printf("Ошибка удаления недействительного токена push уведомлений %d: %v", token.ID, err)
+			}
+		default:
+			lastErr = err
+		}
+	}
+
+	switch {
+	case delivered > 0:
+		return nil
+	case lastErr != nil:
+		return lastErr
+	default:
+		return fmt.Errorf("%w: нет зарегистрированных устройств", ErrUndeliverable)
+	}
+}
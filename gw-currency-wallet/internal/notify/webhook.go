@@ -0,0 +1,162 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Заголовки подписи уведомлений webhook (та же схема, что у принимаемых webhook платежного провайдера)
+const (
+	WebhookIDHeader        = "X-Webhook-Id"        // Идентификатор уведомления (повторная доставка - тот же id)
+	WebhookTimestampHeader = "X-Webhook-Timestamp" // Время отправки (Unix, секунды)
+	WebhookNonceHeader     = "X-Webhook-Nonce"     // Одноразовое значение запроса
+	WebhookSignatureHeader = "X-Webhook-Signature" // hex(HMAC-SHA256(secret, timestamp.nonce.body))
+)
+
+// errPrivateAddress - адрес webhook указывает во внутреннюю сеть
+var errPrivateAddress = errors.New("адрес во внутренней сети запрещен")
+
+// WebhookOptions содержит параметры канала webhook
+type WebhookOptions struct {
+	Secret       string        // Секрет подписи запросов
+	Timeout      time.Duration // Таймаут запроса
+	AllowPrivate bool          // Разрешить адреса во внутренней сети (только для разработки)
+}
+
+// WebhookChannel доставляет уведомления подписанным POST запросом в JSON на адрес из правила пользователя
+type WebhookChannel struct {
+	name   string
+	opts   WebhookOptions
+	client *http.Client
+}
+
+// webhookBody - тело запроса уведомления
+type webhookBody struct {
+	ID          int64           `json:"id"`
+	OperationID string          `json:"operation_id"`
+	Subject     string          `json:"subject"`
+	Text        string          `json:"text"`
+	Entries     json.RawMessage `json:"entries"`
+}
+
+// NewWebhookChannel создает канал webhook
+// Без AllowPrivate соединения с адресами внутренней сети (loopback, частные, link-local) запрещены
+// при установке соединения, в том числе после перенаправления и для имен, разрешающихся в такие адреса
+// Параметры:
+//   - name: название канала
+//   - opts: секрет подписи, таймаут и доступ во внутреннюю сеть
+func NewWebhookChannel(name string, opts WebhookOptions) *WebhookChannel {
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &WebhookChannel{
+		name:   name,
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout, Transport: transport},
+	}
+}
+
+// ValidateWebhookURL проверяет адрес webhook из правила пользователя: абсолютный URL https
+func ValidateWebhookURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return errors.New("адрес webhook должен быть URL https без учетных данных")
+	}
+	return nil
+}
+
+// Name возвращает название канала
+func (c *WebhookChannel) Name() string {
+	return c.name
+}
+
+// Send отправляет уведомление на адрес доставки
+// Ответ 2xx - доставлено; 4xx, кроме 408 и 429, и адрес во внутренней сети - недоставляемо; остальное - повтор
+func (c *WebhookChannel) Send(ctx context.Context, delivery Delivery) error {
+	if err := ValidateWebhookURL(delivery.Target); err != nil {
+		return fmt.Errorf("%w: %v", ErrUndeliverable, err)
+	}
+	body, err := json.Marshal(webhookBody{
+		ID:          delivery.ID,
+		OperationID: delivery.OperationID,
+		Subject:     delivery.Subject,
+		Text:        delivery.Text,
+		Entries:     delivery.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка формирования webhook: %w", err)
+	}
+
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return fmt.Errorf("ошибка формирования webhook: %w", err)
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(c.opts.Secret))
+	mac.Write([]byte(timestamp + "." + nonce + "."))
+	mac.Write(body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUndeliverable, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookNonceHeader, nonce)
+	req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errPrivateAddress) {
+			return fmt.Errorf("%w: %v", ErrUndeliverable, err)
+		}
+		return fmt.Errorf("ошибка отправки webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: webhook ответил %d", ErrUndeliverable, resp.StatusCode)
+	default:
+		return fmt.Errorf("webhook ответил %d", resp.StatusCode)
+	}
+}
+
+// isPrivateIP сообщает, относится ли адрес к внутренней сети
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() || ip.IsInterfaceLocalMulticast()
+}
@@ -0,0 +1,253 @@
+// Package pdf формирует простые документы PDF: текст, линии и заливка на страницах A4
+// Текст набирается шрифтом Roboto (встраивается в документ), поэтому поддерживает кириллицу и знаки валют
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"github.com/golang/freetype/truetype"
+	"github.com/wcharczuk/go-chart/v2/roboto" // Шрифт Roboto Medium (TTF)
+	"golang.org/x/image/math/fixed"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf16"
+)
+
+// Размер страницы A4 в пунктах
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// fontName - имя встроенного шрифта в документе
+const fontName = "Roboto-Medium"
+
+var (
+	fontOnce   sync.Once
+	parsedFont *truetype.Font // Метрики шрифта
+	fontFile   []byte         // Сжатый файл шрифта для встраивания
+	fontErr    error
+)
+
+// loadFont разбирает и сжимает встроенный шрифт один раз на процесс
+func loadFont() (*truetype.Font, error) {
+	fontOnce.Do(func() {
+		if parsedFont, fontErr = truetype.Parse(roboto.Roboto); fontErr == nil {
+			fontFile, fontErr = deflate(roboto.Roboto)
+		}
+	})
+	return parsedFont, fontErr
+}
+
+// Document - документ PDF, собираемый в памяти
+// Координаты - в пунктах от левого нижнего угла страницы
+type Document struct {
+	font   *truetype.Font
+	pages  []*bytes.Buffer            // Содержимое страниц
+	glyphs map[truetype.Index]rune    // Использованные глифы и их символы (ширины и ToUnicode)
+	widths map[truetype.Index]float64 // Ширина глифов в тысячных долях кегля
+}
+
+// New создает документ с одной пустой страницей
+func New() (*Document, error) {
+	font, err := loadFont()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки шрифта: %w", err)
+	}
+	d := &Document{
+		font:   font,
+		glyphs: make(map[truetype.Index]rune),
+		widths: make(map[truetype.Index]float64),
+	}
+	d.AddPage()
+	return d, nil
+}
+
+// AddPage начинает новую страницу; дальнейший вывод идет на нее
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+}
+
+// page возвращает содержимое текущей страницы
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Text выводит строку кеглем size; (x, y) - начало базовой линии
+func (d *Document) Text(x, y, size float64, text string) {
+	var hex strings.Builder
+	for _, r := range text {
+		fmt.Fprintf(&hex, "%04X", uint16(d.glyph(r)))
+	}
+	fmt.Fprintf(d.page(), "BT /F1 %s Tf %s %s Td <%s> Tj ET\n", num(size), num(x), num(y), hex.String())
+}
+
+// TextRight выводит строку, выровненную по правому краю right
+func (d *Document) TextRight(right, y, size float64, text string) {
+	d.Text(right-d.TextWidth(size, text), y, size, text)
+}
+
+// TextWidth возвращает ширину строки кеглем size в пунктах
+func (d *Document) TextWidth(size float64, text string) float64 {
+	var width float64
+	for _, r := range text {
+		width += d.widths[d.glyph(r)]
+	}
+	return width * size / 1000
+}
+
+// Line рисует отрезок толщиной width
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page(), "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// FillRect заливает прямоугольник оттенком серого gray (0 - черный, 1 - белый)
+func (d *Document) FillRect(x, y, width, height, gray float64) {
+	fmt.Fprintf(d.page(), "%s g %s %s %s %s re f 0 g\n", num(gray), num(x), num(y), num(width), num(height))
+}
+
+// glyph возвращает глиф символа и запоминает его для шрифта документа (0 - символа нет в шрифте)
+func (d *Document) glyph(r rune) truetype.Index {
+	index := d.font.Index(r)
+	if _, ok := d.widths[index]; !ok {
+		em := fixed.Int26_6(d.font.FUnitsPerEm())
+		d.widths[index] = float64(d.font.HMetric(em, index).AdvanceWidth) * 1000 / float64(em)
+		d.glyphs[index] = r
+	}
+	return index
+}
+
+// WriteTo записывает документ в w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	// Объекты: 1 - каталог, 2 - дерево страниц, 3-7 - шрифт, далее пары страница + содержимое
+	const firstPage = 8
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		d.pagesObject(firstPage),
+		fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+			"/DescendantFonts [4 0 R] /ToUnicode 6 0 R >>", fontName),
+		fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor 5 0 R /CIDToGIDMap /Identity /W [%s] >>", fontName, d.widthsArray()),
+		d.descriptorObject(),
+		stream(d.toUnicode(), ""),
+		stream(fontFile, fmt.Sprintf("/Length1 %d /Filter /FlateDecode", len(roboto.Roboto))),
+	}
+	for i, content := range d.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+				"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				num(PageWidth), num(PageHeight), firstPage+2*i+1),
+			stream(content.Bytes(), ""),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.WriteTo(w)
+}
+
+// pagesObject возвращает дерево страниц; страницы - объекты first, first+2, ...
+func (d *Document) pagesObject(first int) string {
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", first+2*i)
+	}
+	return fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))
+}
+
+// descriptorObject возвращает описание шрифта с габаритами в тысячных долях кегля
+func (d *Document) descriptorObject() string {
+	em := fixed.Int26_6(d.font.FUnitsPerEm())
+	bounds := d.font.Bounds(em)
+	scale := func(v fixed.Int26_6) string { return num(float64(v) * 1000 / float64(em)) }
+	return fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%s %s %s %s] "+
+		"/ItalicAngle 0 /Ascent %s /Descent %s /CapHeight 711 /StemV 80 /FontFile2 7 0 R >>",
+		fontName, scale(bounds.Min.X), scale(bounds.Min.Y), scale(bounds.Max.X), scale(bounds.Max.Y),
+		scale(bounds.Max.Y), scale(bounds.Min.Y))
+}
+
+// widthsArray возвращает ширины использованных глифов для /W
+func (d *Document) widthsArray() string {
+	var b strings.Builder
+	for _, index := range d.usedGlyphs() {
+		fmt.Fprintf(&b, "%d [%s] ", index, num(d.widths[index]))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// toUnicode возвращает CMap соответствия глифов символам (копирование и поиск текста)
+func (d *Document) toUnicode() []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	used := d.usedGlyphs()
+	// В блоке bfchar не больше 100 записей
+	for start := 0; start < len(used); start += 100 {
+		chunk := used[start:min(start+100, len(used))]
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(chunk))
+		for _, index := range chunk {
+			fmt.Fprintf(&b, "<%04X> <", uint16(index))
+			for _, unit := range utf16.Encode([]rune{d.glyphs[index]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// usedGlyphs возвращает использованные глифы по возрастанию
+func (d *Document) usedGlyphs() []truetype.Index {
+	used := make([]truetype.Index, 0, len(d.glyphs))
+	for index := range d.glyphs {
+		used = append(used, index)
+	}
+	slices.Sort(used)
+	return used
+}
+
+// stream возвращает объект-поток с данными data и дополнительными ключами словаря
+func stream(data []byte, keys string) string {
+	if keys != "" {
+		keys = " " + keys
+	}
+	return fmt.Sprintf("<< /Length %d%s >>\nstream\n%s\nendstream", len(data), keys, data)
+}
+
+// deflate сжимает данные для /FlateDecode
+func deflate(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	writer := zlib.NewWriter(&b)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// num форматирует число для PDF (не больше 2 знаков после запятой)
+func num(v float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", v), "0")
+	return strings.TrimSuffix(s, ".")
+}
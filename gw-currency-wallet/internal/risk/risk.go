@@ -0,0 +1,98 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+)
+
+// Action - решение антифрода по операции
+type Action string
+
+// Возможные решения, упорядоченные по строгости
+const (
+	ActionAllow  Action = "allow"  // Операция выполняется
+	ActionReview Action = "review" // Операция откладывается до решения администратора
+	ActionDeny   Action = "deny"   // Операция отклоняется
+)
+
+// severity возвращает строгость решения для выбора наиболее строгого из нескольких
+func (a Action) severity() int {
+	switch a {
+	case ActionDeny:
+		return 2
+	case ActionReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Типы проверяемых операций
+const (
+	OperationWithdraw = "withdraw" // Снятие средств
+	OperationTransfer = "transfer" // Перевод другому пользователю
+)
+
+// Operation - операция, которую нужно оценить перед выполнением
+type Operation struct {
+	Type        string  // Тип операции (withdraw, transfer)
+	UserID      int     // Инициатор операции
+	RecipientID int     // Получатель (только для переводов)
+	Currency    string  // Валюта
+	Amount      float64 // Сумма
+}
+
+// Decision - результат оценки операции
+type Decision struct {
+	Action Action // Решение
+	Rule   string // Правило, принявшее решение (пусто для allow)
+	Reason string // Пояснение для журнала и администратора
+}
+
+// Allow - решение "разрешить" без замечаний
+var Allow = Decision{Action: ActionAllow}
+
+// Evaluator оценивает риск операции перед ее выполнением
+// Реализации должны быть безопасны для параллельного использования
+type Evaluator interface {
+	Evaluate(ctx context.Context, op Operation) (Decision, error)
+}
+
+// Rule - отдельное правило оценки риска
+type Rule interface {
+	// Name возвращает имя правила (для журнала и очереди проверки)
+	Name() string
+	// Evaluate оценивает операцию; для операций, к которым правило не относится, возвращает Allow
+	Evaluate(ctx context.Context, op Operation) (Decision, error)
+}
+
+// Engine применяет набор правил и возвращает наиболее строгое решение
+// Пустой набор правил разрешает любые операции
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine создает движок с указанными правилами
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Evaluate применяет все правила; решение deny прерывает проверку остальных
+func (e *Engine) Evaluate(ctx context.Context, op Operation) (Decision, error) {
+	result := Allow
+	for _, rule := range e.rules {
+		decision, err := rule.Evaluate(ctx, op)
+		if err != nil {
+			return Decision{}, fmt.Errorf("ошибка правила %s: %w", rule.Name(), err)
+		}
+		if decision.Action.severity() <= result.Action.severity() {
+			continue
+		}
+		decision.Rule = rule.Name()
+		result = decision
+		if result.Action == ActionDeny {
+			break
+		}
+	}
+	return result, nil
+}
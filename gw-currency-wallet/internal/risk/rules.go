@@ -0,0 +1,175 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"strconv"
+	"time"
+)
+
+// AmountRule отправляет на проверку или отклоняет операции с необычно большой суммой
+type AmountRule struct {
+	review map[string]float64 // Пороги проверки по валютам
+	deny   map[string]float64 // Пороги отказа по валютам
+}
+
+// NewAmountRule создает правило крупных сумм
+// Параметры:
+//   - review: валюта -> сумма, начиная с которой операция отправляется на проверку
+//   - deny: валюта -> сумма, начиная с которой операция отклоняется
+func NewAmountRule(review, deny map[string]float64) *AmountRule {
+	return &AmountRule{review: review, deny: deny}
+}
+
+// Name возвращает имя правила
+func (r *AmountRule) Name() string { return "unusual_amount" }
+
+// Evaluate сравнивает сумму операции с порогами для ее валюты
+func (r *AmountRule) Evaluate(_ context.Context, op Operation) (Decision, error) {
+	if limit, ok := r.deny[op.Currency]; ok && op.Amount >= limit {
+		return Decision{
+			Action: ActionDeny,
+			Reason: fmt.Sprintf("сумма %.2f %s превышает допустимый максимум %.2f", op.Amount, op.Currency, limit),
+		}, nil
+	}
+	if limit, ok := r.review[op.Currency]; ok && op.Amount >= limit {
+		return Decision{
+			Action: ActionReview,
+			Reason: fmt.Sprintf("сумма %.2f %s требует проверки (порог %.2f)", op.Amount, op.Currency, limit),
+		}, nil
+	}
+	return Allow, nil
+}
+
+// VelocityRule отклоняет операции сверх допустимого количества за окно времени
+// Счетчики хранятся в кэше, поэтому при Redis ограничение общее для всех экземпляров сервиса
+type VelocityRule struct {
+	cache  storage.Cache // Кэш со счетчиками операций
+	limit  int64         // Максимум операций за окно
+	window time.Duration // Длительность окна
+}
+
+// NewVelocityRule создает правило частоты операций
+// Параметры:
+//   - cache: кэш для счетчиков
+//   - limit: максимум снятий и переводов пользователя за окно
+//   - window: длительность окна
+func NewVelocityRule(cache storage.Cache, limit int, window time.Duration) *VelocityRule {
+	return &VelocityRule{cache: cache, limit: int64(limit), window: window}
+}
+
+// Name возвращает имя правила
+func (r *VelocityRule) Name() string { return "velocity" }
+
+// Evaluate учитывает операцию в счетчике пользователя и проверяет лимит
+// Пока Redis недоступен (storage.ErrCacheUnavailable), правило не применяется, чтобы сбой кэша
+// не останавливал снятия и переводы; остальные ошибки счетчика отклоняют операцию
+func (r *VelocityRule) Evaluate(ctx context.Context, op Operation) (Decision, error) {
+	count, err := r.cache.Incr(ctx, "risk:velocity:"+strconv.Itoa(op.UserID), r.window)
+	if errors.Is(err, storage.ErrCacheUnavailable) {
+		return Allow, nil
+	}
+	if err != nil {
+		return Decision{}, err
+	}
+	if count > r.limit {
+		return Decision{
+			Action: ActionDeny,
+			Reason: fmt.Sprintf("превышено количество операций: %d за %s", r.limit, r.window),
+		}, nil
+	}
+	return Allow, nil
+}
+
+// TransferHistory сообщает о переводах между пользователями
+type TransferHistory interface {
+	// CountTransfers возвращает количество переводов от fromUserID к toUserID
+	CountTransfers(ctx context.Context, fromUserID, toUserID int) (int, error)
+}
+
+// NewRecipientRule отправляет на проверку крупный первый перевод новому получателю
+type NewRecipientRule struct {
+	history    TransferHistory    // История переводов
+	thresholds map[string]float64 // Пороги суммы по валютам
+}
+
+// NewNewRecipientRule создает правило нового получателя
+// Параметры:
+//   - history: источник истории переводов
+//   - thresholds: валюта -> сумма первого перевода, начиная с которой нужна проверка
+func NewNewRecipientRule(history TransferHistory, thresholds map[string]float64) *NewRecipientRule {
+	return &NewRecipientRule{history: history, thresholds: thresholds}
+}
+
+// Name возвращает имя правила
+func (r *NewRecipientRule) Name() string { return "new_recipient" }
+
+// Evaluate проверяет, переводил ли пользователь средства этому получателю раньше
+func (r *NewRecipientRule) Evaluate(ctx context.Context, op Operation) (Decision, error) {
+	if op.Type != OperationTransfer {
+		return Allow, nil
+	}
+	limit, ok := r.thresholds[op.Currency]
+	if !ok || op.Amount < limit {
+		return Allow, nil
+	}
+
+	count, err := r.history.CountTransfers(ctx, op.UserID, op.RecipientID)
+	if err != nil {
+		return Decision{}, err
+	}
+	if count == 0 {
+		return Decision{
+			Action: ActionReview,
+			Reason: fmt.Sprintf("первый перевод получателю %d на сумму %.2f %s", op.RecipientID, op.Amount, op.Currency),
+		}, nil
+	}
+	return Allow, nil
+}
+
+// UserDirectory предоставляет данные пользователей
+type UserDirectory interface {
+	// GetUserByID возвращает пользователя или nil, если он не найден
+	GetUserByID(ctx context.Context, id int) (*models.User, error)
+}
+
+// KYCRule отклоняет операции сверх пониженных лимитов для пользователей без верификации
+type KYCRule struct {
+	users  UserDirectory      // Источник статуса верификации
+	limits map[string]float64 // Максимальная сумма операции без верификации по валютам
+}
+
+// NewKYCRule создает правило лимитов для неверифицированных пользователей
+// Параметры:
+//   - users: источник данных пользователей
+//   - limits: валюта -> максимальная сумма одной операции без верификации
+func NewKYCRule(users UserDirectory, limits map[string]float64) *KYCRule {
+	return &KYCRule{users: users, limits: limits}
+}
+
+// Name возвращает имя правила
+func (r *KYCRule) Name() string { return "kyc_limit" }
+
+// Evaluate проверяет статус верификации, только если сумма превышает лимит
+func (r *KYCRule) Evaluate(ctx context.Context, op Operation) (Decision, error) {
+	limit, ok := r.limits[op.Currency]
+	if !ok || op.Amount <= limit {
+		return Allow, nil
+	}
+
+	user, err := r.users.GetUserByID(ctx, op.UserID)
+	if err != nil {
+		return Decision{}, err
+	}
+	if user != nil && user.KYCStatus == models.KYCVerified {
+		return Allow, nil
+	}
+	return Decision{
+		Action: ActionDeny,
+		Reason: fmt.Sprintf("сумма %.2f %s превышает лимит %.2f для пользователей без верификации",
+			op.Amount, op.Currency, limit),
+	}, nil
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/acme/autocert" // Автоматический выпуск сертификатов Let's Encrypt
+	"gw-currency-wallet/internal/config"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Server объединяет основной HTTP(S) сервер приложения и вспомогательный
+// HTTP сервер для ACME challenge (используется только в режиме autocert)
+type Server struct {
+	mode        string            // Режим TLS (none, file, autocert)
+	certFile    string            // Путь к сертификату (режим file)
+	keyFile     string            // Путь к ключу (режим file)
+	main        *http.Server      // Основной сервер с API
+	challenge   *http.Server      // Сервер для HTTP-01 challenge и редиректа на HTTPS
+	certManager *autocert.Manager // Менеджер сертификатов Let's Encrypt (режим autocert)
+}
+
+// New создает сервер в соответствии с настройками TLS из конфигурации
+// Параметры:
+//   - cfg: конфигурация приложения
+//   - handler: обработчик запросов (роутер Gin)
+//
+// Возвращает:
+//   - *Server: сервер, готовый к запуску
+//   - error: ошибка при некорректных настройках TLS
+func New(cfg *config.Config, handler http.Handler) (*Server, error) {
+	s := &Server{
+		mode:     cfg.TLSMode,
+		certFile: cfg.TLSCertFile,
+		keyFile:  cfg.TLSKeyFile,
+		main: &http.Server{
+			Addr:              cfg.ServerAddress,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second, // Защита от медленных клиентов (Slowloris)
+		},
+	}
+
+	switch cfg.TLSMode {
+	case "", config.TLSModeNone:
+		s.mode = config.TLSModeNone
+
+	case config.TLSModeFile:
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return nil, errors.New("для режима TLS file необходимо указать TLS_CERT_FILE и TLS_KEY_FILE")
+		}
+		s.main.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	case config.TLSModeAutocert:
+		if len(cfg.TLSAutocertDomains) == 0 {
+			return nil, errors.New("для режима TLS autocert необходимо указать TLS_AUTOCERT_DOMAINS")
+		}
+		s.certManager = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLSAutocertDomains...),
+			Cache:      autocert.DirCache(cfg.TLSAutocertCacheDir),
+			Email:      cfg.TLSAutocertEmail,
+		}
+		s.main.TLSConfig = s.certManager.TLSConfig()
+		s.main.TLSConfig.MinVersion = tls.VersionTLS12
+
+		// HTTP сервер отвечает на ACME challenge, остальные запросы перенаправляет на HTTPS
+		s.challenge = &http.Server{
+			Addr:              cfg.TLSHTTPAddr,
+			Handler:           s.certManager.HTTPHandler(nil),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+	default:
+		return nil, fmt.Errorf("неизвестный режим TLS: %s", cfg.TLSMode)
+	}
+
+	return s, nil
+}
+
+// Start запускает сервер (блокирующая операция)
+// Возвращает http.ErrServerClosed после вызова Shutdown
+func (s *Server) Start() error {
+	switch s.mode {
+	case config.TLSModeFile:
+		log.Printf("Запуск HTTPS сервера на %s (сертификат из файла)", s.main.Addr)
+		return s.main.ListenAndServeTLS(s.certFile, s.keyFile)
+
+	case config.TLSModeAutocert:
+		go func() {
+			log.Printf("Запуск HTTP сервера для ACME challenge на %s", s.challenge.Addr)
+			if err := s.challenge.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Ошибка HTTP сервера ACME challenge: %v", err)
+			}
+		}()
+		log.Printf("Запуск HTTPS сервера на %s (Let's Encrypt)", s.main.Addr)
+		// Сертификаты берутся из TLSConfig, поэтому пути к файлам не указываются
+		return s.main.ListenAndServeTLS("", "")
+
+	default:
+		log.Printf("Запуск сервера на %s", s.main.Addr)
+		return s.main.ListenAndServe()
+	}
+}
+
+// Shutdown корректно останавливает сервер, дожидаясь завершения активных запросов
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.challenge != nil {
+		_ = s.challenge.Shutdown(ctx)
+	}
+	return s.main.Shutdown(ctx)
+}
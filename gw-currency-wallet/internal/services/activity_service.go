@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+)
+
+// Ограничения запросов ленты активности
+const (
+	defaultActivityLimit = 50  // Количество событий по умолчанию
+	maxActivityLimit     = 200 // Максимальное количество событий на странице
+)
+
+// ErrInvalidActivityLimit возвращается для недопустимого размера страницы ленты
+var ErrInvalidActivityLimit = errors.New("недопустимый limit")
+
+// ActivityService ведет журнал активности пользователя и собирает ленту активности:
+// входы, изменения учетной записи и операции с деньгами
+type ActivityService struct {
+	repo storage.ActivityRepository // Журнал активности и лента
+}
+
+// NewActivityService создает сервис ленты активности
+// Параметры:
+//   - repo: репозиторий журнала активности
+//
+// Возвращает:
+//   - *ActivityService: инициализированный сервис
+func NewActivityService(repo storage.ActivityRepository) *ActivityService {
+	return &ActivityService{repo: repo}
+}
+
+// Record записывает событие в журнал активности
+// Ошибка записи только журналируется: действие пользователя уже выполнено и не отменяется
+func (s *ActivityService) Record(ctx context.Context, entry models.ActivityEntry) {
+	if err := s.repo.RecordActivity(ctx, entry); err != nil {
+		log.Printf("Ошибка записи активности %s пользователя %d: %v", entry.Action, entry.UserID, err)
+	}
+}
+
+// Feed возвращает страницу ленты активности пользователя
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - cursor: курсор из предыдущей страницы (пусто - первая страница)
+//   - limit: количество событий (0 - значение по умолчанию)
+//
+// Возвращает:
+//   - *models.ActivityFeed: события от новых к старым и курсор следующей страницы
+//   - error: ErrInvalidActivityLimit, storage.ErrInvalidCursor или ошибка хранилища
+func (s *ActivityService) Feed(ctx context.Context, userID int, cursor string, limit int) (*models.ActivityFeed, error) {
+	switch {
+	case limit == 0:
+		limit = defaultActivityLimit
+	case limit < 0 || limit > maxActivityLimit:
+		return nil, fmt.Errorf("%w: должен быть от 1 до %d", ErrInvalidActivityLimit, maxActivityLimit)
+	}
+
+	// Лишнее событие показывает, что есть следующая страница
+	events, err := s.repo.ListActivity(ctx, userID, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	feed := &models.ActivityFeed{Events: events}
+	if len(events) > limit {
+		feed.Events = events[:limit]
+		feed.NextCursor = events[limit-1].Cursor
+	}
+	return feed, nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"strings"
+)
+
+// ErrSelfApproval возвращается, если администратор пытается подтвердить собственную корректировку
+var ErrSelfApproval = errors.New("корректировку должен подтвердить другой администратор")
+
+// minAdjustmentReason - минимальная длина обоснования корректировки
+const minAdjustmentReason = 10
+
+// AdjustmentService реализует зачисление и списание средств администраторами
+type AdjustmentService struct {
+	repo             storage.AdjustmentRepository // Репозиторий корректировок и журнала действий
+	users            storage.UserRepository       // Репозиторий пользователей
+	approvalRequired bool                         // Требуется подтверждение вторым администратором
+}
+
+// NewAdjustmentService создает сервис корректировок баланса
+// Параметры:
+//   - repo: репозиторий корректировок
+//   - users: репозиторий пользователей
+//   - approvalRequired: true - корректировка проводится только после подтверждения другим администратором
+//
+// Возвращает:
+//   - *AdjustmentService: инициализированный сервис
+func NewAdjustmentService(
+	repo storage.AdjustmentRepository,
+	users storage.UserRepository,
+	approvalRequired bool,
+) *AdjustmentService {
+	return &AdjustmentService{
+		repo:             repo,
+		users:            users,
+		approvalRequired: approvalRequired,
+	}
+}
+
+// Request создает корректировку баланса
+// Без обязательного подтверждения корректировка сразу проводится; если провести ее не удалось
+// (например, списание больше баланса), она отклоняется с указанием причины
+// Параметры:
+//   - ctx: контекст выполнения
+//   - adminID: администратор, создающий корректировку
+//   - req: пользователь, валюта, сумма со знаком и обоснование
+//
+// Возвращает:
+//   - *models.BalanceAdjustment: корректировка (pending, если требуется подтверждение)
+//   - *models.Balance: новый баланс пользователя (nil, если корректировка ожидает подтверждения)
+//   - error: ошибка валидации или проведения
+func (s *AdjustmentService) Request(
+	ctx context.Context,
+	adminID int,
+	req models.AdjustmentRequest,
+) (*models.BalanceAdjustment, *models.Balance, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if len([]rune(reason)) < minAdjustmentReason {
+		return nil, nil, fmt.Errorf("обоснование должно содержать не менее %d символов", minAdjustmentReason)
+	}
+	if !isValidCurrency(req.Currency) {
+		return nil, nil, fmt.Errorf("неподдерживаемая валюта: %s", req.Currency)
+	}
+	if req.Amount == 0 {
+		return nil, nil, errors.New("сумма корректировки не может быть нулевой")
+	}
+
+	user, err := s.users.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, errors.New("пользователь не найден")
+	}
+
+	adj := &models.BalanceAdjustment{
+		UserID:      req.UserID,
+		Currency:    req.Currency,
+		Amount:      req.Amount,
+		Reason:      reason,
+		RequestedBy: adminID,
+	}
+	if err := s.repo.CreateAdjustment(ctx, adj); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("Администратор %d создал корректировку №%d: %.2f %s пользователю %d",
+		adminID, adj.ID, adj.Amount, adj.Currency, adj.UserID)
+
+	if s.approvalRequired {
+		return adj, nil, nil
+	}
+
+	applied, balance, err := s.repo.ApplyAdjustment(ctx, adj.ID, adminID)
+	if err != nil {
+		if _, rejectErr := s.repo.RejectAdjustment(ctx, adj.ID, adminID, err.Error()); rejectErr != nil {
+			log.Printf("Не удалось отклонить непроведенную корректировку №%d: %v", adj.ID, rejectErr)
+		}
+		return nil, nil, err
+	}
+	return applied, balance, nil
+}
+
+// Approve подтверждает и проводит корректировку
+// Параметры:
+//   - ctx: контекст выполнения
+//   - id: идентификатор корректировки
+//   - adminID: подтверждающий администратор (при обязательном подтверждении - не автор корректировки)
+//
+// Возвращает:
+//   - *models.BalanceAdjustment: проведенная корректировка
+//   - *models.Balance: новый баланс пользователя
+//   - error: ErrSelfApproval, storage.ErrAdjustmentNotFound или ошибка проведения
+func (s *AdjustmentService) Approve(ctx context.Context, id, adminID int) (*models.BalanceAdjustment, *models.Balance, error) {
+	adj, err := s.repo.GetAdjustment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.approvalRequired && adj.RequestedBy == adminID {
+		return nil, nil, ErrSelfApproval
+	}
+
+	applied, balance, err := s.repo.ApplyAdjustment(ctx, id, adminID)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("Администратор %d провел корректировку №%d", adminID, id)
+	return applied, balance, nil
+}
+
+// Reject отклоняет корректировку, ожидающую подтверждения
+// Параметры:
+//   - ctx: контекст выполнения
+//   - id: идентификатор корректировки
+//   - adminID: администратор
+//   - comment: причина отклонения
+//
+// Возвращает:
+//   - *models.BalanceAdjustment: отклоненная корректировка
+//   - error: storage.ErrAdjustmentNotFound, если корректировка не ожидает подтверждения
+func (s *AdjustmentService) Reject(ctx context.Context, id, adminID int, comment string) (*models.BalanceAdjustment, error) {
+	adj, err := s.repo.RejectAdjustment(ctx, id, adminID, strings.TrimSpace(comment))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Администратор %d отклонил корректировку №%d", adminID, id)
+	return adj, nil
+}
+
+// List возвращает корректировки в указанном статусе
+// Параметры:
+//   - ctx: контекст выполнения
+//   - status: статус (пустая строка - все)
+//   - limit: максимальное количество записей (0 - 100)
+//
+// Возвращает:
+//   - []models.BalanceAdjustment: корректировки от новых к старым
+//   - error: ошибка получения данных
+func (s *AdjustmentService) List(ctx context.Context, status string, limit int) ([]models.BalanceAdjustment, error) {
+	switch status {
+	case "", models.AdjustmentPending, models.AdjustmentApplied, models.AdjustmentRejected:
+	default:
+		return nil, fmt.Errorf("неизвестный статус корректировки: %s", status)
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.ListAdjustments(ctx, status, limit)
+}
+
+// AuditLog возвращает последние записи журнала действий администраторов
+// Параметры:
+//   - ctx: контекст выполнения
+//   - limit: максимальное количество записей (0 - 100)
+//
+// Возвращает:
+//   - []models.AuditEntry: записи от новых к старым
+//   - error: ошибка получения данных
+func (s *AdjustmentService) AuditLog(ctx context.Context, limit int) ([]models.AuditEntry, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.ListAuditLog(ctx, limit)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"slices"
+)
+
+// Ошибки уведомлений о курсах
+var (
+	ErrInvalidAlert  = errors.New("некорректное уведомление о курсе")
+	ErrAlertLimit    = errors.New("достигнуто максимальное количество уведомлений")
+	ErrAlertNotFound = errors.New("уведомление не найдено")
+)
+
+// AlertNotifyFunc отправляет сработавшее уведомление о курсе (rate - текущий курс пары)
+type AlertNotifyFunc func(ctx context.Context, alert models.RateAlert, rate float64) error
+
+// AlertService управляет уведомлениями о достижении курсом порога и проверяет их срабатывание
+type AlertService struct {
+	alerts storage.AlertRepository // Хранилище уведомлений
+	rates  RateProvider            // Текущие курсы (из кэша курсов)
+	limit  int                     // Максимум уведомлений одного чата
+}
+
+// NewAlertService создает сервис уведомлений о курсах
+// Параметры:
+//   - alerts: хранилище уведомлений
+//   - rates: провайдер текущих курсов
+//   - limit: максимальное количество уведомлений одного чата
+//
+// Возвращает:
+//   - *AlertService: инициализированный сервис
+func NewAlertService(alerts storage.AlertRepository, rates RateProvider, limit int) *AlertService {
+	return &AlertService{alerts: alerts, rates: rates, limit: limit}
+}
+
+// Create сохраняет уведомление о курсе
+// Параметры:
+//   - ctx: контекст выполнения
+//   - alert: уведомление (чат, пара валют, условие и порог)
+//
+// Возвращает:
+//   - error: ErrInvalidAlert, ErrAlertLimit или ошибка хранилища
+func (s *AlertService) Create(ctx context.Context, alert *models.RateAlert) error {
+	switch {
+	case !slices.Contains(supportedRateCurrencies, alert.FromCurrency),
+		!slices.Contains(supportedRateCurrencies, alert.ToCurrency),
+		alert.FromCurrency == alert.ToCurrency:
+		return fmt.Errorf("%w: поддерживаются пары валют USD, EUR, RUB", ErrInvalidAlert)
+	case alert.Condition != models.AlertAbove && alert.Condition != models.AlertBelow:
+		return fmt.Errorf("%w: условие должно быть > или <", ErrInvalidAlert)
+	case alert.Threshold <= 0:
+		return fmt.Errorf("%w: порог должен быть положительным", ErrInvalidAlert)
+	}
+
+	created, err := s.alerts.CreateAlert(ctx, alert, s.limit)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return fmt.Errorf("%w (%d)", ErrAlertLimit, s.limit)
+	}
+	return nil
+}
+
+// List возвращает уведомления чата в порядке создания
+func (s *AlertService) List(ctx context.Context, chatID int64) ([]models.RateAlert, error) {
+	return s.alerts.ListAlerts(ctx, chatID)
+}
+
+// Delete удаляет уведомление чата
+// Возвращает:
+//   - error: ErrAlertNotFound, если у чата нет такого уведомления, или ошибка хранилища
+func (s *AlertService) Delete(ctx context.Context, chatID int64, id int) error {
+	deleted, err := s.alerts.DeleteAlert(ctx, chatID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAlertNotFound
+	}
+	return nil
+}
+
+// Check проверяет все уведомления по текущим курсам и отправляет сработавшие
+// Отправленное уведомление удаляется; неотправленное проверяется снова при следующем запуске
+// Параметры:
+//   - ctx: контекст выполнения
+//   - notify: отправка сработавшего уведомления
+//
+// Возвращает:
+//   - error: ошибка получения уведомлений (ошибки курсов и отправки записываются в журнал)
+func (s *AlertService) Check(ctx context.Context, notify AlertNotifyFunc) error {
+	alerts, err := s.alerts.ListAlerts(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	rates := make(map[string]float64) // Курсы пар, уже полученные в этом запуске (0 - курс недоступен)
+	for _, alert := range alerts {
+		pair := alert.FromCurrency + "/" + alert.ToCurrency
+		rate, ok := rates[pair]
+		if !ok {
+			if rate, err = s.rates.GetRate(ctx, alert.FromCurrency, alert.ToCurrency); err != nil {
+				log.Printf("Ошибка получения курса %s для уведомлений: %v", pair, err)
+			}
+			rates[pair] = rate
+		}
+		if rate == 0 || !alert.Triggered(rate) {
+			continue
+		}
+
+		if err := notify(ctx, alert, rate); err != nil {
+			log.Printf("Ошибка отправки уведомления о курсе %d: %v", alert.ID, err)
+			continue
+		}
+		if _, err := s.alerts.DeleteAlert(ctx, 0, alert.ID); err != nil {
+			log.Printf("Ошибка удаления отправленного уведомления о курсе %d: %v", alert.ID, err)
+		}
+	}
+	return nil
+}
@@ -7,18 +7,24 @@ import (
 	"gw-currency-wallet/internal/middleware"
 	"gw-currency-wallet/internal/models"
 	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tenant"
 	"time"
 )
 
+// ErrPasswordTooLong возвращается при регистрации с паролем длиннее, чем допускает bcrypt
+var ErrPasswordTooLong = errors.New("пароль не должен быть длиннее 72 байт")
+
 // AuthService предоставляет функционал для регистрации и аутентификации пользователей.
 // Содержит зависимости:
 // - repo: для операций с хранилищем пользователей
 // - jwtSecret: секретный ключ для подписи JWT
 // - tokenExpiration: срок действия токена
+// - adminUsernames: пользователи арендатора по умолчанию, получающие роль администратора при входе
 type AuthService struct {
 	repo            storage.UserRepository
 	jwtSecret       string
 	tokenExpiration time.Duration
+	adminUsernames  map[string]bool
 }
 
 // NewAuthService - конструктор для создания экземпляра AuthService.
@@ -26,19 +32,31 @@ type AuthService struct {
 // - repo: реализация интерфейса работы с хранилищем пользователей
 // - jwtSecret: секретный ключ для генерации/верификации токенов
 // - tokenExpiration: длительность жизни токена (например 24h)
+// - adminUsernames: имена пользователей, которым назначается роль администратора
 //
 // Возвращает готовый к использованию экземпляр AuthService.
-func NewAuthService(repo storage.UserRepository, jwtSecret string, tokenExpiration time.Duration) *AuthService {
+func NewAuthService(
+	repo storage.UserRepository,
+	jwtSecret string,
+	tokenExpiration time.Duration,
+	adminUsernames []string,
+) *AuthService {
+	admins := make(map[string]bool, len(adminUsernames))
+	for _, name := range adminUsernames {
+		admins[name] = true
+	}
 	return &AuthService{
 		repo:            repo,
 		jwtSecret:       jwtSecret,
 		tokenExpiration: tokenExpiration,
+		adminUsernames:  admins,
 	}
 }
 
 // Register регистрирует нового пользователя в системе.
+// Пользователь создается у арендатора из контекста запроса (tenant.IDFromContext).
 // Последовательность операций:
-// 1. Проверка уникальности имени пользователя
+// 1. Проверка уникальности имени пользователя у арендатора
 // 2. Хеширование пароля
 // 3. Создание записи пользователя
 //
@@ -50,26 +68,32 @@ func NewAuthService(repo storage.UserRepository, jwtSecret string, tokenExpirati
 // - *models.User: данные зарегистрированного пользователя
 // - error: ошибка при возникновении проблем
 func (s *AuthService) Register(ctx context.Context, req models.CreateUserRequest) (*models.User, error) {
+	tenantID := tenant.IDFromContext(ctx)
+
 	// Проверка существующего пользователя
-	existing, _ := s.repo.GetUserByUsername(ctx, req.Username)
+	existing, _ := s.repo.GetUserByUsername(ctx, tenantID, req.Username)
 	if existing != nil {
-		return nil, errors.New("пользователь с таким именем уже существует") // Сообщение об ошибке
+		return nil, storage.ErrUsernameTaken // Имя занято (параллельная регистрация отклоняется хранилищем)
 	}
 
 	// Генерация хеша пароля с стандартной стоимостью вычисления
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return nil, ErrPasswordTooLong
+	}
 	if err != nil {
 		return nil, err // Возвращаем ошибку хеширования
 	}
 
 	// Создание объекта пользователя
 	user := &models.User{
+		TenantID:     tenantID,
 		Username:     req.Username,
 		Email:        req.Email,
 		PasswordHash: string(hashedPassword), // Сохранение хеша вместо пароля
 	}
 
-	// Сохранение пользователя в хранилище
+	// Сохранение пользователя вместе с кошельком (в одной транзакции)
 	err = s.repo.CreateUser(ctx, user)
 	if err != nil {
 		return nil, err // Ошибка сохранения
@@ -78,11 +102,11 @@ func (s *AuthService) Register(ctx context.Context, req models.CreateUserRequest
 	return user, nil
 }
 
-// Login выполняет аутентификацию пользователя и генерирует JWT токен.
+// Authenticate проверяет учетные данные пользователя.
 // Алгоритм работы:
-// 1. Поиск пользователя по username
+// 1. Поиск пользователя по username у арендатора из контекста запроса
 // 2. Сравнение хеша пароля
-// 3. Генерация токена при успешной проверке
+// 3. Назначение роли администратора пользователям из конфигурации
 //
 // Параметры:
 // - ctx: контекст выполнения
@@ -90,24 +114,48 @@ func (s *AuthService) Register(ctx context.Context, req models.CreateUserRequest
 // - password: пароль пользователя
 //
 // Возвращает:
-// - string: JWT токен для доступа
+// - *models.User: пользователь с актуальной ролью
 // - error: ошибка аутентификации
-func (s *AuthService) Login(ctx context.Context, username, password string) (string, error) {
+func (s *AuthService) Authenticate(ctx context.Context, username, password string) (*models.User, error) {
 	// Получение пользователя из хранилища
-	user, err := s.repo.GetUserByUsername(ctx, username)
+	user, err := s.repo.GetUserByUsername(ctx, tenant.IDFromContext(ctx), username)
 	if err != nil || user == nil {
 		// Обобщенное сообщение для безопасности (не раскрываем детали)
-		return "", errors.New("неверные учетные данные")
+		return nil, errors.New("неверные учетные данные")
 	}
 
 	// Сравнение хеша пароля с предоставленным паролем
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", errors.New("неверные учетные данные")
+		return nil, errors.New("неверные учетные данные")
+	}
+
+	// Администраторы задаются в конфигурации (ADMIN_USERNAMES): роль сохраняется в БД при первом входе
+	// Администраторы управляют всей установкой, поэтому назначаются только у арендатора по умолчанию:
+	// пользователь другого бренда с тем же именем роль не получает
+	if s.adminUsernames[user.Username] && user.TenantID == tenant.DefaultID && user.Role != models.RoleAdmin {
+		if err := s.repo.SetRole(ctx, user.ID, models.RoleAdmin); err != nil {
+			return nil, errors.New("ошибка назначения роли")
+		}
+		user.Role = models.RoleAdmin
 	}
 
+	return user, nil
+}
+
+// IssueToken генерирует JWT токен с ролью пользователя.
+//
+// Параметры:
+// - user: пользователь, прошедший аутентификацию
+//
+// Возвращает:
+// - string: JWT токен для доступа
+// - error: ошибка генерации токена
+func (s *AuthService) IssueToken(user *models.User) (string, error) {
 	// Генерация JWT токена с указанными параметрами
 	token, err := middleware.GenerateJWTToken(
 		user.ID,           // ID пользователя в claims
+		user.Role,         // Роль пользователя в claims
+		user.TenantID,     // Арендатор пользователя в claims
 		s.jwtSecret,       // Секретный ключ
 		s.tokenExpiration, // Время жизни токена
 	)
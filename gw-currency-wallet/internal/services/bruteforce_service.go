@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Ошибки снятия блокировки адреса
+var (
+	ErrBanNotFound = errors.New("адрес не заблокирован")
+	ErrInvalidIP   = errors.New("некорректный IP адрес")
+)
+
+// BruteForceOptions содержит параметры защиты от подбора паролей
+type BruteForceOptions struct {
+	Limit       int           // Максимум попыток входа и регистрации с адреса за окно
+	Window      time.Duration // Длина скользящего окна подсчета попыток
+	BanDuration time.Duration // Длительность блокировки адреса после превышения лимита
+}
+
+// BruteForceService ограничивает попытки входа и регистрации с одного IP адреса
+// Попытки считаются в скользящем окне (счетчики текущего и предыдущего окна в кэше);
+// при превышении лимита адрес временно блокируется
+type BruteForceService struct {
+	cache storage.Cache     // Счетчики попыток (Redis или in-memory)
+	bans  storage.BanList   // Список заблокированных адресов
+	opts  BruteForceOptions // Параметры защиты
+}
+
+// NewBruteForceService создает сервис защиты от подбора паролей
+// Параметры:
+//   - cache: кэш для счетчиков попыток
+//   - bans: список блокировок адресов
+//   - opts: лимит, окно и длительность блокировки
+//
+// Возвращает:
+//   - *BruteForceService: инициализированный сервис
+func NewBruteForceService(cache storage.Cache, bans storage.BanList, opts BruteForceOptions) *BruteForceService {
+	return &BruteForceService{cache: cache, bans: bans, opts: opts}
+}
+
+// Enabled сообщает, ограничены ли попытки (лимит 0 выключает защиту)
+func (s *BruteForceService) Enabled() bool {
+	return s.opts.Limit > 0
+}
+
+// Attempt учитывает попытку с адреса и проверяет его блокировку
+// Параметры:
+//   - ctx: контекст выполнения
+//   - ip: адрес клиента
+//
+// Возвращает:
+//   - time.Time: время снятия блокировки (нулевое значение - попытка разрешена)
+//   - error: ошибка обращения к кэшу или списку блокировок
+func (s *BruteForceService) Attempt(ctx context.Context, ip string) (time.Time, error) {
+	ban, err := s.bans.Get(ctx, ip)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ошибка проверки блокировки адреса: %w", err)
+	}
+	if ban != nil {
+		return ban.ExpiresAt, nil
+	}
+
+	attempts, err := s.countAttempt(ctx, ip)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ошибка подсчета попыток: %w", err)
+	}
+	if attempts <= int64(s.opts.Limit) {
+		return time.Time{}, nil
+	}
+
+	now := time.Now()
+	ban = &models.IPBan{
+		IP:        ip,
+		Reason:    fmt.Sprintf("более %d попыток входа и регистрации за %s", s.opts.Limit, s.opts.Window),
+		Attempts:  attempts,
+		BannedAt:  now,
+		ExpiresAt: now.Add(s.opts.BanDuration),
+	}
+	if err := s.bans.Ban(ctx, *ban); err != nil {
+		return time.Time{}, fmt.Errorf("ошибка блокировки адреса: %w", err)
+	}
+	log.Printf("Адрес %s заблокирован до %s: %s", ip, ban.ExpiresAt.Format(time.RFC3339), ban.Reason)
+	return ban.ExpiresAt, nil
+}
+
+// countAttempt увеличивает счетчик текущего окна и оценивает число попыток в скользящем окне:
+// попытки предыдущего окна учитываются с весом оставшейся доли этого окна
+func (s *BruteForceService) countAttempt(ctx context.Context, ip string) (int64, error) {
+	now := time.Now()
+	window := now.UnixNano() / int64(s.opts.Window)
+	elapsed := float64(now.UnixNano()%int64(s.opts.Window)) / float64(s.opts.Window)
+
+	current, err := s.cache.Incr(ctx, attemptsKey(ip, window), 2*s.opts.Window)
+	if err != nil {
+		return 0, err
+	}
+
+	var previous int64
+	value, err := s.cache.Get(ctx, attemptsKey(ip, window-1))
+	switch {
+	case err == nil:
+		previous, _ = strconv.ParseInt(string(value), 10, 64)
+	case !errors.Is(err, storage.ErrCacheMiss):
+		return 0, err
+	}
+
+	return current + int64(float64(previous)*(1-elapsed)), nil
+}
+
+// attemptsKey - ключ счетчика попыток адреса в окне
+func attemptsKey(ip string, window int64) string {
+	return "bruteforce:attempts:" + ip + ":" + strconv.FormatInt(window, 10)
+}
+
+// ListBans возвращает действующие блокировки адресов, начиная с самых поздних
+func (s *BruteForceService) ListBans(ctx context.Context) ([]models.IPBan, error) {
+	bans, err := s.bans.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения блокировок: %w", err)
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].BannedAt.After(bans[j].BannedAt) })
+	return bans, nil
+}
+
+// Unban снимает блокировку адреса досрочно
+// Возвращает ErrInvalidIP для некорректного адреса и ErrBanNotFound, если адрес не заблокирован
+func (s *BruteForceService) Unban(ctx context.Context, adminID int, ip string) error {
+	if net.ParseIP(ip) == nil {
+		return ErrInvalidIP
+	}
+	removed, err := s.bans.Unban(ctx, ip)
+	if err != nil {
+		return fmt.Errorf("ошибка снятия блокировки: %w", err)
+	}
+	if !removed {
+		return ErrBanNotFound
+	}
+	log.Printf("Блокировка адреса %s снята администратором %d", ip, adminID)
+	return nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidCacheScope - неизвестная группа записей кэша
+var ErrInvalidCacheScope = errors.New("некорректная группа записей кэша")
+
+// cacheScopeKeys - ключи и префиксы ключей кэша по группам записей
+// Ключи принадлежат ExchangeService, кэшу балансов postgres, графикам бота Telegram и QuotaService;
+// котировки обмена (exchange:quote:) не сбрасываются - это обещания клиентам, а не кэш
+var cacheScopeKeys = map[string][]string{
+	models.CacheScopeRates:    {ratesCacheKey, currenciesCacheKey, "exchange:chart:", "telegram:chart:"},
+	models.CacheScopeBalances: {"wallet:balance:"},
+	models.CacheScopeLimits:   {"quota:plan:", "quota:requests:"},
+}
+
+// CacheService сбрасывает группы записей кэша по запросу администратора
+// (после ручной установки курса или исправления данных в БД)
+type CacheService struct {
+	cache     storage.Cache          // Кэш приложения
+	broadcast storage.CacheBroadcast // Рассылка сброса экземплярам (nil - без Redis)
+}
+
+// NewCacheService создает сервис сброса кэша
+// Параметры:
+//   - cache: кэш приложения
+//   - broadcast: рассылка сброса остальным экземплярам (nil - экземпляр один, in-memory кэш)
+//
+// Возвращает:
+//   - *CacheService: инициализированный сервис
+func NewCacheService(cache storage.Cache, broadcast storage.CacheBroadcast) *CacheService {
+	return &CacheService{cache: cache, broadcast: broadcast}
+}
+
+// Flush удаляет записи групп и рассылает сброс остальным экземплярам
+// Ошибка рассылки не отменяет сброс: записи в Redis общие для всех экземпляров
+// Параметры:
+//   - ctx: контекст выполнения
+//   - adminID: администратор (для журнала)
+//   - scopes: группы записей (models.CacheScope*)
+//
+// Возвращает:
+//   - *models.CacheFlushResult: количество удаленных записей по группам и признак рассылки
+//   - error: ErrInvalidCacheScope или ошибка кэша
+func (s *CacheService) Flush(ctx context.Context, adminID int, scopes []string) (*models.CacheFlushResult, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: укажите rates, balances или limits", ErrInvalidCacheScope)
+	}
+	scopes = slices.Compact(slices.Sorted(slices.Values(scopes)))
+	for _, scope := range scopes {
+		if _, ok := cacheScopeKeys[scope]; !ok {
+			return nil, fmt.Errorf("%w: %s (допустимо: rates, balances, limits)", ErrInvalidCacheScope, scope)
+		}
+	}
+
+	result := &models.CacheFlushResult{Deleted: make(map[string]int64, len(scopes))}
+	for _, scope := range scopes {
+		deleted, err := s.flush(ctx, scope)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сброса кэша %s: %w", scope, err)
+		}
+		result.Deleted[scope] = deleted
+	}
+	log.Printf("Кэш %s сброшен администратором %d: %v", strings.Join(scopes, ", "), adminID, result.Deleted)
+
+	if s.broadcast != nil {
+		if err := s.broadcast.Publish(ctx, scopes); err != nil {
+			log.Printf("Ошибка рассылки сброса кэша %s: %v", strings.Join(scopes, ", "), err)
+		} else {
+			result.Broadcast = true
+		}
+	}
+	return result, nil
+}
+
+// Listen применяет сбросы, разосланные другими экземплярами, до отмены контекста
+// Повторное удаление записей после рассылки убирает значения, записанные в кэш запросами экземпляра,
+// которые прочитали данные до сброса и завершились после него
+func (s *CacheService) Listen(ctx context.Context) {
+	if s.broadcast == nil {
+		return
+	}
+	s.broadcast.Subscribe(ctx, func(scopes []string) {
+		for _, scope := range scopes {
+			if _, ok := cacheScopeKeys[scope]; !ok {
+				continue
+			}
+			if _, err := s.flush(ctx, scope); err != nil {
+				log.Printf("Ошибка сброса кэша %s по рассылке: %v", scope, err)
+			}
+		}
+	})
+}
+
+// flush удаляет записи группы (ключ курсов или справочника валют удаляется как префикс из самого себя)
+func (s *CacheService) flush(ctx context.Context, scope string) (int64, error) {
+	var deleted int64
+	for _, prefix := range cacheScopeKeys[scope] {
+		n, err := s.cache.DeletePrefix(ctx, prefix)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/captcha"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"strconv"
+	"time"
+)
+
+// CaptchaOptions содержит параметры проверки CAPTCHA
+type CaptchaOptions struct {
+	LoginAfterFailures int           // Число неудачных входов с адреса, после которого вход требует CAPTCHA (0 - всегда)
+	FailureWindow      time.Duration // Время хранения счетчика неудачных входов
+}
+
+// CaptchaService проверяет CAPTCHA при регистрации и при входе после неудачных попыток
+// Неудачные входы считаются по IP адресу клиента в кэше
+type CaptchaService struct {
+	verifier *captcha.Verifier // Проверка ответов у провайдера (nil - CAPTCHA выключена)
+	cache    storage.Cache     // Счетчики неудачных входов
+	opts     CaptchaOptions    // Параметры проверки
+}
+
+// NewCaptchaService создает сервис проверки CAPTCHA
+// Параметры:
+//   - verifier: проверка ответов у провайдера (nil - проверка выключена)
+//   - cache: кэш для счетчиков неудачных входов
+//   - opts: порог неудачных входов и время хранения счетчика
+//
+// Возвращает:
+//   - *CaptchaService: инициализированный сервис
+func NewCaptchaService(verifier *captcha.Verifier, cache storage.Cache, opts CaptchaOptions) *CaptchaService {
+	return &CaptchaService{verifier: verifier, cache: cache, opts: opts}
+}
+
+// Enabled сообщает, настроена ли проверка CAPTCHA
+func (s *CaptchaService) Enabled() bool {
+	return s.verifier != nil
+}
+
+// RequiredForLogin сообщает, требуется ли CAPTCHA для входа с адреса
+// Если счетчик недоступен, CAPTCHA не требуется (вход не должен зависеть от кэша)
+func (s *CaptchaService) RequiredForLogin(ctx context.Context, ip string) bool {
+	if s.opts.LoginAfterFailures <= 0 {
+		return true
+	}
+	value, err := s.cache.Get(ctx, loginFailuresKey(ip))
+	if err != nil {
+		if !errors.Is(err, storage.ErrCacheMiss) {
+			log.Printf("Ошибка чтения счетчика неудачных входов: %v", err)
+		}
+		return false
+	}
+	failures, _ := strconv.Atoi(string(value))
+	return failures >= s.opts.LoginAfterFailures
+}
+
+// Verify проверяет ответ пользователя на CAPTCHA
+// Возвращает:
+//   - bool: true если ответ передан и верный
+//   - error: провайдер CAPTCHA недоступен
+func (s *CaptchaService) Verify(ctx context.Context, token, ip string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	return s.verifier.Verify(ctx, token, ip)
+}
+
+// LoginFailed учитывает неудачный вход с адреса
+func (s *CaptchaService) LoginFailed(ctx context.Context, ip string) {
+	if _, err := s.cache.Incr(ctx, loginFailuresKey(ip), s.opts.FailureWindow); err != nil {
+		log.Printf("Ошибка учета неудачного входа: %v", err)
+	}
+}
+
+// LoginSucceeded сбрасывает счетчик неудачных входов адреса
+func (s *CaptchaService) LoginSucceeded(ctx context.Context, ip string) {
+	if err := s.cache.Delete(ctx, loginFailuresKey(ip)); err != nil {
+		log.Printf("Ошибка сброса счетчика неудачных входов: %v", err)
+	}
+}
+
+// loginFailuresKey - ключ счетчика неудачных входов адреса
+func loginFailuresKey(ip string) string {
+	return "captcha:login-failures:" + ip
+}
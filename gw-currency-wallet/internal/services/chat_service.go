@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// ErrInvalidDigestHour возвращается для часа сводки вне диапазона 0-23
+var ErrInvalidDigestHour = errors.New("час сводки должен быть от 0 до 23 (UTC)")
+
+// ChatService управляет чатами Telegram бота (личными и группами) и их настройками
+type ChatService struct {
+	chats storage.ChatRepository // Хранилище чатов
+}
+
+// NewChatService создает сервис чатов Telegram бота
+func NewChatService(chats storage.ChatRepository) *ChatService {
+	return &ChatService{chats: chats}
+}
+
+// Register сохраняет чат, в который добавлен бот (повторный вызов обновляет тип и название)
+func (s *ChatService) Register(ctx context.Context, chat *models.TelegramChat) error {
+	return s.chats.SaveChat(ctx, chat)
+}
+
+// Get возвращает чат с настройками (nil - чат не сохранен, настройки по умолчанию)
+func (s *ChatService) Get(ctx context.Context, chatID int64) (*models.TelegramChat, error) {
+	return s.chats.GetChat(ctx, chatID)
+}
+
+// SetDigest включает ежедневную сводку курсов чата или выключает ее
+// Параметры:
+//   - ctx: контекст выполнения
+//   - chat: чат (сохраняется, если еще не сохранен)
+//   - hour: час отправки по UTC (nil - выключить сводку)
+//
+// Возвращает:
+//   - error: ErrInvalidDigestHour или ошибка хранилища
+func (s *ChatService) SetDigest(ctx context.Context, chat *models.TelegramChat, hour *int) error {
+	if hour != nil && (*hour < 0 || *hour > 23) {
+		return ErrInvalidDigestHour
+	}
+	if err := s.chats.SaveChat(ctx, chat); err != nil {
+		return err
+	}
+	_, err := s.chats.SetDigestHour(ctx, chat.ChatID, hour)
+	return err
+}
+
+// Remove удаляет чат, из которого удален бот, вместе с его настройками и уведомлениями о курсах
+func (s *ChatService) Remove(ctx context.Context, chatID int64) error {
+	return s.chats.DeleteChat(ctx, chatID)
+}
+
+// DueDigests возвращает чаты, которым пора отправить ежедневную сводку, и отмечает сводки отправленными
+func (s *ChatService) DueDigests(ctx context.Context, now time.Time) ([]int64, error) {
+	return s.chats.ClaimDigests(ctx, now)
+}
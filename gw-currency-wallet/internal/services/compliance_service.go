@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"io"
+	"strconv"
+	"time"
+)
+
+// amlReportHeader - заголовок CSV отчета о крупных операциях
+var amlReportHeader = []string{
+	"report_id",
+	"operation_time_utc",
+	"operation_id",
+	"client_id",
+	"client_username",
+	"client_email",
+	"operation_type",
+	"currency",
+	"amount",
+	"threshold",
+	"counterparty_id",
+}
+
+// ComplianceService формирует отчетность о крупных операциях
+type ComplianceService struct {
+	repo storage.ComplianceRepository // Репозиторий отчета AML
+}
+
+// NewComplianceService создает сервис отчетности
+// Параметры:
+//   - repo: репозиторий отчета о крупных операциях
+//
+// Возвращает:
+//   - *ComplianceService: инициализированный сервис
+func NewComplianceService(repo storage.ComplianceRepository) *ComplianceService {
+	return &ComplianceService{repo: repo}
+}
+
+// ExportAMLReport записывает отчет о крупных операциях за период в формате CSV
+// Время выводится в UTC (RFC3339), суммы - с двумя знаками после точки
+// Параметры:
+//   - ctx: контекст выполнения
+//   - from: начало периода (включительно)
+//   - to: конец периода (не включительно)
+//   - w: получатель CSV
+//
+// Возвращает:
+//   - error: ошибка периода, чтения отчета или записи
+func (s *ComplianceService) ExportAMLReport(ctx context.Context, from, to time.Time, w io.Writer) error {
+	from, to, err := s.ReportPeriod(from, to)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(amlReportHeader); err != nil {
+		return err
+	}
+
+	err = s.repo.ExportAMLReports(ctx, from, to, func(report models.AMLReport) error {
+		counterparty := ""
+		if report.CounterpartyID != nil {
+			counterparty = strconv.Itoa(*report.CounterpartyID)
+		}
+		return writer.Write([]string{
+			strconv.Itoa(report.ID),
+			report.CreatedAt.UTC().Format(time.RFC3339),
+			report.OperationID,
+			strconv.Itoa(report.UserID),
+			report.Username,
+			report.Email,
+			report.Type,
+			report.Currency,
+			strconv.FormatFloat(report.Amount, 'f', 2, 64),
+			strconv.FormatFloat(report.Threshold, 'f', 2, 64),
+			counterparty,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReportPeriod подставляет границы периода по умолчанию и проверяет их
+// По умолчанию отчет строится с начала прошлого месяца (типичный отчетный период) до текущего момента
+// Параметры:
+//   - from: начало периода (нулевое значение - начало прошлого месяца)
+//   - to: конец периода (нулевое значение - текущий момент)
+//
+// Возвращает:
+//   - time.Time, time.Time: итоговые границы периода
+//   - error: ошибка, если период пустой или длиннее 366 дней
+func (s *ComplianceService) ReportPeriod(from, to time.Time) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("начало периода должно быть раньше конца")
+	}
+	if to.Sub(from) > maxHistoryPeriod {
+		return time.Time{}, time.Time{}, fmt.Errorf("период отчета не может превышать %d дней", int(maxHistoryPeriod.Hours()/24))
+	}
+	return from, to, nil
+}
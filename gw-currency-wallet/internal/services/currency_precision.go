@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"math"
+)
+
+// defaultCurrencyDecimals - количество знаков после запятой, если справочник валют недоступен
+const defaultCurrencyDecimals = 2
+
+// CurrencyPrecision предоставляет количество знаков после запятой в суммах валют
+// (справочник валют сервиса обмена)
+type CurrencyPrecision interface {
+	// Decimals возвращает количество знаков после запятой в суммах валюты
+	Decimals(ctx context.Context, currency string) int
+}
+
+// currencyDecimals возвращает количество знаков после запятой в суммах валюты по справочнику источника курсов
+// Источник без справочника (не реализует CurrencyPrecision) - defaultCurrencyDecimals
+func currencyDecimals(ctx context.Context, rates any, currency string) int {
+	if precision, ok := rates.(CurrencyPrecision); ok && precision != nil {
+		return precision.Decimals(ctx, currency)
+	}
+	return defaultCurrencyDecimals
+}
+
+// roundAmount округляет сумму до decimals знаков после запятой
+func roundAmount(amount float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Round(amount*scale) / scale
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/notify"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tenant"
+	"log"
+	"time"
+)
+
+// Ошибки планов регулярных обменов
+var (
+	ErrInvalidDCAPlan = errors.New("некорректный план регулярных обменов")
+	ErrDCAPlanLimit   = errors.New("слишком много активных планов регулярных обменов")
+)
+
+const (
+	maxActiveDCAPlans = 10               // Активных планов на пользователя
+	maxDCALegs        = 366              // Обменов в плане
+	dcaBatchSize      = 100              // Планов за один запуск
+	dcaClaimLease     = 10 * time.Minute // Захват плана на время обмена
+)
+
+// dcaPeriodDays - интервал между обменами плана в днях по периодичности
+var dcaPeriodDays = map[string]int{
+	models.DCAPeriodDaily:  1,
+	models.DCAPeriodWeekly: 7,
+}
+
+// DCANotifyFunc уведомляет пользователя об обмене по плану, который пропущен или не выполнен
+type DCANotifyFunc func(ctx context.Context, plan models.DCAPlan, leg models.DCALeg) error
+
+// DCAService выполняет планы регулярных обменов фиксированной суммы (усреднение курса)
+// Каждый обмен плана выполняется через WalletService по курсу и комиссии на момент обмена; при недостатке
+// средств обмен пропускается и засчитывается в план. Запуски, пропущенные из-за остановки сервиса,
+// не догоняются: выполняется один обмен, следующий - по расписанию
+type DCAService struct {
+	repo        storage.DCARepository // Планы и их обмены
+	wallet      *WalletService        // Выполнение обменов
+	preferences *PreferencesService   // Часовой пояс пользователя по умолчанию
+	notify      DCANotifyFunc         // Уведомление о пропуске или неисполнении обмена (nil - только журнал)
+}
+
+// NewDCAService создает сервис планов регулярных обменов
+// Параметры:
+//   - repo: хранилище планов
+//   - wallet: сервис кошелька, выполняющий обмены
+//   - preferences: настройки пользователей (часовой пояс плана по умолчанию)
+//   - notify: уведомление о пропущенном или невыполненном обмене (nil - без уведомлений)
+//
+// Возвращает:
+//   - *DCAService: инициализированный сервис
+func NewDCAService(
+	repo storage.DCARepository,
+	wallet *WalletService,
+	preferences *PreferencesService,
+	notify DCANotifyFunc,
+) *DCAService {
+	return &DCAService{repo: repo, wallet: wallet, preferences: preferences, notify: notify}
+}
+
+// Create создает план; первый обмен - в ближайшее время плана
+// Параметры:
+//   - ctx: контекст выполнения (арендатор пользователя)
+//   - userID: пользователь
+//   - request: параметры плана
+//
+// Возвращает:
+//   - *models.DCAPlan: созданный план
+//   - error: ErrInvalidDCAPlan, ErrDCAPlanLimit или ошибка хранилища
+func (s *DCAService) Create(ctx context.Context, userID int, request models.CreateDCAPlanRequest) (*models.DCAPlan, error) {
+	if !s.wallet.currencyAllowed(ctx, request.FromCurrency) || !s.wallet.currencyAllowed(ctx, request.ToCurrency) {
+		return nil, fmt.Errorf("%w: неподдерживаемая валюта", ErrInvalidDCAPlan)
+	}
+	if request.FromCurrency == request.ToCurrency {
+		return nil, fmt.Errorf("%w: валюты обмена совпадают", ErrInvalidDCAPlan)
+	}
+	if request.Amount <= 0 {
+		return nil, fmt.Errorf("%w: сумма должна быть положительной", ErrInvalidDCAPlan)
+	}
+	if request.Legs <= 0 || request.Legs > maxDCALegs {
+		return nil, fmt.Errorf("%w: количество обменов должно быть от 1 до %d", ErrInvalidDCAPlan, maxDCALegs)
+	}
+	if request.Period == "" {
+		request.Period = models.DCAPeriodDaily
+	}
+	if _, ok := dcaPeriodDays[request.Period]; !ok {
+		return nil, fmt.Errorf("%w: периодичность должна быть daily или weekly", ErrInvalidDCAPlan)
+	}
+	if _, err := time.Parse("15:04", request.Time); err != nil {
+		return nil, fmt.Errorf("%w: время обмена должно быть в формате ЧЧ:ММ", ErrInvalidDCAPlan)
+	}
+	if request.Timezone == "" {
+		prefs, err := s.preferences.Get(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		request.Timezone = prefs.Timezone
+	}
+	if _, err := time.LoadLocation(request.Timezone); err != nil {
+		return nil, fmt.Errorf("%w: неизвестный часовой пояс %s", ErrInvalidDCAPlan, request.Timezone)
+	}
+
+	active, err := s.repo.CountActiveDCAPlans(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active >= maxActiveDCAPlans {
+		return nil, fmt.Errorf("%w: не больше %d", ErrDCAPlanLimit, maxActiveDCAPlans)
+	}
+
+	plan := &models.DCAPlan{
+		UserID:       userID,
+		TenantID:     tenant.IDFromContext(ctx),
+		FromCurrency: request.FromCurrency,
+		ToCurrency:   request.ToCurrency,
+		Amount:       request.Amount,
+		Period:       request.Period,
+		TimeOfDay:    request.Time,
+		Timezone:     request.Timezone,
+		Legs:         request.Legs,
+	}
+	first := firstDCARun(plan, time.Now())
+	plan.NextRunAt = &first
+	if err := s.repo.CreateDCAPlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	log.Printf("План регулярных обменов %d пользователя %d: %.2f %s в %s, %s в %s (%s), обменов: %d",
+		plan.ID, userID, plan.Amount, plan.FromCurrency, plan.ToCurrency, plan.Period, plan.TimeOfDay, plan.Timezone, plan.Legs)
+	return plan, nil
+}
+
+// List возвращает планы пользователя
+func (s *DCAService) List(ctx context.Context, userID int) ([]models.DCAPlan, error) {
+	return s.repo.ListDCAPlans(ctx, userID)
+}
+
+// Progress возвращает ход выполнения плана: обмены и итоги по выполненным обменам
+// Возвращает:
+//   - *models.DCAPlanProgress: ход выполнения
+//   - error: storage.ErrDCAPlanNotFound, если плана нет
+func (s *DCAService) Progress(ctx context.Context, userID int, id int64) (*models.DCAPlanProgress, error) {
+	plan, err := s.repo.GetDCAPlan(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	legs, err := s.repo.ListDCALegs(ctx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &models.DCAPlanProgress{Plan: *plan, Legs: legs}
+	if plan.Status == models.DCAPlanActive {
+		progress.Remaining = plan.Legs - plan.Executed - plan.Skipped - plan.Failed
+	}
+	for _, leg := range legs {
+		if leg.Status != models.DCALegExecuted || leg.ExchangedAmount == nil {
+			continue
+		}
+		progress.TotalSpent += plan.Amount
+		progress.TotalReceived += *leg.ExchangedAmount
+	}
+	if progress.TotalSpent > 0 {
+		progress.AverageRate = progress.TotalReceived / progress.TotalSpent
+	}
+	return progress, nil
+}
+
+// Cancel отменяет активный план; выполняющийся обмен завершается
+// Возвращает:
+//   - *models.DCAPlan: отмененный план
+//   - error: storage.ErrDCAPlanNotFound, если плана нет или он уже завершен
+func (s *DCAService) Cancel(ctx context.Context, userID int, id int64) (*models.DCAPlan, error) {
+	plan, err := s.repo.CancelDCAPlan(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("План регулярных обменов %d отменен пользователем %d", id, userID)
+	return plan, nil
+}
+
+// RunDue выполняет обмены планов, время которых наступило
+// Параметры:
+//   - ctx: контекст выполнения
+//
+// Возвращает:
+//   - error: ошибка хранилища планов
+func (s *DCAService) RunDue(ctx context.Context) error {
+	plans, err := s.repo.ClaimDueDCAPlans(ctx, dcaClaimLease, dcaBatchSize)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range plans {
+		if err := s.runLeg(ctx, &plans[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runLeg выполняет очередной обмен захваченного плана и переносит план на следующий обмен
+// Обмен, начатый до сбоя экземпляра, не повторяется: он отмечается невыполненным
+func (s *DCAService) runLeg(ctx context.Context, plan *models.DCAPlan) error {
+	leg := &models.DCALeg{
+		PlanID:      plan.ID,
+		Number:      plan.Executed + plan.Skipped + plan.Failed + 1,
+		ScheduledAt: *plan.NextRunAt,
+		Status:      models.DCALegExecuting,
+	}
+	started, err := s.repo.StartDCALeg(ctx, leg)
+	if err != nil {
+		return err
+	}
+	if started {
+		s.exchange(ctx, plan, leg)
+	} else {
+		leg.Status, leg.Error = models.DCALegFailed, "обмен прерван сбоем, проверьте историю операций"
+	}
+
+	var next *time.Time
+	if leg.Number < plan.Legs {
+		at := nextDCARun(plan, leg.ScheduledAt, time.Now())
+		next = &at
+	}
+	if err := s.repo.FinishDCALeg(ctx, leg, next); err != nil {
+		return err
+	}
+
+	if leg.Status != models.DCALegExecuted && s.notify != nil {
+		if err := s.notify(ctx, *plan, *leg); err != nil {
+			log.Printf("Ошибка уведомления пользователя %d об обмене %d плана %d: %v", plan.UserID, leg.Number, plan.ID, err)
+		}
+	}
+	return nil
+}
+
+// exchange выполняет обмен плана и записывает результат в leg
+// Обмен при недостатке средств пропускается
+func (s *DCAService) exchange(ctx context.Context, plan *models.DCAPlan, leg *models.DCALeg) {
+	// Валюты и комиссия определяются арендатором пользователя
+	ctx = tenant.WithID(ctx, plan.TenantID)
+	response, err := s.wallet.Exchange(ctx, plan.UserID, plan.FromCurrency, plan.ToCurrency, plan.Amount)
+	switch {
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		leg.Status, leg.Error = models.DCALegSkipped, err.Error()
+		log.Printf("Обмен %d плана %d пропущен: %v", leg.Number, plan.ID, err)
+	case err != nil:
+		leg.Status, leg.Error = models.DCALegFailed, err.Error()
+		log.Printf("Обмен %d плана %d не выполнен: %v", leg.Number, plan.ID, err)
+	default:
+		leg.Status, leg.Rate, leg.ExchangedAmount = models.DCALegExecuted, &response.Rate, &response.ExchangedAmount
+	}
+}
+
+// firstDCARun возвращает время первого обмена плана: ближайшее время плана после now
+func firstDCARun(plan *models.DCAPlan, now time.Time) time.Time {
+	loc, err := time.LoadLocation(plan.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	clock, _ := time.Parse("15:04", plan.TimeOfDay)
+	local := now.In(loc)
+	first := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if !first.After(now) {
+		first = time.Date(local.Year(), local.Month(), local.Day()+1, clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+	return first
+}
+
+// nextDCARun возвращает время следующего обмена плана после обмена по расписанию scheduled:
+// первое время по расписанию позже now (пропущенные запуски не догоняются)
+// Время суток сохраняется при переходе на летнее время и обратно
+func nextDCARun(plan *models.DCAPlan, scheduled, now time.Time) time.Time {
+	loc, err := time.LoadLocation(plan.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	clock, _ := time.Parse("15:04", plan.TimeOfDay)
+	step := dcaPeriodDays[plan.Period]
+	local := scheduled.In(loc)
+	for days := step; ; days += step {
+		next := time.Date(local.Year(), local.Month(), local.Day()+days, clock.Hour(), clock.Minute(), 0, 0, loc)
+		if next.After(now) {
+			return next
+		}
+	}
+}
+
+// EmailDCANotifier возвращает уведомление о пропущенном или невыполненном обмене плана по email
+// Параметры:
+//   - users: пользователи (адрес уведомления)
+//   - notifier: отправка писем
+//
+// Возвращает:
+//   - DCANotifyFunc: уведомление по email
+func EmailDCANotifier(users storage.UserRepository, notifier notify.Notifier) DCANotifyFunc {
+	return func(ctx context.Context, plan models.DCAPlan, leg models.DCALeg) error {
+		user, err := users.GetUserByID(ctx, plan.UserID)
+		if err != nil {
+			return err
+		}
+		title, text := dcaLegMessage(plan, leg)
+		return notifier.Notify(ctx, notify.Message{To: user.Email, Subject: title, Body: text})
+	}
+}
+
+// dcaLegMessage возвращает заголовок и текст уведомления о пропущенном или невыполненном обмене плана
+func dcaLegMessage(plan models.DCAPlan, leg models.DCALeg) (string, string) {
+	title, result := "Регулярный обмен не выполнен", "не выполнен"
+	if leg.Status == models.DCALegSkipped {
+		title, result = "Регулярный обмен пропущен", "пропущен"
+	}
+	return title, fmt.Sprintf("Обмен %d из %d по плану №%d (%.2f %s -> %s) %s: %s.",
+		leg.Number, plan.Legs, plan.ID, plan.Amount, plan.FromCurrency, plan.ToCurrency, result, leg.Error)
+}
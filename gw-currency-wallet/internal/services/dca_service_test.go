@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"testing"
+	"time"
+)
+
+// newTestDCA создает сервис планов с кошельком alice и планом из legs обменов по amount USD в EUR
+func newTestDCA(t *testing.T, balance models.Balance, amount float64, legs int) (*DCAService, *fakeDCA, *fakeWallets, *[]models.DCALeg) {
+	t.Helper()
+	wallet, wallets := newTestWallet(map[int]models.Balance{testAlice: balance})
+	repo := newFakeDCA()
+	notified := new([]models.DCALeg)
+	s := NewDCAService(repo, wallet, nil, func(_ context.Context, _ models.DCAPlan, leg models.DCALeg) error {
+		*notified = append(*notified, leg)
+		return nil
+	})
+	_, err := s.Create(context.Background(), testAlice, models.CreateDCAPlanRequest{
+		FromCurrency: "USD",
+		ToCurrency:   "EUR",
+		Amount:       amount,
+		Time:         "12:00",
+		Timezone:     "Europe/Moscow",
+		Legs:         legs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, repo, wallets, notified
+}
+
+// Обмен при недостатке средств пропускается и засчитывается в план, следующий выполняется по расписанию
+func TestDCASkipsLegOnInsufficientFunds(t *testing.T) {
+	ctx := context.Background()
+	s, repo, wallets, notified := newTestDCA(t, models.Balance{USD: 100}, 40, 4)
+
+	wantLegs := []string{models.DCALegExecuted, models.DCALegExecuted, models.DCALegSkipped, models.DCALegExecuted}
+	for i := range wantLegs {
+		if i == 3 {
+			wallets.UpdateBalance(ctx, testAlice, "USD", 30) // Пополнение после пропуска
+		}
+		repo.due(1)
+		if err := s.RunDue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	progress, err := s.Progress(ctx, testAlice, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(progress.Legs) != len(wantLegs) {
+		t.Fatalf("обменов %d, ожидалось %d", len(progress.Legs), len(wantLegs))
+	}
+	for i, leg := range progress.Legs {
+		if leg.Status != wantLegs[i] {
+			t.Errorf("обмен %d: %s (%s), ожидался %s", leg.Number, leg.Status, leg.Error, wantLegs[i])
+		}
+	}
+
+	plan := progress.Plan
+	if plan.Status != models.DCAPlanCompleted || plan.Executed != 3 || plan.Skipped != 1 || plan.NextRunAt != nil {
+		t.Errorf("план %s: выполнено %d, пропущено %d, следующий обмен %v", plan.Status, plan.Executed, plan.Skipped, plan.NextRunAt)
+	}
+	if progress.TotalSpent != 120 || progress.TotalReceived != 108 || progress.AverageRate != 0.9 {
+		t.Errorf("итоги: списано %v, получено %v, средний курс %v", progress.TotalSpent, progress.TotalReceived, progress.AverageRate)
+	}
+	if got := wallets.balance(testAlice); got != (models.Balance{USD: 10, EUR: 108}) {
+		t.Errorf("баланс %+v", got)
+	}
+	if len(*notified) != 1 || (*notified)[0].Number != 3 || (*notified)[0].Status != models.DCALegSkipped {
+		t.Errorf("уведомления: %+v, ожидалось уведомление о пропуске обмена 3", *notified)
+	}
+}
+
+func TestDCALegResults(t *testing.T) {
+	tests := []struct {
+		name        string
+		prepare     func(s *DCAService, repo *fakeDCA)
+		wantStatus  string
+		wantBalance models.Balance
+	}{
+		{
+			name:        "обмен выполнен",
+			wantStatus:  models.DCALegExecuted,
+			wantBalance: models.Balance{USD: 60, EUR: 36},
+		},
+		{
+			name: "валюта приостановлена",
+			prepare: func(s *DCAService, _ *fakeDCA) {
+				s.wallet.freezes = NewCurrencyFreezeService(newFakeFreezes(), 0)
+				s.wallet.freezes.Freeze(context.Background(), 99, "EUR", "")
+			},
+			wantStatus:  models.DCALegFailed,
+			wantBalance: models.Balance{USD: 100},
+		},
+		{
+			name: "обмен прерван сбоем экземпляра",
+			prepare: func(_ *DCAService, repo *fakeDCA) {
+				repo.StartDCALeg(context.Background(), &models.DCALeg{PlanID: 1, Number: 1, Status: models.DCALegExecuting})
+			},
+			wantStatus:  models.DCALegFailed,
+			wantBalance: models.Balance{USD: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, repo, wallets, notified := newTestDCA(t, models.Balance{USD: 100}, 40, 2)
+			if tt.prepare != nil {
+				tt.prepare(s, repo)
+			}
+			repo.due(1)
+			if err := s.RunDue(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			legs, _ := repo.ListDCALegs(ctx, 1)
+			if len(legs) != 1 || legs[0].Status != tt.wantStatus {
+				t.Fatalf("обмены %+v, ожидался один в состоянии %s", legs, tt.wantStatus)
+			}
+			if plan := repo.plan(1); plan.Status != models.DCAPlanActive || plan.NextRunAt == nil || !plan.NextRunAt.After(time.Now()) {
+				t.Errorf("план не перенесен на следующий обмен: %s, %v", plan.Status, plan.NextRunAt)
+			}
+			if got := wallets.balance(testAlice); got != tt.wantBalance {
+				t.Errorf("баланс %+v, ожидался %+v", got, tt.wantBalance)
+			}
+			if wantNotify := tt.wantStatus != models.DCALegExecuted; (len(*notified) > 0) != wantNotify {
+				t.Errorf("уведомлений: %d", len(*notified))
+			}
+		})
+	}
+}
+
+func TestDCACreateValidation(t *testing.T) {
+	valid := models.CreateDCAPlanRequest{FromCurrency: "USD", ToCurrency: "EUR", Amount: 10, Time: "09:30", Timezone: "UTC", Legs: 5}
+	tests := []struct {
+		name   string
+		modify func(r *models.CreateDCAPlanRequest)
+	}{
+		{"совпадающие валюты", func(r *models.CreateDCAPlanRequest) { r.ToCurrency = "USD" }},
+		{"неподдерживаемая валюта", func(r *models.CreateDCAPlanRequest) { r.ToCurrency = "GBP" }},
+		{"нулевая сумма", func(r *models.CreateDCAPlanRequest) { r.Amount = 0 }},
+		{"нет обменов", func(r *models.CreateDCAPlanRequest) { r.Legs = 0 }},
+		{"слишком много обменов", func(r *models.CreateDCAPlanRequest) { r.Legs = maxDCALegs + 1 }},
+		{"неизвестная периодичность", func(r *models.CreateDCAPlanRequest) { r.Period = "monthly" }},
+		{"некорректное время", func(r *models.CreateDCAPlanRequest) { r.Time = "25:00" }},
+		{"неизвестный часовой пояс", func(r *models.CreateDCAPlanRequest) { r.Timezone = "Mars/Olympus" }},
+	}
+
+	wallet, _ := newTestWallet(map[int]models.Balance{testAlice: {}})
+	s := NewDCAService(newFakeDCA(), wallet, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid
+			tt.modify(&request)
+			if _, err := s.Create(context.Background(), testAlice, request); !errors.Is(err, ErrInvalidDCAPlan) {
+				t.Errorf("ошибка %v, ожидалась %v", err, ErrInvalidDCAPlan)
+			}
+		})
+	}
+}
+
+func TestNextDCARun(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("нет базы часовых поясов:", err)
+	}
+	tests := []struct {
+		name      string
+		period    string
+		scheduled time.Time
+		now       time.Time
+		want      time.Time
+	}{
+		{
+			name:      "следующий день",
+			period:    models.DCAPeriodDaily,
+			scheduled: time.Date(2025, 5, 10, 12, 0, 0, 0, berlin),
+			now:       time.Date(2025, 5, 10, 12, 0, 5, 0, berlin),
+			want:      time.Date(2025, 5, 11, 12, 0, 0, 0, berlin),
+		},
+		{
+			name:      "переход на летнее время",
+			period:    models.DCAPeriodDaily,
+			scheduled: time.Date(2025, 3, 29, 12, 0, 0, 0, berlin),
+			now:       time.Date(2025, 3, 29, 12, 1, 0, 0, berlin),
+			want:      time.Date(2025, 3, 30, 12, 0, 0, 0, berlin),
+		},
+		{
+			name:      "пропущенные запуски не догоняются",
+			period:    models.DCAPeriodDaily,
+			scheduled: time.Date(2025, 5, 1, 12, 0, 0, 0, berlin),
+			now:       time.Date(2025, 5, 4, 15, 0, 0, 0, berlin),
+			want:      time.Date(2025, 5, 5, 12, 0, 0, 0, berlin),
+		},
+		{
+			name:      "следующая неделя",
+			period:    models.DCAPeriodWeekly,
+			scheduled: time.Date(2025, 10, 20, 12, 0, 0, 0, berlin),
+			now:       time.Date(2025, 10, 20, 12, 0, 1, 0, berlin),
+			want:      time.Date(2025, 10, 27, 12, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &models.DCAPlan{Period: tt.period, TimeOfDay: "12:00", Timezone: "Europe/Berlin"}
+			if got := nextDCARun(plan, tt.scheduled, tt.now); !got.Equal(tt.want) {
+				t.Errorf("следующий обмен %s, ожидался %s", got, tt.want)
+			}
+		})
+	}
+}
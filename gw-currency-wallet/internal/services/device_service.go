@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/geoip"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/notify"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"math/big"
+	"time"
+)
+
+var (
+	// ErrConfirmationNotFound - подтверждение не найдено, истекло или исчерпаны попытки ввода кода
+	ErrConfirmationNotFound = errors.New("подтверждение не найдено или истекло")
+	// ErrInvalidConfirmationCode - неверный код подтверждения
+	ErrInvalidConfirmationCode = errors.New("неверный код подтверждения")
+)
+
+// Префиксы ключей кэша подтверждений входа
+const (
+	deviceConfirmKeyPrefix  = "device:confirm:"          // Ожидающее подтверждение (JSON pendingDevice)
+	deviceAttemptsKeyPrefix = "device:confirm-attempts:" // Счетчик неверных кодов
+)
+
+// DeviceOptions содержит параметры контроля устройств
+type DeviceOptions struct {
+	RequireConfirmation bool          // Вход с нового устройства требует кода из письма
+	CodeTTL             time.Duration // Время действия кода подтверждения
+	MaxCodeAttempts     int           // Число попыток ввода кода
+}
+
+// LoginDevice - сведения об устройстве из запроса входа
+type LoginDevice struct {
+	UserAgent string // Заголовок User-Agent
+	DeviceID  string // Необязательный идентификатор установки клиента (заголовок X-Device-ID)
+	IP        string // IP адрес клиента
+}
+
+// pendingDevice - вход с нового устройства, ожидающий подтверждения кодом
+type pendingDevice struct {
+	Device   models.Device `json:"device"`    // Устройство, сохраняемое после подтверждения
+	CodeHash string        `json:"code_hash"` // SHA-256 кода подтверждения
+}
+
+// DeviceService ведет список устройств пользователей, уведомляет о входе с нового устройства
+// и при включенном подтверждении выдает токен только после ввода кода из письма
+type DeviceService struct {
+	repo     storage.DeviceRepository // Устройства пользователей
+	users    storage.UserRepository   // Пользователи (email для уведомлений)
+	cache    storage.Cache            // Ожидающие подтверждения
+	locator  *geoip.Locator           // Геолокация адресов (nil - выключена)
+	notifier notify.Notifier          // Отправка уведомлений
+	activity *ActivityService         // Журнал активности (входы)
+	opts     DeviceOptions            // Параметры контроля
+}
+
+// NewDeviceService создает сервис контроля устройств
+// Параметры:
+//   - repo: хранилище устройств
+//   - users: хранилище пользователей
+//   - cache: кэш ожидающих подтверждений
+//   - locator: геолокация адресов (nil - местоположение не определяется)
+//   - notifier: отправка уведомлений пользователям
+//   - activity: журнал активности пользователя
+//   - opts: параметры подтверждения входа
+//
+// Возвращает:
+//   - *DeviceService: инициализированный сервис
+func NewDeviceService(
+	repo storage.DeviceRepository,
+	users storage.UserRepository,
+	cache storage.Cache,
+	locator *geoip.Locator,
+	notifier notify.Notifier,
+	activity *ActivityService,
+	opts DeviceOptions,
+) *DeviceService {
+	return &DeviceService{
+		repo:     repo,
+		users:    users,
+		cache:    cache,
+		locator:  locator,
+		notifier: notifier,
+		activity: activity,
+		opts:     opts,
+	}
+}
+
+// CheckLogin записывает вход пользователя с устройства
+// Для устройства, с которого пользователь еще не входил, отправляется уведомление;
+// при включенном подтверждении устройство сохраняется только после ввода кода из письма.
+// Первое устройство пользователя (первый вход после регистрации) считается доверенным
+// Параметры:
+//   - ctx: контекст выполнения
+//   - user: пользователь, прошедший проверку пароля
+//   - login: сведения об устройстве
+//
+// Возвращает:
+//   - string: идентификатор подтверждения (пусто - подтверждение не требуется, можно выдать токен)
+//   - error: ошибка хранилища или отправки кода
+func (s *DeviceService) CheckLogin(ctx context.Context, user *models.User, login LoginDevice) (string, error) {
+	location := s.locator.Lookup(login.IP)
+	device := &models.Device{
+		UserID:      user.ID,
+		Fingerprint: deviceFingerprint(login.UserAgent, login.DeviceID),
+		UserAgent:   login.UserAgent,
+		LastIP:      login.IP,
+		Country:     location.Country,
+		City:        location.City,
+	}
+
+	known, err := s.repo.FindDevice(ctx, user.ID, device.Fingerprint)
+	if err != nil {
+		return "", err
+	}
+	if known != nil {
+		if err := s.repo.SaveDevice(ctx, device); err != nil {
+			return "", err
+		}
+		s.recordLogin(ctx, device, models.ActionLogin, false)
+		return "", nil
+	}
+
+	devices, err := s.repo.ListDevices(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if len(devices) == 0 {
+		device.Confirmed = s.opts.RequireConfirmation
+		if err := s.repo.SaveDevice(ctx, device); err != nil {
+			return "", err
+		}
+		s.recordLogin(ctx, device, models.ActionLogin, true)
+		return "", nil
+	}
+
+	if s.opts.RequireConfirmation {
+		return s.requestConfirmation(ctx, user, device, location)
+	}
+
+	if err := s.repo.SaveDevice(ctx, device); err != nil {
+		return "", err
+	}
+	s.recordLogin(ctx, device, models.ActionLogin, true)
+	msg := notify.Message{
+		To:      user.Email,
+		Subject: "Вход с нового устройства",
+		Body: fmt.Sprintf("Выполнен вход в кошелек %s с нового устройства.\n\n%s\n\n"+
+			"Если это были не вы, смените пароль.", user.Username, deviceDescription(device, location)),
+	}
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		// Вход не блокируется из-за недоступности почты
+		log.Printf("Ошибка уведомления пользователя %d о новом устройстве: %v", user.ID, err)
+	}
+	return "", nil
+}
+
+// requestConfirmation сохраняет ожидающий вход и отправляет пользователю код подтверждения
+func (s *DeviceService) requestConfirmation(
+	ctx context.Context,
+	user *models.User,
+	device *models.Device,
+	location geoip.Location,
+) (string, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	code, err := confirmationCode()
+	if err != nil {
+		return "", err
+	}
+
+	device.Confirmed = true
+	pending, err := json.Marshal(pendingDevice{Device: *device, CodeHash: hashCode(code)})
+	if err != nil {
+		return "", fmt.Errorf("ошибка сериализации подтверждения: %w", err)
+	}
+	if err := s.cache.Set(ctx, deviceConfirmKeyPrefix+id, pending, s.opts.CodeTTL); err != nil {
+		return "", fmt.Errorf("ошибка сохранения подтверждения: %w", err)
+	}
+
+	msg := notify.Message{
+		To:      user.Email,
+		Subject: "Код подтверждения входа",
+		Body: fmt.Sprintf("Выполняется вход в кошелек %s с нового устройства.\n\n%s\n\n"+
+			"Код подтверждения: %s (действует %s).\n\nЕсли это не вы, не сообщайте код и смените пароль.",
+			user.Username, deviceDescription(device, location), code, s.opts.CodeTTL),
+	}
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		_ = s.cache.Delete(ctx, deviceConfirmKeyPrefix+id)
+		return "", err
+	}
+	return id, nil
+}
+
+// ConfirmLogin проверяет код подтверждения и сохраняет устройство как доверенное
+// После MaxCodeAttempts неверных кодов подтверждение удаляется
+// Параметры:
+//   - ctx: контекст выполнения
+//   - confirmationID: идентификатор из ответа входа
+//   - code: код из письма
+//
+// Возвращает:
+//   - *models.User: пользователь, которому выдается токен
+//   - error: ErrConfirmationNotFound, ErrInvalidConfirmationCode или ошибка хранилища
+func (s *DeviceService) ConfirmLogin(ctx context.Context, confirmationID, code string) (*models.User, error) {
+	key := deviceConfirmKeyPrefix + confirmationID
+	data, err := s.cache.Get(ctx, key)
+	if errors.Is(err, storage.ErrCacheMiss) {
+		return nil, ErrConfirmationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения подтверждения: %w", err)
+	}
+	var pending pendingDevice
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, fmt.Errorf("ошибка чтения подтверждения: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashCode(code)), []byte(pending.CodeHash)) != 1 {
+		attempts, err := s.cache.Incr(ctx, deviceAttemptsKeyPrefix+confirmationID, s.opts.CodeTTL)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка учета попытки ввода кода: %w", err)
+		}
+		if int(attempts) >= s.opts.MaxCodeAttempts {
+			_ = s.cache.Delete(ctx, key, deviceAttemptsKeyPrefix+confirmationID)
+		}
+		return nil, ErrInvalidConfirmationCode
+	}
+	// Код одноразовый
+	if err := s.cache.Delete(ctx, key, deviceAttemptsKeyPrefix+confirmationID); err != nil {
+		return nil, fmt.Errorf("ошибка удаления подтверждения: %w", err)
+	}
+
+	if err := s.repo.SaveDevice(ctx, &pending.Device); err != nil {
+		return nil, err
+	}
+	s.recordLogin(ctx, &pending.Device, models.ActionLoginConfirmed, true)
+	user, err := s.users.GetUserByID(ctx, pending.Device.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrConfirmationNotFound
+	}
+	return user, nil
+}
+
+// CodeTTL возвращает время действия кода подтверждения
+func (s *DeviceService) CodeTTL() time.Duration {
+	return s.opts.CodeTTL
+}
+
+// ListDevices возвращает устройства пользователя
+func (s *DeviceService) ListDevices(ctx context.Context, userID int) ([]models.Device, error) {
+	return s.repo.ListDevices(ctx, userID)
+}
+
+// recordLogin записывает вход с устройства в журнал активности
+func (s *DeviceService) recordLogin(ctx context.Context, device *models.Device, action string, newDevice bool) {
+	s.activity.Record(ctx, models.ActivityEntry{
+		UserID:    device.UserID,
+		Category:  models.ActivityLogin,
+		Action:    action,
+		IP:        device.LastIP,
+		UserAgent: device.UserAgent,
+		Details: map[string]interface{}{
+			"device_id":  device.ID,
+			"new_device": newDevice,
+			"country":    device.Country,
+			"city":       device.City,
+		},
+	})
+}
+
+// deviceFingerprint вычисляет отпечаток устройства по User-Agent и идентификатору установки клиента
+func deviceFingerprint(userAgent, deviceID string) string {
+	sum := sha256.Sum256([]byte(userAgent + "|" + deviceID))
+	return hex.EncodeToString(sum[:])
+}
+
+// deviceDescription описывает устройство для уведомления
+func deviceDescription(device *models.Device, location geoip.Location) string {
+	userAgent := device.UserAgent
+	if userAgent == "" {
+		userAgent = "неизвестно"
+	}
+	return fmt.Sprintf("Устройство: %s\nIP адрес: %s\nМестоположение: %s\nВремя: %s",
+		userAgent, device.LastIP, location, time.Now().UTC().Format(time.RFC1123))
+}
+
+// confirmationCode генерирует шестизначный код подтверждения
+func confirmationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("ошибка генерации кода: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// randomHex возвращает случайную строку из size байт в шестнадцатеричном виде
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("ошибка генерации идентификатора: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashCode возвращает SHA-256 кода подтверждения или токена обновления (они не хранятся в открытом виде)
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"gw-currency-wallet/internal/models"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	pb "gw-proto/proto"
+)
+
+// CheckHealth проверяет связь с сервисом обмена запросом курсов в обход кэша и запоминает результат
+// Вызов не ожидает готовности соединения (WaitForReady): недоступный сервис обнаруживается сразу,
+// а не по истечении таймаута. Смена состояния записывается в лог
+// Вместе с результатом запоминается состояние обновлений курсов в сервисе обмена (GetUpdateStatus);
+// ошибка этого запроса не влияет на результат проверки
+// Параметры:
+//   - ctx: контекст выполнения
+//   - timeout: максимальное время проверки
+//
+// Возвращает:
+//   - error: ошибка запроса курсов (nil - сервис обмена отвечает)
+func (s *ExchangeService) CheckHealth(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	rates, err := s.client.GetExchangeRates(ctx, &pb.ExchangeRatesRequest{}, grpc.WaitForReady(false))
+	now := time.Now()
+
+	status, errText := models.ExchangeHealthOK, ""
+	switch {
+	case err != nil:
+		status, errText = models.ExchangeHealthUnavailable, err.Error()
+	case rates.Degraded:
+		status = models.ExchangeHealthStale
+	}
+
+	var updates []models.ExchangeUpdateStatus
+	if err == nil {
+		updates, _ = s.UpdateStatus(ctx)
+	}
+
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	previous := s.health.Status
+	s.health.Status, s.health.Error, s.health.CheckedAt = status, errText, &now
+	s.health.Updates = updates
+	if err != nil {
+		s.health.Failures++
+	} else {
+		s.health.Failures = 0
+	}
+
+	if status != previous {
+		switch status {
+		case models.ExchangeHealthOK:
+			log.Printf("Сервис обмена доступен (соединение %s)", s.conn.GetState())
+		case models.ExchangeHealthStale:
+			log.Printf("ВНИМАНИЕ: сервис обмена сообщает об устаревших курсах")
+		case models.ExchangeHealthUnavailable:
+			log.Printf("ВНИМАНИЕ: сервис обмена недоступен (соединение %s), кошелек работает в деградированном режиме: "+
+				"курсы и обмен недоступны, остальные операции выполняются: %v", s.conn.GetState(), err)
+		}
+	}
+	return err
+}
+
+// MonitorHealth проверяет связь с сервисом обмена с интервалом interval до отмены контекста
+// Проверка выполняется на каждом экземпляре (состояние публикуется в его /readyz), поэтому
+// не использует планировщик задач с распределенной блокировкой
+// Параметры:
+//   - ctx: контекст выполнения
+//   - interval: интервал проверок
+//   - timeout: максимальное время одной проверки
+func (s *ExchangeService) MonitorHealth(ctx context.Context, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.CheckHealth(ctx, timeout)
+		}
+	}
+}
+
+// Health возвращает состояние связи с сервисом обмена по результатам последней проверки
+func (s *ExchangeService) Health() models.ExchangeHealth {
+	s.healthMu.Lock()
+	health := s.health
+	s.healthMu.Unlock()
+
+	health.Connection = s.conn.GetState().String()
+	return health
+}
+
+// Degraded сообщает, что по результатам последней проверки сервис обмена недоступен
+func (s *ExchangeService) Degraded() bool {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	return s.health.Status == models.ExchangeHealthUnavailable
+}
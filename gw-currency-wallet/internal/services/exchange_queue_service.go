@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/notify"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tenant"
+	"log"
+	"strings"
+	"time"
+)
+
+// Ошибки очереди обменов
+var (
+	ErrInvalidQueuedExchange = errors.New("некорректный обмен для очереди")
+	ErrExchangeQueueFull     = errors.New("слишком много обменов в очереди")
+)
+
+const (
+	maxPendingExchanges  = 20               // Ожидающих обменов на пользователя
+	queuedExchangesLimit = 100              // Обменов в списке пользователя
+	queueBatchSize       = 100              // Обменов за один запуск исполнения
+	queueStaleAfter      = 10 * time.Minute // Захваченный обмен без результата дольше считается прерванным сбоем
+)
+
+// QueuedExchangeNotifyFunc уведомляет пользователя о результате исполнения обмена из очереди
+type QueuedExchangeNotifyFunc func(ctx context.Context, exchange models.QueuedExchange) error
+
+// ExchangeQueueService откладывает обмены до наступления условия исполнения и исполняет их
+// Обмен исполняется через WalletService по курсу и комиссии на момент исполнения, со всеми проверками обычного обмена;
+// средства до исполнения не резервируются
+type ExchangeQueueService struct {
+	repo   storage.QueuedExchangeRepository // Очередь обменов
+	wallet *WalletService                   // Исполнение обменов
+	notify QueuedExchangeNotifyFunc         // Уведомление о результате исполнения (nil - только журнал)
+
+	rateWatch bool // Обмены по следующему курсу исполняются по событиям сервиса обмена (WatchRates)
+}
+
+// NewExchangeQueueService создает сервис очереди обменов
+// Параметры:
+//   - repo: хранилище очереди
+//   - wallet: сервис кошелька, исполняющий обмены
+//   - notify: уведомление пользователя об исполнении или неисполнении обмена (nil - без уведомлений)
+//
+// Возвращает:
+//   - *ExchangeQueueService: инициализированный сервис
+func NewExchangeQueueService(
+	repo storage.QueuedExchangeRepository,
+	wallet *WalletService,
+	notify QueuedExchangeNotifyFunc,
+) *ExchangeQueueService {
+	return &ExchangeQueueService{repo: repo, wallet: wallet, notify: notify}
+}
+
+// WatchRates исполняет обмены по следующему курсу при событиях сервиса обмена об изменении курсов
+// Вызывается до приема запросов; без него обмены по следующему курсу не принимаются
+func (s *ExchangeQueueService) WatchRates(exchange *ExchangeService) {
+	exchange.OnRateUpdate(s.onRateUpdate)
+	s.rateWatch = true
+}
+
+// Enqueue ставит обмен в очередь
+// В пробном режиме (storage.WithDryRun) обмен проверяется, но в очередь не ставится (ID = 0)
+// Параметры:
+//   - ctx: контекст выполнения (арендатор пользователя)
+//   - userID: пользователь
+//   - fromCurrency: исходная валюта
+//   - toCurrency: целевая валюта
+//   - amount: сумма для обмена
+//   - trigger: условие исполнения (models.QueuedExchangeTrigger*)
+//   - executeAfter: исполнение не раньше этого времени
+//
+// Возвращает:
+//   - *models.QueuedExchange: обмен в очереди
+//   - error: ErrInvalidQueuedExchange, ErrExchangeQueueFull или ошибка хранилища
+func (s *ExchangeQueueService) Enqueue(
+	ctx context.Context,
+	userID int,
+	fromCurrency string,
+	toCurrency string,
+	amount float64,
+	trigger string,
+	executeAfter time.Time,
+) (*models.QueuedExchange, error) {
+	if !s.wallet.currencyAllowed(ctx, fromCurrency) || !s.wallet.currencyAllowed(ctx, toCurrency) {
+		return nil, fmt.Errorf("%w: неподдерживаемая валюта", ErrInvalidQueuedExchange)
+	}
+	if fromCurrency == toCurrency {
+		return nil, fmt.Errorf("%w: валюты обмена совпадают", ErrInvalidQueuedExchange)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: сумма должна быть положительной", ErrInvalidQueuedExchange)
+	}
+	if trigger == models.QueuedExchangeTriggerRate && !s.rateWatch {
+		return nil, fmt.Errorf("%w: исполнение по следующему курсу недоступно (поток изменений курсов выключен)",
+			ErrInvalidQueuedExchange)
+	}
+
+	pending, err := s.repo.CountPendingExchanges(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pending >= maxPendingExchanges {
+		return nil, fmt.Errorf("%w: не больше %d ожидающих обменов", ErrExchangeQueueFull, maxPendingExchanges)
+	}
+
+	exchange := &models.QueuedExchange{
+		UserID:       userID,
+		TenantID:     tenant.IDFromContext(ctx),
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+		Amount:       amount,
+		Trigger:      trigger,
+		Status:       models.QueuedExchangePending,
+		ExecuteAfter: executeAfter,
+	}
+	if storage.IsDryRun(ctx) {
+		return exchange, nil
+	}
+	if err := s.repo.CreateQueuedExchange(ctx, exchange); err != nil {
+		return nil, err
+	}
+	log.Printf("Обмен %d пользователя %d поставлен в очередь (%s): %.2f %s в %s после %s",
+		exchange.ID, userID, trigger, amount, fromCurrency, toCurrency, executeAfter.Format(time.RFC3339))
+	return exchange, nil
+}
+
+// List возвращает обмены пользователя в очереди, включая исполненные и неисполненные
+func (s *ExchangeQueueService) List(ctx context.Context, userID int) ([]models.QueuedExchange, error) {
+	return s.repo.ListQueuedExchanges(ctx, userID, queuedExchangesLimit)
+}
+
+// Cancel отменяет ожидающий исполнения обмен пользователя
+// Возвращает:
+//   - *models.QueuedExchange: отмененный обмен
+//   - error: storage.ErrQueuedExchangeNotFound, если обмена нет или он уже исполняется
+func (s *ExchangeQueueService) Cancel(ctx context.Context, userID int, id int64) (*models.QueuedExchange, error) {
+	exchange, err := s.repo.CancelQueuedExchange(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Обмен %d из очереди отменен пользователем %d", id, userID)
+	return exchange, nil
+}
+
+// ExecuteDue исполняет обмены, отложенные до открытия окна исполнения пары
+// Обмены, исполнение которых прервано сбоем экземпляра, отмечаются неисполненными без повтора:
+// обмен мог быть выполнен, результат виден в истории операций
+// Параметры:
+//   - ctx: контекст выполнения
+//
+// Возвращает:
+//   - error: ошибка хранилища очереди
+func (s *ExchangeQueueService) ExecuteDue(ctx context.Context) error {
+	if stale, err := s.repo.FailStaleQueuedExchanges(ctx, queueStaleAfter); err != nil {
+		return err
+	} else if stale > 0 {
+		log.Printf("Обменов из очереди, прерванных сбоем: %d", stale)
+	}
+	return s.executeClaimed(ctx, models.QueuedExchangeTriggerWindow, nil, time.Now())
+}
+
+// onRateUpdate исполняет обмены по следующему курсу с изменившимися валютами, поставленные в очередь
+// до изменения курсов. Событие получает каждый экземпляр, обмен исполняет захвативший его
+func (s *ExchangeQueueService) onRateUpdate(ctx context.Context, currencies []string, changedAt time.Time) {
+	if err := s.executeClaimed(ctx, models.QueuedExchangeTriggerRate, currencies, changedAt); err != nil {
+		log.Printf("Ошибка исполнения обменов по новому курсу (%s): %v", strings.Join(currencies, ", "), err)
+	}
+}
+
+// executeClaimed захватывает и исполняет обмены с условием trigger, наступившим в момент at
+func (s *ExchangeQueueService) executeClaimed(ctx context.Context, trigger string, currencies []string, at time.Time) error {
+	for {
+		exchanges, err := s.repo.ClaimQueuedExchanges(ctx, trigger, currencies, at, queueBatchSize)
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for i := range exchanges {
+			if err := s.execute(ctx, &exchanges[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 || len(exchanges) < queueBatchSize {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// execute исполняет захваченный обмен и сохраняет результат
+// Если окно пары снова закрыто (обмен захвачен перед закрытием), обмен переносится на следующее открытие
+func (s *ExchangeQueueService) execute(ctx context.Context, exchange *models.QueuedExchange) error {
+	// Валюты и комиссия определяются арендатором пользователя
+	ctx = tenant.WithID(ctx, exchange.TenantID)
+	response, err := s.wallet.Exchange(ctx, exchange.UserID, exchange.FromCurrency, exchange.ToCurrency, exchange.Amount)
+
+	var closed *TradingClosedError
+	switch {
+	case errors.As(err, &closed) && closed.NextOpen != nil:
+		// Обмен по следующему курсу после переноса исполняется первым курсом, опубликованным в окне
+		return s.repo.RescheduleQueuedExchange(ctx, exchange.ID, *closed.NextOpen)
+	case err != nil:
+		log.Printf("Обмен %d из очереди не исполнен: %v", exchange.ID, err)
+		if err := s.repo.FailQueuedExchange(ctx, exchange.ID, err.Error()); err != nil {
+			return err
+		}
+		exchange.Status, exchange.Error = models.QueuedExchangeFailed, err.Error()
+	default:
+		log.Printf("Обмен %d из очереди исполнен: %.2f %s -> %.2f %s по курсу %.6f", exchange.ID,
+			exchange.Amount, exchange.FromCurrency, response.ExchangedAmount, exchange.ToCurrency, response.Rate)
+		if err := s.repo.CompleteQueuedExchange(ctx, exchange.ID, response.Rate, response.ExchangedAmount); err != nil {
+			return err
+		}
+		exchange.Status, exchange.Rate, exchange.ExchangedAmount = models.QueuedExchangeExecuted, &response.Rate, &response.ExchangedAmount
+	}
+	now := time.Now()
+	exchange.ExecutedAt = &now
+
+	// Результат сохранен: ошибка уведомления не влияет на исполнение
+	if s.notify != nil {
+		if err := s.notify(ctx, *exchange); err != nil {
+			log.Printf("Ошибка уведомления пользователя %d об обмене %d из очереди: %v", exchange.UserID, exchange.ID, err)
+		}
+	}
+	return nil
+}
+
+// EmailQueuedExchangeNotifier возвращает уведомление о результате исполнения обмена из очереди по email
+// Параметры:
+//   - users: пользователи (адрес уведомления)
+//   - notifier: отправка писем
+//
+// Возвращает:
+//   - QueuedExchangeNotifyFunc: уведомление по email
+func EmailQueuedExchangeNotifier(users storage.UserRepository, notifier notify.Notifier) QueuedExchangeNotifyFunc {
+	return func(ctx context.Context, exchange models.QueuedExchange) error {
+		user, err := users.GetUserByID(ctx, exchange.UserID)
+		if err != nil {
+			return err
+		}
+		title, text := queuedExchangeMessage(exchange)
+		return notifier.Notify(ctx, notify.Message{To: user.Email, Subject: title, Body: text})
+	}
+}
+
+// queuedExchangeMessage возвращает заголовок и текст уведомления о результате исполнения обмена из очереди
+func queuedExchangeMessage(exchange models.QueuedExchange) (string, string) {
+	if exchange.Status == models.QueuedExchangeExecuted && exchange.Rate != nil && exchange.ExchangedAmount != nil {
+		return "Отложенный обмен исполнен", fmt.Sprintf("Обмен №%d исполнен: %.2f %s -> %.2f %s по курсу %.6f.",
+			exchange.ID, exchange.Amount, exchange.FromCurrency, *exchange.ExchangedAmount, exchange.ToCurrency, *exchange.Rate)
+	}
+	return "Отложенный обмен не исполнен", fmt.Sprintf("Обмен №%d (%.2f %s -> %s) не исполнен: %s.",
+		exchange.ID, exchange.Amount, exchange.FromCurrency, exchange.ToCurrency, exchange.Error)
+}
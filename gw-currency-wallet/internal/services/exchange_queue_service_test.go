@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tradinghours"
+	"testing"
+	"time"
+)
+
+func TestExchangeQueueEnqueue(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		amount   float64
+		trigger  string
+		pending  int // Обменов пользователя в очереди до постановки
+		wantErr  error
+	}{
+		{name: "обмен по открытию окна", from: "USD", to: "EUR", amount: 10, trigger: models.QueuedExchangeTriggerWindow},
+		{name: "неподдерживаемая валюта", from: "USD", to: "GBP", amount: 10, trigger: models.QueuedExchangeTriggerWindow, wantErr: ErrInvalidQueuedExchange},
+		{name: "совпадающие валюты", from: "USD", to: "USD", amount: 10, trigger: models.QueuedExchangeTriggerWindow, wantErr: ErrInvalidQueuedExchange},
+		{name: "нулевая сумма", from: "USD", to: "EUR", trigger: models.QueuedExchangeTriggerWindow, wantErr: ErrInvalidQueuedExchange},
+		{name: "следующий курс без потока курсов", from: "USD", to: "EUR", amount: 10, trigger: models.QueuedExchangeTriggerRate, wantErr: ErrInvalidQueuedExchange},
+		{name: "очередь заполнена", from: "USD", to: "EUR", amount: 10, trigger: models.QueuedExchangeTriggerWindow, pending: maxPendingExchanges, wantErr: ErrExchangeQueueFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			wallet, _ := newTestWallet(map[int]models.Balance{testAlice: {USD: 100}})
+			queue := &fakeQueue{}
+			for range tt.pending {
+				queue.CreateQueuedExchange(ctx, &models.QueuedExchange{UserID: testAlice, Trigger: models.QueuedExchangeTriggerWindow})
+			}
+			s := NewExchangeQueueService(queue, wallet, nil)
+
+			exchange, err := s.Enqueue(ctx, testAlice, tt.from, tt.to, tt.amount, tt.trigger, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+			}
+			if err == nil && (exchange.ID == 0 || queue.get(exchange.ID).Status != models.QueuedExchangePending) {
+				t.Errorf("обмен не поставлен в очередь: %+v", exchange)
+			}
+		})
+	}
+}
+
+func TestExchangeQueueEnqueueDryRun(t *testing.T) {
+	wallet, _ := newTestWallet(map[int]models.Balance{testAlice: {}})
+	queue := &fakeQueue{}
+	s := NewExchangeQueueService(queue, wallet, nil)
+
+	exchange, err := s.Enqueue(storage.WithDryRun(context.Background()), testAlice, "USD", "EUR", 10,
+		models.QueuedExchangeTriggerWindow, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exchange.ID != 0 || len(queue.exchanges) != 0 {
+		t.Errorf("пробный обмен поставлен в очередь: %+v", exchange)
+	}
+}
+
+func TestExchangeQueueExecuteDue(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     models.Balance
+		hours       string // Окна исполнения (пусто - круглосуточно)
+		wantStatus  string
+		wantBalance models.Balance
+		wantNotify  bool
+	}{
+		{
+			name:        "обмен исполнен",
+			balance:     models.Balance{USD: 100},
+			wantStatus:  models.QueuedExchangeExecuted,
+			wantBalance: models.Balance{USD: 50, EUR: 45},
+			wantNotify:  true,
+		},
+		{
+			name:        "недостаточно средств к исполнению",
+			balance:     models.Balance{USD: 30},
+			wantStatus:  models.QueuedExchangeFailed,
+			wantBalance: models.Balance{USD: 30},
+			wantNotify:  true,
+		},
+		{
+			name:        "окно пары снова закрыто",
+			balance:     models.Balance{USD: 100},
+			hours:       "USD/EUR=" + weekdayAfter(2) + " 00:00-24:00",
+			wantStatus:  models.QueuedExchangePending,
+			wantBalance: models.Balance{USD: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			wallet, wallets := newTestWallet(map[int]models.Balance{testAlice: tt.balance})
+			hours, err := tradinghours.Parse(tt.hours, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wallet.hours = hours
+
+			queue := &fakeQueue{}
+			var notified []models.QueuedExchange
+			s := NewExchangeQueueService(queue, wallet, func(_ context.Context, exchange models.QueuedExchange) error {
+				notified = append(notified, exchange)
+				return nil
+			})
+			queue.CreateQueuedExchange(ctx, &models.QueuedExchange{
+				UserID:       testAlice,
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       50,
+				Trigger:      models.QueuedExchangeTriggerWindow,
+				ExecuteAfter: time.Now().Add(-time.Minute),
+			})
+
+			if err := s.ExecuteDue(ctx); err != nil {
+				t.Fatal(err)
+			}
+			exchange := queue.get(1)
+			if exchange.Status != tt.wantStatus {
+				t.Errorf("состояние обмена %s (%s), ожидалось %s", exchange.Status, exchange.Error, tt.wantStatus)
+			}
+			if tt.wantStatus == models.QueuedExchangePending && !exchange.ExecuteAfter.After(time.Now()) {
+				t.Errorf("обмен не перенесен на открытие окна: %s", exchange.ExecuteAfter)
+			}
+			if got := wallets.balance(testAlice); got != tt.wantBalance {
+				t.Errorf("баланс %+v, ожидался %+v", got, tt.wantBalance)
+			}
+			if (len(notified) > 0) != tt.wantNotify {
+				t.Errorf("уведомлений: %d", len(notified))
+			}
+			if len(notified) > 0 && notified[0].Status != tt.wantStatus {
+				t.Errorf("в уведомлении состояние %s, ожидалось %s", notified[0].Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestExchangeQueueCancel(t *testing.T) {
+	ctx := context.Background()
+	wallet, _ := newTestWallet(map[int]models.Balance{testAlice: {USD: 100}})
+	queue := &fakeQueue{}
+	s := NewExchangeQueueService(queue, wallet, nil)
+	exchange, err := s.Enqueue(ctx, testAlice, "USD", "EUR", 10, models.QueuedExchangeTriggerWindow, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Cancel(ctx, testBob, exchange.ID); !errors.Is(err, storage.ErrQueuedExchangeNotFound) {
+		t.Errorf("отмена чужого обмена: %v", err)
+	}
+	if _, err := s.Cancel(ctx, testAlice, exchange.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := queue.get(exchange.ID).Status; got != models.QueuedExchangeCancelled {
+		t.Errorf("отмененный обмен в состоянии %s", got)
+	}
+}
+
+// weekdayAfter возвращает сокращение дня недели через days дней (UTC) для окон исполнения
+func weekdayAfter(days int) string {
+	names := []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	return names[time.Now().UTC().AddDate(0, 0, days).Weekday()]
+}
@@ -5,72 +5,103 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"gw-currency-wallet/internal/storage/redis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gw-currency-wallet/internal/metrics"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
 	pb "gw-proto/proto" // Импорт сгенерированного protobuf кода
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
+// ratesCacheKey - ключ кэша с курсами валют
+const ratesCacheKey = "exchange:rates"
+
+// currenciesCacheKey - ключ кэша справочника валют (количество знаков после запятой по коду валюты)
+const currenciesCacheKey = "exchange:currencies"
+
+// ratesSnapshot - курсы, полученные от сервиса обмена, с их источниками и временем получения
+// Хранится в кэше целиком, чтобы обмен по кэшированному курсу сохранял его происхождение
+type ratesSnapshot struct {
+	Rates     map[string]float64 `json:"rates"`             // Стоимость единицы валюты в базовой валюте
+	Sources   map[string]string  `json:"sources,omitempty"` // Источник курса валюты (cbr, ecb, manual), базовой валюты нет
+	FetchedAt time.Time          `json:"fetched_at"`        // Время получения курсов от сервиса обмена
+}
+
+// Ограничения графика курса (совпадают с ограничениями GetRateCandles сервиса обмена)
+const (
+	minChartInterval = time.Minute // Минимальная длительность свечи
+	maxChartCandles  = 1000        // Максимальное количество свечей
+)
+
+// Ошибки графика курса
+var (
+	ErrInvalidChart  = errors.New("некорректные параметры графика")
+	ErrNoRateHistory = errors.New("нет истории курса за период")
+)
+
 // ExchangeService предоставляет функционал для работы с курсами валют
 // Использует:
 // - gRPC клиент для получения актуальных курсов
-// - Redis для кэширования результатов
+// - кэш (Redis или in-memory) для хранения результатов
 type ExchangeService struct {
 	client        pb.ExchangeServiceClient // gRPC клиент для сервиса курсов
 	conn          *grpc.ClientConn         // gRPC соединение
-	redisClient   *redis.Client            // Клиент Redis для кэширования
+	cache         storage.Cache            // Кэш курсов валют
 	cacheDuration time.Duration            // Время жизни кэша
+
+	healthMu sync.Mutex
+	health   models.ExchangeHealth // Результат последней проверки связи (CheckHealth)
+
+	listenersMu sync.Mutex
+	listeners   []RateUpdateFunc // Обработчики событий об изменении курсов (OnRateUpdate)
 }
 
+// RateUpdateFunc обрабатывает событие сервиса обмена об изменении курсов валют
+// (вызывается после сброса кэша курсов, поэтому обработчик получает новые курсы)
+type RateUpdateFunc func(ctx context.Context, currencies []string, changedAt time.Time)
+
 // NewExchangeService создает новый экземпляр ExchangeService
 // Параметры:
-//   - addr: адрес gRPC сервиса курсов валют
-//   - redisAddr: адрес Redis сервера
+//   - addr: адрес gRPC сервиса курсов валют (несколько адресов через запятую - балансировка round robin)
+//   - apiToken: API токен клиента для сервиса курсов (пустой - без аутентификации)
+//   - cache: кэш для хранения курсов (Redis или in-memory)
 //   - cacheDuration: время жизни кэша (например 5m)
+//   - opts: дополнительные параметры gRPC соединения (например перехватчики, ClientOptions)
 //
 // Возвращает:
 //   - *ExchangeService: инициализированный сервис
 //   - error: ошибка при создании
-func NewExchangeService(addr string, redisAddr string, cacheDuration time.Duration) (*ExchangeService, error) {
-	if addr == "" {
-		return nil, errors.New("адрес сервиса обмена не может быть пустым")
-	}
-
+func NewExchangeService(addr string, apiToken string, cache storage.Cache, cacheDuration time.Duration, opts ...grpc.DialOption) (*ExchangeService, error) {
 	// Устанавливаем соединение с gRPC сервером
-	// 1. Создаем контекст с таймаутом подключения
-	_, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// 2. Устанавливаем соединение с современными параметрами
-	conn, err := grpc.NewClient(
-		addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithConnectParams(grpc.ConnectParams{
-			MinConnectTimeout: 5 * time.Second, // Минимальное время попытки подключения
-		}),
-	)
-
-	// Инициализация Redis клиента
-	redisClient, err := redis.New(redis.Options{
-		Addr:     redisAddr,
-		Password: "", // Пароль, если требуется
-		DB:       0,  // Номер базы данных
-	})
+	dialOpts := append([]grpc.DialOption{
+		WithAPIToken(apiToken), // Токен клиента для аутентификации в сервисе курсов
+		WithTraceID(),          // Идентификатор запроса для сопоставления журналов
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()), // Span OpenTelemetry на вызов и передача traceparent сервису обмена
+	}, opts...)
+	conn, err := NewExchangeConn(addr, dialOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка подключения к Redis: %w", err)
+		return nil, err
 	}
 
 	return &ExchangeService{
 		client:        pb.NewExchangeServiceClient(conn),
 		conn:          conn,
-		redisClient:   redisClient,
+		cache:         cache,
 		cacheDuration: cacheDuration,
+		health:        models.ExchangeHealth{Status: models.ExchangeHealthUnknown},
 	}, nil
 }
 
 // GetRates возвращает текущие курсы валют
-// Сначала проверяет кэш в Redis, если нет - запрашивает через gRPC
+// Сначала проверяет кэш, если нет - запрашивает через gRPC
 // Возвращает:
 //   - map[string]float64: курс валют (например {"USD": 75.50})
 //   - error: ошибка при получении
@@ -79,41 +110,281 @@ func (s *ExchangeService) GetRates(ctx context.Context) (map[string]float64, err
 		return nil, errors.New("сервис обмена не инициализирован")
 	}
 
-	// Пробуем получить из кэша Redis
-	cacheKey := "exchange:rates"
-	cachedRates, err := s.redisClient.Get(ctx, cacheKey).Bytes()
+	snapshot, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.filterRates(snapshot.Rates), nil // Возвращаем отфильтрованные курсы
+}
+
+// snapshot возвращает курсы с происхождением: из кэша, если они там есть, иначе от сервиса обмена
+func (s *ExchangeService) snapshot(ctx context.Context) (*ratesSnapshot, error) {
+	// Пробуем получить из кэша (записи прежнего формата без курсов считаются промахом)
+	cachedRates, err := s.cache.Get(ctx, ratesCacheKey)
 	if err == nil {
-		var rates map[string]float64
-		if err := json.Unmarshal(cachedRates, &rates); err == nil {
-			return s.filterRates(rates), nil // Возвращаем отфильтрованные курсы
+		var snapshot ratesSnapshot
+		if err := json.Unmarshal(cachedRates, &snapshot); err == nil && len(snapshot.Rates) > 0 {
+			metrics.SetExchangeRatesFetched(ctx, snapshot.FetchedAt)
+			return &snapshot, nil
 		}
 	}
 
-	// Запрашиваем актуальные курсы через gRPC
-	rates, err := s.client.GetExchangeRates(ctx, &pb.Empty{})
+	return s.fetchSnapshot(ctx)
+}
+
+// RefreshRates запрашивает актуальные курсы через gRPC в обход кэша и обновляет кэш
+// Возвращает:
+//   - map[string]float64: курсы поддерживаемых валют
+//   - error: ошибка при получении
+func (s *ExchangeService) RefreshRates(ctx context.Context) (map[string]float64, error) {
+	if s == nil {
+		return nil, errors.New("сервис обмена не инициализирован")
+	}
+
+	snapshot, err := s.fetchSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.filterRates(snapshot.Rates), nil
+}
+
+// fetchSnapshot запрашивает курсы с источниками через gRPC и сохраняет их в кэш
+func (s *ExchangeService) fetchSnapshot(ctx context.Context) (*ratesSnapshot, error) {
+	rates, err := s.client.GetExchangeRates(ctx, &pb.ExchangeRatesRequest{})
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения курсов от gRPC сервиса: %w", err)
 	}
 
 	// Конвертируем protobuf в map
-	result := make(map[string]float64)
+	snapshot := &ratesSnapshot{
+		Rates:     make(map[string]float64),
+		Sources:   rates.Sources,
+		FetchedAt: time.Now().UTC(),
+	}
 	for k, v := range rates.Rates {
-		result[k] = float64(v)
+		snapshot.Rates[k] = float64(v)
 	}
+	// Точные курсы (double) заменяют округленные до float, если сервис обмена их передает
+	for k, v := range rates.PreciseRates {
+		snapshot.Rates[k] = v
+	}
+
+	metrics.SetExchangeRatesFetched(ctx, snapshot.FetchedAt)
 
 	// Сохраняем в кэш
-	ratesJSON, err := json.Marshal(result)
+	ratesJSON, err := json.Marshal(snapshot)
 	if err == nil {
-		s.redisClient.Set(ctx, cacheKey, ratesJSON, s.cacheDuration)
+		_ = s.cache.Set(ctx, ratesCacheKey, ratesJSON, s.cacheDuration)
+	}
+
+	return snapshot, nil
+}
+
+// Decimals возвращает количество знаков после запятой в суммах валюты по справочнику сервиса обмена
+// Справочник кэшируется на время жизни кэша курсов; если сервис обмена недоступен или валюты
+// нет в справочнике, используется defaultCurrencyDecimals
+// Параметры:
+//   - ctx: контекст выполнения
+//   - currency: код валюты
+//
+// Возвращает:
+//   - int: количество знаков после запятой
+func (s *ExchangeService) Decimals(ctx context.Context, currency string) int {
+	if s == nil {
+		return defaultCurrencyDecimals
+	}
+	decimals, err := s.currencyDecimals(ctx)
+	if err != nil {
+		// Сервис обмена прежней версии не публикует справочник - это не ошибка
+		if status.Code(errors.Unwrap(err)) != codes.Unimplemented {
+			log.Printf("Справочник валют недоступен, суммы %s округляются до %d знаков: %v", currency, defaultCurrencyDecimals, err)
+		}
+		return defaultCurrencyDecimals
+	}
+	if value, ok := decimals[currency]; ok {
+		return value
+	}
+	return defaultCurrencyDecimals
+}
+
+// currencyDecimals возвращает справочник знаков после запятой: из кэша, если он там есть, иначе от сервиса обмена
+func (s *ExchangeService) currencyDecimals(ctx context.Context) (map[string]int, error) {
+	if cached, err := s.cache.Get(ctx, currenciesCacheKey); err == nil {
+		var decimals map[string]int
+		if err := json.Unmarshal(cached, &decimals); err == nil && len(decimals) > 0 {
+			return decimals, nil
+		}
+	}
+
+	response, err := s.client.GetCurrencies(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения справочника валют от gRPC сервиса: %w", err)
+	}
+	decimals := make(map[string]int, len(response.Currencies))
+	for _, currency := range response.Currencies {
+		decimals[currency.Code] = int(currency.Decimals)
+	}
+	if data, err := json.Marshal(decimals); err == nil && len(decimals) > 0 {
+		_ = s.cache.Set(ctx, currenciesCacheKey, data, s.cacheDuration)
+	}
+	return decimals, nil
+}
+
+// ProbeRates проверяет получение курсов от сервиса обмена в обход кэша (кэш не изменяется)
+// Возвращает:
+//   - bool: сервис обмена сообщает, что часть курсов устарела
+//   - error: ошибка получения курсов (в том числе недоступность сервиса)
+func (s *ExchangeService) ProbeRates(ctx context.Context) (bool, error) {
+	rates, err := s.client.GetExchangeRates(ctx, &pb.ExchangeRatesRequest{})
+	if err != nil {
+		return false, err
+	}
+	return rates.Degraded, nil
+}
+
+// UpdateStatus запрашивает у сервиса обмена состояние фоновых обновлений курсов (не кэшируется)
+// Возвращает:
+//   - []models.ExchangeUpdateStatus: обновления в порядке имен
+//   - error: ошибка запроса (Unimplemented - сервис обмена не поддерживает запрос)
+func (s *ExchangeService) UpdateStatus(ctx context.Context) ([]models.ExchangeUpdateStatus, error) {
+	response, err := s.client.GetUpdateStatus(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	updates := make([]models.ExchangeUpdateStatus, 0, len(response.Updates))
+	for _, update := range response.Updates {
+		status := models.ExchangeUpdateStatus{
+			Name:       update.Name,
+			LastError:  update.LastError,
+			Source:     update.Source,
+			Currencies: int(update.Currencies),
+		}
+		if update.LastSuccess > 0 {
+			lastSuccess := time.Unix(update.LastSuccess, 0)
+			status.LastSuccess = &lastSuccess
+		}
+		updates = append(updates, status)
+	}
+	return updates, nil
+}
+
+// SnapshotRates возвращает курсы из снимка сервиса обмена на конец дня
+// Курсы не кэшируются: снимки прошлых дней запрашиваются редко (налоговая отчетность)
+// Параметры:
+//   - ctx: контекст выполнения
+//   - date: дата снимка (UTC)
+//
+// Возвращает:
+//   - map[string]float64: стоимость единицы валюты в базовой валюте сервиса обмена
+//   - error: ошибка получения (в том числе снимок на дату отсутствует)
+func (s *ExchangeService) SnapshotRates(ctx context.Context, date time.Time) (map[string]float64, error) {
+	if s == nil {
+		return nil, errors.New("сервис обмена не инициализирован")
 	}
 
-	return s.filterRates(result), nil
+	day := date.UTC().Format(time.DateOnly)
+	snapshot, err := s.client.GetDailySnapshot(ctx, &pb.DailySnapshotRequest{Date: day})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения снимка курсов на %s: %w", day, err)
+	}
+	return s.filterRates(snapshot.Rates), nil
 }
 
+// GetChart возвращает свечи курса пары валют за период до текущего момента
+// Графики кэшируются на время жизни кэша курсов (клиенты запрашивают одни и те же графики)
+// Параметры:
+//   - ctx: контекст выполнения
+//   - pair: пара валют в формате USD/RUB
+//   - interval: длительность свечи (например 15m, 1h, 1d)
+//   - period: период графика (например 24h, 7d, 30d)
+//
+// Возвращает:
+//   - *models.RateChart: свечи в порядке времени
+//   - error: ErrInvalidChart при некорректных параметрах, ErrNoRateHistory при отсутствии истории
+//     или ошибка сервиса обмена
+func (s *ExchangeService) GetChart(ctx context.Context, pair, interval, period string) (*models.RateChart, error) {
+	if s == nil {
+		return nil, errors.New("сервис обмена не инициализирован")
+	}
+
+	from, to, ok := strings.Cut(strings.ToUpper(pair), "/")
+	if !ok || !slices.Contains(supportedRateCurrencies, from) || !slices.Contains(supportedRateCurrencies, to) || from == to {
+		return nil, fmt.Errorf("%w: пара валют должна быть в формате USD/RUB (валюты USD, EUR, RUB)", ErrInvalidChart)
+	}
+	step, err := parseChartDuration(interval)
+	if err != nil || step < minChartInterval {
+		return nil, fmt.Errorf("%w: длительность свечи должна быть не меньше %s", ErrInvalidChart, minChartInterval)
+	}
+	length, err := parseChartDuration(period)
+	if err != nil || length < step {
+		return nil, fmt.Errorf("%w: период должен быть не меньше длительности свечи", ErrInvalidChart)
+	}
+	if length/step > maxChartCandles {
+		return nil, fmt.Errorf("%w: период не может содержать больше %d свечей", ErrInvalidChart, maxChartCandles)
+	}
+
+	chart := &models.RateChart{Pair: from + "/" + to, Interval: interval, Range: period}
+	key := "exchange:chart:" + chart.Pair + ":" + step.String() + ":" + length.String()
+	if cached, err := s.cache.Get(ctx, key); err == nil {
+		if err := json.Unmarshal(cached, &chart.Candles); err == nil {
+			return chart, nil
+		}
+	}
+
+	now := time.Now()
+	response, err := s.client.GetRateCandles(ctx, &pb.RateCandlesRequest{
+		FromCurrency:    from,
+		ToCurrency:      to,
+		From:            now.Add(-length).Unix(),
+		To:              now.Unix(),
+		IntervalSeconds: int64(step / time.Second),
+	})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.NotFound:
+		return nil, ErrNoRateHistory
+	case codes.InvalidArgument:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidChart, status.Convert(err).Message())
+	default:
+		return nil, fmt.Errorf("ошибка получения свечей курса %s: %w", chart.Pair, err)
+	}
+
+	chart.Candles = make([]models.RateCandle, 0, len(response.Candles))
+	for _, candle := range response.Candles {
+		chart.Candles = append(chart.Candles, models.RateCandle{
+			Time:  time.Unix(candle.Start, 0).UTC(),
+			Open:  candle.Open,
+			High:  candle.High,
+			Low:   candle.Low,
+			Close: candle.Close,
+		})
+	}
+
+	if candlesJSON, err := json.Marshal(chart.Candles); err == nil {
+		_ = s.cache.Set(ctx, key, candlesJSON, s.cacheDuration)
+	}
+	return chart, nil
+}
+
+// parseChartDuration разбирает длительность графика: формат time.ParseDuration или целое число дней (7d)
+func parseChartDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("некорректная длительность %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// supportedRateCurrencies - валюты, курсы которых использует кошелек
+var supportedRateCurrencies = []string{"USD", "EUR", "RUB"}
+
 // filterRates оставляет только поддерживаемые валюты (USD, EUR, RUB)
 func (s *ExchangeService) filterRates(rates map[string]float64) map[string]float64 {
 	res := make(map[string]float64)
-	for _, currency := range []string{"USD", "EUR", "RUB"} {
+	for _, currency := range supportedRateCurrencies {
 		if rate, ok := rates[currency]; ok {
 			res[currency] = rate
 		}
@@ -122,7 +393,8 @@ func (s *ExchangeService) filterRates(rates map[string]float64) map[string]float
 }
 
 // GetRate возвращает курс обмена между двумя валютами
-// Поддерживает конвертацию через RUB как базовую валюту
+// Курсы сервиса обмена - стоимость единицы валюты в его базовой валюте (BASE_CURRENCY),
+// поэтому кросс-курс не зависит от того, какая валюта базовая
 // Параметры:
 //   - from: исходная валюта
 //   - to: целевая валюта
@@ -131,51 +403,151 @@ func (s *ExchangeService) filterRates(rates map[string]float64) map[string]float
 //   - float64: курс обмена
 //   - error: ошибка при расчете
 func (s *ExchangeService) GetRate(ctx context.Context, from, to string) (float64, error) {
+	rate, _, err := s.GetRateWithProvenance(ctx, from, to)
+	return rate, err
+}
+
+// GetRateWithProvenance возвращает курс обмена между двумя валютами вместе с его происхождением:
+// источниками курсов валют пары и временем их получения от сервиса обмена (для аудита обменов)
+// Параметры:
+//   - from: исходная валюта
+//   - to: целевая валюта
+//
+// Возвращает:
+//   - float64: курс обмена
+//   - models.RateProvenance: источники и время получения курса (пусто для одинаковых валют)
+//   - error: ошибка при расчете
+func (s *ExchangeService) GetRateWithProvenance(ctx context.Context, from, to string) (float64, models.RateProvenance, error) {
 	// Если валюты одинаковые
 	if from == to {
-		return 1.0, nil
+		return 1.0, models.RateProvenance{}, nil
+	}
+	if s == nil {
+		return 0, models.RateProvenance{}, errors.New("сервис обмена не инициализирован")
 	}
 
-	rates, err := s.GetRates(ctx)
+	snapshot, err := s.snapshot(ctx)
 	if err != nil {
-		return 0, err
+		return 0, models.RateProvenance{}, err
 	}
 
-	// Проверка доступности базовых курсов
-	if rates["USD"] == 0 || rates["EUR"] == 0 {
-		return 0, errors.New("базовые курсы недоступны")
+	rates := s.filterRates(snapshot.Rates)
+	fromRate, toRate := rates[from], rates[to]
+	if fromRate == 0 || toRate == 0 {
+		if !slices.Contains(supportedRateCurrencies, from) || !slices.Contains(supportedRateCurrencies, to) {
+			return 0, models.RateProvenance{}, errors.New("неподдерживаемая валютная пара")
+		}
+		return 0, models.RateProvenance{}, errors.New("базовые курсы недоступны")
 	}
 
-	// Логика конвертации через RUB
-	switch {
-	// Прямые котировки с RUB
-	case from == "RUB" && to == "USD":
-		return 1 / rates["USD"], nil // 1 RUB = (1/USD_RATE) USD
-	case from == "USD" && to == "RUB":
-		return rates["USD"], nil // 1 USD = USD_RATE RUB
-	case from == "RUB" && to == "EUR":
-		return 1 / rates["EUR"], nil // 1 RUB = (1/EUR_RATE) EUR
-	case from == "EUR" && to == "RUB":
-		return rates["EUR"], nil // 1 EUR = EUR_RATE RUB
+	// Базовая валюта сервиса обмена не имеет источника; одинаковые источники указываются один раз
+	var sources []string
+	for _, currency := range []string{from, to} {
+		if source := snapshot.Sources[currency]; source != "" && !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	fetchedAt := snapshot.FetchedAt
+	provenance := models.RateProvenance{RateSource: strings.Join(sources, ","), RateTimestamp: &fetchedAt}
 
-	// Кросс-курсы через RUB
-	case from == "USD" && to == "EUR":
-		return (1 / rates["EUR"]) * rates["USD"], nil // USD->RUB->EUR
-	case from == "EUR" && to == "USD":
-		return (1 / rates["USD"]) * rates["EUR"], nil // EUR->RUB->USD
+	// 1 from = fromRate базовой валюты = fromRate / toRate единиц to
+	return fromRate / toRate, provenance, nil
+}
 
-	default:
-		return 0, errors.New("неподдерживаемая валютная пара")
+// Параметры переподключения к потоку событий об изменении курсов
+const (
+	rateWatchMinBackoff = time.Second      // Первая пауза после обрыва потока
+	rateWatchMaxBackoff = 30 * time.Second // Максимальная пауза
+)
+
+// WatchRateUpdates подписывается на события сервиса обмена об изменении курсов и сбрасывает кэш курсов,
+// не дожидаясь истечения его времени жизни. Каждый экземпляр кошелька подписывается сам, поэтому
+// сбрасывается и in-memory кэш. После обрыва поток возобновляется с последнего полученного события;
+// при первом подключении кэш сбрасывается, так как события до подписки неизвестны.
+// Работает до отмены контекста или до отказа сервиса обмена (хранилище без потока событий)
+func (s *ExchangeService) WatchRateUpdates(ctx context.Context) {
+	var lastID int64
+	backoff := rateWatchMinBackoff
+
+	for ctx.Err() == nil {
+		received, err := s.watchRateUpdates(ctx, &lastID)
+		if ctx.Err() != nil {
+			return
+		}
+		if status.Code(err) == codes.Unimplemented {
+			log.Printf("Поток изменений курсов не поддерживается сервисом обмена, кэш курсов сбрасывается по времени жизни")
+			return
+		}
+		if received {
+			backoff = rateWatchMinBackoff // Поток работал: переподключаемся без нарастающей паузы
+		}
+		log.Printf("Поток изменений курсов прерван, переподключение через %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, rateWatchMaxBackoff)
 	}
 }
 
-// Close освобождает ресурсы (gRPC и Redis соединения)
+// watchRateUpdates читает один поток событий до его обрыва
+// Возвращает признак получения хотя бы одного события и ошибку обрыва
+func (s *ExchangeService) watchRateUpdates(ctx context.Context, lastID *int64) (bool, error) {
+	stream, err := s.client.WatchRateUpdates(ctx, &pb.WatchRateUpdatesRequest{AfterId: *lastID})
+	if err != nil {
+		return false, err
+	}
+	if *lastID == 0 {
+		s.invalidateRates(ctx)
+	}
+
+	received := false
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+		received = true
+		*lastID = event.Id
+		log.Printf("Курсы валют изменены (%s), кэш курсов сброшен", strings.Join(event.Currencies, ", "))
+		s.invalidateRates(ctx)
+		s.notifyRateUpdate(ctx, event.Currencies, time.Unix(event.ChangedAt, 0))
+	}
+}
+
+// OnRateUpdate добавляет обработчик событий об изменении курсов из потока WatchRateUpdates
+// Обработчики вызываются последовательно в потоке событий: долгая обработка задерживает следующие события
+func (s *ExchangeService) OnRateUpdate(fn RateUpdateFunc) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// notifyRateUpdate передает событие об изменении курсов обработчикам
+func (s *ExchangeService) notifyRateUpdate(ctx context.Context, currencies []string, changedAt time.Time) {
+	s.listenersMu.Lock()
+	listeners := slices.Clone(s.listeners)
+	s.listenersMu.Unlock()
+
+	for _, fn := range listeners {
+		fn(ctx, currencies, changedAt)
+	}
+}
+
+// invalidateRates удаляет курсы из кэша: следующий запрос получит их от сервиса обмена
+func (s *ExchangeService) invalidateRates(ctx context.Context) {
+	if err := s.cache.Delete(ctx, ratesCacheKey); err != nil {
+		log.Printf("Ошибка сброса кэша курсов: %v", err)
+	}
+}
+
+// Close освобождает ресурсы (gRPC соединение)
+// Кэш передается извне и закрывается его владельцем
 func (s *ExchangeService) Close() error {
 	if s.conn != nil {
 		_ = s.conn.Close()
 	}
-	if s.redisClient != nil {
-		_ = s.redisClient.Close()
-	}
 	return nil
 }
@@ -0,0 +1,581 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tenant"
+	"slices"
+	"sync"
+	"time"
+)
+
+// fakeWallets - кошельки в памяти с проверкой средств, как в транзакции изменения баланса PostgreSQL
+// Методы storage.WalletRepository, не используемые тестами, не реализованы (вызов паникует)
+type fakeWallets struct {
+	storage.WalletRepository
+
+	mu        sync.Mutex
+	balances  map[int]*models.Balance
+	raceDebit map[string]float64     // Списание параллельной операцией сразу после чтения баланса (по валютам)
+	reversed  []models.ExchangeQuote // Сторнированные обмены
+}
+
+// newFakeWallets создает кошельки пользователей с начальными балансами
+func newFakeWallets(balances map[int]models.Balance) *fakeWallets {
+	w := &fakeWallets{balances: make(map[int]*models.Balance), raceDebit: make(map[string]float64)}
+	for userID, balance := range balances {
+		w.balances[userID] = &balance
+	}
+	return w
+}
+
+// balance возвращает копию баланса пользователя
+func (w *fakeWallets) balance(userID int) models.Balance {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return *w.balances[userID]
+}
+
+func (w *fakeWallets) GetBalance(_ context.Context, userID int) (*models.Balance, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	balance, ok := w.balances[userID]
+	if !ok {
+		return nil, storage.ErrWalletUnavailable
+	}
+	result := *balance
+	// Параллельная операция списывает средства после чтения баланса, до транзакции
+	for currency, amount := range w.raceDebit {
+		*currencyField(balance, currency) -= amount
+	}
+	clear(w.raceDebit)
+	return &result, nil
+}
+
+// GetCachedBalance читает баланс как GetBalance: кэша балансов у кошельков в памяти нет
+func (w *fakeWallets) GetCachedBalance(ctx context.Context, userID int) (*models.Balance, error) {
+	return w.GetBalance(ctx, userID)
+}
+
+func (w *fakeWallets) UpdateBalance(_ context.Context, userID int, currency string, amount float64) (*models.Balance, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.apply(userID, currency, amount); err != nil {
+		return nil, err
+	}
+	result := *w.balances[userID]
+	return &result, nil
+}
+
+func (w *fakeWallets) Transfer(
+	_ context.Context,
+	fromUserID int,
+	toUserID int,
+	currency string,
+	amount float64,
+) (*models.Balance, *models.Balance, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.apply(fromUserID, currency, -amount); err != nil {
+		return nil, nil, err
+	}
+	if err := w.apply(toUserID, currency, amount); err != nil {
+		w.apply(fromUserID, currency, amount)
+		return nil, nil, err
+	}
+	from, to := *w.balances[fromUserID], *w.balances[toUserID]
+	return &from, &to, nil
+}
+
+func (w *fakeWallets) Exchange(_ context.Context, quote *models.ExchangeQuote) (*models.Balance, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.apply(quote.UserID, quote.FromCurrency, -quote.Amount); err != nil {
+		return nil, err
+	}
+	if err := w.apply(quote.UserID, quote.ToCurrency, quote.ExchangedAmount); err != nil {
+		return nil, err
+	}
+	result := *w.balances[quote.UserID]
+	return &result, nil
+}
+
+func (w *fakeWallets) ReverseExchange(_ context.Context, quote *models.ExchangeQuote) (*models.Balance, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.apply(quote.UserID, quote.ToCurrency, -quote.ExchangedAmount); err != nil {
+		return nil, err
+	}
+	if err := w.apply(quote.UserID, quote.FromCurrency, quote.Amount); err != nil {
+		return nil, err
+	}
+	w.reversed = append(w.reversed, *quote)
+	result := *w.balances[quote.UserID]
+	return &result, nil
+}
+
+// apply изменяет баланс; списание больше баланса отклоняется, как UPDATE с проверкой средств в changeBalanceTx
+func (w *fakeWallets) apply(userID int, currency string, amount float64) error {
+	balance, ok := w.balances[userID]
+	if !ok {
+		return storage.ErrWalletUnavailable
+	}
+	field := currencyField(balance, currency)
+	if *field+amount < 0 {
+		return storage.ErrInsufficientFunds
+	}
+	*field += amount
+	return nil
+}
+
+// currencyField возвращает поле баланса в валюте
+func currencyField(balance *models.Balance, currency string) *float64 {
+	switch currency {
+	case "USD":
+		return &balance.USD
+	case "RUB":
+		return &balance.RUB
+	case "EUR":
+		return &balance.EUR
+	}
+	panic("неподдерживаемая валюта " + currency)
+}
+
+// fakeUsers - пользователи в памяти для поиска получателя перевода
+type fakeUsers struct {
+	storage.UserRepository
+
+	users []models.User
+}
+
+func (u *fakeUsers) GetUserByUsername(_ context.Context, tenantID, username string) (*models.User, error) {
+	for _, user := range u.users {
+		if user.TenantID == tenantID && user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (u *fakeUsers) GetUserByID(_ context.Context, id int) (*models.User, error) {
+	for _, user := range u.users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeRates - постоянные курсы пар "FROM/TO"
+type fakeRates map[string]float64
+
+func (r fakeRates) GetRate(_ context.Context, fromCurrency, toCurrency string) (float64, error) {
+	rate, ok := r[fromCurrency+"/"+toCurrency]
+	if !ok {
+		return 0, fmt.Errorf("курс %s/%s не задан", fromCurrency, toCurrency)
+	}
+	return rate, nil
+}
+
+func (r fakeRates) GetRateWithProvenance(ctx context.Context, fromCurrency, toCurrency string) (float64, models.RateProvenance, error) {
+	rate, err := r.GetRate(ctx, fromCurrency, toCurrency)
+	return rate, models.RateProvenance{RateSource: "test"}, err
+}
+
+// Пользователи тестов кошелька
+const (
+	testAlice = 1
+	testBob   = 2
+)
+
+// testRates - курсы тестов кошелька
+var testRates = fakeRates{"USD/EUR": 0.9, "EUR/USD": 1.1, "USD/RUB": 90, "RUB/USD": 0.011}
+
+// newTestWallet создает сервис кошелька с пользователями alice и bob арендатора по умолчанию
+// и начальными балансами; антифрод, комиссия, приостановки и окна исполнения выключены
+func newTestWallet(balances map[int]models.Balance) (*WalletService, *fakeWallets) {
+	wallets := newFakeWallets(balances)
+	users := &fakeUsers{users: []models.User{
+		{ID: testAlice, TenantID: tenant.DefaultID, Username: "alice", Email: "alice@example.com"},
+		{ID: testBob, TenantID: tenant.DefaultID, Username: "bob", Email: "bob@example.com"},
+	}}
+	return NewWalletService(wallets, users, testRates, nil, nil, nil, nil, nil, nil, nil), wallets
+}
+
+// fakeOperations - состояние многошаговых операций в памяти
+type fakeOperations struct {
+	mu         sync.Mutex
+	operations map[int64]*models.MoneyOperation
+}
+
+func newFakeOperations() *fakeOperations {
+	return &fakeOperations{operations: make(map[int64]*models.MoneyOperation)}
+}
+
+// get возвращает копию операции
+func (o *fakeOperations) get(id int64) models.MoneyOperation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return *o.operations[id]
+}
+
+func (o *fakeOperations) CreateOperation(_ context.Context, op *models.MoneyOperation) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	op.ID, op.Status = int64(len(o.operations)+1), models.OperationPending
+	stored := *op
+	o.operations[op.ID] = &stored
+	return nil
+}
+
+func (o *fakeOperations) SetOperationStep(_ context.Context, id int64, step int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.operations[id].Step = step
+	return nil
+}
+
+func (o *fakeOperations) SetOperationStatus(_ context.Context, id int64, status string, errText string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.operations[id].Status = status
+	if errText != "" {
+		o.operations[id].Error = errText
+	}
+	return nil
+}
+
+func (o *fakeOperations) ClaimStaleOperations(_ context.Context, _ time.Time, limit int) ([]models.MoneyOperation, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var claimed []models.MoneyOperation
+	for id := int64(1); id <= int64(len(o.operations)) && len(claimed) < limit; id++ {
+		op := o.operations[id]
+		if op.Status != models.OperationPending && op.Status != models.OperationCompensating {
+			continue
+		}
+		op.Status = models.OperationCompensating
+		op.Attempts++
+		claimed = append(claimed, *op)
+	}
+	return claimed, nil
+}
+
+// fakeFreezes - приостановки валют в памяти
+type fakeFreezes struct {
+	mu      sync.Mutex
+	freezes map[string]models.CurrencyFreeze
+	listErr error // Ошибка чтения приостановок
+}
+
+func newFakeFreezes() *fakeFreezes {
+	return &fakeFreezes{freezes: make(map[string]models.CurrencyFreeze)}
+}
+
+func (f *fakeFreezes) FreezeCurrency(_ context.Context, freeze *models.CurrencyFreeze) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	freeze.FrozenAt = time.Now()
+	f.freezes[freeze.Currency] = *freeze
+	return nil
+}
+
+func (f *fakeFreezes) UnfreezeCurrency(_ context.Context, currency string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.freezes[currency]
+	delete(f.freezes, currency)
+	return ok, nil
+}
+
+func (f *fakeFreezes) ListCurrencyFreezes(context.Context) ([]models.CurrencyFreeze, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	list := make([]models.CurrencyFreeze, 0, len(f.freezes))
+	for _, freeze := range f.freezes {
+		list = append(list, freeze)
+	}
+	return list, nil
+}
+
+// fakeQueue - очередь отложенных обменов в памяти
+type fakeQueue struct {
+	mu        sync.Mutex
+	exchanges []*models.QueuedExchange
+}
+
+// get возвращает копию обмена
+func (q *fakeQueue) get(id int64) models.QueuedExchange {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return *q.exchanges[id-1]
+}
+
+func (q *fakeQueue) CreateQueuedExchange(_ context.Context, exchange *models.QueuedExchange) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	exchange.ID, exchange.Status, exchange.CreatedAt = int64(len(q.exchanges)+1), models.QueuedExchangePending, time.Now()
+	stored := *exchange
+	q.exchanges = append(q.exchanges, &stored)
+	return nil
+}
+
+func (q *fakeQueue) CountPendingExchanges(_ context.Context, userID int) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	count := 0
+	for _, exchange := range q.exchanges {
+		if exchange.UserID == userID && exchange.Status == models.QueuedExchangePending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *fakeQueue) ListQueuedExchanges(_ context.Context, userID int, limit int) ([]models.QueuedExchange, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var list []models.QueuedExchange
+	for i := len(q.exchanges) - 1; i >= 0 && len(list) < limit; i-- {
+		if q.exchanges[i].UserID == userID {
+			list = append(list, *q.exchanges[i])
+		}
+	}
+	return list, nil
+}
+
+func (q *fakeQueue) ClaimQueuedExchanges(
+	_ context.Context,
+	trigger string,
+	currencies []string,
+	at time.Time,
+	limit int,
+) ([]models.QueuedExchange, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var claimed []models.QueuedExchange
+	for _, exchange := range q.exchanges {
+		if len(claimed) == limit {
+			break
+		}
+		if exchange.Status != models.QueuedExchangePending || exchange.Trigger != trigger || exchange.ExecuteAfter.After(at) {
+			continue
+		}
+		if currencies != nil && !slices.Contains(currencies, exchange.FromCurrency) && !slices.Contains(currencies, exchange.ToCurrency) {
+			continue
+		}
+		exchange.Status = models.QueuedExchangeExecuting
+		claimed = append(claimed, *exchange)
+	}
+	return claimed, nil
+}
+
+func (q *fakeQueue) CancelQueuedExchange(_ context.Context, userID int, id int64) (*models.QueuedExchange, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if id < 1 || id > int64(len(q.exchanges)) {
+		return nil, storage.ErrQueuedExchangeNotFound
+	}
+	exchange := q.exchanges[id-1]
+	if exchange.UserID != userID || exchange.Status != models.QueuedExchangePending {
+		return nil, storage.ErrQueuedExchangeNotFound
+	}
+	exchange.Status = models.QueuedExchangeCancelled
+	result := *exchange
+	return &result, nil
+}
+
+func (q *fakeQueue) CompleteQueuedExchange(_ context.Context, id int64, rate, exchangedAmount float64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	exchange := q.exchanges[id-1]
+	exchange.Status, exchange.Rate, exchange.ExchangedAmount = models.QueuedExchangeExecuted, &rate, &exchangedAmount
+	return nil
+}
+
+func (q *fakeQueue) FailQueuedExchange(_ context.Context, id int64, reason string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.exchanges[id-1].Status, q.exchanges[id-1].Error = models.QueuedExchangeFailed, reason
+	return nil
+}
+
+func (q *fakeQueue) RescheduleQueuedExchange(_ context.Context, id int64, at time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.exchanges[id-1].Status, q.exchanges[id-1].ExecuteAfter = models.QueuedExchangePending, at
+	return nil
+}
+
+func (q *fakeQueue) FailStaleQueuedExchanges(context.Context, time.Duration) (int64, error) {
+	return 0, nil
+}
+
+// fakeDCA - планы регулярных обменов в памяти
+type fakeDCA struct {
+	mu    sync.Mutex
+	plans []*models.DCAPlan
+	legs  map[int64][]*models.DCALeg
+}
+
+func newFakeDCA() *fakeDCA {
+	return &fakeDCA{legs: make(map[int64][]*models.DCALeg)}
+}
+
+// plan возвращает копию плана
+func (d *fakeDCA) plan(id int64) models.DCAPlan {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return *d.plans[id-1]
+}
+
+func (d *fakeDCA) CreateDCAPlan(_ context.Context, plan *models.DCAPlan) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	plan.ID, plan.Status, plan.CreatedAt = int64(len(d.plans)+1), models.DCAPlanActive, time.Now()
+	stored := *plan
+	d.plans = append(d.plans, &stored)
+	return nil
+}
+
+func (d *fakeDCA) CountActiveDCAPlans(_ context.Context, userID int) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	count := 0
+	for _, plan := range d.plans {
+		if plan.UserID == userID && plan.Status == models.DCAPlanActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (d *fakeDCA) ListDCAPlans(_ context.Context, userID int) ([]models.DCAPlan, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var list []models.DCAPlan
+	for i := len(d.plans) - 1; i >= 0; i-- {
+		if d.plans[i].UserID == userID {
+			list = append(list, *d.plans[i])
+		}
+	}
+	return list, nil
+}
+
+func (d *fakeDCA) GetDCAPlan(_ context.Context, userID int, id int64) (*models.DCAPlan, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if id < 1 || id > int64(len(d.plans)) || d.plans[id-1].UserID != userID {
+		return nil, storage.ErrDCAPlanNotFound
+	}
+	plan := *d.plans[id-1]
+	return &plan, nil
+}
+
+func (d *fakeDCA) ListDCALegs(_ context.Context, planID int64) ([]models.DCALeg, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	legs := make([]models.DCALeg, 0, len(d.legs[planID]))
+	for _, leg := range d.legs[planID] {
+		legs = append(legs, *leg)
+	}
+	return legs, nil
+}
+
+func (d *fakeDCA) CancelDCAPlan(_ context.Context, userID int, id int64) (*models.DCAPlan, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if id < 1 || id > int64(len(d.plans)) || d.plans[id-1].UserID != userID || d.plans[id-1].Status != models.DCAPlanActive {
+		return nil, storage.ErrDCAPlanNotFound
+	}
+	plan := d.plans[id-1]
+	now := time.Now()
+	plan.Status, plan.NextRunAt, plan.FinishedAt = models.DCAPlanCancelled, nil, &now
+	result := *plan
+	return &result, nil
+}
+
+func (d *fakeDCA) ClaimDueDCAPlans(_ context.Context, _ time.Duration, limit int) ([]models.DCAPlan, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var claimed []models.DCAPlan
+	for _, plan := range d.plans {
+		if len(claimed) < limit && plan.Status == models.DCAPlanActive && !plan.NextRunAt.After(time.Now()) {
+			claimed = append(claimed, *plan)
+		}
+	}
+	return claimed, nil
+}
+
+func (d *fakeDCA) StartDCALeg(_ context.Context, leg *models.DCALeg) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, started := range d.legs[leg.PlanID] {
+		if started.Number == leg.Number {
+			return false, nil
+		}
+	}
+	stored := *leg
+	d.legs[leg.PlanID] = append(d.legs[leg.PlanID], &stored)
+	return true, nil
+}
+
+func (d *fakeDCA) FinishDCALeg(_ context.Context, leg *models.DCALeg, nextRunAt *time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, stored := range d.legs[leg.PlanID] {
+		if stored.Number == leg.Number {
+			*stored = *leg
+		}
+	}
+	plan := d.plans[leg.PlanID-1]
+	switch leg.Status {
+	case models.DCALegExecuted:
+		plan.Executed++
+	case models.DCALegSkipped:
+		plan.Skipped++
+	default:
+		plan.Failed++
+	}
+	plan.NextRunAt = nextRunAt
+	if nextRunAt == nil {
+		now := time.Now()
+		plan.Status, plan.FinishedAt = models.DCAPlanCompleted, &now
+	}
+	return nil
+}
+
+// due переносит следующий обмен плана на текущее время
+func (d *fakeDCA) due(id int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now().Add(-time.Second)
+	d.plans[id-1].NextRunAt = &now
+}
+
+// fakeLedger - журнал операций в памяти
+// Методы storage.TransactionRepository, не используемые тестами, не реализованы (вызов паникует)
+type fakeLedger struct {
+	storage.TransactionRepository
+
+	entries []models.Transaction
+}
+
+func (l *fakeLedger) GetOperationEntries(_ context.Context, transactionID int64) ([]models.Transaction, error) {
+	i := slices.IndexFunc(l.entries, func(entry models.Transaction) bool { return entry.ID == transactionID })
+	if i < 0 {
+		return nil, storage.ErrTransactionNotFound
+	}
+	var entries []models.Transaction
+	for _, entry := range l.entries {
+		if entry.OperationID == l.entries[i].OperationID && entry.UserID == l.entries[i].UserID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
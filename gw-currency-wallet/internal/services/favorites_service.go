@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/storage"
+	"slices"
+	"strings"
+)
+
+// maxFavorites - максимальное количество избранных валют чата
+const maxFavorites = 20
+
+// ErrInvalidFavorites возвращается для некорректного списка избранных валют
+var ErrInvalidFavorites = errors.New("некорректный список валют")
+
+// FavoritesService управляет избранными валютами чатов Telegram бота (состав и порядок вывода /rates)
+type FavoritesService struct {
+	favorites storage.FavoritesRepository // Хранилище избранных валют
+}
+
+// NewFavoritesService создает сервис избранных валют
+func NewFavoritesService(favorites storage.FavoritesRepository) *FavoritesService {
+	return &FavoritesService{favorites: favorites}
+}
+
+// Get возвращает избранные валюты чата в порядке вывода (nil - не настроены)
+func (s *FavoritesService) Get(ctx context.Context, chatID int64) ([]string, error) {
+	return s.favorites.GetFavorites(ctx, chatID)
+}
+
+// Set сохраняет избранные валюты чата
+// Коды приводятся к верхнему регистру, повторы отбрасываются; порядок сохраняется
+// Параметры:
+//   - ctx: контекст выполнения
+//   - chatID: чат
+//   - currencies: коды валют в порядке вывода (пусто - сброс к выводу всех валют)
+//
+// Возвращает:
+//   - []string: сохраненный список
+//   - error: ErrInvalidFavorites или ошибка хранилища
+func (s *FavoritesService) Set(ctx context.Context, chatID int64, currencies []string) ([]string, error) {
+	var normalized []string
+	for _, currency := range currencies {
+		currency = strings.ToUpper(currency)
+		if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+			return nil, fmt.Errorf("%w: %q не код валюты", ErrInvalidFavorites, currency)
+		}
+		if !slices.Contains(normalized, currency) {
+			normalized = append(normalized, currency)
+		}
+	}
+	if len(normalized) > maxFavorites {
+		return nil, fmt.Errorf("%w: не больше %d валют", ErrInvalidFavorites, maxFavorites)
+	}
+
+	if err := s.favorites.SaveFavorites(ctx, chatID, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
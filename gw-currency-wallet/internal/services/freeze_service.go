@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ошибки приостановки операций в валютах
+var (
+	ErrCurrencyFrozen    = errors.New("валюта временно недоступна")
+	ErrCurrencyNotFrozen = errors.New("операции в валюте не приостановлены")
+	ErrInvalidFreeze     = errors.New("некорректная приостановка валюты")
+)
+
+// maxFreezeReason - максимальная длина причины приостановки
+const maxFreezeReason = 256
+
+// CurrencyFreezeService приостанавливает операции в отдельных валютах по решению администратора
+// Приостановки хранятся в БД и действуют на всех экземплярах; каждый экземпляр держит их копию в памяти
+// и перечитывает не реже refresh, поэтому на других экземплярах приостановка вступает в силу с этой задержкой
+type CurrencyFreezeService struct {
+	repo    storage.CurrencyFreezeRepository // Приостановки валют
+	refresh time.Duration                    // Период обновления копии
+
+	mu       sync.Mutex
+	freezes  map[string]models.CurrencyFreeze // Копия приостановок по валютам
+	loadedAt time.Time                        // Время чтения копии
+}
+
+// NewCurrencyFreezeService создает сервис приостановки операций в валютах
+// Параметры:
+//   - repo: хранилище приостановок
+//   - refresh: период обновления копии приостановок экземпляра (<= 0 - чтение из БД при каждой проверке)
+//
+// Возвращает:
+//   - *CurrencyFreezeService: инициализированный сервис
+func NewCurrencyFreezeService(repo storage.CurrencyFreezeRepository, refresh time.Duration) *CurrencyFreezeService {
+	return &CurrencyFreezeService{repo: repo, refresh: refresh}
+}
+
+// Check проверяет, что операции в валютах не приостановлены
+// Если приостановки не удалось перечитать, используется последняя прочитанная копия
+// Параметры:
+//   - ctx: контекст выполнения
+//   - currencies: валюты операции
+//
+// Возвращает:
+//   - error: ErrCurrencyFrozen с валютой и причиной
+func (s *CurrencyFreezeService) Check(ctx context.Context, currencies ...string) error {
+	if s == nil {
+		return nil
+	}
+	freezes := s.snapshot(ctx)
+	for _, currency := range currencies {
+		freeze, ok := freezes[currency]
+		if !ok {
+			continue
+		}
+		if freeze.Reason != "" {
+			return fmt.Errorf("%w: операции в %s приостановлены (%s)", ErrCurrencyFrozen, currency, freeze.Reason)
+		}
+		return fmt.Errorf("%w: операции в %s приостановлены", ErrCurrencyFrozen, currency)
+	}
+	return nil
+}
+
+// List возвращает действующие приостановки
+func (s *CurrencyFreezeService) List(ctx context.Context) ([]models.CurrencyFreeze, error) {
+	return s.repo.ListCurrencyFreezes(ctx)
+}
+
+// Freeze приостанавливает операции в валюте
+// Параметры:
+//   - ctx: контекст выполнения
+//   - adminID: администратор
+//   - currency: валюта
+//   - reason: причина (показывается пользователям в ошибке операции)
+//
+// Возвращает:
+//   - *models.CurrencyFreeze: действующая приостановка
+//   - error: ErrInvalidFreeze или ошибка хранилища
+func (s *CurrencyFreezeService) Freeze(ctx context.Context, adminID int, currency, reason string) (*models.CurrencyFreeze, error) {
+	currency = strings.ToUpper(currency)
+	if !isValidCurrency(currency) {
+		return nil, fmt.Errorf("%w: неподдерживаемая валюта %s", ErrInvalidFreeze, currency)
+	}
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > maxFreezeReason {
+		return nil, fmt.Errorf("%w: причина длиннее %d символов", ErrInvalidFreeze, maxFreezeReason)
+	}
+
+	freeze := &models.CurrencyFreeze{Currency: currency, Reason: reason, FrozenBy: adminID}
+	if err := s.repo.FreezeCurrency(ctx, freeze); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	log.Printf("Операции в %s приостановлены администратором %d: %s", currency, adminID, reason)
+	return freeze, nil
+}
+
+// Unfreeze возобновляет операции в валюте
+// Возвращает:
+//   - error: ErrCurrencyNotFrozen или ошибка хранилища
+func (s *CurrencyFreezeService) Unfreeze(ctx context.Context, adminID int, currency string) error {
+	currency = strings.ToUpper(currency)
+	removed, err := s.repo.UnfreezeCurrency(ctx, currency)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrCurrencyNotFrozen
+	}
+	s.invalidate()
+	log.Printf("Операции в %s возобновлены администратором %d", currency, adminID)
+	return nil
+}
+
+// snapshot возвращает копию приостановок, перечитывая ее по истечении периода обновления
+func (s *CurrencyFreezeService) snapshot(ctx context.Context) map[string]models.CurrencyFreeze {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.freezes != nil && time.Since(s.loadedAt) < s.refresh {
+		return s.freezes
+	}
+	list, err := s.repo.ListCurrencyFreezes(ctx)
+	if err != nil {
+		log.Printf("Ошибка чтения приостановок валют, используется прежний список: %v", err)
+		return s.freezes
+	}
+	freezes := make(map[string]models.CurrencyFreeze, len(list))
+	for _, freeze := range list {
+		freezes[freeze.Currency] = freeze
+	}
+	s.freezes, s.loadedAt = freezes, time.Now()
+	return freezes
+}
+
+// invalidate помечает копию приостановок устаревшей: изменение вступает в силу на экземпляре сразу
+func (s *CurrencyFreezeService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCurrencyFreezeRejectsOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      func(ctx context.Context, s *WalletService) error
+		wantErr error
+	}{
+		{
+			name: "пополнение в приостановленной валюте",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Deposit(ctx, testAlice, "USD", 10)
+				return err
+			},
+			wantErr: ErrCurrencyFrozen,
+		},
+		{
+			name: "снятие в приостановленной валюте",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Withdraw(ctx, testAlice, "USD", 10)
+				return err
+			},
+			wantErr: ErrCurrencyFrozen,
+		},
+		{
+			name: "перевод в приостановленной валюте",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Transfer(ctx, testAlice, "bob", "USD", 10)
+				return err
+			},
+			wantErr: ErrCurrencyFrozen,
+		},
+		{
+			name: "обмен из приостановленной валюты",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Exchange(ctx, testAlice, "USD", "EUR", 10)
+				return err
+			},
+			wantErr: ErrCurrencyFrozen,
+		},
+		{
+			name: "обмен в приостановленную валюту",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Exchange(ctx, testAlice, "EUR", "USD", 10)
+				return err
+			},
+			wantErr: ErrCurrencyFrozen,
+		},
+		{
+			name: "снятие в другой валюте",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Withdraw(ctx, testAlice, "EUR", 10)
+				return err
+			},
+		},
+		{
+			name: "перевод в другой валюте",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Transfer(ctx, testAlice, "bob", "EUR", 10)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, wallets := newTestWallet(map[int]models.Balance{testAlice: {USD: 100, EUR: 100}, testBob: {}})
+			s.freezes = NewCurrencyFreezeService(newFakeFreezes(), time.Hour)
+			if _, err := s.freezes.Freeze(ctx, 99, "usd", "сбой платежного шлюза"); err != nil {
+				t.Fatal(err)
+			}
+
+			err := tt.op(ctx, s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+			if !strings.Contains(err.Error(), "сбой платежного шлюза") {
+				t.Errorf("в ошибке %q нет причины приостановки", err)
+			}
+			if got := wallets.balance(testAlice); got != (models.Balance{USD: 100, EUR: 100}) {
+				t.Errorf("баланс изменился: %+v", got)
+			}
+		})
+	}
+}
+
+func TestCurrencyFreezeService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("возобновление операций", func(t *testing.T) {
+		s := NewCurrencyFreezeService(newFakeFreezes(), time.Hour)
+		if _, err := s.Freeze(ctx, 99, "RUB", ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Check(ctx, "USD", "RUB"); !errors.Is(err, ErrCurrencyFrozen) {
+			t.Fatalf("ошибка %v, ожидалась %v", err, ErrCurrencyFrozen)
+		}
+		if err := s.Unfreeze(ctx, 99, "rub"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Check(ctx, "USD", "RUB"); err != nil {
+			t.Errorf("операции не возобновлены: %v", err)
+		}
+		if err := s.Unfreeze(ctx, 99, "RUB"); !errors.Is(err, ErrCurrencyNotFrozen) {
+			t.Errorf("ошибка %v, ожидалась %v", err, ErrCurrencyNotFrozen)
+		}
+	})
+
+	t.Run("некорректная приостановка", func(t *testing.T) {
+		s := NewCurrencyFreezeService(newFakeFreezes(), time.Hour)
+		for _, tt := range []struct{ currency, reason string }{
+			{"GBP", ""},
+			{"USD", strings.Repeat("я", maxFreezeReason+1)},
+		} {
+			if _, err := s.Freeze(ctx, 99, tt.currency, tt.reason); !errors.Is(err, ErrInvalidFreeze) {
+				t.Errorf("%s: ошибка %v, ожидалась %v", tt.currency, err, ErrInvalidFreeze)
+			}
+		}
+	})
+
+	t.Run("приостановка на другом экземпляре", func(t *testing.T) {
+		repo := newFakeFreezes()
+		admin := NewCurrencyFreezeService(repo, time.Hour)
+		cached := NewCurrencyFreezeService(repo, time.Hour)
+		uncached := NewCurrencyFreezeService(repo, 0)
+		cached.Check(ctx, "EUR") // Копия прочитана до приостановки
+
+		if _, err := admin.Freeze(ctx, 99, "EUR", ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := cached.Check(ctx, "EUR"); err != nil {
+			t.Errorf("копия экземпляра обновлена раньше периода обновления: %v", err)
+		}
+		if err := uncached.Check(ctx, "EUR"); !errors.Is(err, ErrCurrencyFrozen) {
+			t.Errorf("ошибка %v, ожидалась %v", err, ErrCurrencyFrozen)
+		}
+	})
+
+	t.Run("ошибка чтения приостановок", func(t *testing.T) {
+		repo := newFakeFreezes()
+		s := NewCurrencyFreezeService(repo, 0)
+		if _, err := s.Freeze(ctx, 99, "EUR", ""); err != nil {
+			t.Fatal(err)
+		}
+		s.Check(ctx, "EUR")
+
+		repo.listErr = errors.New("база недоступна")
+		if err := s.Check(ctx, "EUR"); !errors.Is(err, ErrCurrencyFrozen) {
+			t.Errorf("без прежней копии приостановок: %v", err)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"gw-currency-wallet/internal/middleware"
+)
+
+// tokenCredentials передает API токен клиента в метаданных каждого gRPC вызова
+// Сервис обмена валют определяет по токену клиента (wallet, bot) и применяет его квоты
+type tokenCredentials struct {
+	token string // API токен клиента
+}
+
+// GetRequestMetadata добавляет заголовок authorization к запросу
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+// RequireTransportSecurity разрешает передачу токена без TLS (соединение внутри сети сервисов)
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// WithAPIToken возвращает опцию подключения, добавляющую API токен ко всем вызовам
+// Если токен пустой, возвращается опция без эффекта (аутентификация на стороне сервера отключена)
+func WithAPIToken(token string) grpc.DialOption {
+	if token == "" {
+		return grpc.EmptyDialOption{}
+	}
+	return grpc.WithPerRPCCredentials(tokenCredentials{token: token})
+}
+
+// traceIDMetadataKey - ключ метаданных с идентификатором запроса (сервис обмена пишет его в журнал и возвращает)
+const traceIDMetadataKey = "x-request-id"
+
+// traceIDInterceptor передает идентификатор HTTP запроса (X-Request-ID) в метаданных gRPC вызова,
+// чтобы записи журналов кошелька и сервиса обмена можно было сопоставить
+func traceIDInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := middleware.TraceIDFromContext(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, traceIDMetadataKey, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// WithTraceID возвращает опцию подключения, передающую идентификатор запроса во все вызовы
+func WithTraceID() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(traceIDInterceptor)
+}
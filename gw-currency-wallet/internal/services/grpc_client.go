@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"strings"
+	"time"
+)
+
+// exchangeResolverScheme - схема статического списка адресов сервиса обмена
+const exchangeResolverScheme = "exchanger"
+
+// roundRobinServiceConfig распределяет вызовы по всем доступным адресам сервиса обмена
+// (по умолчанию gRPC использует только первый доступный адрес)
+const roundRobinServiceConfig = `{"loadBalancingConfig": [{"round_robin": {}}]}`
+
+// ClientConfig содержит параметры gRPC соединения с сервисом обмена валют
+type ClientConfig struct {
+	KeepaliveTime                time.Duration // Период ping простаивающего соединения (0 - без ping)
+	KeepaliveTimeout             time.Duration // Ожидание ответа на ping до переподключения
+	KeepalivePermitWithoutStream bool          // Отправлять ping без активных вызовов
+	WaitForReady                 bool          // Ожидать подключения вместо немедленной ошибки (в пределах таймаута вызова)
+}
+
+// ClientOptions возвращает параметры соединения с сервисом обмена:
+// keepalive, ожидание готовности и балансировку round robin
+// Параметры:
+//   - cfg: параметры соединения
+//
+// Возвращает:
+//   - []grpc.DialOption: параметры для NewExchangeConn
+func ClientOptions(cfg ClientConfig) []grpc.DialOption {
+	opts := []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(roundRobinServiceConfig),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(cfg.WaitForReady)),
+	}
+	if cfg.KeepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.KeepaliveTime,
+			Timeout:             cfg.KeepaliveTimeout,
+			PermitWithoutStream: cfg.KeepalivePermitWithoutStream,
+		}))
+	}
+	return opts
+}
+
+// NewExchangeConn создает gRPC соединение с сервисом обмена валют
+// Несколько адресов через запятую ("exchanger-1:50051,exchanger-2:50051") объединяются в статический список,
+// по которому балансируются вызовы; один адрес разрешается через DNS (все записи имени)
+// Параметры:
+//   - addr: адрес или список адресов сервиса обмена через запятую
+//   - opts: дополнительные параметры соединения (токен, перехватчики, ClientOptions)
+//
+// Возвращает:
+//   - *grpc.ClientConn: соединение (подключение выполняется при первом вызове)
+//   - error: ошибка при создании
+func NewExchangeConn(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	addrs := SplitAddrs(addr)
+	if len(addrs) == 0 {
+		return nil, errors.New("адрес сервиса обмена не может быть пустым")
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			MinConnectTimeout: 5 * time.Second, // Минимальное время попытки подключения
+		}),
+	}
+	target := addrs[0]
+	if len(addrs) > 1 {
+		r := manual.NewBuilderWithScheme(exchangeResolverScheme)
+		state := resolver.State{}
+		for _, a := range addrs {
+			state.Addresses = append(state.Addresses, resolver.Address{Addr: a})
+		}
+		r.InitialState(state)
+		dialOpts = append(dialOpts, grpc.WithResolvers(r))
+		target = exchangeResolverScheme + ":///" + strings.Join(addrs, ",")
+	}
+
+	conn, err := grpc.NewClient(target, append(dialOpts, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания gRPC клиента: %w", err)
+	}
+	return conn, nil
+}
+
+// SplitAddrs разбирает список адресов через запятую, пропуская пустые элементы
+func SplitAddrs(addr string) []string {
+	var addrs []string
+	for _, a := range strings.Split(addr, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/xlsx"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Форматы выгрузки истории операций
+const (
+	ExportFormatCSV   = "csv"   // CSV с заголовком
+	ExportFormatXLSX  = "xlsx"  // Книга Excel с оформлением и итогами по валютам
+	ExportFormatJSONL = "jsonl" // JSON Lines: запись журнала на строку, для программной обработки
+)
+
+// ErrInvalidExport возвращается для неизвестного формата или некорректного периода выгрузки
+var ErrInvalidExport = errors.New("некорректные параметры выгрузки")
+
+// ExportFormat описывает ответ с выгрузкой в одном из форматов
+type ExportFormat struct {
+	ContentType string // Тип содержимого ответа
+	Extension   string // Расширение файла
+}
+
+// exportFormats - поддерживаемые форматы выгрузки истории операций
+var exportFormats = map[string]ExportFormat{
+	ExportFormatCSV:   {ContentType: "text/csv; charset=utf-8", Extension: "csv"},
+	ExportFormatXLSX:  {ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Extension: "xlsx"},
+	ExportFormatJSONL: {ContentType: "application/x-ndjson", Extension: "jsonl"},
+}
+
+// LookupExportFormat возвращает описание формата выгрузки ("" - CSV)
+func LookupExportFormat(name string) (ExportFormat, error) {
+	if name == "" {
+		name = ExportFormatCSV
+	}
+	format, ok := exportFormats[name]
+	if !ok {
+		return ExportFormat{}, fmt.Errorf("%w: формат должен быть csv, xlsx или jsonl", ErrInvalidExport)
+	}
+	return format, nil
+}
+
+// transactionExportHeader - заголовок CSV выгрузки истории операций
+var transactionExportHeader = []string{
+	"id",
+	"created_at_utc",
+	"operation_id",
+	"type",
+	"currency",
+	"amount",
+	"rate",
+	"counterparty_id",
+}
+
+// transactionSheetHeader - заголовок листа XLSX; столбцы совпадают с CSV
+var transactionSheetHeader = []string{
+	"ID записи", "Время (UTC)", "Операция", "Тип", "Валюта", "Сумма", "Курс", "Контрагент",
+}
+
+// transactionSheetWidths - ширина столбцов листа XLSX в символах
+var transactionSheetWidths = []float64{12, 20, 38, 16, 10, 16, 12, 12}
+
+// Столбцы листа XLSX, участвующие в итогах
+const (
+	sheetCurrencyColumn = 4
+	sheetAmountColumn   = 5
+)
+
+// ExportTransactions выгружает операции пользователя за период в формате format
+// Ограничения периода совпадают с GetHistory (по умолчанию последние 30 дней, не более 366 дней), количество
+// записей не ограничено. Записи идут от старых к новым, время - в UTC. Период проверяется до записи в w
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - from, to: границы периода (нулевые значения - последние 30 дней)
+//   - format: формат выгрузки (ExportFormat*, "" - CSV)
+//   - w: получатель выгрузки
+//
+// Возвращает:
+//   - error: ErrInvalidExport (формат или период), ошибка журнала или записи
+func (s *HistoryService) ExportTransactions(
+	ctx context.Context,
+	userID int,
+	from time.Time,
+	to time.Time,
+	format string,
+	w io.Writer,
+) error {
+	if _, err := LookupExportFormat(format); err != nil {
+		return err
+	}
+	from, to, _, err := historyQuery(userID, from, to, 0)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+
+	switch format {
+	case ExportFormatXLSX:
+		return s.exportXLSX(ctx, userID, from, to, w)
+	case ExportFormatJSONL:
+		encoder := json.NewEncoder(w)
+		return s.repo.ExportTransactions(ctx, userID, from, to, func(t models.Transaction) error {
+			return encoder.Encode(t)
+		})
+	default:
+		return s.exportCSV(ctx, userID, from, to, w)
+	}
+}
+
+// exportCSV выгружает операции в CSV
+func (s *HistoryService) exportCSV(ctx context.Context, userID int, from, to time.Time, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(transactionExportHeader); err != nil {
+		return err
+	}
+
+	err := s.repo.ExportTransactions(ctx, userID, from, to, func(t models.Transaction) error {
+		rate, counterparty := "", ""
+		if t.Rate != nil {
+			rate = strconv.FormatFloat(*t.Rate, 'f', -1, 64)
+		}
+		if t.CounterpartyID != nil {
+			counterparty = strconv.Itoa(*t.CounterpartyID)
+		}
+		return writer.Write([]string{
+			strconv.FormatInt(t.ID, 10),
+			t.CreatedAt.UTC().Format(time.RFC3339),
+			t.OperationID,
+			t.Type,
+			t.Currency,
+			strconv.FormatFloat(t.Amount, 'f', 2, 64),
+			rate,
+			counterparty,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportXLSX выгружает операции в книгу Excel: заголовок закреплен и с фильтром, суммы и время оформлены
+// числовыми форматами, под записями - строки итогов по каждой валюте (формула SUMIF с вычисленным значением)
+func (s *HistoryService) exportXLSX(ctx context.Context, userID int, from, to time.Time, w io.Writer) error {
+	sheet, err := xlsx.NewWriter(w, "Операции", transactionSheetWidths, true)
+	if err != nil {
+		return err
+	}
+	header := make([]xlsx.Cell, len(transactionSheetHeader))
+	for i, title := range transactionSheetHeader {
+		header[i] = xlsx.Cell{Value: title, Style: xlsx.StyleHeader}
+	}
+	if err := sheet.WriteRow(header...); err != nil {
+		return err
+	}
+
+	totals := make(map[string]float64)
+	err = s.repo.ExportTransactions(ctx, userID, from, to, func(t models.Transaction) error {
+		totals[t.Currency] += t.Amount
+		row := []xlsx.Cell{
+			{Value: t.ID},
+			{Value: t.CreatedAt.UTC(), Style: xlsx.StyleDateTime},
+			{Value: t.OperationID},
+			{Value: t.Type},
+			{Value: t.Currency},
+			{Value: t.Amount, Style: xlsx.StyleAmount},
+			{},
+			{},
+		}
+		if t.Rate != nil {
+			row[6] = xlsx.Cell{Value: *t.Rate, Style: xlsx.StyleRate}
+		}
+		if t.CounterpartyID != nil {
+			row[7] = xlsx.Cell{Value: *t.CounterpartyID}
+		}
+		return sheet.WriteRow(row...)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Итоги - изменение баланса каждой валюты за период
+	last := sheet.Rows()
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+	for _, currency := range currencies {
+		total := roundAmount(totals[currency], defaultCurrencyDecimals)
+		formula := fmt.Sprintf(`SUMIF($%[1]s$2:$%[1]s$%[3]d,"%[4]s",$%[2]s$2:$%[2]s$%[3]d)`,
+			xlsx.ColumnName(sheetCurrencyColumn), xlsx.ColumnName(sheetAmountColumn), last, currency)
+		row := make([]xlsx.Cell, len(transactionSheetHeader))
+		for i := range row {
+			row[i] = xlsx.Cell{Style: xlsx.StyleTotal}
+		}
+		row[0].Value = "Итого"
+		row[sheetCurrencyColumn].Value = currency
+		row[sheetAmountColumn] = xlsx.Cell{Value: total, Style: xlsx.StyleTotalAmount, Formula: formula}
+		if err := sheet.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+
+	return sheet.Close("A1:" + xlsx.CellName(len(transactionSheetHeader)-1, last))
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"time"
+)
+
+// Ограничения запросов истории операций
+const (
+	defaultHistoryPeriod = 30 * 24 * time.Hour  // Период по умолчанию, если границы не заданы
+	maxHistoryPeriod     = 366 * 24 * time.Hour // Максимальный период одного запроса
+	defaultHistoryLimit  = 50                   // Количество записей по умолчанию
+	maxHistoryLimit      = 500                  // Максимальное количество записей
+)
+
+// HistoryService предоставляет историю операций и обслуживает секционированный журнал
+type HistoryService struct {
+	repo            storage.TransactionRepository // Репозиторий журнала операций
+	retentionMonths int                           // Сколько месяцев записи хранятся в журнале до архивации
+	partitionsAhead int                           // На сколько месяцев вперед создаются секции
+}
+
+// NewHistoryService создает сервис истории операций
+// Параметры:
+//   - repo: репозиторий журнала операций
+//   - retentionMonths: количество полных месяцев, которые остаются в журнале (0 - архивация отключена)
+//   - partitionsAhead: количество будущих месяцев, для которых заранее создаются секции
+//
+// Возвращает:
+//   - *HistoryService: инициализированный сервис
+func NewHistoryService(repo storage.TransactionRepository, retentionMonths, partitionsAhead int) *HistoryService {
+	return &HistoryService{
+		repo:            repo,
+		retentionMonths: retentionMonths,
+		partitionsAhead: partitionsAhead,
+	}
+}
+
+// GetHistory возвращает операции пользователя за период
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - from, to: границы периода (нулевые значения - последние 30 дней)
+//   - limit: максимальное количество записей (0 - значение по умолчанию)
+//
+// Возвращает:
+//   - *models.TransactionHistoryResponse: записи за период
+//   - error: ошибка валидации или получения данных
+func (s *HistoryService) GetHistory(
+	ctx context.Context,
+	userID int,
+	from time.Time,
+	to time.Time,
+	limit int,
+) (*models.TransactionHistoryResponse, error) {
+	from, to, limit, err := historyQuery(userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.repo.ListTransactions(ctx, userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TransactionHistoryResponse{
+		From:         from,
+		To:           to,
+		Transactions: transactions,
+	}, nil
+}
+
+// GetExchangeHistory возвращает обмены пользователя за период с примененным курсом и комиссией
+// Параметры и ограничения периода совпадают с GetHistory
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - from, to: границы периода (нулевые значения - последние 30 дней)
+//   - limit: максимальное количество обменов (0 - значение по умолчанию)
+//
+// Возвращает:
+//   - *models.ExchangeHistoryResponse: обмены за период
+//   - error: ошибка валидации или получения данных
+func (s *HistoryService) GetExchangeHistory(
+	ctx context.Context,
+	userID int,
+	from time.Time,
+	to time.Time,
+	limit int,
+) (*models.ExchangeHistoryResponse, error) {
+	from, to, limit, err := historyQuery(userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	exchanges, err := s.repo.ListExchanges(ctx, userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExchangeHistoryResponse{
+		From:      from,
+		To:        to,
+		Exchanges: exchanges,
+	}, nil
+}
+
+// historyQuery проверяет параметры запроса истории и подставляет значения по умолчанию
+// Период всегда ограничен, чтобы запрос затрагивал только секции нужных месяцев
+func historyQuery(userID int, from, to time.Time, limit int) (time.Time, time.Time, int, error) {
+	if userID <= 0 {
+		return from, to, limit, errors.New("неверный ID пользователя")
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultHistoryPeriod)
+	}
+	if !from.Before(to) {
+		return from, to, limit, errors.New("начало периода должно быть раньше конца")
+	}
+	if to.Sub(from) > maxHistoryPeriod {
+		return from, to, limit, fmt.Errorf("период не может превышать %d дней", int(maxHistoryPeriod.Hours()/24))
+	}
+
+	switch {
+	case limit == 0:
+		limit = defaultHistoryLimit
+	case limit < 0 || limit > maxHistoryLimit:
+		return from, to, limit, fmt.Errorf("limit должен быть от 1 до %d", maxHistoryLimit)
+	}
+	return from, to, limit, nil
+}
+
+// MaintainLedger создает секции журнала на будущие месяцы и архивирует устаревшие
+// Предназначен для периодического запуска планировщиком задач
+func (s *HistoryService) MaintainLedger(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	// Текущий месяц и partitionsAhead следующих
+	if err := s.repo.EnsurePartitions(ctx, now, s.partitionsAhead+1); err != nil {
+		return err
+	}
+
+	if s.retentionMonths <= 0 {
+		return nil
+	}
+
+	// Граница архивации - начало месяца, отстоящего на retentionMonths от текущего
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	before := current.AddDate(0, -s.retentionMonths, 0)
+
+	archived, err := s.repo.ArchiveTransactions(ctx, before)
+	if err != nil {
+		return fmt.Errorf("ошибка архивации журнала: %w", err)
+	}
+	if archived > 0 {
+		log.Printf("В архив перенесено %d записей журнала до %s", archived, before.Format("2006-01-02"))
+	}
+	return nil
+}
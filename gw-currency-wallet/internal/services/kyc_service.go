@@ -0,0 +1,251 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+)
+
+// ErrDocumentTooLarge возвращается, если файл документа превышает допустимый размер
+var ErrDocumentTooLarge = errors.New("файл документа слишком большой")
+
+// kycDocumentTypes - допустимые типы документов верификации
+var kycDocumentTypes = map[string]bool{
+	models.KYCDocumentPassport:       true,
+	models.KYCDocumentIDCard:         true,
+	models.KYCDocumentDriverLicense:  true,
+	models.KYCDocumentProofOfAddress: true,
+	models.KYCDocumentSelfie:         true,
+}
+
+// kycContentTypes - допустимые форматы файлов и расширения, под которыми они сохраняются
+var kycContentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+// KYCService реализует подачу документов и проверку верификации пользователей
+type KYCService struct {
+	repo    storage.KYCRepository  // Репозиторий верификации
+	users   storage.UserRepository // Репозиторий пользователей
+	store   storage.DocumentStore  // Хранилище файлов документов
+	maxSize int64                  // Максимальный размер файла в байтах
+}
+
+// NewKYCService создает сервис верификации пользователей
+// Параметры:
+//   - repo: репозиторий верификации (метаданные документов и статусы)
+//   - users: репозиторий пользователей
+//   - store: хранилище файлов документов
+//   - maxSize: максимальный размер одного файла в байтах
+//
+// Возвращает:
+//   - *KYCService: инициализированный сервис
+func NewKYCService(
+	repo storage.KYCRepository,
+	users storage.UserRepository,
+	store storage.DocumentStore,
+	maxSize int64,
+) *KYCService {
+	return &KYCService{
+		repo:    repo,
+		users:   users,
+		store:   store,
+		maxSize: maxSize,
+	}
+}
+
+// MaxDocumentSize возвращает максимальный размер файла документа в байтах
+func (s *KYCService) MaxDocumentSize() int64 {
+	return s.maxSize
+}
+
+// SubmitDocument сохраняет документ пользователя и переводит его в статус pending
+// Формат файла определяется по содержимому, а не по заголовкам клиента
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - docType: тип документа (passport, id_card, driver_license, proof_of_address, selfie)
+//   - fileName: исходное имя файла
+//   - content: содержимое файла
+//
+// Возвращает:
+//   - *models.KYCDocument: сохраненный документ
+//   - error: storage.ErrKYCTransition для верифицированного пользователя,
+//     ErrDocumentTooLarge или ошибка валидации/сохранения
+func (s *KYCService) SubmitDocument(
+	ctx context.Context,
+	userID int,
+	docType string,
+	fileName string,
+	content io.Reader,
+) (*models.KYCDocument, error) {
+	if !kycDocumentTypes[docType] {
+		return nil, fmt.Errorf("неизвестный тип документа: %s", docType)
+	}
+
+	// Определяем формат по первым байтам файла
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("пустой файл документа")
+		}
+		return nil, fmt.Errorf("ошибка чтения документа: %w", err)
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	ext, ok := kycContentTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("неподдерживаемый формат документа: %s (допустимы JPEG, PNG, PDF)", contentType)
+	}
+
+	// Верифицированному пользователю не нужно загружать файл, чтобы получить отказ
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("пользователь не найден")
+	}
+	if user.KYCStatus == models.KYCVerified {
+		return nil, storage.ErrKYCTransition
+	}
+
+	key, err := newDocumentKey(userID, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	// Читаем не больше maxSize+1 байт, чтобы обнаружить превышение размера
+	reader := io.LimitReader(io.MultiReader(bytes.NewReader(head), content), s.maxSize+1)
+	size, err := s.store.Save(ctx, key, reader)
+	if err != nil {
+		return nil, err
+	}
+	if size > s.maxSize {
+		s.deleteDocument(ctx, key)
+		return nil, ErrDocumentTooLarge
+	}
+
+	doc := &models.KYCDocument{
+		UserID:      userID,
+		Type:        docType,
+		FileName:    filepath.Base(fileName),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	if err := s.repo.AddDocument(ctx, doc); err != nil {
+		s.deleteDocument(ctx, key)
+		return nil, err
+	}
+
+	log.Printf("Пользователь %d подал документ %s (№%d) на верификацию", userID, docType, doc.ID)
+	return doc, nil
+}
+
+// GetStatus возвращает статус верификации пользователя и поданные документы
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - *models.KYCStatusResponse: статус и документы
+//   - error: ошибка получения данных
+func (s *KYCService) GetStatus(ctx context.Context, userID int) (*models.KYCStatusResponse, error) {
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("пользователь не найден")
+	}
+
+	docs, err := s.repo.ListDocuments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.KYCStatusResponse{
+		Status:    user.KYCStatus,
+		Comment:   user.KYCComment,
+		Documents: docs,
+	}, nil
+}
+
+// ListApplicants возвращает пользователей в указанном статусе верификации
+// Параметры:
+//   - ctx: контекст выполнения
+//   - status: статус верификации (по умолчанию pending)
+//   - limit: максимальное количество записей (0 - 100)
+//
+// Возвращает:
+//   - []models.User: пользователи, от давно ожидающих к недавним
+//   - error: ошибка получения данных
+func (s *KYCService) ListApplicants(ctx context.Context, status string, limit int) ([]models.User, error) {
+	switch status {
+	case models.KYCNone, models.KYCPending, models.KYCVerified, models.KYCRejected:
+	default:
+		return nil, fmt.Errorf("неизвестный статус верификации: %s", status)
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.ListUsersByKYCStatus(ctx, status, limit)
+}
+
+// ListDocuments возвращает документы пользователя (для администратора)
+func (s *KYCService) ListDocuments(ctx context.Context, userID int) ([]models.KYCDocument, error) {
+	return s.repo.ListDocuments(ctx, userID)
+}
+
+// Resolve принимает решение по заявке на верификацию
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - adminID: идентификатор администратора
+//   - verify: true - верифицировать, false - отклонить
+//   - comment: комментарий (причина отказа показывается пользователю)
+//
+// Возвращает:
+//   - error: storage.ErrKYCTransition, если заявка не ожидает проверки
+func (s *KYCService) Resolve(ctx context.Context, userID, adminID int, verify bool, comment string) error {
+	status := models.KYCRejected
+	if verify {
+		status = models.KYCVerified
+	}
+
+	if err := s.repo.TransitionKYC(ctx, userID, models.KYCPending, status, adminID, comment); err != nil {
+		return err
+	}
+
+	log.Printf("Администратор %d перевел верификацию пользователя %d в статус %s", adminID, userID, status)
+	return nil
+}
+
+// deleteDocument удаляет файл документа, который не удалось зарегистрировать
+func (s *KYCService) deleteDocument(ctx context.Context, key string) {
+	if err := s.store.Delete(context.WithoutCancel(ctx), key); err != nil {
+		log.Printf("Не удалось удалить файл документа %s: %v", key, err)
+	}
+}
+
+// newDocumentKey генерирует ключ файла документа, не зависящий от имени, присланного клиентом
+func newDocumentKey(userID int, ext string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("ошибка генерации ключа документа: %w", err)
+	}
+	return fmt.Sprintf("%d/%s%s", userID, hex.EncodeToString(buf), ext), nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"gw-currency-wallet/internal/fees"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tenant"
+	"math"
+	"time"
+)
+
+// loyaltyWindow - период, за который считается объем обменов для уровня лояльности
+const loyaltyWindow = 30 * 24 * time.Hour
+
+// loyaltyCurrency - валюта, в которой считается объем обменов
+const loyaltyCurrency = "USD"
+
+// LoyaltyService определяет уровень лояльности по объему обменов за 30 дней
+// и рассчитывает комиссию обмена с учетом скидки уровня
+// Базовая комиссия и уровни скидок задаются для каждого арендатора
+type LoyaltyService struct {
+	ledger  storage.TransactionRepository // Журнал операций (объем обменов)
+	rates   RateProvider                  // Курсы для пересчета объема в USD
+	tenants *tenant.Registry              // Арендаторы с базовой комиссией и уровнями скидок
+}
+
+// NewLoyaltyService создает сервис уровней лояльности
+// Параметры:
+//   - ledger: журнал операций
+//   - rates: сервис курсов валют
+//   - tenants: арендаторы с базовой комиссией обмена и уровнями скидок
+//
+// Возвращает:
+//   - *LoyaltyService: инициализированный сервис
+func NewLoyaltyService(ledger storage.TransactionRepository, rates RateProvider, tenants *tenant.Registry) *LoyaltyService {
+	return &LoyaltyService{
+		ledger:  ledger,
+		rates:   rates,
+		tenants: tenants,
+	}
+}
+
+// ExchangeFeePercent возвращает комиссию обмена для пользователя и его уровень
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - float64: комиссия в процентах
+//   - string: название уровня
+//   - error: ошибка расчета объема
+func (s *LoyaltyService) ExchangeFeePercent(ctx context.Context, userID int) (float64, string, error) {
+	schedule := s.schedule(ctx)
+	if schedule.BasePercent == 0 {
+		return 0, "", nil // Комиссия отключена - объем не нужен
+	}
+
+	volume, err := s.volume(ctx, userID)
+	if err != nil {
+		return 0, "", err
+	}
+	tier, _ := schedule.TierFor(volume)
+	return schedule.FeePercent(tier), tier.Name, nil
+}
+
+// Status возвращает уровень пользователя и прогресс до следующего уровня
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - *models.LoyaltyStatus: уровень, комиссия и прогресс
+//   - error: ошибка расчета объема
+func (s *LoyaltyService) Status(ctx context.Context, userID int) (*models.LoyaltyStatus, error) {
+	volume, err := s.volume(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := s.schedule(ctx)
+	tier, next := schedule.TierFor(volume)
+	status := &models.LoyaltyStatus{
+		Tier:            tier.Name,
+		DiscountPercent: tier.Discount,
+		FeePercent:      schedule.FeePercent(tier),
+		Volume30d:       roundCents(volume),
+	}
+	if next != nil {
+		remaining := roundCents(next.MinVolume - volume)
+		status.NextTier = next.Name
+		status.NextTierVolume = &next.MinVolume
+		status.VolumeToNextTier = &remaining
+	}
+	return status, nil
+}
+
+// schedule возвращает комиссию и уровни арендатора из контекста запроса
+func (s *LoyaltyService) schedule(ctx context.Context) fees.Schedule {
+	if t, ok := s.tenants.Get(tenant.IDFromContext(ctx)); ok {
+		return t.Fees
+	}
+	return s.tenants.Default().Fees
+}
+
+// volume возвращает объем обменов пользователя за 30 дней в USD по текущим курсам
+func (s *LoyaltyService) volume(ctx context.Context, userID int) (float64, error) {
+	byCurrency, err := s.ledger.ExchangeVolume(ctx, userID, time.Now().Add(-loyaltyWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for currency, amount := range byCurrency {
+		if currency == loyaltyCurrency {
+			total += amount
+			continue
+		}
+		rate, err := s.rates.GetRate(ctx, currency, loyaltyCurrency)
+		if err != nil {
+			return 0, fmt.Errorf("ошибка пересчета объема обменов в %s: %w", loyaltyCurrency, err)
+		}
+		total += amount * rate
+	}
+	return total, nil
+}
+
+// roundCents округляет сумму до сотых
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/locale"
+	"gw-currency-wallet/internal/metrics"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/notify"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Параметры отправки уведомлений из outbox
+const (
+	notificationBatchSize     = 100             // Максимум уведомлений за одно чтение outbox
+	notificationLease         = 5 * time.Minute // Время, на которое захватываются уведомления (повтор, если экземпляр прервался)
+	notificationMaxRetryDelay = time.Hour       // Максимальная задержка повторной отправки
+	notificationSubject       = "Операция по кошельку"
+	notificationListLimit     = 50  // Размер списка уведомлений по умолчанию
+	notificationListMaxLimit  = 200 // Максимальный размер списка уведомлений
+)
+
+// Ошибки уведомлений об операциях
+var (
+	ErrInvalidNotificationRoute  = errors.New("некорректное правило уведомлений")
+	ErrNotificationRouteNotFound = errors.New("правило уведомлений не найдено")
+	ErrNotificationNotFound      = errors.New("неотправленное уведомление не найдено")
+)
+
+// NotificationOptions содержит параметры отправки уведомлений
+type NotificationOptions struct {
+	MaxAttempts int           // Попыток отправки до отметки неотправленным
+	RetryDelay  time.Duration // Задержка первой повторной отправки (удваивается с каждой попыткой)
+	Retention   time.Duration // Время хранения отправленных и неотправленных уведомлений
+}
+
+// NotificationService доставляет уведомления пользователям об операциях с балансом из outbox
+// через подключенные каналы и управляет правилами доставки пользователей
+// Уведомления записываются хранилищем в транзакции операции по правилам пользователя,
+// сервис доставляет их с повторами; недоставленные уведомления остаются в dead letter
+type NotificationService struct {
+	notifications storage.NotificationRepository // Outbox уведомлений и правила доставки
+	preferences   storage.PreferencesRepository  // Настройки пользователей (локаль сумм)
+	channels      *notify.Registry               // Подключенные каналы доставки
+	opts          NotificationOptions            // Параметры повторов и хранения
+}
+
+// NewNotificationService создает сервис отправки уведомлений
+// Параметры:
+//   - notifications: outbox уведомлений
+//   - preferences: настройки пользователей
+//   - channels: подключенные каналы доставки
+//   - opts: параметры повторов и хранения
+//
+// Возвращает:
+//   - *NotificationService: инициализированный сервис
+func NewNotificationService(
+	notifications storage.NotificationRepository,
+	preferences storage.PreferencesRepository,
+	channels *notify.Registry,
+	opts NotificationOptions,
+) *NotificationService {
+	return &NotificationService{notifications: notifications, preferences: preferences, channels: channels, opts: opts}
+}
+
+// Dispatch отправляет уведомления, время отправки которых наступило, и удаляет устаревшие
+// Выполняется фоновой задачей; ошибка отправки одного уведомления откладывает его повтор
+// и не прерывает отправку остальных
+// Параметры:
+//   - ctx: контекст выполнения
+//
+// Возвращает:
+//   - error: ошибка хранилища
+func (s *NotificationService) Dispatch(ctx context.Context) error {
+	for {
+		notifications, err := s.notifications.ClaimNotifications(ctx, notificationBatchSize, notificationLease)
+		if err != nil {
+			return err
+		}
+		for _, notification := range notifications {
+			if err := s.deliver(ctx, notification); err != nil {
+				return err
+			}
+		}
+		if len(notifications) < notificationBatchSize {
+			break
+		}
+	}
+
+	purged, err := s.notifications.PurgeNotifications(ctx, time.Now().Add(-s.opts.Retention))
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("Удалено устаревших уведомлений: %d", purged)
+	}
+	return nil
+}
+
+// deliver отправляет уведомление и записывает результат попытки
+// Возвращает только ошибку записи результата
+func (s *NotificationService) deliver(ctx context.Context, notification models.Notification) error {
+	sendErr := s.send(ctx, notification)
+	switch {
+	case sendErr == nil:
+		metrics.Notifications.WithLabelValues(notification.Channel, models.NotificationSent).Inc()
+		return s.notifications.MarkNotificationSent(ctx, notification.ID)
+
+	case errors.Is(sendErr, notify.ErrUndeliverable) || notification.Attempts >= s.opts.MaxAttempts:
+		log.Printf("Уведомление %d (%s) пользователю %d не отправлено за %d попыток: %v",
+			notification.ID, notification.Channel, notification.UserID, notification.Attempts, sendErr)
+		metrics.Notifications.WithLabelValues(notification.Channel, models.NotificationFailed).Inc()
+		return s.notifications.FailNotification(ctx, notification.ID, sendErr.Error())
+
+	default:
+		metrics.Notifications.WithLabelValues(notification.Channel, "retry").Inc()
+		return s.notifications.RetryNotification(ctx, notification.ID, sendErr.Error(),
+			time.Now().Add(s.retryDelay(notification.Attempts)))
+	}
+}
+
+// send формирует текст уведомления и доставляет его через канал уведомления
+func (s *NotificationService) send(ctx context.Context, notification models.Notification) error {
+	channel, ok := s.channels.Channel(notification.Channel)
+	if !ok {
+		return fmt.Errorf("%w: канал %s не подключен", notify.ErrUndeliverable, notification.Channel)
+	}
+	entries, err := notification.Entries()
+	if err != nil {
+		return fmt.Errorf("%w: некорректные данные операции: %v", notify.ErrUndeliverable, err)
+	}
+
+	loc := models.DefaultLocale
+	if prefs, err := s.preferences.GetPreferences(ctx, notification.UserID); err == nil {
+		loc = prefs.Locale
+	}
+	return channel.Send(ctx, notify.Delivery{
+		ID:          notification.ID,
+		UserID:      notification.UserID,
+		OperationID: notification.OperationID,
+		Target:      deliveryTarget(notification),
+		Subject:     notificationSubject,
+		Text:        notificationText(notification.OperationID, entries, loc),
+		Payload:     notification.Payload,
+	})
+}
+
+// deliveryTarget возвращает адрес доставки: адрес из правила пользователя
+// или адрес канала по умолчанию (email профиля, личный чат привязанного Telegram)
+func deliveryTarget(notification models.Notification) string {
+	if notification.Target != "" {
+		return notification.Target
+	}
+	switch notification.Channel {
+	case models.NotificationChannelEmail:
+		return notification.Email
+	case models.NotificationChannelTelegram:
+		if notification.TelegramChatID != 0 {
+			return strconv.FormatInt(notification.TelegramChatID, 10)
+		}
+	}
+	return ""
+}
+
+// retryDelay возвращает задержку повторной отправки после attempts попыток:
+// RetryDelay, удваиваемая с каждой попыткой, но не больше часа
+func (s *NotificationService) retryDelay(attempts int) time.Duration {
+	delay := s.opts.RetryDelay
+	for i := 1; i < attempts && delay < notificationMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, notificationMaxRetryDelay)
+}
+
+// notificationEntryTitles - описания записей журнала в тексте уведомления
+var notificationEntryTitles = map[string]string{
+	models.TransactionDeposit:          "Пополнение",
+	models.TransactionWithdraw:         "Снятие",
+	models.TransactionTransferOut:      "Исходящий перевод",
+	models.TransactionTransferIn:       "Входящий перевод",
+	models.TransactionExchangeOut:      "Обмен: списание",
+	models.TransactionExchangeIn:       "Обмен: зачисление",
+	models.TransactionAdminCredit:      "Зачисление администратором",
+	models.TransactionAdminDebit:       "Списание администратором",
+	models.TransactionPromoBonus:       "Бонус по промокоду",
+	models.TransactionFee:              "Комиссия",
+	models.TransactionExchangeReversal: "Отмена обмена",
+}
+
+// notificationText формирует текст уведомления: по строке на запись журнала с суммой по локали получателя
+func notificationText(operationID string, entries []models.Transaction, loc string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Операция %s по вашему кошельку:\n", operationID)
+	for _, entry := range entries {
+		title, ok := notificationEntryTitles[entry.Type]
+		if !ok {
+			title = entry.Type
+		}
+		fmt.Fprintf(&b, "%s: %s\n", title, locale.FormatAmount(entry.Amount, entry.Currency, loc))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Routes возвращает правила доставки уведомлений пользователя
+func (s *NotificationService) Routes(ctx context.Context, userID int) ([]models.NotificationRoute, error) {
+	return s.notifications.ListNotificationRoutes(ctx, userID)
+}
+
+// SaveRoute создает или заменяет правило пользователя для канала
+// Пока у пользователя нет правил, уведомления доставляются в каналы по умолчанию;
+// первое правило заменяет их (каналы без правил больше не используются)
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - channel: подключенный канал
+//   - request: адрес, типы записей, наименьшая сумма и признак действия правила
+//
+// Возвращает:
+//   - *models.NotificationRoute: сохраненное правило
+//   - error: ErrInvalidNotificationRoute или ошибка хранилища
+func (s *NotificationService) SaveRoute(ctx context.Context, userID int, channel string, request models.SaveNotificationRouteRequest) (*models.NotificationRoute, error) {
+	if _, ok := s.channels.Channel(channel); !ok {
+		return nil, fmt.Errorf("%w: канал %q не подключен (доступны: %s)",
+			ErrInvalidNotificationRoute, channel, strings.Join(s.channels.Names(), ", "))
+	}
+
+	switch channel {
+	case models.NotificationChannelWebhook:
+		if err := notify.ValidateWebhookURL(request.Target); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidNotificationRoute, err)
+		}
+	case models.NotificationChannelEmail:
+		if request.Target != "" {
+			if _, err := mail.ParseAddress(request.Target); err != nil {
+				return nil, fmt.Errorf("%w: некорректный email", ErrInvalidNotificationRoute)
+			}
+		}
+	default:
+		if request.Target != "" {
+			return nil, fmt.Errorf("%w: адрес канала %s не задается", ErrInvalidNotificationRoute, channel)
+		}
+	}
+	for _, entryType := range request.Types {
+		if _, ok := notificationEntryTitles[entryType]; !ok {
+			return nil, fmt.Errorf("%w: неизвестный тип записи %q", ErrInvalidNotificationRoute, entryType)
+		}
+	}
+	if request.MinAmount < 0 {
+		return nil, fmt.Errorf("%w: сумма не может быть отрицательной", ErrInvalidNotificationRoute)
+	}
+
+	route := &models.NotificationRoute{
+		UserID:    userID,
+		Channel:   channel,
+		Target:    request.Target,
+		Types:     request.Types,
+		MinAmount: request.MinAmount,
+		Enabled:   request.Enabled == nil || *request.Enabled,
+	}
+	if err := s.notifications.SaveNotificationRoute(ctx, route); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// DeleteRoute удаляет правило пользователя для канала
+// Возвращает:
+//   - error: ErrNotificationRouteNotFound или ошибка хранилища
+func (s *NotificationService) DeleteRoute(ctx context.Context, userID int, channel string) error {
+	deleted, err := s.notifications.DeleteNotificationRoute(ctx, userID, channel)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotificationRouteNotFound
+	}
+	return nil
+}
+
+// History возвращает уведомления пользователя с состоянием доставки от новых к старым
+// limit ограничивается 200, 0 - 50 уведомлений
+func (s *NotificationService) History(ctx context.Context, userID, limit int) ([]models.Notification, error) {
+	return s.notifications.ListNotifications(ctx, userID, "", notificationLimit(limit))
+}
+
+// DeadLetters возвращает недоставленные уведомления всех пользователей от новых к старым
+// limit ограничивается 200, 0 - 50 уведомлений
+func (s *NotificationService) DeadLetters(ctx context.Context, limit int) ([]models.Notification, error) {
+	return s.notifications.ListNotifications(ctx, 0, models.NotificationFailed, notificationLimit(limit))
+}
+
+// Requeue возвращает недоставленное уведомление в очередь (например, после исправления адреса или сбоя канала)
+// Возвращает:
+//   - error: ErrNotificationNotFound или ошибка хранилища
+func (s *NotificationService) Requeue(ctx context.Context, id int64) error {
+	requeued, err := s.notifications.RequeueNotification(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !requeued {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// notificationLimit приводит размер списка уведомлений к допустимому
+func notificationLimit(limit int) int {
+	if limit <= 0 {
+		return notificationListLimit
+	}
+	return min(limit, notificationListMaxLimit)
+}
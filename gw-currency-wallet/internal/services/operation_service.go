@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/metrics"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"time"
+)
+
+// OperationStep выполняет шаг многошаговой денежной операции
+// Контекст шага содержит операцию и номер шага (storage.WithOperationStep): транзакция изменения баланса
+// записывает выполнение шага вместе с изменением баланса
+type OperationStep func(ctx context.Context) error
+
+// Compensation отменяет выполненный шаг операции по ее параметрам (op.Payload)
+// Компенсация может выполняться повторно после сбоя, поэтому должна изменять баланс в одной транзакции
+// (контекст содержит номер шага после отмены) или быть идемпотентной
+type Compensation func(ctx context.Context, op *models.MoneyOperation) error
+
+// OperationOptions - параметры фоновой компенсации незавершенных операций
+type OperationOptions struct {
+	StaleAfter  time.Duration // Операция без изменений дольше этого времени считается прерванной сбоем
+	MaxAttempts int           // Попыток компенсации фоновой задачей до перевода операции в failed
+	BatchSize   int           // Операций за один запуск фоновой задачи
+}
+
+// OperationService выполняет многошаговые денежные операции с компенсацией (сага)
+//
+// Состояние операции и количество выполненных шагов хранятся в БД. Если шаг завершился ошибкой,
+// выполненные шаги отменяются компенсациями в обратном порядке. Операции, прерванные сбоем экземпляра
+// сервиса или неудачной компенсацией, доводит фоновая задача (Recover): компенсирует выполненные шаги,
+// а операцию со всеми выполненными шагами считает завершенной
+type OperationService struct {
+	repo          storage.OperationRepository // Состояние операций
+	opts          OperationOptions            // Параметры фоновой компенсации
+	compensations map[string][]Compensation   // Компенсации шагов по видам операций
+}
+
+// NewOperationService создает сервис многошаговых денежных операций
+// Параметры:
+//   - repo: репозиторий состояния операций
+//   - opts: параметры фоновой компенсации
+//
+// Возвращает:
+//   - *OperationService: сервис без зарегистрированных видов операций (Register)
+func NewOperationService(repo storage.OperationRepository, opts OperationOptions) *OperationService {
+	opts.MaxAttempts = max(opts.MaxAttempts, 1)
+	opts.BatchSize = max(opts.BatchSize, 1)
+	return &OperationService{repo: repo, opts: opts, compensations: make(map[string][]Compensation)}
+}
+
+// Register регистрирует вид операции: по компенсации на каждый шаг в порядке выполнения (nil - шаг не требует отмены)
+// Регистрация выполняется при создании сервисов, до запуска операций и фоновой задачи
+func (s *OperationService) Register(kind string, compensations ...Compensation) {
+	s.compensations[kind] = compensations
+}
+
+// Run выполняет шаги операции вида kind по порядку с сохранением состояния
+// Если шаг завершился ошибкой, выполненные шаги компенсируются сразу (неудачную компенсацию повторит
+// фоновая задача) и возвращается ошибка шага
+// В пробном режиме (storage.WithDryRun) и без сервиса (nil) шаги выполняются без сохранения состояния
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - kind: вид операции (зарегистрированный Register, шагов столько же, сколько компенсаций)
+//   - payload: параметры операции, по которым компенсируются шаги (сохраняются в JSON)
+//   - steps: шаги операции
+//
+// Возвращает:
+//   - error: ошибка шага или сохранения состояния операции
+func (s *OperationService) Run(ctx context.Context, userID int, kind string, payload any, steps ...OperationStep) error {
+	if s == nil || storage.IsDryRun(ctx) {
+		for _, step := range steps {
+			if err := step(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(s.compensations[kind]) != len(steps) {
+		return fmt.Errorf("вид операции %q не зарегистрирован для %d шагов", kind, len(steps))
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения параметров операции: %w", err)
+	}
+	op := &models.MoneyOperation{UserID: userID, Kind: kind, Payload: data}
+	if err := s.repo.CreateOperation(ctx, op); err != nil {
+		return err
+	}
+
+	for i, step := range steps {
+		if err := step(storage.WithOperationStep(ctx, op.ID, i+1)); err != nil {
+			s.abort(ctx, op, err)
+			return err
+		}
+		// Шаг с изменением баланса уже записан в своей транзакции, остальные записываются здесь
+		op.Step = i + 1
+		if err := s.repo.SetOperationStep(ctx, op.ID, op.Step); err != nil {
+			log.Printf("Ошибка сохранения шага %d операции %d: %v", op.Step, op.ID, err)
+		}
+	}
+
+	// Незаписанное завершение фоновая задача восстановит по количеству выполненных шагов
+	if err := s.finish(ctx, op, models.OperationCompleted, ""); err != nil {
+		log.Printf("Ошибка завершения операции %d: %v", op.ID, err)
+	}
+	return nil
+}
+
+// abort отменяет операцию после ошибки шага cause: компенсирует выполненные шаги
+// Компенсация выполняется и после отмены контекста запроса: изменения баланса уже зафиксированы
+func (s *OperationService) abort(ctx context.Context, op *models.MoneyOperation, cause error) {
+	ctx = context.WithoutCancel(ctx)
+	if op.Step > 0 {
+		log.Printf("Операция %d (%s) прервана на шаге %d: %v; компенсация выполненных шагов", op.ID, op.Kind, op.Step+1, cause)
+	}
+	if err := s.repo.SetOperationStatus(ctx, op.ID, models.OperationCompensating, cause.Error()); err != nil {
+		log.Printf("Ошибка сохранения состояния операции %d: %v", op.ID, err)
+		return
+	}
+	if err := s.compensate(ctx, op); err != nil {
+		log.Printf("Ошибка компенсации операции %d (повторит фоновая задача): %v", op.ID, err)
+	}
+}
+
+// compensate отменяет выполненные шаги операции в обратном порядке и переводит ее в compensated
+func (s *OperationService) compensate(ctx context.Context, op *models.MoneyOperation) error {
+	compensations, ok := s.compensations[op.Kind]
+	if !ok || op.Step > len(compensations) {
+		return fmt.Errorf("неизвестный вид операции %q или шаг %d", op.Kind, op.Step)
+	}
+	for op.Step > 0 {
+		if compensation := compensations[op.Step-1]; compensation != nil {
+			if err := compensation(storage.WithOperationStep(ctx, op.ID, op.Step-1), op); err != nil {
+				return fmt.Errorf("компенсация шага %d: %w", op.Step, err)
+			}
+		}
+		op.Step--
+		if err := s.repo.SetOperationStep(ctx, op.ID, op.Step); err != nil {
+			return err
+		}
+	}
+	return s.finish(ctx, op, models.OperationCompensated, "")
+}
+
+// finish переводит операцию в конечное состояние status
+func (s *OperationService) finish(ctx context.Context, op *models.MoneyOperation, status, errText string) error {
+	if err := s.repo.SetOperationStatus(ctx, op.ID, status, errText); err != nil {
+		return err
+	}
+	op.Status = status
+	metrics.MultiStepOperations.WithLabelValues(op.Kind, status).Inc()
+	return nil
+}
+
+// Recover доводит операции, прерванные сбоем или неудачной компенсацией: операции со всеми выполненными
+// шагами завершает, в остальных компенсирует выполненные шаги
+// Операция, компенсация которой не удалась за MaxAttempts попыток, переводится в failed с оповещением дежурных
+// Параметры:
+//   - ctx: контекст выполнения
+//   - alert: оповещение дежурных об операциях, требующих разбора вручную (nil - только журнал)
+//
+// Возвращает:
+//   - error: ошибка получения операций
+func (s *OperationService) Recover(ctx context.Context, alert OpsAlertFunc) error {
+	operations, err := s.repo.ClaimStaleOperations(ctx, time.Now().Add(-s.opts.StaleAfter), s.opts.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range operations {
+		op := &operations[i]
+		if compensations, ok := s.compensations[op.Kind]; ok && op.Step == len(compensations) {
+			// Все шаги выполнены, не записано только завершение
+			if err := s.finish(ctx, op, models.OperationCompleted, ""); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		err := s.compensate(ctx, op)
+		if err == nil {
+			log.Printf("Операция %d (%s) пользователя %d компенсирована", op.ID, op.Kind, op.UserID)
+			continue
+		}
+		log.Printf("Ошибка компенсации операции %d (попытка %d из %d): %v", op.ID, op.Attempts, s.opts.MaxAttempts, err)
+		if op.Attempts < s.opts.MaxAttempts {
+			continue
+		}
+
+		// Ошибка компенсации дополняет исходную ошибку шага
+		errText := err.Error()
+		if op.Error != "" {
+			errText = op.Error + "; " + errText
+		}
+		if err := s.finish(ctx, op, models.OperationFailed, errText); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if alert != nil {
+			text := fmt.Sprintf("⚠️ Операция %d (%s) пользователя %d не компенсирована за %d попыток, выполнено шагов: %d: %v",
+				op.ID, op.Kind, op.UserID, op.Attempts, op.Step, err)
+			if err := alert(ctx, text); err != nil {
+				log.Printf("Ошибка оповещения о незавершенной операции: %v", err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
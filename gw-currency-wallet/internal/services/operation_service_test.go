@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newOperationsWallet создает сервис кошелька, выполняющий обмены многошаговыми операциями
+func newOperationsWallet(rates fakeRates, balance models.Balance) (*WalletService, *fakeWallets, *fakeOperations, *OperationService) {
+	wallets := newFakeWallets(map[int]models.Balance{testAlice: balance})
+	repo := newFakeOperations()
+	operations := NewOperationService(repo, OperationOptions{StaleAfter: time.Minute, MaxAttempts: 2})
+	s := NewWalletService(wallets, nil, rates, nil, nil, nil, nil, operations, nil, nil)
+	return s, wallets, repo, operations
+}
+
+func TestExchangeOperationCompensation(t *testing.T) {
+	tests := []struct {
+		name         string
+		rates        fakeRates
+		from, to     string
+		raceDebit    float64 // Списание параллельной операцией после быстрой проверки средств
+		wantErr      string
+		wantStatus   string
+		wantStep     int
+		wantReversed int
+		wantBalance  models.Balance
+	}{
+		{
+			name:        "все шаги выполнены",
+			rates:       testRates,
+			from:        "USD",
+			to:          "EUR",
+			wantStatus:  models.OperationCompleted,
+			wantStep:    2,
+			wantBalance: models.Balance{EUR: 90},
+		},
+		{
+			name:         "проверка курса после обмена не пройдена",
+			rates:        fakeRates{"USD/RUB": 150},
+			from:         "USD",
+			to:           "RUB",
+			wantErr:      "слишком высокий курс",
+			wantStatus:   models.OperationCompensated,
+			wantReversed: 1,
+			wantBalance:  models.Balance{USD: 100},
+		},
+		{
+			name:        "обмен не выполнен",
+			rates:       testRates,
+			from:        "USD",
+			to:          "EUR",
+			raceDebit:   50,
+			wantErr:     storage.ErrInsufficientFunds.Error(),
+			wantStatus:  models.OperationCompensated,
+			wantBalance: models.Balance{USD: 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, wallets, repo, _ := newOperationsWallet(tt.rates, models.Balance{USD: 100})
+			wallets.raceDebit["USD"] = tt.raceDebit
+
+			_, err := s.Exchange(context.Background(), testAlice, tt.from, tt.to, 100)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ошибка %v, ожидалась %q", err, tt.wantErr)
+			}
+
+			op := repo.get(1)
+			if op.Status != tt.wantStatus || op.Step != tt.wantStep {
+				t.Errorf("операция %s на шаге %d, ожидалась %s на шаге %d", op.Status, op.Step, tt.wantStatus, tt.wantStep)
+			}
+			if len(wallets.reversed) != tt.wantReversed {
+				t.Errorf("сторнировано обменов: %d, ожидалось %d", len(wallets.reversed), tt.wantReversed)
+			}
+			if got := wallets.balance(testAlice); got != tt.wantBalance {
+				t.Errorf("баланс %+v, ожидался %+v", got, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestOperationServiceRecover(t *testing.T) {
+	quote, _ := json.Marshal(models.ExchangeQuote{FromCurrency: "USD", ToCurrency: "EUR", Amount: 100, Rate: 0.9, ExchangedAmount: 90})
+
+	tests := []struct {
+		name        string
+		step        int            // Выполнено шагов до сбоя
+		balance     models.Balance // Баланс после прерванной операции
+		recoveries  int            // Запусков фоновой задачи
+		wantStatus  string
+		wantBalance models.Balance
+		wantAlert   bool
+	}{
+		{
+			name:        "операция прервана до обмена",
+			balance:     models.Balance{USD: 100},
+			recoveries:  1,
+			wantStatus:  models.OperationCompensated,
+			wantBalance: models.Balance{USD: 100},
+		},
+		{
+			name:        "операция прервана после обмена",
+			step:        1,
+			balance:     models.Balance{EUR: 90},
+			recoveries:  1,
+			wantStatus:  models.OperationCompensated,
+			wantBalance: models.Balance{USD: 100},
+		},
+		{
+			name:        "все шаги выполнены, завершение не записано",
+			step:        2,
+			balance:     models.Balance{EUR: 90},
+			recoveries:  1,
+			wantStatus:  models.OperationCompleted,
+			wantBalance: models.Balance{EUR: 90},
+		},
+		{
+			name:        "компенсация не удается",
+			step:        1,
+			balance:     models.Balance{EUR: 40}, // Полученная сумма частично потрачена
+			recoveries:  1,
+			wantStatus:  models.OperationCompensating,
+			wantBalance: models.Balance{EUR: 40},
+		},
+		{
+			name:        "компенсация не удалась за все попытки",
+			step:        1,
+			balance:     models.Balance{EUR: 40},
+			recoveries:  2,
+			wantStatus:  models.OperationFailed,
+			wantBalance: models.Balance{EUR: 40},
+			wantAlert:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, wallets, repo, operations := newOperationsWallet(testRates, tt.balance)
+			ctx := context.Background()
+			op := &models.MoneyOperation{UserID: testAlice, Kind: models.OperationKindExchange, Payload: quote}
+			if err := repo.CreateOperation(ctx, op); err != nil {
+				t.Fatal(err)
+			}
+			repo.SetOperationStep(ctx, op.ID, tt.step)
+
+			var alerts []string
+			alert := func(_ context.Context, text string) error {
+				alerts = append(alerts, text)
+				return nil
+			}
+			for range tt.recoveries {
+				if err := operations.Recover(ctx, alert); err != nil {
+					t.Fatalf("ошибка фоновой задачи: %v", err)
+				}
+			}
+
+			if got := repo.get(op.ID).Status; got != tt.wantStatus {
+				t.Errorf("состояние операции %s, ожидалось %s", got, tt.wantStatus)
+			}
+			if got := wallets.balance(testAlice); got != tt.wantBalance {
+				t.Errorf("баланс %+v, ожидался %+v", got, tt.wantBalance)
+			}
+			if (len(alerts) > 0) != tt.wantAlert {
+				t.Errorf("оповещения дежурных: %q", alerts)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OpsAlertFunc отправляет оповещение дежурным (например, в служебный чат Telegram)
+type OpsAlertFunc func(ctx context.Context, text string) error
+
+// RateMonitor проверяет получение курсов от сервиса обмена и оповещает дежурных,
+// если сервис недоступен, отвечает ошибкой или сообщает об устаревших курсах несколько проверок подряд.
+// После оповещения следующее отправляется только при восстановлении
+type RateMonitor struct {
+	rates     *ExchangeService // Сервис курсов валют
+	threshold int              // Число неудачных проверок подряд до оповещения
+	alert     OpsAlertFunc     // Отправка оповещений дежурным
+
+	mu       sync.Mutex
+	failures int  // Неудачных проверок подряд
+	alerted  bool // Оповещение о сбое отправлено, ожидается восстановление
+}
+
+// NewRateMonitor создает монитор получения курсов
+// Параметры:
+//   - rates: сервис курсов валют
+//   - threshold: число неудачных проверок подряд до оповещения (не меньше 1)
+//   - alert: отправка оповещений дежурным
+func NewRateMonitor(rates *ExchangeService, threshold int, alert OpsAlertFunc) *RateMonitor {
+	return &RateMonitor{rates: rates, threshold: max(threshold, 1), alert: alert}
+}
+
+// Check запрашивает курсы у сервиса обмена в обход кэша и при необходимости оповещает дежурных
+// Возвращает ошибку только при сбое отправки оповещения: недоступность сервиса обмена - результат проверки
+func (m *RateMonitor) Check(ctx context.Context) error {
+	degraded, err := m.rates.ProbeRates(ctx)
+
+	var problem string
+	switch {
+	case status.Code(err) == codes.Unavailable || status.Code(err) == codes.DeadlineExceeded:
+		problem = fmt.Sprintf("сервис обмена недоступен: %v", err)
+	case err != nil:
+		problem = fmt.Sprintf("ошибка получения курсов: %v", err)
+	case degraded:
+		problem = "сервис обмена сообщает об устаревших курсах (обновление курсов не удается)"
+		if details := m.updateFailures(ctx); details != "" {
+			problem += ": " + details
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if problem == "" {
+		m.failures = 0
+		if !m.alerted {
+			return nil
+		}
+		m.alerted = false
+		return m.alert(ctx, "✅ Получение курсов восстановлено")
+	}
+
+	m.failures++
+	log.Printf("Проверка курсов (%d подряд): %s", m.failures, problem)
+	if m.alerted || m.failures < m.threshold {
+		return nil
+	}
+	if err := m.alert(ctx, fmt.Sprintf("⚠️ Курсы валют: %s (%d проверок подряд)", problem, m.failures)); err != nil {
+		return err
+	}
+	m.alerted = true
+	return nil
+}
+
+// updateFailures описывает неудачные обновления курсов по состоянию сервиса обмена (GetUpdateStatus):
+// время последнего успешного обновления и последнюю ошибку. Пустая строка - состояние недоступно или ошибок нет
+func (m *RateMonitor) updateFailures(ctx context.Context) string {
+	updates, err := m.rates.UpdateStatus(ctx)
+	if err != nil {
+		return ""
+	}
+
+	var failures []string
+	for _, update := range updates {
+		if update.LastError == "" {
+			continue
+		}
+		lastSuccess := "не было"
+		if update.LastSuccess != nil {
+			lastSuccess = update.LastSuccess.UTC().Format(time.DateTime) + " UTC"
+		}
+		failures = append(failures, fmt.Sprintf("%s - последнее успешное обновление: %s, ошибка: %s",
+			update.Name, lastSuccess, update.LastError))
+	}
+	return strings.Join(failures, "; ")
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrWebhookSignature - подпись webhook отсутствует или неверна
+	ErrWebhookSignature = errors.New("неверная подпись webhook")
+	// ErrWebhookExpired - время отправки webhook вне допустимого окна
+	ErrWebhookExpired = errors.New("время отправки webhook вне допустимого окна")
+	// ErrWebhookReplay - webhook с этим nonce уже был принят
+	ErrWebhookReplay = errors.New("повторная доставка webhook с тем же nonce")
+	// ErrWebhookEvent - событие не содержит обязательных полей или их значения недопустимы
+	ErrWebhookEvent = errors.New("некорректное событие платежа")
+)
+
+// webhookNonceKeyPrefix - префикс ключей кэша принятых nonce
+const webhookNonceKeyPrefix = "webhook:nonce:"
+
+// PaymentWebhookOptions содержит параметры проверки webhook платежного провайдера
+type PaymentWebhookOptions struct {
+	Secret    string        // Общий секрет подписи HMAC-SHA256 (пусто - прием webhook выключен)
+	Tolerance time.Duration // Допустимое расхождение времени отправки с текущим временем
+}
+
+// PaymentWebhookService принимает события платежного провайдера
+// Подлинность проверяется подписью HMAC-SHA256, повтор перехваченного запроса - окном времени
+// и одноразовым nonce, а однократность зачисления - сохранением идентификатора события
+type PaymentWebhookService struct {
+	repo  storage.PaymentRepository // Зачисление платежей
+	cache storage.Cache             // Принятые nonce
+	opts  PaymentWebhookOptions     // Параметры проверки
+}
+
+// NewPaymentWebhookService создает сервис приема событий платежного провайдера
+// Параметры:
+//   - repo: хранилище зачислений
+//   - cache: кэш принятых nonce (Redis - общий для всех экземпляров)
+//   - opts: секрет подписи и окно времени
+//
+// Возвращает:
+//   - *PaymentWebhookService: инициализированный сервис
+func NewPaymentWebhookService(
+	repo storage.PaymentRepository,
+	cache storage.Cache,
+	opts PaymentWebhookOptions,
+) *PaymentWebhookService {
+	return &PaymentWebhookService{repo: repo, cache: cache, opts: opts}
+}
+
+// Enabled сообщает, настроен ли прием webhook
+func (s *PaymentWebhookService) Enabled() bool {
+	return s.opts.Secret != ""
+}
+
+// Verify проверяет подпись, время отправки и nonce запроса
+// Подпись - hex(HMAC-SHA256(secret, timestamp + "." + nonce + "." + body)).
+// Nonce запоминается на двойное окно времени: за это время запрос с тем же nonce отклоняется,
+// а после - отклоняется по времени отправки
+// Параметры:
+//   - ctx: контекст выполнения
+//   - timestamp: время отправки (Unix, секунды)
+//   - nonce: одноразовое значение запроса
+//   - signature: подпись запроса
+//   - body: тело запроса без изменений
+//
+// Возвращает:
+//   - error: ErrWebhookSignature, ErrWebhookExpired, ErrWebhookReplay или ошибка кэша
+func (s *PaymentWebhookService) Verify(ctx context.Context, timestamp, nonce, signature string, body []byte) error {
+	if timestamp == "" || nonce == "" || signature == "" {
+		return ErrWebhookSignature
+	}
+	mac := hmac.New(sha256.New, []byte(s.opts.Secret))
+	mac.Write([]byte(timestamp + "." + nonce + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrWebhookSignature
+	}
+
+	// Время проверяется после подписи: подписанное время нельзя подменить
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookSignature
+	}
+	if age := time.Since(time.Unix(sent, 0)); age > s.opts.Tolerance || age < -s.opts.Tolerance {
+		return ErrWebhookExpired
+	}
+
+	// Incr атомарен: из параллельных запросов с одним nonce принимается только первый
+	seen, err := s.cache.Incr(ctx, webhookNonceKeyPrefix+nonce, 2*s.opts.Tolerance)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки nonce: %w", err)
+	}
+	if seen > 1 {
+		return ErrWebhookReplay
+	}
+	return nil
+}
+
+// Process зачисляет платеж по событию провайдера
+// События других типов принимаются без зачисления (nil без ошибки).
+// Повторное событие не зачисляется: возвращается первое зачисление с Duplicate
+// Параметры:
+//   - ctx: контекст выполнения
+//   - event: событие провайдера (подпись уже проверена)
+//
+// Возвращает:
+//   - *models.PaymentCredit: зачисление или nil для событий без зачисления
+//   - error: ErrWebhookEvent, storage.ErrWalletUnavailable или ошибка хранилища
+func (s *PaymentWebhookService) Process(ctx context.Context, event models.PaymentEvent) (*models.PaymentCredit, error) {
+	if event.EventID == "" || len(event.EventID) > 128 {
+		return nil, ErrWebhookEvent
+	}
+	if event.Type != models.PaymentSucceeded {
+		return nil, nil
+	}
+	if event.UserID <= 0 || !isValidCurrency(event.Currency) || event.Amount <= 0 {
+		return nil, ErrWebhookEvent
+	}
+
+	credit, err := s.repo.CreditPayment(ctx, event)
+	if errors.Is(err, storage.ErrPaymentEventProcessed) {
+		return credit, nil
+	}
+	return credit, err
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"math"
+	"time"
+)
+
+// CurrentRateProvider предоставляет текущие курсы всех валют
+type CurrentRateProvider interface {
+	// GetRates возвращает стоимость единицы валюты в базовой валюте по текущим курсам
+	GetRates(ctx context.Context) (map[string]float64, error)
+}
+
+// PortfolioService оценивает валютные позиции пользователя
+//
+// Средний курс приобретения каждой валюты рассчитывается по всей истории журнала операций
+// так же, как стоимость приобретения в налоговом отчете методом средней стоимости: обмены
+// с валютой оценки - по фактическим суммам, остальные поступления и кросс-обмены - по снимкам
+// курсов на дату операции (для текущего дня снимка еще нет - по текущим курсам).
+// Нереализованный доход - текущая стоимость баланса минус стоимость его приобретения
+type PortfolioService struct {
+	ledger    storage.TransactionRepository // Журнал операций
+	wallets   storage.WalletRepository      // Балансы
+	rates     CurrentRateProvider           // Текущие курсы
+	snapshots SnapshotRateProvider          // Курсы на прошедшие даты
+}
+
+// NewPortfolioService создает сервис оценки валютных позиций
+// Параметры:
+//   - ledger: репозиторий журнала операций
+//   - wallets: репозиторий кошельков
+//   - rates: источник текущих курсов
+//   - snapshots: источник курсов на прошедшие даты (снимки сервиса обмена)
+//
+// Возвращает:
+//   - *PortfolioService: инициализированный сервис
+func NewPortfolioService(ledger storage.TransactionRepository, wallets storage.WalletRepository, rates CurrentRateProvider, snapshots SnapshotRateProvider) *PortfolioService {
+	return &PortfolioService{ledger: ledger, wallets: wallets, rates: rates, snapshots: snapshots}
+}
+
+// Portfolio оценивает позиции пользователя в валюте currency
+// Количество валюты в позиции - текущий баланс; часть баланса, не покрытая историей приобретения
+// (например, журнал начат позже), оценивается по текущему курсу и дохода не дает
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - currency: валюта оценки (USD, RUB, EUR)
+//
+// Возвращает:
+//   - *models.Portfolio: позиции и итоги
+//   - error: ErrUnsupportedCurrency, ErrRatesUnavailable или ошибка журнала и баланса
+func (s *PortfolioService) Portfolio(ctx context.Context, userID int, currency string) (*models.Portfolio, error) {
+	if !isValidCurrency(currency) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+
+	now := time.Now()
+	current, err := s.rates.GetRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRatesUnavailable, err)
+	}
+
+	entries, err := s.ledger.ListLedger(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	calc := newTaxCalculator(portfolioRates{snapshots: s.snapshots, current: current, today: now}, currency, TaxMethodAverage)
+	for _, operation := range groupOperations(entries) {
+		if _, err := calc.apply(ctx, operation); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRatesUnavailable, err)
+		}
+	}
+
+	balance, err := s.wallets.GetCachedBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio := &models.Portfolio{Currency: currency, ValuedAt: now}
+	for _, holding := range []struct {
+		currency string
+		amount   float64
+	}{
+		{"USD", balance.USD},
+		{"RUB", balance.RUB},
+		{"EUR", balance.EUR},
+	} {
+		rate := 1.0
+		if holding.currency != currency {
+			from, to := current[holding.currency], current[currency]
+			if from == 0 || to == 0 {
+				return nil, fmt.Errorf("%w: нет курса %s или %s", ErrRatesUnavailable, holding.currency, currency)
+			}
+			rate = from / to
+		}
+		position := valuePosition(calc, holding.currency, holding.amount, rate)
+		portfolio.Positions = append(portfolio.Positions, position)
+		portfolio.CostBasis += position.CostBasis
+		portfolio.MarketValue += position.MarketValue
+		portfolio.UnrealizedPnL += position.UnrealizedPnL
+	}
+	portfolio.CostBasis = roundCents(portfolio.CostBasis)
+	portfolio.MarketValue = roundCents(portfolio.MarketValue)
+	portfolio.UnrealizedPnL = roundCents(portfolio.UnrealizedPnL)
+	return portfolio, nil
+}
+
+// valuePosition оценивает баланс валюты по партиям расчета и текущему курсу rate
+func valuePosition(calc *taxCalculator, currency string, amount, rate float64) models.PortfolioPosition {
+	position := models.PortfolioPosition{Currency: currency, Amount: amount, CurrentRate: rate}
+	if amount <= 0 {
+		return position
+	}
+
+	cost := amount // Валюта оценки приобретается по курсу 1
+	if currency != calc.currency {
+		held, heldCost := calc.pool(currency).held()
+		covered := min(amount, held)
+		cost = (amount - covered) * rate
+		if covered > taxAmountEpsilon {
+			cost += heldCost * covered / held
+		}
+	}
+
+	position.AverageRate = math.Round(cost/amount*1e6) / 1e6
+	position.CostBasis = roundCents(cost)
+	position.MarketValue = roundCents(amount * rate)
+	position.UnrealizedPnL = roundCents(position.MarketValue - position.CostBasis)
+	if position.CostBasis > 0 {
+		position.UnrealizedPnLPercent = roundCents(position.UnrealizedPnL / position.CostBasis * 100)
+	}
+	return position
+}
+
+// portfolioRates - курсы на дату операции для расчета стоимости приобретения:
+// снимки сервиса обмена, для текущего дня (снимка еще нет) - текущие курсы
+type portfolioRates struct {
+	snapshots SnapshotRateProvider // Снимки на прошедшие даты
+	current   map[string]float64   // Текущие курсы
+	today     time.Time            // Момент оценки
+}
+
+// SnapshotRates возвращает курсы на конец дня date (для текущего дня - текущие курсы)
+func (r portfolioRates) SnapshotRates(ctx context.Context, date time.Time) (map[string]float64, error) {
+	if date.UTC().Format(time.DateOnly) == r.today.UTC().Format(time.DateOnly) {
+		return r.current, nil
+	}
+	return r.snapshots.SnapshotRates(ctx, date)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/locale"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// ErrInvalidPreferences возвращается при недопустимом значении настройки
+var ErrInvalidPreferences = errors.New("некорректные настройки")
+
+// PreferencesService хранит настройки пользователя и оформляет по ним
+// балансы и историю операций: валюта итогов, формат сумм, часовой пояс
+type PreferencesService struct {
+	repo     storage.PreferencesRepository // Репозиторий настроек
+	rates    RateProvider                  // Курсы для пересчета в валюту отображения
+	activity *ActivityService              // Журнал активности (изменения настроек)
+}
+
+// NewPreferencesService создает сервис настроек пользователя
+// Параметры:
+//   - repo: репозиторий настроек
+//   - rates: сервис курсов валют
+//   - activity: журнал активности пользователя
+//
+// Возвращает:
+//   - *PreferencesService: инициализированный сервис
+func NewPreferencesService(
+	repo storage.PreferencesRepository,
+	rates RateProvider,
+	activity *ActivityService,
+) *PreferencesService {
+	return &PreferencesService{repo: repo, rates: rates, activity: activity}
+}
+
+// Get возвращает настройки пользователя (до первого сохранения - по умолчанию)
+func (s *PreferencesService) Get(ctx context.Context, userID int) (*models.UserPreferences, error) {
+	return s.repo.GetPreferences(ctx, userID)
+}
+
+// Update изменяет заданные в запросе настройки
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - request: новые значения (незаданные поля не меняются)
+//
+// Возвращает:
+//   - *models.UserPreferences: сохраненные настройки
+//   - error: ErrInvalidPreferences или ошибка сохранения
+func (s *PreferencesService) Update(
+	ctx context.Context,
+	userID int,
+	request models.UpdatePreferencesRequest,
+) (*models.UserPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.DefaultCurrency != nil {
+		if !isValidCurrency(*request.DefaultCurrency) {
+			return nil, fmt.Errorf("%w: неподдерживаемая валюта %q", ErrInvalidPreferences, *request.DefaultCurrency)
+		}
+		prefs.DefaultCurrency = *request.DefaultCurrency
+	}
+	if request.Locale != nil {
+		if !locale.Supported(*request.Locale) {
+			return nil, fmt.Errorf("%w: неподдерживаемая локаль %q (доступны %s, %s)",
+				ErrInvalidPreferences, *request.Locale, locale.RU, locale.EN)
+		}
+		prefs.Locale = *request.Locale
+	}
+	if request.Timezone != nil {
+		// Пустая строка для time.LoadLocation означает UTC - требуем явное имя пояса
+		if _, err := time.LoadLocation(*request.Timezone); err != nil || *request.Timezone == "" {
+			return nil, fmt.Errorf("%w: неизвестный часовой пояс %q", ErrInvalidPreferences, *request.Timezone)
+		}
+		prefs.Timezone = *request.Timezone
+	}
+
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+	s.activity.Record(ctx, models.ActivityEntry{
+		UserID:   userID,
+		Category: models.ActivityProfile,
+		Action:   models.ActionPreferencesUpdated,
+		Details:  request,
+	})
+	return prefs, nil
+}
+
+// DisplayBalance оформляет баланс по настройкам пользователя
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - balance: баланс по валютам
+//
+// Возвращает:
+//   - *models.BalanceDisplay: итог в валюте отображения и суммы по локали
+//   - error: ошибка получения настроек или курсов
+func (s *PreferencesService) DisplayBalance(
+	ctx context.Context,
+	userID int,
+	balance *models.Balance,
+) (*models.BalanceDisplay, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := map[string]float64{"USD": balance.USD, "RUB": balance.RUB, "EUR": balance.EUR}
+	converter := s.converter(ctx, prefs.DefaultCurrency)
+	display := &models.BalanceDisplay{
+		Currency:  prefs.DefaultCurrency,
+		Formatted: make(map[string]string, len(amounts)),
+	}
+	for currency, amount := range amounts {
+		converted, err := converter(currency, amount)
+		if err != nil {
+			return nil, err
+		}
+		display.Total += converted
+		display.Formatted[currency] = locale.FormatAmount(amount, currency, prefs.Locale)
+	}
+	display.Total = roundAmount(display.Total, currencyDecimals(ctx, s.rates, prefs.DefaultCurrency))
+	display.TotalFormatted = locale.FormatAmount(display.Total, prefs.DefaultCurrency, prefs.Locale)
+	return display, nil
+}
+
+// LocalizeHistory дополняет историю операций временем в часовом поясе пользователя,
+// суммами по локали и суммами в валюте отображения
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - history: история операций (изменяется на месте)
+//
+// Возвращает:
+//   - error: ошибка получения настроек или курсов
+func (s *PreferencesService) LocalizeHistory(
+	ctx context.Context,
+	userID int,
+	history *models.TransactionHistoryResponse,
+) error {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	tz, err := time.LoadLocation(prefs.Timezone)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки часового пояса %q: %w", prefs.Timezone, err)
+	}
+
+	converter := s.converter(ctx, prefs.DefaultCurrency)
+	decimals := currencyDecimals(ctx, s.rates, prefs.DefaultCurrency)
+	for i := range history.Transactions {
+		t := &history.Transactions[i]
+		converted, err := converter(t.Currency, t.Amount)
+		if err != nil {
+			return err
+		}
+		converted = roundAmount(converted, decimals)
+		t.DisplayAmount = &converted
+		t.LocalTime = locale.FormatTime(t.CreatedAt, tz, prefs.Locale)
+		t.AmountFormatted = locale.FormatAmount(t.Amount, t.Currency, prefs.Locale)
+	}
+	history.DisplayCurrency = prefs.DefaultCurrency
+	history.Timezone = prefs.Timezone
+	return nil
+}
+
+// converter возвращает функцию пересчета сумм в валюту target по текущим курсам
+// Курс каждой валюты запрашивается один раз
+func (s *PreferencesService) converter(ctx context.Context, target string) func(currency string, amount float64) (float64, error) {
+	rates := map[string]float64{target: 1}
+	return func(currency string, amount float64) (float64, error) {
+		if amount == 0 {
+			return 0, nil // Пустые балансы не требуют курса
+		}
+		rate, ok := rates[currency]
+		if !ok {
+			var err error
+			rate, err = s.rates.GetRate(ctx, currency, target)
+			if err != nil {
+				return 0, fmt.Errorf("ошибка получения курса %s->%s: %w", currency, target, err)
+			}
+			rates[currency] = rate
+		}
+		return amount * rate, nil
+	}
+}
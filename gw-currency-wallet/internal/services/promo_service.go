@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// promoCodePattern - допустимый формат промокода (после приведения к верхнему регистру)
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// PromoService реализует управление промокодами и их активацию
+type PromoService struct {
+	repo storage.PromoRepository // Репозиторий промокодов
+}
+
+// NewPromoService создает сервис промокодов
+// Параметры:
+//   - repo: репозиторий промокодов
+//
+// Возвращает:
+//   - *PromoService: инициализированный сервис
+func NewPromoService(repo storage.PromoRepository) *PromoService {
+	return &PromoService{repo: repo}
+}
+
+// Create создает промокод
+// Параметры:
+//   - ctx: контекст выполнения
+//   - adminID: администратор, создающий промокод
+//   - req: параметры промокода
+//
+// Возвращает:
+//   - *models.PromoCode: созданный промокод
+//   - error: ошибка валидации или storage.ErrPromoExists
+func (s *PromoService) Create(ctx context.Context, adminID int, req models.CreatePromoRequest) (*models.PromoCode, error) {
+	code := normalizePromoCode(req.Code)
+	if !promoCodePattern.MatchString(code) {
+		return nil, errors.New("промокод должен содержать 3-32 символа: латинские буквы, цифры, дефис или подчеркивание")
+	}
+	if !isValidCurrency(req.Currency) {
+		return nil, fmt.Errorf("неподдерживаемая валюта: %s", req.Currency)
+	}
+	if req.Value <= 0 {
+		return nil, errors.New("значение промокода должно быть положительным")
+	}
+
+	switch req.Kind {
+	case models.PromoFixed:
+		if req.MaxBonus != nil {
+			return nil, errors.New("max_bonus задается только для процентного промокода")
+		}
+	case models.PromoPercent:
+		if req.Value > 100 {
+			return nil, errors.New("процент бонуса не может превышать 100")
+		}
+		if req.MaxBonus != nil && *req.MaxBonus <= 0 {
+			return nil, errors.New("max_bonus должен быть положительным")
+		}
+	default:
+		return nil, fmt.Errorf("неизвестный вид промокода: %s", req.Kind)
+	}
+
+	if req.MaxUses != nil && *req.MaxUses <= 0 {
+		return nil, errors.New("max_uses должен быть положительным")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("срок действия промокода уже истек")
+	}
+
+	promo := &models.PromoCode{
+		Code:      code,
+		Kind:      req.Kind,
+		Currency:  req.Currency,
+		Value:     req.Value,
+		MaxBonus:  req.MaxBonus,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: adminID,
+	}
+	if err := s.repo.CreatePromo(ctx, promo); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Администратор %d создал промокод %s (%s %.2f %s)", adminID, promo.Code, promo.Kind, promo.Value, promo.Currency)
+	return promo, nil
+}
+
+// List возвращает промокоды
+// Параметры:
+//   - ctx: контекст выполнения
+//   - limit: максимальное количество записей (0 - 100)
+//
+// Возвращает:
+//   - []models.PromoCode: промокоды от новых к старым
+//   - error: ошибка получения данных
+func (s *PromoService) List(ctx context.Context, limit int) ([]models.PromoCode, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.ListPromos(ctx, limit)
+}
+
+// Deactivate отключает промокод
+// Параметры:
+//   - ctx: контекст выполнения
+//   - code: промокод
+//   - adminID: администратор
+//
+// Возвращает:
+//   - error: storage.ErrPromoUnavailable, если промокод не найден или уже отключен
+func (s *PromoService) Deactivate(ctx context.Context, code string, adminID int) error {
+	code = normalizePromoCode(code)
+	if err := s.repo.DeactivatePromo(ctx, code, adminID); err != nil {
+		return err
+	}
+	log.Printf("Администратор %d отключил промокод %s", adminID, code)
+	return nil
+}
+
+// Redeem активирует фиксированный промокод
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - code: промокод
+//
+// Возвращает:
+//   - *models.PromoRedemption: активация с зачисленным бонусом
+//   - *models.Balance: новый баланс
+//   - error: ошибка активации (storage.ErrPromo*)
+func (s *PromoService) Redeem(ctx context.Context, userID int, code string) (*models.PromoRedemption, *models.Balance, error) {
+	return s.repo.RedeemPromo(ctx, userID, normalizePromoCode(code), "", 0)
+}
+
+// DepositWithPromo пополняет баланс и начисляет бонус по процентному промокоду в одной транзакции
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - currency: валюта пополнения (должна совпадать с валютой промокода)
+//   - amount: сумма пополнения
+//   - code: промокод
+//
+// Возвращает:
+//   - *models.PromoRedemption: активация с зачисленным бонусом
+//   - *models.Balance: новый баланс
+//   - error: ошибка валидации или активации (storage.ErrPromo*)
+func (s *PromoService) DepositWithPromo(
+	ctx context.Context,
+	userID int,
+	currency string,
+	amount float64,
+	code string,
+) (*models.PromoRedemption, *models.Balance, error) {
+	if !isValidCurrency(currency) {
+		return nil, nil, fmt.Errorf("неподдерживаемая валюта: %s", currency)
+	}
+	if amount <= 0 {
+		return nil, nil, errors.New("сумма должна быть положительной")
+	}
+	return s.repo.RedeemPromo(ctx, userID, normalizePromoCode(code), currency, amount)
+}
+
+// normalizePromoCode приводит промокод к каноническому виду (без пробелов, в верхнем регистре)
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/notify"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"regexp"
+	"strconv"
+)
+
+// Ошибки push уведомлений
+var (
+	ErrInvalidPushToken  = errors.New("некорректный токен push уведомлений")
+	ErrPushTokenNotFound = errors.New("токен push уведомлений не найден")
+)
+
+// pushTokenFormats - допустимый вид токенов устройств по платформам
+// (токен APNs - hex, токен FCM - base64url с разделителем ':')
+var pushTokenFormats = map[string]*regexp.Regexp{
+	models.PushPlatformAPNs: regexp.MustCompile(`^[0-9a-fA-F]{64,200}$`),
+	models.PushPlatformFCM:  regexp.MustCompile(`^[A-Za-z0-9_:\-]{32,512}$`),
+}
+
+// PushService управляет токенами push уведомлений мобильного приложения
+// и отправляет push о сработавших уведомлениях о курсах пользователям с привязанным Telegram
+type PushService struct {
+	tokens  storage.PushTokenRepository    // Токены устройств
+	links   storage.TelegramLinkRepository // Привязки Telegram (владелец чата уведомления о курсе)
+	channel *notify.PushChannel            // Канал push (nil - платформы не настроены)
+}
+
+// NewPushService создает сервис push уведомлений
+// Параметры:
+//   - tokens: хранилище токенов устройств
+//   - links: привязки аккаунтов Telegram
+//   - channel: канал push с настроенными платформами (nil - push не настроен)
+//
+// Возвращает:
+//   - *PushService: инициализированный сервис
+func NewPushService(tokens storage.PushTokenRepository, links storage.TelegramLinkRepository, channel *notify.PushChannel) *PushService {
+	return &PushService{tokens: tokens, links: links, channel: channel}
+}
+
+// Register сохраняет токен устройства пользователя
+// Приложение регистрирует токен при каждом запуске: повторная регистрация обновляет время и название,
+// токен, зарегистрированный другим пользователем на том же устройстве, переходит к текущему
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - request: платформа, токен и название устройства
+//
+// Возвращает:
+//   - *models.PushToken: сохраненный токен
+//   - error: ErrInvalidPushToken или ошибка хранилища
+func (s *PushService) Register(ctx context.Context, userID int, request models.RegisterPushTokenRequest) (*models.PushToken, error) {
+	format, ok := pushTokenFormats[request.Platform]
+	if !ok {
+		return nil, fmt.Errorf("%w: платформа должна быть fcm или apns", ErrInvalidPushToken)
+	}
+	if s.channel == nil || !s.channel.Supports(request.Platform) {
+		return nil, fmt.Errorf("%w: push уведомления платформы %s не настроены", ErrInvalidPushToken, request.Platform)
+	}
+	if !format.MatchString(request.Token) {
+		return nil, fmt.Errorf("%w: токен не соответствует формату платформы %s", ErrInvalidPushToken, request.Platform)
+	}
+	if len([]rune(request.DeviceName)) > 128 {
+		return nil, fmt.Errorf("%w: название устройства длиннее 128 символов", ErrInvalidPushToken)
+	}
+
+	token := &models.PushToken{
+		UserID:     userID,
+		Platform:   request.Platform,
+		Token:      request.Token,
+		DeviceName: request.DeviceName,
+	}
+	if err := s.tokens.RegisterPushToken(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// List возвращает токены устройств пользователя
+func (s *PushService) List(ctx context.Context, userID int) ([]models.PushToken, error) {
+	return s.tokens.ListPushTokens(ctx, userID)
+}
+
+// Delete удаляет токен устройства пользователя (выход из приложения на устройстве)
+// Возвращает:
+//   - error: ErrPushTokenNotFound или ошибка хранилища
+func (s *PushService) Delete(ctx context.Context, userID int, id int64) error {
+	deleted, err := s.tokens.DeletePushToken(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPushTokenNotFound
+	}
+	return nil
+}
+
+// AlertNotifier дополняет отправку сработавшего уведомления о курсе push уведомлением
+// владельцу чата, если чат - личный чат привязанного аккаунта Telegram
+// Ошибка push не влияет на результат: уведомление о курсе считается отправленным после отправки в чат
+// Параметры:
+//   - next: отправка уведомления в чат
+//
+// Возвращает:
+//   - AlertNotifyFunc: отправка в чат и push
+func (s *PushService) AlertNotifier(next AlertNotifyFunc) AlertNotifyFunc {
+	if s.channel == nil {
+		return next
+	}
+	return func(ctx context.Context, alert models.RateAlert, rate float64) error {
+		if err := next(ctx, alert, rate); err != nil {
+			return err
+		}
+
+		userID, err := s.links.GetLinkedUser(ctx, alert.ChatID)
+		if err != nil || userID == 0 {
+			if err != nil {
+				log.Printf("Ошибка поиска владельца уведомления о курсе %d: %v", alert.ID, err)
+			}
+			return nil
+		}
+		err = s.channel.Push(ctx, userID, notify.PushMessage{
+			Title: fmt.Sprintf("Курс %s/%s", alert.FromCurrency, alert.ToCurrency),
+			Body: fmt.Sprintf("Курс %s/%s: %.4f, условие %s %s выполнено", alert.FromCurrency, alert.ToCurrency, rate,
+				alert.Condition, strconv.FormatFloat(alert.Threshold, 'f', -1, 64)),
+			Data: map[string]string{"alert_id": strconv.Itoa(alert.ID)},
+		})
+		if err != nil && !errors.Is(err, notify.ErrUndeliverable) {
+			log.Printf("Ошибка отправки push уведомления о курсе %d: %v", alert.ID, err)
+		}
+		return nil
+	}
+}
+
+// QueuedExchangeNotifier дополняет уведомление о результате исполнения обмена из очереди push уведомлением
+// Ошибка push не влияет на результат: уведомление считается отправленным после отправки next
+// Параметры:
+//   - next: основное уведомление (nil - только push)
+//
+// Возвращает:
+//   - QueuedExchangeNotifyFunc: уведомление и push
+func (s *PushService) QueuedExchangeNotifier(next QueuedExchangeNotifyFunc) QueuedExchangeNotifyFunc {
+	if s.channel == nil {
+		return next
+	}
+	return func(ctx context.Context, exchange models.QueuedExchange) error {
+		if next != nil {
+			if err := next(ctx, exchange); err != nil {
+				return err
+			}
+		}
+		title, body := queuedExchangeMessage(exchange)
+		err := s.channel.Push(ctx, exchange.UserID, notify.PushMessage{
+			Title: title,
+			Body:  body,
+			Data:  map[string]string{"queued_exchange_id": strconv.FormatInt(exchange.ID, 10)},
+		})
+		if err != nil && !errors.Is(err, notify.ErrUndeliverable) {
+			log.Printf("Ошибка отправки push уведомления об обмене %d из очереди: %v", exchange.ID, err)
+		}
+		return nil
+	}
+}
+
+// DCANotifier дополняет уведомление о пропущенном или невыполненном обмене плана push уведомлением
+// Ошибка push не влияет на результат: уведомление считается отправленным после отправки next
+// Параметры:
+//   - next: основное уведомление (nil - только push)
+//
+// Возвращает:
+//   - DCANotifyFunc: уведомление и push
+func (s *PushService) DCANotifier(next DCANotifyFunc) DCANotifyFunc {
+	if s.channel == nil {
+		return next
+	}
+	return func(ctx context.Context, plan models.DCAPlan, leg models.DCALeg) error {
+		if next != nil {
+			if err := next(ctx, plan, leg); err != nil {
+				return err
+			}
+		}
+		title, body := dcaLegMessage(plan, leg)
+		err := s.channel.Push(ctx, plan.UserID, notify.PushMessage{
+			Title: title,
+			Body:  body,
+			Data:  map[string]string{"dca_plan_id": strconv.FormatInt(plan.ID, 10), "leg": strconv.Itoa(leg.Number)},
+		})
+		if err != nil && !errors.Is(err, notify.ErrUndeliverable) {
+			log.Printf("Ошибка отправки push уведомления об обмене %d плана %d: %v", leg.Number, plan.ID, err)
+		}
+		return nil
+	}
+}
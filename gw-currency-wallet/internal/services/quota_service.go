@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"strconv"
+	"time"
+)
+
+// Ошибки назначения тарифного плана
+var (
+	ErrUnknownPlan  = errors.New("неизвестный тарифный план")
+	ErrUserNotFound = errors.New("пользователь не найден")
+)
+
+// planCacheTTL - время хранения тарифного плана пользователя в кэше
+// (смена плана администратором сбрасывает кэш, на других экземплярах без Redis - через это время)
+const planCacheTTL = time.Minute
+
+// QuotaOptions содержит параметры квот запросов
+type QuotaOptions struct {
+	Limits map[string]int64 // Запросов за окно по тарифным планам (план без квоты - без ограничения)
+	Window time.Duration    // Длина окна квоты
+}
+
+// QuotaService ограничивает количество запросов пользователя за окно в соответствии с его тарифным планом
+// Запросы считаются в фиксированных окнах (счетчик окна в кэше, общий для экземпляров с Redis)
+type QuotaService struct {
+	cache storage.Cache          // Счетчики запросов и кэш тарифных планов
+	users storage.UserRepository // Тарифные планы пользователей
+	opts  QuotaOptions           // Квоты и окно
+}
+
+// NewQuotaService создает сервис квот запросов
+// Параметры:
+//   - cache: кэш для счетчиков запросов
+//   - users: репозиторий пользователей
+//   - opts: квоты по планам и длина окна
+//
+// Возвращает:
+//   - *QuotaService: инициализированный сервис
+func NewQuotaService(cache storage.Cache, users storage.UserRepository, opts QuotaOptions) *QuotaService {
+	return &QuotaService{cache: cache, users: users, opts: opts}
+}
+
+// Enabled сообщает, ограничено ли количество запросов (квоты не заданы - выключено)
+func (s *QuotaService) Enabled() bool {
+	return len(s.opts.Limits) > 0 && s.opts.Window > 0
+}
+
+// Consume учитывает запрос пользователя
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - *models.QuotaUsage: использование квоты с учетом запроса (Limit 0 - план без ограничения)
+//   - bool: true, если запрос укладывается в квоту
+//   - error: ошибка обращения к кэшу или хранилищу пользователей
+func (s *QuotaService) Consume(ctx context.Context, userID int) (*models.QuotaUsage, bool, error) {
+	usage, err := s.usage(ctx, userID)
+	if err != nil || usage.Limit == 0 {
+		return usage, true, err
+	}
+
+	used, err := s.cache.Incr(ctx, quotaKey(userID, usage.ResetAt), s.opts.Window)
+	if err != nil {
+		return nil, false, fmt.Errorf("ошибка учета запроса: %w", err)
+	}
+	usage.Used = used
+	usage.Remaining = max(usage.Limit-used, 0)
+	return usage, used <= usage.Limit, nil
+}
+
+// Usage возвращает использование квоты пользователя в текущем окне, не учитывая запрос
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - *models.QuotaUsage: использование квоты
+//   - error: ошибка обращения к кэшу или хранилищу пользователей
+func (s *QuotaService) Usage(ctx context.Context, userID int) (*models.QuotaUsage, error) {
+	usage, err := s.usage(ctx, userID)
+	if err != nil || usage.Limit == 0 {
+		return usage, err
+	}
+
+	value, err := s.cache.Get(ctx, quotaKey(userID, usage.ResetAt))
+	switch {
+	case err == nil:
+		usage.Used, _ = strconv.ParseInt(string(value), 10, 64)
+	case !errors.Is(err, storage.ErrCacheMiss):
+		return nil, fmt.Errorf("ошибка получения счетчика запросов: %w", err)
+	}
+	usage.Remaining = max(usage.Limit-usage.Used, 0)
+	return usage, nil
+}
+
+// SetPlan назначает пользователю тарифный план
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - plan: тарифный план (free, premium или план из квот)
+//
+// Возвращает:
+//   - error: ErrUnknownPlan, ErrUserNotFound или ошибка хранилища
+func (s *QuotaService) SetPlan(ctx context.Context, userID int, plan string) error {
+	if _, ok := s.opts.Limits[plan]; !ok && plan != models.PlanFree && plan != models.PlanPremium {
+		return fmt.Errorf("%w: %s", ErrUnknownPlan, plan)
+	}
+
+	found, err := s.users.SetPlan(ctx, userID, plan)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrUserNotFound
+	}
+	return s.cache.Delete(ctx, planKey(userID))
+}
+
+// usage возвращает план, квоту и границу текущего окна пользователя (без счетчика)
+func (s *QuotaService) usage(ctx context.Context, userID int) (*models.QuotaUsage, error) {
+	plan, err := s.plan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &models.QuotaUsage{Plan: plan, Window: s.opts.Window.String()}
+	if !s.Enabled() {
+		return usage, nil
+	}
+	usage.Limit = s.opts.Limits[plan]
+	if usage.Limit > 0 {
+		usage.ResetAt = time.Now().Truncate(s.opts.Window).Add(s.opts.Window)
+	}
+	return usage, nil
+}
+
+// plan возвращает тарифный план пользователя (из кэша или хранилища пользователей)
+func (s *QuotaService) plan(ctx context.Context, userID int) (string, error) {
+	if value, err := s.cache.Get(ctx, planKey(userID)); err == nil {
+		return string(value), nil
+	}
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения тарифного плана: %w", err)
+	}
+	if user == nil {
+		return "", ErrUserNotFound
+	}
+	_ = s.cache.Set(ctx, planKey(userID), []byte(user.Plan), planCacheTTL) // Ошибка кэша не мешает запросу
+	return user.Plan, nil
+}
+
+// quotaKey - ключ счетчика запросов пользователя в окне, заканчивающемся в resetAt
+func quotaKey(userID int, resetAt time.Time) string {
+	return "quota:requests:" + strconv.Itoa(userID) + ":" + strconv.FormatInt(resetAt.Unix(), 10)
+}
+
+// planKey - ключ тарифного плана пользователя в кэше
+func planKey(userID int) string {
+	return "quota:plan:" + strconv.Itoa(userID)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+var (
+	// ErrQuoteNotFound - котировка не найдена, истекла или принадлежит другому пользователю
+	ErrQuoteNotFound = errors.New("котировка не найдена или истекла")
+	// ErrQuoteUsed - обмен по котировке уже выполнен
+	ErrQuoteUsed = errors.New("обмен по котировке уже выполнен")
+)
+
+// Префиксы ключей кэша котировок обмена
+const (
+	quoteKeyPrefix     = "exchange:quote:"      // Котировка (JSON pendingQuote)
+	quoteUsedKeyPrefix = "exchange:quote-used:" // Счетчик подтверждений (обмен выполняется один раз)
+)
+
+// pendingQuote - котировка, ожидающая подтверждения
+type pendingQuote struct {
+	Quote  *models.ExchangeQuote `json:"quote"`   // Расчет обмена
+	UserID int                   `json:"user_id"` // Пользователь, для которого рассчитан обмен
+}
+
+// QuoteService фиксирует курс и комиссию обмена на время подтверждения:
+// котировка действует ttl и выполняется не больше одного раза
+type QuoteService struct {
+	wallet *WalletService // Расчет и выполнение обмена
+	cache  storage.Cache  // Котировки, ожидающие подтверждения
+	ttl    time.Duration  // Срок действия котировки
+}
+
+// NewQuoteService создает сервис котировок обмена
+// Параметры:
+//   - wallet: сервис кошельков
+//   - cache: кэш котировок
+//   - ttl: срок действия котировки
+func NewQuoteService(wallet *WalletService, cache storage.Cache, ttl time.Duration) *QuoteService {
+	return &QuoteService{wallet: wallet, cache: cache, ttl: ttl}
+}
+
+// Quote рассчитывает обмен по текущему курсу и сохраняет котировку до подтверждения
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - fromCurrency: исходная валюта
+//   - toCurrency: целевая валюта
+//   - amount: сумма для обмена
+//
+// Возвращает:
+//   - *models.ExchangeQuote: котировка с идентификатором и сроком действия
+//   - error: некорректные параметры обмена или ошибка сохранения
+func (s *QuoteService) Quote(ctx context.Context, userID int, fromCurrency, toCurrency string, amount float64) (*models.ExchangeQuote, error) {
+	quote, err := s.wallet.PrepareExchange(ctx, userID, fromCurrency, toCurrency, amount)
+	if err != nil {
+		return nil, err
+	}
+	if quote.ID, err = randomHex(12); err != nil {
+		return nil, err
+	}
+	quote.ExpiresAt = time.Now().Add(s.ttl).UTC()
+
+	data, err := json.Marshal(pendingQuote{Quote: quote, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации котировки: %w", err)
+	}
+	if err := s.cache.Set(ctx, quoteKeyPrefix+quote.ID, data, s.ttl); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения котировки: %w", err)
+	}
+	return quote, nil
+}
+
+// Confirm выполняет обмен по котировке с зафиксированными курсом и комиссией
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь (должен совпадать с пользователем котировки)
+//   - quoteID: идентификатор котировки
+//
+// Возвращает:
+//   - *models.ExchangeResponse: результат обмена
+//   - error: ErrQuoteNotFound, ErrQuoteUsed или ошибка обмена
+func (s *QuoteService) Confirm(ctx context.Context, userID int, quoteID string) (*models.ExchangeResponse, error) {
+	data, err := s.cache.Get(ctx, quoteKeyPrefix+quoteID)
+	if err != nil {
+		return nil, ErrQuoteNotFound
+	}
+	var pending pendingQuote
+	if err := json.Unmarshal(data, &pending); err != nil || pending.Quote == nil {
+		return nil, ErrQuoteNotFound
+	}
+	if pending.UserID != userID || time.Now().After(pending.Quote.ExpiresAt) {
+		return nil, ErrQuoteNotFound
+	}
+
+	// Повторное подтверждение (двойное нажатие, несколько экземпляров сервиса) не выполняет обмен еще раз
+	used, err := s.cache.Incr(ctx, quoteUsedKeyPrefix+quoteID, s.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки котировки: %w", err)
+	}
+	if used > 1 {
+		return nil, ErrQuoteUsed
+	}
+	_ = s.cache.Delete(ctx, quoteKeyPrefix+quoteID)
+
+	pending.Quote.UserID = pending.UserID
+	return s.wallet.ExecuteExchange(ctx, pending.Quote)
+}
+
+// Cancel отменяет котировку пользователя; отмена отсутствующей котировки не считается ошибкой
+func (s *QuoteService) Cancel(ctx context.Context, userID int, quoteID string) error {
+	data, err := s.cache.Get(ctx, quoteKeyPrefix+quoteID)
+	if err != nil {
+		return nil
+	}
+	var pending pendingQuote
+	if err := json.Unmarshal(data, &pending); err != nil || pending.UserID != userID {
+		return nil
+	}
+	return s.cache.Delete(ctx, quoteKeyPrefix+quoteID)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/pdf"
+	"gw-currency-wallet/internal/storage"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidReceiptCode возвращается, если код проверки не соответствует ни одной операции
+var ErrInvalidReceiptCode = errors.New("квитанция не найдена: код проверки неверен")
+
+// receiptSignatureSize - байт подписи HMAC-SHA256 в коде проверки (80 бит)
+const receiptSignatureSize = 10
+
+// receiptEncoding - кодирование подписи в коде проверки (без неоднозначных строчных букв)
+var receiptEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// receiptEntryTitles - названия записей журнала в квитанции
+var receiptEntryTitles = map[string]string{
+	models.TransactionDeposit:          "Пополнение",
+	models.TransactionWithdraw:         "Снятие",
+	models.TransactionTransferOut:      "Исходящий перевод",
+	models.TransactionTransferIn:       "Входящий перевод",
+	models.TransactionExchangeOut:      "Списание при обмене",
+	models.TransactionExchangeIn:       "Зачисление при обмене",
+	models.TransactionOpening:          "Входящий остаток",
+	models.TransactionAdminCredit:      "Зачисление администратором",
+	models.TransactionAdminDebit:       "Списание администратором",
+	models.TransactionPromoBonus:       "Бонус по промокоду",
+	models.TransactionFee:              "Комиссия за обмен",
+	models.TransactionExchangeReversal: "Сторнирование обмена",
+}
+
+// ReceiptOptions содержит параметры квитанций
+type ReceiptOptions struct {
+	Secret  string // Ключ подписи кодов проверки HMAC-SHA256 (пусто - квитанции выключены)
+	BaseURL string // Публичный адрес сервиса для ссылки проверки (пусто - без ссылки)
+}
+
+// ReceiptService выдает квитанции о выполненных операциях и проверяет их коды
+// Код проверки - "<ID записи>-<подпись>", подпись - HMAC-SHA256 записей операции, поэтому квитанции
+// не хранятся: код проверяется пересчетом подписи по журналу, а изменение записей делает код неверным
+type ReceiptService struct {
+	ledger storage.TransactionRepository // Журнал операций
+	opts   ReceiptOptions                // Ключ подписи и адрес проверки
+}
+
+// NewReceiptService создает сервис квитанций
+// Параметры:
+//   - ledger: журнал операций (включая архив)
+//   - opts: ключ подписи и публичный адрес
+//
+// Возвращает:
+//   - *ReceiptService: инициализированный сервис
+func NewReceiptService(ledger storage.TransactionRepository, opts ReceiptOptions) *ReceiptService {
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	return &ReceiptService{ledger: ledger, opts: opts}
+}
+
+// Enabled сообщает, настроены ли квитанции
+func (s *ReceiptService) Enabled() bool {
+	return s.opts.Secret != ""
+}
+
+// Get возвращает квитанцию об операции пользователя по записи журнала
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - transactionID: запись журнала (любая запись операции)
+//
+// Возвращает:
+//   - *models.Receipt: квитанция с кодом проверки
+//   - error: storage.ErrTransactionNotFound, если записи нет или она чужая, или ошибка журнала
+func (s *ReceiptService) Get(ctx context.Context, userID int, transactionID int64) (*models.Receipt, error) {
+	entries, err := s.ledger.GetOperationEntries(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if entries[0].UserID != userID {
+		return nil, storage.ErrTransactionNotFound
+	}
+	return s.receipt(transactionID, entries), nil
+}
+
+// Verify проверяет код квитанции и возвращает данные операции
+// Вторая сторона перевода в ответе не раскрывается: код может проверить любой, кому передана квитанция
+// Параметры:
+//   - ctx: контекст выполнения
+//   - code: код проверки из квитанции (регистр не важен)
+//
+// Возвращает:
+//   - *models.Receipt: квитанция без второй стороны перевода
+//   - error: ErrInvalidReceiptCode или ошибка журнала
+func (s *ReceiptService) Verify(ctx context.Context, code string) (*models.Receipt, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	rawID, _, ok := strings.Cut(code, "-")
+	transactionID, err := strconv.ParseInt(rawID, 10, 64)
+	if !ok || err != nil || transactionID <= 0 {
+		return nil, ErrInvalidReceiptCode
+	}
+	entries, err := s.ledger.GetOperationEntries(ctx, transactionID)
+	if errors.Is(err, storage.ErrTransactionNotFound) {
+		return nil, ErrInvalidReceiptCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := s.receipt(transactionID, entries)
+	if !hmac.Equal([]byte(receipt.VerificationCode), []byte(code)) {
+		return nil, ErrInvalidReceiptCode
+	}
+	for i := range receipt.Entries {
+		receipt.Entries[i].CounterpartyID = nil
+	}
+	return receipt, nil
+}
+
+// receipt собирает квитанцию по записям операции и подписывает ее
+func (s *ReceiptService) receipt(transactionID int64, entries []models.Transaction) *models.Receipt {
+	receipt := &models.Receipt{
+		TransactionID: transactionID,
+		OperationID:   entries[0].OperationID,
+		CreatedAt:     entries[0].CreatedAt,
+		Entries:       make([]models.ReceiptEntry, len(entries)),
+		IssuedAt:      time.Now().UTC(),
+	}
+	for i, entry := range entries {
+		if entry.ID == transactionID {
+			receipt.Type, receipt.CreatedAt = entry.Type, entry.CreatedAt
+		}
+		receipt.Entries[i] = models.ReceiptEntry{
+			ID:             entry.ID,
+			Type:           entry.Type,
+			Currency:       entry.Currency,
+			Amount:         entry.Amount,
+			Rate:           entry.Rate,
+			CounterpartyID: entry.CounterpartyID,
+		}
+	}
+	receipt.VerificationCode = fmt.Sprintf("%d-%s", transactionID, s.sign(transactionID, entries))
+	if s.opts.BaseURL != "" {
+		receipt.VerifyURL = s.opts.BaseURL + "/api/v2/receipts/" + receipt.VerificationCode
+	}
+	return receipt
+}
+
+// sign возвращает подпись записей операции для кода проверки
+// Подписываются запись квитанции, владелец и все поля записей операции
+func (s *ReceiptService) sign(transactionID int64, entries []models.Transaction) string {
+	mac := hmac.New(sha256.New, []byte(s.opts.Secret))
+	fmt.Fprintf(mac, "receipt:v1\n%d\n%d\n", transactionID, entries[0].UserID)
+	for _, entry := range entries {
+		rate, counterparty := "", ""
+		if entry.Rate != nil {
+			rate = strconv.FormatFloat(*entry.Rate, 'f', 10, 64)
+		}
+		if entry.CounterpartyID != nil {
+			counterparty = strconv.Itoa(*entry.CounterpartyID)
+		}
+		fmt.Fprintf(mac, "%d|%s|%s|%s|%s|%.2f|%s|%s\n", entry.ID, entry.OperationID,
+			entry.CreatedAt.UTC().Format(time.RFC3339Nano), entry.Type, entry.Currency, entry.Amount, rate, counterparty)
+	}
+	return receiptEncoding.EncodeToString(mac.Sum(nil)[:receiptSignatureSize])
+}
+
+// WritePDF записывает квитанцию в PDF
+// Параметры:
+//   - receipt: квитанция
+//   - w: получатель документа
+//
+// Возвращает:
+//   - error: ошибка формирования или записи
+func (s *ReceiptService) WritePDF(receipt *models.Receipt, w io.Writer) error {
+	doc, err := pdf.New()
+	if err != nil {
+		return err
+	}
+	const left, right = 56.0, pdf.PageWidth - 56
+	y := pdf.PageHeight - 72
+
+	doc.Text(left, y, 18, fmt.Sprintf("Квитанция № %d", receipt.TransactionID))
+	y -= 14
+	doc.Line(left, y, right, y, 1)
+	y -= 26
+
+	for _, field := range [][2]string{
+		{"Операция", receiptEntryTitle(receipt.Type)},
+		{"Идентификатор операции", receipt.OperationID},
+		{"Дата и время (UTC)", receipt.CreatedAt.UTC().Format(time.DateTime)},
+	} {
+		doc.Text(left, y, 10, field[0])
+		doc.Text(left+170, y, 10, field[1])
+		y -= 18
+	}
+
+	// Записи операции: название, валюта, сумма, курс
+	y -= 12
+	doc.FillRect(left, y-6, right-left, 20, 0.9)
+	doc.Text(left+6, y, 10, "Запись")
+	doc.Text(left+250, y, 10, "Валюта")
+	doc.TextRight(right-110, y, 10, "Сумма")
+	doc.TextRight(right-6, y, 10, "Курс")
+	y -= 22
+	for _, entry := range receipt.Entries {
+		title := receiptEntryTitle(entry.Type)
+		if entry.CounterpartyID != nil {
+			title += fmt.Sprintf(" (пользователь № %d)", *entry.CounterpartyID)
+		}
+		doc.Text(left+6, y, 10, title)
+		doc.Text(left+250, y, 10, entry.Currency)
+		doc.TextRight(right-110, y, 10, strconv.FormatFloat(entry.Amount, 'f', 2, 64))
+		if entry.Rate != nil {
+			doc.TextRight(right-6, y, 10, strconv.FormatFloat(*entry.Rate, 'f', 6, 64))
+		}
+		y -= 18
+	}
+	doc.Line(left, y+8, right, y+8, 0.5)
+
+	y -= 24
+	doc.Text(left, y, 11, "Код проверки: "+receipt.VerificationCode)
+	if receipt.VerifyURL != "" {
+		y -= 16
+		doc.Text(left, y, 9, "Проверить квитанцию: "+receipt.VerifyURL)
+	}
+	y -= 16
+	doc.Text(left, y, 9, "Квитанция сформирована "+receipt.IssuedAt.UTC().Format(time.DateTime)+" UTC")
+
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+// receiptEntryTitle возвращает название записи журнала (тип, если название не задано)
+func receiptEntryTitle(entryType string) string {
+	if title, ok := receiptEntryTitles[entryType]; ok {
+		return title
+	}
+	return entryType
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testReceiptSecret = "0123456789abcdef0123456789abcdef"
+
+// newTestLedger возвращает журнал с обменом alice (списание, зачисление, комиссия) и переводом alice -> bob
+func newTestLedger() *fakeLedger {
+	rate, at := 0.9, time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	alice, bob := testAlice, testBob
+	return &fakeLedger{entries: []models.Transaction{
+		{ID: 10, OperationID: "exchange-1", UserID: testAlice, Type: models.TransactionExchangeOut, Currency: "USD", Amount: -100, Rate: &rate, CreatedAt: at},
+		{ID: 11, OperationID: "exchange-1", UserID: testAlice, Type: models.TransactionExchangeIn, Currency: "EUR", Amount: 90, Rate: &rate, CreatedAt: at},
+		{ID: 12, OperationID: "exchange-1", UserID: testAlice, Type: models.TransactionFee, Currency: "EUR", Amount: -0.9, CreatedAt: at},
+		{ID: 20, OperationID: "transfer-1", UserID: testAlice, Type: models.TransactionTransferOut, Currency: "USD", Amount: -25, CounterpartyID: &bob, CreatedAt: at},
+		{ID: 21, OperationID: "transfer-1", UserID: testBob, Type: models.TransactionTransferIn, Currency: "USD", Amount: 25, CounterpartyID: &alice, CreatedAt: at},
+	}}
+}
+
+func TestReceiptGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      int
+		id          int64
+		wantErr     error
+		wantType    string
+		wantEntries int
+	}{
+		{name: "квитанция по списанию обмена", userID: testAlice, id: 10, wantType: models.TransactionExchangeOut, wantEntries: 3},
+		{name: "квитанция по комиссии обмена", userID: testAlice, id: 12, wantType: models.TransactionFee, wantEntries: 3},
+		{name: "квитанция получателя перевода", userID: testBob, id: 21, wantType: models.TransactionTransferIn, wantEntries: 1},
+		{name: "чужая запись", userID: testBob, id: 10, wantErr: storage.ErrTransactionNotFound},
+		{name: "несуществующая запись", userID: testAlice, id: 99, wantErr: storage.ErrTransactionNotFound},
+	}
+
+	s := NewReceiptService(newTestLedger(), ReceiptOptions{Secret: testReceiptSecret, BaseURL: "https://wallet.example.com/"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt, err := s.Get(context.Background(), tt.userID, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if receipt.Type != tt.wantType || len(receipt.Entries) != tt.wantEntries {
+				t.Errorf("квитанция %s с %d записями, ожидалась %s с %d", receipt.Type, len(receipt.Entries), tt.wantType, tt.wantEntries)
+			}
+			if want := "https://wallet.example.com/api/v2/receipts/" + receipt.VerificationCode; receipt.VerifyURL != want {
+				t.Errorf("ссылка проверки %s, ожидалась %s", receipt.VerifyURL, want)
+			}
+		})
+	}
+}
+
+func TestReceiptVerify(t *testing.T) {
+	ctx := context.Background()
+	ledger := newTestLedger()
+	s := NewReceiptService(ledger, ReceiptOptions{Secret: testReceiptSecret})
+	exchange, err := s.Get(ctx, testAlice, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer, err := s.Get(ctx, testAlice, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exchange.VerifyURL != "" {
+		t.Errorf("ссылка проверки без публичного адреса: %s", exchange.VerifyURL)
+	}
+
+	// Подмена символа подписи
+	code := exchange.VerificationCode
+	last := "A"
+	if strings.HasSuffix(code, "A") {
+		last = "B"
+	}
+	tampered := code[:len(code)-1] + last
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{name: "код квитанции", code: code},
+		{name: "код в нижнем регистре с пробелами", code: " " + strings.ToLower(code) + " "},
+		{name: "подпись изменена", code: tampered, wantErr: ErrInvalidReceiptCode},
+		{name: "подпись другой записи", code: strings.Replace(transfer.VerificationCode, "20-", "10-", 1), wantErr: ErrInvalidReceiptCode},
+		{name: "код без подписи", code: "11", wantErr: ErrInvalidReceiptCode},
+		{name: "пустая подпись", code: "11-", wantErr: ErrInvalidReceiptCode},
+		{name: "некорректный ID", code: "abc-" + code[3:], wantErr: ErrInvalidReceiptCode},
+		{name: "несуществующая запись", code: "99-" + code[3:], wantErr: ErrInvalidReceiptCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt, err := s.Verify(ctx, tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+			}
+			if err == nil && (receipt.TransactionID != 11 || len(receipt.Entries) != 3) {
+				t.Errorf("проверена квитанция %d с %d записями", receipt.TransactionID, len(receipt.Entries))
+			}
+		})
+	}
+
+	t.Run("вторая сторона перевода скрыта", func(t *testing.T) {
+		receipt, err := s.Verify(ctx, transfer.VerificationCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.Entries[0].CounterpartyID != nil {
+			t.Errorf("проверка раскрывает получателя перевода: %d", *receipt.Entries[0].CounterpartyID)
+		}
+		if transfer.Entries[0].CounterpartyID == nil {
+			t.Error("в квитанции владельца нет получателя перевода")
+		}
+	})
+
+	t.Run("запись журнала изменена после выдачи", func(t *testing.T) {
+		ledger.entries[1].Amount = 95
+		defer func() { ledger.entries[1].Amount = 90 }()
+		if _, err := s.Verify(ctx, code); !errors.Is(err, ErrInvalidReceiptCode) {
+			t.Errorf("ошибка %v, ожидалась %v", err, ErrInvalidReceiptCode)
+		}
+	})
+
+	t.Run("другой ключ подписи", func(t *testing.T) {
+		other := NewReceiptService(ledger, ReceiptOptions{Secret: strings.Repeat("x", 32)})
+		if _, err := other.Verify(ctx, code); !errors.Is(err, ErrInvalidReceiptCode) {
+			t.Errorf("ошибка %v, ожидалась %v", err, ErrInvalidReceiptCode)
+		}
+	})
+}
+
+func TestReceiptWritePDF(t *testing.T) {
+	s := NewReceiptService(newTestLedger(), ReceiptOptions{Secret: testReceiptSecret, BaseURL: "https://wallet.example.com"})
+	receipt, err := s.Get(context.Background(), testAlice, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document bytes.Buffer
+	if err := s.WritePDF(receipt, &document); err != nil {
+		t.Fatal(err)
+	}
+	data := document.Bytes()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Errorf("документ не похож на PDF: %q...", data[:min(len(data), 16)])
+	}
+}
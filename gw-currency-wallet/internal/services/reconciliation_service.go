@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/metrics"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrReconciliationRunning возвращается, если сверка уже выполняется
+var ErrReconciliationRunning = errors.New("сверка балансов уже выполняется")
+
+// ReconciliationService сверяет балансы кошельков с журналом операций
+type ReconciliationService struct {
+	ledger     storage.TransactionRepository // Журнал операций
+	wallets    storage.WalletRepository      // Кошельки (для блокировки)
+	quarantine bool                          // Блокировать кошельки с расхождениями
+	alert      OpsAlertFunc                  // Оповещение дежурных о расхождениях (nil - только журнал)
+
+	running sync.Mutex                   // Исключает параллельные запуски сверки
+	mu      sync.RWMutex                 // Защищает last
+	last    *models.ReconciliationReport // Результат последней сверки
+}
+
+// NewReconciliationService создает сервис сверки балансов
+// Параметры:
+//   - ledger: репозиторий журнала операций
+//   - wallets: репозиторий кошельков
+//   - quarantine: блокировать ли кошельки, по которым найдены расхождения
+//   - alert: оповещение дежурных о найденных расхождениях (nil - только журнал)
+//
+// Возвращает:
+//   - *ReconciliationService: инициализированный сервис
+func NewReconciliationService(
+	ledger storage.TransactionRepository,
+	wallets storage.WalletRepository,
+	quarantine bool,
+	alert OpsAlertFunc,
+) *ReconciliationService {
+	return &ReconciliationService{
+		ledger:     ledger,
+		wallets:    wallets,
+		quarantine: quarantine,
+		alert:      alert,
+	}
+}
+
+// Run пересчитывает балансы всех кошельков по журналу и сообщает о расхождениях
+// При включенной блокировке кошельки с расхождениями переводятся в карантин
+// Параметры:
+//   - ctx: контекст выполнения
+//
+// Возвращает:
+//   - *models.ReconciliationReport: отчет о сверке
+//   - error: ErrReconciliationRunning или ошибка получения данных
+func (s *ReconciliationService) Run(ctx context.Context) (*models.ReconciliationReport, error) {
+	if !s.running.TryLock() {
+		return nil, ErrReconciliationRunning
+	}
+	defer s.running.Unlock()
+
+	report := &models.ReconciliationReport{StartedAt: time.Now()}
+
+	mismatches, err := s.ledger.FindBalanceMismatches(ctx)
+	if err != nil {
+		metrics.ReconciliationFailures.Inc()
+		return nil, err
+	}
+	report.Mismatches = mismatches
+
+	// Метрики расхождений по валютам (нулевые значения сбрасывают прошлые результаты)
+	discrepancy := map[string]float64{"USD": 0, "RUB": 0, "EUR": 0}
+	affected := make(map[int]bool)
+	for _, m := range mismatches {
+		discrepancy[m.Currency] += math.Abs(m.Difference)
+		affected[m.UserID] = true
+		log.Printf("Расхождение баланса: пользователь %d, %s: кошелек %.2f, журнал %.2f",
+			m.UserID, m.Currency, m.WalletBalance, m.LedgerBalance)
+	}
+	for currency, amount := range discrepancy {
+		metrics.ReconciliationDiscrepancy.WithLabelValues(currency).Set(amount)
+	}
+	metrics.ReconciliationMismatches.Set(float64(len(mismatches)))
+
+	if s.quarantine {
+		for userID := range affected {
+			reason := fmt.Sprintf("расхождение с журналом операций (сверка %s)", report.StartedAt.Format(time.RFC3339))
+			if err := s.wallets.SetQuarantine(ctx, userID, true, reason); err != nil {
+				log.Printf("Ошибка блокировки кошелька пользователя %d: %v", userID, err)
+				continue
+			}
+			report.Quarantined = append(report.Quarantined, userID)
+			metrics.QuarantinedWallets.Inc()
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	metrics.ReconciliationLastRun.Set(float64(report.FinishedAt.Unix()))
+	if len(mismatches) > 0 {
+		summary := fmt.Sprintf("Сверка балансов: найдено %d расхождений у %d кошельков, заблокировано %d",
+			len(mismatches), len(affected), len(report.Quarantined))
+		log.Print(summary)
+		s.alertMismatches(ctx, summary, mismatches)
+	}
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+
+	return report, nil
+}
+
+// maxAlertMismatches - число расхождений, перечисляемых в оповещении дежурных
+const maxAlertMismatches = 10
+
+// alertMismatches оповещает дежурных о расхождениях; ошибка отправки не прерывает сверку
+func (s *ReconciliationService) alertMismatches(ctx context.Context, summary string, mismatches []models.BalanceMismatch) {
+	if s.alert == nil {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("⚠️ " + summary + "\n")
+	for i, m := range mismatches {
+		if i == maxAlertMismatches {
+			sb.WriteString(fmt.Sprintf("... и еще %d\n", len(mismatches)-maxAlertMismatches))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("пользователь %d, %s: кошелек %.2f, журнал %.2f\n",
+			m.UserID, m.Currency, m.WalletBalance, m.LedgerBalance))
+	}
+	if err := s.alert(ctx, sb.String()); err != nil {
+		log.Printf("Ошибка оповещения о расхождениях: %v", err)
+	}
+}
+
+// RunJob выполняет сверку как фоновую задачу планировщика
+// Пропуск из-за уже идущей сверки не считается ошибкой
+func (s *ReconciliationService) RunJob(ctx context.Context) error {
+	_, err := s.Run(ctx)
+	if errors.Is(err, ErrReconciliationRunning) {
+		return nil
+	}
+	return err
+}
+
+// LastReport возвращает результат последней сверки или nil, если сверка еще не выполнялась
+func (s *ReconciliationService) LastReport() *models.ReconciliationReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// ReleaseWallet снимает блокировку с кошелька пользователя после разбора расхождения
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//
+// Возвращает:
+//   - error: storage.ErrWalletUnavailable, если кошелек не найден, или ошибка обновления
+func (s *ReconciliationService) ReleaseWallet(ctx context.Context, userID int) error {
+	if userID <= 0 {
+		return errors.New("неверный ID пользователя")
+	}
+	return s.wallets.SetQuarantine(ctx, userID, false, "")
+}
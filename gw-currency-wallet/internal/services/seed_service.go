@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Значения параметров генерации по умолчанию
+const (
+	defaultSeedPrefix     = "demo"          // Префикс имен пользователей
+	defaultSeedPassword   = "demo-password" // Пароль всех созданных пользователей
+	defaultSeedOperations = 20              // Операций на пользователя
+)
+
+// seedRUBRates - демонстрационные курсы к рублю, если сервис курсов не передан
+var seedRUBRates = map[string]float64{"RUB": 1, "USD": 90, "EUR": 98}
+
+// seedScale - типичная сумма операции в каждой валюте
+var seedScale = map[string]float64{"USD": 100, "EUR": 100, "RUB": 9000}
+
+// seedCurrencies - валюты, в которых генерируются операции
+var seedCurrencies = []string{"USD", "EUR", "RUB"}
+
+// SeedOptions - параметры генерации демонстрационных данных
+type SeedOptions struct {
+	Users             int    // Количество пользователей
+	OperationsPerUser int    // Операций на пользователя (0 - 20)
+	Prefix            string // Префикс имен пользователей (пусто - demo)
+	Password          string // Пароль пользователей (пусто - demo-password)
+	RandomSeed        int64  // Начальное значение генератора (0 - от текущего времени)
+}
+
+// SeedReport - итог генерации
+type SeedReport struct {
+	Usernames  []string // Созданные пользователи
+	Operations int      // Проведенные операции
+	Failed     int      // Операции, отклоненные хранилищем (например, из-за блокировки кошелька)
+}
+
+// SeedService создает пользователей со случайными балансами и историей операций
+// для нагрузочного тестирования и демонстрационных стендов
+// Операции проводятся через репозиторий кошельков и попадают в журнал, как настоящие,
+// но без антифрода и комиссий
+type SeedService struct {
+	users   storage.UserRepository   // Репозиторий пользователей
+	wallets storage.WalletRepository // Репозиторий кошельков
+	rates   RateProvider             // Курсы для обменов (nil - демонстрационные курсы)
+}
+
+// NewSeedService создает сервис генерации демонстрационных данных
+// Параметры:
+//   - users: репозиторий пользователей
+//   - wallets: репозиторий кошельков
+//   - rates: сервис курсов валют (nil - фиксированные демонстрационные курсы)
+//
+// Возвращает:
+//   - *SeedService: инициализированный сервис
+func NewSeedService(users storage.UserRepository, wallets storage.WalletRepository, rates RateProvider) *SeedService {
+	return &SeedService{users: users, wallets: wallets, rates: rates}
+}
+
+// Seed создает пользователей и проводит для них случайные операции:
+// пополнения, снятия, обмены и переводы между созданными пользователями
+// Параметры:
+//   - ctx: контекст выполнения (отмена прерывает генерацию, созданные данные остаются)
+//   - opts: параметры генерации
+//
+// Возвращает:
+//   - *SeedReport: созданные пользователи и количество операций
+//   - error: ошибка создания пользователя или получения курса
+func (s *SeedService) Seed(ctx context.Context, opts SeedOptions) (*SeedReport, error) {
+	if opts.Users <= 0 {
+		return nil, fmt.Errorf("количество пользователей должно быть положительным")
+	}
+	if opts.OperationsPerUser <= 0 {
+		opts.OperationsPerUser = defaultSeedOperations
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = defaultSeedPrefix
+	}
+	if opts.Password == "" {
+		opts.Password = defaultSeedPassword
+	}
+	if opts.RandomSeed == 0 {
+		opts.RandomSeed = time.Now().UnixNano()
+	}
+	rnd := rand.New(rand.NewSource(opts.RandomSeed))
+
+	// Хэш пароля один на всех: bcrypt на каждого пользователя сделал бы генерацию очень медленной
+	hash, err := bcrypt.GenerateFromPassword([]byte(opts.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка хэширования пароля: %w", err)
+	}
+
+	// Суффикс запуска позволяет генерировать данные повторно без конфликтов имен
+	run := fmt.Sprintf("%04x", rnd.Intn(0x10000))
+	report := &SeedReport{}
+	userIDs := make([]int, 0, opts.Users)
+	balances := make(map[int]*models.Balance, opts.Users)
+
+	for i := 1; i <= opts.Users; i++ {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		user := &models.User{
+			Username:     fmt.Sprintf("%s_%s_%d", opts.Prefix, run, i),
+			PasswordHash: string(hash),
+		}
+		user.Email = user.Username + "@example.com"
+		if err := s.users.CreateUser(ctx, user); err != nil {
+			return report, fmt.Errorf("ошибка создания пользователя %s: %w", user.Username, err)
+		}
+		userIDs = append(userIDs, user.ID)
+		balances[user.ID] = &models.Balance{}
+		report.Usernames = append(report.Usernames, user.Username)
+	}
+
+	for _, userID := range userIDs {
+		for n := 0; n < opts.OperationsPerUser; n++ {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			balance, err := s.randomOperation(ctx, rnd, userID, userIDs, balances)
+			if err != nil {
+				log.Printf("Генерация: операция пользователя %d не проведена: %v", userID, err)
+				report.Failed++
+				continue
+			}
+			balances[userID] = balance
+			report.Operations++
+		}
+	}
+	return report, nil
+}
+
+// randomOperation проводит одну случайную операцию пользователя
+// Списания выбираются в пределах известного баланса; без средств проводится пополнение
+// Возвращает новый баланс пользователя
+func (s *SeedService) randomOperation(
+	ctx context.Context,
+	rnd *rand.Rand,
+	userID int,
+	userIDs []int,
+	balances map[int]*models.Balance,
+) (*models.Balance, error) {
+	balance := balances[userID]
+	currency := seedCurrencies[rnd.Intn(len(seedCurrencies))]
+	available, _ := getBalanceByCurrency(balance, currency)
+	amount := seedAmount(rnd, currency)
+
+	roll := rnd.Intn(100)
+	if available < 1 || roll < 40 {
+		return s.wallets.UpdateBalance(ctx, userID, currency, amount)
+	}
+	amount = math.Min(amount, math.Floor(available*rnd.Float64()*100)/100)
+	if amount <= 0 {
+		return s.wallets.UpdateBalance(ctx, userID, currency, seedAmount(rnd, currency))
+	}
+
+	switch {
+	case roll < 60:
+		return s.wallets.UpdateBalance(ctx, userID, currency, -amount)
+	case roll < 85:
+		target := seedCurrencies[rnd.Intn(len(seedCurrencies))]
+		if target == currency {
+			return s.wallets.UpdateBalance(ctx, userID, currency, -amount)
+		}
+		rate, err := s.rate(ctx, currency, target)
+		if err != nil {
+			return nil, err
+		}
+		return s.wallets.Exchange(ctx, &models.ExchangeQuote{
+			UserID:          userID,
+			FromCurrency:    currency,
+			ToCurrency:      target,
+			Amount:          amount,
+			Rate:            rate,
+			ExchangedAmount: math.Round(amount*rate*100) / 100,
+		})
+	default:
+		recipient := userIDs[rnd.Intn(len(userIDs))]
+		if recipient == userID {
+			return s.wallets.UpdateBalance(ctx, userID, currency, -amount)
+		}
+		sender, receiver, err := s.wallets.Transfer(ctx, userID, recipient, currency, amount)
+		if err != nil {
+			return nil, err
+		}
+		balances[recipient] = receiver
+		return sender, nil
+	}
+}
+
+// rate возвращает курс обмена из сервиса курсов или демонстрационный
+func (s *SeedService) rate(ctx context.Context, from, to string) (float64, error) {
+	if s.rates != nil {
+		return s.rates.GetRate(ctx, from, to)
+	}
+	return seedRUBRates[from] / seedRUBRates[to], nil
+}
+
+// seedAmount возвращает случайную сумму операции в валюте (от 1% до 300% типичной суммы)
+func seedAmount(rnd *rand.Rand, currency string) float64 {
+	amount := seedScale[currency] * (0.01 + rnd.Float64()*2.99)
+	return math.Round(amount*100) / 100
+}
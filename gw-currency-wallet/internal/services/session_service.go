@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tenant"
+	"log"
+	"time"
+)
+
+var (
+	// ErrInvalidRefreshToken - токен обновления не найден, уже использован или отозван
+	ErrInvalidRefreshToken = errors.New("токен обновления недействителен")
+	// ErrSessionExpired - истек срок действия токена обновления или абсолютный срок жизни сессии
+	ErrSessionExpired = errors.New("срок действия сессии истек, войдите заново")
+)
+
+// SessionOptions - политика срока жизни сессий
+type SessionOptions struct {
+	IdleTTL     time.Duration // Скользящее окно: токен обновления продлевается при каждом использовании (0 - без продления)
+	MaxLifetime time.Duration // Абсолютный срок жизни сессии от входа по паролю (0 - без ограничения)
+}
+
+// SessionService выдает JWT токены вместе с токенами обновления и обновляет их без повторного входа
+//
+// Сессия начинается входом по паролю. Каждое обновление заменяет токен обновления следующим (ротация):
+// срок действия нового токена - IdleTTL от момента обновления, но не позже MaxLifetime от начала сессии.
+// Сроки вычисляются по текущей политике, поэтому ее ужесточение действует и на открытые сессии.
+// Повторное предъявление замененного токена означает его утечку: сессия отзывается целиком
+type SessionService struct {
+	tokens storage.RefreshTokenRepository // Токены обновления
+	users  storage.UserRepository         // Пользователи (актуальная роль для нового JWT)
+	auth   *AuthService                   // Выдача JWT токенов
+	opts   SessionOptions                 // Политика срока жизни
+}
+
+// NewSessionService создает сервис сессий
+// Параметры:
+//   - tokens: хранилище токенов обновления
+//   - users: хранилище пользователей
+//   - auth: сервис аутентификации (выдача JWT)
+//   - opts: скользящий и абсолютный сроки жизни сессии
+func NewSessionService(tokens storage.RefreshTokenRepository, users storage.UserRepository, auth *AuthService, opts SessionOptions) *SessionService {
+	return &SessionService{tokens: tokens, users: users, auth: auth, opts: opts}
+}
+
+// Start начинает сессию пользователя, прошедшего аутентификацию
+// Параметры:
+//   - ctx: контекст выполнения
+//   - user: пользователь
+//
+// Возвращает:
+//   - *models.LoginResponse: JWT токен и токен обновления
+//   - error: ошибка генерации или сохранения токенов
+func (s *SessionService) Start(ctx context.Context, user *models.User) (*models.LoginResponse, error) {
+	sessionID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	token := &models.RefreshToken{
+		SessionID:        sessionID,
+		UserID:           user.ID,
+		TokenHash:        hashCode(refreshToken),
+		SessionStartedAt: now,
+		ExpiresAt:        s.expiresAt(now, now),
+	}
+	if err := s.tokens.CreateRefreshToken(ctx, token); err != nil {
+		return nil, err
+	}
+	return s.respond(user, refreshToken, token.ExpiresAt)
+}
+
+// Refresh выдает новый JWT токен и следующий токен обновления той же сессии
+// Параметры:
+//   - ctx: контекст выполнения (арендатор запроса должен совпадать с арендатором пользователя)
+//   - refreshToken: токен обновления
+//
+// Возвращает:
+//   - *models.LoginResponse: JWT токен и следующий токен обновления
+//   - error: ErrInvalidRefreshToken, ErrSessionExpired или ошибка хранилища
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
+	current, err := s.tokens.GetRefreshToken(ctx, hashCode(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.RevokedAt != nil {
+		// Замененный токен предъявлен повторно: у кого-то есть копия, сессия отзывается
+		log.Printf("Повторное использование токена обновления пользователя %d, сессия %s отозвана", current.UserID, current.SessionID)
+		if err := s.tokens.RevokeRefreshSession(ctx, current.SessionID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	expiresAt := s.expiresAt(current.SessionStartedAt, now)
+	if !now.Before(current.ExpiresAt) || !now.Before(expiresAt) {
+		return nil, ErrSessionExpired
+	}
+
+	user, err := s.users.GetUserByID(ctx, current.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователя: %w", err)
+	}
+	if user == nil || user.TenantID != tenant.IDFromContext(ctx) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	nextToken, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	next := &models.RefreshToken{
+		SessionID:        current.SessionID,
+		UserID:           current.UserID,
+		TokenHash:        hashCode(nextToken),
+		SessionStartedAt: current.SessionStartedAt,
+		ExpiresAt:        expiresAt,
+	}
+	rotated, err := s.tokens.RotateRefreshToken(ctx, current.ID, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, ErrInvalidRefreshToken // Токен уже использован параллельным запросом
+	}
+	return s.respond(user, nextToken, next.ExpiresAt)
+}
+
+// expiresAt возвращает срок действия токена обновления, выданного в момент now сессии, начатой в startedAt
+func (s *SessionService) expiresAt(startedAt, now time.Time) time.Time {
+	absolute := startedAt.Add(s.opts.MaxLifetime)
+	if s.opts.IdleTTL <= 0 {
+		return absolute // Без продления токен действует до конца сессии
+	}
+	sliding := now.Add(s.opts.IdleTTL)
+	if s.opts.MaxLifetime > 0 && absolute.Before(sliding) {
+		return absolute
+	}
+	return sliding
+}
+
+// respond формирует ответ с новым JWT токеном и токеном обновления
+func (s *SessionService) respond(user *models.User, refreshToken string, refreshExpiresAt time.Time) (*models.LoginResponse, error) {
+	token, err := s.auth.IssueToken(user)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoginResponse{Token: token, RefreshToken: refreshToken, RefreshExpiresAt: refreshExpiresAt}, nil
+}
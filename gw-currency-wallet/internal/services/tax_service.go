@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Методы учета стоимости приобретения валюты
+const (
+	TaxMethodFIFO    = "fifo"    // Списываются первые приобретенные партии
+	TaxMethodAverage = "average" // Списывается средняя стоимость всех партий
+)
+
+// ErrInvalidTaxYear возвращается для года отчета, который еще не завершился или слишком ранний
+var ErrInvalidTaxYear = errors.New("некорректный год отчета")
+
+// taxAmountEpsilon - остаток партии, который считается нулевым (погрешность float64)
+const taxAmountEpsilon = 1e-9
+
+// taxReportHeader - заголовок CSV налогового отчета
+var taxReportHeader = []string{
+	"exchange_time_utc",
+	"operation_id",
+	"disposed_currency",
+	"disposed_amount",
+	"received_currency",
+	"received_amount",
+	"fee",
+	"report_currency",
+	"proceeds",
+	"cost_basis",
+	"gain",
+	"method",
+}
+
+// SnapshotRateProvider предоставляет курсы на конец прошедшего дня
+type SnapshotRateProvider interface {
+	// SnapshotRates возвращает стоимость единицы валюты в базовой валюте на конец дня date
+	SnapshotRates(ctx context.Context, date time.Time) (map[string]float64, error)
+}
+
+// TaxService формирует отчет о курсовых доходах и убытках от обменов валюты
+//
+// Отчет строится в валюте отчетности по всей истории журнала операций: каждая поступившая
+// иностранная валюта - партия со стоимостью приобретения, каждое списание уменьшает партии
+// выбранным методом (FIFO или средняя стоимость). Доход по обмену - выручка в валюте отчетности
+// минус стоимость приобретения проданной валюты. Для обменов с валютой отчетности используются
+// фактические суммы обмена, для остальных поступлений и кросс-обменов - курсы из снимков
+// сервиса обмена на дату операции
+type TaxService struct {
+	ledger   storage.TransactionRepository // Журнал операций
+	rates    SnapshotRateProvider          // Исторические курсы
+	currency string                        // Валюта отчетности
+	method   string                        // Метод учета стоимости приобретения
+}
+
+// NewTaxService создает сервис налоговой отчетности
+// Параметры:
+//   - ledger: репозиторий журнала операций
+//   - rates: источник курсов на прошедшие даты (снимки сервиса обмена)
+//   - currency: валюта отчетности (USD, RUB, EUR)
+//   - method: метод учета стоимости приобретения (TaxMethodFIFO, TaxMethodAverage)
+//
+// Возвращает:
+//   - *TaxService: инициализированный сервис
+func NewTaxService(ledger storage.TransactionRepository, rates SnapshotRateProvider, currency, method string) *TaxService {
+	return &TaxService{ledger: ledger, rates: rates, currency: currency, method: method}
+}
+
+// ExportTaxReport записывает отчет о курсовых доходах за завершенный календарный год (UTC) в формате CSV
+// Строка отчета - обмен, в котором продана валюта, отличная от валюты отчетности
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь
+//   - year: год отчета
+//   - w: получатель CSV
+//
+// Возвращает:
+//   - error: ErrInvalidTaxYear, ошибка журнала, курсов или записи
+func (s *TaxService) ExportTaxReport(ctx context.Context, userID, year int, w io.Writer) error {
+	// Отчет строится только за завершенный год: снимки курсов сервиса обмена есть лишь за прошедшие дни
+	if year < 2000 || year >= time.Now().UTC().Year() {
+		return fmt.Errorf("%w: %d", ErrInvalidTaxYear, year)
+	}
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	entries, err := s.ledger.ListLedger(ctx, userID, to)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(taxReportHeader); err != nil {
+		return err
+	}
+
+	calc := newTaxCalculator(s.rates, s.currency, s.method)
+	for _, operation := range groupOperations(entries) {
+		row, err := calc.apply(ctx, operation)
+		if err != nil {
+			return err
+		}
+		if row == nil || row.time.Before(from) {
+			continue
+		}
+		if err := writer.Write(row.record(s.currency, s.method)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// groupOperations объединяет записи журнала одной операции, сохраняя порядок операций
+func groupOperations(entries []models.Transaction) [][]models.Transaction {
+	var operations [][]models.Transaction
+	index := make(map[string]int)
+	for _, entry := range entries {
+		if i, ok := index[entry.OperationID]; ok {
+			operations[i] = append(operations[i], entry)
+			continue
+		}
+		index[entry.OperationID] = len(operations)
+		operations = append(operations, []models.Transaction{entry})
+	}
+	return operations
+}
+
+// taxRow - строка налогового отчета (продажа валюты при обмене)
+type taxRow struct {
+	time           time.Time
+	operationID    string
+	disposed       string
+	disposedAmount float64
+	received       string
+	receivedAmount float64
+	fee            float64
+	proceeds       float64
+	costBasis      float64
+	gain           float64
+}
+
+// record возвращает строку CSV
+func (r *taxRow) record(currency, method string) []string {
+	return []string{
+		r.time.UTC().Format(time.RFC3339),
+		r.operationID,
+		r.disposed,
+		strconv.FormatFloat(r.disposedAmount, 'f', 2, 64),
+		r.received,
+		strconv.FormatFloat(r.receivedAmount, 'f', 2, 64),
+		strconv.FormatFloat(r.fee, 'f', 2, 64),
+		currency,
+		strconv.FormatFloat(r.proceeds, 'f', 2, 64),
+		strconv.FormatFloat(r.costBasis, 'f', 2, 64),
+		strconv.FormatFloat(r.gain, 'f', 2, 64),
+		method,
+	}
+}
+
+// taxCalculator ведет партии валют при проходе по журналу
+type taxCalculator struct {
+	rates     SnapshotRateProvider          // Курсы на дату операции
+	currency  string                        // Валюта отчетности (оценки)
+	method    string                        // Метод учета стоимости приобретения
+	pools     map[string]costPool           // Партии по валютам (кроме валюты отчетности)
+	snapshots map[string]map[string]float64 // Снимки курсов по датам (YYYY-MM-DD)
+}
+
+// newTaxCalculator создает расчет партий в валюте currency методом method
+func newTaxCalculator(rates SnapshotRateProvider, currency, method string) *taxCalculator {
+	return &taxCalculator{
+		rates:     rates,
+		currency:  currency,
+		method:    method,
+		pools:     make(map[string]costPool),
+		snapshots: make(map[string]map[string]float64),
+	}
+}
+
+// apply учитывает операцию и возвращает строку отчета, если в операции продана иностранная валюта
+func (c *taxCalculator) apply(ctx context.Context, operation []models.Transaction) (*taxRow, error) {
+	var out, in *models.Transaction
+	fee := 0.0
+	for i := range operation {
+		switch operation[i].Type {
+		case models.TransactionExchangeOut:
+			out = &operation[i]
+		case models.TransactionExchangeIn:
+			in = &operation[i]
+		case models.TransactionFee:
+			fee += -operation[i].Amount
+		}
+	}
+	if out == nil || in == nil {
+		// Не обмен: поступления - партии по курсу на дату, списания уменьшают партии
+		for _, entry := range operation {
+			if err := c.move(ctx, entry.Currency, entry.Amount, entry.CreatedAt); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	return c.exchange(ctx, out, in, fee)
+}
+
+// exchange учитывает обмен: продажу исходной валюты и приобретение целевой
+func (c *taxCalculator) exchange(ctx context.Context, out, in *models.Transaction, fee float64) (*taxRow, error) {
+	report := c.currency
+	row := &taxRow{
+		time:           out.CreatedAt,
+		operationID:    out.OperationID,
+		disposed:       out.Currency,
+		disposedAmount: -out.Amount,
+		received:       in.Currency,
+		receivedAmount: in.Amount - fee,
+		fee:            fee,
+	}
+
+	// Стоимость полученной валюты (за вычетом комиссии) в валюте отчетности
+	switch {
+	case in.Currency == report:
+		row.proceeds = row.receivedAmount
+	case out.Currency == report:
+		row.proceeds = row.disposedAmount
+	default:
+		rate, err := c.rate(ctx, in.Currency, in.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		row.proceeds = row.receivedAmount * rate
+	}
+
+	if in.Currency != report {
+		c.pool(in.Currency).add(row.receivedAmount, row.proceeds)
+	}
+	if out.Currency == report {
+		return nil, nil
+	}
+
+	cost, uncovered := c.pool(out.Currency).take(row.disposedAmount)
+	// Валюта без истории приобретения (например, журнал начат позже) оценивается по выручке: доход 0
+	cost += uncovered * row.proceeds / row.disposedAmount
+	row.costBasis = roundCents(cost)
+	row.proceeds = roundCents(row.proceeds)
+	row.gain = roundCents(row.proceeds - row.costBasis)
+	return row, nil
+}
+
+// move учитывает поступление (amount > 0) или списание (amount < 0) валюты вне обмена
+func (c *taxCalculator) move(ctx context.Context, currency string, amount float64, at time.Time) error {
+	if currency == c.currency || amount == 0 {
+		return nil
+	}
+	if amount < 0 {
+		c.pool(currency).take(-amount)
+		return nil
+	}
+	rate, err := c.rate(ctx, currency, at)
+	if err != nil {
+		return err
+	}
+	c.pool(currency).add(amount, amount*rate)
+	return nil
+}
+
+// rate возвращает стоимость единицы валюты в валюте отчетности по снимку курсов на дату
+func (c *taxCalculator) rate(ctx context.Context, currency string, at time.Time) (float64, error) {
+	day := at.UTC().Format(time.DateOnly)
+	rates, ok := c.snapshots[day]
+	if !ok {
+		var err error
+		if rates, err = c.rates.SnapshotRates(ctx, at); err != nil {
+			return 0, err
+		}
+		c.snapshots[day] = rates
+	}
+	from, to := rates[currency], rates[c.currency]
+	if from == 0 || to == 0 {
+		return 0, fmt.Errorf("в снимке курсов на %s нет курса %s или %s", day, currency, c.currency)
+	}
+	return from / to, nil
+}
+
+// pool возвращает партии валюты
+func (c *taxCalculator) pool(currency string) costPool {
+	pool, ok := c.pools[currency]
+	if !ok {
+		if c.method == TaxMethodAverage {
+			pool = &averagePool{}
+		} else {
+			pool = &fifoPool{}
+		}
+		c.pools[currency] = pool
+	}
+	return pool
+}
+
+// costPool - партии одной валюты со стоимостью приобретения
+type costPool interface {
+	// add добавляет партию
+	add(amount, cost float64)
+	// take списывает сумму и возвращает ее стоимость приобретения и сумму, не покрытую партиями
+	take(amount float64) (cost, uncovered float64)
+	// held возвращает остаток валюты в партиях и его стоимость приобретения
+	held() (amount, cost float64)
+}
+
+// fifoLot - партия валюты
+type fifoLot struct {
+	amount float64 // Остаток партии
+	cost   float64 // Стоимость остатка
+}
+
+// fifoPool списывает партии в порядке приобретения
+type fifoPool struct {
+	lots []fifoLot
+}
+
+func (p *fifoPool) add(amount, cost float64) {
+	p.lots = append(p.lots, fifoLot{amount: amount, cost: cost})
+}
+
+func (p *fifoPool) take(amount float64) (float64, float64) {
+	cost := 0.0
+	for amount > taxAmountEpsilon && len(p.lots) > 0 {
+		lot := &p.lots[0]
+		if lot.amount <= amount+taxAmountEpsilon {
+			cost += lot.cost
+			amount -= lot.amount
+			p.lots = p.lots[1:]
+			continue
+		}
+		part := lot.cost * amount / lot.amount
+		cost += part
+		lot.cost -= part
+		lot.amount -= amount
+		amount = 0
+	}
+	return cost, math.Max(amount, 0)
+}
+
+func (p *fifoPool) held() (float64, float64) {
+	amount, cost := 0.0, 0.0
+	for _, lot := range p.lots {
+		amount += lot.amount
+		cost += lot.cost
+	}
+	return amount, cost
+}
+
+// averagePool списывает среднюю стоимость всех партий
+type averagePool struct {
+	amount float64 // Остаток валюты
+	cost   float64 // Стоимость остатка
+}
+
+func (p *averagePool) add(amount, cost float64) {
+	p.amount += amount
+	p.cost += cost
+}
+
+func (p *averagePool) take(amount float64) (float64, float64) {
+	covered := math.Min(amount, p.amount)
+	if covered <= taxAmountEpsilon {
+		return 0, amount
+	}
+	cost := p.cost * covered / p.amount
+	p.amount -= covered
+	p.cost -= cost
+	if p.amount <= taxAmountEpsilon {
+		p.amount, p.cost = 0, 0
+	}
+	return cost, amount - covered
+}
+
+func (p *averagePool) held() (float64, float64) {
+	return p.amount, p.cost
+}
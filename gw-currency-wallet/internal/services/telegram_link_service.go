@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidLinkCode - код привязки не найден или истек
+	ErrInvalidLinkCode = errors.New("код привязки не найден или истек")
+	// ErrTelegramNotLinked - аккаунт Telegram не привязан к пользователю
+	ErrTelegramNotLinked = errors.New("аккаунт Telegram не привязан")
+)
+
+// telegramLinkKeyPrefix - префикс ключей кэша кодов привязки Telegram (значение - ID пользователя)
+const telegramLinkKeyPrefix = "telegram:link:"
+
+// TelegramLinkService привязывает аккаунты Telegram к пользователям кошелька:
+// пользователь получает одноразовый код в API и отправляет его боту командой /link
+type TelegramLinkService struct {
+	links   storage.TelegramLinkRepository // Привязки аккаунтов
+	cache   storage.Cache                  // Коды привязки
+	codeTTL time.Duration                  // Время действия кода
+}
+
+// NewTelegramLinkService создает сервис привязки аккаунтов Telegram
+// Параметры:
+//   - links: хранилище привязок
+//   - cache: кэш кодов привязки
+//   - codeTTL: время действия кода привязки
+func NewTelegramLinkService(links storage.TelegramLinkRepository, cache storage.Cache, codeTTL time.Duration) *TelegramLinkService {
+	return &TelegramLinkService{links: links, cache: cache, codeTTL: codeTTL}
+}
+
+// CreateCode выдает пользователю одноразовый код привязки аккаунта Telegram
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: пользователь кошелька
+//
+// Возвращает:
+//   - string: код для команды /link
+//   - time.Time: срок действия кода
+//   - error: ошибка генерации или сохранения кода
+func (s *TelegramLinkService) CreateCode(ctx context.Context, userID int) (string, time.Time, error) {
+	code, err := randomHex(8)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	code = strings.ToUpper(code)
+
+	expiresAt := time.Now().Add(s.codeTTL)
+	if err := s.cache.Set(ctx, telegramLinkKeyPrefix+code, []byte(strconv.Itoa(userID)), s.codeTTL); err != nil {
+		return "", time.Time{}, fmt.Errorf("ошибка сохранения кода привязки: %w", err)
+	}
+	return code, expiresAt, nil
+}
+
+// Link привязывает аккаунт Telegram по коду; код действует один раз
+// Параметры:
+//   - ctx: контекст выполнения
+//   - code: код из API
+//   - telegramUserID: идентификатор пользователя Telegram
+//
+// Возвращает:
+//   - int: пользователь кошелька
+//   - error: ErrInvalidLinkCode или ошибка хранилища
+func (s *TelegramLinkService) Link(ctx context.Context, code string, telegramUserID int64) (int, error) {
+	key := telegramLinkKeyPrefix + strings.ToUpper(strings.TrimSpace(code))
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return 0, ErrInvalidLinkCode
+	}
+	userID, err := strconv.Atoi(string(value))
+	if err != nil {
+		return 0, ErrInvalidLinkCode
+	}
+	if err := s.cache.Delete(ctx, key); err != nil {
+		return 0, fmt.Errorf("ошибка удаления кода привязки: %w", err)
+	}
+
+	if err := s.links.LinkTelegram(ctx, userID, telegramUserID); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// LinkedUser возвращает пользователя кошелька, к которому привязан аккаунт Telegram
+// Возвращает ErrTelegramNotLinked, если аккаунт не привязан
+func (s *TelegramLinkService) LinkedUser(ctx context.Context, telegramUserID int64) (int, error) {
+	userID, err := s.links.GetLinkedUser(ctx, telegramUserID)
+	if err != nil {
+		return 0, err
+	}
+	if userID == 0 {
+		return 0, ErrTelegramNotLinked
+	}
+	return userID, nil
+}
+
+// Unlink снимает привязку аккаунта Telegram пользователя
+// Возвращает ErrTelegramNotLinked, если привязки не было
+func (s *TelegramLinkService) Unlink(ctx context.Context, userID int) error {
+	unlinked, err := s.links.UnlinkTelegram(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !unlinked {
+		return ErrTelegramNotLinked
+	}
+	return nil
+}
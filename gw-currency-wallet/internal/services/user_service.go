@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+)
+
+// ErrInvalidUserSearch возвращается для некорректных параметров поиска пользователей
+var ErrInvalidUserSearch = errors.New("некорректные параметры поиска")
+
+// Размер страницы поиска пользователей
+const (
+	defaultUserSearchLimit = 50
+	maxUserSearchLimit     = 200
+)
+
+// UserService предоставляет администраторам поиск пользователей
+type UserService struct {
+	users storage.UserRepository // Репозиторий пользователей
+}
+
+// NewUserService создает сервис поиска пользователей
+// Параметры:
+//   - users: репозиторий пользователей
+//
+// Возвращает:
+//   - *UserService: инициализированный сервис
+func NewUserService(users storage.UserRepository) *UserService {
+	return &UserService{users: users}
+}
+
+// Search ищет пользователей по префиксу имени или email, статусу верификации, роли, плану и периоду регистрации
+// По умолчанию новые пользователи выводятся первыми, страница - 50 пользователей (не более 200)
+// Параметры:
+//   - ctx: контекст выполнения
+//   - search: фильтры, сортировка и страница
+//
+// Возвращает:
+//   - []models.User: пользователи страницы
+//   - int: количество пользователей, подходящих под фильтры
+//   - error: ErrInvalidUserSearch или ошибка поиска
+func (s *UserService) Search(ctx context.Context, search models.UserSearch) ([]models.User, int, error) {
+	switch search.KYCStatus {
+	case "", models.KYCNone, models.KYCPending, models.KYCVerified, models.KYCRejected:
+	default:
+		return nil, 0, fmt.Errorf("%w: неизвестный статус верификации %s", ErrInvalidUserSearch, search.KYCStatus)
+	}
+	switch search.Role {
+	case "", models.RoleUser, models.RoleAdmin:
+	default:
+		return nil, 0, fmt.Errorf("%w: неизвестная роль %s", ErrInvalidUserSearch, search.Role)
+	}
+	switch search.Plan {
+	case "", models.PlanFree, models.PlanPremium:
+	default:
+		return nil, 0, fmt.Errorf("%w: неизвестный тарифный план %s", ErrInvalidUserSearch, search.Plan)
+	}
+	switch search.Sort {
+	case "":
+		search.Sort, search.Desc = models.UserSortCreatedAt, true
+	case models.UserSortID, models.UserSortCreatedAt, models.UserSortUsername, models.UserSortEmail:
+	default:
+		return nil, 0, fmt.Errorf("%w: неизвестное поле сортировки %s", ErrInvalidUserSearch, search.Sort)
+	}
+	if !search.From.IsZero() && !search.To.IsZero() && !search.From.Before(search.To) {
+		return nil, 0, fmt.Errorf("%w: начало периода регистрации должно быть раньше конца", ErrInvalidUserSearch)
+	}
+	if search.Offset < 0 {
+		return nil, 0, fmt.Errorf("%w: отрицательное смещение", ErrInvalidUserSearch)
+	}
+	if search.Limit <= 0 || search.Limit > maxUserSearchLimit {
+		search.Limit = defaultUserSearchLimit
+	}
+
+	return s.users.SearchUsers(ctx, search)
+}
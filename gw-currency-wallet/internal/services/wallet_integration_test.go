@@ -0,0 +1,145 @@
+//go:build integration
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/testenv"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWalletIntegration проходит путь пользователя на PostgreSQL, Redis и заглушке сервиса обмена:
+// регистрация, вход, пополнение, обмен и баланс, сверенный с журналом операций
+func TestWalletIntegration(t *testing.T) {
+	env := testenv.New(t)
+	ctx := context.Background()
+	auth := services.NewAuthService(env.DB.GetUserRepository(), "integration-secret", time.Hour, nil)
+	wallet := services.NewWalletService(env.DB.GetWalletRepository(), env.DB.GetUserRepository(), env.Rates,
+		nil, nil, nil, nil, nil, nil, nil)
+
+	t.Run("сервис обмена недоступен", func(t *testing.T) {
+		user := env.CreateUser(t, "offline", testenv.Funds{"USD": 100})
+		env.Exchanger.FailWith(status.Error(codes.Unavailable, "сервис обмена остановлен"))
+		defer env.Exchanger.FailWith(nil)
+
+		// Подтест выполняется первым: курсы еще не запрашивались и не сохранены в кэше
+		if _, err := wallet.Exchange(ctx, user.ID, "USD", "RUB", 10); err == nil {
+			t.Fatal("обмен выполнен без курса")
+		}
+		if balance := env.Balance(t, user); *balance != (models.Balance{USD: 100}) {
+			t.Errorf("баланс изменился: %+v", balance)
+		}
+	})
+
+	t.Run("регистрация, пополнение, обмен, баланс", func(t *testing.T) {
+		username := fmt.Sprintf("flow_%d", time.Now().UnixNano())
+		user, err := auth.Register(ctx, models.CreateUserRequest{
+			Username: username,
+			Email:    username + "@example.com",
+			Password: "integration-password",
+		})
+		if err != nil {
+			t.Fatalf("ошибка регистрации: %v", err)
+		}
+		if _, err := auth.Authenticate(ctx, username, "integration-password"); err != nil {
+			t.Fatalf("ошибка входа: %v", err)
+		}
+
+		balance, err := wallet.Deposit(ctx, user.ID, "USD", 100)
+		if err != nil {
+			t.Fatalf("ошибка пополнения: %v", err)
+		}
+		if balance.USD != 100 {
+			t.Fatalf("баланс после пополнения %+v", balance)
+		}
+
+		exchange, err := wallet.Exchange(ctx, user.ID, "USD", "EUR", 50)
+		if err != nil {
+			t.Fatalf("ошибка обмена: %v", err)
+		}
+		// Курс заглушки - кросс-курс через рубль
+		wantRate := testenv.DefaultRates["USD"] / testenv.DefaultRates["EUR"]
+		if math.Abs(exchange.Rate-wantRate) > 1e-6 {
+			t.Errorf("курс обмена %v, ожидался %v", exchange.Rate, wantRate)
+		}
+		if want := math.Round(50*exchange.Rate*100) / 100; exchange.ExchangedAmount != want {
+			t.Errorf("получено %v EUR, ожидалось %v", exchange.ExchangedAmount, want)
+		}
+
+		balance, err = wallet.GetBalance(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("ошибка получения баланса: %v", err)
+		}
+		if *balance != (models.Balance{USD: 50, EUR: exchange.ExchangedAmount}) {
+			t.Errorf("баланс %+v, ожидался USD 50, EUR %v", balance, exchange.ExchangedAmount)
+		}
+
+		// Сумма журнала по каждой валюте совпадает с балансом
+		entries, err := env.DB.GetTransactionRepository().ListTransactions(ctx, user.ID,
+			time.Now().Add(-time.Hour), time.Now().Add(time.Hour), 100)
+		if err != nil {
+			t.Fatalf("ошибка чтения журнала: %v", err)
+		}
+		totals := map[string]float64{}
+		for _, entry := range entries {
+			totals[entry.Currency] += entry.Amount
+		}
+		if math.Abs(totals["USD"]-balance.USD) > 1e-9 || math.Abs(totals["EUR"]-balance.EUR) > 1e-9 {
+			t.Errorf("журнал %v не сходится с балансом %+v", totals, balance)
+		}
+	})
+
+	t.Run("обмен сверх баланса", func(t *testing.T) {
+		user := env.CreateUser(t, "short", testenv.Funds{"USD": 100})
+		if _, err := wallet.Exchange(ctx, user.ID, "USD", "EUR", 150); !errors.Is(err, storage.ErrInsufficientFunds) {
+			t.Fatalf("ошибка %v, ожидалась %v", err, storage.ErrInsufficientFunds)
+		}
+		if balance := env.Balance(t, user); *balance != (models.Balance{USD: 100}) {
+			t.Errorf("баланс изменился: %+v", balance)
+		}
+	})
+
+	t.Run("параллельные снятия не уводят баланс в минус", func(t *testing.T) {
+		user := env.CreateUser(t, "race", testenv.Funds{"USD": 100})
+
+		const attempts = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, attempts)
+		for range attempts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := wallet.Withdraw(ctx, user.ID, "USD", 30)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		succeeded := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case !errors.Is(err, storage.ErrInsufficientFunds):
+				t.Errorf("неожиданная ошибка снятия: %v", err)
+			}
+		}
+		if succeeded != 3 {
+			t.Errorf("выполнено снятий: %d, ожидалось 3", succeeded)
+		}
+		if balance := env.Balance(t, user); *balance != (models.Balance{USD: 10}) {
+			t.Errorf("баланс %+v, ожидался USD 10", balance)
+		}
+	})
+}
@@ -2,40 +2,145 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"gw-currency-wallet/internal/metrics"
+	"gw-currency-wallet/internal/middleware"
 	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/risk"
 	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tenant"
+	"gw-currency-wallet/internal/tradinghours"
 	"log"
+	"time"
 )
 
+// ErrUnsupportedCurrency возвращается для валюты, которую кошелек не поддерживает
+var ErrUnsupportedCurrency = errors.New("неподдерживаемая валюта")
+
+// ErrRatesUnavailable возвращается, если сервис курсов не ответил
+var ErrRatesUnavailable = errors.New("курсы валют недоступны")
+
+// ErrOperationDenied возвращается, если антифрод отклонил операцию
+var ErrOperationDenied = errors.New("операция отклонена системой безопасности")
+
+// ReviewPendingError возвращается, если операция отложена до проверки администратором
+type ReviewPendingError struct {
+	ReviewID int    // Идентификатор проверки в очереди
+	Reason   string // Причина отправки на проверку
+}
+
+// Error реализует интерфейс error
+func (e *ReviewPendingError) Error() string {
+	return fmt.Sprintf("операция отправлена на проверку (№%d): %s", e.ReviewID, e.Reason)
+}
+
+// ErrTradingClosed возвращается, если обмен пары запрошен вне окна исполнения
+var ErrTradingClosed = errors.New("обмен пары вне окна исполнения")
+
+// TradingClosedError возвращается, если обмен пары запрошен вне окна исполнения; оборачивает ErrTradingClosed
+type TradingClosedError struct {
+	FromCurrency string     // Исходная валюта
+	ToCurrency   string     // Целевая валюта
+	NextOpen     *time.Time // Начало ближайшего окна (nil - окно не открывается)
+}
+
+// Error реализует интерфейс error
+func (e *TradingClosedError) Error() string {
+	if e.NextOpen == nil {
+		return fmt.Sprintf("обмен %s/%s сейчас не исполняется: окно исполнения пары не открывается", e.FromCurrency, e.ToCurrency)
+	}
+	return fmt.Sprintf("обмен %s/%s вне окна исполнения, ближайшее открытие %s",
+		e.FromCurrency, e.ToCurrency, e.NextOpen.Format("02.01.2006 15:04 MST"))
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrTradingClosed)
+func (e *TradingClosedError) Unwrap() error {
+	return ErrTradingClosed
+}
+
 // RateProvider определяет интерфейс для работы с сервисом курсов валют
 // Это позволяет абстрагироваться от конкретной реализации и легко подменять сервис курсов
 type RateProvider interface {
 	GetRate(ctx context.Context, fromCurrency, toCurrency string) (float64, error)
 }
 
+// ExchangeRateProvider предоставляет курс для обмена вместе с его происхождением (источники и время получения),
+// которое сохраняется в аудите обмена
+type ExchangeRateProvider interface {
+	RateProvider
+	GetRateWithProvenance(ctx context.Context, fromCurrency, toCurrency string) (float64, models.RateProvenance, error)
+}
+
+// FeePolicy определяет комиссию обмена для пользователя
+type FeePolicy interface {
+	// ExchangeFeePercent возвращает комиссию в процентах и название уровня пользователя
+	ExchangeFeePercent(ctx context.Context, userID int) (float64, string, error)
+}
+
 // WalletService реализует бизнес-логику работы с кошельком пользователя
 type WalletService struct {
 	repo        storage.WalletRepository // Репозиторий для работы с данными кошелька
-	rateService RateProvider             // Сервис для получения курсов валют
+	users       storage.UserRepository   // Репозиторий пользователей (поиск получателя перевода)
+	rateService ExchangeRateProvider     // Сервис для получения курсов валют
+	risk        risk.Evaluator           // Оценка риска снятий и переводов (nil - проверка отключена)
+	reviews     storage.ReviewRepository // Очередь операций, отложенных до проверки
+	fees        FeePolicy                // Комиссия обмена (nil - без комиссии)
+	tenants     *tenant.Registry         // Арендаторы и доступные им валюты (nil - все поддерживаемые валюты)
+	operations  *OperationService        // Многошаговые операции с компенсацией (nil - без сохранения состояния)
+	freezes     *CurrencyFreezeService   // Приостановки операций в валютах (nil - без приостановок)
+	hours       *tradinghours.Schedule   // Окна исполнения обменов (nil - круглосуточно)
 }
 
 // NewWalletService создает новый экземпляр WalletService
 // Параметры:
 //   - repo: репозиторий для работы с хранилищем кошельков
+//   - users: репозиторий пользователей
 //   - rateService: сервис для получения курсов валют
+//   - evaluator: антифрод для снятий и переводов (nil - проверка отключена)
+//   - reviews: очередь проверки операций, отложенных антифродом
+//   - fees: политика комиссии обмена (nil - обмен без комиссии)
+//   - tenants: арендаторы, валюты операций ограничиваются валютами арендатора пользователя (nil - без ограничения)
+//   - operations: сервис многошаговых операций, в нем регистрируется компенсация обмена (nil - без сохранения состояния)
+//   - freezes: приостановки операций в валютах администраторами (nil - без приостановок)
+//   - hours: окна исполнения обменов по парам (nil - обмены исполняются круглосуточно)
 //
 // Возвращает:
 //   - *WalletService: инициализированный сервис работы с кошельком
-func NewWalletService(repo storage.WalletRepository, rateService RateProvider) *WalletService {
-	return &WalletService{
+func NewWalletService(
+	repo storage.WalletRepository,
+	users storage.UserRepository,
+	rateService ExchangeRateProvider,
+	evaluator risk.Evaluator,
+	reviews storage.ReviewRepository,
+	fees FeePolicy,
+	tenants *tenant.Registry,
+	operations *OperationService,
+	freezes *CurrencyFreezeService,
+	hours *tradinghours.Schedule,
+) *WalletService {
+	s := &WalletService{
 		repo:        repo,
+		users:       users,
 		rateService: rateService,
+		risk:        evaluator,
+		reviews:     reviews,
+		fees:        fees,
+		tenants:     tenants,
+		operations:  operations,
+		freezes:     freezes,
+		hours:       hours,
+	}
+	if operations != nil {
+		// Шаги обмена: обмен в транзакции, проверка примененного курса
+		operations.Register(models.OperationKindExchange, s.reverseExchange, nil)
 	}
+	return s
 }
 
 // GetBalance возвращает баланс пользователя по всем валютам
+// Баланс может читаться из кэша балансов; проверка средств перед операциями его не использует
 // Параметры:
 //   - ctx: контекст выполнения
 //   - userID: идентификатор пользователя
@@ -48,10 +153,84 @@ func (s *WalletService) GetBalance(ctx context.Context, userID int) (*models.Bal
 		return nil, errors.New("неверный ID пользователя") // Валидация входных данных
 	}
 
-	return s.repo.GetBalance(ctx, userID) // Делегируем получение баланса репозиторию
+	return s.repo.GetCachedBalance(ctx, userID) // Делегируем получение баланса репозиторию (через кэш балансов, если он включен)
+}
+
+// MaxBalanceListSize - максимальное количество балансов в одном запросе списка балансов
+const MaxBalanceListSize = 500
+
+// ListBalances возвращает балансы нескольких пользователей одним запросом (администрирование и отчеты)
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userIDs: пользователи, не более MaxBalanceListSize (пусто - все пользователи страницами)
+//   - afterID: ID пользователя, после которого продолжается список (next_after_id предыдущей страницы)
+//   - limit: размер страницы (по умолчанию 100, не более MaxBalanceListSize)
+//
+// Возвращает:
+//   - []models.UserBalance: балансы по возрастанию ID пользователя
+//   - int: afterID следующей страницы (0 - страница последняя)
+//   - error: ошибка при получении балансов
+func (s *WalletService) ListBalances(ctx context.Context, userIDs []int, afterID, limit int) ([]models.UserBalance, int, error) {
+	if len(userIDs) > 0 {
+		// Балансы пользователей из списка возвращаются одной страницей
+		limit = len(userIDs)
+	} else if limit <= 0 || limit > MaxBalanceListSize {
+		limit = 100
+	}
+
+	balances, err := s.repo.ListBalances(ctx, userIDs, max(afterID, 0), limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	next := 0
+	if len(userIDs) == 0 && len(balances) == limit {
+		next = balances[len(balances)-1].UserID
+	}
+	return balances, next, nil
+}
+
+// TotalBalance оценивает все балансы пользователя в одной валюте по текущим курсам
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - currency: валюта оценки (USD, RUB, EUR)
+//
+// Возвращает:
+//   - *models.BalanceTotal: итог и оценка по каждой валюте
+//   - error: ErrUnsupportedCurrency, ErrRatesUnavailable или ошибка получения баланса
+func (s *WalletService) TotalBalance(ctx context.Context, userID int, currency string) (*models.BalanceTotal, error) {
+	if !isValidCurrency(currency) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+
+	balance, err := s.GetBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	total := &models.BalanceTotal{Currency: currency, ValuedAt: time.Now()}
+	decimals := currencyDecimals(ctx, s.rateService, currency)
+	for _, holding := range []models.BalanceValuation{
+		{Currency: "USD", Amount: balance.USD},
+		{Currency: "RUB", Amount: balance.RUB},
+		{Currency: "EUR", Amount: balance.EUR},
+	} {
+		holding.Rate = 1
+		if holding.Currency != currency {
+			if holding.Rate, err = s.rateService.GetRate(ctx, holding.Currency, currency); err != nil {
+				return nil, fmt.Errorf("%w: %s->%s: %v", ErrRatesUnavailable, holding.Currency, currency, err)
+			}
+		}
+		holding.Value = roundAmount(holding.Amount*holding.Rate, decimals)
+		total.Total += holding.Value
+		total.Breakdown = append(total.Breakdown, holding)
+	}
+	total.Total = roundAmount(total.Total, decimals)
+	return total, nil
 }
 
 // Deposit пополняет баланс пользователя в указанной валюте
+// Пополнение в приостановленной валюте возвращает ErrCurrencyFrozen
 // Параметры:
 //   - ctx: контекст выполнения
 //   - userID: идентификатор пользователя
@@ -67,7 +246,7 @@ func (s *WalletService) Deposit(ctx context.Context, userID int, currency string
 		return nil, errors.New("неверный ID пользователя")
 	}
 
-	if !isValidCurrency(currency) {
+	if !s.currencyAllowed(ctx, currency) {
 		return nil, fmt.Errorf("неподдерживаемая валюта: %s", currency)
 	}
 
@@ -75,11 +254,18 @@ func (s *WalletService) Deposit(ctx context.Context, userID int, currency string
 		return nil, errors.New("сумма должна быть положительной")
 	}
 
+	if err := s.freezes.Check(ctx, currency); err != nil {
+		return nil, err
+	}
+
 	// Выполняем операцию пополнения через репозиторий
 	return s.repo.UpdateBalance(ctx, userID, currency, amount)
 }
 
 // Withdraw снимает средства с баланса пользователя
+// Перед снятием операция оценивается антифродом: отклоненная возвращает ErrOperationDenied,
+// отложенная до проверки - *ReviewPendingError;
+// операция в приостановленной валюте - ErrCurrencyFrozen
 // Параметры:
 //   - ctx: контекст выполнения
 //   - userID: идентификатор пользователя
@@ -95,7 +281,7 @@ func (s *WalletService) Withdraw(ctx context.Context, userID int, currency strin
 		return nil, errors.New("неверный ID пользователя")
 	}
 
-	if !isValidCurrency(currency) {
+	if !s.currencyAllowed(ctx, currency) {
 		return nil, fmt.Errorf("неподдерживаемая валюта: %s", currency)
 	}
 
@@ -103,27 +289,252 @@ func (s *WalletService) Withdraw(ctx context.Context, userID int, currency strin
 		return nil, errors.New("сумма должна быть положительной")
 	}
 
-	// Получаем текущий баланс
+	if err := s.freezes.Check(ctx, currency); err != nil {
+		return nil, err
+	}
+
+	// Проверяем достаточность средств до оценки риска, чтобы не ставить в очередь заведомо невыполнимые операции
+	if err := s.ensureFunds(ctx, userID, currency, amount); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRisk(ctx, risk.Operation{
+		Type:     risk.OperationWithdraw,
+		UserID:   userID,
+		Currency: currency,
+		Amount:   amount,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Выполняем операцию снятия (передаем отрицательное значение)
+	return s.repo.UpdateBalance(ctx, userID, currency, -amount)
+}
+
+// Transfer переводит средства другому пользователю
+// Перед переводом операция оценивается антифродом: отклоненная возвращает ErrOperationDenied,
+// отложенная до проверки - *ReviewPendingError;
+// операция в приостановленной валюте - ErrCurrencyFrozen
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор отправителя
+//   - toUsername: имя пользователя получателя
+//   - currency: валюта перевода (USD, RUB, EUR)
+//   - amount: сумма перевода
+//
+// Возвращает:
+//   - *models.Balance: новый баланс отправителя
+//   - error: ошибка при выполнении операции
+func (s *WalletService) Transfer(
+	ctx context.Context,
+	userID int,
+	toUsername string,
+	currency string,
+	amount float64,
+) (*models.Balance, error) {
+	// Валидация входных параметров
+	if userID <= 0 {
+		return nil, errors.New("неверный ID пользователя")
+	}
+
+	if !s.currencyAllowed(ctx, currency) {
+		return nil, fmt.Errorf("неподдерживаемая валюта: %s", currency)
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("сумма должна быть положительной")
+	}
+
+	if err := s.freezes.Check(ctx, currency); err != nil {
+		return nil, err
+	}
+
+	// Переводы возможны только между пользователями одного арендатора
+	recipient, err := s.users.GetUserByUsername(ctx, tenant.IDFromContext(ctx), toUsername)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска получателя: %w", err)
+	}
+	if recipient == nil {
+		return nil, errors.New("получатель не найден")
+	}
+	if recipient.ID == userID {
+		return nil, errors.New("нельзя перевести средства самому себе")
+	}
+
+	if err := s.ensureFunds(ctx, userID, currency, amount); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRisk(ctx, risk.Operation{
+		Type:        risk.OperationTransfer,
+		UserID:      userID,
+		RecipientID: recipient.ID,
+		Currency:    currency,
+		Amount:      amount,
+	}); err != nil {
+		return nil, err
+	}
+
+	fromBalance, _, err := s.repo.Transfer(ctx, userID, recipient.ID, currency, amount)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка перевода: %w", err)
+	}
+	return fromBalance, nil
+}
+
+// ensureFunds проверяет, что на балансе пользователя достаточно средств
+// Это быстрая проверка до антифрода и очередей: она не защищает от параллельных списаний,
+// окончательно средства проверяются в транзакции изменения баланса (storage.ErrInsufficientFunds)
+func (s *WalletService) ensureFunds(ctx context.Context, userID int, currency string, amount float64) error {
 	balance, err := s.repo.GetBalance(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка получения баланса: %w", err)
+		return fmt.Errorf("ошибка получения баланса: %w", err)
 	}
 
-	// Проверяем достаточность средств
 	currentBalance, err := getBalanceByCurrency(balance, currency)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if currentBalance < amount {
-		return nil, errors.New("недостаточно средств")
+		return storage.ErrInsufficientFunds
 	}
+	return nil
+}
 
-	// Выполняем операцию снятия (передаем отрицательное значение)
-	return s.repo.UpdateBalance(ctx, userID, currency, -amount)
+// checkRisk оценивает операцию антифродом
+// Возвращает nil для разрешенной операции, ErrOperationDenied для отклоненной
+// и *ReviewPendingError для операции, поставленной в очередь проверки
+// (в пробном режиме операция в очередь не ставится, ReviewID = 0)
+func (s *WalletService) checkRisk(ctx context.Context, op risk.Operation) error {
+	if s.risk == nil {
+		return nil
+	}
+
+	decision, err := s.risk.Evaluate(ctx, op)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки операции: %w", err)
+	}
+
+	switch decision.Action {
+	case risk.ActionDeny:
+		log.Printf("Антифрод отклонил %s пользователя %d (%s): %s", op.Type, op.UserID, decision.Rule, decision.Reason)
+		return fmt.Errorf("%w: %s", ErrOperationDenied, decision.Reason)
+
+	case risk.ActionReview:
+		if storage.IsDryRun(ctx) {
+			return &ReviewPendingError{Reason: decision.Reason}
+		}
+		review := &models.RiskReview{
+			UserID:    op.UserID,
+			Operation: op.Type,
+			Currency:  op.Currency,
+			Amount:    op.Amount,
+			Rule:      decision.Rule,
+			Reason:    decision.Reason,
+		}
+		if op.RecipientID > 0 {
+			review.RecipientID = &op.RecipientID
+		}
+		if err := s.reviews.CreateReview(ctx, review); err != nil {
+			return err
+		}
+		log.Printf("Антифрод отправил %s пользователя %d на проверку №%d (%s): %s",
+			op.Type, op.UserID, review.ID, decision.Rule, decision.Reason)
+		return &ReviewPendingError{ReviewID: review.ID, Reason: decision.Reason}
+	}
+
+	return nil
+}
+
+// ListReviews возвращает операции из очереди проверки антифрода
+// Параметры:
+//   - ctx: контекст выполнения
+//   - status: статус проверок (пустая строка - все)
+//   - limit: максимальное количество записей (0 - 100)
+//
+// Возвращает:
+//   - []models.RiskReview: проверки от старых к новым
+//   - error: ошибка получения данных
+func (s *WalletService) ListReviews(ctx context.Context, status string, limit int) ([]models.RiskReview, error) {
+	switch status {
+	case "", models.ReviewPending, models.ReviewApproved, models.ReviewRejected, models.ReviewFailed:
+	default:
+		return nil, fmt.Errorf("неизвестный статус проверки: %s", status)
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.reviews.ListReviews(ctx, status, limit)
+}
+
+// ResolveReview применяет решение администратора по отложенной операции
+// При одобрении операция выполняется без повторной оценки риска; если выполнить ее
+// не удалось (например, средств уже недостаточно), проверка получает статус failed
+// Параметры:
+//   - ctx: контекст выполнения
+//   - reviewID: идентификатор проверки
+//   - adminID: администратор, принимающий решение
+//   - approve: true - выполнить операцию, false - отклонить
+//   - comment: комментарий администратора
+//
+// Возвращает:
+//   - *models.RiskReview: проверка с итоговым статусом
+//   - error: storage.ErrReviewNotFound или ошибка обновления
+func (s *WalletService) ResolveReview(
+	ctx context.Context,
+	reviewID int,
+	adminID int,
+	approve bool,
+	comment string,
+) (*models.RiskReview, error) {
+	if !approve {
+		return s.reviews.TransitionReview(ctx, reviewID, models.ReviewPending, models.ReviewRejected, adminID, comment)
+	}
+
+	// Сначала фиксируем одобрение: это исключает повторное выполнение при параллельных решениях
+	review, err := s.reviews.TransitionReview(ctx, reviewID, models.ReviewPending, models.ReviewApproved, adminID, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.executeReview(ctx, review); err != nil {
+		log.Printf("Ошибка выполнения одобренной операции №%d: %v", reviewID, err)
+		return s.reviews.TransitionReview(ctx, reviewID, models.ReviewApproved, models.ReviewFailed, adminID,
+			"ошибка выполнения: "+err.Error())
+	}
+
+	log.Printf("Операция №%d одобрена администратором %d и выполнена", reviewID, adminID)
+	return review, nil
+}
+
+// executeReview выполняет одобренную операцию без повторной оценки риска
+// Достаточность средств проверяется заново: баланс мог измениться за время проверки
+func (s *WalletService) executeReview(ctx context.Context, review *models.RiskReview) error {
+	if err := s.freezes.Check(ctx, review.Currency); err != nil {
+		return err
+	}
+	if err := s.ensureFunds(ctx, review.UserID, review.Currency, review.Amount); err != nil {
+		return err
+	}
+
+	switch review.Operation {
+	case risk.OperationWithdraw:
+		_, err := s.repo.UpdateBalance(ctx, review.UserID, review.Currency, -review.Amount)
+		return err
+	case risk.OperationTransfer:
+		if review.RecipientID == nil {
+			return errors.New("у перевода не указан получатель")
+		}
+		_, _, err := s.repo.Transfer(ctx, review.UserID, *review.RecipientID, review.Currency, review.Amount)
+		return err
+	default:
+		return fmt.Errorf("неизвестный тип операции: %s", review.Operation)
+	}
 }
 
 // Exchange выполняет обмен валюты по текущему курсу
+// Вне окна исполнения пары возвращает *TradingClosedError (EXCHANGE_TRADING_HOURS)
 // Параметры:
 //   - ctx: контекст выполнения
 //   - userID: идентификатор пользователя
@@ -141,16 +552,41 @@ func (s *WalletService) Exchange(
 	toCurrency string,
 	amount float64,
 ) (*models.ExchangeResponse, error) {
+	quote, err := s.PrepareExchange(ctx, userID, fromCurrency, toCurrency, amount)
+	if err != nil {
+		return nil, err
+	}
+	return s.ExecuteExchange(ctx, quote)
+}
+
+// PrepareExchange рассчитывает обмен по текущему курсу без его выполнения: курс, комиссию и сумму к получению
+// Параметры:
+//   - ctx: контекст выполнения
+//   - userID: идентификатор пользователя
+//   - fromCurrency: исходная валюта
+//   - toCurrency: целевая валюта
+//   - amount: сумма для обмена
+//
+// Возвращает:
+//   - *models.ExchangeQuote: расчет обмена (без идентификатора и срока действия)
+//   - error: некорректные параметры или ошибка получения курса и комиссии
+func (s *WalletService) PrepareExchange(
+	ctx context.Context,
+	userID int,
+	fromCurrency string,
+	toCurrency string,
+	amount float64,
+) (*models.ExchangeQuote, error) {
 	// Валидация входных параметров
 	if userID <= 0 {
 		return nil, errors.New("неверный ID пользователя")
 	}
 
-	if !isValidCurrency(fromCurrency) {
+	if !s.currencyAllowed(ctx, fromCurrency) {
 		return nil, fmt.Errorf("неподдерживаемая исходная валюта: %s", fromCurrency)
 	}
 
-	if !isValidCurrency(toCurrency) {
+	if !s.currencyAllowed(ctx, toCurrency) {
 		return nil, fmt.Errorf("неподдерживаемая целевая валюта: %s", toCurrency)
 	}
 
@@ -158,12 +594,23 @@ func (s *WalletService) Exchange(
 		return nil, errors.New("сумма должна быть положительной")
 	}
 
-	// Получаем текущий курс обмена
-	rate, err := s.rateService.GetRate(ctx, fromCurrency, toCurrency)
+	if err := s.freezes.Check(ctx, fromCurrency, toCurrency); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTradingHours(fromCurrency, toCurrency); err != nil {
+		return nil, err
+	}
+
+	// Получаем текущий курс обмена и его происхождение для аудита
+	rate, provenance, err := s.rateService.GetRateWithProvenance(ctx, fromCurrency, toCurrency)
 	if err != nil {
 		log.Printf("Ошибка получения курса для %s->%s: %v", fromCurrency, toCurrency, err)
 		return nil, fmt.Errorf("ошибка получения курса обмена: %w", err)
 	}
+	if provenance.RateTimestamp != nil {
+		metrics.ObserveExchangeRateAge(ctx, time.Since(*provenance.RateTimestamp), middleware.TraceIDFromContext(ctx))
+	}
 
 	// Проверка реалистичности курса (защита от аномалий)
 	if (fromCurrency == "RUB" && toCurrency == "USD" && rate > 0.05) ||
@@ -171,43 +618,138 @@ func (s *WalletService) Exchange(
 		return nil, errors.New("нереалистичный курс обмена, проверьте сервис")
 	}
 
-	// Логирование параметров операции
-	log.Printf("Запрос обмена: %f %s в %s по курсу: %f", amount, fromCurrency, toCurrency, rate)
-
-	// Выполняем обмен валюты в рамках транзакции
-	newBalance, err := s.repo.Exchange(ctx, userID, fromCurrency, toCurrency, amount, rate)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка обмена: %w", err)
+	// Комиссия обмена с учетом уровня лояльности удерживается из полученной суммы
+	var feePercent float64
+	var tier string
+	if s.fees != nil {
+		feePercent, tier, err = s.fees.ExchangeFeePercent(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка расчета комиссии: %w", err)
+		}
 	}
+	// Суммы в целевой валюте округляются до количества знаков валюты по справочнику сервиса обмена
+	decimals := currencyDecimals(ctx, s.rateService, toCurrency)
+	exchanged := roundAmount(amount*rate, decimals)
+	fee := roundAmount(exchanged*feePercent/100, decimals)
+
+	return &models.ExchangeQuote{
+		UserID:          userID,
+		FromCurrency:    fromCurrency,
+		ToCurrency:      toCurrency,
+		Amount:          amount,
+		Rate:            rate,
+		Fee:             fee,
+		FeePercent:      feePercent,
+		Tier:            tier,
+		ExchangedAmount: roundAmount(exchanged-fee, decimals),
+		RateProvenance:  provenance,
+	}, nil
+}
 
-	// Дополнительные проверки курса
-	maxRates := map[string]float64{
-		"RUB/USD": 0.05,
-		"USD/RUB": 100,
-		"EUR/USD": 2.0,
-		"USD/EUR": 2.0,
+// ExecuteExchange выполняет обмен по рассчитанным курсу и комиссии
+// Параметры:
+//   - ctx: контекст выполнения
+//   - quote: расчет обмена (PrepareExchange)
+//
+// Возвращает:
+//   - *models.ExchangeResponse: результат обмена
+//   - error: ошибка при выполнении операции (в том числе недостаточно средств)
+func (s *WalletService) ExecuteExchange(ctx context.Context, quote *models.ExchangeQuote) (*models.ExchangeResponse, error) {
+	fromCurrency, toCurrency, amount, rate, fee := quote.FromCurrency, quote.ToCurrency, quote.Amount, quote.Rate, quote.Fee
+
+	// Котировка могла быть выдана до приостановки операций в валюте или закрытия окна исполнения
+	if err := s.freezes.Check(ctx, fromCurrency, toCurrency); err != nil {
+		return nil, err
+	}
+	if err := s.checkTradingHours(fromCurrency, toCurrency); err != nil {
+		return nil, err
+	}
+	if err := s.ensureFunds(ctx, quote.UserID, fromCurrency, amount); err != nil {
+		return nil, err
 	}
 
-	key := fmt.Sprintf("%s/%s", fromCurrency, toCurrency)
-	if maxRate, ok := maxRates[key]; ok && rate > maxRate {
-		return nil, fmt.Errorf("слишком высокий курс обмена: %f", rate)
+	// Логирование параметров операции
+	log.Printf("Запрос обмена: %f %s в %s по курсу: %f, комиссия: %.2f%%", amount, fromCurrency, toCurrency, rate, quote.FeePercent)
+
+	// Обмен - многошаговая операция: шаги после обмена, завершившиеся ошибкой, сторнируют его
+	var newBalance *models.Balance
+	err := s.operations.Run(ctx, quote.UserID, models.OperationKindExchange, quote,
+		// Выполняем обмен валюты в рамках транзакции
+		// Курс, его источники, время получения и котировка сохраняются в аудите обмена
+		func(ctx context.Context) error {
+			var err error
+			newBalance, err = s.repo.Exchange(ctx, quote)
+			if err != nil {
+				return fmt.Errorf("ошибка обмена: %w", err)
+			}
+			return nil
+		},
+		// Дополнительные проверки курса
+		func(ctx context.Context) error {
+			maxRates := map[string]float64{
+				"RUB/USD": 0.05,
+				"USD/RUB": 100,
+				"EUR/USD": 2.0,
+				"USD/EUR": 2.0,
+			}
+
+			key := fmt.Sprintf("%s/%s", fromCurrency, toCurrency)
+			if maxRate, ok := maxRates[key]; ok && rate > maxRate {
+				return fmt.Errorf("слишком высокий курс обмена: %f", rate)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	// Логирование результата
-	log.Printf("Обмен: %s->%s сумма: %.2f, курс: %.6f, результат: %.2f",
-		fromCurrency, toCurrency, amount, rate, amount*rate)
+	log.Printf("Обмен: %s->%s сумма: %.2f, курс: %.6f, результат: %.2f, комиссия: %.2f",
+		fromCurrency, toCurrency, amount, rate, quote.ExchangedAmount, fee)
 
 	// Формируем ответ
 	return &models.ExchangeResponse{
 		Message:         "Обмен выполнен успешно",
-		ExchangedAmount: amount * rate,
+		ExchangedAmount: quote.ExchangedAmount,
 		NewBalance:      newBalance,
 		Rate:            rate,
+		Fee:             fee,
+		FeePercent:      quote.FeePercent,
+		Tier:            quote.Tier,
+		RateProvenance:  quote.RateProvenance,
 	}, nil
 }
 
+// reverseExchange компенсирует шаг обмена многошаговой операции: сторнирует обмен по сохраненному расчету
+func (s *WalletService) reverseExchange(ctx context.Context, op *models.MoneyOperation) error {
+	var quote models.ExchangeQuote
+	if err := json.Unmarshal(op.Payload, &quote); err != nil {
+		return fmt.Errorf("ошибка чтения расчета обмена: %w", err)
+	}
+	quote.UserID = op.UserID
+	if _, err := s.repo.ReverseExchange(ctx, &quote); err != nil {
+		return fmt.Errorf("ошибка сторнирования обмена: %w", err)
+	}
+	log.Printf("Обмен %.2f %s->%s пользователя %d сторнирован (операция %d)",
+		quote.Amount, quote.FromCurrency, quote.ToCurrency, op.UserID, op.ID)
+	return nil
+}
+
 // Вспомогательные функции
 
+// currencyAllowed проверяет, что валюта поддерживается и доступна арендатору из контекста запроса
+func (s *WalletService) currencyAllowed(ctx context.Context, currency string) bool {
+	if !isValidCurrency(currency) {
+		return false
+	}
+	if s.tenants == nil {
+		return true
+	}
+	t, ok := s.tenants.Get(tenant.IDFromContext(ctx))
+	return ok && t.SupportsCurrency(currency)
+}
+
 // isValidCurrency проверяет, поддерживается ли указанная валюта
 func isValidCurrency(currency string) bool {
 	switch currency {
@@ -231,3 +773,18 @@ func getBalanceByCurrency(balance *models.Balance, currency string) (float64, er
 		return 0, fmt.Errorf("неподдерживаемая валюта: %s", currency)
 	}
 }
+
+// checkTradingHours проверяет, что обмен пары можно исполнить сейчас
+// Возвращает:
+//   - error: *TradingClosedError с началом ближайшего окна
+func (s *WalletService) checkTradingHours(fromCurrency, toCurrency string) error {
+	now := time.Now()
+	if s.hours.Open(fromCurrency, toCurrency, now) {
+		return nil
+	}
+	closed := &TradingClosedError{FromCurrency: fromCurrency, ToCurrency: toCurrency}
+	if next, ok := s.hours.NextOpen(fromCurrency, toCurrency, now); ok {
+		closed.NextOpen = &next
+	}
+	return closed
+}
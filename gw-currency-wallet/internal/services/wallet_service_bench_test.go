@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"gw-currency-wallet/internal/models"
+	"io"
+	"log"
+	"testing"
+)
+
+// Бенчмарки сервисного слоя горячих путей бюджета утилиты loadtest (cmd/loadtest/budget.yaml)
+// Хранилище и сервис обмена заменены данными в памяти: замеряются проверки и расчеты самого сервиса
+
+// BenchmarkWalletServiceGetBalance - запрос баланса (бюджет balance)
+func BenchmarkWalletServiceGetBalance(b *testing.B) {
+	s, _ := newTestWallet(map[int]models.Balance{testAlice: {USD: 100, EUR: 50}})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := s.GetBalance(ctx, testAlice); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkWalletServiceExchange - обмен 1 USD в EUR (бюджет exchange)
+func BenchmarkWalletServiceExchange(b *testing.B) {
+	b.Run("без многошаговых операций", func(b *testing.B) {
+		s, _ := newTestWallet(map[int]models.Balance{testAlice: {USD: 1e12}})
+		benchmarkExchange(b, s)
+	})
+	b.Run("с сохранением состояния операции", func(b *testing.B) {
+		s, _, _, _ := newOperationsWallet(testRates, models.Balance{USD: 1e12})
+		benchmarkExchange(b, s)
+	})
+}
+
+// benchmarkExchange замеряет обмены 1 USD в EUR пользователя alice
+func benchmarkExchange(b *testing.B, s *WalletService) {
+	output := log.Writer()
+	log.SetOutput(io.Discard) // Журнал операций не относится к замерам
+	b.Cleanup(func() { log.SetOutput(output) })
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := s.Exchange(ctx, testAlice, "USD", "EUR", 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
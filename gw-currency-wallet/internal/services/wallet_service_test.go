@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"testing"
+)
+
+func TestWalletServiceFunds(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      func(ctx context.Context, s *WalletService) error
+		wantErr error
+		want    map[int]models.Balance // Балансы после операции
+	}{
+		{
+			name: "снятие в пределах баланса",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Withdraw(ctx, testAlice, "USD", 40)
+				return err
+			},
+			want: map[int]models.Balance{testAlice: {USD: 60, EUR: 50}},
+		},
+		{
+			name: "снятие всего баланса",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Withdraw(ctx, testAlice, "USD", 100)
+				return err
+			},
+			want: map[int]models.Balance{testAlice: {EUR: 50}},
+		},
+		{
+			name: "снятие больше баланса",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Withdraw(ctx, testAlice, "USD", 100.01)
+				return err
+			},
+			wantErr: storage.ErrInsufficientFunds,
+			want:    map[int]models.Balance{testAlice: {USD: 100, EUR: 50}},
+		},
+		{
+			name: "перевод в пределах баланса",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Transfer(ctx, testAlice, "bob", "EUR", 50)
+				return err
+			},
+			want: map[int]models.Balance{testAlice: {USD: 100}, testBob: {EUR: 50}},
+		},
+		{
+			name: "перевод больше баланса",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Transfer(ctx, testAlice, "bob", "EUR", 60)
+				return err
+			},
+			wantErr: storage.ErrInsufficientFunds,
+			want:    map[int]models.Balance{testAlice: {USD: 100, EUR: 50}, testBob: {}},
+		},
+		{
+			name: "обмен в пределах баланса",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Exchange(ctx, testAlice, "USD", "EUR", 100)
+				return err
+			},
+			want: map[int]models.Balance{testAlice: {EUR: 140}},
+		},
+		{
+			name: "обмен больше баланса",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Exchange(ctx, testAlice, "USD", "EUR", 150)
+				return err
+			},
+			wantErr: storage.ErrInsufficientFunds,
+			want:    map[int]models.Balance{testAlice: {USD: 100, EUR: 50}},
+		},
+		{
+			name: "снятие из пустого кошелька",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Withdraw(ctx, testBob, "RUB", 1)
+				return err
+			},
+			wantErr: storage.ErrInsufficientFunds,
+			want:    map[int]models.Balance{testBob: {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, wallets := newTestWallet(map[int]models.Balance{
+				testAlice: {USD: 100, EUR: 50},
+				testBob:   {},
+			})
+
+			err := tt.op(context.Background(), s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+			}
+			for userID, want := range tt.want {
+				if got := wallets.balance(userID); got != want {
+					t.Errorf("баланс пользователя %d: %+v, ожидался %+v", userID, got, want)
+				}
+			}
+		})
+	}
+}
+
+// Средства, списанные параллельной операцией после быстрой проверки баланса, проверяются
+// при изменении баланса: операция отклоняется, баланс не уходит в минус
+func TestWalletServiceFundsSpentConcurrently(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(ctx context.Context, s *WalletService) error
+	}{
+		{
+			name: "снятие",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Withdraw(ctx, testAlice, "USD", 80)
+				return err
+			},
+		},
+		{
+			name: "перевод",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Transfer(ctx, testAlice, "bob", "USD", 80)
+				return err
+			},
+		},
+		{
+			name: "обмен",
+			op: func(ctx context.Context, s *WalletService) error {
+				_, err := s.Exchange(ctx, testAlice, "USD", "EUR", 80)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, wallets := newTestWallet(map[int]models.Balance{testAlice: {USD: 100}, testBob: {}})
+			wallets.raceDebit["USD"] = 50
+
+			err := tt.op(context.Background(), s)
+			if !errors.Is(err, storage.ErrInsufficientFunds) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, storage.ErrInsufficientFunds)
+			}
+			if got := wallets.balance(testAlice); got != (models.Balance{USD: 50}) {
+				t.Errorf("баланс %+v, ожидалось USD 50 после параллельного списания", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore хранит документы в директории на локальном диске
+// Подходит для одного экземпляра сервиса или общего тома; для нескольких экземпляров
+// без общего диска нужна реализация storage.DocumentStore поверх объектного хранилища
+type LocalStore struct {
+	dir string // Корневая директория хранилища
+}
+
+// NewLocalStore создает хранилище документов в указанной директории
+// Параметры:
+//   - dir: корневая директория (создается, если не существует)
+//
+// Возвращает:
+//   - *LocalStore: хранилище документов
+//   - error: ошибка создания директории
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("ошибка создания директории документов %s: %w", dir, err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Save записывает содержимое во временный файл и переименовывает его,
+// чтобы в хранилище не оставалось частично записанных документов
+func (s *LocalStore) Save(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return 0, fmt.Errorf("ошибка создания директории документа: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания файла документа: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // После успешного переименования файла уже нет
+
+	written, err := io.Copy(tmp, &contextReader{ctx: ctx, r: r})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка записи документа: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("ошибка сохранения документа: %w", err)
+	}
+	return written, nil
+}
+
+// Delete удаляет файл документа
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("ошибка удаления документа: %w", err)
+	}
+	return nil
+}
+
+// path возвращает путь к файлу по ключу; ключи вне корневой директории отклоняются
+func (s *LocalStore) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("недопустимый ключ документа: %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+// contextReader прерывает чтение при отмене контекста (например, разрыве соединения клиента)
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read читает данные, если контекст еще не отменен
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"gw-currency-wallet/internal/models"
+	"sync"
+	"time"
+)
+
+// BanList - in-memory реализация storage.BanList
+// Используется без Redis: у каждого экземпляра сервиса свой список блокировок
+type BanList struct {
+	mu   sync.Mutex
+	bans map[string]models.IPBan // Блокировки по адресу
+}
+
+// NewBanList создает пустой список блокировок
+func NewBanList() *BanList {
+	return &BanList{bans: make(map[string]models.IPBan)}
+}
+
+// Ban блокирует адрес до ban.ExpiresAt
+func (b *BanList) Ban(_ context.Context, ban models.IPBan) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bans[ban.IP] = ban
+	return nil
+}
+
+// Get возвращает действующую блокировку адреса или nil
+func (b *BanList) Get(_ context.Context, ip string) (*models.IPBan, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ban, ok := b.bans[ip]
+	if !ok {
+		return nil, nil
+	}
+	if !ban.Active(time.Now()) {
+		delete(b.bans, ip)
+		return nil, nil
+	}
+	return &ban, nil
+}
+
+// List возвращает действующие блокировки; истекшие удаляются
+func (b *BanList) List(_ context.Context) ([]models.IPBan, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	bans := make([]models.IPBan, 0, len(b.bans))
+	for ip, ban := range b.bans {
+		if !ban.Active(now) {
+			delete(b.bans, ip)
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	return bans, nil
+}
+
+// Unban снимает блокировку адреса
+func (b *BanList) Unban(_ context.Context, ip string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, ok := b.bans[ip]
+	delete(b.bans, ip)
+	return ok, nil
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"gw-currency-wallet/internal/storage"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache - in-memory реализация storage.Cache с ограничением количества записей
+//
+// Используется, когда Redis не настроен. Данные хранятся в памяти процесса,
+// поэтому при нескольких экземплярах сервиса кэш и счетчики у каждого свои.
+// При превышении лимита вытесняются давно не использованные записи (LRU).
+type Cache struct {
+	mu         sync.Mutex
+	maxEntries int                      // Максимальное количество записей
+	items      map[string]*list.Element // Быстрый доступ к записям по ключу
+	order      *list.List               // Порядок использования: в начале - недавно использованные
+	stop       chan struct{}            // Сигнал остановки фоновой очистки
+}
+
+// entry - запись кэша
+type entry struct {
+	key       string    // Ключ записи
+	value     []byte    // Значение
+	expiresAt time.Time // Момент истечения срока жизни (нулевое значение - бессрочно)
+}
+
+// NewCache создает кэш с ограничением размера и запускает фоновую очистку просроченных записей
+// Параметры:
+//   - maxEntries: максимальное количество записей (значение <= 0 заменяется на 10000)
+func NewCache(maxEntries int) *Cache {
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+	c := &Cache{
+		maxEntries: maxEntries,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+		stop:       make(chan struct{}),
+	}
+	go c.janitor(time.Minute)
+	return c
+}
+
+// Get возвращает значение по ключу или storage.ErrCacheMiss
+func (c *Cache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.lookup(key)
+	if !ok {
+		return nil, storage.ErrCacheMiss
+	}
+	c.order.MoveToFront(c.items[key])
+	return append([]byte(nil), e.value...), nil
+}
+
+// Set сохраняет копию значения с указанным временем жизни
+func (c *Cache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store(key, append([]byte(nil), value...), expiration(ttl))
+	return nil
+}
+
+// Delete удаляет ключи
+func (c *Cache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+	}
+	return nil
+}
+
+// DeletePrefix удаляет ключи с префиксом
+func (c *Cache) DeletePrefix(_ context.Context, prefix string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var deleted int64
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Incr увеличивает счетчик; время жизни устанавливается только при создании счетчика
+func (c *Cache) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var counter int64
+	expiresAt := expiration(ttl)
+	if e, ok := c.lookup(key); ok {
+		n, err := strconv.ParseInt(string(e.value), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		counter = n
+		expiresAt = e.expiresAt
+	}
+	counter++
+	c.store(key, []byte(strconv.FormatInt(counter, 10)), expiresAt)
+	return counter, nil
+}
+
+// Close останавливает фоновую очистку
+func (c *Cache) Close() error {
+	close(c.stop)
+	return nil
+}
+
+// lookup возвращает актуальную запись; просроченная запись удаляется
+// Вызывается под блокировкой
+func (c *Cache) lookup(key string) (*entry, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.remove(el)
+		return nil, false
+	}
+	return e, true
+}
+
+// store добавляет или обновляет запись и вытесняет лишние
+// Вызывается под блокировкой
+func (c *Cache) store(key string, value []byte, expiresAt time.Time) {
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove удаляет запись (вызывается под блокировкой)
+func (c *Cache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
+
+// janitor периодически удаляет просроченные записи, чтобы они не занимали память
+func (c *Cache) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			now := time.Now()
+			for _, el := range c.items {
+				e := el.Value.(*entry)
+				if !e.expiresAt.IsZero() && now.After(e.expiresAt) {
+					c.remove(el)
+				}
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// expiration вычисляет момент истечения срока жизни (ttl <= 0 - бессрочно)
+func expiration(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
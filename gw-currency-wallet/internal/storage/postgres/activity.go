@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// activityRepository реализует интерфейс ActivityRepository
+type activityRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyActivityMigrations создает журнал активности пользователей
+func applyActivityMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS user_activity_log (
+			id BIGSERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			category VARCHAR(20) NOT NULL,
+			action VARCHAR(50) NOT NULL,
+			ip VARCHAR(45) NOT NULL DEFAULT '',
+			user_agent TEXT NOT NULL DEFAULT '',
+			details JSONB,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания журнала активности: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_user_activity_log_user_created ON user_activity_log (user_id, created_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания индекса журнала активности: %w", err)
+	}
+	return nil
+}
+
+// RecordActivity записывает событие в журнал активности
+func (r *activityRepository) RecordActivity(ctx context.Context, entry models.ActivityEntry) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var details []byte
+	if entry.Details != nil {
+		var err error
+		if details, err = json.Marshal(entry.Details); err != nil {
+			return fmt.Errorf("ошибка сериализации события активности: %w", err)
+		}
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_activity_log (user_id, category, action, ip, user_agent, details)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		entry.UserID, entry.Category, entry.Action, entry.IP, entry.UserAgent, details,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи события активности: %w", err)
+	}
+	return nil
+}
+
+// ListActivity собирает ленту из журнала активности, журнала операций, архива и даты регистрации
+//
+// Лента упорядочена по (created_at, feed_key) - ключ источника с идентификатором записи различает
+// события с одинаковым временем, поэтому курсор однозначно задает позицию. Условие по времени
+// повторяется в каждом источнике, чтобы использовались индексы (user_id, created_at)
+func (r *activityRepository) ListActivity(
+	ctx context.Context,
+	userID int,
+	before string,
+	limit int,
+) ([]models.ActivityEvent, error) {
+	var beforeTime *time.Time
+	var beforeKey string
+	if before != "" {
+		t, key, err := decodeActivityCursor(before)
+		if err != nil {
+			return nil, err
+		}
+		beforeTime, beforeKey = &t, key
+	}
+
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT feed_key, category, action, details, ip, user_agent, created_at
+		FROM (
+			SELECT 'a' || id AS feed_key, category, action, details, ip, user_agent, created_at
+			FROM user_activity_log
+			WHERE user_id = $1 AND ($2::timestamptz IS NULL OR created_at <= $2)
+			UNION ALL
+			SELECT 'o' || id, $5::text, type, `+operationDetails+`, '', '', created_at
+			FROM transactions
+			WHERE user_id = $1 AND ($2::timestamptz IS NULL OR created_at <= $2)
+			UNION ALL
+			SELECT 'o' || id, $5::text, type, `+operationDetails+`, '', '', created_at
+			FROM transactions_archive
+			WHERE user_id = $1 AND ($2::timestamptz IS NULL OR created_at <= $2)
+			UNION ALL
+			SELECT 'u' || id, $6::text, $7::text, NULL::jsonb, '', '', created_at
+			FROM users
+			WHERE id = $1 AND created_at IS NOT NULL AND ($2::timestamptz IS NULL OR created_at <= $2)
+		) feed
+		WHERE $2::timestamptz IS NULL OR (created_at, feed_key) < ($2, $3)
+		ORDER BY created_at DESC, feed_key DESC
+		LIMIT $4`,
+		userID, beforeTime, beforeKey, limit,
+		models.ActivityOperation, models.ActivityProfile, models.ActionAccountCreated,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ленты активности: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.ActivityEvent{}
+	for rows.Next() {
+		var event models.ActivityEvent
+		var key string
+		var details []byte
+		if err := rows.Scan(&key, &event.Category, &event.Action, &details, &event.IP, &event.UserAgent,
+			&event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения события активности: %w", err)
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &event.Details); err != nil {
+				return nil, fmt.Errorf("ошибка чтения подробностей события: %w", err)
+			}
+		}
+		event.Cursor = encodeActivityCursor(event.CreatedAt, key)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// operationDetails - подробности записи журнала операций для ленты активности
+const operationDetails = `jsonb_strip_nulls(jsonb_build_object(
+	'operation_id', operation_id,
+	'currency', currency,
+	'amount', amount,
+	'rate', rate,
+	'counterparty_id', counterparty_id
+))`
+
+// encodeActivityCursor кодирует позицию события в ленте: время (наносекунды Unix) и ключ источника
+func encodeActivityCursor(createdAt time.Time, key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(createdAt.UnixNano(), 10) + ":" + key))
+}
+
+// decodeActivityCursor разбирает курсор ленты активности
+func decodeActivityCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", storage.ErrInvalidCursor
+	}
+	nanos, key, ok := strings.Cut(string(raw), ":")
+	if !ok || key == "" {
+		return time.Time{}, "", storage.ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", storage.ErrInvalidCursor
+	}
+	return time.Unix(0, n), key, nil
+}
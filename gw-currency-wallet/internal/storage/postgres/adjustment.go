@@ -0,0 +1,349 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+)
+
+// Действия администраторов, записываемые в журнал
+const (
+	auditAdjustmentRequested = "adjustment.requested" // Создана корректировка баланса
+	auditAdjustmentApplied   = "adjustment.applied"   // Корректировка проведена
+	auditAdjustmentRejected  = "adjustment.rejected"  // Корректировка отклонена
+)
+
+// adjustmentRepository реализует интерфейс AdjustmentRepository
+// Использует репозиторий кошельков для изменения баланса и записи в журнал операций
+type adjustmentRepository struct {
+	wallets *walletRepository // Репозиторий кошельков (подключение, таймаут, пороги AML)
+}
+
+// adjustmentColumns - столбцы корректировки в порядке сканирования scanAdjustment
+const adjustmentColumns = `id, user_id, currency, amount, reason, status, requested_by, resolved_by,
+	COALESCE(comment, ''), created_at, resolved_at`
+
+// applyAdjustmentMigrations создает таблицы корректировок и журнала действий администраторов
+func applyAdjustmentMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS balance_adjustments (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			currency VARCHAR(3) NOT NULL,
+			amount DECIMAL(15, 2) NOT NULL CHECK (amount <> 0),
+			reason TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			requested_by INTEGER NOT NULL REFERENCES users(id),
+			resolved_by INTEGER REFERENCES users(id),
+			comment TEXT,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			resolved_at TIMESTAMP WITH TIME ZONE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы корректировок баланса: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS admin_audit_log (
+			id SERIAL PRIMARY KEY,
+			admin_id INTEGER NOT NULL REFERENCES users(id),
+			action VARCHAR(50) NOT NULL,
+			target_user_id INTEGER REFERENCES users(id),
+			details JSONB,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания журнала действий администраторов: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created ON admin_audit_log (created_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания индекса журнала действий администраторов: %w", err)
+	}
+	return nil
+}
+
+// insertAuditTx записывает действие администратора в журнал в рамках транзакции
+// targetUserID равен nil для действий, не относящихся к конкретному пользователю
+func insertAuditTx(ctx context.Context, tx *sql.Tx, adminID int, action string, targetUserID *int, details interface{}) error {
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("ошибка подготовки записи журнала действий: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO admin_audit_log (admin_id, action, target_user_id, details)
+		VALUES ($1, $2, $3, $4)`,
+		adminID, action, targetUserID, payload,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи в журнал действий администраторов: %w", err)
+	}
+	return nil
+}
+
+// CreateAdjustment сохраняет корректировку и запись о ней в журнале действий
+func (r *adjustmentRepository) CreateAdjustment(ctx context.Context, adj *models.BalanceAdjustment) error {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	tx, err := r.wallets.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO balance_adjustments (user_id, currency, amount, reason, requested_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at`,
+		adj.UserID, adj.Currency, adj.Amount, adj.Reason, adj.RequestedBy,
+	).Scan(&adj.ID, &adj.Status, &adj.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания корректировки: %w", err)
+	}
+
+	if err := insertAuditTx(ctx, tx, adj.RequestedBy, auditAdjustmentRequested, &adj.UserID, adj); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return nil
+}
+
+// GetAdjustment возвращает корректировку по идентификатору
+func (r *adjustmentRepository) GetAdjustment(ctx context.Context, id int) (*models.BalanceAdjustment, error) {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	adj, err := scanAdjustment(r.wallets.db.QueryRowContext(ctx,
+		`SELECT `+adjustmentColumns+` FROM balance_adjustments WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrAdjustmentNotFound
+		}
+		return nil, err
+	}
+	return adj, nil
+}
+
+// ApplyAdjustment проводит корректировку в одной транзакции с изменением баланса,
+// записью в журнал операций и журнал действий администраторов
+func (r *adjustmentRepository) ApplyAdjustment(
+	ctx context.Context,
+	id int,
+	adminID int,
+) (*models.BalanceAdjustment, *models.Balance, error) {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	var adj *models.BalanceAdjustment
+	var balance *models.Balance
+	err := r.wallets.runBalanceTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		adj, balance, err = r.applyAdjustmentTx(ctx, tx, id, adminID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return adj, balance, nil
+}
+
+// applyAdjustmentTx проводит корректировку в транзакции и фиксирует ее
+func (r *adjustmentRepository) applyAdjustmentTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	id int,
+	adminID int,
+) (*models.BalanceAdjustment, *models.Balance, error) {
+	// Переводим корректировку в applied первой: параллельное подтверждение дождется
+	// блокировки строки и уже не найдет ее в статусе pending
+	adj, err := scanAdjustment(tx.QueryRowContext(ctx, `
+		UPDATE balance_adjustments
+		SET status = 'applied', resolved_by = $1, resolved_at = NOW()
+		WHERE id = $2 AND status = 'pending'
+		RETURNING `+adjustmentColumns,
+		adminID, id,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, storage.ErrAdjustmentNotFound
+		}
+		return nil, nil, err
+	}
+
+	balance, err := changeBalanceTx(ctx, tx, adj.UserID, adj.Currency, adj.Amount, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entryType := models.TransactionAdminCredit
+	if adj.Amount < 0 {
+		entryType = models.TransactionAdminDebit
+	}
+	if _, err := r.wallets.recordOperationTx(ctx, tx, models.Transaction{
+		UserID:   adj.UserID,
+		Type:     entryType,
+		Currency: adj.Currency,
+		Amount:   adj.Amount,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if err := insertAuditTx(ctx, tx, adminID, auditAdjustmentApplied, &adj.UserID, adj); err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.wallets.commitBalanceTx(ctx, tx, adj.UserID); err != nil {
+		return nil, nil, fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return adj, balance, nil
+}
+
+// RejectAdjustment отклоняет корректировку и записывает это в журнал действий
+func (r *adjustmentRepository) RejectAdjustment(
+	ctx context.Context,
+	id int,
+	adminID int,
+	comment string,
+) (*models.BalanceAdjustment, error) {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	tx, err := r.wallets.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	adj, err := scanAdjustment(tx.QueryRowContext(ctx, `
+		UPDATE balance_adjustments
+		SET status = 'rejected', resolved_by = $1, comment = NULLIF($2, ''), resolved_at = NOW()
+		WHERE id = $3 AND status = 'pending'
+		RETURNING `+adjustmentColumns,
+		adminID, comment, id,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrAdjustmentNotFound
+		}
+		return nil, err
+	}
+
+	if err := insertAuditTx(ctx, tx, adminID, auditAdjustmentRejected, &adj.UserID, adj); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return adj, nil
+}
+
+// ListAdjustments возвращает корректировки в указанном статусе
+func (r *adjustmentRepository) ListAdjustments(
+	ctx context.Context,
+	status string,
+	limit int,
+) ([]models.BalanceAdjustment, error) {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	rows, err := r.wallets.db.QueryContext(ctx, `
+		SELECT `+adjustmentColumns+`
+		FROM balance_adjustments
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`,
+		status, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения корректировок: %w", err)
+	}
+	defer rows.Close()
+
+	adjustments := make([]models.BalanceAdjustment, 0)
+	for rows.Next() {
+		adj, err := scanAdjustment(rows)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, *adj)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения корректировок: %w", err)
+	}
+	return adjustments, nil
+}
+
+// ListAuditLog возвращает последние записи журнала действий администраторов
+func (r *adjustmentRepository) ListAuditLog(ctx context.Context, limit int) ([]models.AuditEntry, error) {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	rows, err := r.wallets.db.QueryContext(ctx, `
+		SELECT id, admin_id, action, target_user_id, details, created_at
+		FROM admin_audit_log
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения журнала действий: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		var target sql.NullInt64
+		var details []byte
+		if err := rows.Scan(&entry.ID, &entry.AdminID, &entry.Action, &target, &details, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения журнала действий: %w", err)
+		}
+		if target.Valid {
+			id := int(target.Int64)
+			entry.TargetUserID = &id
+		}
+		entry.Details = details
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения журнала действий: %w", err)
+	}
+	return entries, nil
+}
+
+// scanAdjustment читает корректировку из строки результата (столбцы adjustmentColumns)
+func scanAdjustment(row interface{ Scan(...interface{}) error }) (*models.BalanceAdjustment, error) {
+	var adj models.BalanceAdjustment
+	var resolvedBy sql.NullInt64
+	var resolvedAt sql.NullTime
+	err := row.Scan(&adj.ID, &adj.UserID, &adj.Currency, &adj.Amount, &adj.Reason, &adj.Status,
+		&adj.RequestedBy, &resolvedBy, &adj.Comment, &adj.CreatedAt, &resolvedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ошибка чтения корректировки: %w", err)
+	}
+	if resolvedBy.Valid {
+		id := int(resolvedBy.Int64)
+		adj.ResolvedBy = &id
+	}
+	if resolvedAt.Valid {
+		adj.ResolvedAt = &resolvedAt.Time
+	}
+	return &adj, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"time"
+)
+
+// alertRepository реализует интерфейс AlertRepository
+type alertRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyAlertMigrations создает таблицу уведомлений о курсах
+func applyAlertMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS rate_alerts (
+			id SERIAL PRIMARY KEY,
+			chat_id BIGINT NOT NULL,
+			from_currency VARCHAR(3) NOT NULL,
+			to_currency VARCHAR(3) NOT NULL,
+			condition VARCHAR(1) NOT NULL CHECK (condition IN ('>', '<')),
+			threshold NUMERIC(20, 8) NOT NULL CHECK (threshold > 0),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_rate_alerts_chat ON rate_alerts(chat_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы уведомлений о курсах: %w", err)
+	}
+	return nil
+}
+
+// CreateAlert сохраняет уведомление, если у чата меньше limit уведомлений
+func (r *alertRepository) CreateAlert(ctx context.Context, alert *models.RateAlert, limit int) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	// Количество проверяется в том же запросе, чтобы одновременные команды не превысили лимит
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO rate_alerts (chat_id, from_currency, to_currency, condition, threshold)
+		SELECT $1, $2, $3, $4, $5
+		WHERE (SELECT COUNT(*) FROM rate_alerts WHERE chat_id = $1) < $6
+		RETURNING id, created_at`,
+		alert.ChatID, alert.FromCurrency, alert.ToCurrency, alert.Condition, alert.Threshold, limit,
+	).Scan(&alert.ID, &alert.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("ошибка создания уведомления о курсе: %w", err)
+	}
+	return true, nil
+}
+
+// ListAlerts возвращает уведомления чата (chatID 0 - всех чатов) в порядке создания
+func (r *alertRepository) ListAlerts(ctx context.Context, chatID int64) ([]models.RateAlert, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, chat_id, from_currency, to_currency, condition, threshold, created_at
+		FROM rate_alerts
+		WHERE $1 = 0 OR chat_id = $1
+		ORDER BY id`,
+		chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения уведомлений о курсах: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.RateAlert
+	for rows.Next() {
+		var alert models.RateAlert
+		if err := rows.Scan(&alert.ID, &alert.ChatID, &alert.FromCurrency, &alert.ToCurrency,
+			&alert.Condition, &alert.Threshold, &alert.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения уведомления о курсе: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки уведомлений о курсах: %w", err)
+	}
+	return alerts, nil
+}
+
+// DeleteAlert удаляет уведомление чата (chatID 0 - любого чата)
+func (r *alertRepository) DeleteAlert(ctx context.Context, chatID int64, id int) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM rate_alerts WHERE id = $1 AND ($2 = 0 OR chat_id = $2)`,
+		id, chatID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления уведомления о курсе: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка получения результата удаления: %w", err)
+	}
+	return affected > 0, nil
+}
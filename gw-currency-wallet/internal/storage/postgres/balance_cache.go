@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"gw-currency-wallet/internal/metrics"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// balanceCacheKeyPrefix - префикс ключей кэша балансов: wallet:balance:<id пользователя>
+const balanceCacheKeyPrefix = "wallet:balance:"
+
+// balanceCacheTimeout - максимальное время обращения к кэшу балансов
+// (медленный кэш не должен задерживать чтение баланса дольше запроса к БД)
+const balanceCacheTimeout = 200 * time.Millisecond
+
+// Очистка кэша балансов после недоставленного сброса: не чаще раза в balanceCachePurgeInterval,
+// каждая попытка ограничена balanceCachePurgeTimeout (ключи перебираются командой SCAN)
+const (
+	balanceCachePurgeInterval = time.Second
+	balanceCachePurgeTimeout  = 10 * time.Second
+)
+
+// balanceCache кэширует балансы кошельков для чтения без обращения к БД
+// Записи сбрасываются после фиксации каждой транзакции, изменившей баланс (commitBalanceTx),
+// поэтому кэш разделяется всеми экземплярами сервиса только через Redis.
+// Если сброс не доставлен (Redis недоступен или не ответил вовремя), кэш не читается и не пополняется,
+// пока все записи балансов не будут удалены фоновой очисткой. Время жизни записи ограничивает устаревание
+// баланса на других экземплярах до очистки и при чтении из БД раньше фиксации параллельной транзакции
+type balanceCache struct {
+	cache       storage.Cache // Хранилище (Redis)
+	ttl         time.Duration // Время жизни записи
+	undelivered atomic.Uint64 // Количество недоставленных сбросов
+	purged      atomic.Uint64 // Количество недоставленных сбросов, учтенных последней очисткой
+	purging     atomic.Bool   // Выполняется очистка
+	retryAt     atomic.Int64  // Время следующей попытки очистки (UnixNano)
+}
+
+// newBalanceCache создает кэш балансов (nil, если кэш не задан или время жизни не положительно)
+func newBalanceCache(cache storage.Cache, ttl time.Duration) *balanceCache {
+	if cache == nil || ttl <= 0 {
+		return nil
+	}
+	return &balanceCache{cache: cache, ttl: ttl}
+}
+
+// get возвращает баланс из кэша; ошибки кэша считаются промахом
+// Пока сброс балансов не доставлен, кэш не читается (запускается очистка)
+func (c *balanceCache) get(ctx context.Context, userID int) (*models.Balance, bool) {
+	if c == nil {
+		return nil, false
+	}
+	if !c.usable() {
+		metrics.BalanceCacheRequests.WithLabelValues("bypass").Inc()
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, balanceCacheTimeout)
+	defer cancel()
+
+	data, err := c.cache.Get(ctx, balanceCacheKey(userID))
+	if err == nil {
+		var balance models.Balance
+		if err = json.Unmarshal(data, &balance); err == nil {
+			metrics.BalanceCacheRequests.WithLabelValues("hit").Inc()
+			return &balance, true
+		}
+	}
+	if err != nil && !errors.Is(err, storage.ErrCacheMiss) && !errors.Is(err, storage.ErrCacheUnavailable) {
+		log.Printf("Ошибка чтения баланса пользователя %d из кэша: %v", userID, err)
+	}
+	metrics.BalanceCacheRequests.WithLabelValues("miss").Inc()
+	return nil, false
+}
+
+// set сохраняет прочитанный из БД баланс
+func (c *balanceCache) set(ctx context.Context, userID int, balance *models.Balance) {
+	if c == nil || c.stale() {
+		return
+	}
+	data, err := json.Marshal(balance)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, balanceCacheTimeout)
+	defer cancel()
+
+	if err := c.cache.Set(ctx, balanceCacheKey(userID), data, c.ttl); err != nil && !errors.Is(err, storage.ErrCacheUnavailable) {
+		log.Printf("Ошибка сохранения баланса пользователя %d в кэш: %v", userID, err)
+	}
+}
+
+// invalidate сбрасывает балансы пользователей в кэше
+// Выполняется и после отмены контекста запроса: транзакция уже зафиксирована
+func (c *balanceCache) invalidate(ctx context.Context, userIDs ...int) {
+	if c == nil || len(userIDs) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), balanceCacheTimeout)
+	defer cancel()
+
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, balanceCacheKey(userID))
+	}
+	if err := c.cache.Delete(ctx, keys...); err != nil {
+		c.undelivered.Add(1)
+		log.Printf("Ошибка сброса балансов %v в кэше (кэш балансов не используется до его очистки): %v", userIDs, err)
+	}
+}
+
+// stale сообщает, что есть недоставленные сбросы балансов, не учтенные очисткой кэша
+func (c *balanceCache) stale() bool {
+	return c.undelivered.Load() != c.purged.Load()
+}
+
+// usable сообщает, что кэш можно читать; при недоставленных сбросах запускает фоновую очистку
+func (c *balanceCache) usable() bool {
+	if !c.stale() {
+		return true
+	}
+	if time.Now().UnixNano() >= c.retryAt.Load() && c.purging.CompareAndSwap(false, true) {
+		go c.purge()
+	}
+	return false
+}
+
+// purge удаляет все балансы из кэша, после чего кэш снова используется
+// Сбросы, не доставленные во время очистки, требуют следующей очистки
+func (c *balanceCache) purge() {
+	defer c.purging.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), balanceCachePurgeTimeout)
+	defer cancel()
+
+	undelivered := c.undelivered.Load()
+	if _, err := c.cache.DeletePrefix(ctx, balanceCacheKeyPrefix); err != nil {
+		c.retryAt.Store(time.Now().Add(balanceCachePurgeInterval).UnixNano())
+		return
+	}
+	c.purged.Store(undelivered)
+	log.Printf("Кэш балансов очищен после недоставленных сбросов, чтение из кэша возобновлено")
+}
+
+// balanceCacheKey возвращает ключ баланса пользователя в кэше
+func balanceCacheKey(userID int) string {
+	return balanceCacheKeyPrefix + strconv.Itoa(userID)
+}
+
+// GetCachedBalance возвращает баланс пользователя из кэша балансов, при промахе - из БД с сохранением в кэш
+// Без кэша равносилен GetBalance
+func (r *walletRepository) GetCachedBalance(ctx context.Context, userID int) (*models.Balance, error) {
+	if balance, ok := r.balances.get(ctx, userID); ok {
+		return balance, nil
+	}
+	balance, err := r.GetBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	r.balances.set(ctx, userID, balance)
+	return balance, nil
+}
+
+// commitBalanceTx фиксирует транзакцию, изменившую балансы пользователей, и сбрасывает их в кэше
+// В пробном режиме (storage.WithDryRun) транзакция откатывается: результат операции получен, состояние не меняется
+// Шаг многошаговой операции (storage.WithOperationStep) записывается в той же транзакции
+func (r *walletRepository) commitBalanceTx(ctx context.Context, tx *sql.Tx, userIDs ...int) error {
+	if storage.IsDryRun(ctx) {
+		return tx.Rollback()
+	}
+	if err := markOperationStepTx(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.balances.invalidate(ctx, userIDs...)
+	return nil
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/storage/memory"
+	"io"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyCache - кэш в памяти, который пока down установлен, отвечает storage.ErrCacheUnavailable
+// (как клиент Redis в деградированном режиме)
+type flakyCache struct {
+	*memory.Cache
+	down atomic.Bool
+}
+
+func (c *flakyCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if c.down.Load() {
+		return nil, storage.ErrCacheUnavailable
+	}
+	return c.Cache.Get(ctx, key)
+}
+
+func (c *flakyCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if c.down.Load() {
+		return storage.ErrCacheUnavailable
+	}
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func (c *flakyCache) Delete(ctx context.Context, keys ...string) error {
+	if c.down.Load() {
+		return storage.ErrCacheUnavailable
+	}
+	return c.Cache.Delete(ctx, keys...)
+}
+
+func (c *flakyCache) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	if c.down.Load() {
+		return 0, storage.ErrCacheUnavailable
+	}
+	return c.Cache.DeletePrefix(ctx, prefix)
+}
+
+func TestBalanceCacheUndeliveredInvalidation(t *testing.T) {
+	output := log.Writer()
+	log.SetOutput(io.Discard) // Ошибки сброса и сообщение об очистке не нужны в выводе тестов
+	t.Cleanup(func() { log.SetOutput(output) })
+
+	ctx := context.Background()
+	store := &flakyCache{Cache: memory.NewCache(100)}
+	t.Cleanup(func() { store.Close() })
+	cache := newBalanceCache(store, time.Minute)
+
+	cache.set(ctx, 1, &models.Balance{USD: 10})
+	if _, ok := cache.get(ctx, 1); !ok {
+		t.Fatal("баланс не прочитан из кэша")
+	}
+
+	// Redis недоступен во время сброса: запись баланса в нем остается
+	store.down.Store(true)
+	cache.invalidate(ctx, 1)
+	store.down.Store(false)
+
+	t.Run("кэш не читается до очистки", func(t *testing.T) {
+		if balance, ok := cache.get(ctx, 1); ok {
+			t.Errorf("прочитан устаревший баланс %+v", balance)
+		}
+		cache.set(ctx, 1, &models.Balance{USD: 5})
+	})
+
+	t.Run("очистка удаляет устаревшие балансы и возобновляет чтение", func(t *testing.T) {
+		deadline := time.Now().Add(5 * time.Second)
+		for !cache.usable() {
+			if time.Now().After(deadline) {
+				t.Fatal("кэш балансов не очищен")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if balance, ok := cache.get(ctx, 1); ok {
+			t.Errorf("после очистки прочитан баланс %+v", balance)
+		}
+		cache.set(ctx, 1, &models.Balance{USD: 5})
+		if balance, ok := cache.get(ctx, 1); !ok || balance.USD != 5 {
+			t.Errorf("баланс после очистки %+v", balance)
+		}
+	})
+}
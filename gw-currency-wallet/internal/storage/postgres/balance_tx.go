@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"gw-currency-wallet/internal/metrics"
+	"math/rand"
+	"time"
+)
+
+// Коды ошибок PostgreSQL (SQLSTATE), после которых транзакция изменения баланса повторяется
+const (
+	serializationFailureCode = "40001" // Конфликт сериализации (REPEATABLE READ, SERIALIZABLE)
+	deadlockDetectedCode     = "40P01" // Взаимная блокировка (встречные переводы)
+)
+
+// balanceTxRetryDelay - базовая пауза перед повтором транзакции (растет с номером попытки, со случайной добавкой)
+const balanceTxRetryDelay = 10 * time.Millisecond
+
+// runBalanceTx выполняет fn в транзакции изменения баланса с уровнем изоляции из настроек (Options.TxIsolation)
+// Конфликт сериализации или взаимная блокировка откатывают транзакцию, и fn выполняется заново в новой транзакции,
+// всего не более Options.TxMaxAttempts раз; fn должна фиксировать транзакцию сама (commitBalanceTx),
+// так как конфликт SERIALIZABLE может обнаружиться и при фиксации
+func (r *walletRepository) runBalanceTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: r.txIsolation})
+		if err != nil {
+			return fmt.Errorf("ошибка начала транзакции: %w", err)
+		}
+		err = fn(tx)
+		tx.Rollback() // После фиксации не действует
+
+		reason, retryable := balanceTxConflict(err)
+		if !retryable || attempt >= r.txMaxAttempts {
+			return err
+		}
+		metrics.BalanceTxRetries.WithLabelValues(reason).Inc()
+
+		delay := time.Duration(attempt)*balanceTxRetryDelay + time.Duration(rand.Int63n(int64(balanceTxRetryDelay)))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// balanceTxConflict сообщает, что транзакция прервана конфликтом с параллельной и ее можно повторить
+func balanceTxConflict(err error) (string, bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return "", false
+	}
+	switch pqErr.Code {
+	case serializationFailureCode:
+		return "serialization_failure", true
+	case deadlockDetectedCode:
+		return "deadlock", true
+	}
+	return "", false
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"math"
+	"time"
+)
+
+// complianceRepository реализует интерфейс ComplianceRepository
+type complianceRepository struct {
+	db *sql.DB // Подключение к базе данных
+}
+
+// applyComplianceMigrations создает таблицу отчета о крупных операциях
+func applyComplianceMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS aml_reports (
+			id SERIAL PRIMARY KEY,
+			operation_id VARCHAR(32) NOT NULL,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			type VARCHAR(20) NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			amount DECIMAL(15, 2) NOT NULL,
+			threshold DECIMAL(15, 2) NOT NULL,
+			counterparty_id INTEGER REFERENCES users(id),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы отчета AML: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_aml_reports_created ON aml_reports (created_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания индекса отчета AML: %w", err)
+	}
+	return nil
+}
+
+// recordOperationTx записывает операцию в журнал и, если сумма записи не меньше порога
+// для ее валюты, в отчет AML. Обе записи делаются в транзакции изменения баланса,
+// поэтому в отчет попадает каждая выполненная крупная операция и только она;
+// так же в outbox записываются уведомления участникам операции
+// Возвращает идентификатор операции в журнале
+func (r *walletRepository) recordOperationTx(ctx context.Context, tx *sql.Tx, entries ...models.Transaction) (string, error) {
+	operationID, err := insertLedgerTx(ctx, tx, entries...)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		threshold, ok := r.amlThresholds[entry.Currency]
+		if !ok || math.Abs(entry.Amount) < threshold {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO aml_reports (operation_id, user_id, type, currency, amount, threshold, counterparty_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			operationID, entry.UserID, entry.Type, entry.Currency, math.Abs(entry.Amount), threshold, entry.CounterpartyID,
+		)
+		if err != nil {
+			return "", fmt.Errorf("ошибка записи в отчет AML: %w", err)
+		}
+	}
+
+	if err := insertNotificationsTx(ctx, tx, operationID, r.notifyChannels, r.notifyDefaults, entries...); err != nil {
+		return "", err
+	}
+	return operationID, nil
+}
+
+// ExportAMLReports передает записи отчета за период в fn в порядке времени операций
+func (r *complianceRepository) ExportAMLReports(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	fn func(models.AMLReport) error,
+) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.operation_id, a.user_id, u.username, u.email, a.type, a.currency,
+			a.amount, a.threshold, a.counterparty_id, a.created_at
+		FROM aml_reports a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.created_at >= $1 AND a.created_at < $2
+		ORDER BY a.created_at, a.id`,
+		from, to,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка получения отчета AML: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report models.AMLReport
+		var counterparty sql.NullInt64
+		err := rows.Scan(&report.ID, &report.OperationID, &report.UserID, &report.Username, &report.Email,
+			&report.Type, &report.Currency, &report.Amount, &report.Threshold, &counterparty, &report.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения отчета AML: %w", err)
+		}
+		if counterparty.Valid {
+			id := int(counterparty.Int64)
+			report.CounterpartyID = &id
+		}
+		if err := fn(report); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения отчета AML: %w", err)
+	}
+	return nil
+}
@@ -5,72 +5,209 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	_ "github.com/lib/pq" // Импорт драйвера PostgreSQL без прямого использования
+	"github.com/lib/pq" // Драйвер PostgreSQL
+	"gw-currency-wallet/internal/chaos"
 	"gw-currency-wallet/internal/models"
 	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/tenant"
 	"log"
 	"os"
+	"strings"
 	"time"
 )
 
+// Options содержит параметры работы с PostgreSQL
+type Options struct {
+	QueryTimeout         time.Duration      // Максимальное время выполнения одного запроса (или транзакции)
+	MigrationTimeout     time.Duration      // Максимальное время применения миграций при запуске
+	MaxOpenConns         int                // Максимум открытых соединений (0 - без ограничений)
+	MaxIdleConns         int                // Максимум простаивающих соединений в пуле
+	ConnMaxLifetime      time.Duration      // Максимальное время жизни соединения (0 - без ограничений)
+	AMLThresholds        map[string]float64 // Суммы по валютам, начиная с которых операции попадают в отчет AML
+	Chaos                *chaos.Injector    // Внедрение сбоев в запросы для проверки устойчивости (nil - выключено)
+	BalanceCache         storage.Cache      // Кэш балансов кошельков (Redis; nil - баланс всегда читается из БД)
+	BalanceCacheTTL      time.Duration      // Время жизни баланса в кэше
+	TxIsolation          sql.IsolationLevel // Уровень изоляции транзакций изменения баланса (sql.LevelDefault - READ COMMITTED)
+	TxMaxAttempts        int                // Попыток транзакции изменения баланса при конфликте сериализации (не меньше 1)
+	NotificationChannels []string           // Настроенные каналы уведомлений пользователей об операциях, записываемых в outbox (пусто - выключены)
+	NotificationDefaults []string           // Каналы уведомлений пользователей без правил доставки
+}
+
 // userRepository реализует интерфейс UserRepository для работы с пользователями в PostgreSQL
 type userRepository struct {
-	db *sql.DB // Подключение к базе данных
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут запроса
 }
 
 // PostgresStorage объединяет все репозитории для работы с PostgreSQL
 type PostgresStorage struct {
-	db *sql.DB // Общее подключение к БД
+	db       *sql.DB       // Общее подключение к БД
+	opts     Options       // Параметры работы с БД
+	balances *balanceCache // Кэш балансов кошельков (nil - выключен)
 }
 
 // walletRepository реализует интерфейс WalletRepository для работы с кошельками
 type walletRepository struct {
-	db *sql.DB // Подключение к базе данных
+	db             *sql.DB            // Подключение к базе данных
+	queryTimeout   time.Duration      // Таймаут запроса
+	amlThresholds  map[string]float64 // Пороги отчетности AML по валютам
+	balances       *balanceCache      // Кэш балансов (nil - выключен)
+	txIsolation    sql.IsolationLevel // Уровень изоляции транзакций изменения баланса
+	txMaxAttempts  int                // Попыток транзакции при конфликте сериализации
+	notifyChannels []string           // Настроенные каналы уведомлений об операциях (пусто - уведомления выключены)
+	notifyDefaults []string           // Каналы уведомлений пользователей без правил доставки
+}
+
+// withTimeout ограничивает время выполнения запроса таймаутом из настроек
+// Если контекст вызывающего уже содержит более ранний дедлайн, действует он
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// uniqueViolationCode - код ошибки PostgreSQL (SQLSTATE) нарушения уникальности
+const uniqueViolationCode = "23505"
+
+// uniqueViolation возвращает имя ограничения (или уникального индекса), нарушенного запросом
+// Возвращает false, если ошибка не является нарушением уникальности
+func uniqueViolation(err error) (string, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return pqErr.Constraint, true
+	}
+	return "", false
 }
 
 // CreateUser создает нового пользователя в базе данных
+// Пользователь и его пустой кошелек создаются в одной транзакции
 func (r *userRepository) CreateUser(ctx context.Context, user *models.User) error {
+	// Таймаут действует на всю транзакцию
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	// Пользователь без арендатора относится к арендатору по умолчанию
+	if user.TenantID == "" {
+		user.TenantID = tenant.DefaultID
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
 	// SQL-запрос с возвратом ID созданного пользователя
-	query := `INSERT INTO users (username, email, password_hash) VALUES ($1, $2, $3) RETURNING id`
-	err := r.db.QueryRowContext(ctx, query, user.Username, user.Email, user.PasswordHash).Scan(&user.ID)
+	query := `INSERT INTO users (tenant_id, username, email, password_hash) VALUES ($1, $2, $3, $4) RETURNING id, role, plan, kyc_status`
+	err = tx.QueryRowContext(ctx, query, user.TenantID, user.Username, user.Email, user.PasswordHash).Scan(&user.ID, &user.Role, &user.Plan, &user.KYCStatus)
 	if err != nil {
+		// Уникальность имени и email проверяется индексами арендатора, в том числе при параллельной регистрации
+		if constraint, ok := uniqueViolation(err); ok {
+			switch constraint {
+			case "users_tenant_username_key":
+				return storage.ErrUsernameTaken
+			case "users_tenant_email_key":
+				return storage.ErrEmailTaken
+			}
+		}
 		return fmt.Errorf("ошибка создания пользователя: %w", err)
 	}
+
+	// Кошелек относится к арендатору владельца
+	if _, err := tx.ExecContext(ctx, `INSERT INTO wallets (user_id, tenant_id) VALUES ($1, $2)`, user.ID, user.TenantID); err != nil {
+		return fmt.Errorf("ошибка создания кошелька: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return nil
+}
+
+// SetRole назначает роль пользователю
+func (r *userRepository) SetRole(ctx context.Context, userID int, role string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET role = $1 WHERE id = $2`, role, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка назначения роли: %w", err)
+	}
 	return nil
 }
 
-// GetUserByUsername находит пользователя по имени пользователя
-func (r *userRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE username = $1`
-	return r.queryUser(ctx, query, username)
+// SetPlan назначает тарифный план пользователю
+func (r *userRepository) SetPlan(ctx context.Context, userID int, plan string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET plan = $1 WHERE id = $2`, plan, userID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка назначения тарифного плана: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка назначения тарифного плана: %w", err)
+	}
+	return updated > 0, nil
 }
 
-// GetUserByEmail находит пользователя по email
-func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE email = $1`
-	return r.queryUser(ctx, query, email)
+// GetUserByUsername находит пользователя арендатора по имени пользователя
+func (r *userRepository) GetUserByUsername(ctx context.Context, tenantID, username string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE tenant_id = $1 AND username = $2`
+	return r.queryUser(ctx, query, tenantID, username)
+}
+
+// GetUserByEmail находит пользователя арендатора по email
+func (r *userRepository) GetUserByEmail(ctx context.Context, tenantID, email string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE tenant_id = $1 AND email = $2`
+	return r.queryUser(ctx, query, tenantID, email)
 }
 
 // GetUserByID находит пользователя по ID
 func (r *userRepository) GetUserByID(ctx context.Context, id int) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE id = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 	return r.queryUser(ctx, query, id)
 }
 
 // queryUser общий метод для выполнения запросов пользователей
 func (r *userRepository) queryUser(ctx context.Context, query string, args ...interface{}) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Пользователь не найден - не ошибка
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// userColumns - столбцы пользователя в порядке сканирования scanUser
+const userColumns = `id, tenant_id, username, email, password_hash, role, plan, kyc_status, COALESCE(kyc_comment, ''), created_at, updated_at`
+
+// scanUser читает пользователя из строки результата (столбцы userColumns)
+func scanUser(row interface{ Scan(...interface{}) error }) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+	err := row.Scan(
 		&user.ID,
+		&user.TenantID,
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
+		&user.Role,
+		&user.Plan,
+		&user.KYCStatus,
+		&user.KYCComment,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil // Пользователь не найден - не ошибка
+			return nil, err
 		}
 		return nil, fmt.Errorf("ошибка запроса пользователя: %w", err)
 	}
@@ -78,55 +215,98 @@ func (r *userRepository) queryUser(ctx context.Context, query string, args ...in
 }
 
 // GetBalance возвращает баланс пользователя
+// Кошелек создается при регистрации, поэтому его отсутствие - ошибка storage.ErrWalletUnavailable
 func (r *walletRepository) GetBalance(ctx context.Context, userID int) (*models.Balance, error) {
-	query := `SELECT usd, rub, eur FROM wallets WHERE user_id = $1`
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT usd, rub, eur, updated_at FROM wallets WHERE user_id = $1`
 	row := r.db.QueryRowContext(ctx, query, userID)
 
 	var balance models.Balance
-	err := row.Scan(&balance.USD, &balance.RUB, &balance.EUR)
+	err := row.Scan(&balance.USD, &balance.RUB, &balance.EUR, &balance.UpdatedAt)
 	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			if err := r.CreateWallet(ctx, userID); err != nil {
-				return nil, err
-			}
-			return &models.Balance{USD: 0, RUB: 0, EUR: 0}, nil
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: кошелек пользователя %d отсутствует", storage.ErrWalletUnavailable, userID)
 		}
 		return nil, fmt.Errorf("ошибка получения баланса: %w", err)
 	}
 	return &balance, nil
 }
 
-// CreateWallet создает новый кошелек для пользователя
-func (r *walletRepository) CreateWallet(ctx context.Context, userID int) error {
-	_, err := r.db.ExecContext(ctx, "INSERT INTO wallets (user_id) VALUES ($1)", userID)
+// ListBalances возвращает балансы пользователей из списка (или всех) страницей по возрастанию ID
+func (r *walletRepository) ListBalances(ctx context.Context, userIDs []int, afterID, limit int) ([]models.UserBalance, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	ids := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		ids = append(ids, int64(id))
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT w.user_id, u.username, w.usd, w.rub, w.eur, w.updated_at
+		FROM wallets w
+		JOIN users u ON u.id = w.user_id
+		WHERE (cardinality($1::integer[]) = 0 OR w.user_id = ANY($1::integer[]))
+			AND w.user_id > $2
+		ORDER BY w.user_id
+		LIMIT $3`,
+		pq.Array(ids), afterID, limit,
+	)
 	if err != nil {
-		return fmt.Errorf("ошибка создания кошелька: %w", err)
+		return nil, fmt.Errorf("ошибка получения балансов: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	balances := make([]models.UserBalance, 0)
+	for rows.Next() {
+		var b models.UserBalance
+		if err := rows.Scan(&b.UserID, &b.Username, &b.USD, &b.RUB, &b.EUR, &b.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения баланса: %w", err)
+		}
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
 }
 
 // UpdateBalance обновляет баланс пользователя для указанной валюты
+// Изменение баланса и запись в журнал операций выполняются в одной транзакции:
+// положительная сумма учитывается как пополнение, отрицательная - как снятие
 func (r *walletRepository) UpdateBalance(ctx context.Context, userID int, currency string, amount float64) (*models.Balance, error) {
-	var query string
-	switch currency {
-	case "USD":
-		query = `UPDATE wallets SET usd = usd + $1 WHERE user_id = $2 RETURNING usd, rub, eur`
-	case "RUB":
-		query = `UPDATE wallets SET rub = rub + $1 WHERE user_id = $2 RETURNING usd, rub, eur`
-	case "EUR":
-		query = `UPDATE wallets SET eur = eur + $1 WHERE user_id = $2 RETURNING usd, rub, eur`
-	default:
-		return nil, fmt.Errorf("неподдерживаемая валюта: %s", currency)
-	}
+	// Таймаут действует на всю транзакцию вместе с повторами
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 
-	row := r.db.QueryRowContext(ctx, query, amount, userID)
-	var balance models.Balance
-	err := row.Scan(&balance.USD, &balance.RUB, &balance.EUR)
+	var balance *models.Balance
+	err := r.runBalanceTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		balance, err = r.updateBalanceTx(ctx, tx, userID, currency, amount)
+		if err != nil {
+			return fmt.Errorf("ошибка обновления баланса: %w", err)
+		}
+
+		entryType := models.TransactionDeposit
+		if amount < 0 {
+			entryType = models.TransactionWithdraw
+		}
+		if _, err := r.recordOperationTx(ctx, tx, models.Transaction{
+			UserID:   userID,
+			Type:     entryType,
+			Currency: currency,
+			Amount:   amount,
+		}); err != nil {
+			return err
+		}
+
+		if err := r.commitBalanceTx(ctx, tx, userID); err != nil {
+			return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка обновления баланса: %w", err)
+		return nil, err
 	}
-	return &balance, nil
+	return balance, nil
 }
 
 // Transfer выполняет перевод средств между пользователями в рамках транзакции
@@ -137,102 +317,281 @@ func (r *walletRepository) Transfer(
 	currency string,
 	amount float64,
 ) (*models.Balance, *models.Balance, error) {
-	// Начинаем транзакцию
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, nil, fmt.Errorf("ошибка начала транзакции: %w", err)
-	}
-	defer tx.Rollback() // Откат при ошибке
+	// Таймаут действует на всю транзакцию вместе с повторами
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
 
-	// Снимаем средства у отправителя
-	fromBalance, err := r.updateBalanceTx(ctx, tx, fromUserID, currency, -amount)
-	if err != nil {
-		return nil, nil, fmt.Errorf("ошибка списания у отправителя: %w", err)
-	}
+	// Транзакция повторяется при конфликте с параллельной (встречные переводы, SERIALIZABLE)
+	var fromBalance, toBalance *models.Balance
+	err := r.runBalanceTx(ctx, func(tx *sql.Tx) error {
+		var err error
 
-	// Зачисляем средства получателю
-	toBalance, err := r.updateBalanceTx(ctx, tx, toUserID, currency, amount)
-	if err != nil {
-		return nil, nil, fmt.Errorf("ошибка зачисления получателю: %w", err)
-	}
+		// Снимаем средства у отправителя
+		fromBalance, err = r.updateBalanceTx(ctx, tx, fromUserID, currency, -amount)
+		if err != nil {
+			return fmt.Errorf("ошибка списания у отправителя: %w", err)
+		}
 
-	// Фиксируем транзакцию
-	if err := tx.Commit(); err != nil {
-		return nil, nil, fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+		// Зачисляем средства получателю
+		toBalance, err = r.updateBalanceTx(ctx, tx, toUserID, currency, amount)
+		if err != nil {
+			return fmt.Errorf("ошибка зачисления получателю: %w", err)
+		}
+
+		// Записываем обе стороны перевода в журнал
+		if _, err := r.recordOperationTx(ctx, tx,
+			models.Transaction{
+				UserID:         fromUserID,
+				Type:           models.TransactionTransferOut,
+				Currency:       currency,
+				Amount:         -amount,
+				CounterpartyID: &toUserID,
+			},
+			models.Transaction{
+				UserID:         toUserID,
+				Type:           models.TransactionTransferIn,
+				Currency:       currency,
+				Amount:         amount,
+				CounterpartyID: &fromUserID,
+			},
+		); err != nil {
+			return err
+		}
+
+		// Фиксируем транзакцию
+		if err := r.commitBalanceTx(ctx, tx, fromUserID, toUserID); err != nil {
+			return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
 	return fromBalance, toBalance, nil
 }
 
-// Exchange выполняет обмен валюты в рамках транзакции
-func (r *walletRepository) Exchange(
-	ctx context.Context,
-	userID int,
-	fromCurrency string,
-	toCurrency string,
-	amount float64,
-	rate float64,
-) (*models.Balance, error) {
-	// Начинаем транзакцию
-	tx, err := r.db.BeginTx(ctx, nil)
+// Exchange выполняет обмен валюты в рамках транзакции и записывает аудит обмена с происхождением курса
+func (r *walletRepository) Exchange(ctx context.Context, quote *models.ExchangeQuote) (*models.Balance, error) {
+	// Таймаут действует на всю транзакцию вместе с повторами
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var balance *models.Balance
+	err := r.runBalanceTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		balance, err = r.exchangeTx(ctx, tx, quote)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback()
+	return balance, nil
+}
+
+// exchangeTx выполняет обмен по котировке в транзакции и фиксирует ее
+func (r *walletRepository) exchangeTx(ctx context.Context, tx *sql.Tx, quote *models.ExchangeQuote) (*models.Balance, error) {
+	userID, fromCurrency, toCurrency := quote.UserID, quote.FromCurrency, quote.ToCurrency
+	amount, rate, fee := quote.Amount, quote.Rate, quote.Fee
 
 	// Снимаем средства в исходной валюте
-	_, err = r.updateBalanceTx(ctx, tx, userID, fromCurrency, -amount)
+	_, err := r.updateBalanceTx(ctx, tx, userID, fromCurrency, -amount)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка списания %s: %w", fromCurrency, err)
 	}
 
-	// Зачисляем средства в целевой валюте
-	exchangedAmount := amount * rate
-	balance, err := r.updateBalanceTx(ctx, tx, userID, toCurrency, exchangedAmount)
+	// Зачисляем сумму к получению (округлена в котировке до знаков целевой валюты)
+	received := quote.ExchangedAmount
+	exchangedAmount := received + fee
+	balance, err := r.updateBalanceTx(ctx, tx, userID, toCurrency, received)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка зачисления %s: %w", toCurrency, err)
 	}
 
+	// Записываем списание и зачисление в журнал с примененным курсом, комиссию - отдельной записью
+	entries := []models.Transaction{
+		{
+			UserID:   userID,
+			Type:     models.TransactionExchangeOut,
+			Currency: fromCurrency,
+			Amount:   -amount,
+			Rate:     &rate,
+		},
+		{
+			UserID:   userID,
+			Type:     models.TransactionExchangeIn,
+			Currency: toCurrency,
+			Amount:   exchangedAmount,
+			Rate:     &rate,
+		},
+	}
+	if fee > 0 {
+		entries = append(entries, models.Transaction{
+			UserID:   userID,
+			Type:     models.TransactionFee,
+			Currency: toCurrency,
+			Amount:   -fee,
+		})
+	}
+	operationID, err := r.recordOperationTx(ctx, tx, entries...)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertExchangeAuditTx(ctx, tx, operationID, quote); err != nil {
+		return nil, err
+	}
+
 	// Фиксируем транзакцию
-	if err := tx.Commit(); err != nil {
+	if err := r.commitBalanceTx(ctx, tx, userID); err != nil {
 		return nil, fmt.Errorf("ошибка подтверждения транзакции: %w", err)
 	}
 
 	return balance, nil
 }
 
+// ReverseExchange сторнирует выполненный обмен: списывает полученную сумму и возвращает списанную
+// Комиссия возвращается вместе с полученной суммой (списывается сумма к получению за вычетом комиссии)
+func (r *walletRepository) ReverseExchange(ctx context.Context, quote *models.ExchangeQuote) (*models.Balance, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	userID, received := quote.UserID, quote.ExchangedAmount
+	var balance *models.Balance
+	err := r.runBalanceTx(ctx, func(tx *sql.Tx) error {
+		if _, err := r.updateBalanceTx(ctx, tx, userID, quote.ToCurrency, -received); err != nil {
+			return fmt.Errorf("ошибка списания %s: %w", quote.ToCurrency, err)
+		}
+		var err error
+		balance, err = r.updateBalanceTx(ctx, tx, userID, quote.FromCurrency, quote.Amount)
+		if err != nil {
+			return fmt.Errorf("ошибка возврата %s: %w", quote.FromCurrency, err)
+		}
+
+		if _, err := r.recordOperationTx(ctx, tx,
+			models.Transaction{
+				UserID:   userID,
+				Type:     models.TransactionExchangeReversal,
+				Currency: quote.ToCurrency,
+				Amount:   -received,
+				Rate:     &quote.Rate,
+			},
+			models.Transaction{
+				UserID:   userID,
+				Type:     models.TransactionExchangeReversal,
+				Currency: quote.FromCurrency,
+				Amount:   quote.Amount,
+				Rate:     &quote.Rate,
+			},
+		); err != nil {
+			return err
+		}
+
+		if err := r.commitBalanceTx(ctx, tx, userID); err != nil {
+			return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
 // updateBalanceTx вспомогательный метод для обновления баланса в транзакции
+// Возвращает storage.ErrWalletUnavailable, если кошелек не найден или заблокирован
 func (r *walletRepository) updateBalanceTx(
 	ctx context.Context,
 	tx *sql.Tx,
 	userID int,
 	currency string,
 	amount float64,
+) (*models.Balance, error) {
+	return changeBalanceTx(ctx, tx, userID, currency, amount, false)
+}
+
+// changeBalanceTx изменяет баланс кошелька в транзакции
+// Заблокированный кошелек изменяется только при includeQuarantined (корректировки администратора)
+// Достаточность средств проверяется самим UPDATE под блокировкой строки кошелька, поэтому параллельные
+// списания не уводят баланс в минус (проверка баланса до транзакции этого не гарантирует)
+// Возвращает storage.ErrInsufficientFunds, если списание превышает баланс,
+// storage.ErrWalletUnavailable, если кошелек не найден или заблокирован
+func changeBalanceTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	userID int,
+	currency string,
+	amount float64,
+	includeQuarantined bool,
 ) (*models.Balance, error) {
 	var query string
 	switch currency {
 	case "USD":
-		query = `UPDATE wallets SET usd = usd + $1 WHERE user_id = $2 RETURNING usd, rub, eur`
+		query = `UPDATE wallets SET usd = usd + $1 WHERE user_id = $2 AND ($3 OR NOT quarantined) AND usd + $1 >= 0 RETURNING usd, rub, eur, updated_at`
 	case "RUB":
-		query = `UPDATE wallets SET rub = rub + $1 WHERE user_id = $2 RETURNING usd, rub, eur`
+		query = `UPDATE wallets SET rub = rub + $1 WHERE user_id = $2 AND ($3 OR NOT quarantined) AND rub + $1 >= 0 RETURNING usd, rub, eur, updated_at`
 	case "EUR":
-		query = `UPDATE wallets SET eur = eur + $1 WHERE user_id = $2 RETURNING usd, rub, eur`
+		query = `UPDATE wallets SET eur = eur + $1 WHERE user_id = $2 AND ($3 OR NOT quarantined) AND eur + $1 >= 0 RETURNING usd, rub, eur, updated_at`
 	default:
 		return nil, fmt.Errorf("неподдерживаемая валюта: %s", currency)
 	}
 
-	row := tx.QueryRowContext(ctx, query, amount, userID)
+	row := tx.QueryRowContext(ctx, query, amount, userID, includeQuarantined)
 	var balance models.Balance
-	err := row.Scan(&balance.USD, &balance.RUB, &balance.EUR)
+	err := row.Scan(&balance.USD, &balance.RUB, &balance.EUR, &balance.UpdatedAt)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, balanceUnchangedTx(ctx, tx, userID, includeQuarantined)
+		}
 		return nil, err
 	}
 
 	return &balance, nil
 }
 
+// balanceUnchangedTx возвращает причину, по которой UPDATE баланса не изменил ни одной строки:
+// storage.ErrInsufficientFunds, если кошелек доступен (не хватило средств), иначе storage.ErrWalletUnavailable
+func balanceUnchangedTx(ctx context.Context, tx *sql.Tx, userID int, includeQuarantined bool) error {
+	var available bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM wallets WHERE user_id = $1 AND ($2 OR NOT quarantined))`,
+		userID, includeQuarantined,
+	).Scan(&available)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки кошелька: %w", err)
+	}
+	if available {
+		return storage.ErrInsufficientFunds
+	}
+	return storage.ErrWalletUnavailable
+}
+
+// SetQuarantine блокирует или разблокирует кошелек пользователя
+func (r *walletRepository) SetQuarantine(ctx context.Context, userID int, quarantined bool, reason string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if !quarantined {
+		reason = ""
+	}
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE wallets SET quarantined = $1, quarantine_reason = NULLIF($2, '') WHERE user_id = $3`,
+		quarantined, reason, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка изменения блокировки кошелька: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return storage.ErrWalletUnavailable
+	}
+	return nil
+}
+
 // NewPostgresStorage создает новое подключение к PostgreSQL
-func NewPostgresStorage(connString string) (*PostgresStorage, error) {
+// Параметры:
+//   - ctx: контекст запуска (отмена прерывает подключение и миграции)
+//   - connString: строка подключения к БД
+//   - opts: параметры работы с БД (таймауты)
+func NewPostgresStorage(ctx context.Context, connString string, opts Options) (*PostgresStorage, error) {
 	// Получаем параметры подключения из переменных окружения
 	dbHost := os.Getenv("DB_HOST")
 	dbPort := os.Getenv("DB_PORT")
@@ -253,7 +612,9 @@ func NewPostgresStorage(connString string) (*PostgresStorage, error) {
 
 	// Проверка существования БД
 	var exists bool
-	err = adminConn.QueryRowContext(context.Background(),
+	checkCtx, cancelCheck := withTimeout(ctx, opts.QueryTimeout)
+	defer cancelCheck()
+	err = adminConn.QueryRowContext(checkCtx,
 		"SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = 'wallet_db')").Scan(&exists)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка проверки существования БД: %w", err)
@@ -261,7 +622,7 @@ func NewPostgresStorage(connString string) (*PostgresStorage, error) {
 
 	// Создание БД если не существует
 	if !exists {
-		_, err = adminConn.ExecContext(context.Background(), "CREATE DATABASE wallet_db")
+		_, err = adminConn.ExecContext(checkCtx, "CREATE DATABASE wallet_db")
 		if err != nil {
 			return nil, fmt.Errorf("ошибка создания БД: %w", err)
 		}
@@ -274,31 +635,74 @@ func NewPostgresStorage(connString string) (*PostgresStorage, error) {
 	}
 
 	// Проверка подключения
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := db.PingContext(ctx); err != nil {
+	pingCtx, cancelPing := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelPing()
+	if err := db.PingContext(pingCtx); err != nil {
 		return nil, fmt.Errorf("ошибка проверки соединения: %w", err)
 	}
 
 	// Настройка пула соединений
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	configurePool(db, opts)
+	log.Printf("Пул соединений PostgreSQL: max_open=%d, max_idle=%d, max_lifetime=%s",
+		opts.MaxOpenConns, opts.MaxIdleConns, opts.ConnMaxLifetime)
 
-	// Применение миграций
-	if err := applyMigrations(db); err != nil {
+	// Применение миграций с отдельным таймаутом
+	migrateCtx, cancelMigrate := withTimeout(ctx, opts.MigrationTimeout)
+	defer cancelMigrate()
+	if err := applyMigrations(migrateCtx, db); err != nil {
 		return nil, fmt.Errorf("ошибка применения миграций: %w", err)
 	}
 
+	// Секции журнала на текущий и следующий месяц нужны сразу при запуске,
+	// остальные создаются фоновой задачей обслуживания журнала
+	ledger := &transactionRepository{db: db, queryTimeout: opts.QueryTimeout}
+	if err := ledger.EnsurePartitions(ctx, time.Now(), 2); err != nil {
+		return nil, fmt.Errorf("ошибка создания секций журнала операций: %w", err)
+	}
+	if err := backfillOpeningBalances(ctx, db); err != nil {
+		return nil, err
+	}
+
+	// Сбои режима chaos внедряются только в запросы работающего сервиса:
+	// миграции и подготовка журнала выполнены через обычное подключение
+	if opts.Chaos != nil {
+		chaosDB, err := openWithChaos(connString, opts)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		db.Close()
+		db = chaosDB
+		log.Printf("ВНИМАНИЕ: режим chaos для PostgreSQL (%s)", opts.Chaos)
+	}
+
 	log.Println("Успешное подключение к PostgreSQL")
 
-	return &PostgresStorage{db: db}, nil
+	return &PostgresStorage{db: db, opts: opts, balances: newBalanceCache(opts.BalanceCache, opts.BalanceCacheTTL)}, nil
+}
+
+// configurePool применяет к пулу соединений параметры из opts
+func configurePool(db *sql.DB, opts Options) {
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxIdleConns)
+	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+}
+
+// openWithChaos открывает пул соединений, в запросы которого внедряются сбои opts.Chaos
+func openWithChaos(connString string, opts Options) (*sql.DB, error) {
+	connector, err := pq.NewConnector(connString)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора строки подключения: %w", err)
+	}
+	db := sql.OpenDB(chaos.Connector(connector, opts.Chaos))
+	configurePool(db, opts)
+	return db, nil
 }
 
 // applyMigrations создает таблицы если они не существуют
-func applyMigrations(db *sql.DB) error {
+func applyMigrations(ctx context.Context, db *sql.DB) error {
 	// Создание таблицы пользователей
-	_, err := db.Exec(`
+	_, err := db.ExecContext(ctx, `
 		CREATE TABLE IF NOT EXISTS users (
 			id SERIAL PRIMARY KEY,
 			username VARCHAR(50) UNIQUE NOT NULL,
@@ -312,8 +716,24 @@ func applyMigrations(db *sql.DB) error {
 		return fmt.Errorf("ошибка создания таблицы пользователей: %w", err)
 	}
 
+	// Роль пользователя (добавлена позже, поэтому отдельной миграцией)
+	_, err = db.ExecContext(ctx, `
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user'
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления роли пользователей: %w", err)
+	}
+
+	// Тарифный план пользователя (квота запросов)
+	_, err = db.ExecContext(ctx, `
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS plan VARCHAR(20) NOT NULL DEFAULT 'free'
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления тарифного плана пользователей: %w", err)
+	}
+
 	// Создание таблицы кошельков
-	_, err = db.Exec(`
+	_, err = db.ExecContext(ctx, `
 		CREATE TABLE IF NOT EXISTS wallets (
 			user_id INTEGER PRIMARY KEY REFERENCES users(id),
 			usd DECIMAL(15, 2) DEFAULT 0.00,
@@ -327,6 +747,244 @@ func applyMigrations(db *sql.DB) error {
 		return fmt.Errorf("ошибка создания таблицы кошельков: %w", err)
 	}
 
+	// Блокировка кошелька по итогам сверки с журналом
+	_, err = db.ExecContext(ctx, `
+		ALTER TABLE wallets
+			ADD COLUMN IF NOT EXISTS quarantined BOOLEAN NOT NULL DEFAULT FALSE,
+			ADD COLUMN IF NOT EXISTS quarantine_reason TEXT
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления блокировки кошельков: %w", err)
+	}
+
+	// Баланс не может стать отрицательным: ограничение страхует проверку средств в UPDATE баланса
+	// (changeBalanceTx) от любых других изменений кошельков. Ограничение добавляется без проверки
+	// существующих строк (NOT VALID), чтобы кошелек с отрицательным балансом не останавливал запуск,
+	// и проверяется отдельным шагом
+	_, err = db.ExecContext(ctx, `
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'wallets_balance_non_negative') THEN
+				ALTER TABLE wallets ADD CONSTRAINT wallets_balance_non_negative
+					CHECK (usd >= 0 AND rub >= 0 AND eur >= 0) NOT VALID;
+			END IF;
+		END
+		$$
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления ограничения баланса кошельков: %w", err)
+	}
+	if err := validateBalanceConstraint(ctx, db); err != nil {
+		return err
+	}
+
+	// Арендаторы: пользователи и кошельки принадлежат брендированному продукту,
+	// имена пользователей и email уникальны в пределах арендатора
+	for _, statement := range []string{
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(50) NOT NULL DEFAULT 'default'`,
+		`ALTER TABLE wallets ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(50) NOT NULL DEFAULT 'default'`,
+		`ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key`,
+		`ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_username_key ON users (tenant_id, username)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_email_key ON users (tenant_id, email)`,
+		`CREATE INDEX IF NOT EXISTS wallets_tenant_id_idx ON wallets (tenant_id)`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка добавления арендаторов: %w", err)
+		}
+	}
+
+	// Время последнего изменения пользователей и кошельков обновляется триггером
+	// при любом UPDATE, поэтому запросам не нужно устанавливать updated_at самостоятельно
+	for _, statement := range []string{
+		`CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+		BEGIN
+			NEW.updated_at = NOW();
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`CREATE OR REPLACE TRIGGER users_set_updated_at BEFORE UPDATE ON users
+			FOR EACH ROW EXECUTE FUNCTION set_updated_at()`,
+		`CREATE OR REPLACE TRIGGER wallets_set_updated_at BEFORE UPDATE ON wallets
+			FOR EACH ROW EXECUTE FUNCTION set_updated_at()`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка создания триггеров updated_at: %w", err)
+		}
+	}
+
+	// Кошельки создаются при регистрации: пользователям, зарегистрированным до этого
+	// и еще не запрашивавшим баланс, создаются пустые кошельки
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO wallets (user_id, tenant_id)
+		SELECT u.id, u.tenant_id FROM users u
+		WHERE NOT EXISTS (SELECT 1 FROM wallets w WHERE w.user_id = u.id)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания отсутствующих кошельков: %w", err)
+	}
+
+	// Журнал операций с месячными секциями и архив
+	if err := applyLedgerMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Очередь проверки антифрода
+	if err := applyReviewMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Верификация пользователей (KYC)
+	if err := applyKYCMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Отчет о крупных операциях (AML)
+	if err := applyComplianceMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Корректировки баланса администратором и журнал действий администраторов
+	if err := applyAdjustmentMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Промокоды
+	if err := applyPromoMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Настройки пользователей
+	if err := applyPreferencesMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Устройства пользователей
+	if err := applyDeviceMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Обработанные события платежного провайдера
+	if err := applyPaymentMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Журнал активности пользователей
+	if err := applyActivityMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Уведомления о курсах (Telegram бот)
+	if err := applyAlertMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Избранные валюты чатов (Telegram бот)
+	if err := applyFavoritesMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Чаты Telegram бота и их настройки
+	if err := applyChatMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Привязки аккаунтов Telegram к пользователям (обмен из бота)
+	if err := applyTelegramLinkMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Аудит обменов: происхождение примененного курса
+	if err := applyExchangeAuditMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Индексы поиска пользователей администратором
+	if err := applyUserSearchMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Токены обновления сессий (скользящий и абсолютный срок жизни)
+	if err := applyRefreshTokenMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Состояние многошаговых денежных операций для компенсации незавершенных шагов
+	if err := applyOperationMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Outbox уведомлений пользователей об операциях с балансом
+	if err := applyNotificationMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Токены push уведомлений мобильного приложения
+	if err := applyPushTokenMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Приостановки операций в валютах администраторами
+	if err := applyCurrencyFreezeMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Очередь обменов, отложенных до открытия окна исполнения
+	if err := applyQueuedExchangeMigrations(ctx, db); err != nil {
+		return err
+	}
+
+	// Планы регулярных обменов
+	return applyDCAMigrations(ctx, db)
+}
+
+// validateBalanceConstraint проверяет ограничение wallets_balance_non_negative для существующих кошельков
+// Кошельки с отрицательным балансом выводятся в лог, и ограничение остается непроверенным до их исправления
+// (новые изменения кошельков оно проверяет и так); запуск при этом не прерывается
+func validateBalanceConstraint(ctx context.Context, db *sql.DB) error {
+	var validated bool
+	err := db.QueryRowContext(ctx,
+		`SELECT convalidated FROM pg_constraint WHERE conname = 'wallets_balance_non_negative'`,
+	).Scan(&validated)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения ограничения баланса кошельков: %w", err)
+	}
+	if validated {
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT user_id, usd, rub, eur FROM wallets
+		WHERE usd < 0 OR rub < 0 OR eur < 0
+		ORDER BY user_id
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка поиска кошельков с отрицательным балансом: %w", err)
+	}
+	defer rows.Close()
+
+	var negative []string
+	for rows.Next() {
+		var userID int
+		var usd, rub, eur float64
+		if err := rows.Scan(&userID, &usd, &rub, &eur); err != nil {
+			return fmt.Errorf("ошибка чтения кошелька с отрицательным балансом: %w", err)
+		}
+		negative = append(negative, fmt.Sprintf("%d (USD %.2f, RUB %.2f, EUR %.2f)", userID, usd, rub, eur))
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения кошельков с отрицательным балансом: %w", err)
+	}
+	if len(negative) > 0 {
+		log.Printf("ВНИМАНИЕ: ограничение wallets_balance_non_negative не проверено - кошельки с отрицательным балансом (%d): %s",
+			len(negative), strings.Join(negative, "; "))
+		return nil
+	}
+
+	if _, err := db.ExecContext(ctx, `ALTER TABLE wallets VALIDATE CONSTRAINT wallets_balance_non_negative`); err != nil {
+		return fmt.Errorf("ошибка проверки ограничения баланса кошельков: %w", err)
+	}
+	log.Printf("Ограничение wallets_balance_non_negative проверено для существующих кошельков")
 	return nil
 }
 
@@ -337,10 +995,130 @@ func (s *PostgresStorage) Close() error {
 
 // GetUserRepository возвращает реализацию UserRepository
 func (s *PostgresStorage) GetUserRepository() storage.UserRepository {
-	return &userRepository{db: s.db}
+	return &userRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
 }
 
 // GetWalletRepository возвращает реализацию WalletRepository
 func (s *PostgresStorage) GetWalletRepository() storage.WalletRepository {
-	return &walletRepository{db: s.db}
+	return s.walletRepository()
+}
+
+// walletRepository создает репозиторий кошельков; используется и репозиториями,
+// изменяющими баланс в своих транзакциях (корректировки, промокоды)
+func (s *PostgresStorage) walletRepository() *walletRepository {
+	return &walletRepository{
+		db:             s.db,
+		queryTimeout:   s.opts.QueryTimeout,
+		amlThresholds:  s.opts.AMLThresholds,
+		balances:       s.balances,
+		txIsolation:    s.opts.TxIsolation,
+		txMaxAttempts:  max(s.opts.TxMaxAttempts, 1),
+		notifyChannels: s.opts.NotificationChannels,
+		notifyDefaults: s.opts.NotificationDefaults,
+	}
+}
+
+// GetTransactionRepository возвращает реализацию TransactionRepository
+func (s *PostgresStorage) GetTransactionRepository() storage.TransactionRepository {
+	return &transactionRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetReviewRepository возвращает реализацию ReviewRepository
+func (s *PostgresStorage) GetReviewRepository() storage.ReviewRepository {
+	return &reviewRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetKYCRepository возвращает реализацию KYCRepository
+func (s *PostgresStorage) GetKYCRepository() storage.KYCRepository {
+	return &kycRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetComplianceRepository возвращает реализацию ComplianceRepository
+func (s *PostgresStorage) GetComplianceRepository() storage.ComplianceRepository {
+	return &complianceRepository{db: s.db}
+}
+
+// GetAdjustmentRepository возвращает реализацию AdjustmentRepository
+func (s *PostgresStorage) GetAdjustmentRepository() storage.AdjustmentRepository {
+	return &adjustmentRepository{wallets: s.walletRepository()}
+}
+
+// GetPromoRepository возвращает реализацию PromoRepository
+func (s *PostgresStorage) GetPromoRepository() storage.PromoRepository {
+	return &promoRepository{wallets: s.walletRepository()}
+}
+
+// GetPreferencesRepository возвращает реализацию PreferencesRepository
+func (s *PostgresStorage) GetPreferencesRepository() storage.PreferencesRepository {
+	return &preferencesRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetPaymentRepository возвращает реализацию PaymentRepository
+func (s *PostgresStorage) GetPaymentRepository() storage.PaymentRepository {
+	return &paymentRepository{wallets: s.walletRepository()}
+}
+
+// GetActivityRepository возвращает реализацию ActivityRepository
+func (s *PostgresStorage) GetActivityRepository() storage.ActivityRepository {
+	return &activityRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetDeviceRepository возвращает реализацию DeviceRepository
+func (s *PostgresStorage) GetDeviceRepository() storage.DeviceRepository {
+	return &deviceRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetAlertRepository возвращает реализацию AlertRepository
+func (s *PostgresStorage) GetAlertRepository() storage.AlertRepository {
+	return &alertRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetFavoritesRepository возвращает реализацию FavoritesRepository
+func (s *PostgresStorage) GetFavoritesRepository() storage.FavoritesRepository {
+	return &favoritesRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetChatRepository возвращает реализацию ChatRepository
+func (s *PostgresStorage) GetChatRepository() storage.ChatRepository {
+	return &chatRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetRefreshTokenRepository возвращает реализацию RefreshTokenRepository
+func (s *PostgresStorage) GetRefreshTokenRepository() storage.RefreshTokenRepository {
+	return &refreshTokenRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetOperationRepository возвращает реализацию OperationRepository
+func (s *PostgresStorage) GetOperationRepository() storage.OperationRepository {
+	return &operationRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetTelegramLinkRepository возвращает реализацию TelegramLinkRepository
+func (s *PostgresStorage) GetTelegramLinkRepository() storage.TelegramLinkRepository {
+	return &telegramLinkRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetNotificationRepository возвращает реализацию NotificationRepository
+func (s *PostgresStorage) GetNotificationRepository() storage.NotificationRepository {
+	return &notificationRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetPushTokenRepository возвращает реализацию PushTokenRepository
+func (s *PostgresStorage) GetPushTokenRepository() storage.PushTokenRepository {
+	return &pushTokenRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetCurrencyFreezeRepository возвращает реализацию CurrencyFreezeRepository
+func (s *PostgresStorage) GetCurrencyFreezeRepository() storage.CurrencyFreezeRepository {
+	return &currencyFreezeRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetQueuedExchangeRepository возвращает реализацию QueuedExchangeRepository
+func (s *PostgresStorage) GetQueuedExchangeRepository() storage.QueuedExchangeRepository {
+	return &queuedExchangeRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
+}
+
+// GetDCARepository возвращает реализацию DCARepository
+func (s *PostgresStorage) GetDCARepository() storage.DCARepository {
+	return &dcaRepository{db: s.db, queryTimeout: s.opts.QueryTimeout}
 }
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"time"
+)
+
+// currencyFreezeRepository реализует интерфейс CurrencyFreezeRepository
+type currencyFreezeRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyCurrencyFreezeMigrations создает таблицу приостановок операций в валютах
+func applyCurrencyFreezeMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS currency_freezes (
+			currency VARCHAR(10) PRIMARY KEY,
+			reason VARCHAR(256) NOT NULL DEFAULT '',
+			frozen_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			frozen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы приостановок валют: %w", err)
+	}
+	return nil
+}
+
+// FreezeCurrency приостанавливает операции в валюте
+func (r *currencyFreezeRepository) FreezeCurrency(ctx context.Context, freeze *models.CurrencyFreeze) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO currency_freezes (currency, reason, frozen_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (currency) DO UPDATE
+		SET reason = EXCLUDED.reason, frozen_by = EXCLUDED.frozen_by, frozen_at = NOW()
+		RETURNING frozen_at`,
+		freeze.Currency, freeze.Reason, freeze.FrozenBy,
+	).Scan(&freeze.FrozenAt)
+	if err != nil {
+		return fmt.Errorf("ошибка приостановки операций в валюте %s: %w", freeze.Currency, err)
+	}
+	return nil
+}
+
+// UnfreezeCurrency возобновляет операции в валюте
+func (r *currencyFreezeRepository) UnfreezeCurrency(ctx context.Context, currency string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM currency_freezes WHERE currency = $1`, currency)
+	if err != nil {
+		return false, fmt.Errorf("ошибка возобновления операций в валюте %s: %w", currency, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ListCurrencyFreezes возвращает действующие приостановки по валютам
+func (r *currencyFreezeRepository) ListCurrencyFreezes(ctx context.Context) ([]models.CurrencyFreeze, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT currency, reason, COALESCE(frozen_by, 0), frozen_at
+		FROM currency_freezes
+		ORDER BY currency`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения приостановок валют: %w", err)
+	}
+	defer rows.Close()
+
+	freezes := []models.CurrencyFreeze{}
+	for rows.Next() {
+		var freeze models.CurrencyFreeze
+		if err := rows.Scan(&freeze.Currency, &freeze.Reason, &freeze.FrozenBy, &freeze.FrozenAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения приостановки валюты: %w", err)
+		}
+		freezes = append(freezes, freeze)
+	}
+	return freezes, rows.Err()
+}
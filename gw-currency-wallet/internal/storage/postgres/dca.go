@@ -0,0 +1,307 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// dcaRepository реализует интерфейс DCARepository
+type dcaRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// dcaPlanColumns - столбцы плана в порядке полей dcaPlanFields
+const dcaPlanColumns = `id, user_id, tenant_id, from_currency, to_currency, amount, period, time_of_day, timezone,
+	legs, executed, skipped, failed, status, next_run_at, created_at, finished_at`
+
+// dcaPlanFields возвращает поля плана для Scan в порядке dcaPlanColumns
+func dcaPlanFields(p *models.DCAPlan) []any {
+	return []any{
+		&p.ID, &p.UserID, &p.TenantID, &p.FromCurrency, &p.ToCurrency, &p.Amount, &p.Period, &p.TimeOfDay, &p.Timezone,
+		&p.Legs, &p.Executed, &p.Skipped, &p.Failed, &p.Status, &p.NextRunAt, &p.CreatedAt, &p.FinishedAt,
+	}
+}
+
+// applyDCAMigrations создает планы регулярных обменов и журнал их обменов
+// Номер обмена уникален в плане: обмен, прерванный сбоем, не выполняется повторно
+func applyDCAMigrations(ctx context.Context, db *sql.DB) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS dca_plans (
+			id BIGSERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			tenant_id VARCHAR(64) NOT NULL DEFAULT '',
+			from_currency VARCHAR(10) NOT NULL,
+			to_currency VARCHAR(10) NOT NULL,
+			amount NUMERIC(20, 2) NOT NULL CHECK (amount > 0),
+			period VARCHAR(16) NOT NULL,
+			time_of_day VARCHAR(5) NOT NULL,
+			timezone VARCHAR(64) NOT NULL,
+			legs INTEGER NOT NULL CHECK (legs > 0),
+			executed INTEGER NOT NULL DEFAULT 0,
+			skipped INTEGER NOT NULL DEFAULT 0,
+			failed INTEGER NOT NULL DEFAULT 0,
+			status VARCHAR(16) NOT NULL DEFAULT 'active',
+			next_run_at TIMESTAMP WITH TIME ZONE,
+			claimed_until TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			finished_at TIMESTAMP WITH TIME ZONE
+		)`,
+		`CREATE INDEX IF NOT EXISTS dca_plans_due_idx ON dca_plans (next_run_at) WHERE status = 'active'`,
+		`CREATE INDEX IF NOT EXISTS dca_plans_user_idx ON dca_plans (user_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS dca_legs (
+			plan_id BIGINT NOT NULL REFERENCES dca_plans(id) ON DELETE CASCADE,
+			number INTEGER NOT NULL,
+			scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'executing',
+			rate NUMERIC(20, 8),
+			exchanged_amount NUMERIC(20, 2),
+			error TEXT NOT NULL DEFAULT '',
+			executed_at TIMESTAMP WITH TIME ZONE,
+			PRIMARY KEY (plan_id, number)
+		)`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка создания планов регулярных обменов: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateDCAPlan сохраняет план
+func (r *dcaRepository) CreateDCAPlan(ctx context.Context, plan *models.DCAPlan) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO dca_plans (user_id, tenant_id, from_currency, to_currency, amount, period, time_of_day, timezone,
+			legs, next_run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, status, created_at`,
+		plan.UserID, plan.TenantID, plan.FromCurrency, plan.ToCurrency, plan.Amount, plan.Period, plan.TimeOfDay,
+		plan.Timezone, plan.Legs, plan.NextRunAt,
+	).Scan(&plan.ID, &plan.Status, &plan.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания плана регулярных обменов: %w", err)
+	}
+	return nil
+}
+
+// CountActiveDCAPlans возвращает количество активных планов пользователя
+func (r *dcaRepository) CountActiveDCAPlans(ctx context.Context, userID int) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM dca_plans WHERE user_id = $1 AND status = $2`,
+		userID, models.DCAPlanActive,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка подсчета планов регулярных обменов: %w", err)
+	}
+	return count, nil
+}
+
+// ListDCAPlans возвращает планы пользователя от новых к старым
+func (r *dcaRepository) ListDCAPlans(ctx context.Context, userID int) ([]models.DCAPlan, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+dcaPlanColumns+` FROM dca_plans WHERE user_id = $1 ORDER BY created_at DESC, id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения планов регулярных обменов: %w", err)
+	}
+	return scanDCAPlans(rows)
+}
+
+// GetDCAPlan возвращает план пользователя
+func (r *dcaRepository) GetDCAPlan(ctx context.Context, userID int, id int64) (*models.DCAPlan, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var plan models.DCAPlan
+	err := r.db.QueryRowContext(ctx, `
+		SELECT `+dcaPlanColumns+` FROM dca_plans WHERE id = $1 AND user_id = $2`,
+		id, userID,
+	).Scan(dcaPlanFields(&plan)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrDCAPlanNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения плана регулярных обменов %d: %w", id, err)
+	}
+	return &plan, nil
+}
+
+// ListDCALegs возвращает обмены плана от первого к последнему
+func (r *dcaRepository) ListDCALegs(ctx context.Context, planID int64) ([]models.DCALeg, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT plan_id, number, scheduled_at, status, rate, exchanged_amount, error, executed_at
+		FROM dca_legs WHERE plan_id = $1 ORDER BY number`,
+		planID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения обменов плана %d: %w", planID, err)
+	}
+	defer rows.Close()
+
+	legs := []models.DCALeg{}
+	for rows.Next() {
+		var leg models.DCALeg
+		if err := rows.Scan(
+			&leg.PlanID, &leg.Number, &leg.ScheduledAt, &leg.Status, &leg.Rate, &leg.ExchangedAmount, &leg.Error, &leg.ExecutedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения обмена плана: %w", err)
+		}
+		legs = append(legs, leg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения обменов плана: %w", err)
+	}
+	return legs, nil
+}
+
+// CancelDCAPlan отменяет активный план пользователя
+func (r *dcaRepository) CancelDCAPlan(ctx context.Context, userID int, id int64) (*models.DCAPlan, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var plan models.DCAPlan
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE dca_plans SET status = $3, next_run_at = NULL, finished_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND status = $4
+		RETURNING `+dcaPlanColumns,
+		id, userID, models.DCAPlanCancelled, models.DCAPlanActive,
+	).Scan(dcaPlanFields(&plan)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrDCAPlanNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка отмены плана регулярных обменов %d: %w", id, err)
+	}
+	return &plan, nil
+}
+
+// ClaimDueDCAPlans захватывает активные планы, время обмена которых наступило
+// SKIP LOCKED не дает двум экземплярам сервиса захватить один план
+func (r *dcaRepository) ClaimDueDCAPlans(ctx context.Context, lease time.Duration, limit int) ([]models.DCAPlan, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE dca_plans
+		SET claimed_until = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM dca_plans
+			WHERE status = $3 AND next_run_at <= NOW() AND (claimed_until IS NULL OR claimed_until < NOW())
+			ORDER BY next_run_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+dcaPlanColumns,
+		limit, lease.Seconds(), models.DCAPlanActive,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка захвата планов регулярных обменов: %w", err)
+	}
+	return scanDCAPlans(rows)
+}
+
+// StartDCALeg записывает начало обмена по плану
+func (r *dcaRepository) StartDCALeg(ctx context.Context, leg *models.DCALeg) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO dca_legs (plan_id, number, scheduled_at, status) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (plan_id, number) DO NOTHING`,
+		leg.PlanID, leg.Number, leg.ScheduledAt, models.DCALegExecuting,
+	)
+	if err != nil {
+		return false, fmt.Errorf("ошибка записи обмена %d плана %d: %w", leg.Number, leg.PlanID, err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return inserted > 0, nil
+}
+
+// FinishDCALeg записывает результат обмена и переносит план на следующий обмен
+// Отмененный во время обмена план остается отмененным
+func (r *dcaRepository) FinishDCALeg(ctx context.Context, leg *models.DCALeg, nextRunAt *time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE dca_legs SET status = $3, rate = $4, exchanged_amount = $5, error = $6, executed_at = NOW()
+		WHERE plan_id = $1 AND number = $2`,
+		leg.PlanID, leg.Number, leg.Status, leg.Rate, leg.ExchangedAmount, leg.Error,
+	); err != nil {
+		return fmt.Errorf("ошибка записи результата обмена %d плана %d: %w", leg.Number, leg.PlanID, err)
+	}
+
+	// Счетчики результатов плана
+	var executed, skipped, failed int
+	switch leg.Status {
+	case models.DCALegExecuted:
+		executed = 1
+	case models.DCALegSkipped:
+		skipped = 1
+	default:
+		failed = 1
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE dca_plans
+		SET executed = executed + $2, skipped = skipped + $3, failed = failed + $4,
+			status = CASE WHEN status = $6 AND $5::timestamptz IS NULL THEN $7 ELSE status END,
+			finished_at = CASE WHEN status = $6 AND $5::timestamptz IS NULL THEN NOW() ELSE finished_at END,
+			next_run_at = CASE WHEN status = $6 THEN $5::timestamptz END,
+			claimed_until = NULL
+		WHERE id = $1`,
+		leg.PlanID, executed, skipped, failed, nextRunAt, models.DCAPlanActive, models.DCAPlanCompleted,
+	); err != nil {
+		return fmt.Errorf("ошибка переноса плана %d: %w", leg.PlanID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// scanDCAPlans читает планы из результата запроса
+func scanDCAPlans(rows *sql.Rows) ([]models.DCAPlan, error) {
+	defer rows.Close()
+
+	plans := []models.DCAPlan{}
+	for rows.Next() {
+		var plan models.DCAPlan
+		if err := rows.Scan(dcaPlanFields(&plan)...); err != nil {
+			return nil, fmt.Errorf("ошибка чтения плана регулярных обменов: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения планов регулярных обменов: %w", err)
+	}
+	return plans, nil
+}
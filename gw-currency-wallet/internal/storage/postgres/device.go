@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"time"
+)
+
+// deviceRepository реализует интерфейс DeviceRepository
+type deviceRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyDeviceMigrations создает таблицу устройств пользователей
+func applyDeviceMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS user_devices (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			fingerprint VARCHAR(64) NOT NULL,
+			user_agent TEXT NOT NULL,
+			last_ip VARCHAR(45) NOT NULL,
+			country VARCHAR(2) NOT NULL DEFAULT '',
+			city VARCHAR(128) NOT NULL DEFAULT '',
+			confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+			first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, fingerprint)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы устройств: %w", err)
+	}
+	return nil
+}
+
+// FindDevice возвращает устройство пользователя по отпечатку или nil
+func (r *deviceRepository) FindDevice(ctx context.Context, userID int, fingerprint string) (*models.Device, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	device := &models.Device{UserID: userID, Fingerprint: fingerprint}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, user_agent, last_ip, country, city, confirmed, first_seen, last_seen
+		FROM user_devices
+		WHERE user_id = $1 AND fingerprint = $2`,
+		userID, fingerprint,
+	).Scan(&device.ID, &device.UserAgent, &device.LastIP, &device.Country, &device.City,
+		&device.Confirmed, &device.FirstSeen, &device.LastSeen)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения устройства: %w", err)
+	}
+	return device, nil
+}
+
+// SaveDevice создает устройство или обновляет данные последнего входа
+func (r *deviceRepository) SaveDevice(ctx context.Context, device *models.Device) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_devices (user_id, fingerprint, user_agent, last_ip, country, city, confirmed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, fingerprint) DO UPDATE
+		SET user_agent = EXCLUDED.user_agent,
+			last_ip = EXCLUDED.last_ip,
+			country = EXCLUDED.country,
+			city = EXCLUDED.city,
+			confirmed = user_devices.confirmed OR EXCLUDED.confirmed,
+			last_seen = NOW()
+		RETURNING id, confirmed, first_seen, last_seen`,
+		device.UserID, device.Fingerprint, device.UserAgent, device.LastIP, device.Country, device.City, device.Confirmed,
+	).Scan(&device.ID, &device.Confirmed, &device.FirstSeen, &device.LastSeen)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения устройства: %w", err)
+	}
+	return nil
+}
+
+// ListDevices возвращает устройства пользователя, начиная с последнего входа
+func (r *deviceRepository) ListDevices(ctx context.Context, userID int) ([]models.Device, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, fingerprint, user_agent, last_ip, country, city, confirmed, first_seen, last_seen
+		FROM user_devices
+		WHERE user_id = $1
+		ORDER BY last_seen DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения устройств: %w", err)
+	}
+	defer rows.Close()
+
+	devices := []models.Device{}
+	for rows.Next() {
+		device := models.Device{UserID: userID}
+		if err := rows.Scan(&device.ID, &device.Fingerprint, &device.UserAgent, &device.LastIP, &device.Country,
+			&device.City, &device.Confirmed, &device.FirstSeen, &device.LastSeen); err != nil {
+			return nil, fmt.Errorf("ошибка чтения устройства: %w", err)
+		}
+		devices = append(devices, device)
+	}
+	return devices, rows.Err()
+}
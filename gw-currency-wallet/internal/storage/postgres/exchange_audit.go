@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+)
+
+// applyExchangeAuditMigrations создает таблицу аудита обменов: точный курс, его источники,
+// время получения и котировка каждого выполненного обмена (для разбора споров о курсе)
+func applyExchangeAuditMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS exchange_audit (
+			operation_id VARCHAR(32) PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			from_currency VARCHAR(3) NOT NULL,
+			to_currency VARCHAR(3) NOT NULL,
+			rate DOUBLE PRECISION NOT NULL,
+			rate_source VARCHAR(64) NOT NULL DEFAULT '',
+			rate_timestamp TIMESTAMP WITH TIME ZONE,
+			quote_id VARCHAR(64),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы аудита обменов: %w", err)
+	}
+	return nil
+}
+
+// insertExchangeAuditTx записывает аудит обмена в транзакции изменения баланса
+// Курс сохраняется без округления, в отличие от журнала операций
+func insertExchangeAuditTx(ctx context.Context, tx *sql.Tx, operationID string, quote *models.ExchangeQuote) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO exchange_audit (operation_id, user_id, from_currency, to_currency, rate, rate_source, rate_timestamp, quote_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`,
+		operationID, quote.UserID, quote.FromCurrency, quote.ToCurrency, quote.Rate,
+		quote.RateSource, quote.RateTimestamp, quote.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи аудита обмена: %w", err)
+	}
+	return nil
+}
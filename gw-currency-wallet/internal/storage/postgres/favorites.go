@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+// favoritesRepository реализует интерфейс FavoritesRepository
+type favoritesRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyFavoritesMigrations создает таблицу избранных валют чатов Telegram бота
+func applyFavoritesMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS telegram_favorites (
+			chat_id BIGINT PRIMARY KEY,
+			currencies TEXT[] NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы избранных валют: %w", err)
+	}
+	return nil
+}
+
+// GetFavorites возвращает избранные валюты чата в порядке вывода (nil - не настроены)
+func (r *favoritesRepository) GetFavorites(ctx context.Context, chatID int64) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var currencies []string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT currencies FROM telegram_favorites WHERE chat_id = $1`,
+		chatID,
+	).Scan(pq.Array(&currencies))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения избранных валют: %w", err)
+	}
+	return currencies, nil
+}
+
+// SaveFavorites сохраняет избранные валюты чата (пустой список удаляет настройку)
+func (r *favoritesRepository) SaveFavorites(ctx context.Context, chatID int64, currencies []string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var err error
+	if len(currencies) == 0 {
+		_, err = r.db.ExecContext(ctx, `DELETE FROM telegram_favorites WHERE chat_id = $1`, chatID)
+	} else {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO telegram_favorites (chat_id, currencies)
+			VALUES ($1, $2)
+			ON CONFLICT (chat_id) DO UPDATE
+			SET currencies = EXCLUDED.currencies,
+				updated_at = NOW()`,
+			chatID, pq.Array(currencies),
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения избранных валют: %w", err)
+	}
+	return nil
+}
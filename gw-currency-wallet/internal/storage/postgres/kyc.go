@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// kycRepository реализует интерфейс KYCRepository
+type kycRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут запроса
+}
+
+// applyKYCMigrations добавляет статус верификации пользователей и таблицу документов
+func applyKYCMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS kyc_status VARCHAR(20) NOT NULL DEFAULT 'none',
+			ADD COLUMN IF NOT EXISTS kyc_comment TEXT,
+			ADD COLUMN IF NOT EXISTS kyc_reviewed_by INTEGER REFERENCES users(id),
+			ADD COLUMN IF NOT EXISTS kyc_updated_at TIMESTAMP WITH TIME ZONE
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления статуса верификации пользователей: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS kyc_documents (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			doc_type VARCHAR(30) NOT NULL,
+			file_name VARCHAR(255) NOT NULL,
+			content_type VARCHAR(100) NOT NULL,
+			size_bytes BIGINT NOT NULL,
+			storage_key VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы документов верификации: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_kyc_documents_user ON kyc_documents (user_id, created_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания индекса документов верификации: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_users_kyc_status ON users (kyc_status, kyc_updated_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания индекса статуса верификации: %w", err)
+	}
+	return nil
+}
+
+// AddDocument сохраняет документ и переводит пользователя в статус pending в одной транзакции
+func (r *kycRepository) AddDocument(ctx context.Context, doc *models.KYCDocument) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Верифицированный пользователь документы не подает; повторная подача после отказа
+	// сбрасывает комментарий администратора
+	result, err := tx.ExecContext(ctx, `
+		UPDATE users
+		SET kyc_status = 'pending', kyc_comment = NULL, kyc_updated_at = NOW()
+		WHERE id = $1 AND kyc_status <> 'verified'`,
+		doc.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка изменения статуса верификации: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return storage.ErrKYCTransition
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO kyc_documents (user_id, doc_type, file_name, content_type, size_bytes, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		doc.UserID, doc.Type, doc.FileName, doc.ContentType, doc.Size, doc.StorageKey,
+	).Scan(&doc.ID, &doc.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения документа: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// ListDocuments возвращает документы пользователя
+func (r *kycRepository) ListDocuments(ctx context.Context, userID int) ([]models.KYCDocument, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, doc_type, file_name, content_type, size_bytes, storage_key, created_at
+		FROM kyc_documents
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения документов: %w", err)
+	}
+	defer rows.Close()
+
+	docs := make([]models.KYCDocument, 0)
+	for rows.Next() {
+		var doc models.KYCDocument
+		err := rows.Scan(&doc.ID, &doc.UserID, &doc.Type, &doc.FileName, &doc.ContentType,
+			&doc.Size, &doc.StorageKey, &doc.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения документа: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения документов: %w", err)
+	}
+	return docs, nil
+}
+
+// ListUsersByKYCStatus возвращает пользователей в указанном статусе верификации
+func (r *kycRepository) ListUsersByKYCStatus(ctx context.Context, status string, limit int) ([]models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		WHERE kyc_status = $1
+		ORDER BY kyc_updated_at NULLS FIRST, id
+		LIMIT $2`,
+		status, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователей: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения пользователей: %w", err)
+	}
+	return users, nil
+}
+
+// TransitionKYC атомарно меняет статус верификации пользователя, если он находится в статусе from
+func (r *kycRepository) TransitionKYC(
+	ctx context.Context,
+	userID int,
+	from string,
+	to string,
+	adminID int,
+	comment string,
+) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET kyc_status = $1, kyc_comment = NULLIF($2, ''), kyc_reviewed_by = $3, kyc_updated_at = NOW()
+		WHERE id = $4 AND kyc_status = $5`,
+		to, comment, adminID, userID, from,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка изменения статуса верификации: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return storage.ErrKYCTransition
+	}
+	return nil
+}
@@ -0,0 +1,742 @@
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// partitionPrefix - префикс имен месячных секций журнала (transactions_p202501)
+const partitionPrefix = "transactions_p"
+
+// partitionLayout - формат месяца в имени секции
+const partitionLayout = "200601"
+
+// transactionRepository реализует интерфейс TransactionRepository для журнала операций
+type transactionRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут запроса
+}
+
+// applyLedgerMigrations создает секционированную таблицу журнала, секцию по умолчанию и архив
+//
+// Журнал секционирован по месяцам по полю created_at, поэтому первичный ключ включает created_at.
+// Секция по умолчанию страхует от ошибок вставки, если очередная месячная секция еще не создана
+func applyLedgerMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS transactions (
+			id BIGSERIAL,
+			operation_id VARCHAR(32) NOT NULL,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			type VARCHAR(20) NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			amount DECIMAL(15, 2) NOT NULL,
+			rate DECIMAL(20, 10),
+			counterparty_id INTEGER,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (id, created_at)
+		) PARTITION BY RANGE (created_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы журнала операций: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_transactions_user_created ON transactions (user_id, created_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания индекса журнала операций: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS transactions_default PARTITION OF transactions DEFAULT
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания секции журнала по умолчанию: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS transactions_archive (
+			id BIGINT PRIMARY KEY,
+			operation_id VARCHAR(32) NOT NULL,
+			user_id INTEGER NOT NULL,
+			type VARCHAR(20) NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			amount DECIMAL(15, 2) NOT NULL,
+			rate DECIMAL(20, 10),
+			counterparty_id INTEGER,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания архива журнала операций: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_transactions_archive_user_created
+		ON transactions_archive (user_id, created_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания индекса архива журнала: %w", err)
+	}
+
+	return nil
+}
+
+// backfillOpeningBalances записывает входящие остатки кошельков, созданных до появления журнала
+//
+// Для кошелька без единой записи в журнале (и архиве) по каждой ненулевой валюте добавляется запись
+// типа opening, чтобы сумма журнала совпадала с балансом. Идентификатор операции детерминирован,
+// повторный запуск ничего не меняет
+func backfillOpeningBalances(ctx context.Context, db *sql.DB) error {
+	result, err := db.ExecContext(ctx, `
+		INSERT INTO transactions (operation_id, user_id, type, currency, amount)
+		SELECT md5('opening:' || w.user_id), w.user_id, $1, c.currency, c.amount
+		FROM wallets w
+		CROSS JOIN LATERAL (VALUES ('USD', w.usd), ('RUB', w.rub), ('EUR', w.eur)) AS c(currency, amount)
+		WHERE c.amount <> 0
+			AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.user_id = w.user_id)
+			AND NOT EXISTS (SELECT 1 FROM transactions_archive a WHERE a.user_id = w.user_id)`,
+		models.TransactionOpening,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи входящих остатков в журнал: %w", err)
+	}
+	if added, _ := result.RowsAffected(); added > 0 {
+		log.Printf("В журнал записаны входящие остатки существующих кошельков: %d записей", added)
+	}
+	return nil
+}
+
+// newOperationID генерирует идентификатор операции, общий для всех ее записей в журнале
+func newOperationID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("ошибка генерации идентификатора операции: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// insertLedgerTx записывает записи журнала в рамках транзакции изменения баланса
+// Всем записям присваивается один новый идентификатор операции, который возвращается
+func insertLedgerTx(ctx context.Context, tx *sql.Tx, entries ...models.Transaction) (string, error) {
+	operationID, err := newOperationID()
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO transactions (operation_id, user_id, type, currency, amount, rate, counterparty_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			operationID, entry.UserID, entry.Type, entry.Currency, entry.Amount, entry.Rate, entry.CounterpartyID,
+		)
+		if err != nil {
+			return "", fmt.Errorf("ошибка записи в журнал операций: %w", err)
+		}
+	}
+	return operationID, nil
+}
+
+// ListTransactions возвращает записи журнала и архива пользователя за период
+// Условие по created_at позволяет планировщику читать только секции нужных месяцев,
+// архив читается по индексу (user_id, created_at)
+func (r *transactionRepository) ListTransactions(
+	ctx context.Context,
+	userID int,
+	from time.Time,
+	to time.Time,
+	limit int,
+) ([]models.Transaction, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at
+		FROM transactions
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		UNION ALL
+		SELECT id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at
+		FROM transactions_archive
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4`,
+		userID, from, to, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории операций: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTransactions(rows)
+}
+
+// ExportTransactions передает записи журнала и архива пользователя за период в fn, от старых к новым
+// Время выгрузки ограничивает только ctx: fn может писать ответ медленному клиенту
+func (r *transactionRepository) ExportTransactions(
+	ctx context.Context,
+	userID int,
+	from time.Time,
+	to time.Time,
+	fn func(models.Transaction) error,
+) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at
+		FROM transactions
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		UNION ALL
+		SELECT id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at
+		FROM transactions_archive
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at, id`,
+		userID, from, to,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка выгрузки истории операций: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения истории операций: %w", err)
+	}
+	return nil
+}
+
+// ListLedger возвращает все записи журнала пользователя и архива до момента before, от старых к новым
+func (r *transactionRepository) ListLedger(ctx context.Context, userID int, before time.Time) ([]models.Transaction, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at
+		FROM transactions
+		WHERE user_id = $1 AND created_at < $2
+		UNION ALL
+		SELECT id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at
+		FROM transactions_archive
+		WHERE user_id = $1 AND created_at < $2
+		ORDER BY created_at, id`,
+		userID, before,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения журнала операций: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTransactions(rows)
+}
+
+// GetOperationEntries возвращает записи операции владельца записи transactionID из журнала и архива
+// Записи одной операции пишутся одной транзакцией, поэтому поиск по времени ограничен сутками вокруг записи:
+// запрос использует индекс (user_id, created_at) вместо просмотра всех записей пользователя
+func (r *transactionRepository) GetOperationEntries(ctx context.Context, transactionID int64) ([]models.Transaction, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH record AS (
+			SELECT user_id, operation_id, created_at FROM transactions WHERE id = $1
+			UNION ALL
+			SELECT user_id, operation_id, created_at FROM transactions_archive WHERE id = $1
+			LIMIT 1
+		)
+		SELECT t.id, t.operation_id, t.user_id, t.type, t.currency, t.amount, t.rate, t.counterparty_id, t.created_at
+		FROM transactions t
+		JOIN record r ON t.user_id = r.user_id AND t.operation_id = r.operation_id
+			AND t.created_at BETWEEN r.created_at - INTERVAL '1 day' AND r.created_at + INTERVAL '1 day'
+		UNION ALL
+		SELECT t.id, t.operation_id, t.user_id, t.type, t.currency, t.amount, t.rate, t.counterparty_id, t.created_at
+		FROM transactions_archive t
+		JOIN record r ON t.user_id = r.user_id AND t.operation_id = r.operation_id
+			AND t.created_at BETWEEN r.created_at - INTERVAL '1 day' AND r.created_at + INTERVAL '1 day'
+		ORDER BY id`,
+		transactionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения записей операции: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanTransactions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, storage.ErrTransactionNotFound
+	}
+	return entries, nil
+}
+
+// scanTransactions читает записи журнала из результата запроса
+func scanTransactions(rows *sql.Rows) ([]models.Transaction, error) {
+	transactions := make([]models.Transaction, 0)
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения истории операций: %w", err)
+	}
+	return transactions, nil
+}
+
+// scanTransaction читает текущую запись журнала из результата запроса
+func scanTransaction(rows *sql.Rows) (models.Transaction, error) {
+	var t models.Transaction
+	var rate sql.NullFloat64
+	var counterparty sql.NullInt64
+	if err := rows.Scan(
+		&t.ID, &t.OperationID, &t.UserID, &t.Type, &t.Currency, &t.Amount, &rate, &counterparty, &t.CreatedAt,
+	); err != nil {
+		return t, fmt.Errorf("ошибка чтения записи журнала: %w", err)
+	}
+	if rate.Valid {
+		t.Rate = &rate.Float64
+	}
+	if counterparty.Valid {
+		id := int(counterparty.Int64)
+		t.CounterpartyID = &id
+	}
+	return t, nil
+}
+
+// ListExchanges собирает обмены из записей exchange_out, exchange_in и fee с общим operation_id
+// Происхождение курса и котировка берутся из аудита обменов (у обменов до его появления пусты)
+// Архив включается в запрос: история обменов нужна для налоговой отчетности за прошлые годы
+func (r *transactionRepository) ListExchanges(
+	ctx context.Context,
+	userID int,
+	from time.Time,
+	to time.Time,
+	limit int,
+) ([]models.ExchangeRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			operation_id,
+			MAX(currency) FILTER (WHERE type = $5),
+			MAX(currency) FILTER (WHERE type = $6),
+			-SUM(amount) FILTER (WHERE type = $5),
+			MAX(entries.rate) FILTER (WHERE type = $6),
+			SUM(amount) FILTER (WHERE type = $6),
+			COALESCE(-SUM(amount) FILTER (WHERE type = $7), 0),
+			MIN(entries.created_at),
+			COALESCE(MAX(a.quote_id), ''),
+			COALESCE(MAX(a.rate_source), ''),
+			MAX(a.rate_timestamp)
+		FROM (
+			SELECT operation_id, type, currency, amount, rate, created_at
+			FROM transactions
+			WHERE user_id = $1 AND created_at >= $2 AND created_at < $3 AND type IN ($5, $6, $7)
+			UNION ALL
+			SELECT operation_id, type, currency, amount, rate, created_at
+			FROM transactions_archive
+			WHERE user_id = $1 AND created_at >= $2 AND created_at < $3 AND type IN ($5, $6, $7)
+		) entries
+		LEFT JOIN exchange_audit a USING (operation_id)
+		GROUP BY operation_id
+		HAVING COUNT(*) FILTER (WHERE type = $5) > 0 AND COUNT(*) FILTER (WHERE type = $6) > 0
+		ORDER BY MIN(entries.created_at) DESC, operation_id DESC
+		LIMIT $4`,
+		userID, from, to, limit,
+		models.TransactionExchangeOut, models.TransactionExchangeIn, models.TransactionFee,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории обменов: %w", err)
+	}
+	defer rows.Close()
+
+	exchanges := make([]models.ExchangeRecord, 0)
+	for rows.Next() {
+		var e models.ExchangeRecord
+		if err := rows.Scan(
+			&e.OperationID, &e.FromCurrency, &e.ToCurrency, &e.Amount, &e.Rate, &e.ExchangedAmount, &e.Fee, &e.CreatedAt,
+			&e.QuoteID, &e.RateSource, &e.RateTimestamp,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения обмена: %w", err)
+		}
+		e.ReceivedAmount = math.Round((e.ExchangedAmount-e.Fee)*100) / 100
+		exchanges = append(exchanges, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения истории обменов: %w", err)
+	}
+	return exchanges, nil
+}
+
+// EnsurePartitions создает месячные секции журнала, начиная с месяца from
+//
+// Записи, попавшие в секцию по умолчанию (секция их месяца не была создана вовремя), сначала
+// переносятся в секции своих месяцев: иначе создание секции не пройдет проверку секции по умолчанию,
+// а архивация не увидит эти записи
+func (r *transactionRepository) EnsurePartitions(ctx context.Context, from time.Time, months int) error {
+	if err := r.drainDefaultPartition(ctx); err != nil {
+		return err
+	}
+
+	start := monthStart(from)
+	for i := 0; i < months; i++ {
+		if err := r.createPartition(ctx, start.AddDate(0, i, 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drainDefaultPartition переносит записи секции по умолчанию в месячные секции
+func (r *transactionRepository) drainDefaultPartition(ctx context.Context) error {
+	months, err := r.defaultPartitionMonths(ctx)
+	if err != nil {
+		return err
+	}
+	for _, month := range months {
+		log.Printf("В секции журнала по умолчанию есть записи за %s: переносятся в месячную секцию", month.Format("2006-01"))
+		if err := r.createPartition(ctx, month); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultPartitionMonths возвращает месяцы (UTC), записи которых лежат в секции по умолчанию
+func (r *transactionRepository) defaultPartitionMonths(ctx context.Context) ([]time.Time, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT to_char(created_at AT TIME ZONE 'UTC', 'YYYYMM')
+		FROM transactions_default
+		ORDER BY 1`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки секции журнала по умолчанию: %w", err)
+	}
+	defer rows.Close()
+
+	var months []time.Time
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("ошибка чтения секции журнала по умолчанию: %w", err)
+		}
+		month, err := time.Parse(partitionLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный месяц записи журнала %q: %w", value, err)
+		}
+		months = append(months, month)
+	}
+	return months, rows.Err()
+}
+
+// createPartition создает секцию журнала за месяц month, если ее еще нет
+//
+// Если записи этого месяца уже лежат в секции по умолчанию, в одной транзакции секция по умолчанию
+// отсоединяется, создается месячная секция, записи переносятся в нее и секция по умолчанию присоединяется снова
+func (r *transactionRepository) createPartition(ctx context.Context, month time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	name := partitionPrefix + month.Format(partitionLayout)
+	from, to := month.Format(time.RFC3339), month.AddDate(0, 1, 0).Format(time.RFC3339)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists, stray bool
+	if err := tx.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+		return fmt.Errorf("ошибка проверки секции %s: %w", name, err)
+	}
+	if exists {
+		return nil
+	}
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM transactions_default WHERE created_at >= $1 AND created_at < $2)`,
+		from, to,
+	).Scan(&stray)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки секции журнала по умолчанию: %w", err)
+	}
+
+	if stray {
+		if _, err := tx.ExecContext(ctx, "ALTER TABLE transactions DETACH PARTITION transactions_default"); err != nil {
+			return fmt.Errorf("ошибка отсоединения секции журнала по умолчанию: %w", err)
+		}
+	}
+
+	// Имя секции и границы формируются из даты, поэтому подстановка в DDL безопасна
+	query := fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s PARTITION OF transactions FOR VALUES FROM ('%s') TO ('%s')`,
+		name, from, to,
+	)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("ошибка создания секции %s: %w", name, err)
+	}
+
+	if stray {
+		result, err := tx.ExecContext(ctx, `
+			WITH moved AS (
+				DELETE FROM transactions_default
+				WHERE created_at >= $1 AND created_at < $2
+				RETURNING id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at
+			)
+			INSERT INTO `+name+` (id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at)
+			SELECT id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at FROM moved`,
+			from, to,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка переноса записей в секцию %s: %w", name, err)
+		}
+		if _, err := tx.ExecContext(ctx, "ALTER TABLE transactions ATTACH PARTITION transactions_default DEFAULT"); err != nil {
+			return fmt.Errorf("ошибка присоединения секции журнала по умолчанию: %w", err)
+		}
+		moved, _ := result.RowsAffected()
+		log.Printf("Записи журнала из секции по умолчанию перенесены в секцию %s: %d", name, moved)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return nil
+}
+
+// ArchiveTransactions переносит в архив месячные секции, которые целиком лежат до before
+//
+// Каждая секция обрабатывается в отдельной транзакции: она отсоединяется от журнала,
+// ее записи копируются в transactions_archive, после чего секция удаляется.
+// Записи секции по умолчанию предварительно переносятся в секции своих месяцев
+func (r *transactionRepository) ArchiveTransactions(ctx context.Context, before time.Time) (int64, error) {
+	if err := r.drainDefaultPartition(ctx); err != nil {
+		return 0, err
+	}
+
+	partitions, err := r.listPartitions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var archived int64
+	for _, name := range partitions {
+		month, err := time.Parse(partitionLayout, strings.TrimPrefix(name, partitionPrefix))
+		if err != nil {
+			continue // Не месячная секция (например, секция по умолчанию)
+		}
+		if month.AddDate(0, 1, 0).After(before) {
+			continue // Секция содержит записи за период хранения
+		}
+
+		moved, err := r.archivePartition(ctx, name)
+		if err != nil {
+			return archived, err
+		}
+		log.Printf("Секция журнала %s перенесена в архив (%d записей)", name, moved)
+		archived += moved
+	}
+	return archived, nil
+}
+
+// listPartitions возвращает имена всех секций журнала
+func (r *transactionRepository) listPartitions(ctx context.Context) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT child.relname
+		FROM pg_inherits
+		JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE parent.relname = 'transactions'
+		ORDER BY child.relname`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка секций журнала: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ошибка чтения списка секций журнала: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// archivePartition отсоединяет секцию, копирует ее записи в архив и удаляет секцию
+func (r *transactionRepository) archivePartition(ctx context.Context, name string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "ALTER TABLE transactions DETACH PARTITION "+name); err != nil {
+		return 0, fmt.Errorf("ошибка отсоединения секции %s: %w", name, err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO transactions_archive
+			(id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at)
+		SELECT id, operation_id, user_id, type, currency, amount, rate, counterparty_id, created_at
+		FROM `+name+`
+		ON CONFLICT (id) DO NOTHING`)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка копирования секции %s в архив: %w", name, err)
+	}
+	moved, _ := result.RowsAffected()
+
+	if _, err := tx.ExecContext(ctx, "DROP TABLE "+name); err != nil {
+		return 0, fmt.Errorf("ошибка удаления секции %s: %w", name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return moved, nil
+}
+
+// FindBalanceMismatches сравнивает балансы кошельков с суммами записей журнала и архива
+// Таймаут запроса не применяется: сверка читает журнал целиком
+func (r *transactionRepository) FindBalanceMismatches(ctx context.Context) ([]models.BalanceMismatch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH ledger AS (
+			SELECT user_id,
+				COALESCE(SUM(amount) FILTER (WHERE currency = 'USD'), 0) AS usd,
+				COALESCE(SUM(amount) FILTER (WHERE currency = 'RUB'), 0) AS rub,
+				COALESCE(SUM(amount) FILTER (WHERE currency = 'EUR'), 0) AS eur
+			FROM (
+				SELECT user_id, currency, amount FROM transactions
+				UNION ALL
+				SELECT user_id, currency, amount FROM transactions_archive
+			) entries
+			GROUP BY user_id
+		)
+		SELECT w.user_id, w.usd, w.rub, w.eur,
+			COALESCE(l.usd, 0), COALESCE(l.rub, 0), COALESCE(l.eur, 0)
+		FROM wallets w
+		LEFT JOIN ledger l ON l.user_id = w.user_id
+		WHERE w.usd <> COALESCE(l.usd, 0) OR w.rub <> COALESCE(l.rub, 0) OR w.eur <> COALESCE(l.eur, 0)
+		ORDER BY w.user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сверки балансов с журналом: %w", err)
+	}
+	defer rows.Close()
+
+	mismatches := make([]models.BalanceMismatch, 0)
+	for rows.Next() {
+		var userID int
+		var wallet, ledger models.Balance
+		if err := rows.Scan(&userID, &wallet.USD, &wallet.RUB, &wallet.EUR, &ledger.USD, &ledger.RUB, &ledger.EUR); err != nil {
+			return nil, fmt.Errorf("ошибка чтения результата сверки: %w", err)
+		}
+
+		pairs := []struct {
+			currency       string
+			wallet, ledger float64
+		}{
+			{"USD", wallet.USD, ledger.USD},
+			{"RUB", wallet.RUB, ledger.RUB},
+			{"EUR", wallet.EUR, ledger.EUR},
+		}
+		for _, p := range pairs {
+			if p.wallet == p.ledger {
+				continue
+			}
+			mismatches = append(mismatches, models.BalanceMismatch{
+				UserID:        userID,
+				Currency:      p.currency,
+				WalletBalance: p.wallet,
+				LedgerBalance: p.ledger,
+				Difference:    p.wallet - p.ledger,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения результата сверки: %w", err)
+	}
+	return mismatches, nil
+}
+
+// CountTransfers возвращает количество исходящих переводов пользователя указанному получателю
+func (r *transactionRepository) CountTransfers(ctx context.Context, fromUserID, toUserID int) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM transactions
+		WHERE user_id = $1 AND type = $2 AND counterparty_id = $3`,
+		fromUserID, models.TransactionTransferOut, toUserID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения истории переводов: %w", err)
+	}
+	return count, nil
+}
+
+// ExchangeVolume возвращает суммы обменов пользователя по исходным валютам с момента since
+// Условие по created_at ограничивает чтение секциями последних месяцев
+func (r *transactionRepository) ExchangeVolume(ctx context.Context, userID int, since time.Time) (map[string]float64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT currency, SUM(-amount)
+		FROM transactions
+		WHERE user_id = $1 AND type = $2 AND created_at >= $3
+		GROUP BY currency`,
+		userID, models.TransactionExchangeOut, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения объема обменов: %w", err)
+	}
+	defer rows.Close()
+
+	volume := make(map[string]float64)
+	for rows.Next() {
+		var currency string
+		var sum float64
+		if err := rows.Scan(&currency, &sum); err != nil {
+			return nil, fmt.Errorf("ошибка чтения объема обменов: %w", err)
+		}
+		volume[currency] = sum
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения объема обменов: %w", err)
+	}
+	return volume, nil
+}
+
+// monthStart возвращает начало месяца (UTC), к которому относится момент t
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,78 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"gw-currency-wallet/internal/testenv"
+	"testing"
+	"time"
+)
+
+// TestLedgerDefaultPartition проверяет, что записи из секции по умолчанию переносятся в месячную секцию
+// при ее создании и затем архивируются вместе с ней
+func TestLedgerDefaultPartition(t *testing.T) {
+	env := testenv.New(t)
+	ctx := context.Background()
+	ledger := env.DB.GetTransactionRepository()
+	user := env.CreateUser(t, "stray", nil)
+
+	// Секции за январь 2001 нет: запись попадает в секцию по умолчанию
+	month := time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+	_, err := env.SQL.ExecContext(ctx, `
+		INSERT INTO transactions (operation_id, user_id, type, currency, amount, created_at)
+		VALUES ('stray', $1, 'deposit', 'USD', 10, $2)`,
+		user.ID, month.Add(15*24*time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("ошибка записи в журнал: %v", err)
+	}
+
+	if err := ledger.EnsurePartitions(ctx, month, 1); err != nil {
+		t.Fatalf("ошибка создания секции при записях в секции по умолчанию: %v", err)
+	}
+
+	var inDefault, inMonth int
+	if err := env.SQL.QueryRowContext(ctx, `SELECT COUNT(*) FROM transactions_default`).Scan(&inDefault); err != nil {
+		t.Fatalf("ошибка чтения секции по умолчанию: %v", err)
+	}
+	if err := env.SQL.QueryRowContext(ctx, `SELECT COUNT(*) FROM transactions_p200101`).Scan(&inMonth); err != nil {
+		t.Fatalf("ошибка чтения месячной секции: %v", err)
+	}
+	if inDefault != 0 || inMonth != 1 {
+		t.Errorf("записей в секции по умолчанию %d, в месячной %d; ожидалось 0 и 1", inDefault, inMonth)
+	}
+
+	entries, err := ledger.ListTransactions(ctx, user.ID, month, month.AddDate(0, 1, 0), 10)
+	if err != nil {
+		t.Fatalf("ошибка чтения истории: %v", err)
+	}
+	if len(entries) != 1 || entries[0].OperationID != "stray" {
+		t.Errorf("история за месяц: %+v", entries)
+	}
+
+	// Повторная запись в секцию по умолчанию разбирается перед архивацией
+	_, err = env.SQL.ExecContext(ctx, `
+		INSERT INTO transactions (operation_id, user_id, type, currency, amount, created_at)
+		VALUES ('stray-2', $1, 'deposit', 'USD', 5, $2)`,
+		user.ID, month.AddDate(0, 1, 3),
+	)
+	if err != nil {
+		t.Fatalf("ошибка записи в журнал: %v", err)
+	}
+	archived, err := ledger.ArchiveTransactions(ctx, month.AddDate(0, 2, 0))
+	if err != nil {
+		t.Fatalf("ошибка архивации: %v", err)
+	}
+	if archived != 2 {
+		t.Errorf("перенесено в архив %d записей, ожидалось 2", archived)
+	}
+
+	entries, err = ledger.ListTransactions(ctx, user.ID, month, month.AddDate(0, 2, 0), 10)
+	if err != nil {
+		t.Fatalf("ошибка чтения истории: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("история с архивом: %d записей, ожидалось 2", len(entries))
+	}
+}
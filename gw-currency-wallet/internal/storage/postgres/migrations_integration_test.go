@@ -0,0 +1,70 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"gw-currency-wallet/internal/storage/postgres"
+	"gw-currency-wallet/internal/testenv"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestBalanceConstraintNegativeWallet проверяет, что кошелек с отрицательным балансом не прерывает миграции:
+// ограничение остается непроверенным, пока баланс не исправлен, и проверяется при следующем запуске
+func TestBalanceConstraintNegativeWallet(t *testing.T) {
+	env := testenv.New(t)
+	ctx := context.Background()
+	user := env.CreateUser(t, "negative", testenv.Funds{"USD": 10})
+
+	// Кошелек с отрицательным балансом, появившийся до добавления ограничения
+	if _, err := env.SQL.ExecContext(ctx, `ALTER TABLE wallets DROP CONSTRAINT wallets_balance_non_negative`); err != nil {
+		t.Fatalf("ошибка удаления ограничения: %v", err)
+	}
+	if _, err := env.SQL.ExecContext(ctx, `UPDATE wallets SET usd = -5 WHERE user_id = $1`, user.ID); err != nil {
+		t.Fatalf("ошибка подготовки кошелька: %v", err)
+	}
+
+	reconnect(t)
+	if validated := balanceConstraintValidated(t, env); validated {
+		t.Error("ограничение проверено при кошельке с отрицательным балансом")
+	}
+
+	if _, err := env.SQL.ExecContext(ctx, `UPDATE wallets SET usd = 0 WHERE user_id = $1`, user.ID); err != nil {
+		t.Fatalf("ошибка исправления баланса: %v", err)
+	}
+	reconnect(t)
+	if validated := balanceConstraintValidated(t, env); !validated {
+		t.Error("ограничение не проверено после исправления баланса")
+	}
+}
+
+// reconnect подключается к БД окружения заново (миграции применяются при подключении)
+func reconnect(t *testing.T) {
+	t.Helper()
+	connString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"))
+	db, err := postgres.NewPostgresStorage(context.Background(), connString, postgres.Options{
+		QueryTimeout:     5 * time.Second,
+		MigrationTimeout: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("ошибка применения миграций: %v", err)
+	}
+	db.Close()
+}
+
+// balanceConstraintValidated сообщает, проверено ли ограничение баланса для существующих кошельков
+func balanceConstraintValidated(t *testing.T, env *testenv.Env) bool {
+	t.Helper()
+	var validated bool
+	err := env.SQL.QueryRowContext(context.Background(),
+		`SELECT convalidated FROM pg_constraint WHERE conname = 'wallets_balance_non_negative'`,
+	).Scan(&validated)
+	if err != nil {
+		t.Fatalf("ошибка чтения ограничения: %v", err)
+	}
+	return validated
+}
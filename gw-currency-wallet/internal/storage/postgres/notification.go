@@ -0,0 +1,354 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+	"gw-currency-wallet/internal/models"
+	"math"
+	"time"
+)
+
+// notificationRepository реализует интерфейс NotificationRepository
+type notificationRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// notificationColumns - столбцы outbox в порядке полей notificationFields
+const notificationColumns = `id, user_id, channel, target, operation_id, payload, status, attempts, next_attempt_at,
+	last_error, created_at, sent_at`
+
+// notificationFields возвращает поля уведомления для Scan в порядке notificationColumns
+func notificationFields(n *models.Notification) []any {
+	return []any{
+		&n.ID, &n.UserID, &n.Channel, &n.Target, &n.OperationID, &n.Payload, &n.Status, &n.Attempts, &n.NextAttemptAt,
+		&n.LastError, &n.CreatedAt, &n.SentAt,
+	}
+}
+
+// applyNotificationMigrations создает outbox уведомлений пользователей об операциях с балансом
+// и правила их доставки. Ожидающие отправки уведомления отбираются фоновой задачей по частичному индексу
+func applyNotificationMigrations(ctx context.Context, db *sql.DB) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS notification_outbox (
+			id BIGSERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			channel VARCHAR(16) NOT NULL,
+			operation_id VARCHAR(32) NOT NULL,
+			payload JSONB NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS notification_outbox_pending_idx ON notification_outbox (next_attempt_at)
+			WHERE status = 'pending'`,
+		`CREATE INDEX IF NOT EXISTS notification_outbox_created_idx ON notification_outbox (created_at)`,
+		// Адрес из правила пользователя и время доставки
+		`ALTER TABLE notification_outbox ADD COLUMN IF NOT EXISTS target TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE notification_outbox ADD COLUMN IF NOT EXISTS sent_at TIMESTAMP WITH TIME ZONE`,
+		`CREATE INDEX IF NOT EXISTS notification_outbox_user_idx ON notification_outbox (user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS notification_outbox_failed_idx ON notification_outbox (created_at)
+			WHERE status = 'failed'`,
+		// Правила пользователей для доставки уведомлений по каналам
+		`CREATE TABLE IF NOT EXISTS notification_routes (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			channel VARCHAR(16) NOT NULL,
+			target TEXT NOT NULL DEFAULT '',
+			types TEXT[] NOT NULL DEFAULT '{}',
+			min_amount NUMERIC(20, 2) NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (user_id, channel)
+		)`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка создания outbox уведомлений: %w", err)
+		}
+	}
+	return nil
+}
+
+// insertNotificationsTx записывает уведомления об операции в outbox в транзакции изменения баланса
+// Каждый участник операции получает уведомления со своими записями журнала: по правилам доставки,
+// которым соответствует операция (тип записи и сумма), или, если правил у него нет, в каналы defaults.
+// Уведомления записываются только в каналы из channels (настроенные каналы), уведомление в Telegram -
+// только при привязанном аккаунте Telegram
+func insertNotificationsTx(ctx context.Context, tx *sql.Tx, operationID string, channels, defaults []string, entries ...models.Transaction) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	// Записи журнала по пользователям в порядке их появления
+	var users []int
+	byUser := make(map[int][]models.Transaction)
+	for _, entry := range entries {
+		if _, ok := byUser[entry.UserID]; !ok {
+			users = append(users, entry.UserID)
+		}
+		entry.OperationID = operationID
+		byUser[entry.UserID] = append(byUser[entry.UserID], entry)
+	}
+
+	for _, userID := range users {
+		userEntries := byUser[userID]
+		payload, err := json.Marshal(userEntries)
+		if err != nil {
+			return fmt.Errorf("ошибка записи уведомления: %w", err)
+		}
+		types := make([]string, 0, len(userEntries))
+		var maxAmount float64
+		for _, entry := range userEntries {
+			types = append(types, entry.Type)
+			maxAmount = max(maxAmount, math.Abs(entry.Amount))
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO notification_outbox (user_id, channel, target, operation_id, payload)
+			SELECT $1, channel, target, $2, $3 FROM (
+				SELECT r.channel, r.target FROM notification_routes r
+				WHERE r.user_id = $1 AND r.enabled
+					AND (cardinality(r.types) = 0 OR r.types && $6::text[])
+					AND r.min_amount <= $7
+				UNION ALL
+				SELECT channel, '' FROM unnest($5::text[]) AS channel
+				WHERE NOT EXISTS (SELECT 1 FROM notification_routes WHERE user_id = $1)
+			) routed
+			WHERE channel = ANY($4::text[])
+				AND (channel <> $8 OR EXISTS (SELECT 1 FROM telegram_links WHERE user_id = $1))`,
+			userID, operationID, payload, pq.Array(channels), pq.Array(defaults),
+			pq.Array(types), maxAmount, models.NotificationChannelTelegram,
+		); err != nil {
+			return fmt.Errorf("ошибка записи уведомления: %w", err)
+		}
+	}
+	return nil
+}
+
+// ClaimNotifications захватывает уведомления, время отправки которых наступило, на время lease
+// SKIP LOCKED не дает двум экземплярам сервиса захватить одно уведомление
+func (r *notificationRepository) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH claimed AS (
+			UPDATE notification_outbox
+			SET attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id FROM notification_outbox
+				WHERE status = $3 AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING `+notificationColumns+`
+		)
+		SELECT c.*, u.email, COALESCE(t.telegram_user_id, 0)
+		FROM claimed c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN telegram_links t ON t.user_id = c.user_id
+		ORDER BY c.id`,
+		limit, lease.Seconds(), models.NotificationPending,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка захвата уведомлений: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(append(notificationFields(&n), &n.Email, &n.TelegramChatID)...); err != nil {
+			return nil, fmt.Errorf("ошибка чтения уведомления: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения уведомлений: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkNotificationSent отмечает уведомление отправленным и запоминает время доставки
+func (r *notificationRepository) MarkNotificationSent(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE notification_outbox SET status = $2, last_error = '', sent_at = NOW() WHERE id = $1`,
+		id, models.NotificationSent,
+	); err != nil {
+		return fmt.Errorf("ошибка сохранения состояния уведомления: %w", err)
+	}
+	return nil
+}
+
+// RetryNotification откладывает повторную отправку уведомления до at
+func (r *notificationRepository) RetryNotification(ctx context.Context, id int64, errText string, at time.Time) error {
+	return r.setNotificationStatus(ctx, id, models.NotificationPending, errText, &at)
+}
+
+// FailNotification отмечает уведомление неотправленным
+func (r *notificationRepository) FailNotification(ctx context.Context, id int64, errText string) error {
+	return r.setNotificationStatus(ctx, id, models.NotificationFailed, errText, nil)
+}
+
+// setNotificationStatus записывает результат попытки отправки; nil at сохраняет прежнее время попытки
+func (r *notificationRepository) setNotificationStatus(ctx context.Context, id int64, status, errText string, at *time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE notification_outbox
+		SET status = $2, last_error = $3, next_attempt_at = COALESCE($4, next_attempt_at)
+		WHERE id = $1`,
+		id, status, errText, at,
+	); err != nil {
+		return fmt.Errorf("ошибка сохранения состояния уведомления: %w", err)
+	}
+	return nil
+}
+
+// PurgeNotifications удаляет отправленные и неотправленные уведомления, созданные раньше before
+func (r *notificationRepository) PurgeNotifications(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM notification_outbox WHERE status <> $1 AND created_at < $2`,
+		models.NotificationPending, before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления уведомлений: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ListNotifications возвращает уведомления от новых к старым (userID 0 - всех получателей)
+func (r *notificationRepository) ListNotifications(ctx context.Context, userID int, status string, limit int) ([]models.Notification, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+notificationColumns+` FROM notification_outbox
+		WHERE ($1 = 0 OR user_id = $1) AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3`,
+		userID, status, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения уведомлений: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(notificationFields(&n)...); err != nil {
+			return nil, fmt.Errorf("ошибка чтения уведомления: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения уведомлений: %w", err)
+	}
+	return notifications, nil
+}
+
+// RequeueNotification возвращает неотправленное уведомление в очередь с новым счетчиком попыток
+func (r *notificationRepository) RequeueNotification(ctx context.Context, id int64) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE notification_outbox SET status = $2, attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND status = $3`,
+		id, models.NotificationPending, models.NotificationFailed,
+	)
+	if err != nil {
+		return false, fmt.Errorf("ошибка возврата уведомления в очередь: %w", err)
+	}
+	requeued, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка возврата уведомления в очередь: %w", err)
+	}
+	return requeued > 0, nil
+}
+
+// ListNotificationRoutes возвращает правила доставки уведомлений пользователя в порядке каналов
+func (r *notificationRepository) ListNotificationRoutes(ctx context.Context, userID int) ([]models.NotificationRoute, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, channel, target, types, min_amount, enabled, updated_at
+		FROM notification_routes WHERE user_id = $1 ORDER BY channel`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения правил уведомлений: %w", err)
+	}
+	defer rows.Close()
+
+	routes := []models.NotificationRoute{}
+	for rows.Next() {
+		var route models.NotificationRoute
+		if err := rows.Scan(
+			&route.UserID, &route.Channel, &route.Target, pq.Array(&route.Types), &route.MinAmount, &route.Enabled, &route.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения правила уведомлений: %w", err)
+		}
+		routes = append(routes, route)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения правил уведомлений: %w", err)
+	}
+	return routes, nil
+}
+
+// SaveNotificationRoute создает или заменяет правило пользователя для канала
+func (r *notificationRepository) SaveNotificationRoute(ctx context.Context, route *models.NotificationRoute) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if route.Types == nil {
+		route.Types = []string{}
+	}
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO notification_routes (user_id, channel, target, types, min_amount, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, channel) DO UPDATE
+		SET target = EXCLUDED.target, types = EXCLUDED.types, min_amount = EXCLUDED.min_amount,
+			enabled = EXCLUDED.enabled, updated_at = NOW()
+		RETURNING updated_at`,
+		route.UserID, route.Channel, route.Target, pq.Array(route.Types), route.MinAmount, route.Enabled,
+	).Scan(&route.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения правила уведомлений: %w", err)
+	}
+	return nil
+}
+
+// DeleteNotificationRoute удаляет правило пользователя для канала
+func (r *notificationRepository) DeleteNotificationRoute(ctx context.Context, userID int, channel string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM notification_routes WHERE user_id = $1 AND channel = $2`,
+		userID, channel,
+	)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления правила уведомлений: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления правила уведомлений: %w", err)
+	}
+	return deleted > 0, nil
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// operationRepository реализует интерфейс OperationRepository
+type operationRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// operationColumns - столбцы операции в порядке чтения в ClaimStaleOperations
+const operationColumns = `id, user_id, kind, status, step, payload, error, attempts, created_at, updated_at`
+
+// applyOperationMigrations создает таблицу состояния многошаговых денежных операций
+// Незавершенные операции (pending, compensating) отбираются фоновой задачей компенсации по частичному индексу
+func applyOperationMigrations(ctx context.Context, db *sql.DB) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS money_operations (
+			id BIGSERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			kind VARCHAR(32) NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			step INTEGER NOT NULL DEFAULT 0 CHECK (step >= 0),
+			payload JSONB NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS money_operations_unfinished_idx ON money_operations (updated_at)
+			WHERE status IN ('pending', 'compensating')`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка создания таблицы денежных операций: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateOperation сохраняет новую операцию в состоянии pending
+func (r *operationRepository) CreateOperation(ctx context.Context, op *models.MoneyOperation) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	op.Status = models.OperationPending
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO money_operations (user_id, kind, status, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		op.UserID, op.Kind, op.Status, []byte(op.Payload),
+	).Scan(&op.ID, &op.CreatedAt, &op.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения денежной операции: %w", err)
+	}
+	return nil
+}
+
+// SetOperationStep записывает количество выполненных шагов операции
+func (r *operationRepository) SetOperationStep(ctx context.Context, id int64, step int) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE money_operations SET step = $2, updated_at = NOW() WHERE id = $1`,
+		id, step,
+	); err != nil {
+		return fmt.Errorf("ошибка сохранения шага денежной операции: %w", err)
+	}
+	return nil
+}
+
+// SetOperationStatus переводит операцию в состояние status; пустая ошибка сохраняет прежнюю
+func (r *operationRepository) SetOperationStatus(ctx context.Context, id int64, status string, errText string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE money_operations
+		SET status = $2, error = COALESCE(NULLIF($3, ''), error), updated_at = NOW()
+		WHERE id = $1`,
+		id, status, errText,
+	); err != nil {
+		return fmt.Errorf("ошибка сохранения состояния денежной операции: %w", err)
+	}
+	return nil
+}
+
+// ClaimStaleOperations захватывает незавершенные операции, не менявшиеся с момента before
+// SKIP LOCKED не дает двум экземплярам сервиса захватить одну операцию
+func (r *operationRepository) ClaimStaleOperations(ctx context.Context, before time.Time, limit int) ([]models.MoneyOperation, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE money_operations
+		SET status = $3, attempts = attempts + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM money_operations
+			WHERE status IN ($4, $3) AND updated_at < $1
+			ORDER BY updated_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+operationColumns,
+		before, limit, models.OperationCompensating, models.OperationPending,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка захвата незавершенных денежных операций: %w", err)
+	}
+	defer rows.Close()
+
+	var operations []models.MoneyOperation
+	for rows.Next() {
+		var op models.MoneyOperation
+		if err := rows.Scan(
+			&op.ID, &op.UserID, &op.Kind, &op.Status, &op.Step, &op.Payload, &op.Error, &op.Attempts, &op.CreatedAt, &op.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения денежной операции: %w", err)
+		}
+		operations = append(operations, op)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения денежных операций: %w", err)
+	}
+	return operations, nil
+}
+
+// markOperationStepTx записывает количество выполненных шагов операции из контекста (WithOperationStep)
+// в транзакции изменения баланса: шаг считается выполненным только вместе с изменением баланса
+func markOperationStepTx(ctx context.Context, tx *sql.Tx) error {
+	id, step, ok := storage.OperationStepFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE money_operations SET step = $2, updated_at = NOW() WHERE id = $1`,
+		id, step,
+	); err != nil {
+		return fmt.Errorf("ошибка сохранения шага денежной операции: %w", err)
+	}
+	return nil
+}
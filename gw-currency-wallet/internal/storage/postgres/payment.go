@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+)
+
+// paymentRepository реализует интерфейс PaymentRepository
+// Использует репозиторий кошельков для зачисления платежей через журнал операций
+type paymentRepository struct {
+	wallets *walletRepository // Репозиторий кошельков (подключение, таймаут, пороги AML)
+}
+
+// applyPaymentMigrations создает таблицу обработанных событий платежного провайдера
+func applyPaymentMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS payment_events (
+			event_id VARCHAR(128) PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			currency VARCHAR(3) NOT NULL,
+			amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+			processed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы событий платежей: %w", err)
+	}
+	return nil
+}
+
+// CreditPayment сохраняет событие и зачисляет платеж в одной транзакции
+// Первичный ключ event_id гарантирует однократное зачисление при повторных и параллельных доставках
+func (r *paymentRepository) CreditPayment(ctx context.Context, event models.PaymentEvent) (*models.PaymentCredit, error) {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	var credit *models.PaymentCredit
+	err := r.wallets.runBalanceTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		credit, err = r.creditPaymentTx(ctx, tx, event)
+		return err
+	})
+	if err != nil && !errors.Is(err, storage.ErrPaymentEventProcessed) {
+		return nil, err
+	}
+	return credit, err
+}
+
+// creditPaymentTx сохраняет событие и зачисляет платеж в транзакции и фиксирует ее
+// Для уже зачисленного события возвращает первое зачисление и storage.ErrPaymentEventProcessed
+func (r *paymentRepository) creditPaymentTx(ctx context.Context, tx *sql.Tx, event models.PaymentEvent) (*models.PaymentCredit, error) {
+	credit := &models.PaymentCredit{EventID: event.EventID}
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO payment_events (event_id, user_id, currency, amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (event_id) DO NOTHING
+		RETURNING user_id, currency, amount, processed_at`,
+		event.EventID, event.UserID, event.Currency, event.Amount,
+	).Scan(&credit.UserID, &credit.Currency, &credit.Amount, &credit.ProcessedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Событие уже зачислено: возвращаем первое зачисление (транзакция откатывается)
+		err = r.wallets.db.QueryRowContext(ctx, `
+			SELECT user_id, currency, amount, processed_at
+			FROM payment_events
+			WHERE event_id = $1`,
+			event.EventID,
+		).Scan(&credit.UserID, &credit.Currency, &credit.Amount, &credit.ProcessedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения события платежа: %w", err)
+		}
+		credit.Duplicate = true
+		return credit, storage.ErrPaymentEventProcessed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения события платежа: %w", err)
+	}
+
+	if _, err := r.wallets.updateBalanceTx(ctx, tx, event.UserID, event.Currency, event.Amount); err != nil {
+		return nil, err
+	}
+	if _, err := r.wallets.recordOperationTx(ctx, tx, models.Transaction{
+		UserID:   event.UserID,
+		Type:     models.TransactionDeposit,
+		Currency: event.Currency,
+		Amount:   event.Amount,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := r.wallets.commitBalanceTx(ctx, tx, event.UserID); err != nil {
+		return nil, fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return credit, nil
+}
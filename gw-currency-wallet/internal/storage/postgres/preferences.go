@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"time"
+)
+
+// preferencesRepository реализует интерфейс PreferencesRepository
+type preferencesRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyPreferencesMigrations создает таблицу настроек пользователей
+// Строка появляется при первом сохранении; до этого действуют настройки по умолчанию
+func applyPreferencesMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS user_preferences (
+			user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			default_currency VARCHAR(3) NOT NULL,
+			locale VARCHAR(8) NOT NULL,
+			timezone VARCHAR(64) NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы настроек пользователей: %w", err)
+	}
+	return nil
+}
+
+// GetPreferences возвращает настройки пользователя или настройки по умолчанию
+func (r *preferencesRepository) GetPreferences(ctx context.Context, userID int) (*models.UserPreferences, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	prefs := &models.UserPreferences{UserID: userID}
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		SELECT default_currency, locale, timezone, updated_at
+		FROM user_preferences
+		WHERE user_id = $1`,
+		userID,
+	).Scan(&prefs.DefaultCurrency, &prefs.Locale, &prefs.Timezone, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DefaultPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения настроек пользователя: %w", err)
+	}
+	prefs.UpdatedAt = &updatedAt
+	return prefs, nil
+}
+
+// SavePreferences создает или обновляет настройки пользователя
+func (r *preferencesRepository) SavePreferences(ctx context.Context, prefs *models.UserPreferences) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_preferences (user_id, default_currency, locale, timezone)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET default_currency = EXCLUDED.default_currency,
+			locale = EXCLUDED.locale,
+			timezone = EXCLUDED.timezone,
+			updated_at = NOW()
+		RETURNING updated_at`,
+		prefs.UserID, prefs.DefaultCurrency, prefs.Locale, prefs.Timezone,
+	).Scan(&updatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения настроек пользователя: %w", err)
+	}
+	prefs.UpdatedAt = &updatedAt
+	return nil
+}
@@ -0,0 +1,324 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"math"
+)
+
+// Действия администраторов с промокодами
+const (
+	auditPromoCreated     = "promo.created"     // Создан промокод
+	auditPromoDeactivated = "promo.deactivated" // Промокод отключен
+)
+
+// promoRepository реализует интерфейс PromoRepository
+// Использует репозиторий кошельков для зачисления бонусов через журнал операций
+type promoRepository struct {
+	wallets *walletRepository // Репозиторий кошельков (подключение, таймаут, пороги AML)
+}
+
+// promoColumns - столбцы промокода в порядке сканирования scanPromo
+const promoColumns = `id, code, kind, currency, value, max_bonus, max_uses, uses, expires_at, active, created_by, created_at`
+
+// applyPromoMigrations создает таблицы промокодов и их активаций
+func applyPromoMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS promo_codes (
+			id SERIAL PRIMARY KEY,
+			code VARCHAR(32) UNIQUE NOT NULL,
+			kind VARCHAR(10) NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			value DECIMAL(15, 2) NOT NULL CHECK (value > 0),
+			max_bonus DECIMAL(15, 2),
+			max_uses INTEGER,
+			uses INTEGER NOT NULL DEFAULT 0,
+			expires_at TIMESTAMP WITH TIME ZONE,
+			active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by INTEGER NOT NULL REFERENCES users(id),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы промокодов: %w", err)
+	}
+
+	// Каждый пользователь активирует промокод не больше одного раза
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS promo_redemptions (
+			id SERIAL PRIMARY KEY,
+			promo_id INTEGER NOT NULL REFERENCES promo_codes(id),
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			bonus DECIMAL(15, 2) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (promo_id, user_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы активаций промокодов: %w", err)
+	}
+	return nil
+}
+
+// CreatePromo сохраняет промокод и запись о нем в журнале действий администраторов
+func (r *promoRepository) CreatePromo(ctx context.Context, promo *models.PromoCode) error {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	tx, err := r.wallets.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO promo_codes (code, kind, currency, value, max_bonus, max_uses, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING id, uses, active, created_at`,
+		promo.Code, promo.Kind, promo.Currency, promo.Value, promo.MaxBonus, promo.MaxUses, promo.ExpiresAt, promo.CreatedBy,
+	).Scan(&promo.ID, &promo.Uses, &promo.Active, &promo.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrPromoExists
+		}
+		return fmt.Errorf("ошибка создания промокода: %w", err)
+	}
+
+	if err := insertAuditTx(ctx, tx, promo.CreatedBy, auditPromoCreated, nil, promo); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return nil
+}
+
+// ListPromos возвращает промокоды
+func (r *promoRepository) ListPromos(ctx context.Context, limit int) ([]models.PromoCode, error) {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	rows, err := r.wallets.db.QueryContext(ctx, `
+		SELECT `+promoColumns+`
+		FROM promo_codes
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения промокодов: %w", err)
+	}
+	defer rows.Close()
+
+	promos := make([]models.PromoCode, 0)
+	for rows.Next() {
+		promo, err := scanPromo(rows)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, *promo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения промокодов: %w", err)
+	}
+	return promos, nil
+}
+
+// DeactivatePromo отключает промокод и записывает это в журнал действий
+func (r *promoRepository) DeactivatePromo(ctx context.Context, code string, adminID int) error {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	tx, err := r.wallets.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE promo_codes SET active = FALSE WHERE code = $1 AND active`, code)
+	if err != nil {
+		return fmt.Errorf("ошибка отключения промокода: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return storage.ErrPromoUnavailable
+	}
+
+	if err := insertAuditTx(ctx, tx, adminID, auditPromoDeactivated, nil, map[string]string{"code": code}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return nil
+}
+
+// RedeemPromo активирует промокод: учитывает активацию, зачисляет пополнение (для процентного промокода)
+// и бонус отдельной операцией журнала типа promo_bonus - все в одной транзакции
+func (r *promoRepository) RedeemPromo(
+	ctx context.Context,
+	userID int,
+	code string,
+	currency string,
+	deposit float64,
+) (*models.PromoRedemption, *models.Balance, error) {
+	ctx, cancel := withTimeout(ctx, r.wallets.queryTimeout)
+	defer cancel()
+
+	var redemption *models.PromoRedemption
+	var balance *models.Balance
+	err := r.wallets.runBalanceTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		redemption, balance, err = r.redeemPromoTx(ctx, tx, userID, code, currency, deposit)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return redemption, balance, nil
+}
+
+// redeemPromoTx активирует промокод в транзакции и фиксирует ее
+func (r *promoRepository) redeemPromoTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	userID int,
+	code string,
+	currency string,
+	deposit float64,
+) (*models.PromoRedemption, *models.Balance, error) {
+	// Учитываем активацию условным обновлением: при параллельных активациях
+	// лимит max_uses не будет превышен
+	promo, err := scanPromo(tx.QueryRowContext(ctx, `
+		UPDATE promo_codes
+		SET uses = uses + 1
+		WHERE code = $1 AND active
+			AND (expires_at IS NULL OR expires_at > NOW())
+			AND (max_uses IS NULL OR uses < max_uses)
+		RETURNING `+promoColumns,
+		code,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, storage.ErrPromoUnavailable
+		}
+		return nil, nil, err
+	}
+
+	bonus, err := promoBonus(promo, currency, deposit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	redemption := &models.PromoRedemption{
+		Code:     promo.Code,
+		UserID:   userID,
+		Currency: promo.Currency,
+		Bonus:    bonus,
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO promo_redemptions (promo_id, user_id, bonus)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (promo_id, user_id) DO NOTHING
+		RETURNING id, created_at`,
+		promo.ID, userID, bonus,
+	).Scan(&redemption.ID, &redemption.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, storage.ErrPromoAlreadyUsed
+		}
+		return nil, nil, fmt.Errorf("ошибка сохранения активации промокода: %w", err)
+	}
+
+	// Пополнение, к которому применен процентный промокод
+	if deposit > 0 {
+		if _, err := r.wallets.updateBalanceTx(ctx, tx, userID, promo.Currency, deposit); err != nil {
+			return nil, nil, err
+		}
+		if _, err := r.wallets.recordOperationTx(ctx, tx, models.Transaction{
+			UserID:   userID,
+			Type:     models.TransactionDeposit,
+			Currency: promo.Currency,
+			Amount:   deposit,
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	balance, err := r.wallets.updateBalanceTx(ctx, tx, userID, promo.Currency, bonus)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := r.wallets.recordOperationTx(ctx, tx, models.Transaction{
+		UserID:   userID,
+		Type:     models.TransactionPromoBonus,
+		Currency: promo.Currency,
+		Amount:   bonus,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.wallets.commitBalanceTx(ctx, tx, userID); err != nil {
+		return nil, nil, fmt.Errorf("ошибка подтверждения транзакции: %w", err)
+	}
+	return redemption, balance, nil
+}
+
+// promoBonus рассчитывает бонус по промокоду
+// Фиксированный промокод активируется без пополнения, процентный - только с пополнением в его валюте
+func promoBonus(promo *models.PromoCode, currency string, deposit float64) (float64, error) {
+	switch promo.Kind {
+	case models.PromoFixed:
+		if deposit > 0 {
+			return 0, storage.ErrPromoNotApplicable
+		}
+		return promo.Value, nil
+
+	case models.PromoPercent:
+		if deposit <= 0 || currency != promo.Currency {
+			return 0, storage.ErrPromoNotApplicable
+		}
+		bonus := math.Round(deposit*promo.Value) / 100 // Процент с округлением до копеек
+		if promo.MaxBonus != nil && bonus > *promo.MaxBonus {
+			bonus = *promo.MaxBonus
+		}
+		if bonus <= 0 {
+			return 0, storage.ErrPromoNotApplicable
+		}
+		return bonus, nil
+	}
+	return 0, fmt.Errorf("неизвестный вид промокода: %s", promo.Kind)
+}
+
+// scanPromo читает промокод из строки результата (столбцы promoColumns)
+func scanPromo(row interface{ Scan(...interface{}) error }) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	var maxBonus sql.NullFloat64
+	var maxUses sql.NullInt64
+	var expiresAt sql.NullTime
+	err := row.Scan(&promo.ID, &promo.Code, &promo.Kind, &promo.Currency, &promo.Value, &maxBonus, &maxUses,
+		&promo.Uses, &expiresAt, &promo.Active, &promo.CreatedBy, &promo.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ошибка чтения промокода: %w", err)
+	}
+	if maxBonus.Valid {
+		promo.MaxBonus = &maxBonus.Float64
+	}
+	if maxUses.Valid {
+		uses := int(maxUses.Int64)
+		promo.MaxUses = &uses
+	}
+	if expiresAt.Valid {
+		promo.ExpiresAt = &expiresAt.Time
+	}
+	return &promo, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"time"
+)
+
+// pushTokenRepository реализует интерфейс PushTokenRepository
+type pushTokenRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyPushTokenMigrations создает таблицу токенов push уведомлений
+// Токен уникален на платформе: одна установка приложения принадлежит одному пользователю
+func applyPushTokenMigrations(ctx context.Context, db *sql.DB) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS push_tokens (
+			id BIGSERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			platform VARCHAR(8) NOT NULL,
+			token VARCHAR(512) NOT NULL,
+			device_name VARCHAR(128) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (platform, token)
+		)`,
+		`CREATE INDEX IF NOT EXISTS push_tokens_user_idx ON push_tokens (user_id)`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка создания таблицы токенов push уведомлений: %w", err)
+		}
+	}
+	return nil
+}
+
+// RegisterPushToken сохраняет токен за пользователем (повторная регистрация обновляет владельца и название)
+func (r *pushTokenRepository) RegisterPushToken(ctx context.Context, token *models.PushToken) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO push_tokens (user_id, platform, token, device_name)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (platform, token) DO UPDATE
+		SET user_id = EXCLUDED.user_id, device_name = EXCLUDED.device_name, updated_at = NOW(),
+			created_at = CASE WHEN push_tokens.user_id = EXCLUDED.user_id THEN push_tokens.created_at ELSE NOW() END
+		RETURNING id, created_at, updated_at`,
+		token.UserID, token.Platform, token.Token, token.DeviceName,
+	).Scan(&token.ID, &token.CreatedAt, &token.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения токена push уведомлений: %w", err)
+	}
+	return nil
+}
+
+// ListPushTokens возвращает токены пользователя от новых к старым
+func (r *pushTokenRepository) ListPushTokens(ctx context.Context, userID int) ([]models.PushToken, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, platform, token, device_name, created_at, updated_at
+		FROM push_tokens WHERE user_id = $1
+		ORDER BY updated_at DESC, id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения токенов push уведомлений: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []models.PushToken{}
+	for rows.Next() {
+		var token models.PushToken
+		if err := rows.Scan(
+			&token.ID, &token.UserID, &token.Platform, &token.Token, &token.DeviceName, &token.CreatedAt, &token.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения токена push уведомлений: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения токенов push уведомлений: %w", err)
+	}
+	return tokens, nil
+}
+
+// DeletePushToken удаляет токен пользователя
+func (r *pushTokenRepository) DeletePushToken(ctx context.Context, userID int, id int64) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM push_tokens WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления токена push уведомлений: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка удаления токена push уведомлений: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// RemovePushToken удаляет токен, отклоненный платформой
+func (r *pushTokenRepository) RemovePushToken(ctx context.Context, platform, token string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM push_tokens WHERE platform = $1 AND token = $2`, platform, token,
+	); err != nil {
+		return fmt.Errorf("ошибка удаления токена push уведомлений: %w", err)
+	}
+	return nil
+}
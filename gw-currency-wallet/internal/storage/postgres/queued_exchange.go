@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// queuedExchangeRepository реализует интерфейс QueuedExchangeRepository
+type queuedExchangeRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// queuedExchangeColumns - столбцы очереди обменов в порядке полей queuedExchangeFields
+const queuedExchangeColumns = `id, user_id, tenant_id, from_currency, to_currency, amount, trigger, status, execute_after,
+	rate, exchanged_amount, error, created_at, executed_at`
+
+// queuedExchangeFields возвращает поля обмена для Scan в порядке queuedExchangeColumns
+func queuedExchangeFields(e *models.QueuedExchange) []any {
+	return []any{
+		&e.ID, &e.UserID, &e.TenantID, &e.FromCurrency, &e.ToCurrency, &e.Amount, &e.Trigger, &e.Status, &e.ExecuteAfter,
+		&e.Rate, &e.ExchangedAmount, &e.Error, &e.CreatedAt, &e.ExecutedAt,
+	}
+}
+
+// applyQueuedExchangeMigrations создает очередь отложенных обменов
+// Ожидающие обмены отбираются фоновой задачей по частичному индексу
+func applyQueuedExchangeMigrations(ctx context.Context, db *sql.DB) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS queued_exchanges (
+			id BIGSERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			tenant_id VARCHAR(64) NOT NULL DEFAULT '',
+			from_currency VARCHAR(10) NOT NULL,
+			to_currency VARCHAR(10) NOT NULL,
+			amount NUMERIC(20, 2) NOT NULL CHECK (amount > 0),
+			trigger VARCHAR(16) NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			execute_after TIMESTAMP WITH TIME ZONE NOT NULL,
+			rate NUMERIC(20, 8),
+			exchanged_amount NUMERIC(20, 2),
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			claimed_at TIMESTAMP WITH TIME ZONE,
+			executed_at TIMESTAMP WITH TIME ZONE
+		)`,
+		`CREATE INDEX IF NOT EXISTS queued_exchanges_pending_idx ON queued_exchanges (trigger, execute_after)
+			WHERE status = 'pending'`,
+		`CREATE INDEX IF NOT EXISTS queued_exchanges_user_idx ON queued_exchanges (user_id, created_at)`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка создания очереди обменов: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateQueuedExchange ставит обмен в очередь
+func (r *queuedExchangeRepository) CreateQueuedExchange(ctx context.Context, exchange *models.QueuedExchange) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO queued_exchanges (user_id, tenant_id, from_currency, to_currency, amount, trigger, execute_after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, status, created_at`,
+		exchange.UserID, exchange.TenantID, exchange.FromCurrency, exchange.ToCurrency, exchange.Amount,
+		exchange.Trigger, exchange.ExecuteAfter,
+	).Scan(&exchange.ID, &exchange.Status, &exchange.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка постановки обмена в очередь: %w", err)
+	}
+	return nil
+}
+
+// CountPendingExchanges возвращает количество ожидающих исполнения обменов пользователя
+func (r *queuedExchangeRepository) CountPendingExchanges(ctx context.Context, userID int) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM queued_exchanges WHERE user_id = $1 AND status = $2`,
+		userID, models.QueuedExchangePending,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка подсчета обменов в очереди: %w", err)
+	}
+	return count, nil
+}
+
+// ListQueuedExchanges возвращает обмены пользователя в очереди от новых к старым
+func (r *queuedExchangeRepository) ListQueuedExchanges(ctx context.Context, userID int, limit int) ([]models.QueuedExchange, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+queuedExchangeColumns+`
+		FROM queued_exchanges
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`,
+		userID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения обменов в очереди: %w", err)
+	}
+	return scanQueuedExchanges(rows)
+}
+
+// ClaimQueuedExchanges захватывает ожидающие обмены, условие исполнения которых наступило
+// SKIP LOCKED не дает двум экземплярам сервиса захватить один обмен
+func (r *queuedExchangeRepository) ClaimQueuedExchanges(
+	ctx context.Context,
+	trigger string,
+	currencies []string,
+	at time.Time,
+	limit int,
+) ([]models.QueuedExchange, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE queued_exchanges
+		SET status = $3, claimed_at = NOW()
+		WHERE id IN (
+			SELECT id FROM queued_exchanges
+			WHERE status = $4 AND trigger = $1 AND execute_after <= $5
+				AND ($6::text[] IS NULL OR from_currency = ANY($6) OR to_currency = ANY($6))
+			ORDER BY execute_after, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+queuedExchangeColumns,
+		trigger, limit, models.QueuedExchangeExecuting, models.QueuedExchangePending, at, pq.Array(currencies),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка захвата обменов из очереди: %w", err)
+	}
+	return scanQueuedExchanges(rows)
+}
+
+// CancelQueuedExchange отменяет ожидающий обмен пользователя
+func (r *queuedExchangeRepository) CancelQueuedExchange(ctx context.Context, userID int, id int64) (*models.QueuedExchange, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var exchange models.QueuedExchange
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE queued_exchanges SET status = $3, executed_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND status = $4
+		RETURNING `+queuedExchangeColumns,
+		id, userID, models.QueuedExchangeCancelled, models.QueuedExchangePending,
+	).Scan(queuedExchangeFields(&exchange)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrQueuedExchangeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка отмены обмена %d: %w", id, err)
+	}
+	return &exchange, nil
+}
+
+// CompleteQueuedExchange отмечает обмен исполненным
+func (r *queuedExchangeRepository) CompleteQueuedExchange(ctx context.Context, id int64, rate, exchangedAmount float64) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE queued_exchanges
+		SET status = $2, rate = $3, exchanged_amount = $4, error = '', executed_at = NOW()
+		WHERE id = $1`,
+		id, models.QueuedExchangeExecuted, rate, exchangedAmount,
+	); err != nil {
+		return fmt.Errorf("ошибка сохранения исполнения обмена %d: %w", id, err)
+	}
+	return nil
+}
+
+// FailQueuedExchange отмечает обмен неисполненным
+func (r *queuedExchangeRepository) FailQueuedExchange(ctx context.Context, id int64, reason string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE queued_exchanges SET status = $2, error = $3, executed_at = NOW() WHERE id = $1`,
+		id, models.QueuedExchangeFailed, reason,
+	); err != nil {
+		return fmt.Errorf("ошибка сохранения отказа обмена %d: %w", id, err)
+	}
+	return nil
+}
+
+// RescheduleQueuedExchange возвращает захваченный обмен в ожидание до at
+func (r *queuedExchangeRepository) RescheduleQueuedExchange(ctx context.Context, id int64, at time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE queued_exchanges SET status = $2, execute_after = $3, claimed_at = NULL WHERE id = $1`,
+		id, models.QueuedExchangePending, at,
+	); err != nil {
+		return fmt.Errorf("ошибка переноса обмена %d: %w", id, err)
+	}
+	return nil
+}
+
+// FailStaleQueuedExchanges отмечает неисполненными обмены, исполнение которых прервано сбоем
+func (r *queuedExchangeRepository) FailStaleQueuedExchanges(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE queued_exchanges
+		SET status = $2, error = 'исполнение прервано сбоем, проверьте историю операций', executed_at = NOW()
+		WHERE status = $1 AND claimed_at < NOW() - make_interval(secs => $3)`,
+		models.QueuedExchangeExecuting, models.QueuedExchangeFailed, olderThan.Seconds(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка завершения прерванных обменов: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// scanQueuedExchanges читает обмены из результата запроса
+func scanQueuedExchanges(rows *sql.Rows) ([]models.QueuedExchange, error) {
+	defer rows.Close()
+
+	exchanges := []models.QueuedExchange{}
+	for rows.Next() {
+		var e models.QueuedExchange
+		if err := rows.Scan(queuedExchangeFields(&e)...); err != nil {
+			return nil, fmt.Errorf("ошибка чтения обмена из очереди: %w", err)
+		}
+		exchanges = append(exchanges, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения обменов из очереди: %w", err)
+	}
+	return exchanges, nil
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"time"
+)
+
+// refreshTokenRepository реализует интерфейс RefreshTokenRepository
+type refreshTokenRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyRefreshTokenMigrations создает таблицу токенов обновления сессий
+// Замененные токены хранятся до истечения: их повторное предъявление отзывает сессию
+func applyRefreshTokenMigrations(ctx context.Context, db *sql.DB) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS refresh_tokens (
+			id BIGSERIAL PRIMARY KEY,
+			session_id VARCHAR(32) NOT NULL,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			session_started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			revoked_at TIMESTAMP WITH TIME ZONE
+		)`,
+		`CREATE INDEX IF NOT EXISTS refresh_tokens_session_idx ON refresh_tokens (session_id)`,
+		`CREATE INDEX IF NOT EXISTS refresh_tokens_user_expires_idx ON refresh_tokens (user_id, expires_at)`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка создания таблицы токенов обновления: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateRefreshToken сохраняет первый токен сессии и удаляет истекшие токены пользователя
+func (r *refreshTokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM refresh_tokens WHERE user_id = $1 AND expires_at < NOW()`, token.UserID,
+	); err != nil {
+		return fmt.Errorf("ошибка удаления истекших токенов обновления: %w", err)
+	}
+	if err := insertRefreshTokenTx(ctx, tx, token); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// GetRefreshToken возвращает токен по его SHA-256 или nil
+func (r *refreshTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	token := &models.RefreshToken{TokenHash: tokenHash}
+	var revokedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, session_id, user_id, session_started_at, expires_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&token.ID, &token.SessionID, &token.UserID, &token.SessionStartedAt, &token.ExpiresAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения токена обновления: %w", err)
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
+
+// RotateRefreshToken отзывает текущий токен и сохраняет следующий в одной транзакции
+func (r *refreshTokenRepository) RotateRefreshToken(ctx context.Context, currentID int64, next *models.RefreshToken) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Условие revoked_at IS NULL пропускает только одно из параллельных обновлений одним токеном
+	result, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, currentID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка отзыва токена обновления: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+	if err := insertRefreshTokenTx(ctx, tx, next); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return true, nil
+}
+
+// RevokeRefreshSession отзывает все действующие токены сессии
+func (r *refreshTokenRepository) RevokeRefreshSession(ctx context.Context, sessionID string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE session_id = $1 AND revoked_at IS NULL`, sessionID,
+	); err != nil {
+		return fmt.Errorf("ошибка отзыва сессии: %w", err)
+	}
+	return nil
+}
+
+// insertRefreshTokenTx сохраняет токен обновления в транзакции
+func insertRefreshTokenTx(ctx context.Context, tx *sql.Tx, token *models.RefreshToken) error {
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO refresh_tokens (session_id, user_id, token_hash, session_started_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		token.SessionID, token.UserID, token.TokenHash, token.SessionStartedAt, token.ExpiresAt,
+	).Scan(&token.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения токена обновления: %w", err)
+	}
+	return nil
+}
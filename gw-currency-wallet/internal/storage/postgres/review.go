@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// reviewRepository реализует интерфейс ReviewRepository для очереди проверки антифрода
+type reviewRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут запроса
+}
+
+// reviewColumns - столбцы проверки в порядке сканирования scanReview
+const reviewColumns = `id, user_id, operation, recipient_id, currency, amount, rule, reason,
+	status, resolved_by, COALESCE(comment, ''), created_at, resolved_at`
+
+// applyReviewMigrations создает таблицу очереди проверки антифрода
+func applyReviewMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS risk_reviews (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			operation VARCHAR(20) NOT NULL,
+			recipient_id INTEGER REFERENCES users(id),
+			currency VARCHAR(3) NOT NULL,
+			amount DECIMAL(15, 2) NOT NULL,
+			rule VARCHAR(50) NOT NULL,
+			reason TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			resolved_by INTEGER REFERENCES users(id),
+			comment TEXT,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			resolved_at TIMESTAMP WITH TIME ZONE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы проверок антифрода: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_risk_reviews_status_created ON risk_reviews (status, created_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания индекса проверок антифрода: %w", err)
+	}
+	return nil
+}
+
+// CreateReview ставит операцию в очередь проверки
+func (r *reviewRepository) CreateReview(ctx context.Context, review *models.RiskReview) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO risk_reviews (user_id, operation, recipient_id, currency, amount, rule, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, status, created_at`,
+		review.UserID, review.Operation, review.RecipientID, review.Currency, review.Amount, review.Rule, review.Reason,
+	).Scan(&review.ID, &review.Status, &review.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка постановки операции на проверку: %w", err)
+	}
+	return nil
+}
+
+// ListReviews возвращает проверки в указанном статусе
+func (r *reviewRepository) ListReviews(ctx context.Context, status string, limit int) ([]models.RiskReview, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM risk_reviews
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at, id
+		LIMIT $2`,
+		status, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения очереди проверки: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]models.RiskReview, 0)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения очереди проверки: %w", err)
+	}
+	return reviews, nil
+}
+
+// TransitionReview атомарно меняет статус проверки, если она находится в статусе from
+func (r *reviewRepository) TransitionReview(
+	ctx context.Context,
+	id int,
+	from string,
+	to string,
+	adminID int,
+	comment string,
+) (*models.RiskReview, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE risk_reviews
+		SET status = $1, resolved_by = $2, comment = NULLIF($3, ''), resolved_at = NOW()
+		WHERE id = $4 AND status = $5
+		RETURNING `+reviewColumns,
+		to, adminID, comment, id, from,
+	)
+	review, err := scanReview(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+// scanReview читает проверку из строки результата (столбцы reviewColumns)
+func scanReview(row interface{ Scan(...interface{}) error }) (*models.RiskReview, error) {
+	var review models.RiskReview
+	var recipient, resolvedBy sql.NullInt64
+	var resolvedAt sql.NullTime
+	err := row.Scan(
+		&review.ID, &review.UserID, &review.Operation, &recipient, &review.Currency, &review.Amount,
+		&review.Rule, &review.Reason, &review.Status, &resolvedBy, &review.Comment, &review.CreatedAt, &resolvedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ошибка чтения проверки: %w", err)
+	}
+	if recipient.Valid {
+		id := int(recipient.Int64)
+		review.RecipientID = &id
+	}
+	if resolvedBy.Valid {
+		id := int(resolvedBy.Int64)
+		review.ResolvedBy = &id
+	}
+	if resolvedAt.Valid {
+		review.ResolvedAt = &resolvedAt.Time
+	}
+	return &review, nil
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"time"
+)
+
+// chatRepository реализует интерфейс ChatRepository
+type chatRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyChatMigrations создает таблицу чатов Telegram бота и их настроек
+func applyChatMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS telegram_chats (
+			chat_id BIGINT PRIMARY KEY,
+			type VARCHAR(20) NOT NULL,
+			title TEXT NOT NULL DEFAULT '',
+			digest_hour SMALLINT CHECK (digest_hour BETWEEN 0 AND 23),
+			digest_sent_on DATE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы чатов Telegram: %w", err)
+	}
+	return nil
+}
+
+// SaveChat добавляет чат или обновляет его тип и название (настройки не меняются)
+func (r *chatRepository) SaveChat(ctx context.Context, chat *models.TelegramChat) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO telegram_chats (chat_id, type, title)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id) DO UPDATE
+		SET type = EXCLUDED.type,
+			title = EXCLUDED.title,
+			updated_at = NOW()`,
+		chat.ChatID, chat.Type, chat.Title,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения чата: %w", err)
+	}
+	return nil
+}
+
+// GetChat возвращает чат с настройками (nil - чат не сохранен)
+func (r *chatRepository) GetChat(ctx context.Context, chatID int64) (*models.TelegramChat, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var (
+		chat       = &models.TelegramChat{ChatID: chatID}
+		digestHour sql.NullInt16
+		sentOn     sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx,
+		`SELECT type, title, digest_hour, digest_sent_on FROM telegram_chats WHERE chat_id = $1`,
+		chatID,
+	).Scan(&chat.Type, &chat.Title, &digestHour, &sentOn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения чата: %w", err)
+	}
+	if digestHour.Valid {
+		hour := int(digestHour.Int16)
+		chat.DigestHour = &hour
+	}
+	if sentOn.Valid {
+		chat.DigestSentOn = &sentOn.Time
+	}
+	return chat, nil
+}
+
+// SetDigestHour включает ежедневную сводку чата в час hour по UTC (nil - выключает)
+// Сводка за текущий день, если она уже отправлена, повторно не отправляется
+func (r *chatRepository) SetDigestHour(ctx context.Context, chatID int64, hour *int) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE telegram_chats SET digest_hour = $2, updated_at = NOW() WHERE chat_id = $1`,
+		chatID, hour,
+	)
+	if err != nil {
+		return false, fmt.Errorf("ошибка изменения сводки чата: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка получения результата изменения: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// DeleteChat удаляет чат вместе с его избранными валютами и уведомлениями о курсах
+func (r *chatRepository) DeleteChat(ctx context.Context, chatID int64) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	// Изменяющие CTE выполняются в одном запросе (атомарно)
+	_, err := r.db.ExecContext(ctx, `
+		WITH alerts AS (DELETE FROM rate_alerts WHERE chat_id = $1),
+			favorites AS (DELETE FROM telegram_favorites WHERE chat_id = $1)
+		DELETE FROM telegram_chats WHERE chat_id = $1`,
+		chatID,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления чата: %w", err)
+	}
+	return nil
+}
+
+// ClaimDigests отмечает отправленными сводки за день now, час отправки которых наступил,
+// и возвращает их чаты. Отметка выполняется до отправки, поэтому сводка не отправляется дважды
+// (в том числе несколькими экземплярами сервиса)
+func (r *chatRepository) ClaimDigests(ctx context.Context, now time.Time) ([]int64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	now = now.UTC()
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE telegram_chats
+		SET digest_sent_on = $1
+		WHERE digest_hour IS NOT NULL AND digest_hour <= $2
+			AND (digest_sent_on IS NULL OR digest_sent_on < $1)
+		RETURNING chat_id`,
+		now.Format(time.DateOnly), now.Hour(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выбора сводок для отправки: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("ошибка чтения чата сводки: %w", err)
+		}
+		chats = append(chats, chatID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки чатов сводки: %w", err)
+	}
+	return chats, nil
+}
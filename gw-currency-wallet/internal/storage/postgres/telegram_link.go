@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// telegramLinkRepository реализует интерфейс TelegramLinkRepository
+type telegramLinkRepository struct {
+	db           *sql.DB       // Подключение к базе данных
+	queryTimeout time.Duration // Таймаут одного запроса
+}
+
+// applyTelegramLinkMigrations создает таблицу привязок аккаунтов Telegram к пользователям
+func applyTelegramLinkMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS telegram_links (
+			user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			telegram_user_id BIGINT NOT NULL UNIQUE,
+			linked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы привязок Telegram: %w", err)
+	}
+	return nil
+}
+
+// LinkTelegram привязывает аккаунт Telegram к пользователю
+// Прежние привязки пользователя и аккаунта Telegram заменяются
+func (r *telegramLinkRepository) LinkTelegram(ctx context.Context, userID int, telegramUserID int64) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Аккаунт Telegram мог быть привязан к другому пользователю
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM telegram_links WHERE telegram_user_id = $1 AND user_id <> $2`,
+		telegramUserID, userID,
+	); err != nil {
+		return fmt.Errorf("ошибка снятия прежней привязки Telegram: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO telegram_links (user_id, telegram_user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET telegram_user_id = EXCLUDED.telegram_user_id, linked_at = NOW()`,
+		userID, telegramUserID,
+	); err != nil {
+		return fmt.Errorf("ошибка привязки Telegram: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// GetLinkedUser возвращает пользователя, к которому привязан аккаунт Telegram (0 - не привязан)
+func (r *telegramLinkRepository) GetLinkedUser(ctx context.Context, telegramUserID int64) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var userID int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id FROM telegram_links WHERE telegram_user_id = $1`,
+		telegramUserID,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения привязки Telegram: %w", err)
+	}
+	return userID, nil
+}
+
+// UnlinkTelegram снимает привязку аккаунта Telegram пользователя (false - привязки не было)
+func (r *telegramLinkRepository) UnlinkTelegram(ctx context.Context, userID int) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM telegram_links WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка снятия привязки Telegram: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка получения результата удаления: %w", err)
+	}
+	return affected > 0, nil
+}
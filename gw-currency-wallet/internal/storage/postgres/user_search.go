@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"strconv"
+	"strings"
+)
+
+// applyUserSearchMigrations создает индексы поиска пользователей: префикс имени и email
+// без учета регистра (text_pattern_ops для LIKE 'префикс%') и дата регистрации
+func applyUserSearchMigrations(ctx context.Context, db *sql.DB) error {
+	for _, statement := range []string{
+		`CREATE INDEX IF NOT EXISTS users_username_prefix_idx ON users (lower(username) text_pattern_ops)`,
+		`CREATE INDEX IF NOT EXISTS users_email_prefix_idx ON users (lower(email) text_pattern_ops)`,
+		`CREATE INDEX IF NOT EXISTS users_created_at_idx ON users (created_at)`,
+	} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("ошибка создания индексов поиска пользователей: %w", err)
+		}
+	}
+	return nil
+}
+
+// userSortColumns - столбцы сортировки результатов поиска пользователей
+var userSortColumns = map[string]string{
+	models.UserSortID:        "id",
+	models.UserSortCreatedAt: "created_at",
+	models.UserSortUsername:  "lower(username)",
+	models.UserSortEmail:     "lower(email)",
+}
+
+// SearchUsers ищет пользователей по фильтрам; общее количество считается в том же запросе
+func (r *userRepository) SearchUsers(ctx context.Context, search models.UserSearch) ([]models.User, int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+	if search.Query != "" {
+		where(`(lower(username) LIKE ? OR lower(email) LIKE ?)`, escapeLike(strings.ToLower(search.Query))+"%")
+	}
+	if search.TenantID != "" {
+		where(`tenant_id = ?`, search.TenantID)
+	}
+	if search.KYCStatus != "" {
+		where(`kyc_status = ?`, search.KYCStatus)
+	}
+	if search.Role != "" {
+		where(`role = ?`, search.Role)
+	}
+	if search.Plan != "" {
+		where(`plan = ?`, search.Plan)
+	}
+	if !search.From.IsZero() {
+		where(`created_at >= ?`, search.From)
+	}
+	if !search.To.IsZero() {
+		where(`created_at < ?`, search.To)
+	}
+
+	query := `SELECT ` + userColumns + `, COUNT(*) OVER () FROM users`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	order := "ASC"
+	if search.Desc {
+		order = "DESC"
+	}
+	// ID - второй ключ сортировки: порядок страниц стабилен при совпадающих значениях
+	query += fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT %d OFFSET %d`,
+		userSortColumns[search.Sort], order, order, search.Limit, search.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка поиска пользователей: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	total := 0
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(
+			&user.ID, &user.TenantID, &user.Username, &user.Email, &user.PasswordHash, &user.Role,
+			&user.Plan, &user.KYCStatus, &user.KYCComment, &user.CreatedAt, &user.UpdatedAt, &total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("ошибка чтения пользователя: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, total, rows.Err()
+}
+
+// escapeLike экранирует символы шаблона LIKE, чтобы строка поиска сравнивалась буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,57 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/testenv"
+	"io"
+	"log"
+	"testing"
+)
+
+// Бенчмарки слоя хранения для горячих путей бюджета утилиты loadtest (cmd/loadtest/budget.yaml)
+//
+//	go test -tags integration -run XXX -bench . ./internal/storage/postgres/
+
+// BenchmarkWalletRepositoryGetBalance - чтение баланса из БД (бюджет balance)
+func BenchmarkWalletRepositoryGetBalance(b *testing.B) {
+	env := testenv.New(b)
+	user := env.CreateUser(b, "bench", testenv.Funds{"USD": 100})
+	wallets := env.DB.GetWalletRepository()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := wallets.GetBalance(ctx, user.ID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkWalletRepositoryExchange - транзакция обмена 1 USD в EUR с журналом и аудитом (бюджет exchange)
+func BenchmarkWalletRepositoryExchange(b *testing.B) {
+	env := testenv.New(b)
+	user := env.CreateUser(b, "bench", testenv.Funds{"USD": 1_000_000})
+	wallets := env.DB.GetWalletRepository()
+	ctx := context.Background()
+	output := log.Writer()
+	log.SetOutput(io.Discard) // Журнал операций не относится к замерам
+	b.Cleanup(func() { log.SetOutput(output) })
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_, err := wallets.Exchange(ctx, &models.ExchangeQuote{
+			UserID:          user.ID,
+			FromCurrency:    "USD",
+			ToCurrency:      "EUR",
+			Amount:          1,
+			Rate:            0.92,
+			ExchangedAmount: 0.92,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
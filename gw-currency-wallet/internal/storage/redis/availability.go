@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"gw-currency-wallet/internal/storage"
+	"io"
+	"log"
+	"net"
+	"sync/atomic"
+)
+
+// availability отслеживает доступность Redis (хук клиента go-redis)
+// Сетевая ошибка команды переводит клиент в деградированный режим: команды, кроме PING,
+// завершаются storage.ErrCacheUnavailable без обращения к сети (без ожидания таймаутов подключения),
+// а вызывающие продолжают работу без кэша. Подключение восстанавливает удачная проверка PING (Client.Watch)
+type availability struct {
+	addr string      // Адрес Redis (для журнала)
+	down atomic.Bool // Redis недоступен
+}
+
+// BeforeProcess отклоняет команды, пока Redis недоступен (PING проверяет восстановление)
+func (a *availability) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if a.down.Load() && cmd.Name() != "ping" {
+		return ctx, storage.ErrCacheUnavailable
+	}
+	return ctx, nil
+}
+
+// AfterProcess отмечает недоступность Redis по сетевой ошибке команды
+func (a *availability) AfterProcess(_ context.Context, cmd redis.Cmder) error {
+	if cmd.Name() != "ping" && isConnectionError(cmd.Err()) {
+		a.set(cmd.Err())
+	}
+	return nil
+}
+
+// BeforeProcessPipeline отклоняет конвейер команд, пока Redis недоступен
+func (a *availability) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	if a.down.Load() {
+		return ctx, storage.ErrCacheUnavailable
+	}
+	return ctx, nil
+}
+
+// AfterProcessPipeline отмечает недоступность Redis по сетевой ошибке конвейера
+func (a *availability) AfterProcessPipeline(_ context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if isConnectionError(cmd.Err()) {
+			a.set(cmd.Err())
+			break
+		}
+	}
+	return nil
+}
+
+// set обновляет доступность Redis по результату обращения (nil - Redis ответил) и записывает смену состояния в лог
+func (a *availability) set(err error) {
+	if err == nil {
+		if a.down.CompareAndSwap(true, false) {
+			log.Printf("Redis %s снова доступен, кэширование возобновлено", a.addr)
+		}
+		return
+	}
+	if a.down.CompareAndSwap(false, true) {
+		log.Printf("ВНИМАНИЕ: Redis %s недоступен: %v. Кэширование отключено: курсы запрашиваются у сервиса обмена "+
+			"напрямую, балансы читаются из БД, попытки входа и квоты запросов считаются в памяти экземпляра, "+
+			"котировки обмена и коды подтверждения недоступны до восстановления подключения", a.addr, err)
+	}
+}
+
+// isConnectionError сообщает, что ошибка команды вызвана недоступностью Redis, а не ответом сервера
+// Отмена контекста вызывающим (например, по короткому таймауту обращения к кэшу) недоступностью не считается
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, storage.ErrCacheUnavailable) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF)
+}
@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"gw-currency-wallet/internal/models"
+	"time"
+)
+
+// bansKey - хэш блокировок IP адресов (поле - адрес, значение - блокировка в JSON)
+const bansKey = "bruteforce:bans"
+
+// BanList реализует storage.BanList поверх хэша Redis
+// Список общий для всех экземпляров сервиса
+type BanList struct {
+	client *Client // Подключение к Redis
+}
+
+// NewBanList создает список блокировок на основе подключенного клиента Redis
+func NewBanList(client *Client) *BanList {
+	return &BanList{client: client}
+}
+
+// Ban блокирует адрес до ban.ExpiresAt
+func (b *BanList) Ban(ctx context.Context, ban models.IPBan) error {
+	value, err := json.Marshal(ban)
+	if err != nil {
+		return err
+	}
+	return b.client.HSet(ctx, bansKey, ban.IP, value).Err()
+}
+
+// Get возвращает действующую блокировку адреса или nil
+func (b *BanList) Get(ctx context.Context, ip string) (*models.IPBan, error) {
+	value, err := b.client.HGet(ctx, bansKey, ip).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ban models.IPBan
+	if err := json.Unmarshal(value, &ban); err != nil {
+		return nil, err
+	}
+	if !ban.Active(time.Now()) {
+		b.client.HDel(ctx, bansKey, ip) // Истекшая блокировка удаляется при обращении
+		return nil, nil
+	}
+	return &ban, nil
+}
+
+// List возвращает действующие блокировки; истекшие удаляются
+func (b *BanList) List(ctx context.Context) ([]models.IPBan, error) {
+	values, err := b.client.HGetAll(ctx, bansKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bans := make([]models.IPBan, 0, len(values))
+	var expired []string
+	for ip, value := range values {
+		var ban models.IPBan
+		if err := json.Unmarshal([]byte(value), &ban); err != nil || !ban.Active(now) {
+			expired = append(expired, ip)
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	if len(expired) > 0 {
+		b.client.HDel(ctx, bansKey, expired...)
+	}
+	return bans, nil
+}
+
+// Unban снимает блокировку адреса
+func (b *BanList) Unban(ctx context.Context, ip string) (bool, error) {
+	removed, err := b.client.HDel(ctx, bansKey, ip).Result()
+	return removed > 0, err
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// cacheBroadcastChannel - канал pub/sub рассылки сброса кэша (сообщение - группы записей в JSON)
+const cacheBroadcastChannel = "cache:invalidate"
+
+// CacheBroadcast реализует storage.CacheBroadcast поверх pub/sub Redis
+// Сообщения не сохраняются: экземпляр, не подписанный в момент рассылки (перезапуск, недоступность Redis),
+// сброс не получит
+type CacheBroadcast struct {
+	client *Client // Подключение к Redis
+}
+
+// NewCacheBroadcast создает рассылку сброса кэша на основе подключенного клиента Redis
+func NewCacheBroadcast(client *Client) *CacheBroadcast {
+	return &CacheBroadcast{client: client}
+}
+
+// Publish рассылает сброс групп записей всем подписанным экземплярам
+func (b *CacheBroadcast) Publish(ctx context.Context, scopes []string) error {
+	message, err := json.Marshal(scopes)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, cacheBroadcastChannel, message).Err()
+}
+
+// Subscribe вызывает handler для каждого полученного сброса до отмены контекста
+// Подписка восстанавливается клиентом после разрыва соединения
+func (b *CacheBroadcast) Subscribe(ctx context.Context, handler func(scopes []string)) {
+	pubsub := b.client.Subscribe(ctx, cacheBroadcastChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var scopes []string
+			if err := json.Unmarshal([]byte(message.Payload), &scopes); err != nil {
+				log.Printf("Некорректное сообщение сброса кэша: %v", err)
+				continue
+			}
+			handler(scopes)
+		}
+	}
+}
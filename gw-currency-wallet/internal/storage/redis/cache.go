@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"gw-currency-wallet/internal/storage"
+	"strings"
+	"time"
+)
+
+// Cache реализует интерфейс storage.Cache поверх Redis
+type Cache struct {
+	client *Client // Подключение к Redis
+}
+
+// NewCache создает кэш на основе подключенного клиента Redis
+func NewCache(client *Client) *Cache {
+	return &Cache{client: client}
+}
+
+// Get возвращает значение по ключу или storage.ErrCacheMiss
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, storage.ErrCacheMiss
+	}
+	return value, err
+}
+
+// Set сохраняет значение с указанным временем жизни
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete удаляет ключи
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// deletePrefixBatch - количество ключей, проверяемых одной командой SCAN и удаляемых одной командой UNLINK
+const deletePrefixBatch = 500
+
+// DeletePrefix удаляет ключи с префиксом
+// Ключи перебираются командой SCAN (без блокировки Redis, в отличие от KEYS) и удаляются UNLINK
+// (память освобождается в фоне); ключи, созданные во время перебора, могут остаться
+func (c *Cache) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var deleted int64
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, escapePattern(prefix)+"*", deletePrefixBatch).Result()
+		if err != nil {
+			return deleted, err
+		}
+		if len(keys) > 0 {
+			n, err := c.client.Unlink(ctx, keys...).Result()
+			if err != nil {
+				return deleted, err
+			}
+			deleted += n
+		}
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
+
+// escapePattern экранирует спецсимволы шаблона SCAN MATCH
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Incr увеличивает счетчик и при его создании устанавливает время жизни
+func (c *Cache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := c.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, ttl) // Время жизни задается только новому счетчику
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// Close закрывает подключение к Redis
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// pingTimeout - максимальное время проверки подключения командой PING
+const pingTimeout = 5 * time.Second
+
 // Options содержит конфигурационные параметры для подключения к Redis
 // Используется для гибкой настройки подключения без изменения кода
 type Options struct {
@@ -18,7 +21,8 @@ type Options struct {
 // Добавляет уровень абстракции для возможного расширения функционала
 // и упрощения замены реализации в будущем
 type Client struct {
-	*redis.Client // Встраивание стандартного клиента для наследования всех методов
+	*redis.Client               // Встраивание стандартного клиента для наследования всех методов
+	availability  *availability // Доступность Redis: пока он недоступен, команды не отправляются
 }
 
 // New создает и инициализирует новый клиент Redis
@@ -29,22 +33,73 @@ type Client struct {
 //   - *Client: готовый к работе клиент
 //   - error: ошибка если подключение не удалось
 func New(opts Options) (*Client, error) {
+	client := newClient(opts)
+	if err := client.ping(context.Background()); err != nil {
+		client.Close()
+		return nil, err // Возвращаем ошибку если подключение не удалось
+	}
+	return client, nil
+}
+
+// Dial создает клиент Redis, не требуя доступности сервера
+// Если Redis не отвечает, клиент сразу работает в деградированном режиме: команды завершаются
+// ошибкой storage.ErrCacheUnavailable без обращения к сети, пока проверка Watch не восстановит подключение
+// Параметры:
+//   - opts: конфигурация подключения (адрес, пароль, номер БД)
+//
+// Возвращает:
+//   - *Client: клиент (доступность - Available)
+func Dial(opts Options) *Client {
+	client := newClient(opts)
+	_ = client.ping(context.Background()) // Недоступность записывается в лог при смене состояния
+	return client
+}
+
+// newClient создает клиент Redis с отслеживанием доступности
+func newClient(opts Options) *Client {
 	// Создание стандартного клиента Redis с указанными параметрами
 	client := redis.NewClient(&redis.Options{
 		Addr:     opts.Addr,     // Адрес сервера
 		Password: opts.Password, // Пароль (если требуется)
 		DB:       opts.DB,       // Номер базы данных
 	})
+	availability := &availability{addr: opts.Addr}
+	client.AddHook(availability)
+	return &Client{Client: client, availability: availability}
+}
+
+// Available сообщает, что Redis доступен (по результатам последней проверки или команды)
+func (c *Client) Available() bool {
+	return !c.availability.down.Load()
+}
+
+// Watch проверяет подключение к Redis командой PING с интервалом interval до отмены контекста
+// Первая удачная проверка после сбоя возобновляет отправку команд
+func (c *Client) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	// Создание контекста с таймаутом 5 секунд для проверки подключения
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = c.ping(ctx)
+		}
+	}
+}
+
+// ping проверяет подключение и обновляет доступность Redis
+func (c *Client) ping(ctx context.Context) error {
+	// Создание контекста с таймаутом для проверки подключения
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
 	defer cancel() // Освобождение ресурсов контекста
 
 	// Проверка подключения с помощью команды PING
-	if _, err := client.Ping(ctx).Result(); err != nil {
-		return nil, err // Возвращаем ошибку если подключение не удалось
+	err := c.Ping(pingCtx).Err()
+	if err != nil && ctx.Err() != nil {
+		return err // Проверка прервана остановкой сервиса
 	}
-
-	// Возвращаем обернутый клиент если подключение успешно
-	return &Client{client}, nil
+	c.availability.set(err)
+	return err
 }
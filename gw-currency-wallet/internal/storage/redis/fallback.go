@@ -0,0 +1,160 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"time"
+)
+
+// unavailable сообщает, что обращение к Redis не выполнено из-за его недоступности
+func unavailable(err error) bool {
+	return errors.Is(err, storage.ErrCacheUnavailable) || isConnectionError(err)
+}
+
+// FallbackCache - кэш счетчиков на Redis с резервным кэшем в памяти процесса
+// Пока Redis недоступен, счетчики (попытки входа, квоты запросов) считаются в резервном кэше,
+// и ограничения продолжают действовать в пределах экземпляра сервиса. После восстановления Redis
+// счетчики снова общие; удаление выполняется в обоих кэшах, чтобы сброс не терял записи резервного
+type FallbackCache struct {
+	primary  storage.Cache // Общий кэш (Redis)
+	fallback storage.Cache // Резервный кэш экземпляра (in-memory)
+}
+
+// NewFallbackCache создает кэш счетчиков с резервным кэшем на время недоступности Redis
+func NewFallbackCache(primary, fallback storage.Cache) *FallbackCache {
+	return &FallbackCache{primary: primary, fallback: fallback}
+}
+
+// Get возвращает значение из Redis, пока он недоступен - из резервного кэша
+func (c *FallbackCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.primary.Get(ctx, key)
+	if unavailable(err) {
+		return c.fallback.Get(ctx, key)
+	}
+	return value, err
+}
+
+// Set сохраняет значение в Redis, пока он недоступен - в резервном кэше
+func (c *FallbackCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := c.primary.Set(ctx, key, value, ttl)
+	if unavailable(err) {
+		return c.fallback.Set(ctx, key, value, ttl)
+	}
+	return err
+}
+
+// Delete удаляет ключи в обоих кэшах (ошибка недоступности Redis не возвращается)
+func (c *FallbackCache) Delete(ctx context.Context, keys ...string) error {
+	if err := c.fallback.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	if err := c.primary.Delete(ctx, keys...); err != nil && !unavailable(err) {
+		return err
+	}
+	return nil
+}
+
+// DeletePrefix удаляет ключи с префиксом в обоих кэшах и возвращает общее количество удаленных
+func (c *FallbackCache) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	deleted, err := c.fallback.DeletePrefix(ctx, prefix)
+	if err != nil {
+		return deleted, err
+	}
+	n, err := c.primary.DeletePrefix(ctx, prefix)
+	if err != nil && !unavailable(err) {
+		return deleted + n, err
+	}
+	return deleted + n, nil
+}
+
+// Incr увеличивает счетчик в Redis, пока он недоступен - в резервном кэше
+func (c *FallbackCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	value, err := c.primary.Incr(ctx, key, ttl)
+	if unavailable(err) {
+		return c.fallback.Incr(ctx, key, ttl)
+	}
+	return value, err
+}
+
+// Close освобождает ресурсы резервного кэша (подключение к Redis закрывает основной кэш приложения)
+func (c *FallbackCache) Close() error {
+	return c.fallback.Close()
+}
+
+// FallbackBanList - список блокировок адресов на Redis с резервным списком в памяти процесса
+// Пока Redis недоступен, блокировки записываются в резервный список; они действуют на экземпляре
+// до истечения и после восстановления Redis (проверяются вместе с общим списком)
+type FallbackBanList struct {
+	primary  storage.BanList // Общий список (Redis)
+	fallback storage.BanList // Резервный список экземпляра (in-memory)
+}
+
+// NewFallbackBanList создает список блокировок с резервным списком на время недоступности Redis
+func NewFallbackBanList(primary, fallback storage.BanList) *FallbackBanList {
+	return &FallbackBanList{primary: primary, fallback: fallback}
+}
+
+// Ban блокирует адрес в Redis, пока он недоступен - в резервном списке
+func (b *FallbackBanList) Ban(ctx context.Context, ban models.IPBan) error {
+	err := b.primary.Ban(ctx, ban)
+	if unavailable(err) {
+		return b.fallback.Ban(ctx, ban)
+	}
+	return err
+}
+
+// Get возвращает действующую блокировку адреса из общего или резервного списка
+func (b *FallbackBanList) Get(ctx context.Context, ip string) (*models.IPBan, error) {
+	ban, err := b.primary.Get(ctx, ip)
+	if err != nil && !unavailable(err) {
+		return nil, err
+	}
+	if ban != nil {
+		return ban, nil
+	}
+	return b.fallback.Get(ctx, ip)
+}
+
+// List возвращает действующие блокировки общего и резервного списков
+// Пока Redis недоступен, возвращаются только блокировки резервного списка
+func (b *FallbackBanList) List(ctx context.Context) ([]models.IPBan, error) {
+	bans, err := b.primary.List(ctx)
+	if err != nil && !unavailable(err) {
+		return nil, err
+	}
+	local, err := b.fallback.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ban := range local {
+		if !containsBan(bans, ban.IP) {
+			bans = append(bans, ban)
+		}
+	}
+	return bans, nil
+}
+
+// Unban снимает блокировку адреса в обоих списках
+func (b *FallbackBanList) Unban(ctx context.Context, ip string) (bool, error) {
+	removed, err := b.fallback.Unban(ctx, ip)
+	if err != nil {
+		return false, err
+	}
+	shared, err := b.primary.Unban(ctx, ip)
+	if err != nil && !unavailable(err) {
+		return removed, err
+	}
+	return removed || shared, nil
+}
+
+// containsBan сообщает, что среди блокировок есть блокировка адреса ip
+func containsBan(bans []models.IPBan, ip string) bool {
+	for _, ban := range bans {
+		if ban.IP == ip {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"gw-currency-wallet/internal/storage/memory"
+	"io"
+	"log"
+	"testing"
+	"time"
+)
+
+// newUnavailableClient возвращает клиент Redis, работающий в деградированном режиме (сервер не отвечает)
+func newUnavailableClient(t *testing.T) *Client {
+	t.Helper()
+	output := log.Writer()
+	log.SetOutput(io.Discard) // Предупреждение о недоступности Redis не нужно в выводе тестов
+	t.Cleanup(func() { log.SetOutput(output) })
+
+	client := Dial(Options{Addr: "127.0.0.1:1"})
+	t.Cleanup(func() { client.Close() })
+	if client.Available() {
+		t.Fatal("Redis по адресу 127.0.0.1:1 доступен")
+	}
+	return client
+}
+
+func TestFallbackCacheRedisUnavailable(t *testing.T) {
+	ctx := context.Background()
+	client := newUnavailableClient(t)
+	if _, err := NewCache(client).Incr(ctx, "counter", time.Minute); !errors.Is(err, storage.ErrCacheUnavailable) {
+		t.Fatalf("ошибка счетчика без резервного кэша: %v", err)
+	}
+
+	fallback := memory.NewCache(100)
+	t.Cleanup(func() { fallback.Close() })
+	cache := NewFallbackCache(NewCache(client), fallback)
+
+	t.Run("счетчик считается в резервном кэше", func(t *testing.T) {
+		for want := int64(1); want <= 3; want++ {
+			got, err := cache.Incr(ctx, "counter", time.Minute)
+			if err != nil {
+				t.Fatalf("ошибка счетчика: %v", err)
+			}
+			if got != want {
+				t.Errorf("счетчик %d, ожидалось %d", got, want)
+			}
+		}
+	})
+
+	t.Run("значение читается из резервного кэша", func(t *testing.T) {
+		if err := cache.Set(ctx, "plan", []byte("pro"), time.Minute); err != nil {
+			t.Fatalf("ошибка сохранения: %v", err)
+		}
+		value, err := cache.Get(ctx, "plan")
+		if err != nil || string(value) != "pro" {
+			t.Errorf("значение %q, ошибка %v", value, err)
+		}
+	})
+
+	t.Run("удаление без ошибки недоступности", func(t *testing.T) {
+		if err := cache.Delete(ctx, "plan"); err != nil {
+			t.Fatalf("ошибка удаления: %v", err)
+		}
+		if _, err := cache.Get(ctx, "plan"); !errors.Is(err, storage.ErrCacheMiss) {
+			t.Errorf("удаленное значение: ошибка %v, ожидался промах", err)
+		}
+		if _, err := cache.DeletePrefix(ctx, "counter"); err != nil {
+			t.Errorf("ошибка удаления по префиксу: %v", err)
+		}
+	})
+}
+
+func TestFallbackBanListRedisUnavailable(t *testing.T) {
+	ctx := context.Background()
+	bans := NewFallbackBanList(NewBanList(newUnavailableClient(t)), memory.NewBanList())
+	ban := models.IPBan{IP: "192.0.2.1", BannedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)}
+
+	if err := bans.Ban(ctx, ban); err != nil {
+		t.Fatalf("ошибка блокировки: %v", err)
+	}
+	got, err := bans.Get(ctx, ban.IP)
+	if err != nil || got == nil {
+		t.Fatalf("блокировка %+v, ошибка %v", got, err)
+	}
+	list, err := bans.List(ctx)
+	if err != nil || len(list) != 1 {
+		t.Errorf("список блокировок %+v, ошибка %v", list, err)
+	}
+
+	removed, err := bans.Unban(ctx, ban.IP)
+	if err != nil || !removed {
+		t.Errorf("снятие блокировки: %v, ошибка %v", removed, err)
+	}
+	if got, err := bans.Get(ctx, ban.IP); err != nil || got != nil {
+		t.Errorf("блокировка после снятия %+v, ошибка %v", got, err)
+	}
+}
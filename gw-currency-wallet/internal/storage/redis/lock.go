@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"os"
+	"time"
+)
+
+// acquireScript захватывает блокировку или продлевает ее, если она уже принадлежит владельцу
+var acquireScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// extendScript продлевает блокировку, только если она принадлежит владельцу
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript снимает блокировку, только если она принадлежит владельцу
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Locker реализует распределенные блокировки в Redis для нескольких экземпляров сервиса
+// Блокировка - ключ с идентификатором владельца и временем жизни: если экземпляр остановился,
+// не сняв блокировку, она освобождается по истечении времени жизни
+type Locker struct {
+	client *Client // Подключение к Redis
+	owner  string  // Уникальный идентификатор экземпляра (хост и случайный суффикс)
+}
+
+// NewLocker создает блокировки на основе подключенного клиента Redis
+func NewLocker(client *Client) (*Locker, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("ошибка генерации идентификатора владельца: %w", err)
+	}
+	host, _ := os.Hostname()
+	return &Locker{client: client, owner: host + "-" + hex.EncodeToString(suffix)}, nil
+}
+
+// Acquire захватывает блокировку на ttl
+// Повторный захват своей блокировки продлевает ее (экземпляр остается владельцем)
+// Возвращает:
+//   - bool: true если блокировка принадлежит этому экземпляру
+//   - error: ошибка обращения к Redis
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.run(ctx, acquireScript, key, ttl)
+}
+
+// Extend продлевает свою блокировку на ttl
+// Возвращает false, если блокировка истекла или захвачена другим экземпляром
+func (l *Locker) Extend(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.run(ctx, extendScript, key, ttl)
+}
+
+// Release снимает свою блокировку (чужая блокировка не затрагивается)
+func (l *Locker) Release(ctx context.Context, key string) error {
+	return releaseScript.Run(ctx, l.client, []string{key}, l.owner).Err()
+}
+
+// run выполняет скрипт блокировки с идентификатором владельца и временем жизни
+func (l *Locker) run(ctx context.Context, script *redis.Script, key string, ttl time.Duration) (bool, error) {
+	result, err := script.Run(ctx, l.client, []string{key}, l.owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
@@ -2,37 +2,111 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"gw-currency-wallet/internal/models"
+	"io"
+	"time"
 )
 
+// ErrWalletUnavailable возвращается при изменении баланса, если кошелек не найден или заблокирован
+var ErrWalletUnavailable = errors.New("кошелек не найден или заблокирован")
+
+// Ошибки уникальности данных пользователя (в пределах арендатора)
+var (
+	// ErrUsernameTaken возвращается при создании пользователя с занятым именем
+	ErrUsernameTaken = errors.New("пользователь с таким именем уже существует")
+	// ErrEmailTaken возвращается при создании пользователя с занятым email
+	ErrEmailTaken = errors.New("пользователь с таким email уже существует")
+)
+
+// ErrCacheMiss возвращается кэшем, если ключ отсутствует или срок его жизни истек
+var ErrCacheMiss = errors.New("ключ не найден в кэше")
+
+// ErrCacheUnavailable возвращается кэшем, пока его хранилище (Redis) недоступно
+var ErrCacheUnavailable = errors.New("кэш недоступен")
+
+// Cache определяет контракт кэша ключ-значение с ограниченным временем жизни записей
+// Реализуется Redis (общий кэш для всех экземпляров сервиса) и in-memory кэшем
+// (для разработки и небольших установок с одним экземпляром)
+type Cache interface {
+	// Get возвращает значение по ключу или ErrCacheMiss, если ключа нет
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set сохраняет значение с указанным временем жизни
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete удаляет ключи (отсутствующие ключи игнорируются)
+	Delete(ctx context.Context, keys ...string) error
+
+	// DeletePrefix удаляет все ключи с префиксом и возвращает количество удаленных
+	// Перебирает ключи хранилища: используется для редкого сброса групп записей администратором
+	DeletePrefix(ctx context.Context, prefix string) (int64, error)
+
+	// Incr атомарно увеличивает счетчик на 1 и возвращает новое значение
+	// Время жизни ttl устанавливается при создании счетчика (используется для ограничения частоты запросов)
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+
+	// Close освобождает ресурсы кэша
+	Close() error
+}
+
+// CacheBroadcast рассылает сброс групп записей кэша всем экземплярам сервиса
+// Реализуется Redis (pub/sub); без Redis экземпляр один и рассылка не требуется
+type CacheBroadcast interface {
+	// Publish рассылает сброс групп записей (включая отправивший экземпляр)
+	Publish(ctx context.Context, scopes []string) error
+
+	// Subscribe вызывает handler для каждого полученного сброса до отмены контекста
+	Subscribe(ctx context.Context, handler func(scopes []string))
+}
+
+// BanList определяет контракт списка временно заблокированных IP адресов
+// Реализуется Redis (общий список для всех экземпляров сервиса) и in-memory списком
+// Блокировки с истекшим сроком не возвращаются и удаляются при обращении
+type BanList interface {
+	// Ban блокирует адрес до ban.ExpiresAt (заменяет действующую блокировку адреса)
+	Ban(ctx context.Context, ban models.IPBan) error
+
+	// Get возвращает действующую блокировку адреса или nil, если адрес не заблокирован
+	Get(ctx context.Context, ip string) (*models.IPBan, error)
+
+	// List возвращает действующие блокировки
+	List(ctx context.Context) ([]models.IPBan, error)
+
+	// Unban снимает блокировку адреса; false - адрес не был заблокирован
+	Unban(ctx context.Context, ip string) (bool, error)
+}
+
 // UserRepository определяет контракт для работы с данными пользователей
 // Интерфейс абстрагирует работу с хранилищем и позволяет легко подменять реализации
 type UserRepository interface {
-	// CreateUser создает нового пользователя в хранилище
+	// CreateUser создает нового пользователя в хранилище вместе с пустым кошельком (атомарно)
 	// Принимает:
 	//   - ctx: контекст для контроля времени выполнения и отмены
 	//   - user: указатель на объект пользователя для создания
 	// Возвращает:
-	//   - error: ошибка при создании (например, если пользователь уже существует)
+	//   - error: ErrUsernameTaken или ErrEmailTaken, если имя или email заняты, или ошибка при создании
 	CreateUser(ctx context.Context, user *models.User) error
 
-	// GetUserByUsername находит пользователя по имени пользователя
+	// GetUserByUsername находит пользователя арендатора по имени пользователя
 	// Принимает:
 	//   - ctx: контекст выполнения
+	//   - tenantID: арендатор (имена уникальны в пределах арендатора)
 	//   - username: имя пользователя для поиска
 	// Возвращает:
 	//   - *models.User: найденный пользователь или nil если не найден
 	//   - error: ошибка при выполнении запроса
-	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	GetUserByUsername(ctx context.Context, tenantID, username string) (*models.User, error)
 
-	// GetUserByEmail находит пользователя по email
+	// GetUserByEmail находит пользователя арендатора по email
 	// Принимает:
 	//   - ctx: контекст выполнения
+	//   - tenantID: арендатор (email уникален в пределах арендатора)
 	//   - email: email для поиска
 	// Возвращает:
 	//   - *models.User: найденный пользователь или nil если не найден
 	//   - error: ошибка при выполнении запроса
-	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	GetUserByEmail(ctx context.Context, tenantID, email string) (*models.User, error)
 
 	// GetUserByID находит пользователя по идентификатору
 	// Принимает:
@@ -42,6 +116,71 @@ type UserRepository interface {
 	//   - *models.User: найденный пользователь или nil если не найден
 	//   - error: ошибка при выполнении запроса
 	GetUserByID(ctx context.Context, id int) (*models.User, error)
+
+	// SetRole назначает роль пользователю
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - role: новая роль (models.RoleUser, models.RoleAdmin)
+	// Возвращает:
+	//   - error: ошибка при обновлении
+	SetRole(ctx context.Context, userID int, role string) error
+
+	// SetPlan назначает тарифный план пользователю
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - plan: тарифный план (models.PlanFree, models.PlanPremium)
+	// Возвращает:
+	//   - bool: false, если пользователь не найден
+	//   - error: ошибка при обновлении
+	SetPlan(ctx context.Context, userID int, plan string) (bool, error)
+
+	// SearchUsers ищет пользователей по префиксу имени или email и фильтрам (администрирование)
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - search: фильтры, сортировка и страница (поле сортировки проверено вызывающим)
+	// Возвращает:
+	//   - []models.User: пользователи страницы
+	//   - int: количество пользователей, подходящих под фильтры (0, если страница за пределами результатов)
+	//   - error: ошибка при выполнении запроса
+	SearchUsers(ctx context.Context, search models.UserSearch) ([]models.User, int, error)
+}
+
+// dryRunKey - ключ признака пробного выполнения в контексте
+type dryRunKey struct{}
+
+// WithDryRun возвращает контекст пробного выполнения: операции с балансом проходят все проверки
+// в транзакции и возвращают результат, но транзакция откатывается вместо фиксации
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun сообщает, выполняется ли операция в пробном режиме
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// operationStepKey - ключ шага многошаговой денежной операции в контексте
+type operationStepKey struct{}
+
+// operationStep - операция и количество ее выполненных шагов после изменения баланса
+type operationStep struct {
+	id   int64
+	step int
+}
+
+// WithOperationStep возвращает контекст шага многошаговой операции: транзакция изменения баланса
+// при фиксации записывает в состояние операции id количество выполненных шагов step
+func WithOperationStep(ctx context.Context, id int64, step int) context.Context {
+	return context.WithValue(ctx, operationStepKey{}, operationStep{id: id, step: step})
+}
+
+// OperationStepFromContext возвращает операцию и количество ее выполненных шагов из контекста (false - вне операции)
+func OperationStepFromContext(ctx context.Context) (int64, int, bool) {
+	s, ok := ctx.Value(operationStepKey{}).(operationStep)
+	return s.id, s.step, ok
 }
 
 // WalletRepository определяет контракт для работы с финансовыми операциями
@@ -53,16 +192,30 @@ type WalletRepository interface {
 	//   - userID: идентификатор пользователя
 	// Возвращает:
 	//   - *models.Balance: структура с балансами по валютам
-	//   - error: ошибка при получении баланса
+	//   - error: ErrWalletUnavailable, если кошелька нет, или ошибка при получении баланса
 	GetBalance(ctx context.Context, userID int) (*models.Balance, error)
 
-	// CreateWallet создает новый кошелек для пользователя
+	// GetCachedBalance возвращает баланс пользователя для отображения: из кэша балансов, если он включен
+	// Баланс может отставать от БД не дольше времени жизни кэша, поэтому для проверки
+	// достаточности средств используется GetBalance
 	// Принимает:
 	//   - ctx: контекст выполнения
 	//   - userID: идентификатор пользователя
 	// Возвращает:
-	//   - error: ошибка при создании
-	CreateWallet(ctx context.Context, userID int) error
+	//   - *models.Balance: структура с балансами по валютам
+	//   - error: ошибка при получении баланса
+	GetCachedBalance(ctx context.Context, userID int) (*models.Balance, error)
+
+	// ListBalances возвращает балансы нескольких пользователей одним запросом (по возрастанию ID пользователя)
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userIDs: пользователи (пусто - все пользователи)
+	//   - afterID: ID пользователя, после которого продолжается список (0 - с начала)
+	//   - limit: максимальное количество балансов
+	// Возвращает:
+	//   - []models.UserBalance: балансы; пользователи без кошелька пропускаются
+	//   - error: ошибка при выполнении запроса
+	ListBalances(ctx context.Context, userIDs []int, afterID, limit int) ([]models.UserBalance, error)
 
 	// UpdateBalance изменяет баланс пользователя для указанной валюты
 	// Принимает:
@@ -72,7 +225,7 @@ type WalletRepository interface {
 	//   - amount: сумма для изменения (может быть отрицательной)
 	// Возвращает:
 	//   - *models.Balance: новый баланс после изменения
-	//   - error: ошибка при обновлении
+	//   - error: ErrInsufficientFunds, если списание превышает баланс, или ошибка при обновлении
 	UpdateBalance(ctx context.Context, userID int, currency string, amount float64) (*models.Balance, error)
 
 	// Transfer выполняет перевод средств между пользователями
@@ -86,7 +239,7 @@ type WalletRepository interface {
 	// Возвращает:
 	//   - *models.Balance: новый баланс отправителя
 	//   - *models.Balance: новый баланс получателя
-	//   - error: ошибка при переводе
+	//   - error: ErrInsufficientFunds, если сумма превышает баланс отправителя, или ошибка при переводе
 	Transfer(
 		ctx context.Context,
 		fromUserID int,
@@ -95,24 +248,997 @@ type WalletRepository interface {
 		amount float64,
 	) (*models.Balance, *models.Balance, error)
 
-	// Exchange выполняет обмен валюты для пользователя
-	// Должен выполняться атомарно в рамках транзакции
+	// Exchange выполняет обмен валюты для пользователя по расчету обмена
+	// Должен выполняться атомарно в рамках транзакции вместе с записью аудита обмена:
+	// курс, его источники, время получения и идентификатор котировки (если обмен подтвержден по котировке)
 	// Принимает:
 	//   - ctx: контекст выполнения
-	//   - userID: ID пользователя
-	//   - fromCurrency: исходная валюта
-	//   - toCurrency: целевая валюта
-	//   - amount: сумма для обмена
-	//   - rate: курс обмена
+	//   - quote: расчет обмена (пользователь, валюты, сумма, курс, комиссия в целевой валюте, происхождение курса)
 	// Возвращает:
 	//   - *models.Balance: новый баланс после обмена
-	//   - error: ошибка при обмене
-	Exchange(
+	//   - error: ErrInsufficientFunds, если сумма превышает баланс в исходной валюте, или ошибка при обмене
+	Exchange(ctx context.Context, quote *models.ExchangeQuote) (*models.Balance, error)
+
+	// ReverseExchange сторнирует выполненный обмен (компенсация многошаговой операции):
+	// списывает полученную сумму в целевой валюте и возвращает списанную в исходной
+	// Записи журнала имеют тип exchange_reversal
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - quote: расчет выполненного обмена
+	// Возвращает:
+	//   - *models.Balance: новый баланс
+	//   - error: ErrWalletUnavailable, ErrInsufficientFunds в целевой валюте или ошибка при выполнении
+	ReverseExchange(ctx context.Context, quote *models.ExchangeQuote) (*models.Balance, error)
+
+	// SetQuarantine блокирует или разблокирует кошелек
+	// Операции с балансом заблокированного кошелька завершаются ошибкой ErrWalletUnavailable
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - quarantined: true - заблокировать, false - разблокировать
+	//   - reason: причина блокировки (для разблокировки игнорируется)
+	// Возвращает:
+	//   - error: ErrWalletUnavailable, если кошелек не найден, или ошибка при обновлении
+	SetQuarantine(ctx context.Context, userID int, quarantined bool, reason string) error
+}
+
+// ErrTransactionNotFound возвращается, если записи журнала нет ни в журнале, ни в архиве
+var ErrTransactionNotFound = errors.New("запись журнала операций не найдена")
+
+// TransactionRepository определяет контракт для работы с журналом операций (ledger)
+// Журнал секционирован по месяцам: запросы истории всегда ограничены периодом,
+// чтобы затрагивать только нужные секции
+type TransactionRepository interface {
+	// ListTransactions возвращает записи журнала пользователя за период, включая перенесенные в архив
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - from: начало периода (включительно)
+	//   - to: конец периода (не включительно)
+	//   - limit: максимальное количество записей
+	// Возвращает:
+	//   - []models.Transaction: записи от новых к старым
+	//   - error: ошибка при выполнении запроса
+	ListTransactions(ctx context.Context, userID int, from, to time.Time, limit int) ([]models.Transaction, error)
+
+	// ExportTransactions передает записи журнала пользователя за период, включая архив, в функцию fn, от старых к новым
+	// Записи читаются по мере обработки, без загрузки периода в память
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - from: начало периода (включительно)
+	//   - to: конец периода (не включительно)
+	//   - fn: обработчик записи (ошибка прерывает выгрузку и возвращается)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса или ошибка fn
+	ExportTransactions(ctx context.Context, userID int, from, to time.Time, fn func(models.Transaction) error) error
+
+	// ListExchanges возвращает обмены пользователя за период, собранные из записей журнала
+	// (включая архив) по идентификатору операции
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - from: начало периода (включительно)
+	//   - to: конец периода (не включительно)
+	//   - limit: максимальное количество обменов
+	// Возвращает:
+	//   - []models.ExchangeRecord: обмены от новых к старым
+	//   - error: ошибка при выполнении запроса
+	ListExchanges(ctx context.Context, userID int, from, to time.Time, limit int) ([]models.ExchangeRecord, error)
+
+	// ListLedger возвращает все записи журнала пользователя (включая архив) до указанного момента
+	// Используется для расчетов по всей истории (налоговая отчетность)
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - before: конец периода (не включительно)
+	// Возвращает:
+	//   - []models.Transaction: записи от старых к новым
+	//   - error: ошибка при выполнении запроса
+	ListLedger(ctx context.Context, userID int, before time.Time) ([]models.Transaction, error)
+
+	// GetOperationEntries возвращает записи операции владельца записи журнала (включая архив)
+	// Записи второй стороны перевода не включаются
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - transactionID: идентификатор записи журнала
+	// Возвращает:
+	//   - []models.Transaction: записи операции по возрастанию ID (не пусто)
+	//   - error: ErrTransactionNotFound, если записи нет, или ошибка при выполнении запроса
+	GetOperationEntries(ctx context.Context, transactionID int64) ([]models.Transaction, error)
+
+	// EnsurePartitions создает месячные секции журнала, если они еще не существуют
+	// Записи из секции по умолчанию предварительно переносятся в секции своих месяцев
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - from: момент, начиная с месяца которого создаются секции
+	//   - months: количество месяцев (включая месяц from)
+	// Возвращает:
+	//   - error: ошибка при создании секций
+	EnsurePartitions(ctx context.Context, from time.Time, months int) error
+
+	// ArchiveTransactions переносит в архив секции журнала, целиком лежащие до указанного момента
+	// (включая записи, попавшие в секцию по умолчанию)
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - before: граница архивации (секции с более поздними записями не затрагиваются)
+	// Возвращает:
+	//   - int64: количество перенесенных записей
+	//   - error: ошибка при архивации
+	ArchiveTransactions(ctx context.Context, before time.Time) (int64, error)
+
+	// FindBalanceMismatches пересчитывает балансы всех кошельков по журналу (включая архив)
+	// и возвращает расхождения с таблицей кошельков
+	// Запрос читает журнал целиком, поэтому время выполнения ограничивает только ctx
+	// Принимает:
+	//   - ctx: контекст выполнения
+	// Возвращает:
+	//   - []models.BalanceMismatch: расхождения по кошелькам и валютам
+	//   - error: ошибка при выполнении запроса
+	FindBalanceMismatches(ctx context.Context) ([]models.BalanceMismatch, error)
+
+	// CountTransfers возвращает количество переводов от одного пользователя другому
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - fromUserID: отправитель
+	//   - toUserID: получатель
+	// Возвращает:
+	//   - int: количество переводов в журнале
+	//   - error: ошибка при выполнении запроса
+	CountTransfers(ctx context.Context, fromUserID, toUserID int) (int, error)
+
+	// ExchangeVolume возвращает объем обменов пользователя (списанные суммы) по исходным валютам
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - since: начало периода
+	// Возвращает:
+	//   - map[string]float64: валюта -> сумма обменов из этой валюты
+	//   - error: ошибка при выполнении запроса
+	ExchangeVolume(ctx context.Context, userID int, since time.Time) (map[string]float64, error)
+}
+
+// ErrReviewNotFound возвращается, если проверка не найдена или уже не в ожидаемом статусе
+var ErrReviewNotFound = errors.New("проверка не найдена или уже обработана")
+
+// ReviewRepository определяет контракт очереди операций, отложенных антифродом
+type ReviewRepository interface {
+	// CreateReview ставит операцию в очередь проверки
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - review: операция (ID и CreatedAt заполняются хранилищем)
+	// Возвращает:
+	//   - error: ошибка при сохранении
+	CreateReview(ctx context.Context, review *models.RiskReview) error
+
+	// ListReviews возвращает проверки в указанном статусе, от старых к новым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - status: статус (пустая строка - все статусы)
+	//   - limit: максимальное количество записей
+	// Возвращает:
+	//   - []models.RiskReview: найденные проверки
+	//   - error: ошибка при выполнении запроса
+	ListReviews(ctx context.Context, status string, limit int) ([]models.RiskReview, error)
+
+	// TransitionReview переводит проверку из статуса from в статус to
+	// Переход атомарен: две параллельные попытки решить одну проверку не пройдут обе
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: идентификатор проверки
+	//   - from, to: ожидаемый и новый статусы
+	//   - adminID: администратор, принявший решение
+	//   - comment: комментарий
+	// Возвращает:
+	//   - *models.RiskReview: обновленная проверка
+	//   - error: ErrReviewNotFound, если проверка не найдена или не в статусе from
+	TransitionReview(ctx context.Context, id int, from, to string, adminID int, comment string) (*models.RiskReview, error)
+}
+
+// ErrKYCTransition возвращается, если статус верификации пользователя не допускает операцию
+// (например, подача документов верифицированным пользователем или решение по непроверяемой заявке)
+var ErrKYCTransition = errors.New("операция недоступна в текущем статусе верификации")
+
+// KYCRepository определяет контракт для работы с верификацией пользователей
+type KYCRepository interface {
+	// AddDocument сохраняет метаданные документа и переводит пользователя в статус pending
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - doc: документ (ID и CreatedAt заполняются хранилищем)
+	// Возвращает:
+	//   - error: ErrKYCTransition, если пользователь уже верифицирован, или ошибка при сохранении
+	AddDocument(ctx context.Context, doc *models.KYCDocument) error
+
+	// ListDocuments возвращает документы пользователя, от новых к старым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	// Возвращает:
+	//   - []models.KYCDocument: документы пользователя
+	//   - error: ошибка при выполнении запроса
+	ListDocuments(ctx context.Context, userID int) ([]models.KYCDocument, error)
+
+	// ListUsersByKYCStatus возвращает пользователей в указанном статусе верификации,
+	// от давно ожидающих к недавним
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - status: статус верификации
+	//   - limit: максимальное количество записей
+	// Возвращает:
+	//   - []models.User: найденные пользователи
+	//   - error: ошибка при выполнении запроса
+	ListUsersByKYCStatus(ctx context.Context, status string, limit int) ([]models.User, error)
+
+	// TransitionKYC атомарно переводит пользователя из статуса from в статус to
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: идентификатор пользователя
+	//   - from, to: ожидаемый и новый статусы
+	//   - adminID: администратор, принявший решение
+	//   - comment: комментарий
+	// Возвращает:
+	//   - error: ErrKYCTransition, если пользователь не найден или не в статусе from
+	TransitionKYC(ctx context.Context, userID int, from, to string, adminID int, comment string) error
+}
+
+// DocumentStore определяет контракт хранилища файлов документов верификации
+// Позволяет заменить локальный диск на объектное хранилище без изменения сервисов
+type DocumentStore interface {
+	// Save сохраняет содержимое под указанным ключом
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - key: ключ файла (относительный путь вида "<user_id>/<имя>")
+	//   - r: содержимое файла
+	// Возвращает:
+	//   - int64: количество записанных байт
+	//   - error: ошибка при сохранении
+	Save(ctx context.Context, key string, r io.Reader) (int64, error)
+
+	// Delete удаляет файл (отсутствующий файл не является ошибкой)
+	Delete(ctx context.Context, key string) error
+}
+
+// ComplianceRepository определяет контракт для работы с отчетом о крупных операциях (AML)
+// Записи отчета создаются хранилищем кошельков в транзакции самой операции
+type ComplianceRepository interface {
+	// ExportAMLReports передает записи отчета за период в функцию fn, от старых к новым
+	// Записи читаются потоково, поэтому время выполнения ограничивает только ctx
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - from: начало периода (включительно)
+	//   - to: конец периода (не включительно)
+	//   - fn: обработчик записи; ошибка обработчика прерывает выгрузку
+	// Возвращает:
+	//   - error: ошибка запроса или обработчика
+	ExportAMLReports(ctx context.Context, from, to time.Time, fn func(models.AMLReport) error) error
+}
+
+// ErrAdjustmentNotFound возвращается, если корректировка не найдена или уже обработана
+var ErrAdjustmentNotFound = errors.New("корректировка не найдена или уже обработана")
+
+// ErrInsufficientFunds возвращается, если списание привело бы к отрицательному балансу
+var ErrInsufficientFunds = errors.New("недостаточно средств")
+
+// AdjustmentRepository определяет контракт корректировок баланса администраторами
+// Каждое изменение корректировки записывается в журнал действий администраторов
+// в той же транзакции
+type AdjustmentRepository interface {
+	// CreateAdjustment сохраняет корректировку в статусе pending
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - adj: корректировка (ID, Status и CreatedAt заполняются хранилищем)
+	// Возвращает:
+	//   - error: ошибка при сохранении
+	CreateAdjustment(ctx context.Context, adj *models.BalanceAdjustment) error
+
+	// GetAdjustment возвращает корректировку по идентификатору
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: идентификатор корректировки
+	// Возвращает:
+	//   - *models.BalanceAdjustment: корректировка
+	//   - error: ErrAdjustmentNotFound, если корректировка не найдена
+	GetAdjustment(ctx context.Context, id int) (*models.BalanceAdjustment, error)
+
+	// ApplyAdjustment проводит корректировку: изменяет баланс (в том числе заблокированного кошелька),
+	// записывает операцию в журнал операций и переводит корректировку в статус applied
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: идентификатор корректировки
+	//   - adminID: администратор, проводящий корректировку
+	// Возвращает:
+	//   - *models.BalanceAdjustment: проведенная корректировка
+	//   - *models.Balance: новый баланс пользователя
+	//   - error: ErrAdjustmentNotFound, если корректировка не в статусе pending,
+	//     ErrInsufficientFunds, если списание превышает баланс, ErrWalletUnavailable, если кошелька нет
+	ApplyAdjustment(ctx context.Context, id int, adminID int) (*models.BalanceAdjustment, *models.Balance, error)
+
+	// RejectAdjustment отклоняет корректировку в статусе pending
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: идентификатор корректировки
+	//   - adminID: администратор, отклонивший корректировку
+	//   - comment: причина отклонения
+	// Возвращает:
+	//   - *models.BalanceAdjustment: отклоненная корректировка
+	//   - error: ErrAdjustmentNotFound, если корректировка не в статусе pending
+	RejectAdjustment(ctx context.Context, id int, adminID int, comment string) (*models.BalanceAdjustment, error)
+
+	// ListAdjustments возвращает корректировки в указанном статусе, от новых к старым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - status: статус (пустая строка - все статусы)
+	//   - limit: максимальное количество записей
+	// Возвращает:
+	//   - []models.BalanceAdjustment: найденные корректировки
+	//   - error: ошибка при выполнении запроса
+	ListAdjustments(ctx context.Context, status string, limit int) ([]models.BalanceAdjustment, error)
+
+	// ListAuditLog возвращает записи журнала действий администраторов, от новых к старым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - limit: максимальное количество записей
+	// Возвращает:
+	//   - []models.AuditEntry: записи журнала
+	//   - error: ошибка при выполнении запроса
+	ListAuditLog(ctx context.Context, limit int) ([]models.AuditEntry, error)
+}
+
+// Ошибки промокодов
+var (
+	// ErrPromoExists возвращается при создании промокода с уже существующим кодом
+	ErrPromoExists = errors.New("промокод уже существует")
+	// ErrPromoUnavailable возвращается, если промокод не найден, отключен, истек или исчерпан
+	ErrPromoUnavailable = errors.New("промокод недействителен")
+	// ErrPromoAlreadyUsed возвращается при повторной активации промокода пользователем
+	ErrPromoAlreadyUsed = errors.New("промокод уже использован")
+	// ErrPromoNotApplicable возвращается, если промокод не подходит к операции
+	// (процентный промокод без пополнения, фиксированный - с пополнением, другая валюта)
+	ErrPromoNotApplicable = errors.New("промокод не подходит к операции")
+)
+
+// PromoRepository определяет контракт для работы с промокодами
+type PromoRepository interface {
+	// CreatePromo сохраняет промокод и запись о нем в журнале действий администраторов
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - promo: промокод (ID, Uses, Active и CreatedAt заполняются хранилищем)
+	// Возвращает:
+	//   - error: ErrPromoExists, если код занят, или ошибка при сохранении
+	CreatePromo(ctx context.Context, promo *models.PromoCode) error
+
+	// ListPromos возвращает промокоды, от новых к старым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - limit: максимальное количество записей
+	// Возвращает:
+	//   - []models.PromoCode: промокоды
+	//   - error: ошибка при выполнении запроса
+	ListPromos(ctx context.Context, limit int) ([]models.PromoCode, error)
+
+	// DeactivatePromo отключает промокод
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - code: промокод
+	//   - adminID: администратор
+	// Возвращает:
+	//   - error: ErrPromoUnavailable, если промокод не найден или уже отключен
+	DeactivatePromo(ctx context.Context, code string, adminID int) error
+
+	// RedeemPromo активирует промокод и зачисляет бонус в одной транзакции
+	// Для процентного промокода в той же транзакции зачисляется пополнение, к которому он применен
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	//   - code: промокод
+	//   - currency: валюта пополнения (для фиксированного промокода игнорируется)
+	//   - deposit: сумма пополнения (0 - активация фиксированного промокода без пополнения)
+	// Возвращает:
+	//   - *models.PromoRedemption: активация с зачисленным бонусом
+	//   - *models.Balance: новый баланс пользователя
+	//   - error: ErrPromoUnavailable, ErrPromoAlreadyUsed, ErrPromoNotApplicable,
+	//     ErrWalletUnavailable или ошибка при выполнении
+	RedeemPromo(
 		ctx context.Context,
 		userID int,
-		fromCurrency string,
-		toCurrency string,
-		amount float64,
-		rate float64,
-	) (*models.Balance, error)
+		code string,
+		currency string,
+		deposit float64,
+	) (*models.PromoRedemption, *models.Balance, error)
+}
+
+// PreferencesRepository определяет методы для работы с настройками пользователей
+type PreferencesRepository interface {
+	// GetPreferences возвращает настройки пользователя
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	// Возвращает:
+	//   - *models.UserPreferences: сохраненные настройки или настройки по умолчанию
+	//   - error: ошибка при выполнении запроса
+	GetPreferences(ctx context.Context, userID int) (*models.UserPreferences, error)
+
+	// SavePreferences создает или обновляет настройки пользователя
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - prefs: настройки (UpdatedAt заполняется при сохранении)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	SavePreferences(ctx context.Context, prefs *models.UserPreferences) error
+}
+
+// DeviceRepository определяет методы для работы с устройствами пользователей
+type DeviceRepository interface {
+	// FindDevice возвращает устройство пользователя по отпечатку
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	//   - fingerprint: отпечаток устройства
+	// Возвращает:
+	//   - *models.Device: устройство или nil, если вход с него не выполнялся
+	//   - error: ошибка при выполнении запроса
+	FindDevice(ctx context.Context, userID int, fingerprint string) (*models.Device, error)
+
+	// SaveDevice записывает вход с устройства: создает устройство или обновляет адрес, местоположение
+	// и время последнего входа. Подтверждение устройства не снимается
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - device: устройство (ID, FirstSeen и LastSeen заполняются при сохранении)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	SaveDevice(ctx context.Context, device *models.Device) error
+
+	// ListDevices возвращает устройства пользователя, начиная с последнего входа
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	// Возвращает:
+	//   - []models.Device: устройства
+	//   - error: ошибка при выполнении запроса
+	ListDevices(ctx context.Context, userID int) ([]models.Device, error)
+}
+
+// ErrPaymentEventProcessed возвращается при повторной доставке уже зачисленного события провайдера
+var ErrPaymentEventProcessed = errors.New("событие платежа уже обработано")
+
+// PaymentRepository определяет методы зачисления платежей по событиям платежного провайдера
+type PaymentRepository interface {
+	// CreditPayment зачисляет платеж и сохраняет идентификатор события в одной транзакции:
+	// событие с уже сохраненным идентификатором не зачисляется повторно
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - event: событие провайдера
+	// Возвращает:
+	//   - *models.PaymentCredit: зачисление (для повторного события - первое зачисление)
+	//   - error: ErrPaymentEventProcessed (вместе с первым зачислением), ErrWalletUnavailable
+	//     или ошибка при выполнении
+	CreditPayment(ctx context.Context, event models.PaymentEvent) (*models.PaymentCredit, error)
+}
+
+// ActivityRepository определяет методы журнала активности пользователя
+type ActivityRepository interface {
+	// RecordActivity записывает событие в журнал активности
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - entry: событие
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	RecordActivity(ctx context.Context, entry models.ActivityEntry) error
+
+	// ListActivity возвращает ленту активности пользователя: события журнала активности,
+	// записи журнала операций (включая архив) и регистрацию, от новых к старым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	//   - before: курсор события, после которого продолжается лента (пусто - с начала)
+	//   - limit: максимальное количество событий
+	// Возвращает:
+	//   - []models.ActivityEvent: события с заполненным Cursor
+	//   - error: ErrInvalidCursor или ошибка при выполнении запроса
+	ListActivity(ctx context.Context, userID int, before string, limit int) ([]models.ActivityEvent, error)
+}
+
+// ErrInvalidCursor возвращается для некорректного курсора ленты активности
+var ErrInvalidCursor = errors.New("некорректный курсор")
+
+// AlertRepository определяет методы хранения уведомлений о курсах
+type AlertRepository interface {
+	// CreateAlert сохраняет уведомление, если у чата меньше limit уведомлений
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - alert: уведомление (ID и CreatedAt заполняются при сохранении)
+	//   - limit: максимальное количество уведомлений чата
+	// Возвращает:
+	//   - bool: false, если у чата уже limit уведомлений (уведомление не сохранено)
+	//   - error: ошибка при выполнении запроса
+	CreateAlert(ctx context.Context, alert *models.RateAlert, limit int) (bool, error)
+
+	// ListAlerts возвращает уведомления чата в порядке создания
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - chatID: чат (0 - уведомления всех чатов)
+	// Возвращает:
+	//   - []models.RateAlert: уведомления
+	//   - error: ошибка при выполнении запроса
+	ListAlerts(ctx context.Context, chatID int64) ([]models.RateAlert, error)
+
+	// DeleteAlert удаляет уведомление чата
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - chatID: чат-владелец (0 - любой чат)
+	//   - id: идентификатор уведомления
+	// Возвращает:
+	//   - bool: false, если уведомление не найдено
+	//   - error: ошибка при выполнении запроса
+	DeleteAlert(ctx context.Context, chatID int64, id int) (bool, error)
+}
+
+// FavoritesRepository определяет методы хранения избранных валют чатов Telegram бота
+type FavoritesRepository interface {
+	// GetFavorites возвращает избранные валюты чата в порядке вывода
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - chatID: чат
+	// Возвращает:
+	//   - []string: коды валют (nil - чат не настраивал избранное)
+	//   - error: ошибка при выполнении запроса
+	GetFavorites(ctx context.Context, chatID int64) ([]string, error)
+
+	// SaveFavorites сохраняет избранные валюты чата
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - chatID: чат
+	//   - currencies: коды валют в порядке вывода (пусто - сброс настройки)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	SaveFavorites(ctx context.Context, chatID int64, currencies []string) error
+}
+
+// ChatRepository определяет методы хранения чатов Telegram бота и их настроек
+type ChatRepository interface {
+	// SaveChat добавляет чат или обновляет его тип и название, не меняя настройки
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - chat: чат
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	SaveChat(ctx context.Context, chat *models.TelegramChat) error
+
+	// GetChat возвращает чат с настройками
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - chatID: чат
+	// Возвращает:
+	//   - *models.TelegramChat: чат (nil - не сохранен)
+	//   - error: ошибка при выполнении запроса
+	GetChat(ctx context.Context, chatID int64) (*models.TelegramChat, error)
+
+	// SetDigestHour включает ежедневную сводку курсов чата или выключает ее
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - chatID: чат
+	//   - hour: час отправки по UTC (nil - сводка выключена)
+	// Возвращает:
+	//   - bool: false, если чат не сохранен
+	//   - error: ошибка при выполнении запроса
+	SetDigestHour(ctx context.Context, chatID int64, hour *int) (bool, error)
+
+	// DeleteChat удаляет чат вместе с его избранными валютами и уведомлениями о курсах
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - chatID: чат
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	DeleteChat(ctx context.Context, chatID int64) error
+
+	// ClaimDigests отмечает отправленными сводки за текущий день, час которых наступил
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - now: текущее время
+	// Возвращает:
+	//   - []int64: чаты, которым нужно отправить сводку
+	//   - error: ошибка при выполнении запроса
+	ClaimDigests(ctx context.Context, now time.Time) ([]int64, error)
+}
+
+// RefreshTokenRepository определяет методы хранения токенов обновления сессий
+type RefreshTokenRepository interface {
+	// CreateRefreshToken сохраняет первый токен новой сессии и удаляет истекшие токены пользователя
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - token: токен (ID заполняется при сохранении)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+
+	// GetRefreshToken возвращает токен по его SHA-256
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - tokenHash: SHA-256 токена
+	// Возвращает:
+	//   - *models.RefreshToken: токен или nil, если он не найден
+	//   - error: ошибка при выполнении запроса
+	GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+
+	// RotateRefreshToken заменяет токен следующим в той же сессии (в одной транзакции)
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - currentID: заменяемый токен
+	//   - next: следующий токен (ID заполняется при сохранении)
+	// Возвращает:
+	//   - bool: false, если токен уже заменен или отозван (параллельное обновление)
+	//   - error: ошибка при выполнении запроса
+	RotateRefreshToken(ctx context.Context, currentID int64, next *models.RefreshToken) (bool, error)
+
+	// RevokeRefreshSession отзывает все токены сессии
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - sessionID: сессия
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	RevokeRefreshSession(ctx context.Context, sessionID string) error
+}
+
+// OperationRepository определяет методы хранения состояния многошаговых денежных операций
+// Количество выполненных шагов, изменяющих баланс, записывается в транзакции изменения баланса
+// (WithOperationStep), остальные изменения состояния - методами репозитория
+type OperationRepository interface {
+	// CreateOperation сохраняет новую операцию в состоянии pending
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - op: операция (ID, состояние и время заполняются при сохранении)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	CreateOperation(ctx context.Context, op *models.MoneyOperation) error
+
+	// SetOperationStep записывает количество выполненных шагов операции
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: операция
+	//   - step: количество выполненных шагов
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	SetOperationStep(ctx context.Context, id int64, step int) error
+
+	// SetOperationStatus переводит операцию в состояние status
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: операция
+	//   - status: новое состояние
+	//   - errText: ошибка, из-за которой операция отменяется (пусто - прежняя ошибка сохраняется)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	SetOperationStatus(ctx context.Context, id int64, status string, errText string) error
+
+	// ClaimStaleOperations захватывает незавершенные операции (pending, compensating), состояние которых
+	// не менялось с момента before: переводит их в compensating и увеличивает счетчик попыток
+	// Захваченные операции не возвращаются другим экземплярам сервиса до следующего истечения before
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - before: граница времени последнего изменения
+	//   - limit: максимальное количество операций
+	// Возвращает:
+	//   - []models.MoneyOperation: захваченные операции (с увеличенным счетчиком попыток)
+	//   - error: ошибка при выполнении запроса
+	ClaimStaleOperations(ctx context.Context, before time.Time, limit int) ([]models.MoneyOperation, error)
+}
+
+// TelegramLinkRepository определяет методы хранения привязок аккаунтов Telegram к пользователям
+type TelegramLinkRepository interface {
+	// LinkTelegram привязывает аккаунт Telegram к пользователю, заменяя прежние привязки обоих
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь кошелька
+	//   - telegramUserID: идентификатор пользователя Telegram
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	LinkTelegram(ctx context.Context, userID int, telegramUserID int64) error
+
+	// GetLinkedUser возвращает пользователя, к которому привязан аккаунт Telegram
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - telegramUserID: идентификатор пользователя Telegram
+	// Возвращает:
+	//   - int: пользователь кошелька (0 - аккаунт не привязан)
+	//   - error: ошибка при выполнении запроса
+	GetLinkedUser(ctx context.Context, telegramUserID int64) (int, error)
+
+	// UnlinkTelegram снимает привязку аккаунта Telegram пользователя
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь кошелька
+	// Возвращает:
+	//   - bool: false, если привязки не было
+	//   - error: ошибка при выполнении запроса
+	UnlinkTelegram(ctx context.Context, userID int) (bool, error)
+}
+
+// NotificationRepository определяет методы outbox уведомлений пользователей об операциях с балансом
+// и правил их доставки. Уведомления записываются в транзакции изменения баланса по правилам пользователя
+// (без правил - в каналы по умолчанию хранилища), доставляются фоновой задачей отправки
+type NotificationRepository interface {
+	// ClaimNotifications захватывает уведомления, время отправки которых наступило, и увеличивает счетчик попыток
+	// До истечения lease захваченные уведомления не возвращаются другим экземплярам сервиса;
+	// если экземпляр прервался, не отметив результат, уведомление отправляется повторно
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - limit: максимальное количество уведомлений
+	//   - lease: время, на которое уведомления захватываются
+	// Возвращает:
+	//   - []models.Notification: захваченные уведомления с адресами получателей
+	//   - error: ошибка при выполнении запроса
+	ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error)
+
+	// MarkNotificationSent отмечает уведомление отправленным
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: уведомление
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	MarkNotificationSent(ctx context.Context, id int64) error
+
+	// RetryNotification откладывает повторную отправку уведомления до at
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: уведомление
+	//   - errText: ошибка последней попытки
+	//   - at: время следующей попытки
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	RetryNotification(ctx context.Context, id int64, errText string, at time.Time) error
+
+	// FailNotification отмечает уведомление неотправленным (попытки исчерпаны или получателя нет)
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: уведомление
+	//   - errText: причина
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	FailNotification(ctx context.Context, id int64, errText string) error
+
+	// PurgeNotifications удаляет отправленные и неотправленные уведомления, созданные раньше before
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - before: граница времени создания
+	// Возвращает:
+	//   - int64: количество удаленных уведомлений
+	//   - error: ошибка при выполнении запроса
+	PurgeNotifications(ctx context.Context, before time.Time) (int64, error)
+
+	// ListNotifications возвращает уведомления от новых к старым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: получатель (0 - все получатели)
+	//   - status: состояние (пусто - любое)
+	//   - limit: максимальное количество
+	// Возвращает:
+	//   - []models.Notification: уведомления
+	//   - error: ошибка при выполнении запроса
+	ListNotifications(ctx context.Context, userID int, status string, limit int) ([]models.Notification, error)
+
+	// RequeueNotification возвращает неотправленное уведомление (dead letter) в очередь с новым счетчиком попыток
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - id: уведомление
+	// Возвращает:
+	//   - bool: false, если неотправленного уведомления с таким id нет
+	//   - error: ошибка при выполнении запроса
+	RequeueNotification(ctx context.Context, id int64) (bool, error)
+
+	// ListNotificationRoutes возвращает правила доставки уведомлений пользователя в порядке каналов
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	// Возвращает:
+	//   - []models.NotificationRoute: правила
+	//   - error: ошибка при выполнении запроса
+	ListNotificationRoutes(ctx context.Context, userID int) ([]models.NotificationRoute, error)
+
+	// SaveNotificationRoute создает или заменяет правило пользователя для канала
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - route: правило (время изменения заполняется)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	SaveNotificationRoute(ctx context.Context, route *models.NotificationRoute) error
+
+	// DeleteNotificationRoute удаляет правило пользователя для канала
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	//   - channel: канал
+	// Возвращает:
+	//   - bool: false, если правила не было
+	//   - error: ошибка при выполнении запроса
+	DeleteNotificationRoute(ctx context.Context, userID int, channel string) (bool, error)
+}
+
+// PushTokenRepository определяет методы токенов push уведомлений установок мобильного приложения
+type PushTokenRepository interface {
+	// RegisterPushToken сохраняет токен за пользователем; токен, зарегистрированный другим пользователем
+	// (вход в другой аккаунт на том же устройстве), переходит к новому владельцу
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - token: токен (идентификатор и время регистрации заполняются)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	RegisterPushToken(ctx context.Context, token *models.PushToken) error
+
+	// ListPushTokens возвращает токены пользователя от новых к старым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	// Возвращает:
+	//   - []models.PushToken: токены
+	//   - error: ошибка при выполнении запроса
+	ListPushTokens(ctx context.Context, userID int) ([]models.PushToken, error)
+
+	// DeletePushToken удаляет токен пользователя
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: владелец
+	//   - id: регистрация токена
+	// Возвращает:
+	//   - bool: false, если у пользователя нет такого токена
+	//   - error: ошибка при выполнении запроса
+	DeletePushToken(ctx context.Context, userID int, id int64) (bool, error)
+
+	// RemovePushToken удаляет токен, отклоненный платформой (приложение удалено, токен устарел)
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - platform: платформа
+	//   - token: токен устройства
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	RemovePushToken(ctx context.Context, platform, token string) error
+}
+
+// CurrencyFreezeRepository определяет контракт хранилища приостановок операций в валютах
+type CurrencyFreezeRepository interface {
+	// FreezeCurrency приостанавливает операции в валюте (повторная приостановка заменяет причину и администратора)
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - freeze: валюта, причина и администратор; FrozenAt заполняется
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	FreezeCurrency(ctx context.Context, freeze *models.CurrencyFreeze) error
+
+	// UnfreezeCurrency возобновляет операции в валюте
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - currency: валюта
+	// Возвращает:
+	//   - bool: false, если операции в валюте не были приостановлены
+	//   - error: ошибка при выполнении запроса
+	UnfreezeCurrency(ctx context.Context, currency string) (bool, error)
+
+	// ListCurrencyFreezes возвращает действующие приостановки по валютам
+	ListCurrencyFreezes(ctx context.Context) ([]models.CurrencyFreeze, error)
+}
+
+// ErrQueuedExchangeNotFound возвращается, если обмена нет в очереди или он уже исполняется
+var ErrQueuedExchangeNotFound = errors.New("обмен не найден в очереди или уже исполняется")
+
+// QueuedExchangeRepository определяет контракт хранилища очереди отложенных обменов
+type QueuedExchangeRepository interface {
+	// CreateQueuedExchange ставит обмен в очередь
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - exchange: обмен; ID, Status и CreatedAt заполняются
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	CreateQueuedExchange(ctx context.Context, exchange *models.QueuedExchange) error
+
+	// CountPendingExchanges возвращает количество ожидающих исполнения обменов пользователя
+	CountPendingExchanges(ctx context.Context, userID int) (int, error)
+
+	// ListQueuedExchanges возвращает обмены пользователя в очереди от новых к старым
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: пользователь
+	//   - limit: максимальное количество
+	// Возвращает:
+	//   - []models.QueuedExchange: обмены
+	//   - error: ошибка при выполнении запроса
+	ListQueuedExchanges(ctx context.Context, userID int, limit int) ([]models.QueuedExchange, error)
+
+	// ClaimQueuedExchanges переводит в executing ожидающие обмены с условием trigger, время исполнения
+	// которых не позже at. Обмен захватывается одним экземпляром сервиса
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - trigger: условие исполнения
+	//   - currencies: только обмены с одной из валют (nil - любые)
+	//   - at: момент наступления условия
+	//   - limit: максимальное количество
+	// Возвращает:
+	//   - []models.QueuedExchange: захваченные обмены
+	//   - error: ошибка при выполнении запроса
+	ClaimQueuedExchanges(ctx context.Context, trigger string, currencies []string, at time.Time, limit int) ([]models.QueuedExchange, error)
+
+	// CancelQueuedExchange отменяет ожидающий обмен пользователя
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - userID: владелец обмена
+	//   - id: обмен
+	// Возвращает:
+	//   - *models.QueuedExchange: отмененный обмен
+	//   - error: ErrQueuedExchangeNotFound, если обмена нет или он уже исполняется
+	CancelQueuedExchange(ctx context.Context, userID int, id int64) (*models.QueuedExchange, error)
+
+	// CompleteQueuedExchange отмечает обмен исполненным с примененным курсом и полученной суммой
+	CompleteQueuedExchange(ctx context.Context, id int64, rate, exchangedAmount float64) error
+
+	// FailQueuedExchange отмечает обмен неисполненным с причиной
+	FailQueuedExchange(ctx context.Context, id int64, reason string) error
+
+	// RescheduleQueuedExchange возвращает захваченный обмен в ожидание до at
+	RescheduleQueuedExchange(ctx context.Context, id int64, at time.Time) error
+
+	// FailStaleQueuedExchanges отмечает неисполненными обмены, захваченные раньше olderThan назад:
+	// исполнение прервано сбоем экземпляра, и повторять его нельзя - обмен мог быть выполнен
+	// Возвращает:
+	//   - int64: количество обменов
+	//   - error: ошибка при выполнении запроса
+	FailStaleQueuedExchanges(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+// ErrDCAPlanNotFound возвращается, если плана регулярных обменов нет или он уже завершен
+var ErrDCAPlanNotFound = errors.New("план регулярных обменов не найден или уже завершен")
+
+// DCARepository определяет контракт хранилища планов регулярных обменов
+type DCARepository interface {
+	// CreateDCAPlan сохраняет план
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - plan: план; ID, Status и CreatedAt заполняются
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	CreateDCAPlan(ctx context.Context, plan *models.DCAPlan) error
+
+	// CountActiveDCAPlans возвращает количество активных планов пользователя
+	CountActiveDCAPlans(ctx context.Context, userID int) (int, error)
+
+	// ListDCAPlans возвращает планы пользователя от новых к старым
+	ListDCAPlans(ctx context.Context, userID int) ([]models.DCAPlan, error)
+
+	// GetDCAPlan возвращает план пользователя
+	// Возвращает:
+	//   - *models.DCAPlan: план
+	//   - error: ErrDCAPlanNotFound, если плана нет
+	GetDCAPlan(ctx context.Context, userID int, id int64) (*models.DCAPlan, error)
+
+	// ListDCALegs возвращает обмены плана от первого к последнему
+	ListDCALegs(ctx context.Context, planID int64) ([]models.DCALeg, error)
+
+	// CancelDCAPlan отменяет активный план пользователя
+	// Возвращает:
+	//   - *models.DCAPlan: отмененный план
+	//   - error: ErrDCAPlanNotFound, если плана нет или он уже завершен
+	CancelDCAPlan(ctx context.Context, userID int, id int64) (*models.DCAPlan, error)
+
+	// ClaimDueDCAPlans захватывает на время lease активные планы, время обмена которых наступило.
+	// План захватывается одним экземпляром сервиса
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - lease: время захвата (после него план снова доступен, если обмен не завершен)
+	//   - limit: максимальное количество
+	// Возвращает:
+	//   - []models.DCAPlan: захваченные планы
+	//   - error: ошибка при выполнении запроса
+	ClaimDueDCAPlans(ctx context.Context, lease time.Duration, limit int) ([]models.DCAPlan, error)
+
+	// StartDCALeg записывает начало обмена по плану
+	// Возвращает:
+	//   - bool: false, если обмен с этим номером уже начинался (выполнение прервано сбоем)
+	//   - error: ошибка при выполнении запроса
+	StartDCALeg(ctx context.Context, leg *models.DCALeg) (bool, error)
+
+	// FinishDCALeg записывает результат обмена и переносит план на следующий обмен в одной транзакции
+	// Принимает:
+	//   - ctx: контекст выполнения
+	//   - leg: обмен с результатом
+	//   - nextRunAt: время следующего обмена (nil - обмен последний, план завершается)
+	// Возвращает:
+	//   - error: ошибка при выполнении запроса
+	FinishDCALeg(ctx context.Context, leg *models.DCALeg, nextRunAt *time.Time) error
 }
@@ -0,0 +1,216 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// alertUsage - подсказка по команде /alert
+const alertUsage = "Использование:\n" +
+	"/alert set USD RUB > 100 - уведомить, когда курс USD/RUB станет не меньше 100\n" +
+	"/alert list - список уведомлений\n" +
+	"/alert delete 3 - удалить уведомление №3"
+
+// Префиксы данных кнопок подтверждения (callback data)
+const (
+	alertCallbackSet    = "alert:set:"    // Создание уведомления: alert:set:USD:RUB:>:100
+	alertCallbackAsk    = "alert:ask:"    // Запрос подтверждения удаления: alert:ask:3
+	alertCallbackDelete = "alert:delete:" // Удаление уведомления: alert:delete:3
+	alertCallbackCancel = "alert:cancel"  // Отмена действия
+)
+
+// handleAlert обрабатывает команду /alert (set, list, delete)
+// Создание и удаление выполняются только после подтверждения кнопкой
+func (h *Handler) handleAlert(msg *tgbotapi.Message) tgbotapi.MessageConfig {
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		return tgbotapi.NewMessage(msg.Chat.ID, alertUsage)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "set":
+		alert, err := parseAlert(args[1:])
+		if err != nil {
+			return tgbotapi.NewMessage(msg.Chat.ID, err.Error()+"\n\n"+alertUsage)
+		}
+		data := fmt.Sprintf("%s%s:%s:%s:%s", alertCallbackSet, alert.FromCurrency, alert.ToCurrency,
+			alert.Condition, strconv.FormatFloat(alert.Threshold, 'f', -1, 64))
+		response := tgbotapi.NewMessage(msg.Chat.ID, "Создать уведомление "+formatAlert(alert)+"?")
+		response.ReplyMarkup = confirmKeyboard("Создать", data)
+		return response
+
+	case "list":
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+		alerts, err := h.alerts.List(ctx, msg.Chat.ID)
+		if err != nil {
+			log.Printf("Ошибка получения уведомлений чата %d: %v", msg.Chat.ID, err)
+			return tgbotapi.NewMessage(msg.Chat.ID, "Не удалось получить уведомления. Попробуйте позже.")
+		}
+		if len(alerts) == 0 {
+			return tgbotapi.NewMessage(msg.Chat.ID, "Уведомлений нет. Создайте: /alert set USD RUB > 100")
+		}
+
+		var sb strings.Builder
+		var rows [][]tgbotapi.InlineKeyboardButton
+		sb.WriteString("Уведомления о курсах:\n\n")
+		for _, alert := range alerts {
+			sb.WriteString(fmt.Sprintf("№%d: %s\n", alert.ID, formatAlert(alert)))
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("Удалить №%d", alert.ID), alertCallbackAsk+strconv.Itoa(alert.ID))))
+		}
+		response := tgbotapi.NewMessage(msg.Chat.ID, sb.String())
+		response.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+		return response
+
+	case "delete":
+		if len(args) != 2 {
+			return tgbotapi.NewMessage(msg.Chat.ID, alertUsage)
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(args[1], "№"))
+		if err != nil || id <= 0 {
+			return tgbotapi.NewMessage(msg.Chat.ID, "Некорректный номер уведомления.\n\n"+alertUsage)
+		}
+		return deleteConfirmation(msg.Chat.ID, id)
+
+	default:
+		return tgbotapi.NewMessage(msg.Chat.ID, alertUsage)
+	}
+}
+
+// handleAlertCallback обрабатывает нажатие кнопки подтверждения уведомления: выполняет действие
+// и заменяет текст сообщения с кнопками результатом
+func (h *Handler) handleAlertCallback(query *tgbotapi.CallbackQuery) {
+	// В группе подтверждать создание и удаление может только администратор
+	answer := tgbotapi.NewCallback(query.ID, "")
+	allowed := query.Message == nil || query.Data == alertCallbackCancel ||
+		h.canConfigure(query.Message.Chat, query.From)
+	if !allowed {
+		answer = tgbotapi.NewCallbackWithAlert(query.ID, adminOnly)
+	}
+
+	// Telegram ожидает ответ на нажатие, иначе кнопка остается в состоянии загрузки
+	if _, err := h.bot.Request(answer); err != nil {
+		log.Printf("Ошибка ответа на нажатие кнопки: %v", err)
+	}
+	if !allowed || query.Message == nil || h.alerts == nil || !strings.HasPrefix(query.Data, "alert:") {
+		return
+	}
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var text string
+	switch data := query.Data; {
+	case strings.HasPrefix(data, alertCallbackSet):
+		alert, err := parseAlert(strings.Split(strings.TrimPrefix(data, alertCallbackSet), ":"))
+		if err != nil {
+			text = err.Error()
+			break
+		}
+		alert.ChatID = chatID
+		err = h.alerts.Create(ctx, &alert)
+		switch {
+		case errors.Is(err, services.ErrInvalidAlert), errors.Is(err, services.ErrAlertLimit):
+			text = err.Error()
+		case err != nil:
+			log.Printf("Ошибка создания уведомления чата %d: %v", chatID, err)
+			text = "Не удалось создать уведомление. Попробуйте позже."
+		default:
+			text = fmt.Sprintf("Уведомление №%d создано: %s", alert.ID, formatAlert(alert))
+		}
+
+	case strings.HasPrefix(data, alertCallbackAsk):
+		id, _ := strconv.Atoi(strings.TrimPrefix(data, alertCallbackAsk))
+		if _, err := h.bot.Send(deleteConfirmation(chatID, id)); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
+		return
+
+	case strings.HasPrefix(data, alertCallbackDelete):
+		id, _ := strconv.Atoi(strings.TrimPrefix(data, alertCallbackDelete))
+		err := h.alerts.Delete(ctx, chatID, id)
+		switch {
+		case errors.Is(err, services.ErrAlertNotFound):
+			text = fmt.Sprintf("Уведомление №%d не найдено.", id)
+		case err != nil:
+			log.Printf("Ошибка удаления уведомления %d чата %d: %v", id, chatID, err)
+			text = "Не удалось удалить уведомление. Попробуйте позже."
+		default:
+			text = fmt.Sprintf("Уведомление №%d удалено.", id)
+		}
+
+	default:
+		text = "Действие отменено."
+	}
+
+	// Результат заменяет вопрос, кнопки убираются (повторное нажатие невозможно)
+	if _, err := h.bot.Send(tgbotapi.NewEditMessageText(chatID, messageID, text)); err != nil {
+		log.Printf("Ошибка изменения сообщения: %v", err)
+	}
+}
+
+// NotifyAlert отправляет сработавшее уведомление о курсе в чат уведомления
+// Параметры:
+//   - ctx: контекст выполнения (не используется: Telegram Bot API не принимает контекст)
+//   - alert: сработавшее уведомление
+//   - rate: текущий курс пары
+//
+// Возвращает:
+//   - error: ошибка отправки сообщения
+func (b *Bot) NotifyAlert(_ context.Context, alert models.RateAlert, rate float64) error {
+	text := fmt.Sprintf("Курс %s/%s: %.4f\nСработало уведомление №%d: %s",
+		alert.FromCurrency, alert.ToCurrency, rate, alert.ID, formatAlert(alert))
+	_, err := b.botAPI.Send(tgbotapi.NewMessage(alert.ChatID, text))
+	return err
+}
+
+// parseAlert разбирает пару, условие и порог уведомления: ["USD", "RUB", ">", "100"]
+func parseAlert(args []string) (models.RateAlert, error) {
+	if len(args) != 4 {
+		return models.RateAlert{}, errors.New("укажите пару валют, условие (> или <) и порог")
+	}
+	threshold, err := strconv.ParseFloat(strings.ReplaceAll(args[3], ",", "."), 64)
+	if err != nil || threshold <= 0 {
+		return models.RateAlert{}, errors.New("порог должен быть положительным числом")
+	}
+	condition := args[2]
+	if condition != models.AlertAbove && condition != models.AlertBelow {
+		return models.RateAlert{}, errors.New("условие должно быть > или <")
+	}
+	return models.RateAlert{
+		FromCurrency: strings.ToUpper(args[0]),
+		ToCurrency:   strings.ToUpper(args[1]),
+		Condition:    condition,
+		Threshold:    threshold,
+	}, nil
+}
+
+// formatAlert описывает условие уведомления: "USD/RUB > 100"
+func formatAlert(alert models.RateAlert) string {
+	return fmt.Sprintf("%s/%s %s %s", alert.FromCurrency, alert.ToCurrency, alert.Condition,
+		strconv.FormatFloat(alert.Threshold, 'f', -1, 64))
+}
+
+// deleteConfirmation - запрос подтверждения удаления уведомления
+func deleteConfirmation(chatID int64, id int) tgbotapi.MessageConfig {
+	response := tgbotapi.NewMessage(chatID, fmt.Sprintf("Удалить уведомление №%d?", id))
+	response.ReplyMarkup = confirmKeyboard("Удалить", alertCallbackDelete+strconv.Itoa(id))
+	return response
+}
+
+// confirmKeyboard - кнопки подтверждения действия и отмены
+func confirmKeyboard(action, data string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(action, data),
+		tgbotapi.NewInlineKeyboardButtonData("Отмена", alertCallbackCancel),
+	))
+}
@@ -5,22 +5,41 @@ import (
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5" // Официальная обертка Telegram Bot API
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"gw-currency-wallet/internal/notify"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage"
 	"log"
+	"strconv"
 	"time"
 )
 
 // Bot представляет Telegram бота и содержит его основные компоненты
 type Bot struct {
-	botAPI *tgbotapi.BotAPI // Клиент Telegram Bot API
-	config Config           // Конфигурация бота
+	botAPI  *Sender          // Клиент Telegram Bot API с ограничением частоты сообщений
+	config  Config           // Конфигурация бота
+	conn    *grpc.ClientConn // Соединение с сервисом курсов валют
+	handler *Handler         // Обработчик команд
 }
 
 // Config содержит настройки для инициализации бота
 type Config struct {
-	Token               string        // Токен бота от @BotFather
-	ExchangeServiceAddr string        // Адрес gRPC сервиса курсов валют
-	UpdateTimeout       time.Duration // Таймаут получения обновлений
+	Token               string                        // Токен бота от @BotFather
+	ExchangeServiceAddr string                        // Адрес gRPC сервиса курсов валют
+	ExchangeAPIToken    string                        // API токен бота для сервиса курсов валют
+	ExchangeClient      services.ClientConfig         // Параметры соединения с сервисом курсов валют
+	UpdateTimeout       time.Duration                 // Таймаут получения обновлений
+	Debug               bool                          // Журнал всех запросов и обновлений Telegram (содержит данные пользователей)
+	Charts              ChartSource                   // Источник свечей для графиков (nil - команда /chart недоступна)
+	ChartCache          storage.Cache                 // Кэш изображений графиков (nil - без кэша)
+	ChartCacheTTL       time.Duration                 // Время хранения изображения графика в кэше
+	Alerts              *services.AlertService        // Уведомления о курсах (nil - команда /alert недоступна)
+	Favorites           *services.FavoritesService    // Избранные валюты чатов (nil - /rates выводит все валюты)
+	Chats               *services.ChatService         // Настройки чатов и ежедневные сводки (nil - команда /digest недоступна)
+	CommandLimit        int                           // Максимум команд в одном чате за CommandWindow (0 - без ограничений)
+	CommandWindow       time.Duration                 // Окно ограничения частоты команд
+	AdminChatID         int64                         // Служебный чат дежурных для оповещений (0 - оповещения не отправляются)
+	Links               *services.TelegramLinkService // Привязка аккаунтов Telegram к пользователям (nil - /link и /exchange недоступны)
+	Quotes              *services.QuoteService        // Котировки обмена (nil - команда /exchange недоступна)
 }
 
 // New создает новый экземпляр Telegram бота
@@ -31,15 +50,45 @@ type Config struct {
 //   - *Bot: инициализированный бот
 //   - error: ошибка при создании (например, невалидный токен)
 func New(config Config) (*Bot, error) {
-	// Инициализация клиента Telegram API
-	botAPI, err := tgbotapi.NewBotAPI(config.Token)
+	// 1. Инициализация клиента Telegram API (журнал клиента - в журнал приложения, без токена)
+	if err := tgbotapi.SetLogger(botLogger{token: config.Token}); err != nil {
+		return nil, fmt.Errorf("ошибка настройки журнала бота: %w", err)
+	}
+	api, err := tgbotapi.NewBotAPI(config.Token)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка создания бота: %w", err)
 	}
+	api.Debug = config.Debug // Режим отладки только по явной настройке
+	botAPI := NewSender(api) // Исходящие сообщения - в пределах ограничений Telegram
+
+	// 2. Подключение к gRPC сервису курсов валют
+	conn, err := services.NewExchangeConn(config.ExchangeServiceAddr,
+		append(services.ClientOptions(config.ExchangeClient),
+			services.WithAPIToken(config.ExchangeAPIToken), // Токен бота (отдельная квота)
+		)...,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подключения к сервису курсов валют: %w", err)
+	}
+
+	// 3. Инициализация клиента для работы с курсами валют
+	exchangeService := NewExchangeService(conn)
+
+	// 4. Создание обработчиков сообщений с передачей зависимостей
+	var charts *Charts
+	if config.Charts != nil {
+		charts = NewCharts(config.Charts, config.ChartCache, config.ChartCacheTTL)
+	}
+	throttle := NewCommandThrottle(config.CommandLimit, config.CommandWindow)
+	handler := NewHandler(botAPI, exchangeService, charts, config.Alerts, config.Favorites, config.Chats, throttle,
+		config.Links, config.Quotes)
 
 	return &Bot{
-		botAPI: botAPI,
-		config: config,
+		botAPI:  botAPI,
+		config:  config,
+		conn:    conn,
+		handler: handler,
 	}, nil
 }
 
@@ -50,36 +99,25 @@ func New(config Config) (*Bot, error) {
 // Возвращает:
 //   - error: ошибка при работе бота
 func (b *Bot) Start(ctx context.Context) error {
-	b.botAPI.Debug = true // Включаем режим отладки
 	log.Printf("Авторизован как %s", b.botAPI.Self.UserName)
+	defer b.conn.Close() // Гарантированное закрытие соединения с сервисом курсов валют
+	handler := b.handler
 
-	// 1. Подключение к gRPC сервису курсов валют
-	conn, err := grpc.NewClient(b.config.ExchangeServiceAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()), // Без TLS
-		grpc.WithConnectParams(grpc.ConnectParams{
-			MinConnectTimeout: 5 * time.Second, // Минимальное время попытки подключения
-		}),
-	)
-
-	if err != nil {
-		return fmt.Errorf("ошибка подключения к сервису курсов валют: %w", err)
+	// Меню команд в клиентах Telegram (ошибка не мешает работе бота)
+	if err := handler.RegisterMenu(); err != nil {
+		log.Printf("Ошибка регистрации команд бота: %v", err)
 	}
-	defer conn.Close() // Гарантированное закрытие соединения
-
-	// 2. Инициализация клиента для работы с курсами валют
-	exchangeService := NewExchangeService(conn)
-
-	// 3. Создание обработчиков сообщений с передачей зависимостей
-	handler := NewHandler(b.botAPI, exchangeService)
 
-	// 4. Настройка канала обновлений
+	// 1. Настройка канала обновлений
 	u := tgbotapi.NewUpdate(0) // offset=0 - получаем все обновления
 	u.Timeout = int(b.config.UpdateTimeout.Seconds())
+	// Изменения статуса бота в чатах не приходят без явного запроса
+	u.AllowedUpdates = []string{"message", "callback_query", "my_chat_member"}
 
 	// Получаем канал обновлений от Telegram
 	updates := b.botAPI.GetUpdatesChan(u)
 
-	// 5. Главный цикл обработки сообщений
+	// 2. Главный цикл обработки сообщений
 	for {
 		select {
 		case <-ctx.Done():
@@ -87,6 +125,18 @@ func (b *Bot) Start(ctx context.Context) error {
 			log.Println("Бот завершает работу...")
 			return nil
 		case update := <-updates:
+			// Нажатия кнопок подтверждения
+			if update.CallbackQuery != nil {
+				handler.HandleCallback(update.CallbackQuery)
+				continue
+			}
+
+			// Добавление бота в группу и удаление из нее
+			if update.MyChatMember != nil {
+				handler.HandleMembership(update.MyChatMember)
+				continue
+			}
+
 			// Игнорируем не-сообщения (например, обновления чатов)
 			if update.Message == nil {
 				continue
@@ -99,3 +149,55 @@ func (b *Bot) Start(ctx context.Context) error {
 		}
 	}
 }
+
+// NotifyAdmin отправляет оповещение в служебный чат дежурных
+// Параметры:
+//   - ctx: контекст выполнения (не используется: Telegram Bot API не принимает контекст)
+//   - text: текст оповещения
+//
+// Возвращает:
+//   - error: ошибка отправки (nil, если служебный чат не задан)
+func (b *Bot) NotifyAdmin(_ context.Context, text string) error {
+	if b.config.AdminChatID == 0 {
+		return nil
+	}
+	if _, err := b.botAPI.Send(tgbotapi.NewMessage(b.config.AdminChatID, text)); err != nil {
+		return fmt.Errorf("ошибка отправки оповещения в служебный чат: %w", err)
+	}
+	return nil
+}
+
+// NotifyUser отправляет уведомление в личный чат пользователя с привязанным аккаунтом Telegram
+// (идентификатор личного чата совпадает с идентификатором пользователя Telegram)
+func (b *Bot) NotifyUser(_ context.Context, chatID int64, text string) error {
+	if _, err := b.botAPI.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+		return fmt.Errorf("ошибка отправки уведомления в Telegram: %w", err)
+	}
+	return nil
+}
+
+// notificationChannel - канал доставки уведомлений об операциях в личный чат Telegram
+type notificationChannel struct {
+	name string
+	bot  *Bot
+}
+
+// NotificationChannel возвращает канал доставки уведомлений об операциях через бота
+// Адрес доставки - идентификатор личного чата привязанного аккаунта
+func (b *Bot) NotificationChannel(name string) notify.Channel {
+	return &notificationChannel{name: name, bot: b}
+}
+
+// Name возвращает название канала
+func (c *notificationChannel) Name() string {
+	return c.name
+}
+
+// Send отправляет уведомление в личный чат
+func (c *notificationChannel) Send(ctx context.Context, delivery notify.Delivery) error {
+	chatID, err := strconv.ParseInt(delivery.Target, 10, 64)
+	if err != nil || chatID == 0 {
+		return fmt.Errorf("%w: аккаунт Telegram не привязан", notify.ErrUndeliverable)
+	}
+	return c.bot.NotifyUser(ctx, chatID, delivery.Text)
+}
@@ -0,0 +1,160 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wcharczuk/go-chart/v2" // Построение графиков в PNG
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChartSource предоставляет свечи курса пары валют (тот же источник, что у /api/v1/exchange/chart)
+type ChartSource interface {
+	// GetChart возвращает свечи курса пары (USD/RUB) с интервалом interval за период period до текущего момента
+	GetChart(ctx context.Context, pair, interval, period string) (*models.RateChart, error)
+}
+
+// Параметры графиков бота
+const (
+	defaultChartRange = "7d"             // Период графика, если он не указан в команде
+	chartTimeout      = 10 * time.Second // Таймаут получения свечей
+	chartWidth        = 1024             // Ширина изображения в пикселях
+	chartHeight       = 512              // Высота изображения в пикселях
+)
+
+// errChartUsage - ошибка разбора аргументов команды /chart
+var errChartUsage = errors.New("использование: /chart USD RUB 7d (период: 24h, 7d, 30d, 90d, 365d)")
+
+// Charts строит изображения графиков курсов и кэширует их
+type Charts struct {
+	source ChartSource   // Свечи курсов
+	cache  storage.Cache // Кэш построенных изображений (nil - без кэша)
+	ttl    time.Duration // Время хранения изображения в кэше
+}
+
+// NewCharts создает построитель графиков
+// Параметры:
+//   - source: источник свечей курсов
+//   - cache: кэш построенных изображений (nil - изображения строятся на каждый запрос)
+//   - ttl: время хранения изображения в кэше
+//
+// Возвращает:
+//   - *Charts: построитель графиков
+func NewCharts(source ChartSource, cache storage.Cache, ttl time.Duration) *Charts {
+	return &Charts{source: source, cache: cache, ttl: ttl}
+}
+
+// Render возвращает PNG изображение графика курса по аргументам команды /chart ("USD RUB 7d")
+// Параметры:
+//   - ctx: контекст выполнения
+//   - args: аргументы команды
+//
+// Возвращает:
+//   - []byte: изображение в формате PNG
+//   - string: подпись к изображению
+//   - error: errChartUsage при некорректных аргументах или ошибка получения свечей
+func (c *Charts) Render(ctx context.Context, args string) ([]byte, string, error) {
+	fields := strings.Fields(strings.ToUpper(args))
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, "", errChartUsage
+	}
+	pair := fields[0] + "/" + fields[1]
+	period := defaultChartRange
+	if len(fields) == 3 {
+		period = strings.ToLower(fields[2])
+	}
+	interval, err := chartInterval(period)
+	if err != nil {
+		return nil, "", err
+	}
+	caption := fmt.Sprintf("Курс %s за %s", pair, period)
+
+	key := "telegram:chart:" + pair + ":" + period
+	if c.cache != nil {
+		if image, err := c.cache.Get(ctx, key); err == nil {
+			return image, caption, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, chartTimeout)
+	defer cancel()
+	rateChart, err := c.source.GetChart(ctx, pair, interval, period)
+	if err != nil {
+		return nil, "", err
+	}
+	image, err := renderChart(rateChart)
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка построения графика %s: %w", pair, err)
+	}
+
+	if c.cache != nil {
+		_ = c.cache.Set(ctx, key, image, c.ttl) // Ошибка кэша не мешает ответу
+	}
+	return image, caption, nil
+}
+
+// chartInterval подбирает длительность свечи по периоду графика (от 24 до 180 точек на графике)
+func chartInterval(period string) (string, error) {
+	length, err := time.ParseDuration(period)
+	if days, ok := strings.CutSuffix(period, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		length = time.Duration(n) * 24 * time.Hour
+	}
+	switch {
+	case err != nil || length < time.Hour:
+		return "", errChartUsage
+	case length <= 24*time.Hour:
+		return "15m", nil
+	case length <= 7*24*time.Hour:
+		return "1h", nil
+	case length <= 30*24*time.Hour:
+		return "4h", nil
+	default:
+		return "1d", nil
+	}
+}
+
+// renderChart строит график курса закрытия свечей и отрисовывает его в PNG
+func renderChart(rateChart *models.RateChart) ([]byte, error) {
+	if len(rateChart.Candles) < 2 {
+		return nil, errors.New("недостаточно данных для графика")
+	}
+
+	series := chart.TimeSeries{
+		Name:    rateChart.Pair,
+		XValues: make([]time.Time, 0, len(rateChart.Candles)),
+		YValues: make([]float64, 0, len(rateChart.Candles)),
+	}
+	for _, candle := range rateChart.Candles {
+		series.XValues = append(series.XValues, candle.Time)
+		series.YValues = append(series.YValues, candle.Close)
+	}
+
+	timeFormat := "02.01"
+	if rateChart.Interval == "15m" {
+		timeFormat = "15:04"
+	}
+	graph := chart.Chart{
+		Title:  rateChart.Pair + ", " + rateChart.Range,
+		Width:  chartWidth,
+		Height: chartHeight,
+		Background: chart.Style{
+			Padding: chart.Box{Top: 50, Left: 20, Right: 20, Bottom: 20}, // Место для заголовка над графиком
+		},
+		XAxis:  chart.XAxis{ValueFormatter: chart.TimeValueFormatterWithFormat(timeFormat)},
+		YAxis:  chart.YAxis{ValueFormatter: func(v interface{}) string { return fmt.Sprintf("%.4f", v) }},
+		Series: []chart.Series{series},
+	}
+
+	var buf bytes.Buffer
+	if err := graph.Render(chart.PNG, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
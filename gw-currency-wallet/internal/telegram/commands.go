@@ -0,0 +1,168 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// CommandFunc выполняет команду и возвращает ответ: сообщение, изображение и т.п.
+// (nil - ответ не отправляется)
+type CommandFunc func(msg *tgbotapi.Message) tgbotapi.Chattable
+
+// Command описывает команду бота
+type Command struct {
+	Name        string                 // Имя команды без "/" (латиница в нижнем регистре, цифры и "_")
+	Description string                 // Описание для /help и меню команд Telegram
+	Usage       string                 // Пример использования для /help (пусто - без аргументов)
+	Handle      CommandFunc            // Выполнение команды
+	AdminOnly   func(args string) bool // Изменяет ли команда с этими аргументами настройки чата (nil - доступна всем)
+}
+
+// Register добавляет команду бота; команда с тем же именем заменяется
+// Команды выводятся в /help и меню Telegram в порядке регистрации
+// Параметры:
+//   - cmd: описание команды
+func (h *Handler) Register(cmd Command) {
+	if _, ok := h.commands[cmd.Name]; !ok {
+		h.commandOrder = append(h.commandOrder, cmd.Name)
+	}
+	h.commands[cmd.Name] = cmd
+}
+
+// registerCommands регистрирует встроенные команды; команды недоступных функций не регистрируются
+func (h *Handler) registerCommands() {
+	h.Register(Command{
+		Name:        "start",
+		Description: "Начать работу с ботом",
+		Handle: textCommand(func(*tgbotapi.Message) string {
+			return "Привет! Я бот для отслеживания курсов валют.\n\n" + h.helpText()
+		}),
+	})
+	h.Register(Command{
+		Name:        "help",
+		Description: "Список команд",
+		Handle: textCommand(func(*tgbotapi.Message) string {
+			return h.helpText()
+		}),
+	})
+	h.Register(Command{
+		Name:        "rates",
+		Description: "Текущие курсы валют",
+		Handle:      textCommand(h.handleRates),
+	})
+	if h.favorites != nil {
+		h.Register(Command{
+			Name:        "favorites",
+			Description: "Валюты в /rates",
+			Usage:       "/favorites USD EUR CNY",
+			Handle:      textCommand(h.handleFavorites),
+			AdminOnly:   withArguments,
+		})
+	}
+	if h.charts != nil {
+		h.Register(Command{
+			Name:        "chart",
+			Description: "График курса пары",
+			Usage:       "/chart USD RUB 7d",
+			Handle:      h.handleChart,
+		})
+	}
+	if h.alerts != nil {
+		h.Register(Command{
+			Name:        "alert",
+			Description: "Уведомления о курсе",
+			Usage:       "/alert set USD RUB > 100",
+			Handle: func(msg *tgbotapi.Message) tgbotapi.Chattable {
+				return h.handleAlert(msg)
+			},
+			AdminOnly: func(args string) bool {
+				subcommand, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(args)), " ")
+				return subcommand == "set" || subcommand == "delete"
+			},
+		})
+	}
+	if h.links != nil {
+		h.Register(Command{
+			Name:        "link",
+			Description: "Привязать аккаунт кошелька",
+			Usage:       "/link КОД",
+			Handle:      textCommand(h.handleLink),
+		})
+	}
+	if h.links != nil && h.quotes != nil {
+		h.Register(Command{
+			Name:        "exchange",
+			Description: "Обмен валют в кошельке",
+			Usage:       "/exchange 100 USD EUR",
+			Handle:      h.handleExchange,
+		})
+	}
+	if h.chats != nil {
+		h.Register(Command{
+			Name:        "digest",
+			Description: "Ежедневная сводка курсов",
+			Usage:       "/digest 9",
+			Handle:      textCommand(h.handleDigest),
+			AdminOnly:   withArguments,
+		})
+	}
+}
+
+// textCommand преобразует обработчик, возвращающий текст, в CommandFunc
+func textCommand(handle func(msg *tgbotapi.Message) string) CommandFunc {
+	return func(msg *tgbotapi.Message) tgbotapi.Chattable {
+		return tgbotapi.NewMessage(msg.Chat.ID, handle(msg))
+	}
+}
+
+// withArguments - команда без аргументов показывает настройку, с аргументами - изменяет ее
+func withArguments(args string) bool {
+	return strings.TrimSpace(args) != ""
+}
+
+// helpText формирует список зарегистрированных команд
+func (h *Handler) helpText() string {
+	var sb strings.Builder
+	sb.WriteString("Доступные команды:\n")
+	for _, name := range h.commandOrder {
+		cmd := h.commands[name]
+		sb.WriteString(fmt.Sprintf("/%s - %s", cmd.Name, cmd.Description))
+		if cmd.Usage != "" {
+			sb.WriteString(fmt.Sprintf(" (например, %s)", cmd.Usage))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\nВ группах настройки чата могут менять только администраторы.")
+	return sb.String()
+}
+
+// RegisterMenu публикует список команд в меню Telegram (метод setMyCommands)
+// Возвращает:
+//   - error: ошибка запроса к Telegram Bot API
+func (h *Handler) RegisterMenu() error {
+	commands := make([]tgbotapi.BotCommand, 0, len(h.commandOrder))
+	for _, name := range h.commandOrder {
+		commands = append(commands, tgbotapi.BotCommand{Command: name, Description: h.commands[name].Description})
+	}
+	if _, err := h.bot.Request(tgbotapi.NewSetMyCommands(commands...)); err != nil {
+		return fmt.Errorf("ошибка публикации списка команд: %w", err)
+	}
+	return nil
+}
+
+// withReply делает ответ в группе ответом на сообщение с командой
+func withReply(response tgbotapi.Chattable, msg *tgbotapi.Message) tgbotapi.Chattable {
+	replyTo := replyTo(msg)
+	switch r := response.(type) {
+	case tgbotapi.MessageConfig:
+		r.ReplyToMessageID, r.AllowSendingWithoutReply = replyTo, true
+		return r
+	case tgbotapi.PhotoConfig:
+		r.ReplyToMessageID, r.AllowSendingWithoutReply = replyTo, true
+		return r
+	default:
+		return response
+	}
+}
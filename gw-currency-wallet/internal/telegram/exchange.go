@@ -0,0 +1,156 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+)
+
+// Данные кнопок подтверждения обмена (callback data)
+const (
+	exchangeCallbackPrefix  = "exchange:"         // Общий префикс кнопок обмена
+	exchangeCallbackConfirm = "exchange:confirm:" // Выполнение обмена: exchange:confirm:<id котировки>
+	exchangeCallbackCancel  = "exchange:cancel:"  // Отмена обмена: exchange:cancel:<id котировки>
+)
+
+// exchangeUsage - подсказка по команде /exchange
+const exchangeUsage = "Использование: /exchange 100 USD EUR - обменять 100 USD на EUR в кошельке"
+
+// privateOnly - ответ на команды кошелька вне личного чата
+const privateOnly = "Команда доступна только в личном чате с ботом."
+
+// notLinked - ответ пользователю, аккаунт которого не привязан к кошельку
+const notLinked = "Аккаунт Telegram не привязан к кошельку. Получите код в приложении " +
+	"(POST /api/v1/telegram/link) и отправьте его командой /link КОД."
+
+// handleLink обрабатывает команду /link: привязка аккаунта Telegram к пользователю кошелька по коду
+func (h *Handler) handleLink(msg *tgbotapi.Message) string {
+	if !msg.Chat.IsPrivate() {
+		return privateOnly
+	}
+	code := strings.TrimSpace(msg.CommandArguments())
+	if code == "" || msg.From == nil {
+		return notLinked
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	_, err := h.links.Link(ctx, code, msg.From.ID)
+	switch {
+	case errors.Is(err, services.ErrInvalidLinkCode):
+		return "Код привязки не найден или истек. Получите новый код в приложении."
+	case err != nil:
+		log.Printf("Ошибка привязки аккаунта Telegram %d: %v", msg.From.ID, err)
+		return "Не удалось привязать аккаунт. Попробуйте позже."
+	default:
+		return "Аккаунт привязан к кошельку. Обмен валют: " + strings.TrimPrefix(exchangeUsage, "Использование: ")
+	}
+}
+
+// handleExchange обрабатывает команду /exchange 100 USD EUR: котировка обмена с кнопками подтверждения
+func (h *Handler) handleExchange(msg *tgbotapi.Message) tgbotapi.Chattable {
+	if !msg.Chat.IsPrivate() || msg.From == nil {
+		return tgbotapi.NewMessage(msg.Chat.ID, privateOnly)
+	}
+
+	args := strings.Fields(strings.ToUpper(msg.CommandArguments()))
+	if len(args) != 3 {
+		return tgbotapi.NewMessage(msg.Chat.ID, exchangeUsage)
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(args[0], ",", "."), 64)
+	if err != nil || amount <= 0 {
+		return tgbotapi.NewMessage(msg.Chat.ID, "Некорректная сумма.\n\n"+exchangeUsage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	userID, err := h.links.LinkedUser(ctx, msg.From.ID)
+	if errors.Is(err, services.ErrTelegramNotLinked) {
+		return tgbotapi.NewMessage(msg.Chat.ID, notLinked)
+	}
+	if err != nil {
+		log.Printf("Ошибка получения привязки аккаунта Telegram %d: %v", msg.From.ID, err)
+		return tgbotapi.NewMessage(msg.Chat.ID, "Не удалось выполнить команду. Попробуйте позже.")
+	}
+
+	quote, err := h.quotes.Quote(ctx, userID, args[1], args[2], amount)
+	if err != nil {
+		return tgbotapi.NewMessage(msg.Chat.ID, "Не удалось рассчитать обмен: "+err.Error())
+	}
+
+	response := tgbotapi.NewMessage(msg.Chat.ID, formatQuote(quote))
+	response.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Подтвердить", exchangeCallbackConfirm+quote.ID),
+		tgbotapi.NewInlineKeyboardButtonData("Отмена", exchangeCallbackCancel+quote.ID),
+	))
+	return response
+}
+
+// handleExchangeCallback обрабатывает подтверждение или отмену обмена и заменяет котировку результатом
+func (h *Handler) handleExchangeCallback(query *tgbotapi.CallbackQuery) {
+	// Telegram ожидает ответ на нажатие, иначе кнопка остается в состоянии загрузки
+	if _, err := h.bot.Request(tgbotapi.NewCallback(query.ID, "")); err != nil {
+		log.Printf("Ошибка ответа на нажатие кнопки: %v", err)
+	}
+	if query.Message == nil || h.links == nil || h.quotes == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var text string
+	userID, err := h.links.LinkedUser(ctx, query.From.ID)
+	switch data := query.Data; {
+	case errors.Is(err, services.ErrTelegramNotLinked):
+		text = notLinked
+	case err != nil:
+		log.Printf("Ошибка получения привязки аккаунта Telegram %d: %v", query.From.ID, err)
+		text = "Не удалось выполнить обмен. Попробуйте позже."
+
+	case strings.HasPrefix(data, exchangeCallbackConfirm):
+		result, err := h.quotes.Confirm(ctx, userID, strings.TrimPrefix(data, exchangeCallbackConfirm))
+		switch {
+		case errors.Is(err, services.ErrQuoteNotFound):
+			text = "Котировка истекла. Запросите обмен заново: /exchange"
+		case errors.Is(err, services.ErrQuoteUsed):
+			text = "Обмен по этой котировке уже выполнен."
+		case err != nil:
+			text = "Обмен не выполнен: " + err.Error()
+		default:
+			text = fmt.Sprintf("Обмен выполнен: получено %.2f по курсу %.6f, комиссия %.2f (%.2f%%).",
+				result.ExchangedAmount, result.Rate, result.Fee, result.FeePercent)
+		}
+
+	default:
+		if err := h.quotes.Cancel(ctx, userID, strings.TrimPrefix(data, exchangeCallbackCancel)); err != nil {
+			log.Printf("Ошибка отмены котировки: %v", err)
+		}
+		text = "Обмен отменен."
+	}
+
+	// Результат заменяет котировку, кнопки убираются (повторное нажатие невозможно)
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, text)
+	if _, err := h.bot.Send(edit); err != nil {
+		log.Printf("Ошибка изменения сообщения: %v", err)
+	}
+}
+
+// formatQuote формирует текст котировки обмена: курс, комиссия, сумма к получению и срок действия
+func formatQuote(quote *models.ExchangeQuote) string {
+	return fmt.Sprintf("Обмен %.2f %s → %s\nКурс: %.6f\nКомиссия: %.2f %s (%.2f%%)\nК получению: %.2f %s\n\n"+
+		"Котировка действует до %s UTC.",
+		quote.Amount, quote.FromCurrency, quote.ToCurrency, quote.Rate,
+		quote.Fee, quote.ToCurrency, quote.FeePercent,
+		quote.ExchangedAmount, quote.ToCurrency,
+		quote.ExpiresAt.UTC().Format("15:04:05"))
+}
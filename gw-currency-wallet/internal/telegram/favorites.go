@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gw-currency-wallet/internal/services"
+)
+
+// favoritesUsage - подсказка по команде /favorites
+const favoritesUsage = "Использование:\n" +
+	"/favorites USD EUR CNY - показывать в /rates только эти валюты в этом порядке\n" +
+	"/favorites reset - показывать все валюты по алфавиту"
+
+// handleFavorites обрабатывает команду /favorites: показ, изменение и сброс избранных валют чата
+func (h *Handler) handleFavorites(msg *tgbotapi.Message) string {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	args := strings.Fields(strings.ToUpper(msg.CommandArguments()))
+	switch {
+	case len(args) == 0:
+		favorites, err := h.favorites.Get(ctx, msg.Chat.ID)
+		if err != nil {
+			log.Printf("Ошибка получения избранных валют чата %d: %v", msg.Chat.ID, err)
+			return "Не удалось получить избранные валюты. Попробуйте позже."
+		}
+		if len(favorites) == 0 {
+			return "Избранные валюты не выбраны, /rates показывает все валюты.\n\n" + favoritesUsage
+		}
+		return "Избранные валюты: " + strings.Join(favorites, " ") + "\n\n" + favoritesUsage
+
+	case len(args) == 1 && args[0] == "RESET":
+		if _, err := h.favorites.Set(ctx, msg.Chat.ID, nil); err != nil {
+			log.Printf("Ошибка сброса избранных валют чата %d: %v", msg.Chat.ID, err)
+			return "Не удалось сбросить избранные валюты. Попробуйте позже."
+		}
+		return "Избранные валюты сброшены, /rates показывает все валюты."
+	}
+
+	// Сохраняются только валюты, курсы которых публикует сервис обмена
+	rates, err := h.exchangeService.GetAllRates()
+	if err != nil {
+		return "Не удалось получить курсы валют. Попробуйте позже."
+	}
+	var unknown []string
+	for _, currency := range args {
+		if _, ok := rates[currency]; !ok {
+			unknown = append(unknown, currency)
+		}
+	}
+	if len(unknown) > 0 {
+		return "Нет курсов валют: " + strings.Join(unknown, ", ")
+	}
+
+	favorites, err := h.favorites.Set(ctx, msg.Chat.ID, args)
+	if errors.Is(err, services.ErrInvalidFavorites) {
+		return err.Error() + "\n\n" + favoritesUsage
+	}
+	if err != nil {
+		log.Printf("Ошибка сохранения избранных валют чата %d: %v", msg.Chat.ID, err)
+		return "Не удалось сохранить избранные валюты. Попробуйте позже."
+	}
+	return "Избранные валюты сохранены: " + strings.Join(favorites, " ")
+}
+
+// rateCurrencies возвращает валюты для вывода /rates: избранные валюты чата в заданном порядке,
+// а если они не выбраны (или недоступны) - все валюты по алфавиту
+func (h *Handler) rateCurrencies(chatID int64, rates map[string]float64) []string {
+	if h.favorites != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+
+		favorites, err := h.favorites.Get(ctx, chatID)
+		if err != nil {
+			log.Printf("Ошибка получения избранных валют чата %d: %v", chatID, err)
+		}
+		if len(favorites) > 0 {
+			return favorites
+		}
+	}
+
+	currencies := make([]string, 0, len(rates))
+	for currency := range rates {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+	return currencies
+}
@@ -0,0 +1,166 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/services"
+)
+
+// adminOnly - ответ на команду, изменяющую настройки группы, от участника без прав администратора
+const adminOnly = "Настройки группы могут менять только ее администраторы."
+
+// digestUsage - подсказка по команде /digest
+const digestUsage = "Использование:\n" +
+	"/digest 9 - присылать сводку курсов каждый день в 09:00 UTC\n" +
+	"/digest off - выключить сводку"
+
+// addressedToBot сообщает, адресована ли команда этому боту: без имени (/rates) или с его именем (/rates@bot)
+func (h *Handler) addressedToBot(msg *tgbotapi.Message) bool {
+	_, name, found := strings.Cut(msg.CommandWithAt(), "@")
+	return !found || strings.EqualFold(name, h.bot.Self.UserName)
+}
+
+// replyTo возвращает сообщение, ответом на которое отправляется ответ бота (в личном чате - без ответа)
+func replyTo(msg *tgbotapi.Message) int {
+	if msg.Chat.IsPrivate() {
+		return 0
+	}
+	return msg.MessageID
+}
+
+// canConfigure сообщает, может ли пользователь менять настройки чата:
+// в личном чате - всегда, в группе - только администратор или создатель
+func (h *Handler) canConfigure(chat *tgbotapi.Chat, user *tgbotapi.User) bool {
+	if chat.IsPrivate() {
+		return true
+	}
+	if user == nil {
+		return false
+	}
+
+	member, err := h.bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chat.ID, UserID: user.ID},
+	})
+	if err != nil {
+		log.Printf("Ошибка проверки прав пользователя %d в чате %d: %v", user.ID, chat.ID, err)
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+// HandleMembership обрабатывает добавление бота в группу и его удаление из нее:
+// при добавлении чат сохраняется и получает приветствие, при удалении удаляются его настройки
+// Параметры:
+//   - update: изменение статуса бота в чате
+func (h *Handler) HandleMembership(update *tgbotapi.ChatMemberUpdated) {
+	if h.chats == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	chat := update.Chat
+	switch update.NewChatMember.Status {
+	case "left", "kicked":
+		if err := h.chats.Remove(ctx, chat.ID); err != nil {
+			log.Printf("Ошибка удаления настроек чата %d: %v", chat.ID, err)
+		}
+
+	case "member", "administrator":
+		if update.OldChatMember.Status == "member" || update.OldChatMember.Status == "administrator" {
+			return // Изменились права бота, а не его участие в чате
+		}
+		err := h.chats.Register(ctx, &models.TelegramChat{ChatID: chat.ID, Type: chat.Type, Title: chat.Title})
+		if err != nil {
+			log.Printf("Ошибка сохранения чата %d: %v", chat.ID, err)
+		}
+		if chat.IsPrivate() {
+			return
+		}
+		greeting := tgbotapi.NewMessage(chat.ID, "Привет! Я показываю курсы валют: /rates, /chart USD RUB 7d, "+
+			"/alert - уведомления о курсе. Администраторы группы могут выбрать валюты (/favorites) "+
+			"и включить ежедневную сводку (/digest).")
+		if _, err := h.bot.Send(greeting); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
+	}
+}
+
+// handleDigest обрабатывает команду /digest: показ, включение и выключение ежедневной сводки курсов
+func (h *Handler) handleDigest(msg *tgbotapi.Message) string {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	arg := strings.ToLower(strings.TrimSpace(msg.CommandArguments()))
+	if arg == "" {
+		chat, err := h.chats.Get(ctx, msg.Chat.ID)
+		if err != nil {
+			log.Printf("Ошибка получения настроек чата %d: %v", msg.Chat.ID, err)
+			return "Не удалось получить настройки чата. Попробуйте позже."
+		}
+		if chat == nil || chat.DigestHour == nil {
+			return "Ежедневная сводка выключена.\n\n" + digestUsage
+		}
+		return fmt.Sprintf("Ежедневная сводка приходит в %02d:00 UTC.\n\n%s", *chat.DigestHour, digestUsage)
+	}
+
+	var hour *int
+	if arg != "off" {
+		value, err := strconv.Atoi(strings.TrimSuffix(arg, ":00"))
+		if err != nil {
+			return digestUsage
+		}
+		hour = &value
+	}
+
+	chat := &models.TelegramChat{ChatID: msg.Chat.ID, Type: msg.Chat.Type, Title: msg.Chat.Title}
+	err := h.chats.SetDigest(ctx, chat, hour)
+	switch {
+	case errors.Is(err, services.ErrInvalidDigestHour):
+		return err.Error() + "\n\n" + digestUsage
+	case err != nil:
+		log.Printf("Ошибка изменения сводки чата %d: %v", msg.Chat.ID, err)
+		return "Не удалось изменить сводку. Попробуйте позже."
+	case hour == nil:
+		return "Ежедневная сводка выключена."
+	default:
+		return fmt.Sprintf("Ежедневная сводка будет приходить в %02d:00 UTC.", *hour)
+	}
+}
+
+// SendDigests отправляет ежедневные сводки курсов чатам, час сводки которых наступил
+// Параметры:
+//   - ctx: контекст выполнения
+//
+// Возвращает:
+//   - error: ошибка выбора чатов (ошибки отправки записываются в журнал, сводка не повторяется)
+func (b *Bot) SendDigests(ctx context.Context) error {
+	if b.handler.chats == nil {
+		return nil
+	}
+
+	chats, err := b.handler.chats.DueDigests(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, chatID := range chats {
+		text, err := b.handler.ratesText(chatID)
+		if err != nil {
+			log.Printf("Ошибка подготовки сводки для чата %d: %v", chatID, err)
+			continue
+		}
+		if _, err := b.botAPI.Send(tgbotapi.NewMessage(chatID, "Ежедневная сводка. "+text)); err != nil {
+			log.Printf("Ошибка отправки сводки в чат %d: %v", chatID, err)
+		}
+	}
+	return nil
+}
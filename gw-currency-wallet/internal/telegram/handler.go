@@ -1,71 +1,122 @@
 package telegram
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gw-currency-wallet/internal/services"
 )
 
+// commandTimeout - таймаут обращений к хранилищу при обработке одной команды
+const commandTimeout = 10 * time.Second
+
 // Handler представляет обработчик Telegram-бота, который управляет входящими командами
 // и взаимодействует с сервисом для получения курсов валют.
 type Handler struct {
-	bot             *tgbotapi.BotAPI // Клиент Telegram Bot API для отправки сообщений
-	exchangeService *ExchangeService // Сервис для работы с курсами валют
+	bot             *Sender                       // Клиент Telegram Bot API для отправки сообщений (с ограничением частоты)
+	exchangeService *ExchangeService              // Сервис для работы с курсами валют
+	charts          *Charts                       // Графики курсов (nil - команда /chart недоступна)
+	alerts          *services.AlertService        // Уведомления о курсах (nil - команда /alert недоступна)
+	favorites       *services.FavoritesService    // Избранные валюты чатов (nil - /rates выводит все валюты)
+	chats           *services.ChatService         // Чаты и их настройки (nil - сводка недоступна)
+	throttle        *CommandThrottle              // Ограничение частоты команд в чате (nil - без ограничений)
+	links           *services.TelegramLinkService // Привязка аккаунтов Telegram (nil - команда /link недоступна)
+	quotes          *services.QuoteService        // Котировки обмена (nil - команда /exchange недоступна)
+	commands        map[string]Command            // Зарегистрированные команды по имени
+	commandOrder    []string                      // Имена команд в порядке регистрации (для /help и меню)
 }
 
 // NewHandler создает новый экземпляр Handler с заданными зависимостями.
 // Параметры:
-//   - bot: клиент Telegram Bot API
+//   - bot: клиент Telegram Bot API с ограничением частоты сообщений
 //   - exchangeService: сервис для работы с курсами валют
+//   - charts: построитель графиков курсов (nil - команда /chart недоступна)
+//   - alerts: сервис уведомлений о курсах (nil - команда /alert недоступна)
+//   - favorites: сервис избранных валют (nil - /rates выводит все валюты)
+//   - chats: сервис чатов и их настроек (nil - команда /digest недоступна)
+//   - throttle: ограничение частоты команд в чате (nil - без ограничений)
+//   - links: сервис привязки аккаунтов Telegram (nil - команды /link и /exchange недоступны)
+//   - quotes: сервис котировок обмена (nil - команда /exchange недоступна)
 //
 // Возвращает:
 //   - Указатель на созданный Handler
-func NewHandler(bot *tgbotapi.BotAPI, exchangeService *ExchangeService) *Handler {
-	return &Handler{
+func NewHandler(
+	bot *Sender,
+	exchangeService *ExchangeService,
+	charts *Charts,
+	alerts *services.AlertService,
+	favorites *services.FavoritesService,
+	chats *services.ChatService,
+	throttle *CommandThrottle,
+	links *services.TelegramLinkService,
+	quotes *services.QuoteService,
+) *Handler {
+	h := &Handler{
 		bot:             bot,
 		exchangeService: exchangeService,
+		charts:          charts,
+		alerts:          alerts,
+		favorites:       favorites,
+		chats:           chats,
+		throttle:        throttle,
+		links:           links,
+		quotes:          quotes,
+		commands:        make(map[string]Command),
 	}
+	h.registerCommands()
+	return h
 }
 
 // HandleCommand обрабатывает входящую команду от пользователя и отправляет соответствующий ответ.
-// В зависимости от команды (например, "/start" или "/rates"), формируется ответное сообщение.
+// Команда (например, "/start" или "/rates") выполняется обработчиком из реестра команд (Register).
+// В группах бот отвечает на сообщение с командой, а команды другим ботам (/rates@other_bot)
+// и неизвестные команды пропускает. Слишком частые команды в чате не выполняются
 // Параметры:
 //   - msg: входящее сообщение от пользователя
 func (h *Handler) HandleCommand(msg *tgbotapi.Message) {
-	// Создаем новое сообщение для ответа в тот же чат
-	response := tgbotapi.NewMessage(msg.Chat.ID, "")
-
-	// Обрабатываем команду из сообщения
-	switch msg.Command() {
-	case "start":
-		// Ответ на команду /start
-		response.Text = "Привет! Я бот для отслеживания курсов валют. Используй команду /rates чтобы получить текущие курсы."
-
-	case "rates":
-		// Ответ на команду /rates: получение и отображение текущих курсов валют
-		rates, err := h.exchangeService.GetAllRates()
-		if err != nil {
-			response.Text = "Не удалось получить курсы валют. Попробуйте позже."
-			break
-		}
+	if !h.addressedToBot(msg) {
+		return
+	}
 
-		// Формируем строку с курсами валют
-		var sb strings.Builder
-		sb.WriteString("Текущие курсы валют в рублях:\n\n")
+	// Создаем новое сообщение для ответа в тот же чат (в группе - ответом на команду)
+	response := tgbotapi.NewMessage(msg.Chat.ID, "")
+	response.ReplyToMessageID = replyTo(msg)
+	response.AllowSendingWithoutReply = true
 
-		// Добавляем каждую валюту и ее курс в ответ
-		for currency, rate := range rates {
-			flag := GetCurrencyFlag(currency) // Получаем флаг для валюты (например, 🇺🇸 для USD)
-			sb.WriteString(fmt.Sprintf("%s %s: %.4f\n", flag, currency, rate))
+	// Слишком частые команды не выполняются, о превышении чат предупреждается один раз
+	if allowed, warn := h.throttle.Allow(msg.Chat.ID); !allowed {
+		if warn {
+			response.Text = "Слишком много команд. Пожалуйста, подождите немного и повторите."
+			if _, err := h.bot.Send(response); err != nil {
+				log.Printf("Ошибка отправки сообщения: %v", err)
+			}
 		}
+		return
+	}
 
-		response.Text = sb.String()
-
+	// Команды, изменяющие настройки группы, доступны только ее администраторам
+	cmd, ok := h.commands[msg.Command()]
+	switch {
+	case !ok && !msg.Chat.IsPrivate():
+		return // В группе команда без имени бота может быть адресована другому боту
+	case !ok:
+		response.Text = "Я не знаю такой команды. Список команд: /help"
+	case cmd.AdminOnly != nil && cmd.AdminOnly(msg.CommandArguments()) && !h.canConfigure(msg.Chat, msg.From):
+		response.Text = adminOnly
 	default:
-		// Ответ на неизвестную команду
-		response.Text = "Я не знаю такой команды. Доступные команды: /start, /rates"
+		reply := cmd.Handle(msg)
+		if reply == nil {
+			return
+		}
+		if _, err := h.bot.Send(withReply(reply, msg)); err != nil {
+			log.Printf("Ошибка отправки ответа на команду /%s: %v", cmd.Name, err)
+		}
+		return
 	}
 
 	// Отправляем ответ пользователю
@@ -73,3 +124,63 @@ func (h *Handler) HandleCommand(msg *tgbotapi.Message) {
 		log.Printf("Ошибка отправки сообщения: %v", err) // Логируем ошибку, если отправка не удалась
 	}
 }
+
+// HandleCallback обрабатывает нажатие кнопки под сообщением бота (подтверждение уведомления или обмена)
+// Параметры:
+//   - query: нажатие кнопки
+func (h *Handler) HandleCallback(query *tgbotapi.CallbackQuery) {
+	if strings.HasPrefix(query.Data, exchangeCallbackPrefix) {
+		h.handleExchangeCallback(query)
+		return
+	}
+	h.handleAlertCallback(query)
+}
+
+// handleRates обрабатывает команду /rates: текущие курсы валют чата
+func (h *Handler) handleRates(msg *tgbotapi.Message) string {
+	text, err := h.ratesText(msg.Chat.ID)
+	if err != nil {
+		return "Не удалось получить курсы валют. Попробуйте позже."
+	}
+	return text
+}
+
+// handleChart обрабатывает команду /chart USD RUB 7d: изображение графика курса пары
+func (h *Handler) handleChart(msg *tgbotapi.Message) tgbotapi.Chattable {
+	image, caption, err := h.charts.Render(context.Background(), msg.CommandArguments())
+	if errors.Is(err, errChartUsage) {
+		return tgbotapi.NewMessage(msg.Chat.ID, err.Error())
+	}
+	if err != nil {
+		log.Printf("Ошибка построения графика по команде %q: %v", msg.Text, err)
+		return tgbotapi.NewMessage(msg.Chat.ID, "Не удалось построить график. Попробуйте позже.")
+	}
+
+	photo := tgbotapi.NewPhoto(msg.Chat.ID, tgbotapi.FileBytes{Name: "chart.png", Bytes: image})
+	photo.Caption = caption
+	return photo
+}
+
+// ratesText формирует сообщение с текущими курсами: избранные валюты чата или все валюты по алфавиту
+func (h *Handler) ratesText(chatID int64) (string, error) {
+	rates, err := h.exchangeService.GetAllRates()
+	if err != nil {
+		return "", err
+	}
+
+	// Формируем строку с курсами валют
+	var sb strings.Builder
+	sb.WriteString("Текущие курсы валют в рублях:\n\n")
+
+	// Добавляем каждую валюту и ее курс в ответ
+	for _, currency := range h.rateCurrencies(chatID, rates) {
+		flag := GetCurrencyFlag(currency) // Получаем флаг для валюты (например, 🇺🇸 для USD)
+		rate, ok := rates[currency]
+		if !ok {
+			sb.WriteString(fmt.Sprintf("%s %s: нет курса\n", flag, currency))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s %s: %.4f\n", flag, currency, rate))
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,31 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// botLogger направляет журнал клиента Telegram Bot API в журнал приложения:
+// записи помечаются префиксом компонента, токен бота заменяется на [REDACTED]
+type botLogger struct {
+	token string // Токен бота (не должен попасть в журнал)
+}
+
+// Println записывает сообщение клиента Telegram Bot API
+func (l botLogger) Println(v ...interface{}) {
+	l.write(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// Printf записывает форматированное сообщение клиента Telegram Bot API
+func (l botLogger) Printf(format string, v ...interface{}) {
+	l.write(fmt.Sprintf(format, v...))
+}
+
+// write записывает сообщение в журнал приложения без токена бота
+func (l botLogger) write(message string) {
+	if l.token != "" {
+		message = strings.ReplaceAll(message, l.token, "[REDACTED]")
+	}
+	log.Print("telegram: " + message)
+}
@@ -30,8 +30,8 @@ func NewExchangeService(conn *grpc.ClientConn) *ExchangeService {
 //   - map[string]float64: словарь, где ключ — код валюты (например, "USD"), значение — курс к рублю
 //   - error: ошибка, если запрос к серверу не удался
 func (s *ExchangeService) GetAllRates() (map[string]float64, error) {
-	// Вызов gRPC-метода GetExchangeRates с пустым запросом (pb.Empty)
-	rates, err := s.client.GetExchangeRates(context.Background(), &pb.Empty{})
+	// Вызов gRPC-метода GetExchangeRates: курсы пересчитываются к рублю на сервере (база сервиса обмена настраивается)
+	rates, err := s.client.GetExchangeRates(context.Background(), &pb.ExchangeRatesRequest{BaseCurrency: "RUB"})
 	if err != nil {
 		return nil, err // Возвращаем ошибку, если запрос не удался
 	}
@@ -0,0 +1,223 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"golang.org/x/time/rate"
+)
+
+// Ограничения Telegram Bot API на исходящие сообщения
+const (
+	globalMessageRate    = 30               // Сообщений в секунду во все чаты
+	privateMessagePeriod = time.Second      // Интервал сообщений в личный чат
+	groupMessagePeriod   = 3 * time.Second  // Интервал сообщений в группу (20 в минуту)
+	groupMessageBurst    = 3                // Сообщений в группу подряд без ожидания
+	sendWaitTimeout      = 30 * time.Second // Максимальное ожидание очереди отправки
+	maxRetryAfter        = time.Minute      // Максимальная пауза, после которой сообщение отправляется повторно
+)
+
+// maxTrackedChats - число чатов с ограничителями, при превышении которого удаляются неактивные
+const maxTrackedChats = 10000
+
+// Sender отправляет запросы Telegram Bot API с соблюдением ограничений на частоту сообщений:
+// общего для бота и отдельного для каждого чата. При ответе 429 запрос повторяется один раз
+// после паузы, указанной Telegram
+type Sender struct {
+	*tgbotapi.BotAPI               // Клиент Telegram Bot API (методы без ограничений доступны напрямую)
+	global           *rate.Limiter // Общее ограничение бота
+	chats            *chatLimiters // Ограничения по чатам
+}
+
+// NewSender создает отправителя с ограничениями Telegram Bot API
+func NewSender(api *tgbotapi.BotAPI) *Sender {
+	return &Sender{
+		BotAPI: api,
+		global: rate.NewLimiter(globalMessageRate, globalMessageRate),
+		chats: newChatLimiters(func(chatID int64) *rate.Limiter {
+			if chatID < 0 { // Группы и каналы имеют отрицательные идентификаторы
+				return rate.NewLimiter(rate.Every(groupMessagePeriod), groupMessageBurst)
+			}
+			return rate.NewLimiter(rate.Every(privateMessagePeriod), 1)
+		}),
+	}
+}
+
+// Send отправляет сообщение, ожидая очереди чата и бота
+// Параметры:
+//   - c: сообщение, изображение или изменение сообщения
+//
+// Возвращает:
+//   - tgbotapi.Message: отправленное сообщение
+//   - error: ошибка отправки или превышено время ожидания очереди
+func (s *Sender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var message tgbotapi.Message
+	err := s.do(c, func() (err error) {
+		message, err = s.BotAPI.Send(c)
+		return err
+	})
+	return message, err
+}
+
+// Request выполняет запрос к Telegram Bot API; ответы на нажатия кнопок не ограничиваются
+func (s *Sender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if _, ok := c.(tgbotapi.CallbackConfig); ok {
+		return s.BotAPI.Request(c)
+	}
+
+	var response *tgbotapi.APIResponse
+	err := s.do(c, func() (err error) {
+		response, err = s.BotAPI.Request(c)
+		return err
+	})
+	return response, err
+}
+
+// do выполняет запрос после ожидания очереди и повторяет его один раз при ответе 429
+func (s *Sender) do(c tgbotapi.Chattable, request func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sendWaitTimeout)
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		if err := s.wait(ctx, c); err != nil {
+			return fmt.Errorf("очередь отправки Telegram переполнена: %w", err)
+		}
+
+		err := request()
+		var apiErr *tgbotapi.Error
+		if attempt > 0 || !errors.As(err, &apiErr) || apiErr.Code != 429 {
+			return err
+		}
+
+		retryAfter := time.Duration(apiErr.RetryAfter) * time.Second
+		if retryAfter > maxRetryAfter {
+			return err
+		}
+		log.Printf("Telegram ограничил частоту сообщений, повтор через %s", retryAfter)
+		time.Sleep(retryAfter)
+	}
+}
+
+// wait ожидает очереди чата запроса (если он известен) и общей очереди бота
+func (s *Sender) wait(ctx context.Context, c tgbotapi.Chattable) error {
+	if chatID, ok := chatOf(c); ok {
+		if err := s.chats.get(chatID).Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return s.global.Wait(ctx)
+}
+
+// chatOf возвращает чат, в который отправляется запрос
+func chatOf(c tgbotapi.Chattable) (int64, bool) {
+	switch c := c.(type) {
+	case tgbotapi.MessageConfig:
+		return c.ChatID, true
+	case tgbotapi.PhotoConfig:
+		return c.ChatID, true
+	case tgbotapi.EditMessageTextConfig:
+		return c.ChatID, true
+	default:
+		return 0, false
+	}
+}
+
+// CommandThrottle ограничивает частоту команд в каждом чате: не больше limit команд за window.
+// О превышении чат предупреждается один раз, следующие команды до освобождения лимита игнорируются
+type CommandThrottle struct {
+	chats *chatLimiters // Ограничения по чатам
+}
+
+// NewCommandThrottle создает ограничение частоты команд (limit <= 0 или window <= 0 - без ограничений)
+func NewCommandThrottle(limit int, window time.Duration) *CommandThrottle {
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+	return &CommandThrottle{
+		chats: newChatLimiters(func(int64) *rate.Limiter {
+			return rate.NewLimiter(rate.Every(window/time.Duration(limit)), limit)
+		}),
+	}
+}
+
+// Allow сообщает, можно ли выполнить команду в чате
+// Возвращает:
+//   - allowed: команду можно выполнить
+//   - warn: команду нельзя выполнить и чат еще не предупрежден о превышении
+func (t *CommandThrottle) Allow(chatID int64) (allowed, warn bool) {
+	if t == nil {
+		return true, false
+	}
+	return t.chats.allow(chatID)
+}
+
+// chatLimiters хранит ограничители частоты по чатам
+type chatLimiters struct {
+	mu       sync.Mutex
+	limiters map[int64]*chatLimiter
+	newFunc  func(chatID int64) *rate.Limiter // Создание ограничителя для нового чата
+}
+
+// chatLimiter - ограничитель частоты одного чата
+type chatLimiter struct {
+	*rate.Limiter
+	seen   time.Time // Последнее обращение
+	warned bool      // Чат предупрежден о превышении
+}
+
+// newChatLimiters создает хранилище ограничителей по чатам
+func newChatLimiters(newFunc func(chatID int64) *rate.Limiter) *chatLimiters {
+	return &chatLimiters{limiters: make(map[int64]*chatLimiter), newFunc: newFunc}
+}
+
+// get возвращает ограничитель чата, создавая его при первом обращении
+func (l *chatLimiters) get(chatID int64) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lookup(chatID).Limiter
+}
+
+// allow расходует одно обращение чата и отмечает первое превышение
+func (l *chatLimiters) allow(chatID int64) (allowed, warn bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter := l.lookup(chatID)
+	if limiter.Allow() {
+		limiter.warned = false
+		return true, false
+	}
+	warn = !limiter.warned
+	limiter.warned = true
+	return false, warn
+}
+
+// lookup находит или создает ограничитель чата (вызывается под блокировкой)
+func (l *chatLimiters) lookup(chatID int64) *chatLimiter {
+	now := time.Now()
+	limiter, ok := l.limiters[chatID]
+	if !ok {
+		if len(l.limiters) >= maxTrackedChats {
+			l.prune(now)
+		}
+		limiter = &chatLimiter{Limiter: l.newFunc(chatID)}
+		l.limiters[chatID] = limiter
+	}
+	limiter.seen = now
+	return limiter
+}
+
+// prune удаляет ограничители чатов, к которым не обращались дольше времени полного восстановления лимита
+func (l *chatLimiters) prune(now time.Time) {
+	for chatID, limiter := range l.limiters {
+		refill := time.Duration(float64(limiter.Burst()) / float64(limiter.Limit()) * float64(time.Second))
+		if now.Sub(limiter.seen) > refill {
+			delete(l.limiters, chatID)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package telemetry настраивает экспорт трассировок и метрик OpenTelemetry в OpenTelemetry Collector (OTLP/gRPC)
+//
+// Setup устанавливает глобальные TracerProvider, MeterProvider и распространение контекста W3C (traceparent):
+// middleware.Tracing, клиент сервиса обмена и SLO-метрики пакета metrics используют их через otel.
+// Без адреса коллектора остаются провайдеры по умолчанию, которые ничего не записывают
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"log"
+	"time"
+)
+
+// Options - параметры экспорта OpenTelemetry
+type Options struct {
+	Endpoint        string        // Адрес коллектора (http://host:4317 - без TLS, https:// - с TLS); пустой - экспорт отключен
+	ServiceName     string        // Имя сервиса (service.name)
+	SampleRatio     float64       // Доля трассируемых запросов без входящей трассировки (решение вызывающего сервиса соблюдается)
+	MetricsInterval time.Duration // Период экспорта метрик
+}
+
+// ShutdownFunc отправляет накопленные трассировки и метрики и останавливает экспорт
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup настраивает экспорт трассировок и метрик OpenTelemetry
+// Распространение контекста трассировки (traceparent, baggage) включается всегда,
+// чтобы трассировка вызывающего сервиса продолжалась в сервисе обмена и без экспорта в коллектор
+// Параметры:
+//   - ctx: контекст выполнения
+//   - opts: параметры экспорта
+//
+// Возвращает:
+//   - ShutdownFunc: остановка экспорта (вызывается при завершении сервиса)
+//   - error: ошибка создания экспортеров
+func Setup(ctx context.Context, opts Options) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(opts.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка описания ресурса OpenTelemetry: %w", err)
+	}
+
+	// 1. Трассировки: пакетная отправка спанов
+	traceExporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(opts.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания экспортера трассировок: %w", err)
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+
+	// 2. Метрики: периодический экспорт; измерения в трассируемом запросе получают exemplar со span
+	metricExporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpointURL(opts.Endpoint))
+	if err != nil {
+		tracerProvider.Shutdown(ctx)
+		return nil, fmt.Errorf("ошибка создания экспортера метрик: %w", err)
+	}
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(opts.MetricsInterval))),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	log.Printf("Трассировки и метрики OpenTelemetry экспортируются в %s (доля трассировок %.2f)", opts.Endpoint, opts.SampleRatio)
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
@@ -0,0 +1,219 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"gw-currency-wallet/internal/fees"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultID - арендатор по умолчанию: пользователи, созданные до появления арендаторов,
+// запросы без заголовка X-Tenant-ID с неизвестного хоста и утилиты оператора
+const DefaultID = "default"
+
+// Header - заголовок, которым клиент явно указывает арендатора (приоритетнее хоста)
+const Header = "X-Tenant-ID"
+
+// ErrUnknownTenant возвращается для арендатора, отсутствующего в конфигурации
+var ErrUnknownTenant = errors.New("неизвестный арендатор")
+
+// validID ограничивает идентификаторы арендаторов (хранятся в БД и в JWT)
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// supportedCurrencies - валюты, которые поддерживает кошелек
+var supportedCurrencies = []string{"USD", "RUB", "EUR"}
+
+// Tenant - брендированный продукт кошелька в общей установке
+type Tenant struct {
+	ID                 string        `yaml:"id"`                   // Идентификатор (в БД и JWT)
+	Name               string        `yaml:"name"`                 // Название продукта
+	Hosts              []string      `yaml:"hosts"`                // Хосты, запросы на которые относятся к арендатору
+	Currencies         []string      `yaml:"currencies"`           // Доступные валюты (пусто - все поддерживаемые)
+	ExchangeFeePercent *float64      `yaml:"exchange_fee_percent"` // Базовая комиссия обмена (nil - как у арендатора по умолчанию)
+	FeeTiers           string        `yaml:"fee_tiers"`            // Уровни скидок в формате FEE_TIERS (пусто - как у арендатора по умолчанию)
+	Fees               fees.Schedule `yaml:"-"`                    // Комиссия и уровни после разбора
+}
+
+// SupportsCurrency проверяет, доступна ли валюта пользователям арендатора
+func (t *Tenant) SupportsCurrency(currency string) bool {
+	for _, c := range t.Currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// Registry - арендаторы установки с поиском по идентификатору и хосту
+type Registry struct {
+	tenants map[string]*Tenant // Арендаторы по идентификатору
+	hosts   map[string]*Tenant // Арендаторы по хосту
+}
+
+// file - формат файла арендаторов TENANTS_FILE
+type file struct {
+	Tenants []Tenant `yaml:"tenants"`
+}
+
+// Load создает реестр из арендатора по умолчанию и файла арендаторов
+// Параметры:
+//   - path: путь к YAML файлу арендаторов (пусто - только арендатор по умолчанию)
+//   - defaults: комиссия арендатора по умолчанию (глобальные EXCHANGE_FEE_PERCENT и FEE_TIERS)
+//
+// Возвращает:
+//   - *Registry: реестр арендаторов
+//   - error: ошибка чтения файла или некорректный арендатор
+func Load(path string, defaults fees.Schedule) (*Registry, error) {
+	var tenants []Tenant
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения файла арендаторов: %w", err)
+		}
+		var parsed file
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("ошибка разбора файла арендаторов: %w", err)
+		}
+		tenants = parsed.Tenants
+	}
+	return NewRegistry(defaults, tenants)
+}
+
+// NewRegistry создает реестр арендаторов
+// Арендатор по умолчанию добавляется всегда; его можно переопределить записью с id default
+// Параметры:
+//   - defaults: комиссия арендатора по умолчанию
+//   - tenants: арендаторы
+//
+// Возвращает:
+//   - *Registry: реестр арендаторов
+//   - error: некорректный или повторяющийся арендатор, хост или валюта
+func NewRegistry(defaults fees.Schedule, tenants []Tenant) (*Registry, error) {
+	r := &Registry{
+		tenants: map[string]*Tenant{DefaultID: {ID: DefaultID, Currencies: supportedCurrencies, Fees: defaults}},
+		hosts:   make(map[string]*Tenant),
+	}
+
+	seen := make(map[string]bool, len(tenants))
+	for i := range tenants {
+		t := tenants[i]
+		if !validID.MatchString(t.ID) {
+			return nil, fmt.Errorf("арендатор %q: идентификатор должен состоять из a-z, 0-9, _ и - (до 50 символов)", t.ID)
+		}
+		if seen[t.ID] {
+			return nil, fmt.Errorf("арендатор %q указан дважды", t.ID)
+		}
+		seen[t.ID] = true
+
+		currencies := make([]string, 0, len(t.Currencies))
+		for _, currency := range t.Currencies {
+			currency = strings.ToUpper(strings.TrimSpace(currency))
+			if !isSupported(currency) {
+				return nil, fmt.Errorf("арендатор %q: неподдерживаемая валюта %q", t.ID, currency)
+			}
+			currencies = append(currencies, currency)
+		}
+		if len(currencies) == 0 {
+			currencies = supportedCurrencies
+		}
+		t.Currencies = currencies
+
+		t.Fees = defaults
+		if t.ExchangeFeePercent != nil {
+			if *t.ExchangeFeePercent < 0 || *t.ExchangeFeePercent >= 100 {
+				return nil, fmt.Errorf("арендатор %q: exchange_fee_percent должен быть в диапазоне [0, 100)", t.ID)
+			}
+			t.Fees.BasePercent = *t.ExchangeFeePercent
+		}
+		if t.FeeTiers != "" {
+			tiers, err := fees.ParseTiers(t.FeeTiers)
+			if err != nil {
+				return nil, fmt.Errorf("арендатор %q: fee_tiers: %w", t.ID, err)
+			}
+			t.Fees.Tiers = tiers
+		}
+
+		for _, host := range t.Hosts {
+			host = normalizeHost(host)
+			if other, ok := r.hosts[host]; ok {
+				return nil, fmt.Errorf("хост %q указан у арендаторов %q и %q", host, other.ID, t.ID)
+			}
+			r.hosts[host] = &t
+		}
+		r.tenants[t.ID] = &t
+	}
+	return r, nil
+}
+
+// Get возвращает арендатора по идентификатору
+func (r *Registry) Get(id string) (*Tenant, bool) {
+	t, ok := r.tenants[id]
+	return t, ok
+}
+
+// Default возвращает арендатора по умолчанию
+func (r *Registry) Default() *Tenant {
+	return r.tenants[DefaultID]
+}
+
+// Resolve определяет арендатора запроса
+// Параметры:
+//   - header: значение заголовка X-Tenant-ID (пусто - по хосту)
+//   - host: хост запроса (с портом или без)
+//
+// Возвращает:
+//   - *Tenant: арендатор заголовка, хоста или арендатор по умолчанию
+//   - error: ErrUnknownTenant, если заголовок указывает на неизвестного арендатора
+func (r *Registry) Resolve(header, host string) (*Tenant, error) {
+	if header != "" {
+		t, ok := r.tenants[header]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownTenant, header)
+		}
+		return t, nil
+	}
+	if t, ok := r.hosts[normalizeHost(host)]; ok {
+		return t, nil
+	}
+	return r.Default(), nil
+}
+
+// isSupported проверяет, поддерживает ли кошелек валюту
+func isSupported(currency string) bool {
+	for _, c := range supportedCurrencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHost приводит хост к нижнему регистру без порта
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// contextKey - ключ арендатора в контексте запроса
+type contextKey struct{}
+
+// WithID возвращает контекст с арендатором (передается из middleware в сервисы)
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// IDFromContext возвращает арендатора из контекста (DefaultID, если арендатор не указан)
+func IDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return DefaultID
+}
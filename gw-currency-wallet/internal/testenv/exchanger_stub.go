@@ -0,0 +1,116 @@
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "gw-proto/proto"
+	"net"
+	"sync"
+	"testing"
+)
+
+// DefaultRates - курсы заглушки по умолчанию (стоимость единицы валюты в рублях, как у сервиса обмена)
+var DefaultRates = map[string]float64{"RUB": 1, "USD": 90, "EUR": 98}
+
+// ExchangerStub - gRPC-сервер с интерфейсом сервиса обмена и курсами, заданными тестом
+type ExchangerStub struct {
+	pb.UnimplementedExchangeServiceServer
+
+	listener net.Listener
+
+	mu    sync.Mutex
+	rates map[string]float64 // Текущие курсы к рублю
+	err   error              // Ошибка, возвращаемая на все вызовы (имитация недоступности)
+	calls int                // Количество вызовов (проверка кэширования)
+}
+
+// StartExchangerStub запускает заглушку на свободном локальном порту
+// Сервер останавливается по завершении теста
+func StartExchangerStub(t testing.TB) *ExchangerStub {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ошибка запуска заглушки сервиса обмена: %v", err)
+	}
+
+	stub := &ExchangerStub{listener: listener}
+	stub.SetRates(DefaultRates)
+
+	server := grpc.NewServer()
+	pb.RegisterExchangeServiceServer(server, stub)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return stub
+}
+
+// Addr возвращает адрес заглушки (host:port)
+func (s *ExchangerStub) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// SetRates заменяет курсы заглушки
+func (s *ExchangerStub) SetRates(rates map[string]float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates = make(map[string]float64, len(rates))
+	for currency, rate := range rates {
+		s.rates[currency] = rate
+	}
+}
+
+// FailWith заставляет заглушку отвечать ошибкой на все вызовы (nil - снова отвечать курсами)
+func (s *ExchangerStub) FailWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Calls возвращает количество обращений к заглушке
+func (s *ExchangerStub) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// GetExchangeRates возвращает все курсы
+func (s *ExchangerStub) GetExchangeRates(context.Context, *pb.ExchangeRatesRequest) (*pb.ExchangeRatesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	rates := make(map[string]float32, len(s.rates))
+	for currency, rate := range s.rates {
+		rates[currency] = float32(rate)
+	}
+	return &pb.ExchangeRatesResponse{Rates: rates}, nil
+}
+
+// GetExchangeRateForCurrency возвращает кросс-курс пары через рубль
+func (s *ExchangerStub) GetExchangeRateForCurrency(_ context.Context, req *pb.CurrencyRequest) (*pb.ExchangeRateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	from, okFrom := s.rates[req.FromCurrency]
+	to, okTo := s.rates[req.ToCurrency]
+	if !okFrom || !okTo || to == 0 {
+		return nil, status.Errorf(codes.NotFound, "курс %s/%s не найден", req.FromCurrency, req.ToCurrency)
+	}
+	return &pb.ExchangeRateResponse{
+		FromCurrency: req.FromCurrency,
+		ToCurrency:   req.ToCurrency,
+		Rate:         float32(from / to),
+	}, nil
+}
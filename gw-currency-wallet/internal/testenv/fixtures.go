@@ -0,0 +1,61 @@
+//go:build integration
+
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"gw-currency-wallet/internal/models"
+	"sync/atomic"
+	"testing"
+)
+
+// Funds - начальный баланс пользователя по валютам
+type Funds map[string]float64
+
+// userSeq делает имена пользователей уникальными в пределах запуска тестов
+var userSeq atomic.Int64
+
+// CreateUser создает пользователя с кошельком и начальным балансом
+// К имени добавляется порядковый номер, чтобы тесты не конфликтовали в общей БД
+// Пополнения проводятся через репозиторий и попадают в журнал операций
+func (e *Env) CreateUser(t testing.TB, name string, funds Funds) *models.User {
+	t.Helper()
+	ctx := context.Background()
+
+	user := &models.User{
+		Username:     fmt.Sprintf("%s_%d", name, userSeq.Add(1)),
+		PasswordHash: "-", // Вход по паролю в тестах слоя хранения не нужен
+	}
+	user.Email = user.Username + "@example.com"
+	if err := e.DB.GetUserRepository().CreateUser(ctx, user); err != nil {
+		t.Fatalf("ошибка создания пользователя %s: %v", user.Username, err)
+	}
+
+	wallets := e.DB.GetWalletRepository()
+	for currency, amount := range funds {
+		if _, err := wallets.UpdateBalance(ctx, user.ID, currency, amount); err != nil {
+			t.Fatalf("ошибка пополнения %s %s: %v", user.Username, currency, err)
+		}
+	}
+	return user
+}
+
+// MakeAdmin назначает пользователю роль администратора
+func (e *Env) MakeAdmin(t testing.TB, user *models.User) {
+	t.Helper()
+	if err := e.DB.GetUserRepository().SetRole(context.Background(), user.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("ошибка назначения роли администратора: %v", err)
+	}
+	user.Role = models.RoleAdmin
+}
+
+// Balance возвращает текущий баланс пользователя
+func (e *Env) Balance(t testing.TB, user *models.User) *models.Balance {
+	t.Helper()
+	balance, err := e.DB.GetWalletRepository().GetBalance(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("ошибка получения баланса: %v", err)
+	}
+	return balance
+}
@@ -0,0 +1,185 @@
+//go:build integration
+
+// Package testenv поднимает реальные зависимости сервиса кошелька для интеграционных тестов:
+// PostgreSQL и Redis в контейнерах (testcontainers-go) и заглушку сервиса обмена в процессе теста
+//
+// Пакет собирается только с тегом integration и требует Docker; без доступного Docker
+// тест пропускается:
+//
+//	//go:build integration
+//
+//	func TestTransfer(t *testing.T) {
+//		env := testenv.New(t)
+//		alice := env.CreateUser(t, "alice", testenv.Funds{"USD": 100})
+//		...
+//	}
+//
+//	go test -tags integration ./...
+package testenv
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/storage/postgres"
+	"gw-currency-wallet/internal/storage/redis"
+	"net"
+	"testing"
+	"time"
+)
+
+// Образы зависимостей (совпадают с docker-compose)
+const (
+	postgresImage = "postgres:16-alpine"
+	redisImage    = "redis:7-alpine"
+)
+
+// Параметры тестовой базы данных
+// Имя БД совпадает с тем, что создает PostgresStorage при подключении
+const (
+	dbName     = "wallet_db"
+	dbUser     = "wallet"
+	dbPassword = "wallet"
+)
+
+// startTimeout - время на запуск контейнеров и применение миграций
+const startTimeout = 2 * time.Minute
+
+// Env - окружение интеграционного теста
+type Env struct {
+	DB        *postgres.PostgresStorage // Хранилище на PostgreSQL из контейнера с примененными миграциями
+	SQL       *sql.DB                   // Прямое подключение к той же БД для подготовки данных в обход хранилища
+	Cache     *redis.Cache              // Кэш на Redis из контейнера
+	Exchanger *ExchangerStub            // Заглушка сервиса обмена (курсы задаются тестом)
+	Rates     *services.ExchangeService // Клиент сервиса обмена, подключенный к заглушке
+}
+
+// New запускает зависимости и возвращает окружение теста
+// Контейнеры и соединения освобождаются по завершении теста (t.Cleanup)
+// Тест (или бенчмарк) пропускается, если Docker недоступен
+func New(t testing.TB) *Env {
+	t.Helper()
+	skipWithoutDocker(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	env := &Env{}
+	env.DB, env.SQL = startPostgres(ctx, t)
+	env.Cache = startRedis(ctx, t)
+	env.Exchanger = StartExchangerStub(t)
+
+	rates, err := services.NewExchangeService(env.Exchanger.Addr(), "", env.Cache, time.Minute)
+	if err != nil {
+		t.Fatalf("ошибка подключения к заглушке сервиса обмена: %v", err)
+	}
+	t.Cleanup(func() { rates.Close() })
+	env.Rates = rates
+
+	return env
+}
+
+// skipWithoutDocker пропускает тест, если Docker не запущен или недоступен
+// (аналог testcontainers.SkipIfProviderIsNotHealthy, который принимает только *testing.T)
+func skipWithoutDocker(t testing.TB) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Skipf("Docker недоступен, интеграционный тест пропущен: %v", r)
+		}
+	}()
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err == nil {
+		err = provider.Health(context.Background())
+	}
+	if err != nil {
+		t.Skipf("Docker недоступен, интеграционный тест пропущен: %v", err)
+	}
+}
+
+// startPostgres запускает PostgreSQL и подключает к нему хранилище (миграции применяются при подключении)
+// и отдельное прямое подключение
+func startPostgres(ctx context.Context, t testing.TB) (*postgres.PostgresStorage, *sql.DB) {
+	t.Helper()
+
+	container, err := tcpostgres.Run(ctx, postgresImage,
+		tcpostgres.WithDatabase(dbName),
+		tcpostgres.WithUsername(dbUser),
+		tcpostgres.WithPassword(dbPassword),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("ошибка запуска PostgreSQL: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("ошибка получения адреса PostgreSQL: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		t.Fatalf("ошибка получения порта PostgreSQL: %v", err)
+	}
+
+	// PostgresStorage проверяет существование БД через подключение из переменных окружения
+	t.Setenv("DB_HOST", host)
+	t.Setenv("DB_PORT", port.Port())
+	t.Setenv("DB_USER", dbUser)
+	t.Setenv("DB_PASSWORD", dbPassword)
+	t.Setenv("DB_NAME", dbName)
+
+	connString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		host, port.Port(), dbUser, dbPassword, dbName)
+	db, err := postgres.NewPostgresStorage(ctx, connString, postgres.Options{
+		QueryTimeout:     5 * time.Second,
+		MigrationTimeout: time.Minute,
+		MaxOpenConns:     10,
+		MaxIdleConns:     10,
+		ConnMaxLifetime:  5 * time.Minute,
+		AMLThresholds:    map[string]float64{"USD": 10000, "EUR": 10000, "RUB": 1000000},
+	})
+	if err != nil {
+		t.Fatalf("ошибка подключения к PostgreSQL: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	conn, err := sql.Open("postgres", connString)
+	if err != nil {
+		t.Fatalf("ошибка прямого подключения к PostgreSQL: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return db, conn
+}
+
+// startRedis запускает Redis и подключает к нему кэш
+func startRedis(ctx context.Context, t testing.TB) *redis.Cache {
+	t.Helper()
+
+	container, err := tcredis.Run(ctx, redisImage)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("ошибка запуска Redis: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("ошибка получения адреса Redis: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		t.Fatalf("ошибка получения порта Redis: %v", err)
+	}
+
+	client, err := redis.New(redis.Options{Addr: net.JoinHostPort(host, port.Port())})
+	if err != nil {
+		t.Fatalf("ошибка подключения к Redis: %v", err)
+	}
+	cache := redis.NewCache(client)
+	t.Cleanup(func() { cache.Close() })
+	return cache
+}
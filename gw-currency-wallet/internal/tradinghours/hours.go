@@ -0,0 +1,234 @@
+// Package tradinghours описывает окна исполнения обменов (торговые часы) по парам и валютам
+package tradinghours
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minutesPerDay - конец последнего окна дня (24:00)
+const minutesPerDay = 24 * 60
+
+// weekdays - сокращения дней недели в расписании
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// Window - окно исполнения: дни недели и интервал времени суток [Start, End) в минутах
+type Window struct {
+	Days  [7]bool // Дни недели (индекс - time.Weekday)
+	Start int     // Начало окна (минуты от полуночи)
+	End   int     // Конец окна, не включая (до 1440 - 24:00)
+}
+
+// contains сообщает, попадает ли местное время в окно
+func (w Window) contains(local time.Time) bool {
+	minute := local.Hour()*60 + local.Minute()
+	return w.Days[local.Weekday()] && minute >= w.Start && minute < w.End
+}
+
+// Schedule - окна исполнения обменов по парам валют и отдельным валютам
+// Для пары действуют окна пары (в любом направлении), а если их нет - окна обеих валют одновременно.
+// Пара без окон исполняется круглосуточно. Nil Schedule - без ограничений
+type Schedule struct {
+	loc        *time.Location      // Часовой пояс окон
+	pairs      map[string][]Window // Окна пар ("USD/RUB")
+	currencies map[string][]Window // Окна валют ("RUB" - все пары с валютой)
+}
+
+// Parse разбирает окна в формате "ключ=окно,окно;ключ=окно"
+// Ключ - пара валют (USD/RUB) или валюта (RUB - все пары с ней), окно - дни и время суток:
+// "mon-fri 10:00-18:00", "sat 10:00-14:00", "fri-mon 00:00-24:00"
+// (например "RUB=mon-fri 00:00-24:00;USD/EUR=mon-fri 08:00-20:00,sat 10:00-14:00")
+// Параметры:
+//   - raw: строка с окнами
+//   - loc: часовой пояс, в котором заданы окна
+//
+// Возвращает:
+//   - *Schedule: окна (nil, если строка пустая)
+//   - error: ошибка формата
+func Parse(raw string, loc *time.Location) (*Schedule, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	s := &Schedule{loc: loc, pairs: make(map[string][]Window), currencies: make(map[string][]Window)}
+	for _, item := range strings.Split(raw, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("правило %q: ожидается формат ключ=окна", item)
+		}
+		target := s.currencies
+		if from, to, isPair := strings.Cut(key, "/"); isPair {
+			if from == "" || to == "" || from == to {
+				return nil, fmt.Errorf("правило %q: некорректная пара валют", item)
+			}
+			target = s.pairs
+		}
+		if _, exists := target[key]; exists {
+			return nil, fmt.Errorf("окна %s заданы несколько раз", key)
+		}
+		for _, raw := range strings.Split(value, ",") {
+			window, err := parseWindow(raw)
+			if err != nil {
+				return nil, fmt.Errorf("правило %q: %w", item, err)
+			}
+			target[key] = append(target[key], window)
+		}
+	}
+	return s, nil
+}
+
+// parseWindow разбирает окно "дни ЧЧ:ММ-ЧЧ:ММ"
+func parseWindow(raw string) (Window, error) {
+	var w Window
+	raw = strings.TrimSpace(raw)
+	days, hours, ok := strings.Cut(raw, " ")
+	if !ok {
+		return w, fmt.Errorf("окно %q: ожидается формат \"дни ЧЧ:ММ-ЧЧ:ММ\"", raw)
+	}
+	if err := parseDays(strings.ToLower(days), &w.Days); err != nil {
+		return w, err
+	}
+	startRaw, endRaw, ok := strings.Cut(strings.TrimSpace(hours), "-")
+	if !ok {
+		return w, fmt.Errorf("окно %q: ожидается время ЧЧ:ММ-ЧЧ:ММ", raw)
+	}
+	var err error
+	if w.Start, err = parseClock(startRaw); err != nil {
+		return w, err
+	}
+	if w.End, err = parseClock(endRaw); err != nil {
+		return w, err
+	}
+	if w.End <= w.Start {
+		return w, fmt.Errorf("окно %q: конец должен быть позже начала (окно через полночь задается двумя окнами)", raw)
+	}
+	return w, nil
+}
+
+// parseDays разбирает дни недели: "mon", "mon-fri", "sat-sun", "fri-mon"
+func parseDays(raw string, days *[7]bool) error {
+	first, last, isRange := strings.Cut(raw, "-")
+	from, ok := weekdays[first]
+	if !ok {
+		return fmt.Errorf("неизвестный день недели %q (mon, tue, wed, thu, fri, sat, sun)", first)
+	}
+	to := from
+	if isRange {
+		if to, ok = weekdays[last]; !ok {
+			return fmt.Errorf("неизвестный день недели %q (mon, tue, wed, thu, fri, sat, sun)", last)
+		}
+	}
+	for day := from; ; day = (day + 1) % 7 {
+		days[day] = true
+		if day == to {
+			return nil
+		}
+	}
+}
+
+// parseClock разбирает время суток ЧЧ:ММ (24:00 - конец суток) в минуты от полуночи
+func parseClock(raw string) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(raw), ":")
+	hours, errH := strconv.Atoi(h)
+	minutes, errM := strconv.Atoi(m)
+	if !ok || errH != nil || errM != nil || hours < 0 || minutes < 0 || minutes > 59 ||
+		hours*60+minutes > minutesPerDay {
+		return 0, fmt.Errorf("некорректное время %q (ЧЧ:ММ от 00:00 до 24:00)", strings.TrimSpace(raw))
+	}
+	return hours*60 + minutes, nil
+}
+
+// Location возвращает часовой пояс окон
+func (s *Schedule) Location() *time.Location {
+	return s.locOrUTC()
+}
+
+// Restricted сообщает, ограничено ли исполнение обменов пары окнами
+func (s *Schedule) Restricted(from, to string) bool {
+	return len(s.rules(from, to)) > 0
+}
+
+// Open сообщает, можно ли исполнить обмен пары в момент t
+func (s *Schedule) Open(from, to string, t time.Time) bool {
+	local := t.In(s.locOrUTC())
+	for _, windows := range s.rules(from, to) {
+		if !slices.ContainsFunc(windows, func(w Window) bool { return w.contains(local) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextOpen возвращает ближайший момент не раньше t, когда обмен пары можно исполнить
+// Возвращает:
+//   - time.Time: t, если окно открыто, иначе начало ближайшего окна
+//   - bool: false, если окна пары не открываются в течение недели (например, окна валют пары не пересекаются)
+func (s *Schedule) NextOpen(from, to string, t time.Time) (time.Time, bool) {
+	if s.Open(from, to, t) {
+		return t, true
+	}
+	loc := s.locOrUTC()
+	local := t.In(loc)
+
+	// Кандидаты - начала окон всех правил пары на 8 дней вперед: пересечение окон начинается с начала одного из них
+	var candidates []time.Time
+	for offset := 0; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		for _, windows := range s.rules(from, to) {
+			for _, w := range windows {
+				if !w.Days[day.Weekday()] {
+					continue
+				}
+				start := time.Date(day.Year(), day.Month(), day.Day(), w.Start/60, w.Start%60, 0, 0, loc)
+				if start.After(t) {
+					candidates = append(candidates, start)
+				}
+			}
+		}
+	}
+	slices.SortFunc(candidates, func(a, b time.Time) int { return a.Compare(b) })
+	for _, candidate := range candidates {
+		if s.Open(from, to, candidate) {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// rules возвращает окна, которые должны быть открыты для обмена пары
+func (s *Schedule) rules(from, to string) [][]Window {
+	if s == nil {
+		return nil
+	}
+	if windows, ok := s.pairs[from+"/"+to]; ok {
+		return [][]Window{windows}
+	}
+	if windows, ok := s.pairs[to+"/"+from]; ok {
+		return [][]Window{windows}
+	}
+	var rules [][]Window
+	for _, currency := range []string{from, to} {
+		if windows, ok := s.currencies[currency]; ok {
+			rules = append(rules, windows)
+		}
+	}
+	return rules
+}
+
+// locOrUTC возвращает часовой пояс окон (UTC, если не задан)
+func (s *Schedule) locOrUTC() *time.Location {
+	if s == nil || s.loc == nil {
+		return time.UTC
+	}
+	return s.loc
+}
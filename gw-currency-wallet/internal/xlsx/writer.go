@@ -0,0 +1,254 @@
+// Package xlsx записывает таблицу с одним листом в формате Office Open XML (XLSX)
+// Строки пишутся в архив по мере поступления, без накопления листа в памяти
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Style - оформление ячейки (индекс в таблице стилей книги)
+type Style int
+
+// Стили ячеек
+const (
+	StyleDefault     Style = iota // Без оформления
+	StyleHeader                   // Заголовок: полужирный на сером фоне
+	StyleAmount                   // Сумма: разделители разрядов, 2 знака
+	StyleRate                     // Курс: 6 знаков
+	StyleDateTime                 // Дата и время
+	StyleTotal                    // Подпись итогов: полужирный с линией сверху
+	StyleTotalAmount              // Сумма итогов: как StyleAmount, полужирный с линией сверху
+)
+
+// Cell - значение ячейки: string, float64, int, int64, time.Time или nil (пустая ячейка)
+// Для ячейки с формулой Value - значение, вычисленное заранее (показывается до пересчета книги)
+type Cell struct {
+	Value   any    // Значение
+	Style   Style  // Оформление
+	Formula string // Формула без знака "=" (например, SUM(B2:B10))
+}
+
+// excelEpoch - начало отсчета дат Excel (с поправкой на несуществующее 29.02.1900)
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// Writer записывает лист XLSX построчно
+type Writer struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+	closed  bool
+}
+
+// NewWriter начинает книгу с одним листом
+// Параметры:
+//   - w: получатель файла
+//   - sheetName: название листа (до 31 символа)
+//   - widths: ширина столбцов в символах (0 - по умолчанию)
+//   - freezeHeader: закрепить первую строку при прокрутке
+//
+// Возвращает:
+//   - *Writer: запись строк листа (после последней строки вызывается Close)
+//   - error: ошибка записи
+func NewWriter(w io.Writer, sheetName string, widths []float64, freezeHeader bool) (*Writer, error) {
+	archive := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, escape(sheetName))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/styles.xml", stylesXML},
+	}
+	for _, part := range parts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, xml.Header+part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(entry)
+	sheet.WriteString(xml.Header)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if freezeHeader {
+		sheet.WriteString(`<sheetViews><sheetView workbookViewId="0">` +
+			`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+	if len(widths) > 0 {
+		sheet.WriteString("<cols>")
+		for i, width := range widths {
+			if width > 0 {
+				fmt.Fprintf(sheet, `<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, width)
+			}
+		}
+		sheet.WriteString("</cols>")
+	}
+	sheet.WriteString("<sheetData>")
+	return &Writer{archive: archive, sheet: sheet}, nil
+}
+
+// Rows возвращает количество записанных строк
+func (w *Writer) Rows() int {
+	return w.rows
+}
+
+// WriteRow записывает следующую строку листа
+func (w *Writer) WriteRow(cells ...Cell) error {
+	if w.closed {
+		return errors.New("xlsx: запись после Close")
+	}
+	w.rows++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for i, cell := range cells {
+		if cell.Value == nil && cell.Formula == "" && cell.Style == StyleDefault {
+			continue
+		}
+		if err := w.writeCell(CellName(i, w.rows), cell); err != nil {
+			return err
+		}
+	}
+	_, err := w.sheet.WriteString("</row>")
+	return err
+}
+
+// writeCell записывает ячейку с адресом ref
+func (w *Writer) writeCell(ref string, cell Cell) error {
+	fmt.Fprintf(w.sheet, `<c r="%s" s="%d"`, ref, cell.Style)
+	var value string
+	switch v := cell.Value.(type) {
+	case nil:
+	case string:
+		if cell.Formula != "" {
+			w.sheet.WriteString(` t="str"`)
+			value = escape(v)
+			break
+		}
+		_, err := fmt.Fprintf(w.sheet, ` t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, escape(v))
+		return err
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		value = strconv.Itoa(v)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case time.Time:
+		value = strconv.FormatFloat(serialDate(v), 'f', -1, 64)
+	default:
+		return fmt.Errorf("xlsx: неподдерживаемый тип значения %T", cell.Value)
+	}
+	w.sheet.WriteString(">")
+	if cell.Formula != "" {
+		fmt.Fprintf(w.sheet, "<f>%s</f>", escape(cell.Formula))
+	}
+	if value != "" {
+		fmt.Fprintf(w.sheet, "<v>%s</v>", value)
+	}
+	_, err := w.sheet.WriteString("</c>")
+	return err
+}
+
+// Close завершает лист и архив; без вызова Close файл поврежден
+// Параметры:
+//   - autoFilter: диапазон с фильтром по первой строке (например, A1:H10; пусто - без фильтра)
+func (w *Writer) Close(autoFilter string) error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	w.sheet.WriteString("</sheetData>")
+	if autoFilter != "" {
+		fmt.Fprintf(w.sheet, `<autoFilter ref="%s"/>`, escape(autoFilter))
+	}
+	w.sheet.WriteString("</worksheet>")
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// CellName возвращает адрес ячейки по номеру столбца (с 0) и строки (с 1): CellName(0, 1) = "A1"
+func CellName(column, row int) string {
+	return ColumnName(column) + strconv.Itoa(row)
+}
+
+// ColumnName возвращает буквенное обозначение столбца по номеру (с 0): 0 - A, 26 - AA
+func ColumnName(column int) string {
+	var name []byte
+	for column++; column > 0; column = (column - 1) / 26 {
+		name = append([]byte{byte('A' + (column-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// serialDate переводит время в дату Excel (дни от начала отсчета); время записывается в своем часовом поясе
+func serialDate(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
+
+// escape экранирует текст для XML
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const contentTypesXML = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const rootRelsXML = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbookXML = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const workbookRelsXML = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// stylesXML - таблица стилей; порядок cellXfs соответствует константам Style
+const stylesXML = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="3">` +
+	`<numFmt numFmtId="164" formatCode="#,##0.00"/>` +
+	`<numFmt numFmtId="165" formatCode="0.000000"/>` +
+	`<numFmt numFmtId="166" formatCode="yyyy-mm-dd hh:mm:ss"/>` +
+	`</numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFD9D9D9"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="2"><border><left/><right/><top/><bottom/><diagonal/></border>` +
+	`<border><left/><right/><top style="thin"><color auto="1"/></top><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="7">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="0" xfId="0" applyFont="1" applyFill="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="1" xfId="0" applyFont="1" applyBorder="1"/>` +
+	`<xf numFmtId="164" fontId="1" fillId="0" borderId="1" xfId="0" applyNumberFormat="1" applyFont="1" applyBorder="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -0,0 +1,174 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/handlers"
+	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
+	"net"
+)
+
+// registerAPI регистрирует маршруты, общие для версий API, в группе версии (/api/v1, /api/v2)
+// Маршрут, контракт которого меняется в новой версии, переносится из общего набора
+// в функцию регистрации версии с обработчиком из пакета этой версии (internal/handlers/v2)
+// Параметры:
+//   - api: группа маршрутов версии
+//   - svc: сервисы приложения
+//   - jwtOpts: секретный ключ и допуск расхождения часов для проверки JWT-токенов
+//   - adminNetworks: подсети, из которых доступны маршруты /admin (пусто - без ограничения)
+func registerAPI(api *gin.RouterGroup, svc Services, jwtOpts middleware.JWTOptions, adminNetworks []*net.IPNet) {
+	// Группа публичных маршрутов (не требуют аутентификации)
+	// Вход и регистрация ограничены по IP адресу клиента (защита от подбора паролей)
+	public := api.Group("")
+	if svc.BruteForce.Enabled() {
+		public.Use(middleware.BruteForce(svc.BruteForce))
+	}
+	{
+		public.POST("/register", middleware.RequireCaptcha(svc.Captcha), handlers.Register(svc.Auth))                   // Регистрация нового пользователя
+		public.POST("/login", middleware.LoginCaptcha(svc.Captcha), handlers.Login(svc.Auth, svc.Device, svc.Sessions)) // Аутентификация пользователя
+		public.POST("/login/confirm", handlers.ConfirmLogin(svc.Device, svc.Sessions))                                  // Подтверждение входа с нового устройства
+	}
+
+	// Обновление JWT токена (токен обновления случаен, ограничение попыток по IP не требуется)
+	api.POST("/token/refresh", handlers.RefreshToken(svc.Sessions))
+
+	// Webhook платежного провайдера (аутентификация подписью запроса)
+	if svc.PaymentWebhook.Enabled() {
+		api.POST("/webhooks/payments", handlers.PaymentWebhook(svc.PaymentWebhook)) // Зачисление платежей
+	}
+
+	// Публичная проверка квитанций об операциях (аутентификация подписанным кодом)
+	if svc.Receipts.Enabled() {
+		api.GET("/receipts/:code", handlers.VerifyReceipt(svc.Receipts))
+	}
+
+	// Группа защищенных маршрутов (требуют JWT-аутентификации)
+	protected := api.Group("")
+	protected.Use(middleware.JWTAuthMiddleware(jwtOpts)) // Подключаем middleware для проверки JWT
+	if svc.Quota.Enabled() {
+		protected.Use(middleware.Quota(svc.Quota)) // Квота запросов по тарифному плану
+	}
+	{
+		// Операции с кошельком
+		protected.GET("/balance", handlers.GetBalance(svc.Wallet, svc.Preferences))                                      // Получение текущего баланса
+		protected.GET("/balance/total", handlers.GetBalanceTotal(svc.Wallet, svc.Preferences))                           // Стоимость всех балансов в одной валюте
+		protected.GET("/portfolio", handlers.GetPortfolio(svc.Portfolio, svc.Preferences))                               // Средний курс приобретения и нереализованный доход
+		protected.POST("/wallet/deposit", middleware.MoneyOperation("deposit"), handlers.Deposit(svc.Wallet, svc.Promo)) // Пополнение кошелька (с промокодом - с бонусом)
+		protected.POST("/wallet/withdraw", middleware.MoneyOperation("withdraw"), handlers.Withdraw(svc.Wallet))         // Снятие средств с кошелька
+		protected.POST("/wallet/transfer", middleware.MoneyOperation("transfer"), handlers.Transfer(svc.Wallet))         // Перевод другому пользователю
+		protected.GET("/transactions", handlers.GetTransactions(svc.History, svc.Preferences))                           // История операций за период
+		protected.GET("/transactions/export", handlers.ExportTransactions(svc.History))                                  // Выгрузка истории операций (csv, xlsx, jsonl)
+		protected.POST("/promo/redeem", handlers.RedeemPromo(svc.Promo))                                                 // Активация промокода
+		protected.GET("/preferences", handlers.GetPreferences(svc.Preferences))                                          // Настройки пользователя
+		protected.PUT("/preferences", handlers.UpdatePreferences(svc.Preferences))                                       // Изменение настроек
+		protected.GET("/devices", handlers.ListDevices(svc.Device))                                                      // Устройства, с которых выполнялся вход
+		protected.GET("/activity", handlers.GetActivity(svc.Activity))                                                   // Лента активности (входы, изменения, операции)
+		protected.POST("/telegram/link", handlers.CreateTelegramLink(svc.TelegramLink))                                  // Код привязки аккаунта Telegram (обмен из бота)
+		protected.DELETE("/telegram/link", handlers.DeleteTelegramLink(svc.TelegramLink))                                // Отвязка аккаунта Telegram
+		protected.GET("/notifications", handlers.GetNotifications(svc.Notifications))                                    // Уведомления об операциях и состояние доставки
+		protected.GET("/notifications/routes", handlers.GetNotificationRoutes(svc.Notifications))                        // Правила доставки уведомлений
+		protected.PUT("/notifications/routes/:channel", handlers.SaveNotificationRoute(svc.Notifications))               // Правило для канала
+		protected.DELETE("/notifications/routes/:channel", handlers.DeleteNotificationRoute(svc.Notifications))          // Удаление правила
+		protected.POST("/push/tokens", handlers.RegisterPushToken(svc.Push))                                             // Регистрация устройства для push уведомлений
+		protected.GET("/push/tokens", handlers.ListPushTokens(svc.Push))                                                 // Устройства для push уведомлений
+		protected.DELETE("/push/tokens/:id", handlers.DeletePushToken(svc.Push))                                         // Отключение push уведомлений устройства
+
+		// Операции с обменом валют
+		protected.GET("/exchange/rates", handlers.GetExchangeRates(svc.Exchange))                                                    // Получение текущих курсов валют
+		protected.GET("/exchange/chart", handlers.GetExchangeChart(svc.Exchange))                                                    // Свечи курса пары валют для графика
+		protected.GET("/exchange/history", handlers.GetExchangeHistory(svc.History))                                                 // История обменов с курсом и комиссией
+		protected.GET("/exchange/queued", handlers.GetQueuedExchanges(svc.ExchangeQueue))                                            // Обмены, отложенные до открытия окна исполнения или публикации курса
+		protected.DELETE("/exchange/queued/:id", handlers.CancelQueuedExchange(svc.ExchangeQueue))                                   // Отмена обмена в очереди
+		protected.POST("/exchange", middleware.MoneyOperation("exchange"), handlers.ExchangeCurrency(svc.Wallet, svc.ExchangeQueue)) // Обмен одной валюты на другую
+		protected.GET("/loyalty", handlers.GetLoyaltyStatus(svc.Loyalty))                                                            // Уровень лояльности и комиссия обмена
+
+		// Планы регулярных обменов
+		protected.POST("/exchange/dca", handlers.CreateDCAPlan(svc.DCA))         // Создание плана
+		protected.GET("/exchange/dca", handlers.ListDCAPlans(svc.DCA))           // Планы пользователя
+		protected.GET("/exchange/dca/:id", handlers.GetDCAPlanProgress(svc.DCA)) // Ход выполнения плана
+		protected.DELETE("/exchange/dca/:id", handlers.CancelDCAPlan(svc.DCA))   // Отмена плана
+
+		// Квитанции об операциях
+		if svc.Receipts.Enabled() {
+			protected.GET("/transactions/:id/receipt", handlers.GetReceipt(svc.Receipts)) // Квитанция в JSON или PDF
+		}
+
+		// Отчеты
+		protected.GET("/reports/tax", handlers.ExportTaxReport(svc.Tax)) // Курсовые доходы за год (CSV)
+
+		// Верификация пользователя
+		protected.GET("/kyc", handlers.GetKYCStatus(svc.KYC))                 // Статус верификации
+		protected.POST("/kyc/documents", handlers.SubmitKYCDocument(svc.KYC)) // Подача документа
+	}
+
+	// Квота запросов (проверка использования квоту не расходует)
+	api.GET("/quota", middleware.JWTAuthMiddleware(jwtOpts), handlers.GetQuota(svc.Quota))
+
+	// Группа маршрутов администратора (разрешенные подсети, JWT и роль admin)
+	admin := api.Group("/admin")
+	admin.Use(
+		middleware.AllowNetworks(adminNetworks),
+		middleware.JWTAuthMiddleware(jwtOpts),
+		middleware.RequireRole(models.RoleAdmin),
+	)
+	{
+		// Сверка балансов с журналом операций
+		admin.POST("/reconciliation", handlers.RunReconciliation(svc.Reconciliation))            // Запуск сверки
+		admin.GET("/reconciliation", handlers.GetReconciliationReport(svc.Reconciliation))       // Последний отчет
+		admin.DELETE("/wallets/:user_id/quarantine", handlers.ReleaseWallet(svc.Reconciliation)) // Снятие блокировки
+		admin.GET("/balances", handlers.ListBalances(svc.Wallet))                                // Балансы нескольких пользователей
+
+		// Очередь проверки антифрода
+		admin.GET("/reviews", handlers.ListReviews(svc.Wallet))                // Отложенные операции
+		admin.POST("/reviews/:id/resolve", handlers.ResolveReview(svc.Wallet)) // Одобрение или отклонение
+
+		// Проверка заявок на верификацию
+		admin.GET("/kyc", handlers.ListKYCApplicants(svc.KYC))                  // Пользователи по статусу верификации
+		admin.GET("/kyc/:user_id/documents", handlers.GetKYCDocuments(svc.KYC)) // Документы пользователя
+		admin.POST("/kyc/:user_id/resolve", handlers.ResolveKYC(svc.KYC))       // Верификация или отказ
+
+		// Отчетность о крупных операциях
+		admin.GET("/aml/report", handlers.ExportAMLReport(svc.Compliance)) // Выгрузка отчета AML в CSV
+
+		// Корректировки баланса (зачисление и списание с обоснованием)
+		admin.POST("/adjustments", handlers.CreateAdjustment(svc.Adjustment))              // Новая корректировка
+		admin.GET("/adjustments", handlers.ListAdjustments(svc.Adjustment))                // Список корректировок
+		admin.POST("/adjustments/:id/approve", handlers.ApproveAdjustment(svc.Adjustment)) // Подтверждение
+		admin.POST("/adjustments/:id/reject", handlers.RejectAdjustment(svc.Adjustment))   // Отклонение
+		admin.GET("/audit", handlers.GetAuditLog(svc.Adjustment))                          // Журнал действий администраторов
+
+		// Промокоды
+		admin.POST("/promos", handlers.CreatePromo(svc.Promo))             // Создание промокода
+		admin.GET("/promos", handlers.ListPromos(svc.Promo))               // Список промокодов
+		admin.DELETE("/promos/:code", handlers.DeactivatePromo(svc.Promo)) // Отключение промокода
+
+		// Блокировки IP адресов за подбор паролей
+		admin.GET("/bans", handlers.ListIPBans(svc.BruteForce))     // Действующие блокировки
+		admin.DELETE("/bans/:ip", handlers.UnbanIP(svc.BruteForce)) // Снятие блокировки
+
+		// Пользователи
+		admin.GET("/users", handlers.SearchUsers(svc.Users)) // Поиск с фильтрами, сортировкой и страницами
+
+		// Тарифные планы пользователей (квоты запросов)
+		admin.PUT("/users/:user_id/plan", handlers.SetUserPlan(svc.Quota)) // Назначение плана
+
+		// Вывод экземпляра из балансировки перед остановкой (rolling deploy)
+		admin.POST("/drain", handlers.StartDrain(svc.Drain))    // Начать вывод
+		admin.GET("/drain", handlers.GetDrainStatus(svc.Drain)) // Состояние вывода
+
+		// Недоставленные уведомления об операциях (dead letter)
+		admin.GET("/notifications/dead-letters", handlers.ListDeadLetters(svc.Notifications))     // Список
+		admin.POST("/notifications/:id/requeue", handlers.RequeueNotification(svc.Notifications)) // Повторная доставка
+
+		// Сброс кэша курсов, балансов и квот (после ручной установки курса или исправления данных)
+		admin.POST("/cache/flush", handlers.FlushCache(svc.Cache))
+
+		// Приостановка операций в отдельных валютах (например, при резких колебаниях курса)
+		admin.GET("/currencies/freezes", handlers.ListCurrencyFreezes(svc.Freezes))          // Действующие приостановки
+		admin.PUT("/currencies/:currency/freeze", handlers.FreezeCurrency(svc.Freezes))      // Приостановка
+		admin.DELETE("/currencies/:currency/freeze", handlers.UnfreezeCurrency(svc.Freezes)) // Возобновление
+
+		// Действующая конфигурация экземпляра (секреты скрыты) и состояние функций
+		admin.GET("/config", handlers.GetEffectiveConfig(svc.Config))
+	}
+}
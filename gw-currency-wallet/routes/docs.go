@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"gw-currency-wallet/docs"
+	"net/http"
+)
+
+// registerDocs публикует документацию версии API в ее группе маршрутов:
+//   - GET <версия>/openapi.json - спецификация OpenAPI, сгенерированная из аннотаций обработчиков
+//   - GET <версия>/docs/index.html - Swagger UI для этой спецификации
+//
+// Версии пока отличаются только префиксом, поэтому спецификация общая, а basePath и номер версии
+// подставляются для каждой группы. Host не указывается: Swagger UI отправляет запросы на адрес,
+// с которого открыта документация
+// Параметры:
+//   - api: группа маршрутов версии (/api/v1, /api/v2)
+//   - version: номер версии для поля info.version
+func registerDocs(api *gin.RouterGroup, version string) {
+	spec := *docs.SwaggerInfo
+	spec.BasePath = api.BasePath()
+	spec.Host = ""
+	spec.Version = version
+	doc := []byte(spec.ReadDoc())
+
+	api.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", doc)
+	})
+	api.GET("/docs/*any", ginSwagger.WrapHandler(
+		swaggerFiles.Handler,
+		ginSwagger.URL(api.BasePath()+"/openapi.json"), // Спецификация этой версии
+		ginSwagger.DefaultModelsExpandDepth(-1),        // Отключаем отображение моделей в Swagger UI
+		ginSwagger.PersistAuthorization(true),          // Сохраняем авторизацию между перезагрузками страницы
+	))
+}
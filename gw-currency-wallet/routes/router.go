@@ -4,55 +4,109 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gw-currency-wallet/internal/drain"
 	"gw-currency-wallet/internal/handlers"
 	"gw-currency-wallet/internal/middleware"
+	"gw-currency-wallet/internal/models"
 	"gw-currency-wallet/internal/services"
+	"gw-currency-wallet/internal/tenant"
+	"log"
+	"net"
+	"time"
 )
 
+// Services объединяет сервисы, используемые обработчиками HTTP-маршрутов
+type Services struct {
+	Auth           *services.AuthService           // Аутентификация и регистрация пользователей
+	Sessions       *services.SessionService        // Токены обновления сессий
+	Wallet         *services.WalletService         // Операции с кошельком (баланс, депозит, снятие, обмен)
+	Exchange       *services.ExchangeService       // Курсы валют
+	History        *services.HistoryService        // История операций
+	Reconciliation *services.ReconciliationService // Сверка балансов с журналом (администрирование)
+	KYC            *services.KYCService            // Верификация пользователей
+	Compliance     *services.ComplianceService     // Отчетность о крупных операциях (AML)
+	Adjustment     *services.AdjustmentService     // Корректировки баланса администраторами и журнал их действий
+	Promo          *services.PromoService          // Промокоды
+	Loyalty        *services.LoyaltyService        // Уровни лояльности и комиссия обмена
+	Preferences    *services.PreferencesService    // Настройки пользователя (валюта, локаль, часовой пояс)
+	BruteForce     *services.BruteForceService     // Защита входа и регистрации от подбора паролей и блокировки адресов
+	Captcha        *services.CaptchaService        // Проверка CAPTCHA при регистрации и входе
+	Device         *services.DeviceService         // Устройства пользователей и подтверждение входа с новых устройств
+	PaymentWebhook *services.PaymentWebhookService // Прием событий платежного провайдера
+	Activity       *services.ActivityService       // Лента активности пользователя
+	Users          *services.UserService           // Поиск пользователей администраторами
+	Tax            *services.TaxService            // Налоговый отчет о курсовых доходах
+	Portfolio      *services.PortfolioService      // Валютные позиции и нереализованный доход
+	Quota          *services.QuotaService          // Квоты запросов по тарифным планам
+	TelegramLink   *services.TelegramLinkService   // Привязка аккаунтов Telegram (обмен из бота)
+	Notifications  *services.NotificationService   // Уведомления об операциях: правила доставки и dead letter
+	Push           *services.PushService           // Токены устройств для push уведомлений
+	Cache          *services.CacheService          // Сброс групп записей кэша (администрирование)
+	Freezes        *services.CurrencyFreezeService // Приостановка операций в валютах (администрирование)
+	ExchangeQueue  *services.ExchangeQueueService  // Обмены, отложенные до открытия окна исполнения или публикации курса
+	DCA            *services.DCAService            // Планы регулярных обменов
+	Receipts       *services.ReceiptService        // Квитанции об операциях с кодом проверки
+	Tenants        *tenant.Registry                // Арендаторы (бренды) установки
+	Config         *models.EffectiveConfig         // Действующая конфигурация без секретов (администрирование)
+	Drain          *drain.Drainer                  // Вывод экземпляра из балансировки перед остановкой
+}
+
 // SetupRouter создает и настраивает маршруты для HTTP-сервера с использованием Gin.
 // Параметры:
-//   - authService: сервис для аутентификации и регистрации пользователей
-//   - walletService: сервис для операций с кошельком (баланс, депозит, снятие)
-//   - exchangeService: сервис для работы с курсами валют
-//   - jwtSecret: секретный ключ для подписи JWT-токенов
+//   - svc: сервисы приложения
+//   - jwtOpts: секретный ключ и допуск расхождения часов для проверки JWT-токенов
+//   - v1Sunset: дата отключения /api/v1 для заголовка Sunset (нулевое значение - дата не объявлена)
+//   - requestLog: параметры журнала запросов и ответов (nil - журнал выключен)
+//   - timeout: таймаут обработки запроса по умолчанию (0 - без ограничения)
+//   - routeTimeouts: таймауты отдельных маршрутов ("МЕТОД /путь" без префикса версии)
+//   - trustedProxies: адреса и подсети прокси, которым доверяется X-Forwarded-For (пусто - IP соединения)
+//   - adminNetworks: подсети, из которых доступны маршруты /admin (пусто - без ограничения)
 //
 // Возвращает:
 //   - *gin.Engine: настроенный роутер Gin
 func SetupRouter(
-	authService *services.AuthService,
-	walletService *services.WalletService,
-	exchangeService *services.ExchangeService,
-	jwtSecret string,
+	svc Services,
+	jwtOpts middleware.JWTOptions,
+	v1Sunset time.Time,
+	requestLog *middleware.RequestLogOptions,
+	timeout time.Duration,
+	routeTimeouts map[string]time.Duration,
+	trustedProxies []string,
+	adminNetworks []*net.IPNet,
 ) *gin.Engine {
-	router := gin.Default() // Создаем экземпляр Gin с дефолтными middleware (логгирование, восстановление после паники)
+	router := gin.New()
+	// IP адрес клиента (защита от подбора паролей, журнал) берется из X-Forwarded-For только от доверенных прокси
+	// Адреса проверены при валидации конфигурации
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Printf("Ошибка настройки доверенных прокси: %v", err)
+	}
+	router.Use(
+		middleware.AccessLog(),         // Журнал запросов Gin с идентификатором запроса
+		middleware.Tracing(),           // Span OpenTelemetry на запрос (продолжает трассировку из traceparent)
+		middleware.TraceID(),           // Идентификатор запроса для поиска в журнале
+		middleware.InFlight(svc.Drain), // Учет выполняющихся запросов для вывода из балансировки
+		middleware.Recovery(),          // Паника обработчика - ответ 500 в JSON с идентификатором запроса
+		middleware.Tenant(svc.Tenants), // Арендатор запроса по заголовку X-Tenant-ID или хосту
+	)
+	if requestLog != nil {
+		router.Use(middleware.RequestLog(*requestLog)) // Журнал запросов и ответов с маскированием персональных данных
+	}
+	router.Use(middleware.Timeout(timeout, routeTimeouts)) // Ответ 504 в JSON при превышении времени обработки
+
+	// Проверки для оркестратора и балансировщика (вне версий API)
+	router.GET("/healthz", handlers.Healthz())                      // Процесс работает
+	router.GET("/readyz", handlers.Readyz(svc.Drain, svc.Exchange)) // Экземпляр принимает запросы (503 при выводе из балансировки, degraded без сервиса обмена)
 
-	// Настройка Swagger UI
+	// Настройка Swagger UI (документация /api/v1, документация каждой версии - в <версия>/docs)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(
 		swaggerFiles.Handler,
 		ginSwagger.DefaultModelsExpandDepth(-1), // Отключаем отображение моделей в Swagger UI
 		ginSwagger.PersistAuthorization(true),   // Сохраняем авторизацию между перезагрузками страницы
 	))
 
-	// Группа публичных маршрутов (не требуют аутентификации)
-	public := router.Group("/api/v1")
-	{
-		public.POST("/register", handlers.Register(authService)) // Регистрация нового пользователя
-		public.POST("/login", handlers.Login(authService))       // Аутентификация пользователя
-	}
-
-	// Группа защищенных маршрутов (требуют JWT-аутентификации)
-	protected := router.Group("/api/v1")
-	protected.Use(middleware.JWTAuthMiddleware(jwtSecret)) // Подключаем middleware для проверки JWT
-	{
-		// Операции с кошельком
-		protected.GET("/balance", handlers.GetBalance(walletService))        // Получение текущего баланса
-		protected.POST("/wallet/deposit", handlers.Deposit(walletService))   // Пополнение кошелька
-		protected.POST("/wallet/withdraw", handlers.Withdraw(walletService)) // Снятие средств с кошелька
-
-		// Операции с обменом валют
-		protected.GET("/exchange/rates", handlers.GetExchangeRates(exchangeService)) // Получение текущих курсов валют
-		protected.POST("/exchange", handlers.ExchangeCurrency(walletService))        // Обмен одной валюты на другую
-	}
+	// Версии API: v1 помечена устаревшей, новые клиенты используют v2
+	registerV1(router, svc, jwtOpts, adminNetworks, v1Sunset)
+	registerV2(router, svc, jwtOpts, adminNetworks)
 
 	return router
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"net"
+	"time"
+)
+
+// registerV1 регистрирует маршруты /api/v1
+// Версия устарела: каждый ответ содержит заголовки Deprecation, Sunset (если дата объявлена)
+// и ссылку на /api/v2, чтобы клиенты успели перейти до отключения
+func registerV1(router *gin.Engine, svc Services, jwtOpts middleware.JWTOptions, adminNetworks []*net.IPNet, sunset time.Time) {
+	api := router.Group("/api/v1", middleware.Deprecation(sunset, "/api/v2"))
+	registerAPI(api, svc, jwtOpts, adminNetworks)
+	registerDocs(api, "1.0")
+}
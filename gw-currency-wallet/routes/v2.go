@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gw-currency-wallet/internal/middleware"
+	"net"
+)
+
+// registerV2 регистрирует маршруты /api/v2
+// Несовместимые изменения контракта выпускаются только в этой версии:
+// обработчики берутся из internal/handlers/v2, остальные маршруты совпадают с v1
+func registerV2(router *gin.Engine, svc Services, jwtOpts middleware.JWTOptions, adminNetworks []*net.IPNet) {
+	api := router.Group("/api/v2")
+	registerAPI(api, svc, jwtOpts, adminNetworks)
+	registerDocs(api, "2.0")
+}
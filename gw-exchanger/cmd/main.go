@@ -4,60 +4,181 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"github.com/joho/godotenv"               // Для загрузки переменных окружения
+	"gw-exchanger/internal/alert"            // Оповещения дежурных
+	"gw-exchanger/internal/api"              // Поставщики курсов валют
+	"gw-exchanger/internal/config"           // Конфигурация сервиса
+	"gw-exchanger/internal/metrics"          // Метрики Prometheus
 	"gw-exchanger/internal/server"           // Пакет с логикой сервера
+	"gw-exchanger/internal/staleness"        // Отслеживание устаревания курсов
+	"gw-exchanger/internal/status"           // Страница состояния
+	storages "gw-exchanger/internal/storage" // Интерфейсы хранилища и параметры фонового обновления
 	"gw-exchanger/internal/storage/postgres" // Работа с PostgreSQL
+	"gw-exchanger/internal/storage/sqlstore" // Работа с MySQL и SQLite
+	"gw-exchanger/internal/updater"          // Обновление курсов от поставщиков
 	"gw-exchanger/internal/utils"            // Вспомогательные утилиты
 	"log"
 	"os"
-	"strconv"
+	"os/signal"
+	"sort"
+	"syscall"
 	"time"
+	_ "time/tzdata" // База часовых поясов для SNAPSHOT_TIMEZONE (в образе может отсутствовать)
 )
 
 func main() {
 	// 1. Загрузка конфигурации из файла .env
-	if err := godotenv.Load("config.env"); err != nil {
-		log.Fatal("Ошибка загрузки файла config2.env") // Критическая ошибка - завершаем программу
+	cfg, err := config.LoadConfig("config.env")
+	if err != nil {
+		log.Fatalf("Ошибка загрузки конфигурации: %v", err) // Критическая ошибка - завершаем программу
 	}
 
+	// Корневой контекст: отменяется по сигналу завершения и останавливает
+	// фоновое обновление курсов, незавершенные запросы к БД и gRPC сервер
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// 2. Получение параметров для обновления курсов валют
-	apiURL := os.Getenv("CB_API_URL") // URL API Центробанка
-	updateInterval := time.Minute * time.Duration(
-		getEnvAsInt("UPDATE_INTERVAL_MINUTES", 60)) // Интервал обновления (по умолчанию 60 минут)
-
-	// 3. Формирование строки подключения к PostgreSQL
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		os.Getenv("DB_HOST"),     // Хост базы данных
-		os.Getenv("DB_PORT"),     // Порт (обычно 5432)
-		os.Getenv("DB_USER"),     // Имя пользователя
-		os.Getenv("DB_PASSWORD"), // Пароль
-		os.Getenv("DB_NAME"),     // Имя базы данных
-	)
-
-	// 4. Проверка подключения к базе данных
-	if err := checkDBConnection(connStr); err != nil {
-		log.Fatalf("Ошибка подключения к базе данных: %v", err) // Критическая ошибка
+	// Запросы ко всем поставщикам выполняет один HTTP клиент с таймаутами, ограничением ответа и прокси
+	httpClient, err := api.NewHTTPClient(api.HTTPOptions{
+		Timeout:        cfg.ProviderHTTPTimeout,
+		ConnectTimeout: cfg.ProviderConnTimeout,
+		MaxBodySize:    cfg.ProviderMaxBodySize,
+		ProxyURL:       cfg.ProviderProxyURL,
+	})
+	if err != nil {
+		log.Fatalf("Ошибка настройки HTTP клиента поставщиков: %v", err)
+	}
+	providers := rateProviders(cfg.RateProviders, httpClient) // Поставщики курсов (по умолчанию - API Центробанка)
+	schedule := updater.RateSchedule{
+		Interval:  cfg.UpdateInterval, // Интервал обновления (по умолчанию 60 минут)
+		Intervals: cfg.CurrencyIntervals,
+		Allowlist: cfg.CurrencyAllowlist,
+	}
+	aggregation := api.AggregateOptions{
+		Method:       cfg.RateAggregation,
+		Weights:      cfg.ProviderWeights,
+		MaxDeviation: cfg.RateMaxDeviation,
+	}
+	if err := aggregation.Validate(); err != nil {
+		log.Fatalf("Ошибка настройки агрегации курсов: %v", err)
 	}
 
-	// 5. Инициализация хранилища данных с поддержкой периодического обновления
-	storage, err := postgres.NewPostgresStorage(connStr, apiURL, updateInterval)
+	// 3-5. Подключение к хранилищу курсов (STORAGE_DRIVER) и применение миграций
+	storage, err := openStorage(ctx, cfg)
 	if err != nil {
 		log.Fatalf("Ошибка инициализации хранилища: %v", err) // Критическая ошибка
 	}
 	defer storage.Close() // Гарантированное закрытие подключения при завершении
 
-	// 6. Первоначальное обновление курсов валют
-	if err := storage.UpdateRatesFromCB(); err != nil {
-		log.Printf("Ошибка первоначального обновления курсов: %v", err) // Не критическая ошибка
+	// Фоновое обновление курсов от поставщиков (останавливается при отмене ctx)
+	// Первое обновление выполняется сразу после UPDATER_INITIAL_DELAY
+	rates := updater.New(storage, providers, updater.Options{
+		Aggregation: aggregation,
+		Schedule:    schedule,
+		Updater: storages.UpdaterConfig{
+			Enabled:        cfg.UpdaterEnabled,
+			UpdateInterval: schedule.TickInterval(),
+			InitialDelay:   cfg.UpdaterInitialDelay,
+			Jitter:         cfg.UpdaterJitter,
+		},
+		Crypto: cryptoOptions(cfg, httpClient),
+	})
+	go rates.Run(ctx)
+
+	// 6. Вывод списка валют, сохраненных в БД на момент запуска
+	utils.PrintAvailableCurrencies(ctx, storage)
+
+	// Ежедневный снимок курсов на конец дня (если хранилище поддерживает снимки)
+	if snapshots, ok := storage.(storages.SnapshotStore); ok {
+		go snapshots.RunDailySnapshots(ctx, cfg.SnapshotTime, cfg.SnapshotLocation)
+	}
+
+	// 7. Публикация метрик Prometheus (если задан адрес)
+	go metrics.Serve(cfg.MetricsAddr)
+
+	// 8. Отслеживание устаревания курсов (метрики, флаг degraded и оповещения дежурных)
+	var notifier alert.Notifier
+	if telegram := alert.NewTelegram(cfg.AlertTelegramToken, cfg.AlertTelegramChatID); telegram != nil {
+		notifier = telegram
 	}
+	monitor := staleness.NewMonitor(storage, cfg.RateStaleAfter, notifier)
+	go monitor.Run(ctx, stalenessCheckInterval)
 
-	// 7. Вывод списка доступных валют
-	utils.PrintAvailableCurrencies(storage)
+	// Страница состояния для операторов (курсы, поставщики, ошибки обновлений), если задан адрес
+	go status.Serve(cfg.StatusAddr, status.NewHandler(storage, rates, monitor))
 
-	// 8. Запуск gRPC сервера
+	// 9. Запуск gRPC сервера
 	log.Println("Запуск gRPC сервера...")
-	server.Start("50051", storage) // Порт 50051 и инициализированное хранилище
+	server.Start(ctx, cfg, storage, rates, monitor) // Порт из конфигурации и инициализированное хранилище
+}
+
+// stalenessCheckInterval - период проверки возраста курсов
+const stalenessCheckInterval = time.Minute
+
+// rateProviders создает поставщиков курсов в формате ЦБ РФ, упорядоченных по имени
+// Параметры:
+//   - urls: имя поставщика -> URL API
+//   - client: HTTP клиент поставщиков курсов
+func rateProviders(urls map[string]string, client *api.HTTPClient) []api.Provider {
+	names := make([]string, 0, len(urls))
+	for name := range urls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	providers := make([]api.Provider, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, api.NewCBRProvider(name, urls[name], client))
+	}
+	if len(providers) > 1 {
+		log.Printf("Поставщики курсов: %v (курсы агрегируются)", names)
+	}
+	return providers
+}
+
+// cryptoOptions создает параметры обновления курсов криптовалют
+// Криптовалюты отключены, если не задан URL API или список активов
+func cryptoOptions(cfg *config.Config, client *api.HTTPClient) updater.CryptoOptions {
+	if cfg.CryptoAPIURL == "" || len(cfg.CryptoAssets) == 0 {
+		return updater.CryptoOptions{}
+	}
+	return updater.CryptoOptions{
+		Provider:       api.NewCoinGeckoProvider(cfg.CryptoAPIURL, cfg.CryptoAPIKey, cfg.CryptoAssets, cfg.BaseCurrency, client),
+		UpdateInterval: cfg.CryptoUpdateInterval,
+		Jitter:         cfg.UpdaterJitter,
+	}
+}
+
+// openStorage подключается к хранилищу курсов, выбранному в STORAGE_DRIVER
+// PostgreSQL поддерживает все возможности сервиса; MySQL и SQLite - курсы, журнал изменений,
+// карантин и справочник валют (ручные курсы, история, снимки и поток событий недоступны)
+// Параметры:
+//   - ctx: контекст жизни хранилища
+//   - cfg: конфигурация сервиса (драйвер, строка подключения и параметры пула)
+//
+// Возвращает:
+//   - storages.Storage: хранилище с примененными миграциями
+//   - error: ошибка подключения или миграции
+func openStorage(ctx context.Context, cfg *config.Config) (storages.Storage, error) {
+	pool := storages.PoolOptions{
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+	}
+
+	switch cfg.StorageDriver {
+	case "mysql":
+		return sqlstore.NewStore(ctx, sqlstore.MySQL, cfg.StorageDSN, cfg.RateMaxJump, cfg.BaseCurrency, cfg.DBQueryTimeout, pool)
+	case "sqlite":
+		return sqlstore.NewStore(ctx, sqlstore.SQLite, cfg.StorageDSN, cfg.RateMaxJump, cfg.BaseCurrency, cfg.DBQueryTimeout, pool)
+	}
+
+	// Формирование строки подключения к PostgreSQL и проверка подключения
+	connStr := cfg.GetDBConnString()
+	if err := checkDBConnection(connStr); err != nil {
+		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
+	}
+	return postgres.NewPostgresStorage(ctx, connStr, cfg.RateMaxJump, cfg.BaseCurrency, cfg.DBQueryTimeout, pool)
 }
 
 // checkDBConnection проверяет подключение к базе данных
@@ -82,22 +203,3 @@ func checkDBConnection(connStr string) error {
 	}
 	return nil
 }
-
-// getEnvAsInt получает переменную окружения как целое число
-// Параметры:
-//   - name: имя переменной окружения
-//   - defaultValue: значение по умолчанию
-//
-// Возвращает:
-//   - int: значение переменной или значение по умолчанию
-func getEnvAsInt(name string, defaultValue int) int {
-	val := os.Getenv(name)
-	if val == "" {
-		return defaultValue
-	}
-	result, err := strconv.Atoi(val)
-	if err != nil {
-		return defaultValue
-	}
-	return result
-}
@@ -0,0 +1,72 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// telegramAPIURL - адрес Bot API Telegram
+const telegramAPIURL = "https://api.telegram.org"
+
+// Notifier отправляет оповещения дежурным
+type Notifier interface {
+	// Notify отправляет текст оповещения
+	Notify(ctx context.Context, text string) error
+}
+
+// Telegram отправляет оповещения в чат Telegram от имени бота
+type Telegram struct {
+	token  string       // Токен бота
+	chatID string       // Идентификатор чата дежурных
+	client *http.Client // HTTP клиент с таймаутом
+}
+
+// NewTelegram создает отправителя оповещений в Telegram
+// Параметры:
+//   - token: токен бота
+//   - chatID: идентификатор чата
+//
+// Возвращает nil, если токен или чат не заданы (оповещения отключены)
+func NewTelegram(token, chatID string) *Telegram {
+	if token == "" || chatID == "" {
+		return nil
+	}
+	return &Telegram{
+		token:  token,
+		chatID: chatID,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify отправляет сообщение в чат через метод sendMessage
+func (t *Telegram) Notify(ctx context.Context, text string) error {
+	body, err := json.Marshal(map[string]string{"chat_id": t.chatID, "text": text})
+	if err != nil {
+		return fmt.Errorf("ошибка формирования сообщения: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIURL, t.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// Текст ошибки http.Client содержит URL с токеном бота, поэтому не передаем его дальше
+		return fmt.Errorf("ошибка отправки сообщения в Telegram")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		description, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Telegram вернул статус %d: %s", resp.StatusCode, description)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Способы агрегации курсов нескольких поставщиков
+const (
+	AggregationMedian   = "median"   // Медиана курсов поставщиков
+	AggregationWeighted = "weighted" // Взвешенное среднее курсов поставщиков
+)
+
+// AggregateOptions содержит параметры агрегации курсов нескольких поставщиков
+type AggregateOptions struct {
+	Method       string             // Способ агрегации (AggregationMedian, AggregationWeighted)
+	Weights      map[string]float64 // Веса поставщиков для взвешенного среднего (по умолчанию 1)
+	MaxDeviation float64            // Допустимое отклонение от медианы в долях (0 - выбросы не отбрасываются)
+}
+
+// AggregatedRate - итоговый курс валюты и поставщики, которые в нем учтены
+// Если все курсы отброшены как выбросы (поставщики расходятся без большинства), Contributors пуст
+// и курс публиковать нельзя
+type AggregatedRate struct {
+	Rate         float64  // Опубликованный курс (0, если нет учтенных поставщиков)
+	Contributors []string // Поставщики, курсы которых учтены
+	Discarded    []string // Поставщики, курсы которых отброшены как выбросы
+}
+
+// Validate проверяет параметры агрегации
+func (o AggregateOptions) Validate() error {
+	if o.Method != AggregationMedian && o.Method != AggregationWeighted {
+		return fmt.Errorf("неизвестный способ агрегации %q (допустимо: %s, %s)", o.Method, AggregationMedian, AggregationWeighted)
+	}
+	if o.MaxDeviation < 0 {
+		return fmt.Errorf("допустимое отклонение не может быть отрицательным: %v", o.MaxDeviation)
+	}
+	for provider, weight := range o.Weights {
+		if weight <= 0 {
+			return fmt.Errorf("вес поставщика %s должен быть положительным: %v", provider, weight)
+		}
+	}
+	return nil
+}
+
+// Aggregate объединяет курсы нескольких поставщиков в один курс для каждой валюты
+// Сначала отбрасываются курсы, отклоняющиеся от медианы больше чем на MaxDeviation,
+// затем по оставшимся вычисляется медиана или взвешенное среднее
+// Параметры:
+//   - quotes: поставщик -> курсы валют
+//   - opts: параметры агрегации
+//
+// Возвращает:
+//   - map[string]AggregatedRate: итоговые курсы (ключ - код валюты)
+func Aggregate(quotes map[string]map[string]float64, opts AggregateOptions) map[string]AggregatedRate {
+	// Группируем курсы по валютам, поставщики упорядочены по имени для воспроизводимости
+	providers := make([]string, 0, len(quotes))
+	for provider := range quotes {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	byCurrency := make(map[string][]providerQuote)
+	for _, provider := range providers {
+		for currency, rate := range quotes[provider] {
+			if rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+				continue // Некорректный курс поставщика не учитывается
+			}
+			byCurrency[currency] = append(byCurrency[currency], providerQuote{provider: provider, rate: rate})
+		}
+	}
+
+	result := make(map[string]AggregatedRate, len(byCurrency))
+	for currency, currencyQuotes := range byCurrency {
+		result[currency] = aggregateQuotes(currencyQuotes, opts)
+	}
+	return result
+}
+
+// providerQuote - курс валюты от одного поставщика
+type providerQuote struct {
+	provider string  // Имя поставщика
+	rate     float64 // Курс
+}
+
+// aggregateQuotes вычисляет итоговый курс одной валюты
+func aggregateQuotes(quotes []providerQuote, opts AggregateOptions) AggregatedRate {
+	center := median(quotes)
+
+	var aggregated AggregatedRate
+	accepted := make([]providerQuote, 0, len(quotes))
+	for _, quote := range quotes {
+		if opts.MaxDeviation > 0 && math.Abs(quote.rate-center)/center > opts.MaxDeviation {
+			aggregated.Discarded = append(aggregated.Discarded, quote.provider)
+			continue
+		}
+		accepted = append(accepted, quote)
+		aggregated.Contributors = append(aggregated.Contributors, quote.provider)
+	}
+
+	if len(accepted) == 0 {
+		return aggregated
+	}
+	if opts.Method == AggregationWeighted {
+		aggregated.Rate = weightedAverage(accepted, opts.Weights)
+	} else {
+		aggregated.Rate = median(accepted)
+	}
+	return aggregated
+}
+
+// median возвращает медиану непустого списка курсов (для четного количества - среднее двух центральных)
+func median(quotes []providerQuote) float64 {
+	rates := make([]float64, len(quotes))
+	for i, quote := range quotes {
+		rates[i] = quote.rate
+	}
+	sort.Float64s(rates)
+
+	middle := len(rates) / 2
+	if len(rates)%2 == 0 {
+		return (rates[middle-1] + rates[middle]) / 2
+	}
+	return rates[middle]
+}
+
+// weightedAverage возвращает среднее курсов, взвешенное по весам поставщиков
+// Если сумма весов учтенных поставщиков не положительна (веса не проверены Validate),
+// возвращается медиана, чтобы не опубликовать NaN
+func weightedAverage(quotes []providerQuote, weights map[string]float64) float64 {
+	var sum, total float64
+	for _, quote := range quotes {
+		weight, ok := weights[quote.provider]
+		if !ok {
+			weight = 1
+		}
+		sum += quote.rate * weight
+		total += weight
+	}
+	if total <= 0 {
+		return median(quotes)
+	}
+	return sum / total
+}
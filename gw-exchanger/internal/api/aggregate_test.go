@@ -0,0 +1,145 @@
+package api
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name      string
+		quotes    map[string]map[string]float64
+		opts      AggregateOptions
+		want      float64
+		used      []string
+		discarded []string
+	}{
+		{
+			name:   "один поставщик",
+			quotes: map[string]map[string]float64{"cbr": {"USD": 90}},
+			opts:   AggregateOptions{Method: AggregationMedian, MaxDeviation: 0.05},
+			want:   90,
+			used:   []string{"cbr"},
+		},
+		{
+			name:   "медиана нечетного количества",
+			quotes: map[string]map[string]float64{"a": {"USD": 91}, "b": {"USD": 89}, "c": {"USD": 90}},
+			opts:   AggregateOptions{Method: AggregationMedian},
+			want:   90,
+			used:   []string{"a", "b", "c"},
+		},
+		{
+			name:   "медиана четного количества - среднее двух центральных",
+			quotes: map[string]map[string]float64{"a": {"USD": 88}, "b": {"USD": 90}, "c": {"USD": 91}, "d": {"USD": 95}},
+			opts:   AggregateOptions{Method: AggregationMedian},
+			want:   90.5,
+			used:   []string{"a", "b", "c", "d"},
+		},
+		{
+			name:      "выброс отбрасывается до расчета медианы",
+			quotes:    map[string]map[string]float64{"a": {"USD": 90}, "b": {"USD": 91}, "c": {"USD": 120}},
+			opts:      AggregateOptions{Method: AggregationMedian, MaxDeviation: 0.1},
+			want:      90.5,
+			used:      []string{"a", "b"},
+			discarded: []string{"c"},
+		},
+		{
+			name:      "все курсы - выбросы",
+			quotes:    map[string]map[string]float64{"a": {"USD": 80}, "b": {"USD": 100}},
+			opts:      AggregateOptions{Method: AggregationMedian, MaxDeviation: 0.05},
+			want:      0,
+			discarded: []string{"a", "b"},
+		},
+		{
+			name:   "без допустимого отклонения выбросы учитываются",
+			quotes: map[string]map[string]float64{"a": {"USD": 80}, "b": {"USD": 100}},
+			opts:   AggregateOptions{Method: AggregationMedian},
+			want:   90,
+			used:   []string{"a", "b"},
+		},
+		{
+			name:   "взвешенное среднее, вес по умолчанию 1",
+			quotes: map[string]map[string]float64{"a": {"USD": 90}, "b": {"USD": 93}},
+			opts:   AggregateOptions{Method: AggregationWeighted, Weights: map[string]float64{"b": 2}},
+			want:   92,
+			used:   []string{"a", "b"},
+		},
+		{
+			name:      "взвешенное среднее без выброса",
+			quotes:    map[string]map[string]float64{"a": {"USD": 90}, "b": {"USD": 92}, "c": {"USD": 200}},
+			opts:      AggregateOptions{Method: AggregationWeighted, Weights: map[string]float64{"a": 3, "c": 10}, MaxDeviation: 0.1},
+			want:      90.5,
+			used:      []string{"a", "b"},
+			discarded: []string{"c"},
+		},
+		{
+			name:   "нулевые веса - медиана вместо NaN",
+			quotes: map[string]map[string]float64{"a": {"USD": 90}, "b": {"USD": 92}},
+			opts:   AggregateOptions{Method: AggregationWeighted, Weights: map[string]float64{"a": 0, "b": 0}},
+			want:   91,
+			used:   []string{"a", "b"},
+		},
+		{
+			name:   "некорректные курсы поставщика не учитываются",
+			quotes: map[string]map[string]float64{"a": {"USD": 90}, "b": {"USD": 0}, "c": {"USD": math.NaN()}, "d": {"USD": -1}},
+			opts:   AggregateOptions{Method: AggregationMedian, MaxDeviation: 0.05},
+			want:   90,
+			used:   []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Aggregate(tt.quotes, tt.opts)["USD"]
+			if !ok {
+				t.Fatal("курс USD не рассчитан")
+			}
+			if math.Abs(got.Rate-tt.want) > 1e-9 {
+				t.Errorf("курс %v, ожидался %v", got.Rate, tt.want)
+			}
+			if !reflect.DeepEqual(got.Contributors, tt.used) {
+				t.Errorf("учтены %v, ожидались %v", got.Contributors, tt.used)
+			}
+			if !reflect.DeepEqual(got.Discarded, tt.discarded) {
+				t.Errorf("отброшены %v, ожидались %v", got.Discarded, tt.discarded)
+			}
+		})
+	}
+}
+
+func TestAggregateCurrencies(t *testing.T) {
+	quotes := map[string]map[string]float64{
+		"a": {"USD": 90, "EUR": 98},
+		"b": {"USD": 92},
+	}
+	got := Aggregate(quotes, AggregateOptions{Method: AggregationMedian})
+	if len(got) != 2 || got["USD"].Rate != 91 || got["EUR"].Rate != 98 {
+		t.Errorf("курсы %+v", got)
+	}
+	if len(Aggregate(map[string]map[string]float64{}, AggregateOptions{Method: AggregationMedian})) != 0 {
+		t.Error("курсы без поставщиков")
+	}
+}
+
+func TestAggregateOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    AggregateOptions
+		wantErr bool
+	}{
+		{name: "медиана", opts: AggregateOptions{Method: AggregationMedian, MaxDeviation: 0.05}},
+		{name: "взвешенное среднее", opts: AggregateOptions{Method: AggregationWeighted, Weights: map[string]float64{"cbr": 2}}},
+		{name: "неизвестный способ", opts: AggregateOptions{Method: "mean"}, wantErr: true},
+		{name: "отрицательное отклонение", opts: AggregateOptions{Method: AggregationMedian, MaxDeviation: -0.1}, wantErr: true},
+		{name: "нулевой вес", opts: AggregateOptions{Method: AggregationWeighted, Weights: map[string]float64{"cbr": 0}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ошибка %v, ожидалась ошибка: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"log"
+	"math"
+	"strings"
 )
 
 // CBRResponse представляет структуру ответа от API Центрального Банка России
@@ -24,40 +26,72 @@ type CBRate struct {
 
 // FetchCBExchangeRates получает актуальные курсы валют от API ЦБ РФ
 // Параметры:
+//   - ctx: контекст запроса (отмена прерывает HTTP запрос)
+//   - client: HTTP клиент поставщиков курсов
 //   - url: адрес API Центробанка (например: "https://www.cbr-xml-daily.ru/daily_json.js")
 //
 // Возвращает:
 //   - map[string]float64: словарь с курсами валют (ключ - код валюты, значение - курс к рублю)
+//   - []CurrencyInfo: сведения о валютах с корректными курсами (название, номинал, знаки после запятой)
 //   - error: ошибка при получении или обработке данных
-func FetchCBExchangeRates(url string) (map[string]float64, error) {
-	// 1. Отправка HTTP GET запроса к API Центробанка
-	resp, err := http.Get(url)
+func FetchCBExchangeRates(ctx context.Context, client *HTTPClient, url string) (map[string]float64, []CurrencyInfo, error) {
+	// 1-2. Запрос к API Центробанка и чтение тела успешного ответа ограниченного размера
+	body, err := client.Get(ctx, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка получения курсов: %v", err)
-	}
-	defer resp.Body.Close() // Гарантированное закрытие тела ответа
-
-	// 2. Чтение тела ответа
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения ответа: %v", err)
+		return nil, nil, fmt.Errorf("ошибка получения курсов: %v", err)
 	}
 
 	// 3. Парсинг JSON данных в структуру CBRResponse
 	var data CBRResponse
 	if err := json.Unmarshal(body, &data); err != nil {
-		return nil, fmt.Errorf("ошибка разбора JSON: %v", err)
+		return nil, nil, fmt.Errorf("ошибка разбора JSON: %v", err)
 	}
 
 	// 4. Подготовка результата - нормализация курсов к 1 единице валюты
+	// Некорректные записи (нулевой номинал, неположительный курс, чужой код) отбрасываются
 	rates := make(map[string]float64)
-	for _, rate := range data.Rates {
-		// Пересчитываем курс на 1 единицу валюты (делим на номинал)
-		rates[rate.CharCode] = rate.Value / float64(rate.Nominal)
+	currencies := make([]CurrencyInfo, 0, len(data.Rates)+1)
+	for key, rate := range data.Rates {
+		value, err := rate.unitRate(key)
+		if err != nil {
+			log.Printf("Курс %s от %s отброшен: %v", key, url, err)
+			continue
+		}
+		rates[rate.CharCode] = value
+		currencies = append(currencies, CurrencyInfo{
+			Code:     rate.CharCode,
+			Name:     rate.Name,
+			Nominal:  rate.Nominal,
+			Decimals: CurrencyDecimals(rate.CharCode),
+		})
+	}
+	if len(rates) == 0 {
+		return nil, nil, fmt.Errorf("ответ не содержит корректных курсов (записей: %d)", len(data.Rates))
 	}
 
 	// 5. Добавляем рубль с курсом 1.0 для консистентности
-	rates["RUB"] = 1.0
+	rates[CBRBaseCurrency] = 1.0
+	currencies = append(currencies, CurrencyInfo{Code: CBRBaseCurrency, Name: "Российский рубль", Nominal: 1, Decimals: 2})
+
+	return rates, currencies, nil
+}
 
-	return rates, nil
+// unitRate проверяет запись курса и возвращает стоимость единицы валюты в рублях
+// Параметры:
+//   - key: ключ записи в словаре Valute (должен совпадать с буквенным кодом)
+//
+// Возвращает:
+//   - float64: курс единицы валюты
+//   - error: описание некорректного поля
+func (r CBRate) unitRate(key string) (float64, error) {
+	if len(r.CharCode) != 3 || strings.ToUpper(r.CharCode) != r.CharCode || r.CharCode != key {
+		return 0, fmt.Errorf("некорректный код валюты %q", r.CharCode)
+	}
+	if r.Nominal <= 0 {
+		return 0, fmt.Errorf("некорректный номинал %d", r.Nominal)
+	}
+	if r.Value <= 0 || math.IsNaN(r.Value) || math.IsInf(r.Value, 0) {
+		return 0, fmt.Errorf("некорректный курс %v", r.Value)
+	}
+	return r.Value / float64(r.Nominal), nil
 }
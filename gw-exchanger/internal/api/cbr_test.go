@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCBRateUnitRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		rate    CBRate
+		want    float64
+		wantErr bool
+	}{
+		{name: "курс за единицу", key: "USD", rate: CBRate{CharCode: "USD", Nominal: 1, Value: 90.5}, want: 90.5},
+		{name: "курс за номинал", key: "JPY", rate: CBRate{CharCode: "JPY", Nominal: 100, Value: 60}, want: 0.6},
+		{name: "нулевой номинал", key: "USD", rate: CBRate{CharCode: "USD", Nominal: 0, Value: 90}, wantErr: true},
+		{name: "отрицательный номинал", key: "USD", rate: CBRate{CharCode: "USD", Nominal: -1, Value: 90}, wantErr: true},
+		{name: "нулевой курс", key: "USD", rate: CBRate{CharCode: "USD", Nominal: 1, Value: 0}, wantErr: true},
+		{name: "отрицательный курс", key: "USD", rate: CBRate{CharCode: "USD", Nominal: 1, Value: -90}, wantErr: true},
+		{name: "курс NaN", key: "USD", rate: CBRate{CharCode: "USD", Nominal: 1, Value: math.NaN()}, wantErr: true},
+		{name: "бесконечный курс", key: "USD", rate: CBRate{CharCode: "USD", Nominal: 1, Value: math.Inf(1)}, wantErr: true},
+		{name: "код в нижнем регистре", key: "usd", rate: CBRate{CharCode: "usd", Nominal: 1, Value: 90}, wantErr: true},
+		{name: "код не совпадает с ключом", key: "EUR", rate: CBRate{CharCode: "USD", Nominal: 1, Value: 90}, wantErr: true},
+		{name: "код не из трех букв", key: "US", rate: CBRate{CharCode: "US", Nominal: 1, Value: 90}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rate.unitRate(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка %v, ожидалась ошибка: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("курс %v, ожидался %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchCBExchangeRates(t *testing.T) {
+	output := log.Writer()
+	log.SetOutput(io.Discard) // Отброшенные записи пишутся в журнал
+	t.Cleanup(func() { log.SetOutput(output) })
+
+	tests := []struct {
+		name    string
+		body    string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{
+			name: "некорректные записи отбрасываются",
+			body: `{"Date":"2025-01-10","Valute":{
+				"USD":{"CharCode":"USD","Nominal":1,"Name":"Доллар США","Value":90},
+				"JPY":{"CharCode":"JPY","Nominal":100,"Name":"Иен","Value":60},
+				"EUR":{"CharCode":"EUR","Nominal":0,"Name":"Евро","Value":98},
+				"GBP":{"CharCode":"GBP","Nominal":1,"Name":"Фунт","Value":-1}}}`,
+			want: map[string]float64{"USD": 90, "JPY": 0.6, "RUB": 1},
+		},
+		{
+			name:    "нет ни одного корректного курса",
+			body:    `{"Date":"2025-01-10","Valute":{"USD":{"CharCode":"USD","Nominal":1,"Value":0}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "пустой ответ",
+			body:    `{"Date":"2025-01-10","Valute":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "некорректный JSON",
+			body:    `{"Valute":`,
+			wantErr: true,
+		},
+	}
+
+	client, err := NewHTTPClient(HTTPOptions{Timeout: time.Second, ConnectTimeout: time.Second, MaxBodySize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			rates, currencies, err := FetchCBExchangeRates(context.Background(), client, server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка %v, ожидалась ошибка: %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(rates) != len(tt.want) || len(currencies) != len(tt.want) {
+				t.Fatalf("курсы %v, валюты %v; ожидались курсы %v", rates, currencies, tt.want)
+			}
+			for currency, want := range tt.want {
+				if math.Abs(rates[currency]-want) > 1e-12 {
+					t.Errorf("курс %s = %v, ожидался %v", currency, rates[currency], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPOptions содержит параметры HTTP клиента для запросов к поставщикам курсов
+type HTTPOptions struct {
+	Timeout        time.Duration // Общее время запроса, включая чтение тела ответа
+	ConnectTimeout time.Duration // Время на установку соединения и TLS рукопожатие
+	MaxBodySize    int64         // Максимальный размер тела ответа в байтах
+	ProxyURL       string        // Адрес прокси (пустой - из переменных HTTPS_PROXY, HTTP_PROXY, NO_PROXY)
+}
+
+// HTTPClient выполняет запросы к поставщикам курсов с ограничением времени и размера ответа
+type HTTPClient struct {
+	client      *http.Client // HTTP клиент с таймаутами и прокси
+	maxBodySize int64        // Максимальный размер тела ответа
+}
+
+// errBodyTooLarge - тело ответа поставщика превышает допустимый размер
+var errBodyTooLarge = errors.New("ответ превышает допустимый размер")
+
+// NewHTTPClient создает HTTP клиент для запросов к поставщикам курсов
+// Параметры:
+//   - opts: таймауты, максимальный размер ответа и прокси
+//
+// Возвращает:
+//   - *HTTPClient: готовый к работе клиент
+//   - error: некорректный адрес прокси
+func NewHTTPClient(opts HTTPOptions) (*HTTPClient, error) {
+	proxy := http.ProxyFromEnvironment
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			// Адрес не выводится: он может содержать учетные данные прокси
+			return nil, fmt.Errorf("некорректный адрес прокси (ожидается схема://хост:порт)")
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{Timeout: opts.ConnectTimeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   opts.ConnectTimeout,
+		ResponseHeaderTimeout: opts.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+	return &HTTPClient{
+		client:      &http.Client{Timeout: opts.Timeout, Transport: transport},
+		maxBodySize: opts.MaxBodySize,
+	}, nil
+}
+
+// Get выполняет GET запрос и возвращает тело успешного ответа
+// Ответ с кодом, отличным от 200, и ответ больше MaxBodySize считаются ошибкой (тело не разбирается)
+// Параметры:
+//   - ctx: контекст запроса (отмена прерывает запрос)
+//   - rawURL: адрес запроса
+//   - header: дополнительные заголовки (может быть nil)
+//
+// Возвращает:
+//   - []byte: тело ответа
+//   - error: ошибка запроса, статуса или размера ответа
+func (c *HTTPClient) Get(ctx context.Context, rawURL string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() // Гарантированное закрытие тела ответа
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("неожиданный статус ответа: %s", resp.Status)
+	}
+	if c.maxBodySize > 0 && resp.ContentLength > c.maxBodySize {
+		return nil, fmt.Errorf("%w: %d байт (не больше %d)", errBodyTooLarge, resp.ContentLength, c.maxBodySize)
+	}
+
+	// Читаем на байт больше допустимого, чтобы отличить ответ предельного размера от превышения
+	body := io.Reader(resp.Body)
+	if c.maxBodySize > 0 {
+		body = io.LimitReader(resp.Body, c.maxBodySize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ответа: %v", err)
+	}
+	if c.maxBodySize > 0 && int64(len(data)) > c.maxBodySize {
+		return nil, fmt.Errorf("%w: больше %d байт", errBodyTooLarge, c.maxBodySize)
+	}
+	return data, nil
+}
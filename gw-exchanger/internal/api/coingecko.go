@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CoinGeckoProvider получает курсы криптовалют из API CoinGecko (/simple/price)
+// Курсы запрашиваются сразу в базовой валюте, поэтому пересчет через фиатные курсы не нужен
+type CoinGeckoProvider struct {
+	url          string            // Адрес метода /simple/price
+	apiKey       string            // Ключ демо-API (необязательный)
+	assets       map[string]string // Код криптовалюты -> идентификатор CoinGecko (BTC -> bitcoin)
+	baseCurrency string            // Валюта, в которой запрашиваются курсы
+	client       *HTTPClient       // HTTP клиент поставщиков курсов
+}
+
+// NewCoinGeckoProvider создает поставщика курсов криптовалют
+// Параметры:
+//   - url: адрес метода /simple/price (например "https://api.coingecko.com/api/v3/simple/price")
+//   - apiKey: ключ демо-API CoinGecko (пустой - запросы без ключа)
+//   - assets: код криптовалюты -> идентификатор CoinGecko (например BTC -> bitcoin)
+//   - baseCurrency: валюта, в которой запрашиваются курсы (например "RUB")
+//   - client: HTTP клиент поставщиков курсов
+func NewCoinGeckoProvider(url, apiKey string, assets map[string]string, baseCurrency string, client *HTTPClient) *CoinGeckoProvider {
+	return &CoinGeckoProvider{
+		url:          url,
+		apiKey:       apiKey,
+		assets:       assets,
+		baseCurrency: baseCurrency,
+		client:       client,
+	}
+}
+
+// Name возвращает имя поставщика
+func (p *CoinGeckoProvider) Name() string {
+	return "coingecko"
+}
+
+// BaseCurrency возвращает валюту, в которой запрашиваются курсы
+func (p *CoinGeckoProvider) BaseCurrency() string {
+	return p.baseCurrency
+}
+
+// FetchRates получает курсы криптовалют
+// Возвращает стоимость единицы криптовалюты в базовой валюте (ключ - код криптовалюты)
+// и курс самой базовой валюты, равный 1
+func (p *CoinGeckoProvider) FetchRates(ctx context.Context) (map[string]float64, error) {
+	ids := make([]string, 0, len(p.assets))
+	for _, id := range p.assets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	vsCurrency := strings.ToLower(p.baseCurrency)
+
+	query := url.Values{}
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("vs_currencies", vsCurrency)
+	query.Set("precision", "full")
+
+	header := http.Header{}
+	if p.apiKey != "" {
+		header.Set("x-cg-demo-api-key", p.apiKey)
+	}
+	body, err := p.client.Get(ctx, p.url+"?"+query.Encode(), header)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения курсов криптовалют: %v", err)
+	}
+
+	// Ответ: {"bitcoin": {"rub": 9000000.12}, "ethereum": {"rub": 300000.5}}
+	var data map[string]map[string]float64
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("ошибка разбора JSON: %v", err)
+	}
+
+	rates := map[string]float64{p.baseCurrency: 1.0}
+	for symbol, id := range p.assets {
+		price, ok := data[id][vsCurrency]
+		if !ok || price <= 0 {
+			return nil, fmt.Errorf("CoinGecko не вернул курс %s (%s) в %s", symbol, id, p.baseCurrency)
+		}
+		rates[symbol] = price
+	}
+	return rates, nil
+}
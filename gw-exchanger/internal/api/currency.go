@@ -0,0 +1,37 @@
+package api
+
+// CurrencyInfo содержит сведения о валюте из ответа поставщика
+type CurrencyInfo struct {
+	Code     string // Буквенный код валюты (например: USD)
+	Name     string // Название валюты
+	Nominal  int    // Номинал, к которому поставщик публикует курс (например: 10 для JPY у ЦБ РФ)
+	Decimals int    // Количество знаков после запятой в суммах валюты
+}
+
+// CurrencyInfoProvider - поставщик курсов, публикующий сведения о валютах вместе с курсами
+type CurrencyInfoProvider interface {
+	// Currencies возвращает сведения о валютах из последнего успешного ответа (nil - ответа еще не было)
+	Currencies() []CurrencyInfo
+}
+
+// defaultCurrencyDecimals - количество знаков после запятой для большинства валют (ISO 4217)
+const defaultCurrencyDecimals = 2
+
+// currencyDecimals - валюты, количество знаков после запятой которых по ISO 4217 отличается от двух
+var currencyDecimals = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLP": 0, "ISK": 0, "JPY": 0, "KRW": 0, "PYG": 0, "UGX": 0, "VND": 0, "XAF": 0, "XOF": 0,
+}
+
+// CurrencyDecimals возвращает количество знаков после запятой в суммах валюты по ISO 4217
+// Параметры:
+//   - code: буквенный код валюты
+//
+// Возвращает:
+//   - int: количество знаков (для валют, отсутствующих в справочнике, - 2)
+func CurrencyDecimals(code string) int {
+	if decimals, ok := currencyDecimals[code]; ok {
+		return decimals
+	}
+	return defaultCurrencyDecimals
+}
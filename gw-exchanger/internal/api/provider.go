@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Provider - поставщик курсов валют
+type Provider interface {
+	// Name возвращает имя поставщика (используется в логах и при настройке весов)
+	Name() string
+
+	// BaseCurrency возвращает валюту, к которой поставщик публикует курсы
+	BaseCurrency() string
+
+	// FetchRates получает актуальные курсы валют
+	// Ключ - код валюты, значение - стоимость единицы валюты в BaseCurrency (курс базовой валюты равен 1)
+	FetchRates(ctx context.Context) (map[string]float64, error)
+}
+
+// CBRBaseCurrency - валюта, к которой публикует курсы ЦБ РФ
+const CBRBaseCurrency = "RUB"
+
+// CBRProvider получает курсы из API в формате ЦБ РФ (daily_json.js)
+// Этот формат отдают как сам ЦБ РФ, так и его зеркала
+type CBRProvider struct {
+	name   string      // Имя поставщика
+	url    string      // Адрес API
+	client *HTTPClient // HTTP клиент поставщиков курсов
+
+	mu         sync.Mutex
+	currencies []CurrencyInfo // Сведения о валютах из последнего успешного ответа
+}
+
+// NewCBRProvider создает поставщика курсов в формате ЦБ РФ
+// Параметры:
+//   - name: имя поставщика (например "cbr")
+//   - url: адрес API (например "https://www.cbr-xml-daily.ru/daily_json.js")
+//   - client: HTTP клиент поставщиков курсов
+func NewCBRProvider(name, url string, client *HTTPClient) *CBRProvider {
+	return &CBRProvider{name: name, url: url, client: client}
+}
+
+// Name возвращает имя поставщика
+func (p *CBRProvider) Name() string {
+	return p.name
+}
+
+// BaseCurrency возвращает базовую валюту ЦБ РФ (рубль)
+func (p *CBRProvider) BaseCurrency() string {
+	return CBRBaseCurrency
+}
+
+// FetchRates получает курсы валют от API и запоминает сведения о валютах из ответа
+func (p *CBRProvider) FetchRates(ctx context.Context) (map[string]float64, error) {
+	rates, currencies, err := FetchCBExchangeRates(ctx, p.client, p.url)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.currencies = currencies
+	p.mu.Unlock()
+	return rates, nil
+}
+
+// Currencies возвращает сведения о валютах (название, номинал) из последнего успешного ответа
+func (p *CBRProvider) Currencies() []CurrencyInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.currencies
+}
+
+// Rebase пересчитывает курсы к другой базовой валюте
+// Курс валюты - стоимость ее единицы в базовой валюте, поэтому новый курс равен
+// старому, деленному на старый курс новой базовой валюты
+// Параметры:
+//   - rates: курсы к базовой валюте from
+//   - from: текущая базовая валюта
+//   - to: новая базовая валюта
+//
+// Возвращает:
+//   - map[string]float64: курсы к базовой валюте to
+//   - error: ошибка, если курса новой базовой валюты нет среди курсов
+func Rebase(rates map[string]float64, from, to string) (map[string]float64, error) {
+	if from == to {
+		return rates, nil
+	}
+	base, ok := rates[to]
+	if !ok || base <= 0 {
+		return nil, fmt.Errorf("курс базовой валюты %s отсутствует в курсах к %s", to, from)
+	}
+
+	rebased := make(map[string]float64, len(rates))
+	for currency, rate := range rates {
+		rebased[currency] = rate / base
+	}
+	return rebased, nil
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRebase(t *testing.T) {
+	cbr := map[string]float64{"RUB": 1, "USD": 90, "EUR": 98}
+
+	tests := []struct {
+		name string
+		to   string
+		want map[string]float64
+	}{
+		{name: "та же базовая валюта", to: "RUB", want: cbr},
+		{name: "рубли к доллару", to: "USD", want: map[string]float64{"RUB": 1.0 / 90, "USD": 1, "EUR": 98.0 / 90}},
+		{name: "рубли к евро", to: "EUR", want: map[string]float64{"RUB": 1.0 / 98, "USD": 90.0 / 98, "EUR": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Rebase(cbr, "RUB", tt.to)
+			if err != nil {
+				t.Fatalf("ошибка пересчета: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("курсы %v, ожидались %v", got, tt.want)
+			}
+			for currency, want := range tt.want {
+				if math.Abs(got[currency]-want) > 1e-12 {
+					t.Errorf("курс %s = %v, ожидался %v", currency, got[currency], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRebaseMissingBase(t *testing.T) {
+	for _, rates := range []map[string]float64{
+		{"RUB": 1, "USD": 90},
+		{"RUB": 1, "USD": 90, "GBP": 0},
+	} {
+		if _, err := Rebase(rates, "RUB", "GBP"); err == nil {
+			t.Errorf("пересчет к валюте без курса выполнен: %v", rates)
+		}
+	}
+}
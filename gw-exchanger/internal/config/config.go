@@ -0,0 +1,419 @@
+package config
+
+import (
+	"fmt"
+	"github.com/joho/godotenv" // Пакет для загрузки .env файлов
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config содержит все конфигурационные параметры сервиса обмена валют
+type Config struct {
+	GRPCPort       string        // Порт gRPC сервера (например "50051")
+	DBHost         string        // Хост PostgreSQL сервера
+	DBPort         string        // Порт PostgreSQL сервера
+	DBUser         string        // Имя пользователя PostgreSQL
+	DBPassword     string        // Пароль пользователя PostgreSQL
+	DBName         string        // Имя базы данных
+	StorageDriver  string        // Хранилище курсов: postgres, mysql или sqlite
+	StorageDSN     string        // Строка подключения к MySQL или путь к файлу SQLite (для postgres - из DB_*)
+	CBAPIURL       string        // URL API Центробанка (используется, если RATE_PROVIDERS не задан)
+	UpdateInterval time.Duration // Интервал обновления курсов по умолчанию
+
+	CurrencyAllowlist []string                 // Хранимые валюты (пустой - все валюты поставщиков)
+	CurrencyIntervals map[string]time.Duration // Интервалы обновления отдельных валют
+
+	UpdaterEnabled      bool          // Флаг фонового обновления курсов (false - курсы не обновляются)
+	UpdaterInitialDelay time.Duration // Задержка перед первым обновлением после запуска
+	UpdaterJitter       time.Duration // Максимальная случайная добавка к интервалу обновления
+	DBQueryTimeout      time.Duration // Максимальное время выполнения запроса к БД
+
+	DBMaxOpenConns    int           // Максимум открытых соединений с БД (0 - без ограничений)
+	DBMaxIdleConns    int           // Максимум простаивающих соединений в пуле
+	DBConnMaxLifetime time.Duration // Максимальное время жизни соединения (0 - без ограничений)
+
+	ClientTokens map[string]string // API токены клиентов: имя клиента -> токен (wallet, bot, ...)
+	ClientQuotas map[string]int    // Квоты запросов на окно: имя клиента -> лимит ("*" - для остальных)
+	QuotaWindow  time.Duration     // Длительность окна для подсчета квот
+	MetricsAddr  string            // Адрес HTTP сервера метрик Prometheus (пустой - метрики не публикуются)
+	StatusAddr   string            // Адрес HTTP страницы состояния для операторов (пустой - страница отключена)
+
+	AdminClients   []string      // Клиенты, которым доступны административные вызовы (ручные курсы)
+	OverrideMaxTTL time.Duration // Максимальный срок действия ручного курса
+
+	RateProviders    map[string]string  // Поставщики курсов в формате ЦБ РФ: имя -> URL API
+	RateAggregation  string             // Способ агрегации курсов нескольких поставщиков (median, weighted)
+	ProviderWeights  map[string]float64 // Веса поставщиков для взвешенного среднего (по умолчанию 1)
+	RateMaxDeviation float64            // Допустимое отклонение курса поставщика от медианы в долях (0 - без отбрасывания)
+	RateMaxJump      float64            // Допустимое изменение курса за обновление в долях, больший скачок - на карантин (0 - без проверки)
+
+	ProviderHTTPTimeout time.Duration // Общее время запроса к поставщику курсов, включая чтение ответа
+	ProviderConnTimeout time.Duration // Время на установку соединения с поставщиком и TLS рукопожатие
+	ProviderMaxBodySize int64         // Максимальный размер ответа поставщика в байтах
+	ProviderProxyURL    string        // Прокси для запросов к поставщикам (пустой - из HTTPS_PROXY/HTTP_PROXY)
+
+	BaseCurrency        string        // Базовая валюта, к которой хранятся курсы (стоимость единицы валюты в базовой)
+	RateStaleAfter      time.Duration // Возраст курса, после которого он считается устаревшим
+	AlertTelegramToken  string        // Токен бота для оповещений дежурных (пустой - оповещения отключены)
+	AlertTelegramChatID string        // Идентификатор чата дежурных в Telegram
+
+	CryptoAPIURL         string            // URL метода /simple/price CoinGecko (пустой - криптовалюты отключены)
+	CryptoAPIKey         string            // Ключ демо-API CoinGecko (необязательный)
+	CryptoAssets         map[string]string // Криптовалюты: код -> идентификатор CoinGecko (BTC -> bitcoin)
+	CryptoUpdateInterval time.Duration     // Интервал обновления курсов криптовалют
+
+	SnapshotTime     time.Duration  // Время суток ежедневного снимка курсов (смещение от полуночи)
+	SnapshotLocation *time.Location // Часовой пояс, в котором определяются сутки снимка
+
+	GRPC GRPCServerConfig // Параметры gRPC сервера
+}
+
+// GRPCServerConfig содержит параметры gRPC сервера
+// Нулевые значения означают значения по умолчанию библиотеки grpc-go
+type GRPCServerConfig struct {
+	MaxRecvMsgSize       int           // Максимальный размер входящего сообщения в байтах (по умолчанию 4 МБ)
+	MaxSendMsgSize       int           // Максимальный размер исходящего сообщения в байтах (по умолчанию без ограничений)
+	MaxConcurrentStreams uint32        // Максимум одновременных вызовов на соединение (по умолчанию без ограничений)
+	ConnectionTimeout    time.Duration // Время на установку соединения (по умолчанию 120s)
+
+	KeepaliveTime     time.Duration // Период ping сервера простаивающему клиенту (по умолчанию 2h)
+	KeepaliveTimeout  time.Duration // Ожидание ответа на ping до закрытия соединения (по умолчанию 20s)
+	MaxConnectionIdle time.Duration // Закрытие соединения без вызовов после этого времени (по умолчанию без ограничений)
+	MaxConnectionAge  time.Duration // Максимальный возраст соединения (по умолчанию без ограничений)
+
+	KeepaliveMinTime             time.Duration // Минимальный период ping от клиента, чаще - разрыв соединения (по умолчанию 5m)
+	KeepalivePermitWithoutStream bool          // Разрешить ping клиента без активных вызовов
+}
+
+// LoadConfig загружает конфигурацию из .env файла и переменных окружения
+// Принимает имя файла конфигурации (например "config.env")
+// Возвращает указатель на Config или ошибку, если загрузка не удалась
+func LoadConfig(filename string) (*Config, error) {
+	if err := godotenv.Load(filename); err != nil {
+		return nil, fmt.Errorf("ошибка загрузки файла %s: %w", filename, err)
+	}
+
+	tokens, err := getEnvAsMap("CLIENT_TOKENS")
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := make(map[string]int)
+	rawQuotas, err := getEnvAsMap("CLIENT_QUOTAS")
+	if err != nil {
+		return nil, err
+	}
+	for client, value := range rawQuotas {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("некорректная квота для клиента %s: %q", client, value)
+		}
+		quotas[client] = limit
+	}
+
+	quotaWindow, err := time.ParseDuration(getEnv("QUOTA_WINDOW", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение QUOTA_WINDOW: %w", err)
+	}
+
+	queryTimeout, err := time.ParseDuration(getEnv("DB_QUERY_TIMEOUT", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение DB_QUERY_TIMEOUT: %w", err)
+	}
+
+	connMaxLifetime, err := time.ParseDuration(getEnv("DB_CONN_MAX_LIFETIME", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение DB_CONN_MAX_LIFETIME: %w", err)
+	}
+
+	overrideMaxTTL, err := time.ParseDuration(getEnv("OVERRIDE_MAX_TTL", "24h"))
+	if err != nil || overrideMaxTTL <= 0 {
+		return nil, fmt.Errorf("некорректное значение OVERRIDE_MAX_TTL: %q", getEnv("OVERRIDE_MAX_TTL", "24h"))
+	}
+
+	providers, err := getEnvAsMap("RATE_PROVIDERS")
+	if err != nil {
+		return nil, err
+	}
+	if cbURL := getEnv("CB_API_URL", ""); len(providers) == 0 && cbURL != "" {
+		providers["cbr"] = cbURL
+	}
+
+	weights := make(map[string]float64)
+	rawWeights, err := getEnvAsMap("PROVIDER_WEIGHTS")
+	if err != nil {
+		return nil, err
+	}
+	for provider, value := range rawWeights {
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("некорректный вес поставщика %s: %q", provider, value)
+		}
+		weights[provider] = weight
+	}
+
+	maxDeviation, err := strconv.ParseFloat(getEnv("RATE_MAX_DEVIATION", "0.05"), 64)
+	if err != nil || maxDeviation < 0 {
+		return nil, fmt.Errorf("некорректное значение RATE_MAX_DEVIATION: %q", getEnv("RATE_MAX_DEVIATION", "0.05"))
+	}
+
+	maxJump, err := strconv.ParseFloat(getEnv("RATE_MAX_JUMP", "0.3"), 64)
+	if err != nil || maxJump < 0 {
+		return nil, fmt.Errorf("некорректное значение RATE_MAX_JUMP: %q", getEnv("RATE_MAX_JUMP", "0.3"))
+	}
+
+	baseCurrency := strings.ToUpper(strings.TrimSpace(getEnv("BASE_CURRENCY", "RUB")))
+	if len(baseCurrency) != 3 {
+		return nil, fmt.Errorf("некорректное значение BASE_CURRENCY: %q", baseCurrency)
+	}
+
+	staleAfter, err := time.ParseDuration(getEnv("RATE_STALE_AFTER", "3h"))
+	if err != nil || staleAfter <= 0 {
+		return nil, fmt.Errorf("некорректное значение RATE_STALE_AFTER: %q", getEnv("RATE_STALE_AFTER", "3h"))
+	}
+
+	cryptoAssets, err := getEnvAsMap("CRYPTO_ASSETS")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := os.LookupEnv("CRYPTO_ASSETS"); !ok {
+		cryptoAssets = map[string]string{"BTC": "bitcoin", "ETH": "ethereum"}
+	}
+
+	cryptoInterval, err := time.ParseDuration(getEnv("CRYPTO_UPDATE_INTERVAL", "5m"))
+	if err != nil || cryptoInterval <= 0 {
+		return nil, fmt.Errorf("некорректное значение CRYPTO_UPDATE_INTERVAL: %q", getEnv("CRYPTO_UPDATE_INTERVAL", "5m"))
+	}
+
+	providerTimeout, err := time.ParseDuration(getEnv("PROVIDER_HTTP_TIMEOUT", "15s"))
+	if err != nil || providerTimeout <= 0 {
+		return nil, fmt.Errorf("некорректное значение PROVIDER_HTTP_TIMEOUT: %q", getEnv("PROVIDER_HTTP_TIMEOUT", "15s"))
+	}
+
+	providerConnectTimeout, err := time.ParseDuration(getEnv("PROVIDER_CONNECT_TIMEOUT", "5s"))
+	if err != nil || providerConnectTimeout <= 0 {
+		return nil, fmt.Errorf("некорректное значение PROVIDER_CONNECT_TIMEOUT: %q", getEnv("PROVIDER_CONNECT_TIMEOUT", "5s"))
+	}
+
+	providerMaxResponseSize := getEnvAsInt("PROVIDER_MAX_RESPONSE_SIZE", 1<<20)
+	if providerMaxResponseSize <= 0 {
+		return nil, fmt.Errorf("некорректное значение PROVIDER_MAX_RESPONSE_SIZE: %d", providerMaxResponseSize)
+	}
+
+	snapshotClock, err := time.Parse("15:04", getEnv("SNAPSHOT_TIME", "23:59"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение SNAPSHOT_TIME (ожидается ЧЧ:ММ): %w", err)
+	}
+	snapshotLocation, err := time.LoadLocation(getEnv("SNAPSHOT_TIMEZONE", "Europe/Moscow"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение SNAPSHOT_TIMEZONE: %w", err)
+	}
+
+	intervals := make(map[string]time.Duration)
+	rawIntervals, err := getEnvAsMap("CURRENCY_INTERVALS")
+	if err != nil {
+		return nil, err
+	}
+	for currency, value := range rawIntervals {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("некорректный интервал обновления валюты %s: %q", currency, value)
+		}
+		intervals[strings.ToUpper(currency)] = interval
+	}
+
+	updaterEnabled, err := strconv.ParseBool(getEnv("UPDATER_ENABLED", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение UPDATER_ENABLED: %w", err)
+	}
+	initialDelay, err := time.ParseDuration(getEnv("UPDATER_INITIAL_DELAY", "0s"))
+	if err != nil || initialDelay < 0 {
+		return nil, fmt.Errorf("некорректное значение UPDATER_INITIAL_DELAY: %q", getEnv("UPDATER_INITIAL_DELAY", "0s"))
+	}
+	updaterJitter, err := time.ParseDuration(getEnv("UPDATER_JITTER", "30s"))
+	if err != nil || updaterJitter < 0 {
+		return nil, fmt.Errorf("некорректное значение UPDATER_JITTER: %q", getEnv("UPDATER_JITTER", "30s"))
+	}
+
+	allowlist := getEnvAsList("CURRENCY_ALLOWLIST")
+	for i, currency := range allowlist {
+		allowlist[i] = strings.ToUpper(currency)
+	}
+
+	grpcConfig, err := loadGRPCServerConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	storageDriver := strings.ToLower(getEnv("STORAGE_DRIVER", "postgres"))
+	storageDSN := getEnv("STORAGE_DSN", "")
+	switch storageDriver {
+	case "postgres":
+	case "mysql":
+		if storageDSN == "" {
+			return nil, fmt.Errorf("для STORAGE_DRIVER=mysql необходимо задать STORAGE_DSN")
+		}
+	case "sqlite":
+		if storageDSN == "" {
+			storageDSN = "exchange_rates.db"
+		}
+	default:
+		return nil, fmt.Errorf("некорректное значение STORAGE_DRIVER: %q (допустимо: postgres, mysql, sqlite)", storageDriver)
+	}
+
+	maxOpenConns := getEnvAsInt("DB_MAX_OPEN_CONNS", 10)
+	maxIdleConns := getEnvAsInt("DB_MAX_IDLE_CONNS", 5)
+	if maxOpenConns < 0 || maxIdleConns < 0 || connMaxLifetime < 0 {
+		return nil, fmt.Errorf("параметры пула соединений БД не могут быть отрицательными")
+	}
+
+	return &Config{
+		GRPCPort:             getEnv("GRPC_PORT", "50051"),
+		DBHost:               getEnv("DB_HOST", "localhost"),
+		DBPort:               getEnv("DB_PORT", "5432"),
+		DBUser:               getEnv("DB_USER", "postgres"),
+		DBPassword:           getEnv("DB_PASSWORD", ""),
+		DBName:               getEnv("DB_NAME", "exchange_rates"),
+		StorageDriver:        storageDriver,
+		StorageDSN:           storageDSN,
+		CBAPIURL:             getEnv("CB_API_URL", ""),
+		UpdateInterval:       time.Minute * time.Duration(getEnvAsInt("UPDATE_INTERVAL_MINUTES", 60)),
+		CurrencyAllowlist:    allowlist,
+		CurrencyIntervals:    intervals,
+		UpdaterEnabled:       updaterEnabled,
+		UpdaterInitialDelay:  initialDelay,
+		UpdaterJitter:        updaterJitter,
+		DBQueryTimeout:       queryTimeout,
+		DBMaxOpenConns:       maxOpenConns,
+		DBMaxIdleConns:       maxIdleConns,
+		DBConnMaxLifetime:    connMaxLifetime,
+		ClientTokens:         tokens,
+		ClientQuotas:         quotas,
+		QuotaWindow:          quotaWindow,
+		MetricsAddr:          getEnv("METRICS_ADDR", ":9100"),
+		StatusAddr:           getEnv("STATUS_ADDR", ""),
+		AdminClients:         getEnvAsList("ADMIN_CLIENTS"),
+		OverrideMaxTTL:       overrideMaxTTL,
+		RateProviders:        providers,
+		RateAggregation:      getEnv("RATE_AGGREGATION", "median"),
+		ProviderWeights:      weights,
+		RateMaxDeviation:     maxDeviation,
+		RateMaxJump:          maxJump,
+		ProviderHTTPTimeout:  providerTimeout,
+		ProviderConnTimeout:  providerConnectTimeout,
+		ProviderMaxBodySize:  int64(providerMaxResponseSize),
+		ProviderProxyURL:     getEnv("PROVIDER_PROXY_URL", ""),
+		BaseCurrency:         baseCurrency,
+		RateStaleAfter:       staleAfter,
+		AlertTelegramToken:   getEnv("ALERT_TELEGRAM_TOKEN", ""),
+		AlertTelegramChatID:  getEnv("ALERT_TELEGRAM_CHAT_ID", ""),
+		CryptoAPIURL:         getEnv("CRYPTO_API_URL", "https://api.coingecko.com/api/v3/simple/price"),
+		CryptoAPIKey:         getEnv("CRYPTO_API_KEY", ""),
+		CryptoAssets:         cryptoAssets,
+		CryptoUpdateInterval: cryptoInterval,
+		SnapshotTime:         time.Duration(snapshotClock.Hour())*time.Hour + time.Duration(snapshotClock.Minute())*time.Minute,
+		SnapshotLocation:     snapshotLocation,
+		GRPC:                 grpcConfig,
+	}, nil
+}
+
+// loadGRPCServerConfig загружает параметры gRPC сервера из переменных окружения GRPC_*
+func loadGRPCServerConfig() (GRPCServerConfig, error) {
+	cfg := GRPCServerConfig{
+		MaxRecvMsgSize: getEnvAsInt("GRPC_MAX_RECV_MSG_SIZE", 0),
+		MaxSendMsgSize: getEnvAsInt("GRPC_MAX_SEND_MSG_SIZE", 0),
+	}
+	if cfg.MaxRecvMsgSize < 0 || cfg.MaxSendMsgSize < 0 {
+		return GRPCServerConfig{}, fmt.Errorf("размеры сообщений gRPC не могут быть отрицательными")
+	}
+
+	streams := getEnvAsInt("GRPC_MAX_CONCURRENT_STREAMS", 0)
+	if streams < 0 {
+		return GRPCServerConfig{}, fmt.Errorf("некорректное значение GRPC_MAX_CONCURRENT_STREAMS: %d", streams)
+	}
+	cfg.MaxConcurrentStreams = uint32(streams)
+
+	durations := []struct {
+		name   string
+		target *time.Duration
+	}{
+		{"GRPC_CONNECTION_TIMEOUT", &cfg.ConnectionTimeout},
+		{"GRPC_KEEPALIVE_TIME", &cfg.KeepaliveTime},
+		{"GRPC_KEEPALIVE_TIMEOUT", &cfg.KeepaliveTimeout},
+		{"GRPC_MAX_CONNECTION_IDLE", &cfg.MaxConnectionIdle},
+		{"GRPC_MAX_CONNECTION_AGE", &cfg.MaxConnectionAge},
+		{"GRPC_KEEPALIVE_MIN_TIME", &cfg.KeepaliveMinTime},
+	}
+	for _, d := range durations {
+		value, err := time.ParseDuration(getEnv(d.name, "0s"))
+		if err != nil || value < 0 {
+			return GRPCServerConfig{}, fmt.Errorf("некорректное значение %s: %q", d.name, getEnv(d.name, "0s"))
+		}
+		*d.target = value
+	}
+
+	permit, err := strconv.ParseBool(getEnv("GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM", "false"))
+	if err != nil {
+		return GRPCServerConfig{}, fmt.Errorf("некорректное значение GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM: %w", err)
+	}
+	cfg.KeepalivePermitWithoutStream = permit
+
+	return cfg, nil
+}
+
+// GetDBConnString формирует строку подключения к PostgreSQL
+func (c *Config) GetDBConnString() string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		c.DBHost, c.DBPort, c.DBUser, c.DBPassword, c.DBName,
+	)
+}
+
+// getEnv возвращает значение переменной окружения или значение по умолчанию
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsInt получает переменную окружения как целое число
+// Возвращает значение по умолчанию, если переменная не задана или невалидна
+func getEnvAsInt(name string, defaultValue int) int {
+	if value, err := strconv.Atoi(getEnv(name, "")); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsList разбирает переменную окружения формата "значение,значение"
+// Пустые элементы пропускаются
+func getEnvAsList(name string) []string {
+	var result []string
+	for _, item := range strings.Split(getEnv(name, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvAsMap разбирает переменную окружения формата "ключ:значение,ключ:значение"
+// Пустые элементы пропускаются, элемент без двоеточия считается ошибкой
+func getEnvAsMap(name string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, item := range strings.Split(getEnv(name, ""), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("некорректный элемент %q в переменной %s", item, name)
+		}
+		result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return result, nil
+}
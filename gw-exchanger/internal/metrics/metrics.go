@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"          // Клиент Prometheus
+	"github.com/prometheus/client_golang/prometheus/promauto" // Автоматическая регистрация метрик
+	"github.com/prometheus/client_golang/prometheus/promhttp" // HTTP обработчик для /metrics
+	"log"
+	"net/http"
+	"time"
+)
+
+// Метрики сервиса обмена валют
+var (
+	// RejectedCalls - количество отклоненных gRPC вызовов
+	// Метки: client - имя клиента (или "unknown"), method - полный метод gRPC,
+	// reason - причина отказа (unauthenticated, quota_exceeded)
+	RejectedCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "exchanger_grpc_rejected_calls_total",
+		Help: "Количество отклоненных gRPC вызовов по клиентам и причинам",
+	}, []string{"client", "method", "reason"})
+
+	// AcceptedCalls - количество принятых gRPC вызовов по клиентам
+	AcceptedCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "exchanger_grpc_accepted_calls_total",
+		Help: "Количество принятых gRPC вызовов по клиентам",
+	}, []string{"client", "method"})
+
+	// RateAge - возраст опубликованного курса валюты в секундах
+	RateAge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "exchanger_rate_age_seconds",
+		Help: "Время с последнего обновления опубликованного курса валюты",
+	}, []string{"currency"})
+
+	// QuarantinedRates - количество курсов поставщиков, отправленных на карантин из-за резкого скачка
+	QuarantinedRates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "exchanger_rate_quarantined_total",
+		Help: "Количество курсов поставщиков, отправленных на карантин",
+	}, []string{"currency", "source"})
+
+	// RateStoreDuration - время записи курсов источника в БД (одна транзакция на обновление)
+	RateStoreDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "exchanger_rate_store_duration_seconds",
+		Help:    "Время записи курсов источника в БД",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 12),
+	}, []string{"source"})
+
+	// RateWrites - количество записанных курсов при обновлении
+	// Метки: source - источник, result - changed (курс изменился и перезаписан), unchanged (только подтвержден)
+	RateWrites = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "exchanger_rate_writes_total",
+		Help: "Количество курсов, полученных при обновлении, по результату записи",
+	}, []string{"source", "result"})
+
+	// StaleRates - количество валют, курс которых не обновлялся дольше RATE_STALE_AFTER
+	StaleRates = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "exchanger_stale_rates",
+		Help: "Количество устаревших курсов валют",
+	})
+)
+
+// Serve запускает HTTP сервер с эндпоинтом /metrics (блокирующая операция)
+// Если адрес пустой, метрики не публикуются
+func Serve(addr string) {
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	log.Printf("Метрики доступны на %s/metrics", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Ошибка сервера метрик: %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	storages "gw-exchanger/internal/storage"
+	"gw-proto/proto"
+	"log"
+	"strings"
+	"time"
+)
+
+// RateAdminServer реализует административный gRPC сервис ручных курсов валют
+// Доступ к сервису ограничивается в clientAuth списком ADMIN_CLIENTS
+type RateAdminServer struct {
+	proto.UnimplementedRateAdminServiceServer                    // Обязательная встроенная реализация
+	storage                                   storages.RateAdmin // Хранилище данных (PostgreSQL)
+	maxTTL                                    time.Duration      // Максимальный срок действия ручного курса
+}
+
+// NewRateAdminServer создает административный сервис ручных курсов
+// Параметры:
+//   - storage: подключение к хранилищу данных
+//   - maxTTL: максимальный срок действия ручного курса
+//
+// Возвращает:
+//   - *RateAdminServer: готовый к работе сервис
+func NewRateAdminServer(storage storages.RateAdmin, maxTTL time.Duration) *RateAdminServer {
+	return &RateAdminServer{storage: storage, maxTTL: maxTTL}
+}
+
+// SetRateOverride устанавливает ручной курс валюты на ограниченное время
+// Ручной курс имеет наивысший приоритет и заменяет курсы поставщиков до истечения срока
+// Параметры:
+//   - ctx: контекст выполнения (содержит имя клиента-администратора)
+//   - req: валюта, курс, время действия в секундах и причина
+//
+// Возвращает:
+//   - *proto.RateOverride: установленный курс
+//   - error: InvalidArgument при некорректных параметрах, NotFound для неизвестной валюты
+func (s *RateAdminServer) SetRateOverride(ctx context.Context, req *proto.SetRateOverrideRequest) (*proto.RateOverride, error) {
+	currency := normalizeCurrency(req.Currency)
+	if currency == "" {
+		return nil, status.Error(codes.InvalidArgument, "не указана валюта")
+	}
+	if currency == s.storage.BaseCurrency() {
+		return nil, status.Errorf(codes.InvalidArgument, "курс базовой валюты %s всегда равен 1", currency)
+	}
+	if req.Rate <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "курс должен быть положительным")
+	}
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	if req.TtlSeconds <= 0 || ttl > s.maxTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "время действия должно быть от 1 секунды до %s", s.maxTTL)
+	}
+
+	actor := ClientFromContext(ctx)
+	override, err := s.storage.SetOverride(ctx, currency, req.Rate, time.Now().Add(ttl), actor, req.Reason)
+	if errors.Is(err, storages.ErrUnknownCurrency) {
+		return nil, status.Errorf(codes.NotFound, "валюта %s не поставляется ни одним источником", currency)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка установки ручного курса: %v", err)
+	}
+
+	log.Printf("Ручной курс %s = %f установлен клиентом %s до %s (%s) request_id=%s",
+		currency, req.Rate, actor, override.ExpiresAt.Format(time.RFC3339), req.Reason, CorrelationIDFromContext(ctx))
+	return overrideToProto(override), nil
+}
+
+// ClearRateOverride досрочно отменяет ручной курс валюты
+// Параметры:
+//   - ctx: контекст выполнения (содержит имя клиента-администратора)
+//   - req: валюта и причина отмены
+//
+// Возвращает:
+//   - *proto.RateOverride: отмененный курс
+//   - error: InvalidArgument без валюты, NotFound при отсутствии действующего ручного курса
+func (s *RateAdminServer) ClearRateOverride(ctx context.Context, req *proto.ClearRateOverrideRequest) (*proto.RateOverride, error) {
+	currency := normalizeCurrency(req.Currency)
+	if currency == "" {
+		return nil, status.Error(codes.InvalidArgument, "не указана валюта")
+	}
+
+	actor := ClientFromContext(ctx)
+	override, err := s.storage.ClearOverride(ctx, currency, actor, req.Reason)
+	if errors.Is(err, storages.ErrOverrideNotFound) {
+		return nil, status.Errorf(codes.NotFound, "действующий ручной курс %s не найден", currency)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка отмены ручного курса: %v", err)
+	}
+
+	log.Printf("Ручной курс %s отменен клиентом %s (%s) request_id=%s", currency, actor, req.Reason, CorrelationIDFromContext(ctx))
+	return overrideToProto(override), nil
+}
+
+// ListRateOverrides возвращает действующие ручные курсы
+func (s *RateAdminServer) ListRateOverrides(ctx context.Context, req *proto.Empty) (*proto.RateOverridesResponse, error) {
+	overrides, err := s.storage.ListOverrides(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка получения ручных курсов: %v", err)
+	}
+
+	response := &proto.RateOverridesResponse{Overrides: make([]*proto.RateOverride, 0, len(overrides))}
+	for _, override := range overrides {
+		response.Overrides = append(response.Overrides, overrideToProto(override))
+	}
+	return response, nil
+}
+
+// ListQuarantinedRates возвращает курсы поставщиков на карантине, ожидающие решения
+func (s *RateAdminServer) ListQuarantinedRates(ctx context.Context, req *proto.Empty) (*proto.QuarantinedRatesResponse, error) {
+	quarantined, err := s.storage.ListQuarantine(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка получения курсов на карантине: %v", err)
+	}
+
+	response := &proto.QuarantinedRatesResponse{Rates: make([]*proto.QuarantinedRate, 0, len(quarantined))}
+	for _, rate := range quarantined {
+		response.Rates = append(response.Rates, quarantineToProto(rate))
+	}
+	return response, nil
+}
+
+// ResolveQuarantinedRate принимает или отклоняет курс на карантине
+// Принятый курс сразу публикуется как курс поставщика и становится основой следующих проверок
+// Параметры:
+//   - ctx: контекст выполнения (содержит имя клиента-администратора)
+//   - req: идентификатор записи, решение и причина
+//
+// Возвращает:
+//   - *proto.QuarantinedRate: запись карантина после решения
+//   - error: InvalidArgument без идентификатора, NotFound, если запись не ожидает решения
+func (s *RateAdminServer) ResolveQuarantinedRate(ctx context.Context, req *proto.ResolveQuarantinedRateRequest) (*proto.QuarantinedRate, error) {
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "не указан идентификатор курса на карантине")
+	}
+
+	actor := ClientFromContext(ctx)
+	resolved, err := s.storage.ResolveQuarantine(ctx, req.Id, req.Approve, actor, req.Reason)
+	if errors.Is(err, storages.ErrQuarantineNotFound) {
+		return nil, status.Errorf(codes.NotFound, "курс на карантине %d не найден или решение уже принято", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка решения по курсу на карантине: %v", err)
+	}
+
+	log.Printf("Решение по курсу %s (%s) = %f на карантине: %s клиентом %s (%s) request_id=%s",
+		resolved.Currency, resolved.Source, resolved.Rate, resolved.Status, actor, req.Reason, CorrelationIDFromContext(ctx))
+	return quarantineToProto(resolved), nil
+}
+
+// normalizeCurrency приводит код валюты к виду, в котором он хранится в БД
+func normalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// overrideToProto конвертирует ручной курс хранилища в gRPC сообщение
+func overrideToProto(override storages.RateOverride) *proto.RateOverride {
+	var expiresAt int64
+	if !override.ExpiresAt.IsZero() {
+		expiresAt = override.ExpiresAt.Unix()
+	}
+	return &proto.RateOverride{
+		Currency:  override.Currency,
+		Rate:      override.Rate,
+		ExpiresAt: expiresAt,
+		SetBy:     override.SetBy,
+	}
+}
+
+// quarantineToProto конвертирует курс на карантине в gRPC сообщение
+func quarantineToProto(rate storages.QuarantinedRate) *proto.QuarantinedRate {
+	return &proto.QuarantinedRate{
+		Id:           rate.ID,
+		Currency:     rate.Currency,
+		Source:       rate.Source,
+		PreviousRate: rate.PreviousRate,
+		Rate:         rate.Rate,
+		Deviation:    rate.Deviation,
+		Status:       rate.Status,
+		CreatedAt:    rate.CreatedAt.Unix(),
+		UpdatedAt:    rate.UpdatedAt.Unix(),
+		ResolvedBy:   rate.ResolvedBy,
+		Reason:       rate.Reason,
+	}
+}
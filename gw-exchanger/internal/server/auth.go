@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gw-exchanger/internal/metrics"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultQuotaKey - ключ квоты, применяемой к клиентам без персональной квоты
+const defaultQuotaKey = "*"
+
+// adminMethodPrefix - префикс административных методов, доступных только клиентам из ADMIN_CLIENTS
+const adminMethodPrefix = "/exchange.RateAdminService/"
+
+// clientKey - ключ контекста, под которым хранится имя аутентифицированного клиента
+type clientKey struct{}
+
+// ClientFromContext возвращает имя клиента, прошедшего аутентификацию
+// Возвращает "unknown", если аутентификация отключена или клиент не определен
+func ClientFromContext(ctx context.Context) string {
+	if client, ok := ctx.Value(clientKey{}).(string); ok {
+		return client
+	}
+	return "unknown"
+}
+
+// clientAuth проверяет API токены клиентов и соблюдение квот запросов
+type clientAuth struct {
+	tokens map[string]string   // Токен -> имя клиента
+	admins map[string]struct{} // Клиенты с доступом к административным методам
+	quotas *quotaLimiter       // Ограничитель количества запросов
+}
+
+// newClientAuth создает проверку клиентов
+// Параметры:
+//   - clientTokens: имя клиента -> API токен (пустая карта отключает аутентификацию)
+//   - quotas: имя клиента -> лимит запросов за окно ("*" - для остальных, 0 - без ограничений)
+//   - window: длительность окна подсчета квот
+//   - adminClients: клиенты с доступом к административным методам
+func newClientAuth(clientTokens map[string]string, quotas map[string]int, window time.Duration, adminClients []string) *clientAuth {
+	tokens := make(map[string]string, len(clientTokens))
+	for client, token := range clientTokens {
+		tokens[token] = client
+	}
+	admins := make(map[string]struct{}, len(adminClients))
+	for _, client := range adminClients {
+		admins[client] = struct{}{}
+	}
+	return &clientAuth{
+		tokens: tokens,
+		admins: admins,
+		quotas: newQuotaLimiter(quotas, window),
+	}
+}
+
+// authorize определяет клиента по токену из метаданных и проверяет его квоту
+// Административные методы доступны только аутентифицированным клиентам из списка администраторов
+// Возвращает контекст с именем клиента или gRPC ошибку Unauthenticated/PermissionDenied/ResourceExhausted
+func (a *clientAuth) authorize(ctx context.Context, method string) (context.Context, error) {
+	client := "unknown"
+
+	// Аутентификация включена только при наличии настроенных токенов
+	if len(a.tokens) > 0 {
+		token := tokenFromMetadata(ctx)
+		name, ok := a.lookup(token)
+		if !ok {
+			metrics.RejectedCalls.WithLabelValues(client, method, "unauthenticated").Inc()
+			return nil, status.Error(codes.Unauthenticated, "неверный или отсутствующий API токен")
+		}
+		client = name
+	}
+
+	// Без аутентификации клиент не определен, поэтому административные методы недоступны
+	if strings.HasPrefix(method, adminMethodPrefix) && !a.isAdmin(client) {
+		metrics.RejectedCalls.WithLabelValues(client, method, "permission_denied").Inc()
+		return nil, status.Error(codes.PermissionDenied, "метод доступен только администраторам")
+	}
+
+	if !a.quotas.allow(client) {
+		metrics.RejectedCalls.WithLabelValues(client, method, "quota_exceeded").Inc()
+		return nil, status.Error(codes.ResourceExhausted, "превышена квота запросов для клиента "+client)
+	}
+
+	metrics.AcceptedCalls.WithLabelValues(client, method).Inc()
+	return context.WithValue(ctx, clientKey{}, client), nil
+}
+
+// isAdmin сообщает, есть ли клиент в списке администраторов
+func (a *clientAuth) isAdmin(client string) bool {
+	if len(a.tokens) == 0 {
+		return false
+	}
+	_, ok := a.admins[client]
+	return ok
+}
+
+// lookup ищет клиента по токену, сравнивая токены за постоянное время
+func (a *clientAuth) lookup(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+	for known, client := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			return client, true
+		}
+	}
+	return "", false
+}
+
+// UnaryInterceptor возвращает перехватчик для унарных вызовов
+func (a *clientAuth) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor возвращает перехватчик для потоковых вызовов
+func (a *clientAuth) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// tokenFromMetadata извлекает токен из заголовка authorization ("Bearer <token>")
+func tokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+}
+
+// wrappedStream подменяет контекст потока, чтобы передать имя клиента обработчику
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст с данными аутентификации
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+// quotaLimiter ограничивает количество запросов клиента в фиксированном окне времени
+type quotaLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]int          // Лимиты по клиентам
+	window  time.Duration           // Длительность окна
+	windows map[string]*quotaWindow // Текущие окна по клиентам
+}
+
+// quotaWindow - счетчик запросов клиента в текущем окне
+type quotaWindow struct {
+	start time.Time // Начало окна
+	count int       // Количество запросов в окне
+}
+
+// newQuotaLimiter создает ограничитель с указанными лимитами
+func newQuotaLimiter(limits map[string]int, window time.Duration) *quotaLimiter {
+	if window <= 0 {
+		window = time.Minute
+	}
+	return &quotaLimiter{
+		limits:  limits,
+		window:  window,
+		windows: make(map[string]*quotaWindow),
+	}
+}
+
+// allow учитывает запрос клиента и сообщает, укладывается ли он в квоту
+func (q *quotaLimiter) allow(client string) bool {
+	limit, ok := q.limits[client]
+	if !ok {
+		limit = q.limits[defaultQuotaKey]
+	}
+	if limit <= 0 {
+		return true // Квота не задана - ограничений нет
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	w, ok := q.windows[client]
+	if !ok || now.Sub(w.start) >= q.window {
+		w = &quotaWindow{start: now}
+		q.windows[client] = w
+	}
+	if w.count >= limit {
+		return false
+	}
+	w.count++
+	return true
+}
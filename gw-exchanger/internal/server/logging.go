@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"log"
+	"regexp"
+	"time"
+)
+
+// correlationHeader - ключ метаданных с идентификатором запроса (совпадает с X-Request-ID кошелька)
+const correlationHeader = "x-request-id"
+
+// validCorrelationID ограничивает идентификаторы, принимаемые от клиента (защита журнала от мусора)
+var validCorrelationID = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,64}$`)
+
+// correlationKey - ключ контекста, под которым хранится идентификатор запроса
+type correlationKey struct{}
+
+// CorrelationIDFromContext возвращает идентификатор запроса (пустая строка, если перехватчик не подключен)
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// withCorrelationID берет идентификатор запроса из метаданных или генерирует новый
+// Возвращает контекст с идентификатором и сам идентификатор
+func withCorrelationID(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(correlationHeader); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if !validCorrelationID.MatchString(id) {
+		id = newCorrelationID()
+	}
+	return context.WithValue(ctx, correlationKey{}, id), id
+}
+
+// newCorrelationID генерирует случайный идентификатор из 16 байт в hex
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// logCall записывает в журнал метод, длительность, статус и идентификатор вызова
+func logCall(method string, start time.Time, err error, id string) {
+	log.Printf("gRPC %s status=%s duration=%s request_id=%s",
+		method, status.Code(err), time.Since(start).Round(time.Microsecond), id)
+}
+
+// LoggingUnaryInterceptor журналирует унарные вызовы и возвращает идентификатор запроса в заголовке ответа
+// Подключается первым, чтобы в журнал попадали и вызовы, отклоненные проверкой клиента
+func LoggingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx, id := withCorrelationID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(correlationHeader, id))
+
+		resp, err := handler(ctx, req)
+		logCall(info.FullMethod, start, err, id)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor журналирует потоковые вызовы и возвращает идентификатор запроса в заголовке ответа
+func LoggingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, id := withCorrelationID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(correlationHeader, id))
+
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		logCall(info.FullMethod, start, err, id)
+		return err
+	}
+}
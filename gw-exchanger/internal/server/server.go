@@ -2,52 +2,101 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"google.golang.org/grpc"                 // Фреймворк для работы с gRPC
-	"gw-exchanger/internal/storage/postgres" // Реализация хранилища данных
+	"google.golang.org/grpc/codes"           // Коды ошибок gRPC
+	"google.golang.org/grpc/keepalive"       // Параметры keepalive соединений
+	"google.golang.org/grpc/status"          // Статусы ошибок gRPC
+	"gw-exchanger/internal/api"              // Пересчет курсов к базовой валюте
+	"gw-exchanger/internal/config"           // Конфигурация сервиса
+	"gw-exchanger/internal/staleness"        // Отслеживание устаревания курсов
+	storages "gw-exchanger/internal/storage" // Модели хранилища
+	"gw-exchanger/internal/updater"          // Обновление курсов от поставщиков
 	"gw-proto/proto"                         // Сгенерированный Protobuf код
 	"log"
 	"net"
+	"time"
+)
+
+// maxRateChanges - максимальное количество изменений курса в одном ответе GetRateChanges
+const maxRateChanges = 1000
+
+// Ограничения запроса свечей GetRateCandles
+const (
+	minCandleInterval = time.Minute // Минимальная длительность свечи
+	maxRateCandles    = 1000        // Максимальное количество свечей в одном ответе
 )
 
 // ExchangeServer реализует gRPC сервис для работы с курсами валют
 type ExchangeServer struct {
-	proto.UnimplementedExchangeServiceServer                           // Обязательная встроенная реализация
-	storage                                  *postgres.PostgresStorage // Хранилище данных (PostgreSQL)
+	proto.UnimplementedExchangeServiceServer                      // Обязательная встроенная реализация
+	storage                                  storages.Storage     // Хранилище данных (PostgreSQL, MySQL или SQLite)
+	updates                                  *updater.RateUpdater // Обновление курсов (состояние обновлений)
+	monitor                                  *staleness.Monitor   // Монитор устаревания курсов (флаг degraded)
 }
 
+// errUnsupported - ошибка вызова возможности, которую не поддерживает выбранное хранилище (STORAGE_DRIVER)
+var errUnsupported = status.Error(codes.Unimplemented, "не поддерживается хранилищем курсов")
+
 // NewServer создает новый экземпляр gRPC сервера
 // Параметры:
-//   - storage: подключение к хранилищу данных
+//   - storage: подключение к хранилищу данных (вызовы возможностей, которых у хранилища нет, отклоняются с Unimplemented)
+//   - updates: обновление курсов от поставщиков (источник GetUpdateStatus)
+//   - monitor: монитор устаревания курсов (nil - ответы не помечаются флагом degraded)
 //
 // Возвращает:
 //   - *ExchangeServer: готовый к работе сервер
-func NewServer(storage *postgres.PostgresStorage) *ExchangeServer {
-	return &ExchangeServer{storage: storage}
+func NewServer(storage storages.Storage, updates *updater.RateUpdater, monitor *staleness.Monitor) *ExchangeServer {
+	return &ExchangeServer{storage: storage, updates: updates, monitor: monitor}
 }
 
 // GetExchangeRates возвращает все текущие курсы валют
+// Курсы пересчитываются к запрошенной базовой валюте на сервере: курс валюты делится на курс новой базы
 // Параметры:
 //   - ctx: контекст выполнения
-//   - req: пустой запрос (proto.Empty)
+//   - req: базовая валюта ответа (пусто - базовая валюта сервиса)
 //
 // Возвращает:
 //   - *proto.ExchangeRatesResponse: список всех курсов валют
-//   - error: ошибка при получении данных
-func (s *ExchangeServer) GetExchangeRates(ctx context.Context, req *proto.Empty) (*proto.ExchangeRatesResponse, error) {
+//   - error: InvalidArgument, если курса запрошенной базовой валюты нет, или ошибка при получении данных
+func (s *ExchangeServer) GetExchangeRates(ctx context.Context, req *proto.ExchangeRatesRequest) (*proto.ExchangeRatesResponse, error) {
 	// Получаем курсы из хранилища
 	rates, err := s.storage.GetAllRates(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения курсов: %v", err)
 	}
 
-	// Конвертируем map[string]float64 в map[string]float32 для gRPC
-	response := make(map[string]float32, len(rates))
+	// Точные курсы и источники, курс базовой валюты сервиса равен 1
+	base := s.storage.BaseCurrency()
+	precise := map[string]float64{base: 1}
+	sources := make(map[string]string, len(rates))
 	for currency, rate := range rates {
+		precise[currency] = rate.Rate
+		sources[currency] = rate.Source
+	}
+
+	// Пересчитываем курсы к запрошенной базовой валюте
+	if requested := normalizeCurrency(req.GetBaseCurrency()); requested != "" && requested != base {
+		if precise, err = api.Rebase(precise, base, requested); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "нет курса базовой валюты %s", requested)
+		}
+		base = requested
+	}
+
+	// Конвертируем курсы в map[string]float32 для gRPC, точные курсы передаются отдельно
+	response := make(map[string]float32, len(precise))
+	for currency, rate := range precise {
 		response[currency] = float32(rate)
 	}
 
-	return &proto.ExchangeRatesResponse{Rates: response}, nil
+	return &proto.ExchangeRatesResponse{
+		Rates:        response,
+		Sources:      sources,
+		Degraded:     s.monitor.Degraded(),
+		BaseCurrency: base,
+		PreciseRates: precise,
+	}, nil
 }
 
 // GetExchangeRateForCurrency возвращает курс для конкретной пары валют
@@ -60,7 +109,7 @@ func (s *ExchangeServer) GetExchangeRates(ctx context.Context, req *proto.Empty)
 //   - error: ошибка при получении данных
 func (s *ExchangeServer) GetExchangeRateForCurrency(ctx context.Context, req *proto.CurrencyRequest) (*proto.ExchangeRateResponse, error) {
 	// Получаем курс из хранилища
-	rate, err := s.storage.GetRate(ctx, req.FromCurrency, req.ToCurrency)
+	quote, err := s.storage.GetRate(ctx, req.FromCurrency, req.ToCurrency)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения курса: %v", err)
 	}
@@ -68,31 +117,372 @@ func (s *ExchangeServer) GetExchangeRateForCurrency(ctx context.Context, req *pr
 	return &proto.ExchangeRateResponse{
 		FromCurrency: req.FromCurrency,
 		ToCurrency:   req.ToCurrency,
-		Rate:         float32(rate),
+		Rate:         float32(quote.Rate),
+		FromSource:   quote.FromSource,
+		ToSource:     quote.ToSource,
+		Degraded:     s.monitor.Degraded(),
+		PreciseRate:  quote.Rate,
 	}, nil
 }
 
-// Start запускает gRPC сервер на указанном порту
+// GetRateChanges возвращает историю изменений курса валюты за период
+// Параметры:
+//   - ctx: контекст выполнения
+//   - req: валюта и период (Unix timestamp, to = 0 - текущий момент)
+//
+// Возвращает:
+//   - *proto.RateChangesResponse: изменения курса в порядке времени (не более maxRateChanges)
+//   - error: InvalidArgument при некорректном периоде или ошибка получения данных
+func (s *ExchangeServer) GetRateChanges(ctx context.Context, req *proto.RateChangesRequest) (*proto.RateChangesResponse, error) {
+	currency := normalizeCurrency(req.Currency)
+	if currency == "" {
+		return nil, status.Error(codes.InvalidArgument, "не указана валюта")
+	}
+
+	from := time.Unix(req.From, 0)
+	to := time.Now()
+	if req.To != 0 {
+		to = time.Unix(req.To, 0)
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "начало периода должно быть раньше конца")
+	}
+
+	// Запрашиваем на одну запись больше лимита, чтобы определить усечение ответа
+	changes, err := s.storage.GetRateChanges(ctx, currency, from, to, maxRateChanges+1)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения изменений курса: %v", err)
+	}
+
+	response := &proto.RateChangesResponse{}
+	if len(changes) > maxRateChanges {
+		changes = changes[:maxRateChanges]
+		response.Truncated = true
+	}
+	for _, change := range changes {
+		response.Changes = append(response.Changes, &proto.RateChange{
+			Currency:  change.Currency,
+			Source:    change.Source,
+			OldRate:   change.OldRate,
+			NewRate:   change.NewRate,
+			ChangedAt: change.ChangedAt.Unix(),
+		})
+	}
+	return response, nil
+}
+
+// defaultStatsWindow - период статистики курса, если начало периода не указано
+const defaultStatsWindow = 24 * time.Hour
+
+// GetRateHistoryStats возвращает статистику курса пары валют за период
+// Параметры:
+//   - ctx: контекст выполнения
+//   - req: пара валют и период (Unix timestamp, по умолчанию - последние 24 часа)
+//
+// Возвращает:
+//   - *proto.RateHistoryStatsResponse: минимум, максимум, среднее и изменение курса
+//   - error: InvalidArgument при некорректных параметрах, NotFound при отсутствии истории
+func (s *ExchangeServer) GetRateHistoryStats(ctx context.Context, req *proto.RateHistoryStatsRequest) (*proto.RateHistoryStatsResponse, error) {
+	from, to := normalizeCurrency(req.FromCurrency), normalizeCurrency(req.ToCurrency)
+	if from == "" || to == "" {
+		return nil, status.Error(codes.InvalidArgument, "не указаны валюты")
+	}
+
+	end := time.Now()
+	if req.To != 0 {
+		end = time.Unix(req.To, 0)
+	}
+	start := end.Add(-defaultStatsWindow)
+	if req.From != 0 {
+		start = time.Unix(req.From, 0)
+	}
+	if !start.Before(end) {
+		return nil, status.Error(codes.InvalidArgument, "начало периода должно быть раньше конца")
+	}
+
+	history, ok := s.storage.(storages.RateHistory)
+	if !ok {
+		return nil, errUnsupported
+	}
+	stats, err := history.GetRateStats(ctx, from, to, start, end)
+	if errors.Is(err, storages.ErrNoRateHistory) {
+		return nil, status.Errorf(codes.NotFound, "нет истории курса %s/%s за период", from, to)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статистики курса: %v", err)
+	}
+
+	return &proto.RateHistoryStatsResponse{
+		FromCurrency:  from,
+		ToCurrency:    to,
+		From:          stats.Start.Unix(),
+		To:            stats.End.Unix(),
+		Open:          stats.Open,
+		Close:         stats.Close,
+		Min:           stats.Min,
+		Max:           stats.Max,
+		Avg:           stats.Avg,
+		ChangePercent: stats.ChangePercent,
+		Changes:       int32(stats.Changes),
+	}, nil
+}
+
+// GetRateCandles возвращает свечи курса пары валют за период
+// Параметры:
+//   - ctx: контекст выполнения
+//   - req: пара валют, период (Unix timestamp) и длительность свечи
+//
+// Возвращает:
+//   - *proto.RateCandlesResponse: свечи в порядке времени
+//   - error: InvalidArgument при некорректных параметрах, NotFound при отсутствии истории
+func (s *ExchangeServer) GetRateCandles(ctx context.Context, req *proto.RateCandlesRequest) (*proto.RateCandlesResponse, error) {
+	from, to := normalizeCurrency(req.FromCurrency), normalizeCurrency(req.ToCurrency)
+	if from == "" || to == "" {
+		return nil, status.Error(codes.InvalidArgument, "не указаны валюты")
+	}
+
+	interval := time.Duration(req.IntervalSeconds) * time.Second
+	if interval < minCandleInterval {
+		return nil, status.Errorf(codes.InvalidArgument, "длительность свечи должна быть не меньше %s", minCandleInterval)
+	}
+	end := time.Now()
+	if req.To != 0 {
+		end = time.Unix(req.To, 0)
+	}
+	start := time.Unix(req.From, 0)
+	if !start.Before(end) {
+		return nil, status.Error(codes.InvalidArgument, "начало периода должно быть раньше конца")
+	}
+	if end.Sub(start.Truncate(interval)) > interval*maxRateCandles {
+		return nil, status.Errorf(codes.InvalidArgument, "период не может содержать больше %d свечей", maxRateCandles)
+	}
+
+	history, ok := s.storage.(storages.RateHistory)
+	if !ok {
+		return nil, errUnsupported
+	}
+	candles, err := history.GetRateCandles(ctx, from, to, start, end, interval)
+	if errors.Is(err, storages.ErrNoRateHistory) {
+		return nil, status.Errorf(codes.NotFound, "нет истории курса %s/%s за период", from, to)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения свечей курса: %v", err)
+	}
+
+	response := &proto.RateCandlesResponse{
+		FromCurrency:    from,
+		ToCurrency:      to,
+		IntervalSeconds: req.IntervalSeconds,
+		Candles:         make([]*proto.RateCandle, 0, len(candles)),
+	}
+	for _, candle := range candles {
+		response.Candles = append(response.Candles, &proto.RateCandle{
+			Start: candle.Start.Unix(),
+			Open:  candle.Open,
+			High:  candle.High,
+			Low:   candle.Low,
+			Close: candle.Close,
+		})
+	}
+	return response, nil
+}
+
+// GetDailySnapshot возвращает снимок курсов на конец дня
+// Параметры:
+//   - ctx: контекст выполнения
+//   - req: дата снимка (YYYY-MM-DD), пустая - последний снимок
+//
+// Возвращает:
+//   - *proto.DailySnapshotResponse: курсы закрытия с источниками
+//   - error: InvalidArgument при некорректной дате, NotFound при отсутствии снимка
+func (s *ExchangeServer) GetDailySnapshot(ctx context.Context, req *proto.DailySnapshotRequest) (*proto.DailySnapshotResponse, error) {
+	var date time.Time
+	if req.Date != "" {
+		var err error
+		if date, err = time.Parse(time.DateOnly, req.Date); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "дата должна быть в формате YYYY-MM-DD")
+		}
+	}
+
+	snapshots, ok := s.storage.(storages.SnapshotStore)
+	if !ok {
+		return nil, errUnsupported
+	}
+	snapshot, err := snapshots.GetSnapshot(ctx, date)
+	if errors.Is(err, storages.ErrSnapshotNotFound) {
+		return nil, status.Error(codes.NotFound, "снимок курсов не найден")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения снимка курсов: %v", err)
+	}
+
+	response := &proto.DailySnapshotResponse{
+		Date:         snapshot.Date.Format(time.DateOnly),
+		BaseCurrency: snapshot.BaseCurrency,
+		Rates:        make(map[string]float64, len(snapshot.Rates)),
+		Sources:      make(map[string]string, len(snapshot.Rates)),
+		TakenAt:      snapshot.TakenAt.Unix(),
+	}
+	for currency, rate := range snapshot.Rates {
+		response.Rates[currency] = rate.Rate
+		response.Sources[currency] = rate.Source
+	}
+	return response, nil
+}
+
+// GetCurrencies возвращает справочник валют
 // Параметры:
-//   - port: порт для прослушивания (например "50051")
+//   - ctx: контекст выполнения
+//   - req: пустой запрос (proto.Empty)
+//
+// Возвращает:
+//   - *proto.CurrenciesResponse: валюты с названием, номиналом и количеством знаков после запятой
+//   - error: ошибка при получении данных
+func (s *ExchangeServer) GetCurrencies(ctx context.Context, req *proto.Empty) (*proto.CurrenciesResponse, error) {
+	catalog, ok := s.storage.(storages.CurrencyCatalog)
+	if !ok {
+		return nil, errUnsupported
+	}
+	currencies, err := catalog.ListCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения справочника валют: %v", err)
+	}
+
+	response := &proto.CurrenciesResponse{Currencies: make([]*proto.CurrencyInfo, 0, len(currencies))}
+	for _, currency := range currencies {
+		response.Currencies = append(response.Currencies, &proto.CurrencyInfo{
+			Code:      currency.Code,
+			Name:      currency.Name,
+			Nominal:   int32(currency.Nominal),
+			Decimals:  int32(currency.Decimals),
+			UpdatedAt: currency.UpdatedAt.Unix(),
+		})
+	}
+	return response, nil
+}
+
+// GetUpdateStatus возвращает состояние фоновых обновлений курсов с момента запуска сервиса
+// Используется проверками готовности и мониторингом клиентов: по нему видно, когда курсы последний раз
+// обновлялись успешно и почему не удается обновление
+// Параметры:
+//   - ctx: контекст выполнения
+//   - req: пустой запрос (proto.Empty)
+//
+// Возвращает:
+//   - *proto.UpdateStatusResponse: состояние обновлений и флаг устаревания курсов
+//   - error: всегда nil (состояние хранится в памяти)
+func (s *ExchangeServer) GetUpdateStatus(ctx context.Context, req *proto.Empty) (*proto.UpdateStatusResponse, error) {
+	updates := s.updates.UpdateStatuses()
+	response := &proto.UpdateStatusResponse{
+		Updates:  make([]*proto.UpdateStatus, 0, len(updates)),
+		Degraded: s.monitor.Degraded(),
+	}
+	for _, update := range updates {
+		var lastSuccess int64
+		if !update.LastSuccess.IsZero() {
+			lastSuccess = update.LastSuccess.Unix()
+		}
+		response.Updates = append(response.Updates, &proto.UpdateStatus{
+			Name:        update.Name,
+			LastRun:     update.LastRun.Unix(),
+			LastSuccess: lastSuccess,
+			LastError:   update.LastError,
+			Source:      update.Source,
+			Currencies:  int32(update.Currencies),
+			Runs:        update.Runs,
+			Errors:      update.Errors,
+		})
+	}
+	return response, nil
+}
+
+// Start запускает gRPC сервер на порту из конфигурации
+// Блокирует выполнение до остановки сервера; при отмене ctx сервер
+// дожидается завершения активных вызовов и останавливается
+// Параметры:
+//   - ctx: контекст жизни сервера
+//   - cfg: конфигурация сервиса (порт, токены и квоты клиентов)
 //   - storage: подключение к хранилищу данных
-func Start(port string, storage *postgres.PostgresStorage) {
+//   - updates: обновление курсов от поставщиков
+//   - monitor: монитор устаревания курсов
+func Start(ctx context.Context, cfg *config.Config, storage storages.Storage, updates *updater.RateUpdater, monitor *staleness.Monitor) {
+	port := cfg.GRPCPort
+
 	// Создаем TCP listener на указанном порту
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		log.Fatalf("ошибка запуска сервера: %v", err)
 	}
 
+	// Проверка API токенов и квот клиентов для всех вызовов
+	auth := newClientAuth(cfg.ClientTokens, cfg.ClientQuotas, cfg.QuotaWindow, cfg.AdminClients)
+	if len(cfg.ClientTokens) == 0 {
+		log.Println("ВНИМАНИЕ: CLIENT_TOKENS не заданы, аутентификация клиентов отключена")
+	}
+
 	// Создаем новый экземпляр gRPC сервера
-	grpcServer := grpc.NewServer()
+	// Журналирование с идентификатором запроса подключается до проверки клиента
+	grpcServer := grpc.NewServer(append(serverOptions(cfg.GRPC),
+		grpc.ChainUnaryInterceptor(LoggingUnaryInterceptor(), auth.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(LoggingStreamInterceptor(), auth.StreamInterceptor()),
+	)...)
 
 	// Регистрируем наш сервис ExchangeService
-	proto.RegisterExchangeServiceServer(grpcServer, NewServer(storage))
+	proto.RegisterExchangeServiceServer(grpcServer, NewServer(storage, updates, monitor))
+	// Административный сервис ручных курсов (доступ проверяется в clientAuth)
+	// Ручные курсы и решения по карантину поддерживает только хранилище PostgreSQL
+	if admin, ok := storage.(storages.RateAdmin); ok {
+		proto.RegisterRateAdminServiceServer(grpcServer, NewRateAdminServer(admin, cfg.OverrideMaxTTL))
+	} else {
+		log.Printf("Административный сервис ручных курсов отключен: не поддерживается хранилищем %s", cfg.StorageDriver)
+	}
 
 	log.Printf("Сервер запущен на порту %s", port)
 
+	// Graceful shutdown по отмене контекста
+	go func() {
+		<-ctx.Done()
+		log.Println("Остановка gRPC сервера...")
+		grpcServer.GracefulStop()
+	}()
+
 	// Запускаем сервер (блокирующая операция)
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("ошибка работы сервера: %v", err)
 	}
 }
+
+// serverOptions формирует параметры gRPC сервера из конфигурации
+// Нулевые значения не переопределяют значения по умолчанию библиотеки
+func serverOptions(cfg config.GRPCServerConfig) []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if cfg.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(cfg.MaxSendMsgSize))
+	}
+	if cfg.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(cfg.MaxConcurrentStreams))
+	}
+	if cfg.ConnectionTimeout > 0 {
+		opts = append(opts, grpc.ConnectionTimeout(cfg.ConnectionTimeout))
+	}
+
+	// Параметры keepalive сервера: ping простаивающих клиентов и ограничение жизни соединений
+	opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
+		Time:              cfg.KeepaliveTime,
+		Timeout:           cfg.KeepaliveTimeout,
+		MaxConnectionIdle: cfg.MaxConnectionIdle,
+		MaxConnectionAge:  cfg.MaxConnectionAge,
+	}))
+
+	// Политика keepalive клиентов: слишком частые ping приводят к разрыву соединения
+	if cfg.KeepaliveMinTime > 0 || cfg.KeepalivePermitWithoutStream {
+		opts = append(opts, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.KeepaliveMinTime,
+			PermitWithoutStream: cfg.KeepalivePermitWithoutStream,
+		}))
+	}
+	return opts
+}
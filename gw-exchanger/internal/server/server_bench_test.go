@@ -0,0 +1,28 @@
+package server
+
+import (
+	"context"
+	"gw-proto/proto"
+	"testing"
+)
+
+// newBenchClient запускает сервер обмена на хранилище SQLite с курсами USD и EUR к рублю
+// и возвращает gRPC-клиента, подключенного к нему через соединение в памяти
+func newBenchClient(b *testing.B) proto.ExchangeServiceClient {
+	b.Helper()
+	return newTestClient(b, map[string]float64{"USD": 90, "EUR": 98})
+}
+
+// BenchmarkGetExchangeRateForCurrency - gRPC-запрос курса пары (бюджет getrate утилиты loadtest)
+func BenchmarkGetExchangeRateForCurrency(b *testing.B) {
+	client := newBenchClient(b)
+	ctx := context.Background()
+	request := &proto.CurrencyRequest{FromCurrency: "USD", ToCurrency: "EUR"}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := client.GetExchangeRateForCurrency(ctx, request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	storages "gw-exchanger/internal/storage"
+	"gw-exchanger/internal/storage/sqlstore"
+	"gw-proto/proto"
+	"io"
+	"log"
+	"math"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestClient запускает сервер обмена на хранилище SQLite с курсами к рублю
+// и возвращает gRPC-клиента, подключенного к нему через соединение в памяти
+func newTestClient(t testing.TB, rates map[string]float64) proto.ExchangeServiceClient {
+	t.Helper()
+	output := log.Writer()
+	log.SetOutput(io.Discard) // Журнал миграций и пула не нужен в выводе тестов
+	t.Cleanup(func() { log.SetOutput(output) })
+
+	ctx := context.Background()
+	dialect := sqlstore.SQLite
+	dialect.Migrations = filepath.Join("..", "..", dialect.Migrations)
+	store, err := sqlstore.NewStore(ctx, dialect, filepath.Join(t.TempDir(), "rates.db"), 0, "RUB",
+		time.Second, storages.PoolOptions{MaxIdleConns: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.StoreRates(ctx, rates, storages.SourceCBR); err != nil {
+		t.Fatal(err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	proto.RegisterExchangeServiceServer(server, NewServer(store, nil, nil))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return proto.NewExchangeServiceClient(conn)
+}
+
+// testRates - курсы к рублю, которыми заполняется хранилище тестов сервера
+var testRates = map[string]float64{"USD": 90, "EUR": 98, "CNY": 12.5}
+
+func TestGetExchangeRatesBase(t *testing.T) {
+	client := newTestClient(t, testRates)
+
+	tests := []struct {
+		name     string
+		base     string
+		wantBase string
+		want     map[string]float64
+	}{
+		{name: "базовая валюта сервиса", base: "", wantBase: "RUB", want: map[string]float64{"RUB": 1, "USD": 90, "EUR": 98, "CNY": 12.5}},
+		{name: "база совпадает с базой сервиса", base: "RUB", wantBase: "RUB", want: map[string]float64{"RUB": 1, "USD": 90, "EUR": 98, "CNY": 12.5}},
+		{name: "база USD", base: "USD", wantBase: "USD", want: map[string]float64{"RUB": 1.0 / 90, "USD": 1, "EUR": 98.0 / 90, "CNY": 12.5 / 90}},
+		{name: "код в нижнем регистре", base: " eur ", wantBase: "EUR", want: map[string]float64{"RUB": 1.0 / 98, "USD": 90.0 / 98, "EUR": 1, "CNY": 12.5 / 98}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := client.GetExchangeRates(context.Background(), &proto.ExchangeRatesRequest{BaseCurrency: tt.base})
+			if err != nil {
+				t.Fatalf("ошибка получения курсов: %v", err)
+			}
+			if response.BaseCurrency != tt.wantBase {
+				t.Errorf("базовая валюта %q, ожидалась %q", response.BaseCurrency, tt.wantBase)
+			}
+			if len(response.PreciseRates) != len(tt.want) {
+				t.Fatalf("курсы %v, ожидались %v", response.PreciseRates, tt.want)
+			}
+			for currency, want := range tt.want {
+				if got := response.PreciseRates[currency]; math.Abs(got-want) > 1e-12 {
+					t.Errorf("курс %s = %v, ожидался %v", currency, got, want)
+				}
+				if got := float64(response.Rates[currency]); math.Abs(got-want) > 1e-6*want {
+					t.Errorf("курс float32 %s = %v, ожидался %v", currency, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetExchangeRatesUnknownBase(t *testing.T) {
+	client := newTestClient(t, testRates)
+	_, err := client.GetExchangeRates(context.Background(), &proto.ExchangeRatesRequest{BaseCurrency: "GBP"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ошибка %v, ожидался код %s", err, codes.InvalidArgument)
+	}
+}
+
+// TestGetExchangeRatesRoundTrip проверяет согласованность курсов при разных базах:
+// курс A в базе B, умноженный на курс B в базе A, равен 1, и совпадает с курсом пары A/B
+func TestGetExchangeRatesRoundTrip(t *testing.T) {
+	client := newTestClient(t, testRates)
+	ctx := context.Background()
+	currencies := []string{"RUB", "USD", "EUR", "CNY"}
+
+	byBase := make(map[string]map[string]float64, len(currencies))
+	for _, base := range currencies {
+		response, err := client.GetExchangeRates(ctx, &proto.ExchangeRatesRequest{BaseCurrency: base})
+		if err != nil {
+			t.Fatalf("ошибка получения курсов к %s: %v", base, err)
+		}
+		byBase[base] = response.PreciseRates
+	}
+
+	for _, a := range currencies {
+		for _, b := range currencies {
+			if product := byBase[b][a] * byBase[a][b]; math.Abs(product-1) > 1e-12 {
+				t.Errorf("%s в базе %s × %s в базе %s = %v, ожидалось 1", a, b, b, a, product)
+			}
+
+			pair, err := client.GetExchangeRateForCurrency(ctx, &proto.CurrencyRequest{FromCurrency: a, ToCurrency: b})
+			if err != nil {
+				t.Fatalf("ошибка получения курса %s/%s: %v", a, b, err)
+			}
+			if math.Abs(pair.PreciseRate-byBase[b][a]) > 1e-12*pair.PreciseRate {
+				t.Errorf("курс пары %s/%s = %v, в базе %s = %v", a, b, pair.PreciseRate, b, byBase[b][a])
+			}
+		}
+	}
+}
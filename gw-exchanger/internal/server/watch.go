@@ -0,0 +1,75 @@
+package server
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	storages "gw-exchanger/internal/storage"
+	"gw-proto/proto"
+	"time"
+)
+
+// Параметры чтения outbox для потока WatchRateUpdates
+const (
+	rateEventsPollInterval = time.Second // Интервал проверки новых событий
+	rateEventsBatchSize    = 100         // Максимум событий за одно чтение
+)
+
+// WatchRateUpdates передает клиенту события об изменении курсов до отмены вызова
+// События читаются из outbox, куда записываются в транзакции изменения курсов, поэтому
+// после переподключения с after_id клиент получает пропущенные события (за время хранения outbox)
+// Параметры:
+//   - req: идентификатор последнего полученного события (0 - только новые события)
+//   - stream: поток событий
+//
+// Возвращает:
+//   - error: ошибка чтения событий или отправки клиенту
+func (s *ExchangeServer) WatchRateUpdates(req *proto.WatchRateUpdatesRequest, stream grpc.ServerStreamingServer[proto.RateUpdateEvent]) error {
+	ctx := stream.Context()
+
+	// Поток событий читается из outbox, который есть только в хранилище PostgreSQL
+	source, ok := s.storage.(storages.RateEventSource)
+	if !ok {
+		return errUnsupported
+	}
+
+	afterID := req.AfterId
+	if afterID <= 0 {
+		last, err := source.LastRateEventID(ctx)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "ошибка получения событий: %v", err)
+		}
+		afterID = last
+	}
+
+	ticker := time.NewTicker(rateEventsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		events, err := source.RateEventsAfter(ctx, afterID, rateEventsBatchSize)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "ошибка получения событий: %v", err)
+		}
+		for _, event := range events {
+			err := stream.Send(&proto.RateUpdateEvent{
+				Id:           event.ID,
+				BaseCurrency: event.BaseCurrency,
+				Currencies:   event.Currencies,
+				ChangedAt:    event.CreatedAt.Unix(),
+			})
+			if err != nil {
+				return err
+			}
+			afterID = event.ID
+		}
+		if len(events) == rateEventsBatchSize {
+			continue // Есть еще непрочитанные события
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package staleness
+
+import (
+	"context"
+	"fmt"
+	"gw-exchanger/internal/alert"   // Оповещения дежурных
+	"gw-exchanger/internal/metrics" // Метрики Prometheus
+	storages "gw-exchanger/internal/storage"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Monitor отслеживает возраст опубликованных курсов
+// Если хотя бы один курс не обновлялся дольше порога, сервис считается деградированным:
+// ответы помечаются флагом degraded, а дежурные получают оповещение
+type Monitor struct {
+	rates     storages.RateProvider // Источник опубликованных курсов
+	threshold time.Duration         // Допустимый возраст курса
+	notifier  alert.Notifier        // Отправитель оповещений (nil - только лог)
+
+	mu    sync.RWMutex
+	stale []string // Валюты с устаревшими курсами по результатам последней проверки
+}
+
+// NewMonitor создает монитор устаревания курсов
+// Параметры:
+//   - rates: источник опубликованных курсов
+//   - threshold: допустимый возраст курса
+//   - notifier: отправитель оповещений (nil - оповещения отключены)
+func NewMonitor(rates storages.RateProvider, threshold time.Duration, notifier alert.Notifier) *Monitor {
+	return &Monitor{rates: rates, threshold: threshold, notifier: notifier}
+}
+
+// Degraded сообщает, есть ли устаревшие курсы по результатам последней проверки
+func (m *Monitor) Degraded() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.stale) > 0
+}
+
+// Stale возвращает валюты с устаревшими курсами по результатам последней проверки
+func (m *Monitor) Stale() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.stale...)
+}
+
+// Run периодически проверяет возраст курсов до отмены контекста
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Check(ctx); err != nil {
+			log.Printf("Ошибка проверки устаревания курсов: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check проверяет возраст курсов, обновляет метрики и флаг деградации
+// Оповещение отправляется при переходе в деградированное состояние и при восстановлении
+func (m *Monitor) Check(ctx context.Context) error {
+	rates, err := m.rates.GetAllRates(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка получения курсов: %w", err)
+	}
+
+	now := time.Now()
+	var stale []string
+	for currency, rate := range rates {
+		age := now.Sub(rate.UpdatedAt)
+		metrics.RateAge.WithLabelValues(currency).Set(age.Seconds())
+
+		// Ручной курс закреплен намеренно и действует до истечения своего срока
+		if rate.Source != storages.SourceManual && age > m.threshold {
+			stale = append(stale, currency)
+		}
+	}
+	sort.Strings(stale)
+	metrics.StaleRates.Set(float64(len(stale)))
+
+	m.mu.Lock()
+	wasDegraded := len(m.stale) > 0
+	m.stale = stale
+	m.mu.Unlock()
+
+	switch {
+	case len(stale) > 0 && !wasDegraded:
+		m.notify(ctx, fmt.Sprintf("Курсы не обновлялись дольше %s: %s", m.threshold, strings.Join(stale, ", ")))
+	case len(stale) == 0 && wasDegraded:
+		m.notify(ctx, "Курсы снова актуальны")
+	}
+	return nil
+}
+
+// notify пишет оповещение в лог и отправляет его дежурным
+func (m *Monitor) notify(ctx context.Context, text string) {
+	log.Printf("Устаревание курсов: %s", text)
+	if m.notifier == nil {
+		return
+	}
+	if err := m.notifier.Notify(ctx, "gw-exchanger: "+text); err != nil {
+		log.Printf("Ошибка отправки оповещения: %v", err)
+	}
+}
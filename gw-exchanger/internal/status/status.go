@@ -0,0 +1,154 @@
+package status
+
+import (
+	"context"
+	_ "embed" // Шаблон страницы встраивается в исполняемый файл
+	"encoding/json"
+	"errors"
+	"gw-exchanger/internal/staleness"
+	storages "gw-exchanger/internal/storage"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Source - источник опубликованных курсов страницы состояния
+type Source interface {
+	// GetAllRates возвращает опубликованные курсы валют
+	GetAllRates(ctx context.Context) (map[string]storages.ExchangeRate, error)
+	// BaseCurrency возвращает базовую валюту курсов
+	BaseCurrency() string
+}
+
+// Health - источник состояния поставщиков и обновлений курсов страницы состояния
+type Health interface {
+	// ProviderStatuses возвращает состояние поставщиков курсов
+	ProviderStatuses() []storages.ProviderStatus
+	// UpdateStatuses возвращает состояние фоновых обновлений курсов
+	UpdateStatuses() []storages.UpdateStatus
+}
+
+// Report - состояние сервиса для страницы и ответа /status.json
+type Report struct {
+	GeneratedAt  time.Time                 `json:"generated_at"`          // Время формирования
+	BaseCurrency string                    `json:"base_currency"`         // Базовая валюта курсов
+	Degraded     bool                      `json:"degraded"`              // Есть устаревшие курсы
+	LastUpdate   time.Time                 `json:"last_update,omitempty"` // Время последнего изменения курса поставщиком
+	Rates        []Rate                    `json:"rates"`                 // Опубликованные курсы по валютам
+	RatesError   string                    `json:"rates_error,omitempty"` // Ошибка получения курсов из БД
+	Providers    []storages.ProviderStatus `json:"providers"`             // Состояние поставщиков
+	Updates      []storages.UpdateStatus   `json:"updates"`               // Состояние фоновых обновлений
+}
+
+// Rate - опубликованный курс валюты на странице состояния
+type Rate struct {
+	Currency  string    `json:"currency"`   // Код валюты
+	Rate      float64   `json:"rate"`       // Стоимость единицы валюты в базовой валюте
+	Source    string    `json:"source"`     // Источник курса
+	UpdatedAt time.Time `json:"updated_at"` // Время обновления
+	Stale     bool      `json:"stale"`      // Курс устарел (не обновлялся дольше RATE_STALE_AFTER)
+}
+
+// reportTimeout - максимальное время получения курсов для страницы
+const reportTimeout = 5 * time.Second
+
+//go:embed status.html
+var pageSource string
+
+// page - шаблон HTML страницы состояния
+var page = template.Must(template.New("status").Funcs(template.FuncMap{
+	"when": formatTime,
+}).Parse(pageSource))
+
+// NewHandler создает обработчик страницы состояния только для чтения:
+// "/" - HTML страница, "/status.json" - то же состояние в JSON
+// Параметры:
+//   - source: источник курсов
+//   - health: источник состояния поставщиков и обновлений курсов
+//   - monitor: монитор устаревания курсов (nil - устаревание не показывается)
+func NewHandler(source Source, health Health, monitor *staleness.Monitor) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(buildReport(r.Context(), source, health, monitor)); err != nil {
+			log.Printf("Ошибка отправки состояния: %v", err)
+		}
+	})
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := page.Execute(w, buildReport(r.Context(), source, health, monitor)); err != nil {
+			log.Printf("Ошибка формирования страницы состояния: %v", err)
+		}
+	})
+	return mux
+}
+
+// Serve запускает HTTP сервер страницы состояния (блокирующая операция)
+// Если адрес пустой, страница не публикуется
+func Serve(addr string, handler http.Handler) {
+	if addr == "" {
+		return
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	log.Printf("Страница состояния доступна на %s", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Ошибка сервера страницы состояния: %v", err)
+	}
+}
+
+// buildReport собирает состояние сервиса
+// Ошибка получения курсов не прерывает формирование: состояние поставщиков нужно именно при сбоях
+func buildReport(ctx context.Context, source Source, health Health, monitor *staleness.Monitor) Report {
+	report := Report{
+		GeneratedAt:  time.Now(),
+		BaseCurrency: source.BaseCurrency(),
+		Degraded:     monitor.Degraded(),
+		Providers:    health.ProviderStatuses(),
+		Updates:      health.UpdateStatuses(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reportTimeout)
+	defer cancel()
+
+	rates, err := source.GetAllRates(ctx)
+	if err != nil {
+		report.RatesError = err.Error()
+		return report
+	}
+
+	stale := make(map[string]bool)
+	for _, currency := range monitor.Stale() {
+		stale[currency] = true
+	}
+	for _, rate := range rates {
+		report.Rates = append(report.Rates, Rate{
+			Currency:  rate.Currency,
+			Rate:      rate.Rate,
+			Source:    rate.Source,
+			UpdatedAt: rate.UpdatedAt,
+			Stale:     stale[rate.Currency],
+		})
+		// Ручные курсы не обновляются поставщиками и не показывают свежесть обновления
+		if rate.Source != storages.SourceManual && rate.UpdatedAt.After(report.LastUpdate) {
+			report.LastUpdate = rate.UpdatedAt
+		}
+	}
+	sort.Slice(report.Rates, func(i, j int) bool { return report.Rates[i].Currency < report.Rates[j].Currency })
+	return report
+}
+
+// formatTime форматирует время для страницы (нулевое время - прочерк)
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
@@ -0,0 +1,49 @@
+package storages
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// ApplyMigrations применяет SQL-миграции из каталога dir в порядке имен файлов
+// Миграции идемпотентны и выполняются при каждом запуске; каждое хранилище использует
+// свой каталог миграций (PostgreSQL - migrations, MySQL - migrations/mysql, SQLite - migrations/sqlite)
+// Выполнение прерывается при отмене контекста
+// Параметры:
+//   - ctx: контекст выполнения
+//   - db: подключение к БД (файл миграции выполняется одним запросом из нескольких команд)
+//   - dir: каталог миграций
+//
+// Возвращает:
+//   - error: ошибка чтения или выполнения миграции
+func ApplyMigrations(ctx context.Context, db *sql.DB, dir string) error {
+	// Получаем пути к файлам миграций (Glob возвращает их отсортированными)
+	migrationPaths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return fmt.Errorf("ошибка поиска файлов миграций: %v", err)
+	}
+	if len(migrationPaths) == 0 {
+		return fmt.Errorf("файлы миграций не найдены в каталоге %s", dir)
+	}
+
+	for _, migrationPath := range migrationPaths {
+		log.Printf("Применение миграции: %s", migrationPath)
+
+		// Чтение файла миграции
+		sqlBytes, err := os.ReadFile(migrationPath)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения файла миграции %s: %v", migrationPath, err)
+		}
+
+		// Выполнение SQL-запросов
+		if _, err := db.ExecContext(ctx, string(sqlBytes)); err != nil {
+			return fmt.Errorf("ошибка выполнения миграции %s: %v", migrationPath, err)
+		}
+	}
+
+	return nil
+}
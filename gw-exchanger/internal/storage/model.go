@@ -2,14 +2,36 @@ package storages
 
 import "time"
 
+// Источники курсов валют
+const (
+	SourceCBR       = "cbr"       // API Центробанка России
+	SourceECB       = "ecb"       // API Европейского центрального банка
+	SourceManual    = "manual"    // Ручная установка курса (имеет приоритет над поставщиками)
+	SourceAggregate = "aggregate" // Курс, агрегированный по нескольким поставщикам
+	SourceCoinGecko = "coingecko" // API CoinGecko (курсы криптовалют)
+)
+
+// SourcePriority - порядок выбора курса, если для валюты есть записи из нескольких источников
+var SourcePriority = []string{SourceManual, SourceAggregate, SourceCBR, SourceECB, SourceCoinGecko}
+
 // ExchangeRate представляет запись о курсе валюты в хранилище
 // Содержит поля, соответствующие структуре таблицы в БД
 type ExchangeRate struct {
 	Currency  string    `json:"currency" db:"currency"`     // Код валюты (например "USD")
 	Rate      float64   `json:"rate" db:"rate"`             // Текущий курс к базовой валюте
+	Source    string    `json:"source" db:"source"`         // Источник курса (cbr, ecb, manual)
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // Время последнего обновления
 }
 
+// Currency содержит сведения о валюте из справочника currencies
+type Currency struct {
+	Code      string    `json:"code"`       // Код валюты (например "USD")
+	Name      string    `json:"name"`       // Название валюты
+	Nominal   int       `json:"nominal"`    // Номинал, к которому ЦБ РФ публикует курс
+	Decimals  int       `json:"decimals"`   // Количество знаков после запятой в суммах валюты
+	UpdatedAt time.Time `json:"updated_at"` // Время последнего обновления сведений
+}
+
 // RateRequest содержит параметры запроса курса обмена
 // Используется в API для входящих запросов
 type RateRequest struct {
@@ -26,6 +48,116 @@ type RateResponse struct {
 	Timestamp int64   `json:"timestamp"` // Время расчета в Unix timestamp
 }
 
+// RateChange - запись журнала изменений курса валюты
+type RateChange struct {
+	Currency  string    `json:"currency" db:"currency"`     // Код валюты
+	Source    string    `json:"source" db:"source"`         // Источник курса
+	OldRate   float64   `json:"old_rate" db:"old_rate"`     // Предыдущий курс (0 - первое получение курса)
+	NewRate   float64   `json:"new_rate" db:"new_rate"`     // Новый курс
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"` // Время изменения
+}
+
+// RateEvent - событие outbox об изменении курсов валют
+type RateEvent struct {
+	ID           int64     `json:"id" db:"id"`                       // Идентификатор события (возрастает)
+	BaseCurrency string    `json:"base_currency" db:"base_currency"` // Базовая валюта курсов
+	Currencies   []string  `json:"currencies" db:"currencies"`       // Валюты, курсы которых изменились
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`       // Время изменения
+}
+
+// RateStats - статистика курса пары валют за период
+type RateStats struct {
+	From          string    `json:"from"`           // Исходная валюта
+	To            string    `json:"to"`             // Целевая валюта
+	Start         time.Time `json:"start"`          // Начало статистики (не раньше начала периода)
+	End           time.Time `json:"end"`            // Конец периода
+	Open          float64   `json:"open"`           // Курс на начало
+	Close         float64   `json:"close"`          // Курс на конец
+	Min           float64   `json:"min"`            // Минимальный курс
+	Max           float64   `json:"max"`            // Максимальный курс
+	Avg           float64   `json:"avg"`            // Средний курс, взвешенный по времени действия
+	ChangePercent float64   `json:"change_percent"` // Изменение курса в процентах (Close к Open)
+	Changes       int       `json:"changes"`        // Количество значений курса за период
+}
+
+// RateCandle - свеча курса пары валют за интервал
+type RateCandle struct {
+	Start time.Time `json:"start"` // Начало интервала
+	Open  float64   `json:"open"`  // Курс на начало интервала
+	High  float64   `json:"high"`  // Максимальный курс
+	Low   float64   `json:"low"`   // Минимальный курс
+	Close float64   `json:"close"` // Курс на конец интервала
+}
+
+// RateSnapshot - снимок опубликованных курсов на конец дня
+type RateSnapshot struct {
+	Date         time.Time               `json:"date"`          // Дата снимка
+	BaseCurrency string                  `json:"base_currency"` // Базовая валюта
+	Rates        map[string]ExchangeRate `json:"rates"`         // Курсы с источниками (ключ - код валюты)
+	TakenAt      time.Time               `json:"taken_at"`      // Время создания снимка
+}
+
+// RateOverride - ручной курс валюты, действующий до ExpiresAt
+type RateOverride struct {
+	Currency  string    `json:"currency" db:"currency"`     // Код валюты
+	Rate      float64   `json:"rate" db:"rate"`             // Установленный курс
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"` // Время окончания действия (нулевое - бессрочно)
+	SetBy     string    `json:"set_by" db:"set_by"`         // Клиент, установивший курс
+}
+
+// Состояния курса на карантине
+const (
+	QuarantinePending  = "pending"  // Ожидает решения администратора
+	QuarantineApproved = "approved" // Принят и опубликован
+	QuarantineRejected = "rejected" // Отклонен
+)
+
+// QuarantinedRate - курс поставщика, отклоняющийся от опубликованного больше допустимого
+// Такой курс не публикуется до решения администратора
+type QuarantinedRate struct {
+	ID           int64     `json:"id" db:"id"`                       // Идентификатор записи
+	Currency     string    `json:"currency" db:"currency"`           // Код валюты
+	Source       string    `json:"source" db:"source"`               // Источник курса
+	PreviousRate float64   `json:"previous_rate" db:"previous_rate"` // Опубликованный курс источника на момент получения
+	Rate         float64   `json:"rate" db:"rate"`                   // Полученный курс
+	Deviation    float64   `json:"deviation" db:"deviation"`         // Отклонение от опубликованного курса в долях
+	Status       string    `json:"status" db:"status"`               // Состояние: pending, approved, rejected
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`       // Время первого получения
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`       // Время последнего получения
+	ResolvedBy   string    `json:"resolved_by" db:"resolved_by"`     // Клиент, принявший решение
+	Reason       string    `json:"reason" db:"reason"`               // Причина решения
+}
+
+// ProviderStatus - состояние поставщика курсов по результатам запросов с момента запуска
+type ProviderStatus struct {
+	Name          string    `json:"name"`                   // Имя поставщика
+	LastSuccess   time.Time `json:"last_success,omitempty"` // Время последнего успешного запроса
+	LastFailure   time.Time `json:"last_failure,omitempty"` // Время последней ошибки
+	LastError     string    `json:"last_error,omitempty"`   // Текст последней ошибки
+	Failures      int       `json:"failures"`               // Ошибок подряд (0 - последний запрос успешен)
+	TotalFailures int64     `json:"total_failures"`         // Всего ошибок с момента запуска
+}
+
+// UpdateStatus - состояние фонового обновления курсов с момента запуска
+type UpdateStatus struct {
+	Name        string    `json:"name"`                   // Обновление: rates (фиатные валюты), crypto (криптовалюты)
+	LastRun     time.Time `json:"last_run,omitempty"`     // Время последнего запуска
+	LastSuccess time.Time `json:"last_success,omitempty"` // Время последнего успешного обновления
+	LastError   string    `json:"last_error,omitempty"`   // Ошибка последнего запуска (пусто - успешен)
+	Source      string    `json:"source,omitempty"`       // Источник курсов последнего успешного обновления (cbr, aggregate, coingecko)
+	Currencies  int       `json:"currencies"`             // Валют, полученных при последнем успешном обновлении
+	Runs        int64     `json:"runs"`                   // Запусков с момента запуска сервиса
+	Errors      int64     `json:"errors"`                 // Неудачных запусков с момента запуска сервиса
+}
+
+// RateQuote - курс обмена пары валют с источниками курсов обеих валют
+// Источник базовой валюты (USD) и валюты, совпадающей с другой валютой пары, пустой
+type RateQuote struct {
+	Rate       float64 // Курс обмена
+	FromSource string  // Источник курса исходной валюты
+	ToSource   string  // Источник курса целевой валюты
+}
+
 // AllRatesResponse представляет ответ со всеми текущими курсами
 // Используется в API для исходящих ответов
 type AllRatesResponse struct {
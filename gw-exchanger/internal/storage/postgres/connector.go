@@ -4,30 +4,45 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	_ "github.com/lib/pq" // Драйвер PostgreSQL (импорт для side effects)
+	_ "github.com/lib/pq"                    // Драйвер PostgreSQL (импорт для side effects)
+	storages "gw-exchanger/internal/storage" // Интерфейсы и модели хранилища
 	"log"
 	"os"
-	"path/filepath"
 	"time"
 )
 
 // PostgresStorage представляет хранилище данных в PostgreSQL
 type PostgresStorage struct {
-	db             *sql.DB       // Подключение к базе данных
-	apiURL         string        // URL API Центробанка для получения курсов
-	updateInterval time.Duration // Интервал обновления курсов
+	db           *sql.DB       // Подключение к базе данных
+	maxRateJump  float64       // Допустимое изменение курса поставщика за обновление в долях (0 - без карантина)
+	baseCurrency string        // Базовая валюта, к которой хранятся курсы
+	queryTimeout time.Duration // Максимальное время выполнения запроса (или транзакции)
 }
 
+// overrideExpiryInterval - период проверки ручных курсов с истекшим сроком
+const overrideExpiryInterval = time.Minute
+
 // NewPostgresStorage создает и инициализирует новое подключение к PostgreSQL
+// Курсы от поставщиков хранилище не получает: их записывает компонент обновления (updater.RateUpdater)
 // Параметры:
+//   - ctx: контекст жизни хранилища (его отмена останавливает снятие истекших ручных курсов и очистку outbox)
 //   - connStr: строка подключения к основной БД
-//   - apiURL: URL API Центробанка
-//   - updateInterval: интервал обновления курсов
+//   - maxRateJump: допустимое изменение курса за обновление в долях, больший скачок - на карантин (0 - без проверки)
+//   - baseCurrency: базовая валюта (курсы поставщиков пересчитываются к ней)
+//   - queryTimeout: максимальное время выполнения запроса к БД
+//   - pool: параметры пула соединений
 //
 // Возвращает:
 //   - *PostgresStorage: инициализированное хранилище
 //   - error: ошибка при создании
-func NewPostgresStorage(connStr string, apiURL string, updateInterval time.Duration) (*PostgresStorage, error) {
+func NewPostgresStorage(
+	ctx context.Context,
+	connStr string,
+	maxRateJump float64,
+	baseCurrency string,
+	queryTimeout time.Duration,
+	pool storages.PoolOptions,
+) (*PostgresStorage, error) {
 	// 1. Подключение к служебной БД postgres для проверки/создания нужной БД
 	adminConnStr := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=postgres sslmode=disable",
@@ -44,15 +59,18 @@ func NewPostgresStorage(connStr string, apiURL string, updateInterval time.Durat
 	defer adminDb.Close()
 
 	// 2. Проверка существования БД и создание при необходимости
+	checkCtx, cancelCheck := withTimeout(ctx, queryTimeout)
+	defer cancelCheck()
+
 	var exists bool
-	err = adminDb.QueryRowContext(context.Background(),
+	err = adminDb.QueryRowContext(checkCtx,
 		"SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = 'exchange_rates')").Scan(&exists)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка проверки существования БД: %v", err)
 	}
 
 	if !exists {
-		_, err = adminDb.ExecContext(context.Background(), "CREATE DATABASE exchange_rates")
+		_, err = adminDb.ExecContext(checkCtx, "CREATE DATABASE exchange_rates")
 		if err != nil {
 			return nil, fmt.Errorf("ошибка создания БД: %v", err)
 		}
@@ -64,58 +82,51 @@ func NewPostgresStorage(connStr string, apiURL string, updateInterval time.Durat
 		return nil, fmt.Errorf("ошибка подключения к основной БД: %v", err)
 	}
 
+	// Настройка пула соединений
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	log.Printf("Пул соединений PostgreSQL: max_open=%d, max_idle=%d, max_lifetime=%s",
+		pool.MaxOpenConns, pool.MaxIdleConns, pool.ConnMaxLifetime)
+
 	// 3. Проверка подключения с таймаутом 3 секунды
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-	if err := db.PingContext(ctx); err != nil {
+	pingCtx, cancelPing := context.WithTimeout(ctx, 3*time.Second)
+	defer cancelPing()
+	if err := db.PingContext(pingCtx); err != nil {
 		return nil, fmt.Errorf("ошибка проверки подключения: %v", err)
 	}
 
 	// 4. Применение миграций
-	if err := applyMigrations(db); err != nil {
+	if err := storages.ApplyMigrations(ctx, db, "migrations"); err != nil {
 		return nil, fmt.Errorf("ошибка применения миграций: %v", err)
 	}
 
 	log.Println("Успешное подключение к PostgreSQL")
 
 	storage := &PostgresStorage{
-		db:             db,
-		apiURL:         apiURL,
-		updateInterval: updateInterval,
+		db:           db,
+		maxRateJump:  maxRateJump,
+		baseCurrency: baseCurrency,
+		queryTimeout: queryTimeout,
 	}
 
-	// 5. Запуск фонового обновления курсов
-	go storage.startRateUpdater()
+	// 5. Запуск снятия истекших ручных курсов и очистки outbox (останавливаются при отмене ctx)
+	go storage.startOverrideExpiry(ctx, overrideExpiryInterval)
+	go storage.startOutboxCleanup(ctx, outboxCleanupInterval)
 
 	return storage, nil
 }
 
-// applyMigrations применяет SQL-миграции из файла
-func applyMigrations(db *sql.DB) error {
-	// Получаем путь к файлу миграции
-	migrationPath := filepath.Join("migrations", "001_init.sql")
-
-	log.Printf("Путь к миграции: %s", migrationPath)
-	if _, err := os.Stat(migrationPath); os.IsNotExist(err) {
-		return fmt.Errorf("файл миграции не найден: %v", err)
-	}
-
-	// Чтение файла миграции
-	sqlBytes, err := os.ReadFile(migrationPath)
-	if err != nil {
-		return fmt.Errorf("ошибка чтения файла миграции: %v", err)
-	}
-
-	// Выполнение SQL-запросов
-	_, err = db.Exec(string(sqlBytes))
-	if err != nil {
-		return fmt.Errorf("ошибка выполнения миграции: %v", err)
-	}
-
-	return nil
-}
-
 // Close закрывает подключение к БД
 func (s *PostgresStorage) Close() error {
 	return s.db.Close()
 }
+
+// withTimeout ограничивает время выполнения запроса
+// Если контекст вызывающего уже содержит более ранний дедлайн, действует он
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	storages "gw-exchanger/internal/storage"
+)
+
+// StoreCurrencies записывает сведения о валютах из ответа поставщика в справочник currencies
+// Название и номинал обновляются, количество знаков после запятой записывается только для новых валют
+// (значение в справочнике могло быть исправлено вручную)
+func (s *PostgresStorage) StoreCurrencies(ctx context.Context, currencies []storages.Currency) error {
+	if len(currencies) == 0 {
+		return nil
+	}
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	codes := make([]string, 0, len(currencies))
+	names := make([]string, 0, len(currencies))
+	nominals := make([]int64, 0, len(currencies))
+	decimals := make([]int64, 0, len(currencies))
+	for _, currency := range currencies {
+		codes = append(codes, currency.Code)
+		names = append(names, currency.Name)
+		nominals = append(nominals, int64(currency.Nominal))
+		decimals = append(decimals, int64(currency.Decimals))
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO currencies (code, name, nominal, decimals)
+		 SELECT * FROM unnest($1::VARCHAR[], $2::TEXT[], $3::INTEGER[], $4::SMALLINT[])
+		 ON CONFLICT (code) DO UPDATE
+		 SET name = EXCLUDED.name, nominal = EXCLUDED.nominal, updated_at = NOW()
+		 WHERE currencies.name <> EXCLUDED.name OR currencies.nominal <> EXCLUDED.nominal`,
+		pq.Array(codes), pq.Array(names), pq.Array(nominals), pq.Array(decimals),
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи справочника валют: %w", err)
+	}
+	return nil
+}
+
+// ListCurrencies возвращает справочник валют в порядке кодов
+// Параметры:
+//   - ctx: контекст выполнения
+//
+// Возвращает:
+//   - []storages.Currency: сведения о валютах (название, номинал, знаки после запятой)
+//   - error: ошибка БД
+func (s *PostgresStorage) ListCurrencies(ctx context.Context) ([]storages.Currency, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT code, name, nominal, decimals, updated_at FROM currencies ORDER BY code")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса справочника валют: %w", err)
+	}
+	defer rows.Close()
+
+	var currencies []storages.Currency
+	for rows.Next() {
+		var currency storages.Currency
+		if err := rows.Scan(&currency.Code, &currency.Name, &currency.Nominal, &currency.Decimals, &currency.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения валюты: %w", err)
+		}
+		currencies = append(currencies, currency)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %w", err)
+	}
+	return currencies, nil
+}
@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	storages "gw-exchanger/internal/storage"
+	"sort"
+	"time"
+)
+
+// ratePoint - курс валюты, действующий с момента at
+type ratePoint struct {
+	at   time.Time // Время изменения курса
+	rate float64   // Курс
+}
+
+// GetRateStats возвращает статистику курса пары валют за период [start, end)
+// История строится по журналу изменений курсов поставщиков rate_audit (ручные курсы не учитываются)
+// Курс пары в каждый момент - отношение действующих курсов валют к базовой, среднее взвешено по времени
+// Параметры:
+//   - ctx: контекст выполнения
+//   - from: исходная валюта
+//   - to: целевая валюта
+//   - start: начало периода
+//   - end: конец периода
+//
+// Возвращает:
+//   - storages.RateStats: минимум, максимум, среднее и изменение курса
+//   - error: storages.ErrNoRateHistory, если курс пары не известен ни в один момент периода, или ошибка БД
+func (s *PostgresStorage) GetRateStats(ctx context.Context, from, to string, start, end time.Time) (storages.RateStats, error) {
+	fromSeries, err := s.rateSeries(ctx, from, start, end)
+	if err != nil {
+		return storages.RateStats{}, err
+	}
+	toSeries, err := s.rateSeries(ctx, to, start, end)
+	if err != nil {
+		return storages.RateStats{}, err
+	}
+
+	stats, ok := pairStats(pairSeries(fromSeries, toSeries), end)
+	if !ok {
+		return storages.RateStats{}, storages.ErrNoRateHistory
+	}
+	stats.From, stats.To = from, to
+	return stats, nil
+}
+
+// rateSeries возвращает изменения курса валюты за период вместе с курсом, действовавшим на его начало
+// Используется источник, курс которого сейчас публикуется (без учета ручных курсов)
+func (s *PostgresStorage) rateSeries(ctx context.Context, currency string, start, end time.Time) ([]ratePoint, error) {
+	// Курс базовой валюты всегда 1
+	if currency == s.baseCurrency {
+		return []ratePoint{{at: start, rate: 1}}, nil
+	}
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	var source string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT source FROM exchange_rates
+		 WHERE currency = $1 AND base_currency = $2 AND source <> $3
+		 ORDER BY array_position($4::text[], source::text)
+		 LIMIT 1`,
+		currency, s.baseCurrency, storages.SourceManual, pq.Array(storages.SourcePriority),
+	).Scan(&source)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка определения источника курса %s: %v", currency, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`(SELECT new_rate, changed_at FROM rate_audit
+		  WHERE currency = $1 AND source = $2 AND base_currency = $3 AND changed_at < $4
+		  ORDER BY changed_at DESC, id DESC
+		  LIMIT 1)
+		 UNION ALL
+		 (SELECT new_rate, changed_at FROM rate_audit
+		  WHERE currency = $1 AND source = $2 AND base_currency = $3 AND changed_at >= $4 AND changed_at < $5
+		  ORDER BY changed_at, id)`,
+		currency, source, s.baseCurrency, start, end,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса истории курса %s: %v", currency, err)
+	}
+	defer rows.Close()
+
+	var series []ratePoint
+	for rows.Next() {
+		var point ratePoint
+		if err := rows.Scan(&point.rate, &point.at); err != nil {
+			return nil, fmt.Errorf("ошибка чтения истории курса: %v", err)
+		}
+		// Курс, установленный до начала периода, действует с его начала
+		if point.at.Before(start) {
+			point.at = start
+		}
+		series = append(series, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %v", err)
+	}
+	return series, nil
+}
+
+// GetRateCandles возвращает свечи курса пары валют за период [start, end) с интервалом interval
+// Интервалы выравниваются по interval (часовые и суточные - по границам часов и суток UTC); свеча интервала без изменений курса
+// повторяет курс на его начало. Свечи начинаются с интервала, в котором курс пары становится известен
+// Параметры:
+//   - ctx: контекст выполнения
+//   - from: исходная валюта
+//   - to: целевая валюта
+//   - start: начало периода
+//   - end: конец периода
+//   - interval: длительность свечи
+//
+// Возвращает:
+//   - []storages.RateCandle: свечи в порядке времени
+//   - error: storages.ErrNoRateHistory, если курс пары не известен ни в один момент периода, или ошибка БД
+func (s *PostgresStorage) GetRateCandles(ctx context.Context, from, to string, start, end time.Time, interval time.Duration) ([]storages.RateCandle, error) {
+	start = start.Truncate(interval)
+	fromSeries, err := s.rateSeries(ctx, from, start, end)
+	if err != nil {
+		return nil, err
+	}
+	toSeries, err := s.rateSeries(ctx, to, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := pairCandles(pairSeries(fromSeries, toSeries), end, interval)
+	if len(candles) == 0 {
+		return nil, storages.ErrNoRateHistory
+	}
+	return candles, nil
+}
+
+// pairSeries объединяет изменения курсов валют в изменения курса пары
+// Курсы, изменившиеся в один момент, учитываются вместе; ряд начинается с момента,
+// когда известны курсы обеих валют
+func pairSeries(fromSeries, toSeries []ratePoint) []ratePoint {
+	type event struct {
+		at       time.Time
+		rate     float64
+		isSource bool
+	}
+	events := make([]event, 0, len(fromSeries)+len(toSeries))
+	for _, point := range fromSeries {
+		events = append(events, event{at: point.at, rate: point.rate, isSource: true})
+	}
+	for _, point := range toSeries {
+		events = append(events, event{at: point.at, rate: point.rate})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	var (
+		series           []ratePoint
+		fromRate, toRate float64
+	)
+	for i, e := range events {
+		if e.isSource {
+			fromRate = e.rate
+		} else {
+			toRate = e.rate
+		}
+		if i+1 < len(events) && events[i+1].at.Equal(e.at) {
+			continue
+		}
+		if fromRate <= 0 || toRate <= 0 {
+			continue
+		}
+		series = append(series, ratePoint{at: e.at, rate: fromRate / toRate})
+	}
+	return series
+}
+
+// pairStats вычисляет статистику курса пары по ряду его изменений до конца периода end
+// Возвращает false, если ряд пуст (курс пары не известен ни в один момент периода)
+func pairStats(series []ratePoint, end time.Time) (storages.RateStats, bool) {
+	if len(series) == 0 {
+		return storages.RateStats{}, false
+	}
+
+	stats := storages.RateStats{
+		Start: series[0].at,
+		End:   end,
+		Open:  series[0].rate,
+		Min:   series[0].rate,
+		Max:   series[0].rate,
+	}
+	var weighted float64 // Сумма курс * длительность действия (в секундах)
+	for i, point := range series {
+		until := end
+		if i+1 < len(series) {
+			until = series[i+1].at
+		}
+		weighted += point.rate * until.Sub(point.at).Seconds()
+		stats.Min = min(stats.Min, point.rate)
+		stats.Max = max(stats.Max, point.rate)
+	}
+
+	stats.Close = series[len(series)-1].rate
+	if duration := end.Sub(stats.Start).Seconds(); duration > 0 {
+		stats.Avg = weighted / duration
+	} else {
+		stats.Avg = stats.Close
+	}
+	stats.ChangePercent = (stats.Close - stats.Open) / stats.Open * 100
+	stats.Changes = len(series)
+	return stats, true
+}
+
+// pairCandles разбивает ряд изменений курса пары на свечи длительностью interval до конца периода end
+// Первая свеча - интервал первого изменения ряда (начало ряда выровнено по interval)
+func pairCandles(series []ratePoint, end time.Time, interval time.Duration) []storages.RateCandle {
+	if len(series) == 0 {
+		return nil
+	}
+
+	var candles []storages.RateCandle
+	next := 0 // Первое изменение, еще не попавшее в свечу
+	rate := series[0].rate
+	for bucket := series[0].at.Truncate(interval); bucket.Before(end); bucket = bucket.Add(interval) {
+		candle := storages.RateCandle{Start: bucket, Open: rate, High: rate, Low: rate}
+		bucketEnd := bucket.Add(interval)
+		for ; next < len(series) && series[next].at.Before(bucketEnd); next++ {
+			rate = series[next].rate
+			if series[next].at.Equal(bucket) {
+				candle.Open = rate // Курс, установленный в начале интервала, открывает свечу
+				candle.High, candle.Low = rate, rate
+			}
+			candle.High = max(candle.High, rate)
+			candle.Low = min(candle.Low, rate)
+		}
+		candle.Close = rate
+		candles = append(candles, candle)
+	}
+	return candles
+}
@@ -4,39 +4,66 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"gw-exchanger/internal/api"
+	"github.com/lib/pq"
+	"gw-exchanger/internal/metrics"
+	storages "gw-exchanger/internal/storage"
 	"log"
 	"time"
 )
 
-// startRateUpdater запускает фоновое обновление курсов валют
-func (s *PostgresStorage) startRateUpdater() {
-	ticker := time.NewTicker(s.updateInterval)
-	defer ticker.Stop()
+// PruneRates удаляет курсы источника для валют, не входящих в список хранимых
+// (например, после сокращения CURRENCY_ALLOWLIST), чтобы они не публиковались устаревшими
+func (s *PostgresStorage) PruneRates(ctx context.Context, source string, allowlist []string) error {
+	if len(allowlist) == 0 {
+		return nil
+	}
 
-	for range ticker.C {
-		if err := s.UpdateRatesFromCB(); err != nil {
-			log.Printf("Ошибка обновления курсов: %v", err)
-		}
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", err)
 	}
-}
+	defer tx.Rollback()
 
-// UpdateRatesFromCB обновляет курсы валют из API Центробанка
-func (s *PostgresStorage) UpdateRatesFromCB() error {
-	if s.apiURL == "" {
-		return fmt.Errorf("URL API не настроен")
+	rows, err := tx.QueryContext(ctx,
+		`DELETE FROM exchange_rates
+		 WHERE source = $1 AND base_currency = $2 AND currency <> $2 AND NOT (currency = ANY($3::text[]))
+		 RETURNING currency`,
+		source, s.baseCurrency, pq.Array(allowlist),
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления исключенных валют: %v", err)
+	}
+	pruned, err := scanCurrencies(rows)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления исключенных валют: %v", err)
+	}
+	if err := s.recordRateEvent(ctx, tx, pruned); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %v", err)
 	}
 
-	log.Println("Обновление курсов валют...")
+	if len(pruned) > 0 {
+		log.Printf("Удалены курсы валют, исключенных из CURRENCY_ALLOWLIST: %d", len(pruned))
+	}
+	return nil
+}
 
-	// 1. Получение курсов от API
-	rates, err := api.FetchCBExchangeRates(s.apiURL)
-	if err != nil {
-		return fmt.Errorf("ошибка получения курсов: %v", err)
+// StoreRates записывает курсы источника в БД в одной транзакции
+// Изменившиеся курсы записываются в журнал rate_audit и в событие outbox,
+// неизменившиеся не перезаписываются - время их получения записывается в rate_confirmations
+func (s *PostgresStorage) StoreRates(ctx context.Context, rates map[string]float64, source string) error {
+	if len(rates) == 0 {
+		return nil
 	}
+	started := time.Now()
 
-	// 2. Начало транзакции
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// 1. Начало транзакции (таймаут действует на всю транзакцию)
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
 	defer cancel()
 
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -50,93 +77,188 @@ func (s *PostgresStorage) UpdateRatesFromCB() error {
 		}
 	}(tx)
 
-	// 3. Обновление курсов в БД
-	for currency, rate := range rates {
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO exchange_rates (currency, rate) 
-			 VALUES ($1, $2)
-			 ON CONFLICT (currency) DO UPDATE SET rate = $2, updated_at = NOW()`,
-			currency, rate)
-		if err != nil {
-			return fmt.Errorf("ошибка обновления курса %s: %v", currency, err)
-		}
+	// 2. Обновление курсов в БД
+	changed, err := upsertRatesTx(ctx, tx, rates, source, s.baseCurrency)
+	if err != nil {
+		return err
+	}
+
+	// 3. Подтверждение всех полученных курсов (неизменившиеся курсы считаются обновленными)
+	if err := confirmRatesTx(ctx, tx, rates, source, s.baseCurrency); err != nil {
+		return err
 	}
 
-	// 4. Фиксация транзакции
+	// 4. Событие об изменении курсов для сброса кэша клиентов
+	if err := s.recordRateEvent(ctx, tx, changed); err != nil {
+		return err
+	}
+
+	// 5. Фиксация транзакции
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("ошибка фиксации транзакции: %v", err)
 	}
+	metrics.RateStoreDuration.WithLabelValues(source).Observe(time.Since(started).Seconds())
+	metrics.RateWrites.WithLabelValues(source, "changed").Add(float64(len(changed)))
+	metrics.RateWrites.WithLabelValues(source, "unchanged").Add(float64(len(rates) - len(changed)))
+	return nil
+}
+
+// upsertRatesTx записывает курсы источника в транзакции tx одним запросом и возвращает валюты, курс которых изменился
+// Строки курсов, значение и базовая валюта которых не изменились, не перезаписываются;
+// изменившиеся курсы записываются в журнал rate_audit
+// (подзапрос previous видит таблицу до обновления, поэтому содержит старый курс)
+func upsertRatesTx(ctx context.Context, tx *sql.Tx, rates map[string]float64, source, baseCurrency string) ([]string, error) {
+	if len(rates) == 0 {
+		return nil, nil
+	}
+	currencies := make([]string, 0, len(rates))
+	values := make([]float64, 0, len(rates))
+	for currency, rate := range rates {
+		currencies = append(currencies, currency)
+		values = append(values, rate)
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`WITH previous AS (
+			SELECT currency, rate FROM exchange_rates
+			WHERE currency = ANY($1::VARCHAR[]) AND source = $3 AND base_currency = $4
+		), upserted AS (
+			INSERT INTO exchange_rates (currency, rate, source, base_currency)
+			SELECT currency, rate, $3, $4 FROM unnest($1::VARCHAR[], $2::DOUBLE PRECISION[]) AS incoming (currency, rate)
+			ON CONFLICT (currency, source) DO UPDATE
+			SET rate = EXCLUDED.rate, base_currency = EXCLUDED.base_currency, updated_at = NOW()
+			WHERE exchange_rates.rate IS DISTINCT FROM EXCLUDED.rate
+			   OR exchange_rates.base_currency IS DISTINCT FROM EXCLUDED.base_currency
+			RETURNING currency, rate
+		)
+		INSERT INTO rate_audit (currency, source, old_rate, new_rate, base_currency)
+		SELECT upserted.currency, $3, previous.rate, upserted.rate, $4
+		FROM upserted LEFT JOIN previous USING (currency)
+		RETURNING currency`,
+		pq.Array(currencies), pq.Array(values), source, baseCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка обновления курсов: %v", err)
+	}
+	changed, err := scanCurrencies(rows)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка обновления курсов: %v", err)
+	}
+	return changed, nil
+}
 
-	log.Println("Курсы валют успешно обновлены")
+// confirmRatesTx записывает время получения курсов источника в rate_confirmations одной строкой
+// Время подтверждения учитывается как время обновления курса, если курс не перезаписывался
+func confirmRatesTx(ctx context.Context, tx *sql.Tx, rates map[string]float64, source, baseCurrency string) error {
+	currencies := make([]string, 0, len(rates))
+	for currency := range rates {
+		currencies = append(currencies, currency)
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO rate_confirmations (source, base_currency, confirmed)
+		 SELECT $1, $2, jsonb_object_agg(currency, NOW()) FROM unnest($3::VARCHAR[]) AS currency
+		 ON CONFLICT (source, base_currency) DO UPDATE
+		 SET confirmed = rate_confirmations.confirmed || EXCLUDED.confirmed`,
+		source, baseCurrency, pq.Array(currencies))
+	if err != nil {
+		return fmt.Errorf("ошибка подтверждения курсов: %v", err)
+	}
 	return nil
 }
 
 // GetRate возвращает курс обмена между двумя валютами
+// Курс каждой валюты берется из источника с наивысшим приоритетом (ручной курс важнее поставщиков)
 // Параметры:
 //   - ctx: контекст выполнения
 //   - from: исходная валюта
 //   - to: целевая валюта
 //
 // Возвращает:
-//   - float64: курс обмена
+//   - storages.RateQuote: курс обмена и источники курсов валют
 //   - error: ошибка при получении
-func (s *PostgresStorage) GetRate(ctx context.Context, from, to string) (float64, error) {
+func (s *PostgresStorage) GetRate(ctx context.Context, from, to string) (storages.RateQuote, error) {
 	if from == to {
-		return 1.0, nil // Курс одинаковых валют всегда 1
+		return storages.RateQuote{Rate: 1.0}, nil // Курс одинаковых валют всегда 1
 	}
 
-	// Случай 1: Одна из валют - USD
-	if from == "USD" || to == "USD" {
-		targetCurrency := to
-		if from == "USD" {
-			targetCurrency = to
-		} else {
-			targetCurrency = from
-		}
-
-		query := "SELECT rate FROM exchange_rates WHERE currency = $1"
-		var rate float64
-		err := s.db.QueryRowContext(ctx, query, targetCurrency).Scan(&rate)
+	// Курсы хранятся как стоимость единицы валюты в базовой валюте, поэтому
+	// 1 from = source.Rate базовой валюты = source.Rate / target.Rate единиц to
+	source, err := s.baseOrCurrencyRate(ctx, from)
+	if err != nil {
+		return storages.RateQuote{}, err
+	}
+	target, err := s.baseOrCurrencyRate(ctx, to)
+	if err != nil {
+		return storages.RateQuote{}, err
+	}
+	return storages.RateQuote{
+		Rate:       source.Rate / target.Rate,
+		FromSource: source.Source,
+		ToSource:   target.Source,
+	}, nil
+}
 
-		if err != nil {
-			return 0, fmt.Errorf("курс для %s не найден: %v", targetCurrency, err)
-		}
+// BaseCurrency возвращает базовую валюту, к которой хранятся курсы
+func (s *PostgresStorage) BaseCurrency() string {
+	return s.baseCurrency
+}
 
-		if from == "USD" {
-			return rate, nil // Прямой курс (USD -> другая валюта)
-		}
-		return 1 / rate, nil // Обратный курс (другая валюта -> USD)
+// baseOrCurrencyRate возвращает курс валюты; курс базовой валюты всегда 1 и не имеет источника
+func (s *PostgresStorage) baseOrCurrencyRate(ctx context.Context, currency string) (storages.ExchangeRate, error) {
+	if currency == s.baseCurrency {
+		return storages.ExchangeRate{Currency: currency, Rate: 1}, nil
 	}
+	return s.currencyRate(ctx, currency)
+}
 
-	// Случай 2: Кросс-курс (через USD)
-	rateFromUSD, err := s.GetRate(ctx, from, "USD")
-	if err != nil {
-		return 0, err
-	}
-	rateToUSD, err := s.GetRate(ctx, "USD", to)
+// Время обновления курса: курс, не изменившийся при обновлении, не перезаписывается,
+// поэтому учитывается и время его последнего подтверждения источником (rate_confirmations)
+const (
+	rateConfirmationsJoin = `LEFT JOIN rate_confirmations USING (source, base_currency)`
+	rateUpdatedAt         = `GREATEST(updated_at, (rate_confirmations.confirmed ->> currency)::TIMESTAMPTZ)`
+)
+
+// currencyRate возвращает курс валюты из источника с наивысшим приоритетом
+func (s *PostgresStorage) currencyRate(ctx context.Context, currency string) (storages.ExchangeRate, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rate := storages.ExchangeRate{Currency: currency}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT rate, source, `+rateUpdatedAt+` FROM exchange_rates `+rateConfirmationsJoin+`
+		 WHERE currency = $1 AND base_currency = $3 AND (expires_at IS NULL OR expires_at > NOW())
+		 ORDER BY array_position($2::text[], source::text)
+		 LIMIT 1`,
+		currency, pq.Array(storages.SourcePriority), s.baseCurrency,
+	).Scan(&rate.Rate, &rate.Source, &rate.UpdatedAt)
 	if err != nil {
-		return 0, err
+		return storages.ExchangeRate{}, fmt.Errorf("курс для %s не найден: %v", currency, err)
 	}
-	return rateFromUSD * rateToUSD, nil // Расчет кросс-курса
+	return rate, nil
 }
 
 // GetAllRates возвращает все текущие курсы валют
-func (s *PostgresStorage) GetAllRates(ctx context.Context) (map[string]float64, error) {
-	query := "SELECT currency, rate FROM exchange_rates"
-	rows, err := s.db.QueryContext(ctx, query)
+// Для каждой валюты выбирается курс из источника с наивысшим приоритетом
+func (s *PostgresStorage) GetAllRates(ctx context.Context) (map[string]storages.ExchangeRate, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	query := `SELECT DISTINCT ON (currency) currency, rate, source, ` + rateUpdatedAt + `
+		FROM exchange_rates ` + rateConfirmationsJoin + `
+		WHERE base_currency = $2 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY currency, array_position($1::text[], source::text)`
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(storages.SourcePriority), s.baseCurrency)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка запроса курсов: %v", err)
 	}
 	defer rows.Close()
 
-	rates := make(map[string]float64)
+	rates := make(map[string]storages.ExchangeRate)
 	for rows.Next() {
-		var currency string
-		var rate float64
-		if err := rows.Scan(&currency, &rate); err != nil {
+		var rate storages.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.Source, &rate.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("ошибка чтения данных: %v", err)
 		}
-		rates[currency] = rate
+		rates[rate.Currency] = rate
 	}
 
 	if err := rows.Err(); err != nil {
@@ -145,3 +267,39 @@ func (s *PostgresStorage) GetAllRates(ctx context.Context) (map[string]float64,
 
 	return rates, nil
 }
+
+// GetRateChanges возвращает изменения курса валюты за период [from, to) в порядке времени
+// Возвращает не более limit записей
+func (s *PostgresStorage) GetRateChanges(ctx context.Context, currency string, from, to time.Time, limit int) ([]storages.RateChange, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT currency, source, old_rate, new_rate, changed_at FROM rate_audit
+		 WHERE currency = $1 AND base_currency = $5 AND changed_at >= $2 AND changed_at < $3
+		 ORDER BY changed_at, id
+		 LIMIT $4`,
+		currency, from, to, limit, s.baseCurrency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса изменений курса: %v", err)
+	}
+	defer rows.Close()
+
+	var changes []storages.RateChange
+	for rows.Next() {
+		var change storages.RateChange
+		var oldRate sql.NullFloat64
+		if err := rows.Scan(&change.Currency, &change.Source, &oldRate, &change.NewRate, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %v", err)
+		}
+		change.OldRate = oldRate.Float64
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %v", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	storages "gw-exchanger/internal/storage"
+	"log"
+	"sort"
+	"time"
+)
+
+// Параметры очистки outbox: события нужны клиентам только для возобновления потока после переподключения
+const (
+	outboxRetention       = 24 * time.Hour // Время хранения событий
+	outboxCleanupInterval = time.Hour      // Интервал удаления старых событий
+)
+
+// recordRateEvent записывает событие об изменении курсов валют в outbox в транзакции изменения
+// Пустой список валют не записывается
+func (s *PostgresStorage) recordRateEvent(ctx context.Context, tx *sql.Tx, currencies []string) error {
+	if len(currencies) == 0 {
+		return nil
+	}
+	sort.Strings(currencies)
+
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO rate_outbox (base_currency, currencies) VALUES ($1, $2)",
+		s.baseCurrency, pq.Array(currencies),
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи события изменения курсов: %w", err)
+	}
+	return nil
+}
+
+// RateEventsAfter возвращает события об изменении курсов с идентификатором больше afterID
+// Параметры:
+//   - ctx: контекст выполнения
+//   - afterID: идентификатор последнего полученного события
+//   - limit: максимальное количество событий
+//
+// Возвращает:
+//   - []storages.RateEvent: события в порядке записи
+//   - error: ошибка БД
+func (s *PostgresStorage) RateEventsAfter(ctx context.Context, afterID int64, limit int) ([]storages.RateEvent, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, base_currency, currencies, created_at FROM rate_outbox
+		 WHERE id > $1 AND base_currency = $2 ORDER BY id LIMIT $3`,
+		afterID, s.baseCurrency, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения событий изменения курсов: %w", err)
+	}
+	defer rows.Close()
+
+	var events []storages.RateEvent
+	for rows.Next() {
+		var event storages.RateEvent
+		if err := rows.Scan(&event.ID, &event.BaseCurrency, pq.Array(&event.Currencies), &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения события изменения курсов: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// LastRateEventID возвращает идентификатор последнего события об изменении курсов (0 - событий нет)
+func (s *PostgresStorage) LastRateEventID(ctx context.Context) (int64, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	var id int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM rate_outbox").Scan(&id); err != nil {
+		return 0, fmt.Errorf("ошибка получения последнего события изменения курсов: %w", err)
+	}
+	return id, nil
+}
+
+// startOutboxCleanup периодически удаляет события старше outboxRetention
+// Работает до отмены контекста
+func (s *PostgresStorage) startOutboxCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cleanupCtx, cancel := withTimeout(ctx, s.queryTimeout)
+			_, err := s.db.ExecContext(cleanupCtx,
+				"DELETE FROM rate_outbox WHERE created_at < $1", time.Now().Add(-outboxRetention))
+			cancel()
+			if err != nil {
+				log.Printf("Ошибка очистки событий изменения курсов: %v", err)
+			}
+		}
+	}
+}
+
+// scanCurrencies читает коды валют из результата запроса и закрывает его
+func scanCurrencies(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var currencies []string
+	for rows.Next() {
+		var currency string
+		if err := rows.Scan(&currency); err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, currency)
+	}
+	return currencies, rows.Err()
+}
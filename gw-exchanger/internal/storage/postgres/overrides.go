@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	storages "gw-exchanger/internal/storage"
+	"log"
+	"time"
+)
+
+// Действия в журнале аудита ручных курсов
+const (
+	overrideActionSet    = "set"    // Установка курса
+	overrideActionClear  = "clear"  // Досрочная отмена
+	overrideActionExpire = "expire" // Истечение срока действия
+)
+
+// overrideExpiryActor - исполнитель в журнале аудита для курсов, снятых по истечении срока
+const overrideExpiryActor = "system"
+
+// SetOverride устанавливает ручной курс валюты до указанного времени
+// Действующий ручной курс валюты заменяется, установка записывается в журнал аудита
+// Параметры:
+//   - ctx: контекст выполнения
+//   - currency: код валюты (курс должен поставляться хотя бы одним источником)
+//   - rate: стоимость единицы валюты в базовой валюте
+//   - expiresAt: время окончания действия
+//   - actor: клиент, устанавливающий курс
+//   - reason: причина установки
+//
+// Возвращает:
+//   - storages.RateOverride: установленный курс
+//   - error: storages.ErrUnknownCurrency или ошибка БД
+func (s *PostgresStorage) SetOverride(
+	ctx context.Context,
+	currency string,
+	rate float64,
+	expiresAt time.Time,
+	actor string,
+	reason string,
+) (storages.RateOverride, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storages.RateOverride{}, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Ручной курс разрешен только для валют, которые поставляются источниками (защита от опечаток)
+	var known bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM exchange_rates WHERE currency = $1 AND source <> $2 AND base_currency = $3)",
+		currency, storages.SourceManual, s.baseCurrency,
+	).Scan(&known)
+	if err != nil {
+		return storages.RateOverride{}, fmt.Errorf("ошибка проверки валюты: %w", err)
+	}
+	if !known {
+		return storages.RateOverride{}, storages.ErrUnknownCurrency
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO exchange_rates (currency, rate, source, expires_at, set_by, base_currency)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (currency, source) DO UPDATE
+		 SET rate = EXCLUDED.rate, expires_at = EXCLUDED.expires_at, set_by = EXCLUDED.set_by,
+		     base_currency = EXCLUDED.base_currency, updated_at = NOW()`,
+		currency, rate, storages.SourceManual, expiresAt, actor, s.baseCurrency,
+	)
+	if err != nil {
+		return storages.RateOverride{}, fmt.Errorf("ошибка установки ручного курса: %w", err)
+	}
+
+	if err := auditOverride(ctx, tx, currency, overrideActionSet, rate, expiresAt, actor, reason); err != nil {
+		return storages.RateOverride{}, err
+	}
+	if err := s.recordRateEvent(ctx, tx, []string{currency}); err != nil {
+		return storages.RateOverride{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return storages.RateOverride{}, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+
+	return storages.RateOverride{Currency: currency, Rate: rate, ExpiresAt: expiresAt, SetBy: actor}, nil
+}
+
+// ClearOverride досрочно отменяет действующий ручной курс валюты
+// Параметры:
+//   - ctx: контекст выполнения
+//   - currency: код валюты
+//   - actor: клиент, отменяющий курс
+//   - reason: причина отмены
+//
+// Возвращает:
+//   - storages.RateOverride: отмененный курс
+//   - error: storages.ErrOverrideNotFound или ошибка БД
+func (s *PostgresStorage) ClearOverride(ctx context.Context, currency, actor, reason string) (storages.RateOverride, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storages.RateOverride{}, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	override := storages.RateOverride{Currency: currency}
+	var expiresAt sql.NullTime
+	var setBy sql.NullString
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM exchange_rates
+		 WHERE currency = $1 AND source = $2 AND base_currency = $3 AND (expires_at IS NULL OR expires_at > NOW())
+		 RETURNING rate, expires_at, set_by`,
+		currency, storages.SourceManual, s.baseCurrency,
+	).Scan(&override.Rate, &expiresAt, &setBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storages.RateOverride{}, storages.ErrOverrideNotFound
+	}
+	if err != nil {
+		return storages.RateOverride{}, fmt.Errorf("ошибка отмены ручного курса: %w", err)
+	}
+	override.ExpiresAt = expiresAt.Time
+	override.SetBy = setBy.String
+
+	if err := auditOverride(ctx, tx, currency, overrideActionClear, override.Rate, override.ExpiresAt, actor, reason); err != nil {
+		return storages.RateOverride{}, err
+	}
+	if err := s.recordRateEvent(ctx, tx, []string{currency}); err != nil {
+		return storages.RateOverride{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return storages.RateOverride{}, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return override, nil
+}
+
+// ListOverrides возвращает действующие ручные курсы, упорядоченные по валюте
+func (s *PostgresStorage) ListOverrides(ctx context.Context) ([]storages.RateOverride, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT currency, rate, expires_at, set_by FROM exchange_rates
+		 WHERE source = $1 AND base_currency = $2 AND (expires_at IS NULL OR expires_at > NOW())
+		 ORDER BY currency`,
+		storages.SourceManual, s.baseCurrency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса ручных курсов: %w", err)
+	}
+	defer rows.Close()
+
+	var overrides []storages.RateOverride
+	for rows.Next() {
+		var override storages.RateOverride
+		var expiresAt sql.NullTime
+		var setBy sql.NullString
+		if err := rows.Scan(&override.Currency, &override.Rate, &expiresAt, &setBy); err != nil {
+			return nil, fmt.Errorf("ошибка чтения ручного курса: %w", err)
+		}
+		override.ExpiresAt = expiresAt.Time
+		override.SetBy = setBy.String
+		overrides = append(overrides, override)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %w", err)
+	}
+	return overrides, nil
+}
+
+// ExpireOverrides удаляет ручные курсы с истекшим сроком и записывает их истечение в журнал аудита
+// Курсы с истекшим сроком не используются и до удаления, задача только наводит порядок в таблице
+// Возвращает количество снятых курсов
+func (s *PostgresStorage) ExpireOverrides(ctx context.Context) (int, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`WITH expired AS (
+			DELETE FROM exchange_rates
+			WHERE source = $1 AND expires_at <= NOW()
+			RETURNING currency, rate, expires_at
+		)
+		INSERT INTO rate_override_audit (currency, action, rate, expires_at, actor)
+		SELECT currency, $2, rate, expires_at, $3 FROM expired
+		RETURNING currency`,
+		storages.SourceManual, overrideActionExpire, overrideExpiryActor,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка снятия истекших ручных курсов: %w", err)
+	}
+	expired, err := scanCurrencies(rows)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка снятия истекших ручных курсов: %w", err)
+	}
+	if err := s.recordRateEvent(ctx, tx, expired); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return len(expired), nil
+}
+
+// startOverrideExpiry периодически снимает ручные курсы с истекшим сроком
+// Работает до отмены контекста
+func (s *PostgresStorage) startOverrideExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := s.ExpireOverrides(ctx)
+			if err != nil {
+				log.Printf("Ошибка снятия истекших ручных курсов: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Снято ручных курсов по истечении срока: %d", expired)
+			}
+		}
+	}
+}
+
+// auditOverride записывает действие с ручным курсом в журнал аудита
+func auditOverride(
+	ctx context.Context,
+	tx *sql.Tx,
+	currency, action string,
+	rate float64,
+	expiresAt time.Time,
+	actor, reason string,
+) error {
+	var expires sql.NullTime
+	if !expiresAt.IsZero() {
+		expires = sql.NullTime{Time: expiresAt, Valid: true}
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO rate_override_audit (currency, action, rate, expires_at, actor, reason)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		currency, action, rate, expires, actor, reason,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи в журнал аудита ручных курсов: %w", err)
+	}
+	return nil
+}
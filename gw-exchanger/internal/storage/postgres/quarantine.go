@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gw-exchanger/internal/metrics"
+	storages "gw-exchanger/internal/storage"
+	"log"
+	"math"
+)
+
+// ScreenRates отделяет курсы с резким скачком относительно опубликованных курсов источника
+// Курс, отклоняющийся от опубликованного больше чем на maxRateJump, отправляется на карантин
+// и не публикуется до решения администратора; курсы валют без опубликованного курса не проверяются
+// Параметры:
+//   - ctx: контекст выполнения
+//   - rates: полученные курсы (ключ - код валюты)
+//   - source: источник курсов
+//
+// Возвращает:
+//   - map[string]float64: курсы, прошедшие проверку
+//   - error: ошибка БД
+func (s *PostgresStorage) ScreenRates(ctx context.Context, rates map[string]float64, source string) (map[string]float64, error) {
+	if s.maxRateJump <= 0 || len(rates) == 0 {
+		return rates, nil
+	}
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	published, err := s.sourceRates(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := make(map[string]float64, len(rates))
+	for currency, rate := range rates {
+		previous, ok := published[currency]
+		if !ok || previous <= 0 {
+			accepted[currency] = rate
+			continue
+		}
+		deviation := math.Abs(rate-previous) / previous
+		if deviation <= s.maxRateJump {
+			accepted[currency] = rate
+			continue
+		}
+
+		if err := s.quarantineRate(ctx, currency, source, previous, rate, deviation); err != nil {
+			return nil, err
+		}
+		metrics.QuarantinedRates.WithLabelValues(currency, source).Inc()
+		log.Printf("Курс %s (%s) на карантине: %f -> %f, отклонение %.1f%% больше допустимых %.1f%%",
+			currency, source, previous, rate, deviation*100, s.maxRateJump*100)
+	}
+	return accepted, nil
+}
+
+// sourceRates возвращает опубликованные курсы источника к базовой валюте хранилища
+func (s *PostgresStorage) sourceRates(ctx context.Context, source string) (map[string]float64, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT currency, rate FROM exchange_rates WHERE source = $1 AND base_currency = $2",
+		source, s.baseCurrency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса опубликованных курсов: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make(map[string]float64)
+	for rows.Next() {
+		var currency string
+		var rate float64
+		if err := rows.Scan(&currency, &rate); err != nil {
+			return nil, fmt.Errorf("ошибка чтения опубликованного курса: %w", err)
+		}
+		rates[currency] = rate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %w", err)
+	}
+	return rates, nil
+}
+
+// quarantineRate записывает подозрительный курс на карантин
+// Если курс валюты от источника уже ожидает проверки, запись обновляется последним полученным значением
+func (s *PostgresStorage) quarantineRate(ctx context.Context, currency, source string, previous, rate, deviation float64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO rate_quarantine (currency, source, base_currency, previous_rate, rate, deviation)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (currency, source, base_currency) WHERE status = 'pending' DO UPDATE
+		 SET previous_rate = EXCLUDED.previous_rate, rate = EXCLUDED.rate,
+		     deviation = EXCLUDED.deviation, updated_at = NOW()`,
+		currency, source, s.baseCurrency, previous, rate, deviation,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи курса %s на карантин: %w", currency, err)
+	}
+	return nil
+}
+
+// ListQuarantine возвращает курсы на карантине, ожидающие решения, в порядке поступления
+func (s *PostgresStorage) ListQuarantine(ctx context.Context) ([]storages.QuarantinedRate, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+quarantineColumns+` FROM rate_quarantine
+		 WHERE status = $1 AND base_currency = $2
+		 ORDER BY created_at, id`,
+		storages.QuarantinePending, s.baseCurrency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса курсов на карантине: %w", err)
+	}
+	defer rows.Close()
+
+	var quarantined []storages.QuarantinedRate
+	for rows.Next() {
+		rate, err := scanQuarantinedRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		quarantined = append(quarantined, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %w", err)
+	}
+	return quarantined, nil
+}
+
+// ResolveQuarantine принимает решение по курсу на карантине
+// Принятый курс записывается как курс источника (с журналом rate_audit и событием outbox)
+// в той же транзакции, что и решение
+// Параметры:
+//   - ctx: контекст выполнения
+//   - id: идентификатор записи карантина
+//   - approve: true - принять курс, false - отклонить
+//   - actor: клиент, принимающий решение
+//   - reason: причина решения
+//
+// Возвращает:
+//   - storages.QuarantinedRate: запись карантина после решения
+//   - error: storages.ErrQuarantineNotFound или ошибка БД
+func (s *PostgresStorage) ResolveQuarantine(ctx context.Context, id int64, approve bool, actor, reason string) (storages.QuarantinedRate, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storages.QuarantinedRate{}, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	status := storages.QuarantineRejected
+	if approve {
+		status = storages.QuarantineApproved
+	}
+	resolved, err := scanQuarantinedRate(tx.QueryRowContext(ctx,
+		`UPDATE rate_quarantine
+		 SET status = $2, resolved_by = $3, reason = $4, resolved_at = NOW()
+		 WHERE id = $1 AND status = $5 AND base_currency = $6
+		 RETURNING `+quarantineColumns,
+		id, status, actor, reason, storages.QuarantinePending, s.baseCurrency,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return storages.QuarantinedRate{}, storages.ErrQuarantineNotFound
+	}
+	if err != nil {
+		return storages.QuarantinedRate{}, err
+	}
+
+	if approve {
+		changed, err := upsertRatesTx(ctx, tx, map[string]float64{resolved.Currency: resolved.Rate}, resolved.Source, s.baseCurrency)
+		if err != nil {
+			return storages.QuarantinedRate{}, err
+		}
+		if err := s.recordRateEvent(ctx, tx, changed); err != nil {
+			return storages.QuarantinedRate{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return storages.QuarantinedRate{}, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return resolved, nil
+}
+
+// quarantineColumns - столбцы записи карантина в порядке scanQuarantinedRate
+const quarantineColumns = `id, currency, source, previous_rate, rate, deviation, status,
+	created_at, updated_at, resolved_by, reason`
+
+// scanQuarantinedRate читает запись карантина из строки результата
+func scanQuarantinedRate(row interface{ Scan(dest ...any) error }) (storages.QuarantinedRate, error) {
+	var rate storages.QuarantinedRate
+	var resolvedBy sql.NullString
+	err := row.Scan(&rate.ID, &rate.Currency, &rate.Source, &rate.PreviousRate, &rate.Rate, &rate.Deviation,
+		&rate.Status, &rate.CreatedAt, &rate.UpdatedAt, &resolvedBy, &rate.Reason)
+	if err != nil {
+		return storages.QuarantinedRate{}, fmt.Errorf("ошибка чтения курса на карантине: %w", err)
+	}
+	rate.ResolvedBy = resolvedBy.String
+	return rate, nil
+}
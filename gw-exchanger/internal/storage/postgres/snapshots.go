@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	storages "gw-exchanger/internal/storage"
+	"log"
+	"time"
+)
+
+// TakeSnapshot сохраняет снимок опубликованных курсов за дату
+// Для каждой валюты берется курс из источника с наивысшим приоритетом (как в GetAllRates)
+// Существующий снимок за дату не перезаписывается
+// Возвращает количество сохраненных курсов (0, если снимок уже существовал)
+func (s *PostgresStorage) TakeSnapshot(ctx context.Context, date time.Time) (int, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO rate_snapshots (snapshot_date, currency, base_currency, rate, source)
+		 SELECT DISTINCT ON (currency) $1::date, currency, base_currency, rate, source
+		 FROM exchange_rates
+		 WHERE base_currency = $2 AND (expires_at IS NULL OR expires_at > NOW())
+		 ORDER BY currency, array_position($3::text[], source::text)
+		 ON CONFLICT (snapshot_date, base_currency, currency) DO NOTHING`,
+		date.Format(time.DateOnly), s.baseCurrency, pq.Array(storages.SourcePriority),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка сохранения снимка курсов: %v", err)
+	}
+	saved, _ := result.RowsAffected()
+	return int(saved), nil
+}
+
+// GetSnapshot возвращает снимок курсов за дату
+// Нулевая дата означает последний сохраненный снимок
+// Возвращает storages.ErrSnapshotNotFound, если снимка нет
+func (s *PostgresStorage) GetSnapshot(ctx context.Context, date time.Time) (storages.RateSnapshot, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	var day any
+	if !date.IsZero() {
+		day = date.Format(time.DateOnly)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT snapshot_date, currency, rate, source, taken_at FROM rate_snapshots
+		 WHERE base_currency = $1 AND snapshot_date = COALESCE($2::date,
+		     (SELECT MAX(snapshot_date) FROM rate_snapshots WHERE base_currency = $1))
+		 ORDER BY currency`,
+		s.baseCurrency, day,
+	)
+	if err != nil {
+		return storages.RateSnapshot{}, fmt.Errorf("ошибка запроса снимка курсов: %v", err)
+	}
+	defer rows.Close()
+
+	snapshot := storages.RateSnapshot{BaseCurrency: s.baseCurrency, Rates: make(map[string]storages.ExchangeRate)}
+	for rows.Next() {
+		var rate storages.ExchangeRate
+		if err := rows.Scan(&snapshot.Date, &rate.Currency, &rate.Rate, &rate.Source, &snapshot.TakenAt); err != nil {
+			return storages.RateSnapshot{}, fmt.Errorf("ошибка чтения снимка курсов: %v", err)
+		}
+		rate.UpdatedAt = snapshot.TakenAt
+		snapshot.Rates[rate.Currency] = rate
+	}
+	if err := rows.Err(); err != nil {
+		return storages.RateSnapshot{}, fmt.Errorf("ошибка обработки результатов: %v", err)
+	}
+
+	if len(snapshot.Rates) == 0 {
+		return storages.RateSnapshot{}, storages.ErrSnapshotNotFound
+	}
+	return snapshot, nil
+}
+
+// RunDailySnapshots ежедневно сохраняет снимок курсов в момент at (время суток в часовом поясе loc)
+// При запуске сохраняет пропущенный снимок за последний наступивший момент (например, после простоя);
+// такой снимок содержит курсы на момент запуска. Работает до отмены контекста
+// Параметры:
+//   - ctx: контекст жизни планировщика
+//   - at: время суток снимка (например 23ч59м)
+//   - loc: часовой пояс, в котором определяются сутки
+func (s *PostgresStorage) RunDailySnapshots(ctx context.Context, at time.Duration, loc *time.Location) {
+	now := time.Now().In(loc)
+	if last := lastOccurrence(now, at); !last.IsZero() {
+		s.takeSnapshot(ctx, last)
+	}
+
+	for {
+		next := lastOccurrence(now, at).AddDate(0, 0, 1)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.takeSnapshot(ctx, next)
+		now = next
+	}
+}
+
+// takeSnapshot сохраняет снимок за дату момента moment и пишет результат в лог
+func (s *PostgresStorage) takeSnapshot(ctx context.Context, moment time.Time) {
+	saved, err := s.TakeSnapshot(ctx, moment)
+	if err != nil {
+		log.Printf("Ошибка сохранения снимка курсов за %s: %v", moment.Format(time.DateOnly), err)
+		return
+	}
+	if saved > 0 {
+		log.Printf("Сохранен снимок курсов за %s: %d валют", moment.Format(time.DateOnly), saved)
+	}
+}
+
+// lastOccurrence возвращает последний наступивший момент времени суток at (не позже now)
+func lastOccurrence(now time.Time, at time.Duration) time.Time {
+	year, month, day := now.Date()
+	moment := time.Date(year, month, day, 0, 0, 0, 0, now.Location()).Add(at)
+	if moment.After(now) {
+		moment = time.Date(year, month, day-1, 0, 0, 0, 0, now.Location()).Add(at)
+	}
+	return moment
+}
@@ -0,0 +1,190 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/go-sql-driver/mysql"         // Драйвер MySQL
+	storages "gw-exchanger/internal/storage" // Интерфейсы и модели хранилища
+	"log"
+	_ "modernc.org/sqlite" // Драйвер SQLite без CGO (импорт для side effects)
+	"strings"
+	"time"
+)
+
+// Dialect описывает СУБД хранилища: драйвер, каталог миграций и обязательные параметры подключения
+// Запросы хранилища переносимы между диалектами (параметры "?", без функций СУБД);
+// различается только синтаксис upsert, который формирует диалект
+type Dialect struct {
+	Name         string                             // Название СУБД для логов
+	Driver       string                             // Имя драйвера database/sql
+	Migrations   string                             // Каталог миграций диалекта
+	MaxOpenConns int                                // Ограничение открытых соединений (0 - из параметров пула)
+	prepareDSN   func(dsn string) (string, error)   // Дополнение строки подключения обязательными параметрами
+	upsert       func(key, columns []string) string // Условие INSERT, обновляющее columns при конфликте по ключу key
+}
+
+// MySQL - хранилище в MySQL 8 (строка подключения в формате go-sql-driver: user:password@tcp(host:3306)/db)
+var MySQL = Dialect{
+	Name:       "MySQL",
+	Driver:     "mysql",
+	Migrations: "migrations/mysql",
+	prepareDSN: func(dsn string) (string, error) {
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return "", err
+		}
+		// Время хранится в UTC и читается как time.Time, миграции выполняются одним запросом
+		cfg.ParseTime = true
+		cfg.Loc = time.UTC
+		cfg.MultiStatements = true
+		return cfg.FormatDSN(), nil
+	},
+	upsert: func(_, columns []string) string {
+		set := make([]string, len(columns))
+		for i, column := range columns {
+			set[i] = column + " = VALUES(" + column + ")"
+		}
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+	},
+}
+
+// SQLite - хранилище в файле SQLite для встроенных и локальных запусков (строка подключения - путь к файлу)
+// Запись в SQLite выполняется одним соединением, поэтому пул ограничен одним соединением
+var SQLite = Dialect{
+	Name:         "SQLite",
+	Driver:       "sqlite",
+	Migrations:   "migrations/sqlite",
+	MaxOpenConns: 1,
+	prepareDSN: func(dsn string) (string, error) {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		return dsn + separator + "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite", nil
+	},
+	upsert: func(key, columns []string) string {
+		set := make([]string, len(columns))
+		for i, column := range columns {
+			set[i] = column + " = excluded." + column
+		}
+		return "ON CONFLICT (" + strings.Join(key, ", ") + ") DO UPDATE SET " + strings.Join(set, ", ")
+	},
+}
+
+// Store - хранилище курсов в MySQL или SQLite
+// Поддерживает основной интерфейс storages.Storage и справочник валют; ручные курсы, история,
+// снимки и поток событий об изменении курсов поддерживаются только хранилищем PostgreSQL
+type Store struct {
+	db           *sql.DB       // Подключение к базе данных
+	dialect      Dialect       // СУБД хранилища
+	maxRateJump  float64       // Допустимое изменение курса поставщика за обновление в долях (0 - без карантина)
+	baseCurrency string        // Базовая валюта, к которой хранятся курсы
+	queryTimeout time.Duration // Максимальное время выполнения запроса (или транзакции)
+}
+
+// NewStore создает подключение к хранилищу и применяет миграции диалекта
+// Параметры:
+//   - ctx: контекст выполнения (отмена прерывает подключение и миграции)
+//   - dialect: СУБД хранилища (MySQL или SQLite)
+//   - dsn: строка подключения (для SQLite - путь к файлу БД)
+//   - maxRateJump: допустимое изменение курса за обновление в долях, больший скачок - на карантин (0 - без проверки)
+//   - baseCurrency: базовая валюта (курсы поставщиков пересчитываются к ней)
+//   - queryTimeout: максимальное время выполнения запроса к БД
+//   - pool: параметры пула соединений
+//
+// Возвращает:
+//   - *Store: инициализированное хранилище
+//   - error: ошибка при создании
+func NewStore(
+	ctx context.Context,
+	dialect Dialect,
+	dsn string,
+	maxRateJump float64,
+	baseCurrency string,
+	queryTimeout time.Duration,
+	pool storages.PoolOptions,
+) (*Store, error) {
+	dsn, err := dialect.prepareDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная строка подключения %s: %v", dialect.Name, err)
+	}
+
+	// 1. Подключение к БД
+	db, err := sql.Open(dialect.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подключения к %s: %v", dialect.Name, err)
+	}
+
+	// Настройка пула соединений
+	maxOpenConns := pool.MaxOpenConns
+	if dialect.MaxOpenConns > 0 {
+		maxOpenConns = dialect.MaxOpenConns
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	log.Printf("Пул соединений %s: max_open=%d, max_idle=%d, max_lifetime=%s",
+		dialect.Name, maxOpenConns, pool.MaxIdleConns, pool.ConnMaxLifetime)
+
+	// 2. Проверка подключения с таймаутом 3 секунды
+	pingCtx, cancelPing := context.WithTimeout(ctx, 3*time.Second)
+	defer cancelPing()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка проверки подключения: %v", err)
+	}
+
+	// 3. Применение миграций диалекта
+	if err := storages.ApplyMigrations(ctx, db, dialect.Migrations); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка применения миграций: %v", err)
+	}
+
+	log.Printf("Успешное подключение к %s", dialect.Name)
+
+	return &Store{
+		db:           db,
+		dialect:      dialect,
+		maxRateJump:  maxRateJump,
+		baseCurrency: baseCurrency,
+		queryTimeout: queryTimeout,
+	}, nil
+}
+
+// Close закрывает подключение к БД
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// BaseCurrency возвращает базовую валюту, к которой хранятся курсы
+func (s *Store) BaseCurrency() string {
+	return s.baseCurrency
+}
+
+// withTimeout ограничивает время выполнения запроса
+// Если контекст вызывающего уже содержит более ранний дедлайн, действует он
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// now возвращает текущее время в UTC с точностью до микросекунд
+// Время записывается из приложения, а не функциями СУБД: так его формат одинаков во всех диалектах
+// (в SQLite время хранится текстом и сравнивается как строка)
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// placeholders возвращает список параметров "?, ?, ..." для условия IN
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// valuesRows возвращает строки параметров "(?, ?), (?, ?), ..." для INSERT нескольких строк
+func valuesRows(rows, columns int) string {
+	row := "(" + placeholders(columns) + ")"
+	return strings.TrimSuffix(strings.Repeat(row+", ", rows), ", ")
+}
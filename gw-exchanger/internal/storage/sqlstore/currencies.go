@@ -0,0 +1,88 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	storages "gw-exchanger/internal/storage"
+)
+
+// StoreCurrencies записывает сведения о валютах из ответа поставщика в справочник currencies
+// Название и номинал обновляются, количество знаков после запятой записывается только для новых валют
+// (значение в справочнике могло быть исправлено вручную)
+func (s *Store) StoreCurrencies(ctx context.Context, currencies []storages.Currency) error {
+	if len(currencies) == 0 {
+		return nil
+	}
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	updatedAt := now()
+	for _, currency := range currencies {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE currencies SET name = ?, nominal = ?, updated_at = ?
+			 WHERE code = ? AND (name <> ? OR nominal <> ?)`,
+			currency.Name, currency.Nominal, updatedAt, currency.Code, currency.Name, currency.Nominal)
+		if err != nil {
+			return fmt.Errorf("ошибка записи справочника валют: %w", err)
+		}
+		if updated, err := result.RowsAffected(); err == nil && updated > 0 {
+			continue
+		}
+		// Сведения не изменились или валюта новая
+		var exists int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM currencies WHERE code = ?", currency.Code).Scan(&exists); err != nil {
+			return fmt.Errorf("ошибка записи справочника валют: %w", err)
+		}
+		if exists > 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO currencies (code, name, nominal, decimals, updated_at) VALUES (?, ?, ?, ?, ?)",
+			currency.Code, currency.Name, currency.Nominal, currency.Decimals, updatedAt); err != nil {
+			return fmt.Errorf("ошибка записи справочника валют: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// ListCurrencies возвращает справочник валют в порядке кодов
+// Параметры:
+//   - ctx: контекст выполнения
+//
+// Возвращает:
+//   - []storages.Currency: сведения о валютах (название, номинал, знаки после запятой)
+//   - error: ошибка БД
+func (s *Store) ListCurrencies(ctx context.Context) ([]storages.Currency, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT code, name, nominal, decimals, updated_at FROM currencies ORDER BY code")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса справочника валют: %w", err)
+	}
+	defer rows.Close()
+
+	var currencies []storages.Currency
+	for rows.Next() {
+		var currency storages.Currency
+		if err := rows.Scan(&currency.Code, &currency.Name, &currency.Nominal, &currency.Decimals, &currency.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения валюты: %w", err)
+		}
+		currencies = append(currencies, currency)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %w", err)
+	}
+	return currencies, nil
+}
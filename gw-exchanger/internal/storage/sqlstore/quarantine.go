@@ -0,0 +1,85 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"gw-exchanger/internal/metrics"
+	"log"
+	"math"
+)
+
+// ScreenRates отделяет курсы с резким скачком относительно опубликованных курсов источника
+// Курс, отклоняющийся от опубликованного больше чем на maxRateJump, отправляется на карантин;
+// ручное решение по карантину через RateAdminService доступно только в PostgreSQL,
+// в MySQL и SQLite курс на карантине принимается или отклоняется изменением статуса в таблице rate_quarantine
+// Параметры:
+//   - ctx: контекст выполнения
+//   - rates: полученные курсы (ключ - код валюты)
+//   - source: источник курсов
+//
+// Возвращает:
+//   - map[string]float64: курсы, прошедшие проверку
+//   - error: ошибка БД
+func (s *Store) ScreenRates(ctx context.Context, rates map[string]float64, source string) (map[string]float64, error) {
+	if s.maxRateJump <= 0 || len(rates) == 0 {
+		return rates, nil
+	}
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	published, err := sourceRatesTx(ctx, tx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := make(map[string]float64, len(rates))
+	var quarantined []string
+	for currency, rate := range rates {
+		previous, ok := published[currency]
+		if !ok || previous.baseCurrency != s.baseCurrency || previous.rate <= 0 {
+			accepted[currency] = rate
+			continue
+		}
+		deviation := math.Abs(rate-previous.rate) / previous.rate
+		if deviation <= s.maxRateJump {
+			accepted[currency] = rate
+			continue
+		}
+
+		// Если курс валюты от источника уже ожидает проверки, запись обновляется последним полученным значением
+		updatedAt := now()
+		result, err := tx.ExecContext(ctx,
+			`UPDATE rate_quarantine SET previous_rate = ?, rate = ?, deviation = ?, updated_at = ?
+			 WHERE currency = ? AND source = ? AND base_currency = ? AND status = 'pending'`,
+			previous.rate, rate, deviation, updatedAt, currency, source, s.baseCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка записи курса %s на карантин: %v", currency, err)
+		}
+		if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO rate_quarantine (currency, source, base_currency, previous_rate, rate, deviation, created_at, updated_at)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				currency, source, s.baseCurrency, previous.rate, rate, deviation, updatedAt, updatedAt); err != nil {
+				return nil, fmt.Errorf("ошибка записи курса %s на карантин: %v", currency, err)
+			}
+		}
+		quarantined = append(quarantined, currency)
+		log.Printf("Курс %s (%s) на карантине: %f -> %f, отклонение %.1f%% больше допустимых %.1f%%",
+			currency, source, previous.rate, rate, deviation*100, s.maxRateJump*100)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("ошибка фиксации транзакции: %v", err)
+	}
+	for _, currency := range quarantined {
+		metrics.QuarantinedRates.WithLabelValues(currency, source).Inc()
+	}
+	return accepted, nil
+}
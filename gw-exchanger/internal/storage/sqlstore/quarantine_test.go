@@ -0,0 +1,132 @@
+package sqlstore
+
+import (
+	"context"
+	storages "gw-exchanger/internal/storage"
+	"reflect"
+	"testing"
+)
+
+func TestStoreScreenRates(t *testing.T) {
+	published := map[string]float64{"USD": 90, "EUR": 100}
+
+	tests := []struct {
+		name        string
+		maxRateJump float64
+		received    map[string]float64
+		want        map[string]float64
+		quarantined []string
+	}{
+		{
+			name:        "изменение в допустимых пределах",
+			maxRateJump: 0.3,
+			received:    map[string]float64{"USD": 110, "EUR": 75},
+			want:        map[string]float64{"USD": 110, "EUR": 75},
+		},
+		{
+			name:        "скачок больше допустимого - на карантин",
+			maxRateJump: 0.3,
+			received:    map[string]float64{"USD": 120, "EUR": 60},
+			want:        map[string]float64{},
+			quarantined: []string{"EUR", "USD"},
+		},
+		{
+			name:        "нет опубликованного курса",
+			maxRateJump: 0.3,
+			received:    map[string]float64{"USD": 91, "GBP": 1000},
+			want:        map[string]float64{"USD": 91, "GBP": 1000},
+		},
+		{
+			name:        "карантин отключен",
+			maxRateJump: 0,
+			received:    map[string]float64{"USD": 900},
+			want:        map[string]float64{"USD": 900},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newTestStore(t, "RUB", tt.maxRateJump)
+			if err := store.StoreRates(ctx, published, storages.SourceCBR); err != nil {
+				t.Fatal(err)
+			}
+
+			accepted, err := store.ScreenRates(ctx, tt.received, storages.SourceCBR)
+			if err != nil {
+				t.Fatalf("ошибка проверки курсов: %v", err)
+			}
+			if !reflect.DeepEqual(accepted, tt.want) {
+				t.Errorf("приняты курсы %v, ожидались %v", accepted, tt.want)
+			}
+			if got := pendingQuarantine(t, store); !reflect.DeepEqual(got, tt.quarantined) {
+				t.Errorf("на карантине %v, ожидались %v", got, tt.quarantined)
+			}
+
+			// Курс на карантине не публикуется: записываются только принятые курсы
+			if err := store.StoreRates(ctx, accepted, storages.SourceCBR); err != nil {
+				t.Fatal(err)
+			}
+			for _, currency := range tt.quarantined {
+				quote, err := store.GetRate(ctx, currency, "RUB")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if quote.Rate != published[currency] {
+					t.Errorf("опубликован курс %s %v с карантина, ожидался прежний %v", currency, quote.Rate, published[currency])
+				}
+			}
+		})
+	}
+}
+
+func TestStoreScreenRatesRepeatedJump(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "RUB", 0.3)
+	if err := store.StoreRates(ctx, map[string]float64{"USD": 90}, storages.SourceCBR); err != nil {
+		t.Fatal(err)
+	}
+
+	// Повторный скачок обновляет ожидающую проверки запись, а не создает новую
+	for _, rate := range []float64{150, 160} {
+		if _, err := store.ScreenRates(ctx, map[string]float64{"USD": rate}, storages.SourceCBR); err != nil {
+			t.Fatalf("ошибка проверки курсов: %v", err)
+		}
+	}
+
+	var count int
+	var rate float64
+	err := store.db.QueryRowContext(ctx,
+		`SELECT COUNT(*), MAX(rate) FROM rate_quarantine WHERE currency = 'USD' AND status = 'pending'`,
+	).Scan(&count, &rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || rate != 160 {
+		t.Errorf("записей на карантине %d с курсом %v, ожидалась 1 с курсом 160", count, rate)
+	}
+}
+
+// pendingQuarantine возвращает валюты, ожидающие проверки, по алфавиту
+func pendingQuarantine(t *testing.T, store *Store) []string {
+	t.Helper()
+	rows, err := store.db.QueryContext(context.Background(),
+		`SELECT currency FROM rate_quarantine WHERE status = 'pending' ORDER BY currency`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var currencies []string
+	for rows.Next() {
+		var currency string
+		if err := rows.Scan(&currency); err != nil {
+			t.Fatal(err)
+		}
+		currencies = append(currencies, currency)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return currencies
+}
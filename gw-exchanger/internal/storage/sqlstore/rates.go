@@ -0,0 +1,335 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gw-exchanger/internal/metrics"
+	storages "gw-exchanger/internal/storage"
+	"log"
+	"maps"
+	"slices"
+	"time"
+)
+
+// PruneRates удаляет курсы источника для валют, не входящих в список хранимых
+// (например, после сокращения CURRENCY_ALLOWLIST), чтобы они не публиковались устаревшими
+func (s *Store) PruneRates(ctx context.Context, source string, allowlist []string) error {
+	if len(allowlist) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	args := []any{source, s.baseCurrency, s.baseCurrency}
+	for _, currency := range allowlist {
+		args = append(args, currency)
+	}
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM exchange_rates
+		 WHERE source = ? AND base_currency = ? AND currency <> ? AND currency NOT IN (`+placeholders(len(allowlist))+`)`,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления исключенных валют: %v", err)
+	}
+
+	if pruned, err := result.RowsAffected(); err == nil && pruned > 0 {
+		log.Printf("Удалены курсы валют, исключенных из CURRENCY_ALLOWLIST: %d", pruned)
+	}
+	return nil
+}
+
+// StoreRates записывает курсы источника в БД в одной транзакции
+// Новые и изменившиеся курсы записываются одним запросом upsert и одним запросом в журнал rate_audit,
+// неизменившиеся не перезаписываются - время их получения записывается в rate_confirmations
+func (s *Store) StoreRates(ctx context.Context, rates map[string]float64, source string) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	started := time.Now()
+
+	// 1. Начало транзакции (таймаут действует на всю транзакцию)
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	// 2. Опубликованные курсы источника (для отбора изменившихся курсов и журнала)
+	published, err := sourceRatesTx(ctx, tx, source)
+	if err != nil {
+		return err
+	}
+	currencies := slices.Sorted(maps.Keys(rates)) // Порядок строк в запросах не зависит от порядка обхода map
+	changed := slices.DeleteFunc(slices.Clone(currencies), func(currency string) bool {
+		previous, exists := published[currency]
+		return exists && previous.rate == rates[currency] && previous.baseCurrency == s.baseCurrency
+	})
+
+	// 3. Запись новых и изменившихся курсов и журнала изменений
+	updatedAt := now()
+	if err := s.upsertRatesTx(ctx, tx, changed, rates, source, updatedAt); err != nil {
+		return err
+	}
+	if err := s.auditRatesTx(ctx, tx, changed, rates, published, source, updatedAt); err != nil {
+		return err
+	}
+
+	// 4. Подтверждение всех полученных курсов (неизменившиеся курсы считаются обновленными)
+	if err := s.confirmRatesTx(ctx, tx, currencies, source, updatedAt); err != nil {
+		return err
+	}
+
+	// 5. Фиксация транзакции
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %v", err)
+	}
+	metrics.RateStoreDuration.WithLabelValues(source).Observe(time.Since(started).Seconds())
+	metrics.RateWrites.WithLabelValues(source, "changed").Add(float64(len(changed)))
+	metrics.RateWrites.WithLabelValues(source, "unchanged").Add(float64(len(rates) - len(changed)))
+	return nil
+}
+
+// upsertRatesTx записывает курсы валют currencies одним запросом (новые строки вставляются, существующие обновляются)
+func (s *Store) upsertRatesTx(ctx context.Context, tx *sql.Tx, currencies []string, rates map[string]float64, source string, updatedAt time.Time) error {
+	if len(currencies) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(currencies)*5)
+	for _, currency := range currencies {
+		args = append(args, currency, rates[currency], source, s.baseCurrency, updatedAt)
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO exchange_rates (currency, rate, source, base_currency, updated_at) VALUES `+valuesRows(len(currencies), 5)+` `+
+			s.dialect.upsert([]string{"currency", "source"}, []string{"rate", "base_currency", "updated_at"}),
+		args...)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления курсов: %v", err)
+	}
+	return nil
+}
+
+// auditRatesTx записывает изменения курсов валют currencies в журнал rate_audit одним запросом
+// Для новой валюты (или курса к другой базовой валюте) старого курса нет
+func (s *Store) auditRatesTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	currencies []string,
+	rates map[string]float64,
+	published map[string]publishedRate,
+	source string,
+	changedAt time.Time,
+) error {
+	if len(currencies) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(currencies)*6)
+	for _, currency := range currencies {
+		var oldRate sql.NullFloat64
+		if previous, exists := published[currency]; exists && previous.baseCurrency == s.baseCurrency {
+			oldRate = sql.NullFloat64{Float64: previous.rate, Valid: true}
+		}
+		args = append(args, currency, source, oldRate, rates[currency], s.baseCurrency, changedAt)
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO rate_audit (currency, source, old_rate, new_rate, base_currency, changed_at) VALUES `+valuesRows(len(currencies), 6),
+		args...)
+	if err != nil {
+		return fmt.Errorf("ошибка записи журнала курсов: %v", err)
+	}
+	return nil
+}
+
+// confirmRatesTx записывает время получения курсов валют currencies в rate_confirmations одним запросом
+// Время подтверждения учитывается как время обновления курса, если курс не перезаписывался
+func (s *Store) confirmRatesTx(ctx context.Context, tx *sql.Tx, currencies []string, source string, confirmedAt time.Time) error {
+	args := make([]any, 0, len(currencies)*4)
+	for _, currency := range currencies {
+		args = append(args, source, s.baseCurrency, currency, confirmedAt)
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO rate_confirmations (source, base_currency, currency, confirmed_at) VALUES `+valuesRows(len(currencies), 4)+` `+
+			s.dialect.upsert([]string{"source", "base_currency", "currency"}, []string{"confirmed_at"}),
+		args...)
+	if err != nil {
+		return fmt.Errorf("ошибка подтверждения курсов: %v", err)
+	}
+	return nil
+}
+
+// publishedRate - опубликованный курс источника
+type publishedRate struct {
+	rate         float64 // Курс к базовой валюте
+	baseCurrency string  // Базовая валюта курса
+}
+
+// sourceRatesTx возвращает опубликованные курсы источника (ключ - код валюты)
+func sourceRatesTx(ctx context.Context, tx *sql.Tx, source string) (map[string]publishedRate, error) {
+	rows, err := tx.QueryContext(ctx,
+		"SELECT currency, rate, base_currency FROM exchange_rates WHERE source = ?", source)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса опубликованных курсов: %v", err)
+	}
+	defer rows.Close()
+
+	rates := make(map[string]publishedRate)
+	for rows.Next() {
+		var currency string
+		var rate publishedRate
+		if err := rows.Scan(&currency, &rate.rate, &rate.baseCurrency); err != nil {
+			return nil, fmt.Errorf("ошибка чтения опубликованного курса: %v", err)
+		}
+		rates[currency] = rate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %v", err)
+	}
+	return rates, nil
+}
+
+// GetRate возвращает курс обмена между двумя валютами
+// Курс каждой валюты берется из источника с наивысшим приоритетом (ручной курс важнее поставщиков)
+// Параметры:
+//   - ctx: контекст выполнения
+//   - from: исходная валюта
+//   - to: целевая валюта
+//
+// Возвращает:
+//   - storages.RateQuote: курс обмена и источники курсов валют
+//   - error: ошибка при получении
+func (s *Store) GetRate(ctx context.Context, from, to string) (storages.RateQuote, error) {
+	if from == to {
+		return storages.RateQuote{Rate: 1.0}, nil // Курс одинаковых валют всегда 1
+	}
+
+	// 1 from = source.Rate базовой валюты = source.Rate / target.Rate единиц to
+	source, err := s.baseOrCurrencyRate(ctx, from)
+	if err != nil {
+		return storages.RateQuote{}, err
+	}
+	target, err := s.baseOrCurrencyRate(ctx, to)
+	if err != nil {
+		return storages.RateQuote{}, err
+	}
+	return storages.RateQuote{
+		Rate:       source.Rate / target.Rate,
+		FromSource: source.Source,
+		ToSource:   target.Source,
+	}, nil
+}
+
+// baseOrCurrencyRate возвращает курс валюты; курс базовой валюты всегда 1 и не имеет источника
+func (s *Store) baseOrCurrencyRate(ctx context.Context, currency string) (storages.ExchangeRate, error) {
+	if currency == s.baseCurrency {
+		return storages.ExchangeRate{Currency: currency, Rate: 1}, nil
+	}
+
+	rates, err := s.queryRates(ctx, "AND r.currency = ?", currency)
+	if err != nil {
+		return storages.ExchangeRate{}, err
+	}
+	rate, ok := rates[currency]
+	if !ok {
+		return storages.ExchangeRate{}, fmt.Errorf("курс для %s не найден: %v", currency, sql.ErrNoRows)
+	}
+	return rate, nil
+}
+
+// GetAllRates возвращает все текущие курсы валют
+// Для каждой валюты выбирается курс из источника с наивысшим приоритетом
+func (s *Store) GetAllRates(ctx context.Context) (map[string]storages.ExchangeRate, error) {
+	return s.queryRates(ctx, "")
+}
+
+// queryRates возвращает действующие курсы к базовой валюте с дополнительным условием filter (таблица курсов - r)
+// Источник курса выбирается по приоритету в приложении: переносимого аналога DISTINCT ON нет.
+// Неизменившийся курс не перезаписывается, поэтому время обновления - наибольшее из времени записи курса
+// и времени его последнего подтверждения источником (rate_confirmations)
+func (s *Store) queryRates(ctx context.Context, filter string, args ...any) (map[string]storages.ExchangeRate, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT r.currency, r.rate, r.source, r.updated_at, c.confirmed_at
+		 FROM exchange_rates r
+		 LEFT JOIN rate_confirmations c
+		   ON c.source = r.source AND c.base_currency = r.base_currency AND c.currency = r.currency
+		 WHERE r.base_currency = ? AND (r.expires_at IS NULL OR r.expires_at > ?) `+filter,
+		append([]any{s.baseCurrency, now()}, args...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса курсов: %v", err)
+	}
+	defer rows.Close()
+
+	rates := make(map[string]storages.ExchangeRate)
+	for rows.Next() {
+		var rate storages.ExchangeRate
+		var confirmedAt sql.NullTime
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.Source, &rate.UpdatedAt, &confirmedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %v", err)
+		}
+		if confirmedAt.Valid && confirmedAt.Time.After(rate.UpdatedAt) {
+			rate.UpdatedAt = confirmedAt.Time
+		}
+		if current, ok := rates[rate.Currency]; ok && sourceRank(current.Source) <= sourceRank(rate.Source) {
+			continue
+		}
+		rates[rate.Currency] = rate
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %v", err)
+	}
+
+	return rates, nil
+}
+
+// sourceRank возвращает позицию источника в storages.SourcePriority (неизвестные источники - последние)
+func sourceRank(source string) int {
+	if rank := slices.Index(storages.SourcePriority, source); rank >= 0 {
+		return rank
+	}
+	return len(storages.SourcePriority)
+}
+
+// GetRateChanges возвращает изменения курса валюты за период [from, to) в порядке времени
+// Возвращает не более limit записей
+func (s *Store) GetRateChanges(ctx context.Context, currency string, from, to time.Time, limit int) ([]storages.RateChange, error) {
+	ctx, cancel := withTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT currency, source, old_rate, new_rate, changed_at FROM rate_audit
+		 WHERE currency = ? AND base_currency = ? AND changed_at >= ? AND changed_at < ?
+		 ORDER BY changed_at, id
+		 LIMIT ?`,
+		currency, s.baseCurrency, from.UTC(), to.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса изменений курса: %v", err)
+	}
+	defer rows.Close()
+
+	var changes []storages.RateChange
+	for rows.Next() {
+		var change storages.RateChange
+		var oldRate sql.NullFloat64
+		if err := rows.Scan(&change.Currency, &change.Source, &oldRate, &change.NewRate, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %v", err)
+		}
+		change.OldRate = oldRate.Float64
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки результатов: %v", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,29 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+)
+
+// newBenchStore создает хранилище SQLite во временном каталоге с курсами USD и EUR к рублю
+func newBenchStore(b *testing.B) *Store {
+	b.Helper()
+	store := newTestStore(b, "RUB", 0)
+	if err := store.StoreRates(context.Background(), map[string]float64{"USD": 90, "EUR": 98}, "cbr"); err != nil {
+		b.Fatal(err)
+	}
+	return store
+}
+
+// BenchmarkStoreGetRate - чтение курса пары (бюджет getrate утилиты loadtest)
+func BenchmarkStoreGetRate(b *testing.B) {
+	store := newBenchStore(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := store.GetRate(ctx, "USD", "EUR"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	storages "gw-exchanger/internal/storage"
+	"io"
+	"log"
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestStore создает хранилище SQLite во временном каталоге
+// Параметры:
+//   - baseCurrency: базовая валюта хранилища
+//   - maxRateJump: допустимое изменение курса за обновление (0 - без карантина)
+func newTestStore(t testing.TB, baseCurrency string, maxRateJump float64) *Store {
+	t.Helper()
+	output := log.Writer()
+	log.SetOutput(io.Discard) // Журнал миграций, пула и карантина не нужен в выводе тестов
+	t.Cleanup(func() { log.SetOutput(output) })
+
+	dialect := SQLite
+	dialect.Migrations = filepath.Join("..", "..", "..", dialect.Migrations)
+	store, err := NewStore(context.Background(), dialect, filepath.Join(t.TempDir(), "rates.db"), maxRateJump, baseCurrency,
+		time.Second, storages.PoolOptions{MaxIdleConns: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStoreGetRateCrossRates(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		stored   map[string]float64 // Курсы к базовой валюте хранилища
+		from, to string
+		want     float64
+	}{
+		{name: "обе валюты не базовые", base: "RUB", stored: map[string]float64{"USD": 90, "EUR": 98}, from: "USD", to: "EUR", want: 90.0 / 98},
+		{name: "обратный кросс-курс", base: "RUB", stored: map[string]float64{"USD": 90, "EUR": 98}, from: "EUR", to: "USD", want: 98.0 / 90},
+		{name: "в базовую валюту", base: "RUB", stored: map[string]float64{"USD": 90, "EUR": 98}, from: "USD", to: "RUB", want: 90},
+		{name: "из базовой валюты", base: "RUB", stored: map[string]float64{"USD": 90, "EUR": 98}, from: "RUB", to: "EUR", want: 1.0 / 98},
+		{name: "одинаковые валюты", base: "RUB", stored: map[string]float64{"USD": 90}, from: "USD", to: "USD", want: 1},
+		{name: "база USD, пара без базы", base: "USD", stored: map[string]float64{"EUR": 1.09, "RUB": 0.011}, from: "EUR", to: "RUB", want: 1.09 / 0.011},
+		{name: "база USD, из базовой валюты", base: "USD", stored: map[string]float64{"EUR": 1.09, "RUB": 0.011}, from: "USD", to: "EUR", want: 1 / 1.09},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t, tt.base, 0)
+			if err := store.StoreRates(context.Background(), tt.stored, storages.SourceCBR); err != nil {
+				t.Fatal(err)
+			}
+
+			quote, err := store.GetRate(context.Background(), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("ошибка получения курса: %v", err)
+			}
+			if math.Abs(quote.Rate-tt.want) > 1e-9*tt.want {
+				t.Errorf("курс %s/%s = %v, ожидался %v", tt.from, tt.to, quote.Rate, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreGetRateUnknownCurrency(t *testing.T) {
+	store := newTestStore(t, "RUB", 0)
+	if err := store.StoreRates(context.Background(), map[string]float64{"USD": 90}, storages.SourceCBR); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetRate(context.Background(), "USD", "GBP"); err == nil {
+		t.Error("получен курс валюты без курса")
+	}
+}
+
+func TestStoreRatesSkipsUnchanged(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, "RUB", 0)
+	if err := store.StoreRates(ctx, map[string]float64{"USD": 90, "EUR": 98}, storages.SourceCBR); err != nil {
+		t.Fatal(err)
+	}
+	written := storedUpdatedAt(t, store)
+	time.Sleep(5 * time.Millisecond) // Время второго обновления отличается от первого
+
+	if err := store.StoreRates(ctx, map[string]float64{"USD": 90, "EUR": 99, "GBP": 115}, storages.SourceCBR); err != nil {
+		t.Fatal(err)
+	}
+	rewritten := storedUpdatedAt(t, store)
+
+	t.Run("неизменившийся курс не перезаписывается", func(t *testing.T) {
+		if !rewritten["USD"].Equal(written["USD"]) {
+			t.Errorf("время записи USD изменилось: %v -> %v", written["USD"], rewritten["USD"])
+		}
+	})
+
+	t.Run("изменившийся и новый курсы записываются", func(t *testing.T) {
+		if !rewritten["EUR"].After(written["EUR"]) {
+			t.Errorf("время записи EUR не обновлено: %v -> %v", written["EUR"], rewritten["EUR"])
+		}
+		if rewritten["GBP"].IsZero() {
+			t.Error("новый курс GBP не записан")
+		}
+	})
+
+	t.Run("журнал только изменений", func(t *testing.T) {
+		rows, err := store.db.QueryContext(ctx, `SELECT currency, old_rate, new_rate FROM rate_audit WHERE id > 2 ORDER BY currency`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		var audit []string
+		for rows.Next() {
+			var currency string
+			var oldRate sql.NullFloat64
+			var newRate float64
+			if err := rows.Scan(&currency, &oldRate, &newRate); err != nil {
+				t.Fatal(err)
+			}
+			audit = append(audit, fmt.Sprintf("%s %v->%v", currency, oldRate.Float64, newRate))
+		}
+		if want := []string{"EUR 98->99", "GBP 0->115"}; !slices.Equal(audit, want) {
+			t.Errorf("журнал второго обновления %v, ожидался %v", audit, want)
+		}
+	})
+
+	t.Run("подтверждение обновляет время курса при чтении", func(t *testing.T) {
+		rates, err := store.GetAllRates(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rates["USD"].UpdatedAt; !got.Equal(rewritten["EUR"]) {
+			t.Errorf("время обновления USD %v, ожидалось время второго получения %v", got, rewritten["EUR"])
+		}
+	})
+}
+
+// storedUpdatedAt возвращает время записи курсов в exchange_rates (без учета подтверждений)
+func storedUpdatedAt(t *testing.T, store *Store) map[string]time.Time {
+	t.Helper()
+	rows, err := store.db.QueryContext(context.Background(), `SELECT currency, updated_at FROM exchange_rates`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	updatedAt := make(map[string]time.Time)
+	for rows.Next() {
+		var currency string
+		var at time.Time
+		if err := rows.Scan(&currency, &at); err != nil {
+			t.Fatal(err)
+		}
+		updatedAt[currency] = at
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return updatedAt
+}
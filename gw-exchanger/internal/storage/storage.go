@@ -2,15 +2,32 @@ package storages
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// Ошибки ручного управления курсами
+var (
+	ErrUnknownCurrency  = errors.New("курс валюты не поставляется ни одним источником")
+	ErrOverrideNotFound = errors.New("действующий ручной курс не найден")
+)
+
+// ErrQuarantineNotFound - курс на карантине не найден или решение по нему уже принято
+var ErrQuarantineNotFound = errors.New("курс на карантине не найден")
+
+// Ошибки истории курсов
+var (
+	ErrNoRateHistory    = errors.New("нет истории курса за период")
+	ErrSnapshotNotFound = errors.New("снимок курсов не найден")
+)
+
 // Storage - основной интерфейс хранилища курсов валют.
 // Объединяет функциональность для работы с курсами (RateProvider),
-// их обновления (Updater) и управления ресурсами (Closer).
+// записи курсов поставщиков (RateWriter) и управления ресурсами (Closer).
+// Курсы от поставщиков получает и записывает отдельный компонент обновления (Updater)
 type Storage interface {
 	RateProvider  // Методы для получения курсов
-	Updater       // Методы для обновления данных
+	RateWriter    // Методы для записи курсов поставщиков
 	Close() error // Метод для освобождения ресурсов
 }
 
@@ -22,25 +39,143 @@ type RateProvider interface {
 	//   - from: код исходной валюты (например "USD")
 	//   - to: код целевой валюты (например "RUB")
 	// Возвращает:
-	//   - float64: курс обмена
+	//   - RateQuote: курс обмена и источники курсов валют
 	//   - error: ошибка при получении курса
-	GetRate(ctx context.Context, from, to string) (float64, error)
+	GetRate(ctx context.Context, from, to string) (RateQuote, error)
 
 	// GetAllRates возвращает все доступные курсы валют
+	// Для каждой валюты выбирается курс из источника с наивысшим приоритетом (SourcePriority)
 	// Параметры:
 	//   - ctx: контекст выполнения
 	// Возвращает:
-	//   - map[string]float64: словарь курсов (ключ - код валюты)
+	//   - map[string]ExchangeRate: словарь курсов с источниками (ключ - код валюты)
 	//   - error: ошибка при получении данных
-	GetAllRates(ctx context.Context) (map[string]float64, error)
+	GetAllRates(ctx context.Context) (map[string]ExchangeRate, error)
+
+	// GetRateChanges возвращает изменения курса валюты за период в порядке времени
+	// Параметры:
+	//   - ctx: контекст выполнения
+	//   - currency: код валюты
+	//   - from: начало периода (включительно)
+	//   - to: конец периода (не включительно)
+	//   - limit: максимальное количество записей
+	// Возвращает:
+	//   - []RateChange: изменения курса
+	//   - error: ошибка при получении данных
+	GetRateChanges(ctx context.Context, currency string, from, to time.Time, limit int) ([]RateChange, error)
+}
+
+// RateWriter предоставляет методы для записи курсов, полученных от поставщиков
+type RateWriter interface {
+	// BaseCurrency возвращает базовую валюту, к которой хранятся курсы
+	// (курсы поставщиков пересчитываются к ней перед записью)
+	BaseCurrency() string
+
+	// ScreenRates отделяет курсы с резким скачком относительно опубликованных курсов источника
+	// Такие курсы отправляются на карантин и не публикуются до решения администратора
+	// Параметры:
+	//   - ctx: контекст выполнения
+	//   - rates: полученные курсы (ключ - код валюты)
+	//   - source: источник курсов
+	// Возвращает:
+	//   - map[string]float64: курсы, прошедшие проверку
+	//   - error: ошибка при проверке
+	ScreenRates(ctx context.Context, rates map[string]float64, source string) (map[string]float64, error)
+
+	// StoreRates записывает курсы источника
+	// Новые и изменившиеся курсы записываются пакетно (одним запросом курсов и одним запросом журнала rate_audit);
+	// неизменившиеся курсы не перезаписываются и не попадают в журнал - хранилище запоминает время их получения,
+	// и оно учитывается как время обновления курса при чтении
+	// Параметры:
+	//   - ctx: контекст выполнения
+	//   - rates: курсы к базовой валюте (ключ - код валюты)
+	//   - source: источник курсов
+	// Возвращает:
+	//   - error: ошибка при записи (курсы не записываются частично)
+	StoreRates(ctx context.Context, rates map[string]float64, source string) error
+
+	// PruneRates удаляет курсы источника для валют, не входящих в список хранимых
+	// Параметры:
+	//   - ctx: контекст выполнения
+	//   - source: источник курсов
+	//   - allowlist: хранимые валюты (пустой - удалять нечего)
+	// Возвращает:
+	//   - error: ошибка при удалении
+	PruneRates(ctx context.Context, source string, allowlist []string) error
+
+	// StoreCurrencies записывает сведения о валютах из ответа поставщика (название, номинал, знаки после запятой)
+	// Параметры:
+	//   - ctx: контекст выполнения
+	//   - currencies: сведения о валютах (UpdatedAt не используется)
+	// Возвращает:
+	//   - error: ошибка при записи
+	StoreCurrencies(ctx context.Context, currencies []Currency) error
+}
+
+// Дополнительные возможности хранилища, которые поддерживаются не всеми хранилищами
+// (PostgreSQL поддерживает все); вызовы неподдерживаемых возможностей отклоняются сервером
+
+// CurrencyCatalog предоставляет справочник валют
+type CurrencyCatalog interface {
+	// ListCurrencies возвращает справочник валют в порядке кодов
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+}
+
+// RateHistory предоставляет статистику и свечи курса пары валют за период
+type RateHistory interface {
+	// GetRateStats возвращает статистику курса пары валют за период [start, end)
+	GetRateStats(ctx context.Context, from, to string, start, end time.Time) (RateStats, error)
+	// GetRateCandles возвращает свечи курса пары валют за период [start, end) с длительностью interval
+	GetRateCandles(ctx context.Context, from, to string, start, end time.Time, interval time.Duration) ([]RateCandle, error)
+}
+
+// SnapshotStore хранит ежедневные снимки курсов
+type SnapshotStore interface {
+	// GetSnapshot возвращает снимок курсов за дату (нулевая дата - последний снимок)
+	GetSnapshot(ctx context.Context, date time.Time) (RateSnapshot, error)
+	// RunDailySnapshots сохраняет снимок курсов ежедневно в момент at (смещение от полуночи в loc) до отмены контекста
+	RunDailySnapshots(ctx context.Context, at time.Duration, loc *time.Location)
+}
+
+// RateEventSource предоставляет события об изменении курсов (outbox)
+type RateEventSource interface {
+	// LastRateEventID возвращает идентификатор последнего события (0 - событий нет)
+	LastRateEventID(ctx context.Context) (int64, error)
+	// RateEventsAfter возвращает не более limit событий с идентификатором больше afterID
+	RateEventsAfter(ctx context.Context, afterID int64, limit int) ([]RateEvent, error)
+}
+
+// RateAdmin предоставляет ручное управление курсами и карантин курсов поставщиков
+type RateAdmin interface {
+	// BaseCurrency возвращает базовую валюту, к которой хранятся курсы
+	BaseCurrency() string
+	// SetOverride устанавливает ручной курс валюты до expiresAt
+	SetOverride(ctx context.Context, currency string, rate float64, expiresAt time.Time, actor, reason string) (RateOverride, error)
+	// ClearOverride досрочно отменяет ручной курс валюты
+	ClearOverride(ctx context.Context, currency, actor, reason string) (RateOverride, error)
+	// ListOverrides возвращает действующие ручные курсы
+	ListOverrides(ctx context.Context) ([]RateOverride, error)
+	// ListQuarantine возвращает курсы на карантине, ожидающие решения
+	ListQuarantine(ctx context.Context) ([]QuarantinedRate, error)
+	// ResolveQuarantine принимает или отклоняет курс на карантине
+	ResolveQuarantine(ctx context.Context, id int64, approve bool, actor, reason string) (QuarantinedRate, error)
 }
 
 // Updater предоставляет методы для обновления курсов валют
 type Updater interface {
 	// UpdateRates выполняет обновление курсов из внешнего источника
+	// Параметры:
+	//   - ctx: контекст выполнения (отмена прерывает запросы к поставщикам и БД)
 	// Возвращает:
 	//   - error: ошибка при обновлении данных
-	UpdateRates() error
+	UpdateRates(ctx context.Context) error
+}
+
+// PoolOptions содержит параметры пула соединений с БД
+type PoolOptions struct {
+	MaxOpenConns    int           // Максимум открытых соединений (0 - без ограничений)
+	MaxIdleConns    int           // Максимум простаивающих соединений в пуле
+	ConnMaxLifetime time.Duration // Максимальное время жизни соединения (0 - без ограничений)
 }
 
 // UpdaterConfig содержит параметры для фонового обновления курсов
@@ -48,4 +183,5 @@ type UpdaterConfig struct {
 	Enabled        bool          // Флаг активности автоматического обновления
 	UpdateInterval time.Duration // Интервал между обновлениями (например 1h)
 	InitialDelay   time.Duration // Задержка перед первым обновлением
+	Jitter         time.Duration // Максимальная случайная добавка к интервалу
 }
@@ -0,0 +1,56 @@
+package storages
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"time"
+)
+
+// UpdaterFunc позволяет использовать функцию как Updater
+type UpdaterFunc func(ctx context.Context) error
+
+// UpdateRates вызывает функцию обновления
+func (f UpdaterFunc) UpdateRates(ctx context.Context) error {
+	return f(ctx)
+}
+
+// RunUpdater выполняет фоновое обновление по параметрам cfg до отмены контекста
+// Первое обновление выполняется сразу после InitialDelay, следующие - через UpdateInterval
+// плюс случайную задержку до Jitter, чтобы экземпляры сервиса не обращались к поставщикам одновременно
+// Параметры:
+//   - ctx: контекст жизни обновления
+//   - name: название обновления для логов (например "курсы валют")
+//   - updater: выполняемое обновление
+//   - cfg: параметры расписания
+func RunUpdater(ctx context.Context, name string, updater Updater, cfg UpdaterConfig) {
+	if !cfg.Enabled {
+		log.Printf("Фоновое обновление (%s) отключено", name)
+		return
+	}
+
+	delay := cfg.InitialDelay
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Printf("Фоновое обновление (%s) остановлено", name)
+			return
+		case <-timer.C:
+		}
+
+		if err := updater.UpdateRates(ctx); err != nil {
+			log.Printf("Ошибка обновления (%s): %v", name, err)
+		}
+		delay = cfg.UpdateInterval + jitter(cfg.Jitter)
+	}
+}
+
+// jitter возвращает случайную задержку в диапазоне [0, max)
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
@@ -0,0 +1,102 @@
+package updater
+
+import (
+	storages "gw-exchanger/internal/storage"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Имена фоновых обновлений в состоянии обновлений
+const (
+	updateRates  = "rates"  // Курсы фиатных валют от поставщиков
+	updateCrypto = "crypto" // Курсы криптовалют
+)
+
+// healthTracker хранит результаты запросов к поставщикам и фоновых обновлений с момента запуска
+// Состояние хранится в памяти экземпляра и используется страницей состояния и GetUpdateStatus
+type healthTracker struct {
+	mu        sync.Mutex
+	providers map[string]*storages.ProviderStatus // Поставщик -> состояние
+	updates   map[string]*storages.UpdateStatus   // Обновление -> состояние
+}
+
+// newHealthTracker создает пустое хранилище состояния
+func newHealthTracker() *healthTracker {
+	return &healthTracker{
+		providers: make(map[string]*storages.ProviderStatus),
+		updates:   make(map[string]*storages.UpdateStatus),
+	}
+}
+
+// recordFetch записывает результат запроса к поставщику
+func (h *healthTracker) recordFetch(provider string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status, ok := h.providers[provider]
+	if !ok {
+		status = &storages.ProviderStatus{Name: provider}
+		h.providers[provider] = status
+	}
+	if err == nil {
+		status.LastSuccess = time.Now()
+		status.Failures = 0
+		return
+	}
+	status.LastFailure = time.Now()
+	status.LastError = err.Error()
+	status.Failures++
+	status.TotalFailures++
+}
+
+// recordUpdate записывает результат фонового обновления
+// source и currencies - источник и количество полученных курсов (учитываются только при успешном обновлении)
+func (h *healthTracker) recordUpdate(name, source string, currencies int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status, ok := h.updates[name]
+	if !ok {
+		status = &storages.UpdateStatus{Name: name}
+		h.updates[name] = status
+	}
+	status.LastRun = time.Now()
+	status.Runs++
+	if err != nil {
+		status.LastError = err.Error()
+		status.Errors++
+		return
+	}
+	status.LastSuccess = status.LastRun
+	status.LastError = ""
+	status.Source = source
+	status.Currencies = currencies
+}
+
+// ProviderStatuses возвращает состояние поставщиков курсов, упорядоченное по имени
+// Поставщики, к которым еще не было запросов, не включаются
+func (u *RateUpdater) ProviderStatuses() []storages.ProviderStatus {
+	u.health.mu.Lock()
+	defer u.health.mu.Unlock()
+
+	statuses := make([]storages.ProviderStatus, 0, len(u.health.providers))
+	for _, status := range u.health.providers {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// UpdateStatuses возвращает состояние фоновых обновлений курсов, упорядоченное по имени
+func (u *RateUpdater) UpdateStatuses() []storages.UpdateStatus {
+	u.health.mu.Lock()
+	defer u.health.mu.Unlock()
+
+	statuses := make([]storages.UpdateStatus, 0, len(u.health.updates))
+	for _, status := range u.health.updates {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
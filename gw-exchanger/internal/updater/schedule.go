@@ -0,0 +1,82 @@
+package updater
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// RateSchedule определяет, курсы каких валют хранятся и как часто они обновляются
+// Поставщики опрашиваются с наименьшим из интервалов, но курс валюты записывается в БД,
+// только если прошел ее интервал - это снижает нагрузку на БД от редко меняющихся курсов
+type RateSchedule struct {
+	Interval  time.Duration            // Интервал обновления по умолчанию
+	Intervals map[string]time.Duration // Интервалы обновления отдельных валют (например, основных - 15m)
+	Allowlist []string                 // Хранимые валюты (пустой - все, которые возвращают поставщики)
+}
+
+// TickInterval возвращает период опроса поставщиков - наименьший из интервалов
+func (r RateSchedule) TickInterval() time.Duration {
+	tick := r.Interval
+	for _, interval := range r.Intervals {
+		if interval > 0 && interval < tick {
+			tick = interval
+		}
+	}
+	return tick
+}
+
+// interval возвращает интервал обновления валюты
+func (r RateSchedule) interval(currency string) time.Duration {
+	if interval, ok := r.Intervals[currency]; ok && interval > 0 {
+		return interval
+	}
+	return r.Interval
+}
+
+// allowed сообщает, хранится ли курс валюты
+func (r RateSchedule) allowed(currency string) bool {
+	return len(r.Allowlist) == 0 || slices.Contains(r.Allowlist, currency)
+}
+
+// rateScheduler отбирает валюты, курсы которых пора записать в БД
+type rateScheduler struct {
+	schedule RateSchedule
+
+	mu     sync.Mutex
+	stored map[string]time.Time // Время последней записи курса валюты
+}
+
+// newRateScheduler создает планировщик записи курсов
+func newRateScheduler(schedule RateSchedule) *rateScheduler {
+	return &rateScheduler{schedule: schedule, stored: make(map[string]time.Time)}
+}
+
+// due оставляет курсы разрешенных валют, интервал обновления которых истек
+// Курс базовой валюты записывается всегда
+func (r *rateScheduler) due(rates map[string]float64, baseCurrency string, now time.Time) map[string]float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[string]float64, len(rates))
+	for currency, rate := range rates {
+		if currency != baseCurrency && !r.schedule.allowed(currency) {
+			continue
+		}
+		// Небольшой допуск, чтобы курс с интервалом, равным периоду опроса, не пропускал очередной тик
+		if last, ok := r.stored[currency]; ok && now.Sub(last) < r.schedule.interval(currency)-time.Second {
+			continue
+		}
+		result[currency] = rate
+	}
+	return result
+}
+
+// markStored запоминает время записи курсов
+func (r *rateScheduler) markStored(rates map[string]float64, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for currency := range rates {
+		r.stored[currency] = now
+	}
+}
@@ -0,0 +1,253 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"gw-exchanger/internal/api"              // Поставщики курсов валют
+	storages "gw-exchanger/internal/storage" // Интерфейсы и модели хранилища
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options содержит параметры обновления курсов
+type Options struct {
+	Aggregation api.AggregateOptions   // Параметры агрегации курсов, если поставщиков несколько
+	Schedule    RateSchedule           // Хранимые валюты и интервалы обновления их курсов
+	Updater     storages.UpdaterConfig // Параметры фонового обновления (интервал - Schedule.TickInterval)
+	Crypto      CryptoOptions          // Параметры обновления курсов криптовалют
+}
+
+// CryptoOptions содержит параметры обновления курсов криптовалют
+// Криптовалюты обновляются отдельно от фиатных валют и чаще
+type CryptoOptions struct {
+	Provider       api.Provider  // Поставщик курсов криптовалют (nil - криптовалюты не поддерживаются)
+	UpdateInterval time.Duration // Интервал обновления курсов криптовалют
+	Jitter         time.Duration // Максимальная случайная добавка к интервалу
+}
+
+// RateUpdater получает курсы от поставщиков и записывает их в хранилище
+// Хранилище не знает о поставщиках: обновление запускается и останавливается отдельно от него (Run)
+type RateUpdater struct {
+	storage     storages.Storage       // Хранилище курсов
+	providers   []api.Provider         // Поставщики курсов валют
+	aggregation api.AggregateOptions   // Параметры агрегации курсов нескольких поставщиков
+	scheduler   *rateScheduler         // Хранимые валюты и интервалы их обновления
+	updater     storages.UpdaterConfig // Параметры фонового обновления курсов валют
+	crypto      CryptoOptions          // Параметры обновления курсов криптовалют
+	health      *healthTracker         // Результаты запросов к поставщикам и обновлений (страница состояния)
+}
+
+// New создает компонент обновления курсов
+// Параметры:
+//   - storage: хранилище, в которое записываются курсы
+//   - providers: поставщики курсов валют
+//   - opts: агрегация, расписание и параметры фонового обновления
+//
+// Возвращает:
+//   - *RateUpdater: компонент обновления (фоновое обновление запускается методом Run)
+func New(storage storages.Storage, providers []api.Provider, opts Options) *RateUpdater {
+	return &RateUpdater{
+		storage:     storage,
+		providers:   providers,
+		aggregation: opts.Aggregation,
+		scheduler:   newRateScheduler(opts.Schedule),
+		updater:     opts.Updater,
+		crypto:      opts.Crypto,
+		health:      newHealthTracker(),
+	}
+}
+
+// Run выполняет фоновое обновление курсов валют и криптовалют до отмены контекста (блокирующая операция)
+// Первое обновление курсов выполняется сразу после UpdaterConfig.InitialDelay
+func (u *RateUpdater) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	if u.crypto.Provider != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			storages.RunUpdater(ctx, "курсы криптовалют", storages.UpdaterFunc(u.UpdateCryptoRates), storages.UpdaterConfig{
+				Enabled:        true,
+				UpdateInterval: u.crypto.UpdateInterval,
+				Jitter:         u.crypto.Jitter,
+			})
+		}()
+	}
+	storages.RunUpdater(ctx, "курсы валют", u, u.updater)
+	wg.Wait()
+}
+
+// UpdateRates обновляет курсы валют от поставщиков (реализация storages.Updater)
+// Результат обновления учитывается в состоянии обновлений
+func (u *RateUpdater) UpdateRates(ctx context.Context) error {
+	source, count, err := u.UpdateRatesFromCB(ctx)
+	u.health.recordUpdate(updateRates, source, count, err)
+	return err
+}
+
+// UpdateRatesFromCB обновляет курсы валют от настроенных поставщиков (по умолчанию - API Центробанка)
+// Если поставщиков несколько, публикуется агрегированный курс (источник aggregate)
+// Запрос к API и запись в БД прерываются при отмене контекста
+// Возвращает источник и количество полученных курсов
+func (u *RateUpdater) UpdateRatesFromCB(ctx context.Context) (string, int, error) {
+	if len(u.providers) == 0 {
+		return "", 0, fmt.Errorf("поставщики курсов не настроены")
+	}
+
+	log.Println("Обновление курсов валют...")
+
+	// 1. Получение курсов от поставщиков
+	rates, source, err := u.fetchRates(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("ошибка получения курсов: %v", err)
+	}
+
+	// 2. Запись в БД курсов, интервал обновления которых истек
+	// Курсы с резким скачком относительно опубликованных отправляются на карантин и не записываются
+	now := time.Now()
+	due, err := u.storage.ScreenRates(ctx, u.scheduler.due(rates, u.storage.BaseCurrency(), now), source)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := u.storage.StoreRates(ctx, due, source); err != nil {
+		return "", 0, err
+	}
+	u.scheduler.markStored(due, now)
+
+	// 3. Удаление курсов валют, исключенных из списка хранимых
+	if err := u.storage.PruneRates(ctx, source, u.scheduler.schedule.Allowlist); err != nil {
+		return "", 0, err
+	}
+
+	log.Printf("Курсы валют успешно обновлены: %d из %d полученных", len(due), len(rates))
+	return source, len(rates), nil
+}
+
+// UpdateCryptoRates обновляет курсы криптовалют (источник coingecko)
+// Курсы пересчитываются к базовой валюте хранилища, если поставщик вернул их в другой валюте
+// Результат обновления учитывается в состоянии обновлений
+func (u *RateUpdater) UpdateCryptoRates(ctx context.Context) error {
+	count, err := u.updateCryptoRates(ctx)
+	u.health.recordUpdate(updateCrypto, storages.SourceCoinGecko, count, err)
+	return err
+}
+
+// updateCryptoRates получает курсы криптовалют и записывает их в БД
+// Возвращает количество полученных курсов
+func (u *RateUpdater) updateCryptoRates(ctx context.Context) (int, error) {
+	if u.crypto.Provider == nil {
+		return 0, fmt.Errorf("поставщик курсов криптовалют не настроен")
+	}
+
+	rates, err := u.fetchProviderRates(ctx, u.crypto.Provider)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения курсов криптовалют: %v", err)
+	}
+	// Курс базовой валюты поставляют фиатные поставщики
+	delete(rates, u.storage.BaseCurrency())
+
+	if err := u.storage.StoreRates(ctx, rates, storages.SourceCoinGecko); err != nil {
+		return 0, err
+	}
+
+	log.Printf("Курсы криптовалют обновлены: %d", len(rates))
+	return len(rates), nil
+}
+
+// fetchRates получает курсы от поставщиков и пересчитывает их к базовой валюте хранилища
+// Курсы единственного поставщика публикуются как есть (источник cbr), курсы нескольких
+// поставщиков агрегируются (источник aggregate); недоступные поставщики пропускаются
+func (u *RateUpdater) fetchRates(ctx context.Context) (map[string]float64, string, error) {
+	if len(u.providers) == 1 {
+		rates, err := u.fetchProviderRates(ctx, u.providers[0])
+		return rates, storages.SourceCBR, err
+	}
+
+	// Запрашиваем поставщиков параллельно
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		quotes = make(map[string]map[string]float64, len(u.providers))
+	)
+	for _, provider := range u.providers {
+		wg.Add(1)
+		go func(provider api.Provider) {
+			defer wg.Done()
+			rates, err := u.fetchProviderRates(ctx, provider)
+			if err != nil {
+				log.Printf("Поставщик %s недоступен: %v", provider.Name(), err)
+				return
+			}
+			mu.Lock()
+			quotes[provider.Name()] = rates
+			mu.Unlock()
+		}(provider)
+	}
+	wg.Wait()
+
+	if len(quotes) == 0 {
+		return nil, "", fmt.Errorf("ни один из %d поставщиков не вернул курсы", len(u.providers))
+	}
+
+	// Агрегируем курсы и учитываем вклад поставщиков для журнала
+	rates := make(map[string]float64)
+	contributed := make(map[string]int, len(quotes))
+	for currency, aggregated := range api.Aggregate(quotes, u.aggregation) {
+		if len(aggregated.Discarded) > 0 {
+			log.Printf("Курс %s: отброшены выбросы поставщиков %s (учтены: %s)",
+				currency, strings.Join(aggregated.Discarded, ", "), strings.Join(aggregated.Contributors, ", "))
+		}
+		if len(aggregated.Contributors) == 0 {
+			log.Printf("Курс %s не обновлен: поставщики расходятся больше допустимого отклонения", currency)
+			continue
+		}
+		for _, provider := range aggregated.Contributors {
+			contributed[provider]++
+		}
+		rates[currency] = aggregated.Rate
+	}
+
+	summary := make([]string, 0, len(u.providers))
+	for _, provider := range u.providers {
+		summary = append(summary, fmt.Sprintf("%s=%d", provider.Name(), contributed[provider.Name()]))
+	}
+	log.Printf("Курсы агрегированы (%s) по %d валютам, учтено курсов от поставщиков: %s",
+		u.aggregation.Method, len(rates), strings.Join(summary, ", "))
+
+	return rates, storages.SourceAggregate, nil
+}
+
+// fetchProviderRates получает курсы поставщика, пересчитанные к базовой валюте хранилища
+func (u *RateUpdater) fetchProviderRates(ctx context.Context, provider api.Provider) (map[string]float64, error) {
+	rates, err := provider.FetchRates(ctx)
+	if err == nil {
+		rates, err = api.Rebase(rates, provider.BaseCurrency(), u.storage.BaseCurrency())
+	}
+	u.health.recordFetch(provider.Name(), err)
+	if err != nil {
+		return nil, err
+	}
+
+	// Сведения о валютах не влияют на курсы: ошибка их записи не прерывает обновление
+	if info, ok := provider.(api.CurrencyInfoProvider); ok {
+		if err := u.storage.StoreCurrencies(ctx, currencies(info.Currencies())); err != nil {
+			log.Printf("Справочник валют не обновлен (%s): %v", provider.Name(), err)
+		}
+	}
+	return rates, nil
+}
+
+// currencies преобразует сведения о валютах из ответа поставщика в модель хранилища
+func currencies(infos []api.CurrencyInfo) []storages.Currency {
+	result := make([]storages.Currency, 0, len(infos))
+	for _, info := range infos {
+		result = append(result, storages.Currency{
+			Code:     info.Code,
+			Name:     info.Name,
+			Nominal:  info.Nominal,
+			Decimals: info.Decimals,
+		})
+	}
+	return result
+}
@@ -3,25 +3,27 @@ package utils
 import (
 	"context"
 	"fmt"
-	"gw-exchanger/internal/storage/postgres"
+	storages "gw-exchanger/internal/storage"
 	"log"
+	"sort"
 	"time"
 )
 
-// PrintAvailableCurrencies выводит список доступных валют и их курсов к рублю
+// PrintAvailableCurrencies выводит список доступных валют и их курсов к базовой валюте
 // Параметры:
-//   - storage: подключение к хранилищу данных (PostgreSQL)
+//   - ctx: контекст вызывающего (запрос дополнительно ограничен 3 секундами)
+//   - storage: хранилище курсов (PostgreSQL, MySQL или SQLite)
 //
 // Логика работы:
 //  1. Создает контекст с таймаутом 3 секунды для запроса
 //  2. Получает все курсы валют из хранилища
 //  3. Форматирует и выводит результат:
-//     - USD всегда выводится первым как базовая валюта
+//     - Базовая валюта выводится первой
 //     - Остальные валюты выводятся в алфавитном порядке
 //  4. Обрабатывает возможные ошибки
-func PrintAvailableCurrencies(storage *postgres.PostgresStorage) {
+func PrintAvailableCurrencies(ctx context.Context, storage storages.Storage) {
 	// Создаем контекст с ограничением времени выполнения
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel() // Гарантированное освобождение ресурсов
 
 	// Получаем все курсы валют из хранилища
@@ -38,17 +40,21 @@ func PrintAvailableCurrencies(storage *postgres.PostgresStorage) {
 	if len(currencies) == 0 {
 		fmt.Println("В базе данных не найдено курсов валют!")
 	} else {
-		// Специальный вывод для USD (базовая валюта)
-		if usdRate, exists := currencies["USD"]; exists {
-			fmt.Printf("%.4f RUB (базовая валюта) = 1 USD\n", usdRate)
-		}
+		base := storage.BaseCurrency()
+		fmt.Printf("Базовая валюта: %s\n", base)
 
 		// Вывод остальных валют в алфавитном порядке
-		for currency, rate := range currencies {
-			if currency != "USD" {
-				fmt.Printf("%.4f RUB = 1 %s\n", rate, currency)
+		codes := make([]string, 0, len(currencies))
+		for currency := range currencies {
+			if currency != base {
+				codes = append(codes, currency)
 			}
 		}
+		sort.Strings(codes)
+		for _, currency := range codes {
+			rate := currencies[currency]
+			fmt.Printf("%.4f %s = 1 %s [%s]\n", rate.Rate, base, currency, rate.Source)
+		}
 	}
 
 	// Нижний разделитель
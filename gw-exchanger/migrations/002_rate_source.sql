@@ -0,0 +1,15 @@
+-- Источник курса: один и тот же курс может поступать от нескольких поставщиков
+-- (cbr - ЦБ РФ, ecb - Европейский ЦБ) и задаваться вручную (manual), ручной курс имеет приоритет
+ALTER TABLE exchange_rates ADD COLUMN IF NOT EXISTS source VARCHAR(16) NOT NULL DEFAULT 'cbr';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'exchange_rates_source_check') THEN
+        ALTER TABLE exchange_rates
+            ADD CONSTRAINT exchange_rates_source_check CHECK (source IN ('cbr', 'ecb', 'manual'));
+    END IF;
+END $$;
+
+-- Уникальность курса теперь по паре (валюта, источник)
+ALTER TABLE exchange_rates DROP CONSTRAINT IF EXISTS exchange_rates_currency_key;
+CREATE UNIQUE INDEX IF NOT EXISTS exchange_rates_currency_source_idx ON exchange_rates (currency, source);
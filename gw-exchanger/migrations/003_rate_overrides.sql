@@ -0,0 +1,18 @@
+-- Ручные курсы действуют ограниченное время: expires_at задается при установке через RPC,
+-- NULL - бессрочный курс (установлен напрямую в БД)
+ALTER TABLE exchange_rates ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE exchange_rates ADD COLUMN IF NOT EXISTS set_by VARCHAR(64);
+
+-- Журнал аудита ручных курсов: установка, отмена и истечение срока
+CREATE TABLE IF NOT EXISTS rate_override_audit (
+    id SERIAL PRIMARY KEY,
+    currency VARCHAR(3) NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('set', 'clear', 'expire')),
+    rate DECIMAL(10, 6),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    actor VARCHAR(64) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS rate_override_audit_currency_idx ON rate_override_audit (currency, created_at);
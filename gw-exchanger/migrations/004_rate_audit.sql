@@ -0,0 +1,12 @@
+-- Журнал изменений курсов поставщиков: каждое изменение курса при обновлении
+-- записывается со старым и новым значением (old_rate NULL - первое получение курса)
+CREATE TABLE IF NOT EXISTS rate_audit (
+    id BIGSERIAL PRIMARY KEY,
+    currency VARCHAR(3) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    old_rate DECIMAL(10, 6),
+    new_rate DECIMAL(10, 6) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS rate_audit_currency_changed_at_idx ON rate_audit (currency, changed_at);
@@ -0,0 +1,6 @@
+-- Курс, агрегированный по нескольким поставщикам, хранится с источником aggregate
+-- Миграции выполняются при каждом запуске, а список источников расширяется следующими миграциями,
+-- поэтому существующие строки здесь не проверяются (NOT VALID)
+ALTER TABLE exchange_rates DROP CONSTRAINT IF EXISTS exchange_rates_source_check;
+ALTER TABLE exchange_rates
+    ADD CONSTRAINT exchange_rates_source_check CHECK (source IN ('cbr', 'ecb', 'aggregate', 'manual')) NOT VALID;
@@ -0,0 +1,11 @@
+-- Курсы хранятся как стоимость единицы валюты в базовой валюте (BASE_CURRENCY).
+-- Базовая валюта записывается вместе с курсом: при ее смене курсы с прежней базой не используются
+-- до пересчета при следующем обновлении
+ALTER TABLE exchange_rates ADD COLUMN IF NOT EXISTS base_currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+ALTER TABLE rate_audit ADD COLUMN IF NOT EXISTS base_currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+
+-- Точности DECIMAL(10, 6) не хватает для курсов к сильной базовой валюте (например, JPY к USD)
+ALTER TABLE exchange_rates ALTER COLUMN rate TYPE DECIMAL(20, 10);
+ALTER TABLE rate_audit ALTER COLUMN old_rate TYPE DECIMAL(20, 10);
+ALTER TABLE rate_audit ALTER COLUMN new_rate TYPE DECIMAL(20, 10);
+ALTER TABLE rate_override_audit ALTER COLUMN rate TYPE DECIMAL(20, 10);
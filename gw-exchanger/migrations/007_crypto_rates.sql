@@ -0,0 +1,8 @@
+-- Курсы криптовалют (BTC, ETH, ...) поступают от CoinGecko; коды активов бывают длиннее трех символов (USDT)
+ALTER TABLE exchange_rates ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE rate_audit ALTER COLUMN currency TYPE VARCHAR(10);
+ALTER TABLE rate_override_audit ALTER COLUMN currency TYPE VARCHAR(10);
+
+ALTER TABLE exchange_rates DROP CONSTRAINT IF EXISTS exchange_rates_source_check;
+ALTER TABLE exchange_rates
+    ADD CONSTRAINT exchange_rates_source_check CHECK (source IN ('cbr', 'ecb', 'aggregate', 'coingecko', 'manual'));
@@ -0,0 +1,11 @@
+-- Ежедневные снимки опубликованных курсов на конец дня (официальные курсы закрытия для выписок и учета).
+-- Снимок за дату не изменяется после создания
+CREATE TABLE IF NOT EXISTS rate_snapshots (
+    snapshot_date DATE NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(20, 10) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    taken_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_date, base_currency, currency)
+);
@@ -0,0 +1,10 @@
+-- Исходящие события об изменении курсов (outbox). Событие записывается в той же транзакции,
+-- что и изменение курсов, и передается клиентам потоком WatchRateUpdates для сброса кэша
+CREATE TABLE IF NOT EXISTS rate_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    base_currency VARCHAR(3) NOT NULL,
+    currencies TEXT[] NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_outbox_created_at ON rate_outbox (created_at);
@@ -0,0 +1,21 @@
+-- Карантин подозрительных курсов поставщиков: курс, отклоняющийся от опубликованного больше RATE_MAX_JUMP,
+-- не записывается в exchange_rates и ожидает решения администратора (pending -> approved/rejected).
+-- Для пары валюта + источник ожидает проверки не больше одной записи, повторные получения обновляют ее
+CREATE TABLE IF NOT EXISTS rate_quarantine (
+    id BIGSERIAL PRIMARY KEY,
+    currency VARCHAR(10) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    previous_rate DOUBLE PRECISION NOT NULL,
+    rate DOUBLE PRECISION NOT NULL,
+    deviation DOUBLE PRECISION NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    resolved_by VARCHAR(64),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS rate_quarantine_pending_idx
+    ON rate_quarantine (currency, source, base_currency) WHERE status = 'pending';
@@ -0,0 +1,14 @@
+-- Справочник валют: название и номинал из ответа ЦБ РФ, количество знаков после запятой (ISO 4217).
+-- Название и номинал обновляются при каждом получении курсов ЦБ РФ; количество знаков задается при первом
+-- получении валюты и дальше не перезаписывается (может быть исправлено вручную)
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(10) PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    nominal INTEGER NOT NULL DEFAULT 1 CHECK (nominal > 0),
+    decimals SMALLINT NOT NULL DEFAULT 2 CHECK (decimals BETWEEN 0 AND 18),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Рубль не входит в ответ ЦБ РФ (курсы публикуются к нему)
+INSERT INTO currencies (code, name, nominal, decimals) VALUES ('RUB', 'Российский рубль', 1, 2)
+ON CONFLICT (code) DO NOTHING;
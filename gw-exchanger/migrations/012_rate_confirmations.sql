@@ -0,0 +1,9 @@
+-- Подтверждение курсов поставщиком: при обновлении неизменившиеся курсы не перезаписываются в exchange_rates,
+-- а время их последнего получения записывается одной строкой на источник (ключ - код валюты).
+-- Время обновления курса - наибольшее из updated_at курса и времени подтверждения
+CREATE TABLE IF NOT EXISTS rate_confirmations (
+    source VARCHAR(16) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    confirmed JSONB NOT NULL DEFAULT '{}',
+    PRIMARY KEY (source, base_currency)
+);
@@ -0,0 +1,58 @@
+-- Схема хранилища курсов для MySQL 8 (STORAGE_DRIVER=mysql): курсы источников, журнал изменений курсов,
+-- карантин подозрительных курсов и справочник валют. Соответствует миграциям PostgreSQL без ручных курсов,
+-- снимков и outbox. Время хранится в UTC, курсы - как DOUBLE (без округления при сравнении)
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency VARCHAR(10) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    rate DOUBLE NOT NULL,
+    base_currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    expires_at DATETIME(6) NULL,
+    set_by VARCHAR(64) NULL,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (currency, source),
+    CONSTRAINT exchange_rates_source_check CHECK (source IN ('cbr', 'ecb', 'aggregate', 'coingecko', 'manual'))
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS rate_audit (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    currency VARCHAR(10) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    old_rate DOUBLE NULL,
+    new_rate DOUBLE NOT NULL,
+    base_currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    changed_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX rate_audit_currency_changed_at_idx (currency, changed_at)
+) DEFAULT CHARSET = utf8mb4;
+
+-- Для пары валюта + источник ожидает проверки не больше одной записи (частичных индексов в MySQL нет,
+-- поэтому повторные получения обновляют запись на уровне приложения)
+CREATE TABLE IF NOT EXISTS rate_quarantine (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    currency VARCHAR(10) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    previous_rate DOUBLE NOT NULL,
+    rate DOUBLE NOT NULL,
+    deviation DOUBLE NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    resolved_by VARCHAR(64) NULL,
+    reason VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    resolved_at DATETIME(6) NULL,
+    INDEX rate_quarantine_pending_idx (currency, source, base_currency, status),
+    CONSTRAINT rate_quarantine_status_check CHECK (status IN ('pending', 'approved', 'rejected'))
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(10) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    nominal INT NOT NULL DEFAULT 1,
+    decimals SMALLINT NOT NULL DEFAULT 2,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT currencies_nominal_check CHECK (nominal > 0),
+    CONSTRAINT currencies_decimals_check CHECK (decimals BETWEEN 0 AND 18)
+) DEFAULT CHARSET = utf8mb4;
+
+-- Базовая валюта по умолчанию (не поставляется ЦБ РФ)
+INSERT IGNORE INTO currencies (code, name, nominal, decimals) VALUES ('RUB', 'Российский рубль', 1, 2);
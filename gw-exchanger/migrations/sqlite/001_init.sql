@@ -0,0 +1,56 @@
+-- Схема хранилища курсов для SQLite (STORAGE_DRIVER=sqlite): курсы источников, журнал изменений курсов,
+-- карантин подозрительных курсов и справочник валют. Соответствует миграциям PostgreSQL без ручных курсов,
+-- снимков и outbox. Время записывается приложением в UTC
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency VARCHAR(10) NOT NULL,
+    source VARCHAR(16) NOT NULL CHECK (source IN ('cbr', 'ecb', 'aggregate', 'coingecko', 'manual')),
+    rate DOUBLE PRECISION NOT NULL,
+    base_currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    expires_at DATETIME,
+    set_by VARCHAR(64),
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (currency, source)
+);
+
+CREATE TABLE IF NOT EXISTS rate_audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    currency VARCHAR(10) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    old_rate DOUBLE PRECISION,
+    new_rate DOUBLE PRECISION NOT NULL,
+    base_currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    changed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS rate_audit_currency_changed_at_idx ON rate_audit (currency, changed_at);
+
+-- Для пары валюта + источник ожидает проверки не больше одной записи, повторные получения обновляют ее
+CREATE TABLE IF NOT EXISTS rate_quarantine (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    currency VARCHAR(10) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    previous_rate DOUBLE PRECISION NOT NULL,
+    rate DOUBLE PRECISION NOT NULL,
+    deviation DOUBLE PRECISION NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    resolved_by VARCHAR(64),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS rate_quarantine_pending_idx
+    ON rate_quarantine (currency, source, base_currency) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(10) PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    nominal INTEGER NOT NULL DEFAULT 1 CHECK (nominal > 0),
+    decimals SMALLINT NOT NULL DEFAULT 2 CHECK (decimals BETWEEN 0 AND 18),
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Базовая валюта по умолчанию (не поставляется ЦБ РФ)
+INSERT OR IGNORE INTO currencies (code, name, nominal, decimals) VALUES ('RUB', 'Российский рубль', 1, 2);
@@ -0,0 +1,10 @@
+-- Подтверждение курсов поставщиком: при обновлении неизменившиеся курсы не перезаписываются в exchange_rates,
+-- а время их последнего получения записывается сюда. Время обновления курса - наибольшее из updated_at курса
+-- и времени подтверждения
+CREATE TABLE IF NOT EXISTS rate_confirmations (
+    source VARCHAR(16) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    confirmed_at DATETIME NOT NULL,
+    PRIMARY KEY (source, base_currency, currency)
+);
@@ -79,7 +79,11 @@ type ExchangeRateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FromCurrency  string                 `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"` // Исходная валюта
 	ToCurrency    string                 `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`       // Целевая валюта
-	Rate          float32                `protobuf:"fixed32,3,opt,name=rate,proto3" json:"rate,omitempty"`                                   // Курс обмена
+	Rate          float32                `protobuf:"fixed32,3,opt,name=rate,proto3" json:"rate,omitempty"`                                   // Курс обмена (количество целевой валюты за единицу исходной)
+	FromSource    string                 `protobuf:"bytes,4,opt,name=from_source,json=fromSource,proto3" json:"from_source,omitempty"`       // Источник курса исходной валюты (cbr, ecb, manual), пусто для базовой валюты
+	ToSource      string                 `protobuf:"bytes,5,opt,name=to_source,json=toSource,proto3" json:"to_source,omitempty"`             // Источник курса целевой валюты (cbr, ecb, manual), пусто для базовой валюты
+	Degraded      bool                   `protobuf:"varint,6,opt,name=degraded,proto3" json:"degraded,omitempty"`                            // true, если часть курсов устарела (не обновлялась дольше допустимого)
+	PreciseRate   float64                `protobuf:"fixed64,7,opt,name=precise_rate,json=preciseRate,proto3" json:"precise_rate,omitempty"`  // Курс обмена с двойной точностью (для криптовалют точности rate недостаточно)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -135,29 +139,1462 @@ func (x *ExchangeRateResponse) GetRate() float32 {
 	return 0
 }
 
+func (x *ExchangeRateResponse) GetFromSource() string {
+	if x != nil {
+		return x.FromSource
+	}
+	return ""
+}
+
+func (x *ExchangeRateResponse) GetToSource() string {
+	if x != nil {
+		return x.ToSource
+	}
+	return ""
+}
+
+func (x *ExchangeRateResponse) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *ExchangeRateResponse) GetPreciseRate() float64 {
+	if x != nil {
+		return x.PreciseRate
+	}
+	return 0
+}
+
+// Запрос курсов обмена всех валют
+// Без полей совместим с прежним запросом Empty
+type ExchangeRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCurrency  string                 `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"` // Валюта, к которой пересчитываются курсы (например, "EUR"), пусто - базовая валюта сервиса
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeRatesRequest) Reset() {
+	*x = ExchangeRatesRequest{}
+	mi := &file_exchange_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeRatesRequest) ProtoMessage() {}
+
+func (x *ExchangeRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeRatesRequest.ProtoReflect.Descriptor instead.
+func (*ExchangeRatesRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExchangeRatesRequest) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
 // Ответ с курсами обмена всех валют
 type ExchangeRatesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Rates         map[string]float32     `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"` // ключ: валюта, значение: курс
+	Rates         map[string]float32     `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"`                                   // ключ: валюта, значение: стоимость единицы валюты в базовой валюте
+	Sources       map[string]string      `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`                                 // ключ: валюта, значение: источник курса (cbr, ecb, manual)
+	Degraded      bool                   `protobuf:"varint,3,opt,name=degraded,proto3" json:"degraded,omitempty"`                                                                                                        // true, если часть курсов устарела (не обновлялась дольше допустимого)
+	BaseCurrency  string                 `protobuf:"bytes,4,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`                                                                             // Базовая валюта ответа - запрошенная или базовая валюта сервиса (ее курс равен 1)
+	PreciseRates  map[string]float64     `protobuf:"bytes,5,rep,name=precise_rates,json=preciseRates,proto3" json:"precise_rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // Курсы с двойной точностью (для криптовалют точности rates недостаточно)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeRatesResponse) Reset() {
+	*x = ExchangeRatesResponse{}
+	mi := &file_exchange_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeRatesResponse) ProtoMessage() {}
+
+func (x *ExchangeRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeRatesResponse.ProtoReflect.Descriptor instead.
+func (*ExchangeRatesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExchangeRatesResponse) GetRates() map[string]float32 {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *ExchangeRatesResponse) GetSources() map[string]string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *ExchangeRatesResponse) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *ExchangeRatesResponse) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *ExchangeRatesResponse) GetPreciseRates() map[string]float64 {
+	if x != nil {
+		return x.PreciseRates
+	}
+	return nil
+}
+
+// Запрос истории изменений курса валюты
+type RateChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"` // Валюта (например, "EUR")
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`        // Начало периода (Unix timestamp, включительно)
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`            // Конец периода (Unix timestamp, не включительно), 0 - текущий момент
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateChangesRequest) Reset() {
+	*x = RateChangesRequest{}
+	mi := &file_exchange_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateChangesRequest) ProtoMessage() {}
+
+func (x *RateChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateChangesRequest.ProtoReflect.Descriptor instead.
+func (*RateChangesRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RateChangesRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *RateChangesRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RateChangesRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+// Изменение курса валюты
+type RateChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`                     // Валюта
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`                         // Источник курса (cbr, ecb)
+	OldRate       float64                `protobuf:"fixed64,3,opt,name=old_rate,json=oldRate,proto3" json:"old_rate,omitempty"`      // Предыдущий курс, 0 - первое получение курса
+	NewRate       float64                `protobuf:"fixed64,4,opt,name=new_rate,json=newRate,proto3" json:"new_rate,omitempty"`      // Новый курс
+	ChangedAt     int64                  `protobuf:"varint,5,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"` // Время изменения (Unix timestamp)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateChange) Reset() {
+	*x = RateChange{}
+	mi := &file_exchange_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateChange) ProtoMessage() {}
+
+func (x *RateChange) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateChange.ProtoReflect.Descriptor instead.
+func (*RateChange) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RateChange) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *RateChange) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RateChange) GetOldRate() float64 {
+	if x != nil {
+		return x.OldRate
+	}
+	return 0
+}
+
+func (x *RateChange) GetNewRate() float64 {
+	if x != nil {
+		return x.NewRate
+	}
+	return 0
+}
+
+func (x *RateChange) GetChangedAt() int64 {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return 0
+}
+
+// Ответ с историей изменений курса валюты
+type RateChangesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*RateChange          `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`      // Изменения в порядке времени
+	Truncated     bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"` // true, если изменений больше лимита и возвращена только их часть
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateChangesResponse) Reset() {
+	*x = RateChangesResponse{}
+	mi := &file_exchange_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateChangesResponse) ProtoMessage() {}
+
+func (x *RateChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateChangesResponse.ProtoReflect.Descriptor instead.
+func (*RateChangesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RateChangesResponse) GetChanges() []*RateChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *RateChangesResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+// Запрос статистики курса пары валют
+type RateHistoryStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromCurrency  string                 `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"` // Исходная валюта (например, "USD")
+	ToCurrency    string                 `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`       // Целевая валюта (например, "RUB")
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                                    // Начало периода (Unix timestamp), 0 - за 24 часа до конца периода
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                                        // Конец периода (Unix timestamp), 0 - текущий момент
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateHistoryStatsRequest) Reset() {
+	*x = RateHistoryStatsRequest{}
+	mi := &file_exchange_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateHistoryStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateHistoryStatsRequest) ProtoMessage() {}
+
+func (x *RateHistoryStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateHistoryStatsRequest.ProtoReflect.Descriptor instead.
+func (*RateHistoryStatsRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RateHistoryStatsRequest) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *RateHistoryStatsRequest) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *RateHistoryStatsRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+// Статистика курса пары валют за период
+type RateHistoryStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromCurrency  string                 `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`       // Исходная валюта
+	ToCurrency    string                 `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`             // Целевая валюта
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                                          // Начало статистики (позже начала периода, если история курса начинается позже)
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                                              // Конец периода
+	Open          float64                `protobuf:"fixed64,5,opt,name=open,proto3" json:"open,omitempty"`                                         // Курс на начало
+	Close         float64                `protobuf:"fixed64,6,opt,name=close,proto3" json:"close,omitempty"`                                       // Курс на конец
+	Min           float64                `protobuf:"fixed64,7,opt,name=min,proto3" json:"min,omitempty"`                                           // Минимальный курс
+	Max           float64                `protobuf:"fixed64,8,opt,name=max,proto3" json:"max,omitempty"`                                           // Максимальный курс
+	Avg           float64                `protobuf:"fixed64,9,opt,name=avg,proto3" json:"avg,omitempty"`                                           // Средний курс, взвешенный по времени действия
+	ChangePercent float64                `protobuf:"fixed64,10,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"` // Изменение курса за период в процентах
+	Changes       int32                  `protobuf:"varint,11,opt,name=changes,proto3" json:"changes,omitempty"`                                   // Количество значений курса за период
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateHistoryStatsResponse) Reset() {
+	*x = RateHistoryStatsResponse{}
+	mi := &file_exchange_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateHistoryStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateHistoryStatsResponse) ProtoMessage() {}
+
+func (x *RateHistoryStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateHistoryStatsResponse.ProtoReflect.Descriptor instead.
+func (*RateHistoryStatsResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RateHistoryStatsResponse) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *RateHistoryStatsResponse) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *RateHistoryStatsResponse) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsResponse) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsResponse) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsResponse) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsResponse) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsResponse) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsResponse) GetAvg() float64 {
+	if x != nil {
+		return x.Avg
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsResponse) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *RateHistoryStatsResponse) GetChanges() int32 {
+	if x != nil {
+		return x.Changes
+	}
+	return 0
+}
+
+// Запрос свечей курса пары валют
+type RateCandlesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	FromCurrency    string                 `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`           // Исходная валюта (например, "USD")
+	ToCurrency      string                 `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`                 // Целевая валюта (например, "RUB")
+	From            int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                                              // Начало периода (Unix timestamp)
+	To              int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                                                  // Конец периода (Unix timestamp), 0 - текущий момент
+	IntervalSeconds int64                  `protobuf:"varint,5,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // Длительность свечи в секундах
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RateCandlesRequest) Reset() {
+	*x = RateCandlesRequest{}
+	mi := &file_exchange_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateCandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateCandlesRequest) ProtoMessage() {}
+
+func (x *RateCandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateCandlesRequest.ProtoReflect.Descriptor instead.
+func (*RateCandlesRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RateCandlesRequest) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *RateCandlesRequest) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *RateCandlesRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RateCandlesRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *RateCandlesRequest) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+// Свеча курса пары валют за интервал
+type RateCandle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`  // Начало интервала (Unix timestamp)
+	Open          float64                `protobuf:"fixed64,2,opt,name=open,proto3" json:"open,omitempty"`   // Курс на начало интервала
+	High          float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`   // Максимальный курс за интервал
+	Low           float64                `protobuf:"fixed64,4,opt,name=low,proto3" json:"low,omitempty"`     // Минимальный курс за интервал
+	Close         float64                `protobuf:"fixed64,5,opt,name=close,proto3" json:"close,omitempty"` // Курс на конец интервала
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateCandle) Reset() {
+	*x = RateCandle{}
+	mi := &file_exchange_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateCandle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateCandle) ProtoMessage() {}
+
+func (x *RateCandle) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateCandle.ProtoReflect.Descriptor instead.
+func (*RateCandle) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RateCandle) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *RateCandle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *RateCandle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *RateCandle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *RateCandle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+// Ответ со свечами курса пары валют
+type RateCandlesResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	FromCurrency    string                 `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`           // Исходная валюта
+	ToCurrency      string                 `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`                 // Целевая валюта
+	IntervalSeconds int64                  `protobuf:"varint,3,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // Длительность свечи в секундах
+	Candles         []*RateCandle          `protobuf:"bytes,4,rep,name=candles,proto3" json:"candles,omitempty"`                                         // Свечи в порядке времени (начиная с интервала, когда курс пары известен)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RateCandlesResponse) Reset() {
+	*x = RateCandlesResponse{}
+	mi := &file_exchange_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateCandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateCandlesResponse) ProtoMessage() {}
+
+func (x *RateCandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateCandlesResponse.ProtoReflect.Descriptor instead.
+func (*RateCandlesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RateCandlesResponse) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *RateCandlesResponse) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *RateCandlesResponse) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *RateCandlesResponse) GetCandles() []*RateCandle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+// Запрос снимка курсов на конец дня
+type DailySnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // Дата в формате YYYY-MM-DD, пусто - последний сохраненный снимок
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailySnapshotRequest) Reset() {
+	*x = DailySnapshotRequest{}
+	mi := &file_exchange_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailySnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailySnapshotRequest) ProtoMessage() {}
+
+func (x *DailySnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailySnapshotRequest.ProtoReflect.Descriptor instead.
+func (*DailySnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DailySnapshotRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+// Снимок курсов на конец дня
+type DailySnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`                                                                                 // Дата снимка (YYYY-MM-DD)
+	BaseCurrency  string                 `protobuf:"bytes,2,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`                                             // Базовая валюта
+	Rates         map[string]float64     `protobuf:"bytes,3,rep,name=rates,proto3" json:"rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`   // ключ: валюта, значение: стоимость единицы валюты в базовой валюте
+	Sources       map[string]string      `protobuf:"bytes,4,rep,name=sources,proto3" json:"sources,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // ключ: валюта, значение: источник курса
+	TakenAt       int64                  `protobuf:"varint,5,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`                                                           // Время создания снимка (Unix timestamp)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailySnapshotResponse) Reset() {
+	*x = DailySnapshotResponse{}
+	mi := &file_exchange_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailySnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailySnapshotResponse) ProtoMessage() {}
+
+func (x *DailySnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailySnapshotResponse.ProtoReflect.Descriptor instead.
+func (*DailySnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DailySnapshotResponse) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailySnapshotResponse) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *DailySnapshotResponse) GetRates() map[string]float64 {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *DailySnapshotResponse) GetSources() map[string]string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *DailySnapshotResponse) GetTakenAt() int64 {
+	if x != nil {
+		return x.TakenAt
+	}
+	return 0
+}
+
+// Запрос потока событий об изменении курсов
+type WatchRateUpdatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AfterId       int64                  `protobuf:"varint,1,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"` // Идентификатор последнего полученного события, 0 - только новые события
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRateUpdatesRequest) Reset() {
+	*x = WatchRateUpdatesRequest{}
+	mi := &file_exchange_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRateUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRateUpdatesRequest) ProtoMessage() {}
+
+func (x *WatchRateUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRateUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*WatchRateUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchRateUpdatesRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+// Событие об изменении курсов
+type RateUpdateEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                        // Идентификатор события (возрастает, для возобновления потока)
+	BaseCurrency  string                 `protobuf:"bytes,2,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"` // Базовая валюта
+	Currencies    []string               `protobuf:"bytes,3,rep,name=currencies,proto3" json:"currencies,omitempty"`                         // Валюты, курсы которых изменились
+	ChangedAt     int64                  `protobuf:"varint,4,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`         // Время изменения (Unix timestamp)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateUpdateEvent) Reset() {
+	*x = RateUpdateEvent{}
+	mi := &file_exchange_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateUpdateEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateUpdateEvent) ProtoMessage() {}
+
+func (x *RateUpdateEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateUpdateEvent.ProtoReflect.Descriptor instead.
+func (*RateUpdateEvent) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RateUpdateEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RateUpdateEvent) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *RateUpdateEvent) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+func (x *RateUpdateEvent) GetChangedAt() int64 {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return 0
+}
+
+// Сведения о валюте
+type CurrencyInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`                             // Код валюты (например, "USD")
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                             // Название валюты
+	Nominal       int32                  `protobuf:"varint,3,opt,name=nominal,proto3" json:"nominal,omitempty"`                      // Номинал, к которому ЦБ РФ публикует курс (например, 10 для 10 JPY)
+	Decimals      int32                  `protobuf:"varint,4,opt,name=decimals,proto3" json:"decimals,omitempty"`                    // Количество знаков после запятой в суммах валюты (суммы округляются до него)
+	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Время последнего обновления сведений (Unix timestamp)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CurrencyInfo) Reset() {
+	*x = CurrencyInfo{}
+	mi := &file_exchange_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrencyInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyInfo) ProtoMessage() {}
+
+func (x *CurrencyInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyInfo.ProtoReflect.Descriptor instead.
+func (*CurrencyInfo) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CurrencyInfo) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CurrencyInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CurrencyInfo) GetNominal() int32 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+func (x *CurrencyInfo) GetDecimals() int32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *CurrencyInfo) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+// Ответ со справочником валют
+type CurrenciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currencies    []*CurrencyInfo        `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"` // Валюты в порядке кодов
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CurrenciesResponse) Reset() {
+	*x = CurrenciesResponse{}
+	mi := &file_exchange_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrenciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrenciesResponse) ProtoMessage() {}
+
+func (x *CurrenciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrenciesResponse.ProtoReflect.Descriptor instead.
+func (*CurrenciesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CurrenciesResponse) GetCurrencies() []*CurrencyInfo {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+// Состояние фонового обновления курсов с момента запуска сервиса обмена
+type UpdateStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                   // Обновление: rates (фиатные валюты), crypto (криптовалюты)
+	LastRun       int64                  `protobuf:"varint,2,opt,name=last_run,json=lastRun,proto3" json:"last_run,omitempty"`             // Время последнего запуска (Unix timestamp)
+	LastSuccess   int64                  `protobuf:"varint,3,opt,name=last_success,json=lastSuccess,proto3" json:"last_success,omitempty"` // Время последнего успешного обновления (Unix timestamp), 0 - успешных обновлений не было
+	LastError     string                 `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`        // Ошибка последнего запуска, пусто - последний запуск успешен
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`                               // Источник курсов последнего успешного обновления (cbr, aggregate, coingecko)
+	Currencies    int32                  `protobuf:"varint,6,opt,name=currencies,proto3" json:"currencies,omitempty"`                      // Количество валют, полученных при последнем успешном обновлении
+	Runs          int64                  `protobuf:"varint,7,opt,name=runs,proto3" json:"runs,omitempty"`                                  // Запусков с момента запуска сервиса
+	Errors        int64                  `protobuf:"varint,8,opt,name=errors,proto3" json:"errors,omitempty"`                              // Неудачных запусков с момента запуска сервиса
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStatus) Reset() {
+	*x = UpdateStatus{}
+	mi := &file_exchange_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatus) ProtoMessage() {}
+
+func (x *UpdateStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatus.ProtoReflect.Descriptor instead.
+func (*UpdateStatus) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetLastRun() int64 {
+	if x != nil {
+		return x.LastRun
+	}
+	return 0
+}
+
+func (x *UpdateStatus) GetLastSuccess() int64 {
+	if x != nil {
+		return x.LastSuccess
+	}
+	return 0
+}
+
+func (x *UpdateStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetCurrencies() int32 {
+	if x != nil {
+		return x.Currencies
+	}
+	return 0
+}
+
+func (x *UpdateStatus) GetRuns() int64 {
+	if x != nil {
+		return x.Runs
+	}
+	return 0
+}
+
+func (x *UpdateStatus) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+// Ответ с состоянием фоновых обновлений курсов
+type UpdateStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Updates       []*UpdateStatus        `protobuf:"bytes,1,rep,name=updates,proto3" json:"updates,omitempty"`    // Обновления в порядке имен (обновления, которые еще не запускались, не включаются)
+	Degraded      bool                   `protobuf:"varint,2,opt,name=degraded,proto3" json:"degraded,omitempty"` // true, если часть курсов устарела (не обновлялась дольше допустимого)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStatusResponse) Reset() {
+	*x = UpdateStatusResponse{}
+	mi := &file_exchange_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatusResponse) ProtoMessage() {}
+
+func (x *UpdateStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateStatusResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateStatusResponse) GetUpdates() []*UpdateStatus {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+func (x *UpdateStatusResponse) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+// Пустое сообщение(запрос)
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_exchange_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{20}
+}
+
+// Запрос установки ручного курса
+type SetRateOverrideRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`                        // Валюта (например, "EUR")
+	Rate          float64                `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`                              // Курс валюты (стоимость единицы валюты в базовой валюте)
+	TtlSeconds    int64                  `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Время действия курса в секундах
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                            // Причина (записывается в журнал аудита)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRateOverrideRequest) Reset() {
+	*x = SetRateOverrideRequest{}
+	mi := &file_exchange_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRateOverrideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRateOverrideRequest) ProtoMessage() {}
+
+func (x *SetRateOverrideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRateOverrideRequest.ProtoReflect.Descriptor instead.
+func (*SetRateOverrideRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetRateOverrideRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SetRateOverrideRequest) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *SetRateOverrideRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *SetRateOverrideRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Запрос отмены ручного курса
+type ClearRateOverrideRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"` // Валюта
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`     // Причина (записывается в журнал аудита)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearRateOverrideRequest) Reset() {
+	*x = ClearRateOverrideRequest{}
+	mi := &file_exchange_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearRateOverrideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearRateOverrideRequest) ProtoMessage() {}
+
+func (x *ClearRateOverrideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearRateOverrideRequest.ProtoReflect.Descriptor instead.
+func (*ClearRateOverrideRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ClearRateOverrideRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ClearRateOverrideRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Ручной курс валюты
+type RateOverride struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`                     // Валюта
+	Rate          float64                `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`                           // Курс
+	ExpiresAt     int64                  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Время окончания действия (Unix timestamp)
+	SetBy         string                 `protobuf:"bytes,4,opt,name=set_by,json=setBy,proto3" json:"set_by,omitempty"`              // Клиент, установивший курс
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExchangeRatesResponse) Reset() {
-	*x = ExchangeRatesResponse{}
-	mi := &file_exchange_proto_msgTypes[2]
+func (x *RateOverride) Reset() {
+	*x = RateOverride{}
+	mi := &file_exchange_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExchangeRatesResponse) String() string {
+func (x *RateOverride) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExchangeRatesResponse) ProtoMessage() {}
+func (*RateOverride) ProtoMessage() {}
 
-func (x *ExchangeRatesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[2]
+func (x *RateOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,40 +1605,279 @@ func (x *ExchangeRatesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExchangeRatesResponse.ProtoReflect.Descriptor instead.
-func (*ExchangeRatesResponse) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use RateOverride.ProtoReflect.Descriptor instead.
+func (*RateOverride) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *ExchangeRatesResponse) GetRates() map[string]float32 {
+func (x *RateOverride) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *RateOverride) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *RateOverride) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *RateOverride) GetSetBy() string {
+	if x != nil {
+		return x.SetBy
+	}
+	return ""
+}
+
+// Ответ со списком ручных курсов
+type RateOverridesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Overrides     []*RateOverride        `protobuf:"bytes,1,rep,name=overrides,proto3" json:"overrides,omitempty"` // Действующие ручные курсы
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateOverridesResponse) Reset() {
+	*x = RateOverridesResponse{}
+	mi := &file_exchange_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateOverridesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateOverridesResponse) ProtoMessage() {}
+
+func (x *RateOverridesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateOverridesResponse.ProtoReflect.Descriptor instead.
+func (*RateOverridesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RateOverridesResponse) GetOverrides() []*RateOverride {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+// Курс поставщика на карантине: отклоняется от опубликованного больше допустимого
+type QuarantinedRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                          // Идентификатор записи карантина
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`                               // Валюта
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                                   // Источник курса (cbr, aggregate)
+	PreviousRate  float64                `protobuf:"fixed64,4,opt,name=previous_rate,json=previousRate,proto3" json:"previous_rate,omitempty"` // Опубликованный курс источника на момент получения
+	Rate          float64                `protobuf:"fixed64,5,opt,name=rate,proto3" json:"rate,omitempty"`                                     // Полученный курс
+	Deviation     float64                `protobuf:"fixed64,6,opt,name=deviation,proto3" json:"deviation,omitempty"`                           // Отклонение от опубликованного курса в долях (0.35 - 35%)
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`                                   // Состояние: pending, approved, rejected
+	CreatedAt     int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`           // Время первого получения (Unix timestamp)
+	UpdatedAt     int64                  `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`           // Время последнего получения (Unix timestamp)
+	ResolvedBy    string                 `protobuf:"bytes,10,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"`        // Клиент, принявший решение
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`                                  // Причина решения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuarantinedRate) Reset() {
+	*x = QuarantinedRate{}
+	mi := &file_exchange_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantinedRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantinedRate) ProtoMessage() {}
+
+func (x *QuarantinedRate) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantinedRate.ProtoReflect.Descriptor instead.
+func (*QuarantinedRate) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *QuarantinedRate) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *QuarantinedRate) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *QuarantinedRate) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *QuarantinedRate) GetPreviousRate() float64 {
+	if x != nil {
+		return x.PreviousRate
+	}
+	return 0
+}
+
+func (x *QuarantinedRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *QuarantinedRate) GetDeviation() float64 {
+	if x != nil {
+		return x.Deviation
+	}
+	return 0
+}
+
+func (x *QuarantinedRate) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *QuarantinedRate) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *QuarantinedRate) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *QuarantinedRate) GetResolvedBy() string {
+	if x != nil {
+		return x.ResolvedBy
+	}
+	return ""
+}
+
+func (x *QuarantinedRate) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Ответ со списком курсов на карантине
+type QuarantinedRatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rates         []*QuarantinedRate     `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty"` // Курсы, ожидающие проверки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuarantinedRatesResponse) Reset() {
+	*x = QuarantinedRatesResponse{}
+	mi := &file_exchange_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantinedRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantinedRatesResponse) ProtoMessage() {}
+
+func (x *QuarantinedRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantinedRatesResponse.ProtoReflect.Descriptor instead.
+func (*QuarantinedRatesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *QuarantinedRatesResponse) GetRates() []*QuarantinedRate {
 	if x != nil {
 		return x.Rates
 	}
 	return nil
 }
 
-// Пустое сообщение(запрос)
-type Empty struct {
+// Запрос решения по курсу на карантине
+type ResolveQuarantinedRateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`           // Идентификатор записи карантина
+	Approve       bool                   `protobuf:"varint,2,opt,name=approve,proto3" json:"approve,omitempty"` // true - принять и опубликовать курс, false - отклонить
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`    // Причина (записывается в карантин)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
-	mi := &file_exchange_proto_msgTypes[3]
+func (x *ResolveQuarantinedRateRequest) Reset() {
+	*x = ResolveQuarantinedRateRequest{}
+	mi := &file_exchange_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *ResolveQuarantinedRateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*ResolveQuarantinedRateRequest) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[3]
+func (x *ResolveQuarantinedRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -212,9 +1888,30 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ResolveQuarantinedRateRequest.ProtoReflect.Descriptor instead.
+func (*ResolveQuarantinedRateRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ResolveQuarantinedRateRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ResolveQuarantinedRateRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *ResolveQuarantinedRateRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
 }
 
 var File_exchange_proto protoreflect.FileDescriptor
@@ -225,22 +1922,197 @@ const file_exchange_proto_rawDesc = "" +
 	"\x0fCurrencyRequest\x12#\n" +
 	"\rfrom_currency\x18\x01 \x01(\tR\ffromCurrency\x12\x1f\n" +
 	"\vto_currency\x18\x02 \x01(\tR\n" +
-	"toCurrency\"p\n" +
+	"toCurrency\"\xed\x01\n" +
 	"\x14ExchangeRateResponse\x12#\n" +
 	"\rfrom_currency\x18\x01 \x01(\tR\ffromCurrency\x12\x1f\n" +
 	"\vto_currency\x18\x02 \x01(\tR\n" +
 	"toCurrency\x12\x12\n" +
-	"\x04rate\x18\x03 \x01(\x02R\x04rate\"\x93\x01\n" +
+	"\x04rate\x18\x03 \x01(\x02R\x04rate\x12\x1f\n" +
+	"\vfrom_source\x18\x04 \x01(\tR\n" +
+	"fromSource\x12\x1b\n" +
+	"\tto_source\x18\x05 \x01(\tR\btoSource\x12\x1a\n" +
+	"\bdegraded\x18\x06 \x01(\bR\bdegraded\x12!\n" +
+	"\fprecise_rate\x18\a \x01(\x01R\vpreciseRate\";\n" +
+	"\x14ExchangeRatesRequest\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\"\xf1\x03\n" +
 	"\x15ExchangeRatesResponse\x12@\n" +
-	"\x05rates\x18\x01 \x03(\v2*.exchange.ExchangeRatesResponse.RatesEntryR\x05rates\x1a8\n" +
+	"\x05rates\x18\x01 \x03(\v2*.exchange.ExchangeRatesResponse.RatesEntryR\x05rates\x12F\n" +
+	"\asources\x18\x02 \x03(\v2,.exchange.ExchangeRatesResponse.SourcesEntryR\asources\x12\x1a\n" +
+	"\bdegraded\x18\x03 \x01(\bR\bdegraded\x12#\n" +
+	"\rbase_currency\x18\x04 \x01(\tR\fbaseCurrency\x12V\n" +
+	"\rprecise_rates\x18\x05 \x03(\v21.exchange.ExchangeRatesResponse.PreciseRatesEntryR\fpreciseRates\x1a8\n" +
+	"\n" +
+	"RatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x02R\x05value:\x028\x01\x1a:\n" +
+	"\fSourcesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a?\n" +
+	"\x11PreciseRatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"T\n" +
+	"\x12RateChangesRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\"\x95\x01\n" +
+	"\n" +
+	"RateChange\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x19\n" +
+	"\bold_rate\x18\x03 \x01(\x01R\aoldRate\x12\x19\n" +
+	"\bnew_rate\x18\x04 \x01(\x01R\anewRate\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x05 \x01(\x03R\tchangedAt\"c\n" +
+	"\x13RateChangesResponse\x12.\n" +
+	"\achanges\x18\x01 \x03(\v2\x14.exchange.RateChangeR\achanges\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\"\x83\x01\n" +
+	"\x17RateHistoryStatsRequest\x12#\n" +
+	"\rfrom_currency\x18\x01 \x01(\tR\ffromCurrency\x12\x1f\n" +
+	"\vto_currency\x18\x02 \x01(\tR\n" +
+	"toCurrency\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\"\xa5\x02\n" +
+	"\x18RateHistoryStatsResponse\x12#\n" +
+	"\rfrom_currency\x18\x01 \x01(\tR\ffromCurrency\x12\x1f\n" +
+	"\vto_currency\x18\x02 \x01(\tR\n" +
+	"toCurrency\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x12\n" +
+	"\x04open\x18\x05 \x01(\x01R\x04open\x12\x14\n" +
+	"\x05close\x18\x06 \x01(\x01R\x05close\x12\x10\n" +
+	"\x03min\x18\a \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\b \x01(\x01R\x03max\x12\x10\n" +
+	"\x03avg\x18\t \x01(\x01R\x03avg\x12%\n" +
+	"\x0echange_percent\x18\n" +
+	" \x01(\x01R\rchangePercent\x12\x18\n" +
+	"\achanges\x18\v \x01(\x05R\achanges\"\xa9\x01\n" +
+	"\x12RateCandlesRequest\x12#\n" +
+	"\rfrom_currency\x18\x01 \x01(\tR\ffromCurrency\x12\x1f\n" +
+	"\vto_currency\x18\x02 \x01(\tR\n" +
+	"toCurrency\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12)\n" +
+	"\x10interval_seconds\x18\x05 \x01(\x03R\x0fintervalSeconds\"r\n" +
+	"\n" +
+	"RateCandle\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x12\n" +
+	"\x04open\x18\x02 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x03 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x04 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x05 \x01(\x01R\x05close\"\xb6\x01\n" +
+	"\x13RateCandlesResponse\x12#\n" +
+	"\rfrom_currency\x18\x01 \x01(\tR\ffromCurrency\x12\x1f\n" +
+	"\vto_currency\x18\x02 \x01(\tR\n" +
+	"toCurrency\x12)\n" +
+	"\x10interval_seconds\x18\x03 \x01(\x03R\x0fintervalSeconds\x12.\n" +
+	"\acandles\x18\x04 \x03(\v2\x14.exchange.RateCandleR\acandles\"*\n" +
+	"\x14DailySnapshotRequest\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\"\xeb\x02\n" +
+	"\x15DailySnapshotResponse\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12#\n" +
+	"\rbase_currency\x18\x02 \x01(\tR\fbaseCurrency\x12@\n" +
+	"\x05rates\x18\x03 \x03(\v2*.exchange.DailySnapshotResponse.RatesEntryR\x05rates\x12F\n" +
+	"\asources\x18\x04 \x03(\v2,.exchange.DailySnapshotResponse.SourcesEntryR\asources\x12\x19\n" +
+	"\btaken_at\x18\x05 \x01(\x03R\atakenAt\x1a8\n" +
 	"\n" +
 	"RatesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x02R\x05value:\x028\x01\"\a\n" +
-	"\x05Empty2\xb0\x01\n" +
-	"\x0fExchangeService\x12D\n" +
-	"\x10GetExchangeRates\x12\x0f.exchange.Empty\x1a\x1f.exchange.ExchangeRatesResponse\x12W\n" +
-	"\x1aGetExchangeRateForCurrency\x12\x19.exchange.CurrencyRequest\x1a\x1e.exchange.ExchangeRateResponseB\x13Z\x11gw-exchange/protob\x06proto3"
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a:\n" +
+	"\fSourcesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"4\n" +
+	"\x17WatchRateUpdatesRequest\x12\x19\n" +
+	"\bafter_id\x18\x01 \x01(\x03R\aafterId\"\x85\x01\n" +
+	"\x0fRateUpdateEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
+	"\rbase_currency\x18\x02 \x01(\tR\fbaseCurrency\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x03 \x03(\tR\n" +
+	"currencies\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x04 \x01(\x03R\tchangedAt\"\x8b\x01\n" +
+	"\fCurrencyInfo\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\anominal\x18\x03 \x01(\x05R\anominal\x12\x1a\n" +
+	"\bdecimals\x18\x04 \x01(\x05R\bdecimals\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\"L\n" +
+	"\x12CurrenciesResponse\x126\n" +
+	"\n" +
+	"currencies\x18\x01 \x03(\v2\x16.exchange.CurrencyInfoR\n" +
+	"currencies\"\xe3\x01\n" +
+	"\fUpdateStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\blast_run\x18\x02 \x01(\x03R\alastRun\x12!\n" +
+	"\flast_success\x18\x03 \x01(\x03R\vlastSuccess\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tR\tlastError\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x06 \x01(\x05R\n" +
+	"currencies\x12\x12\n" +
+	"\x04runs\x18\a \x01(\x03R\x04runs\x12\x16\n" +
+	"\x06errors\x18\b \x01(\x03R\x06errors\"d\n" +
+	"\x14UpdateStatusResponse\x120\n" +
+	"\aupdates\x18\x01 \x03(\v2\x16.exchange.UpdateStatusR\aupdates\x12\x1a\n" +
+	"\bdegraded\x18\x02 \x01(\bR\bdegraded\"\a\n" +
+	"\x05Empty\"\x81\x01\n" +
+	"\x16SetRateOverrideRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\x01R\x04rate\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x03R\n" +
+	"ttlSeconds\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"N\n" +
+	"\x18ClearRateOverrideRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"t\n" +
+	"\fRateOverride\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\x01R\x04rate\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\x12\x15\n" +
+	"\x06set_by\x18\x04 \x01(\tR\x05setBy\"M\n" +
+	"\x15RateOverridesResponse\x124\n" +
+	"\toverrides\x18\x01 \x03(\v2\x16.exchange.RateOverrideR\toverrides\"\xbb\x02\n" +
+	"\x0fQuarantinedRate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12#\n" +
+	"\rprevious_rate\x18\x04 \x01(\x01R\fpreviousRate\x12\x12\n" +
+	"\x04rate\x18\x05 \x01(\x01R\x04rate\x12\x1c\n" +
+	"\tdeviation\x18\x06 \x01(\x01R\tdeviation\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\x03R\tupdatedAt\x12\x1f\n" +
+	"\vresolved_by\x18\n" +
+	" \x01(\tR\n" +
+	"resolvedBy\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\"K\n" +
+	"\x18QuarantinedRatesResponse\x12/\n" +
+	"\x05rates\x18\x01 \x03(\v2\x19.exchange.QuarantinedRateR\x05rates\"a\n" +
+	"\x1dResolveQuarantinedRateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aapprove\x18\x02 \x01(\bR\aapprove\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason2\xe8\x05\n" +
+	"\x0fExchangeService\x12S\n" +
+	"\x10GetExchangeRates\x12\x1e.exchange.ExchangeRatesRequest\x1a\x1f.exchange.ExchangeRatesResponse\x12W\n" +
+	"\x1aGetExchangeRateForCurrency\x12\x19.exchange.CurrencyRequest\x1a\x1e.exchange.ExchangeRateResponse\x12M\n" +
+	"\x0eGetRateChanges\x12\x1c.exchange.RateChangesRequest\x1a\x1d.exchange.RateChangesResponse\x12\\\n" +
+	"\x13GetRateHistoryStats\x12!.exchange.RateHistoryStatsRequest\x1a\".exchange.RateHistoryStatsResponse\x12M\n" +
+	"\x0eGetRateCandles\x12\x1c.exchange.RateCandlesRequest\x1a\x1d.exchange.RateCandlesResponse\x12S\n" +
+	"\x10GetDailySnapshot\x12\x1e.exchange.DailySnapshotRequest\x1a\x1f.exchange.DailySnapshotResponse\x12R\n" +
+	"\x10WatchRateUpdates\x12!.exchange.WatchRateUpdatesRequest\x1a\x19.exchange.RateUpdateEvent0\x01\x12>\n" +
+	"\rGetCurrencies\x12\x0f.exchange.Empty\x1a\x1c.exchange.CurrenciesResponse\x12B\n" +
+	"\x0fGetUpdateStatus\x12\x0f.exchange.Empty\x1a\x1e.exchange.UpdateStatusResponse2\xa2\x03\n" +
+	"\x10RateAdminService\x12K\n" +
+	"\x0fSetRateOverride\x12 .exchange.SetRateOverrideRequest\x1a\x16.exchange.RateOverride\x12O\n" +
+	"\x11ClearRateOverride\x12\".exchange.ClearRateOverrideRequest\x1a\x16.exchange.RateOverride\x12E\n" +
+	"\x11ListRateOverrides\x12\x0f.exchange.Empty\x1a\x1f.exchange.RateOverridesResponse\x12K\n" +
+	"\x14ListQuarantinedRates\x12\x0f.exchange.Empty\x1a\".exchange.QuarantinedRatesResponse\x12\\\n" +
+	"\x16ResolveQuarantinedRate\x12'.exchange.ResolveQuarantinedRateRequest\x1a\x19.exchange.QuarantinedRateB\x13Z\x11gw-exchange/protob\x06proto3"
 
 var (
 	file_exchange_proto_rawDescOnce sync.Once
@@ -254,25 +2126,87 @@ func file_exchange_proto_rawDescGZIP() []byte {
 	return file_exchange_proto_rawDescData
 }
 
-var file_exchange_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_exchange_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_exchange_proto_goTypes = []any{
-	(*CurrencyRequest)(nil),       // 0: exchange.CurrencyRequest
-	(*ExchangeRateResponse)(nil),  // 1: exchange.ExchangeRateResponse
-	(*ExchangeRatesResponse)(nil), // 2: exchange.ExchangeRatesResponse
-	(*Empty)(nil),                 // 3: exchange.Empty
-	nil,                           // 4: exchange.ExchangeRatesResponse.RatesEntry
+	(*CurrencyRequest)(nil),               // 0: exchange.CurrencyRequest
+	(*ExchangeRateResponse)(nil),          // 1: exchange.ExchangeRateResponse
+	(*ExchangeRatesRequest)(nil),          // 2: exchange.ExchangeRatesRequest
+	(*ExchangeRatesResponse)(nil),         // 3: exchange.ExchangeRatesResponse
+	(*RateChangesRequest)(nil),            // 4: exchange.RateChangesRequest
+	(*RateChange)(nil),                    // 5: exchange.RateChange
+	(*RateChangesResponse)(nil),           // 6: exchange.RateChangesResponse
+	(*RateHistoryStatsRequest)(nil),       // 7: exchange.RateHistoryStatsRequest
+	(*RateHistoryStatsResponse)(nil),      // 8: exchange.RateHistoryStatsResponse
+	(*RateCandlesRequest)(nil),            // 9: exchange.RateCandlesRequest
+	(*RateCandle)(nil),                    // 10: exchange.RateCandle
+	(*RateCandlesResponse)(nil),           // 11: exchange.RateCandlesResponse
+	(*DailySnapshotRequest)(nil),          // 12: exchange.DailySnapshotRequest
+	(*DailySnapshotResponse)(nil),         // 13: exchange.DailySnapshotResponse
+	(*WatchRateUpdatesRequest)(nil),       // 14: exchange.WatchRateUpdatesRequest
+	(*RateUpdateEvent)(nil),               // 15: exchange.RateUpdateEvent
+	(*CurrencyInfo)(nil),                  // 16: exchange.CurrencyInfo
+	(*CurrenciesResponse)(nil),            // 17: exchange.CurrenciesResponse
+	(*UpdateStatus)(nil),                  // 18: exchange.UpdateStatus
+	(*UpdateStatusResponse)(nil),          // 19: exchange.UpdateStatusResponse
+	(*Empty)(nil),                         // 20: exchange.Empty
+	(*SetRateOverrideRequest)(nil),        // 21: exchange.SetRateOverrideRequest
+	(*ClearRateOverrideRequest)(nil),      // 22: exchange.ClearRateOverrideRequest
+	(*RateOverride)(nil),                  // 23: exchange.RateOverride
+	(*RateOverridesResponse)(nil),         // 24: exchange.RateOverridesResponse
+	(*QuarantinedRate)(nil),               // 25: exchange.QuarantinedRate
+	(*QuarantinedRatesResponse)(nil),      // 26: exchange.QuarantinedRatesResponse
+	(*ResolveQuarantinedRateRequest)(nil), // 27: exchange.ResolveQuarantinedRateRequest
+	nil,                                   // 28: exchange.ExchangeRatesResponse.RatesEntry
+	nil,                                   // 29: exchange.ExchangeRatesResponse.SourcesEntry
+	nil,                                   // 30: exchange.ExchangeRatesResponse.PreciseRatesEntry
+	nil,                                   // 31: exchange.DailySnapshotResponse.RatesEntry
+	nil,                                   // 32: exchange.DailySnapshotResponse.SourcesEntry
 }
 var file_exchange_proto_depIdxs = []int32{
-	4, // 0: exchange.ExchangeRatesResponse.rates:type_name -> exchange.ExchangeRatesResponse.RatesEntry
-	3, // 1: exchange.ExchangeService.GetExchangeRates:input_type -> exchange.Empty
-	0, // 2: exchange.ExchangeService.GetExchangeRateForCurrency:input_type -> exchange.CurrencyRequest
-	2, // 3: exchange.ExchangeService.GetExchangeRates:output_type -> exchange.ExchangeRatesResponse
-	1, // 4: exchange.ExchangeService.GetExchangeRateForCurrency:output_type -> exchange.ExchangeRateResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	28, // 0: exchange.ExchangeRatesResponse.rates:type_name -> exchange.ExchangeRatesResponse.RatesEntry
+	29, // 1: exchange.ExchangeRatesResponse.sources:type_name -> exchange.ExchangeRatesResponse.SourcesEntry
+	30, // 2: exchange.ExchangeRatesResponse.precise_rates:type_name -> exchange.ExchangeRatesResponse.PreciseRatesEntry
+	5,  // 3: exchange.RateChangesResponse.changes:type_name -> exchange.RateChange
+	10, // 4: exchange.RateCandlesResponse.candles:type_name -> exchange.RateCandle
+	31, // 5: exchange.DailySnapshotResponse.rates:type_name -> exchange.DailySnapshotResponse.RatesEntry
+	32, // 6: exchange.DailySnapshotResponse.sources:type_name -> exchange.DailySnapshotResponse.SourcesEntry
+	16, // 7: exchange.CurrenciesResponse.currencies:type_name -> exchange.CurrencyInfo
+	18, // 8: exchange.UpdateStatusResponse.updates:type_name -> exchange.UpdateStatus
+	23, // 9: exchange.RateOverridesResponse.overrides:type_name -> exchange.RateOverride
+	25, // 10: exchange.QuarantinedRatesResponse.rates:type_name -> exchange.QuarantinedRate
+	2,  // 11: exchange.ExchangeService.GetExchangeRates:input_type -> exchange.ExchangeRatesRequest
+	0,  // 12: exchange.ExchangeService.GetExchangeRateForCurrency:input_type -> exchange.CurrencyRequest
+	4,  // 13: exchange.ExchangeService.GetRateChanges:input_type -> exchange.RateChangesRequest
+	7,  // 14: exchange.ExchangeService.GetRateHistoryStats:input_type -> exchange.RateHistoryStatsRequest
+	9,  // 15: exchange.ExchangeService.GetRateCandles:input_type -> exchange.RateCandlesRequest
+	12, // 16: exchange.ExchangeService.GetDailySnapshot:input_type -> exchange.DailySnapshotRequest
+	14, // 17: exchange.ExchangeService.WatchRateUpdates:input_type -> exchange.WatchRateUpdatesRequest
+	20, // 18: exchange.ExchangeService.GetCurrencies:input_type -> exchange.Empty
+	20, // 19: exchange.ExchangeService.GetUpdateStatus:input_type -> exchange.Empty
+	21, // 20: exchange.RateAdminService.SetRateOverride:input_type -> exchange.SetRateOverrideRequest
+	22, // 21: exchange.RateAdminService.ClearRateOverride:input_type -> exchange.ClearRateOverrideRequest
+	20, // 22: exchange.RateAdminService.ListRateOverrides:input_type -> exchange.Empty
+	20, // 23: exchange.RateAdminService.ListQuarantinedRates:input_type -> exchange.Empty
+	27, // 24: exchange.RateAdminService.ResolveQuarantinedRate:input_type -> exchange.ResolveQuarantinedRateRequest
+	3,  // 25: exchange.ExchangeService.GetExchangeRates:output_type -> exchange.ExchangeRatesResponse
+	1,  // 26: exchange.ExchangeService.GetExchangeRateForCurrency:output_type -> exchange.ExchangeRateResponse
+	6,  // 27: exchange.ExchangeService.GetRateChanges:output_type -> exchange.RateChangesResponse
+	8,  // 28: exchange.ExchangeService.GetRateHistoryStats:output_type -> exchange.RateHistoryStatsResponse
+	11, // 29: exchange.ExchangeService.GetRateCandles:output_type -> exchange.RateCandlesResponse
+	13, // 30: exchange.ExchangeService.GetDailySnapshot:output_type -> exchange.DailySnapshotResponse
+	15, // 31: exchange.ExchangeService.WatchRateUpdates:output_type -> exchange.RateUpdateEvent
+	17, // 32: exchange.ExchangeService.GetCurrencies:output_type -> exchange.CurrenciesResponse
+	19, // 33: exchange.ExchangeService.GetUpdateStatus:output_type -> exchange.UpdateStatusResponse
+	23, // 34: exchange.RateAdminService.SetRateOverride:output_type -> exchange.RateOverride
+	23, // 35: exchange.RateAdminService.ClearRateOverride:output_type -> exchange.RateOverride
+	24, // 36: exchange.RateAdminService.ListRateOverrides:output_type -> exchange.RateOverridesResponse
+	26, // 37: exchange.RateAdminService.ListQuarantinedRates:output_type -> exchange.QuarantinedRatesResponse
+	25, // 38: exchange.RateAdminService.ResolveQuarantinedRate:output_type -> exchange.QuarantinedRate
+	25, // [25:39] is the sub-list for method output_type
+	11, // [11:25] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_exchange_proto_init() }
@@ -286,9 +2220,9 @@ func file_exchange_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_exchange_proto_rawDesc), len(file_exchange_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   33,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_exchange_proto_goTypes,
 		DependencyIndexes: file_exchange_proto_depIdxs,
@@ -21,6 +21,13 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	ExchangeService_GetExchangeRates_FullMethodName           = "/exchange.ExchangeService/GetExchangeRates"
 	ExchangeService_GetExchangeRateForCurrency_FullMethodName = "/exchange.ExchangeService/GetExchangeRateForCurrency"
+	ExchangeService_GetRateChanges_FullMethodName             = "/exchange.ExchangeService/GetRateChanges"
+	ExchangeService_GetRateHistoryStats_FullMethodName        = "/exchange.ExchangeService/GetRateHistoryStats"
+	ExchangeService_GetRateCandles_FullMethodName             = "/exchange.ExchangeService/GetRateCandles"
+	ExchangeService_GetDailySnapshot_FullMethodName           = "/exchange.ExchangeService/GetDailySnapshot"
+	ExchangeService_WatchRateUpdates_FullMethodName           = "/exchange.ExchangeService/WatchRateUpdates"
+	ExchangeService_GetCurrencies_FullMethodName              = "/exchange.ExchangeService/GetCurrencies"
+	ExchangeService_GetUpdateStatus_FullMethodName            = "/exchange.ExchangeService/GetUpdateStatus"
 )
 
 // ExchangeServiceClient is the client API for ExchangeService service.
@@ -29,10 +36,24 @@ const (
 //
 // Определение сервиса обмена валют
 type ExchangeServiceClient interface {
-	// Получение курсов обмена всех валют
-	GetExchangeRates(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ExchangeRatesResponse, error)
+	// Получение курсов обмена всех валют (к базовой валюте сервиса или к запрошенной валюте)
+	GetExchangeRates(ctx context.Context, in *ExchangeRatesRequest, opts ...grpc.CallOption) (*ExchangeRatesResponse, error)
 	// Получение курса обмена для конкретной валюты
 	GetExchangeRateForCurrency(ctx context.Context, in *CurrencyRequest, opts ...grpc.CallOption) (*ExchangeRateResponse, error)
+	// Получение истории изменений курса валюты за период
+	GetRateChanges(ctx context.Context, in *RateChangesRequest, opts ...grpc.CallOption) (*RateChangesResponse, error)
+	// Получение статистики курса пары валют за период (минимум, максимум, среднее, изменение)
+	GetRateHistoryStats(ctx context.Context, in *RateHistoryStatsRequest, opts ...grpc.CallOption) (*RateHistoryStatsResponse, error)
+	// Получение свечей курса пары валют за период (открытие, максимум, минимум, закрытие по интервалам)
+	GetRateCandles(ctx context.Context, in *RateCandlesRequest, opts ...grpc.CallOption) (*RateCandlesResponse, error)
+	// Получение снимка курсов на конец дня (официальные курсы закрытия)
+	GetDailySnapshot(ctx context.Context, in *DailySnapshotRequest, opts ...grpc.CallOption) (*DailySnapshotResponse, error)
+	// Поток событий об изменении курсов (для сброса кэша клиентов сразу после изменения)
+	WatchRateUpdates(ctx context.Context, in *WatchRateUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RateUpdateEvent], error)
+	// Получение справочника валют (название, номинал, количество знаков после запятой)
+	GetCurrencies(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CurrenciesResponse, error)
+	// Получение состояния фоновых обновлений курсов (время последнего успешного обновления, ошибка, источник)
+	GetUpdateStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UpdateStatusResponse, error)
 }
 
 type exchangeServiceClient struct {
@@ -43,7 +64,7 @@ func NewExchangeServiceClient(cc grpc.ClientConnInterface) ExchangeServiceClient
 	return &exchangeServiceClient{cc}
 }
 
-func (c *exchangeServiceClient) GetExchangeRates(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ExchangeRatesResponse, error) {
+func (c *exchangeServiceClient) GetExchangeRates(ctx context.Context, in *ExchangeRatesRequest, opts ...grpc.CallOption) (*ExchangeRatesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ExchangeRatesResponse)
 	err := c.cc.Invoke(ctx, ExchangeService_GetExchangeRates_FullMethodName, in, out, cOpts...)
@@ -63,16 +84,109 @@ func (c *exchangeServiceClient) GetExchangeRateForCurrency(ctx context.Context,
 	return out, nil
 }
 
+func (c *exchangeServiceClient) GetRateChanges(ctx context.Context, in *RateChangesRequest, opts ...grpc.CallOption) (*RateChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateChangesResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetRateChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetRateHistoryStats(ctx context.Context, in *RateHistoryStatsRequest, opts ...grpc.CallOption) (*RateHistoryStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateHistoryStatsResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetRateHistoryStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetRateCandles(ctx context.Context, in *RateCandlesRequest, opts ...grpc.CallOption) (*RateCandlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateCandlesResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetRateCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetDailySnapshot(ctx context.Context, in *DailySnapshotRequest, opts ...grpc.CallOption) (*DailySnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DailySnapshotResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetDailySnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) WatchRateUpdates(ctx context.Context, in *WatchRateUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RateUpdateEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExchangeService_ServiceDesc.Streams[0], ExchangeService_WatchRateUpdates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRateUpdatesRequest, RateUpdateEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExchangeService_WatchRateUpdatesClient = grpc.ServerStreamingClient[RateUpdateEvent]
+
+func (c *exchangeServiceClient) GetCurrencies(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CurrenciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CurrenciesResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetCurrencies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetUpdateStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UpdateStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateStatusResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetUpdateStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExchangeServiceServer is the server API for ExchangeService service.
 // All implementations must embed UnimplementedExchangeServiceServer
 // for forward compatibility.
 //
 // Определение сервиса обмена валют
 type ExchangeServiceServer interface {
-	// Получение курсов обмена всех валют
-	GetExchangeRates(context.Context, *Empty) (*ExchangeRatesResponse, error)
+	// Получение курсов обмена всех валют (к базовой валюте сервиса или к запрошенной валюте)
+	GetExchangeRates(context.Context, *ExchangeRatesRequest) (*ExchangeRatesResponse, error)
 	// Получение курса обмена для конкретной валюты
 	GetExchangeRateForCurrency(context.Context, *CurrencyRequest) (*ExchangeRateResponse, error)
+	// Получение истории изменений курса валюты за период
+	GetRateChanges(context.Context, *RateChangesRequest) (*RateChangesResponse, error)
+	// Получение статистики курса пары валют за период (минимум, максимум, среднее, изменение)
+	GetRateHistoryStats(context.Context, *RateHistoryStatsRequest) (*RateHistoryStatsResponse, error)
+	// Получение свечей курса пары валют за период (открытие, максимум, минимум, закрытие по интервалам)
+	GetRateCandles(context.Context, *RateCandlesRequest) (*RateCandlesResponse, error)
+	// Получение снимка курсов на конец дня (официальные курсы закрытия)
+	GetDailySnapshot(context.Context, *DailySnapshotRequest) (*DailySnapshotResponse, error)
+	// Поток событий об изменении курсов (для сброса кэша клиентов сразу после изменения)
+	WatchRateUpdates(*WatchRateUpdatesRequest, grpc.ServerStreamingServer[RateUpdateEvent]) error
+	// Получение справочника валют (название, номинал, количество знаков после запятой)
+	GetCurrencies(context.Context, *Empty) (*CurrenciesResponse, error)
+	// Получение состояния фоновых обновлений курсов (время последнего успешного обновления, ошибка, источник)
+	GetUpdateStatus(context.Context, *Empty) (*UpdateStatusResponse, error)
 	mustEmbedUnimplementedExchangeServiceServer()
 }
 
@@ -83,12 +197,33 @@ type ExchangeServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedExchangeServiceServer struct{}
 
-func (UnimplementedExchangeServiceServer) GetExchangeRates(context.Context, *Empty) (*ExchangeRatesResponse, error) {
+func (UnimplementedExchangeServiceServer) GetExchangeRates(context.Context, *ExchangeRatesRequest) (*ExchangeRatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetExchangeRates not implemented")
 }
 func (UnimplementedExchangeServiceServer) GetExchangeRateForCurrency(context.Context, *CurrencyRequest) (*ExchangeRateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetExchangeRateForCurrency not implemented")
 }
+func (UnimplementedExchangeServiceServer) GetRateChanges(context.Context, *RateChangesRequest) (*RateChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateChanges not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetRateHistoryStats(context.Context, *RateHistoryStatsRequest) (*RateHistoryStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateHistoryStats not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetRateCandles(context.Context, *RateCandlesRequest) (*RateCandlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateCandles not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetDailySnapshot(context.Context, *DailySnapshotRequest) (*DailySnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDailySnapshot not implemented")
+}
+func (UnimplementedExchangeServiceServer) WatchRateUpdates(*WatchRateUpdatesRequest, grpc.ServerStreamingServer[RateUpdateEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRateUpdates not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetCurrencies(context.Context, *Empty) (*CurrenciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrencies not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetUpdateStatus(context.Context, *Empty) (*UpdateStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpdateStatus not implemented")
+}
 func (UnimplementedExchangeServiceServer) mustEmbedUnimplementedExchangeServiceServer() {}
 func (UnimplementedExchangeServiceServer) testEmbeddedByValue()                         {}
 
@@ -111,7 +246,7 @@ func RegisterExchangeServiceServer(s grpc.ServiceRegistrar, srv ExchangeServiceS
 }
 
 func _ExchangeService_GetExchangeRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(ExchangeRatesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -123,7 +258,7 @@ func _ExchangeService_GetExchangeRates_Handler(srv interface{}, ctx context.Cont
 		FullMethod: ExchangeService_GetExchangeRates_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ExchangeServiceServer).GetExchangeRates(ctx, req.(*Empty))
+		return srv.(ExchangeServiceServer).GetExchangeRates(ctx, req.(*ExchangeRatesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -146,6 +281,125 @@ func _ExchangeService_GetExchangeRateForCurrency_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ExchangeService_GetRateChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetRateChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetRateChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetRateChanges(ctx, req.(*RateChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetRateHistoryStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateHistoryStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetRateHistoryStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetRateHistoryStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetRateHistoryStats(ctx, req.(*RateHistoryStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetRateCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateCandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetRateCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetRateCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetRateCandles(ctx, req.(*RateCandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetDailySnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DailySnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetDailySnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetDailySnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetDailySnapshot(ctx, req.(*DailySnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_WatchRateUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRateUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExchangeServiceServer).WatchRateUpdates(m, &grpc.GenericServerStream[WatchRateUpdatesRequest, RateUpdateEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExchangeService_WatchRateUpdatesServer = grpc.ServerStreamingServer[RateUpdateEvent]
+
+func _ExchangeService_GetCurrencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetCurrencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetCurrencies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetCurrencies(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetUpdateStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetUpdateStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetUpdateStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetUpdateStatus(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ExchangeService_ServiceDesc is the grpc.ServiceDesc for ExchangeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,6 +415,306 @@ var ExchangeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetExchangeRateForCurrency",
 			Handler:    _ExchangeService_GetExchangeRateForCurrency_Handler,
 		},
+		{
+			MethodName: "GetRateChanges",
+			Handler:    _ExchangeService_GetRateChanges_Handler,
+		},
+		{
+			MethodName: "GetRateHistoryStats",
+			Handler:    _ExchangeService_GetRateHistoryStats_Handler,
+		},
+		{
+			MethodName: "GetRateCandles",
+			Handler:    _ExchangeService_GetRateCandles_Handler,
+		},
+		{
+			MethodName: "GetDailySnapshot",
+			Handler:    _ExchangeService_GetDailySnapshot_Handler,
+		},
+		{
+			MethodName: "GetCurrencies",
+			Handler:    _ExchangeService_GetCurrencies_Handler,
+		},
+		{
+			MethodName: "GetUpdateStatus",
+			Handler:    _ExchangeService_GetUpdateStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRateUpdates",
+			Handler:       _ExchangeService_WatchRateUpdates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "exchange.proto",
+}
+
+const (
+	RateAdminService_SetRateOverride_FullMethodName        = "/exchange.RateAdminService/SetRateOverride"
+	RateAdminService_ClearRateOverride_FullMethodName      = "/exchange.RateAdminService/ClearRateOverride"
+	RateAdminService_ListRateOverrides_FullMethodName      = "/exchange.RateAdminService/ListRateOverrides"
+	RateAdminService_ListQuarantinedRates_FullMethodName   = "/exchange.RateAdminService/ListQuarantinedRates"
+	RateAdminService_ResolveQuarantinedRate_FullMethodName = "/exchange.RateAdminService/ResolveQuarantinedRate"
+)
+
+// RateAdminServiceClient is the client API for RateAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Ручное управление курсами (только для клиентов-администраторов)
+// Ручной курс имеет приоритет над поставщиками и действует ограниченное время
+type RateAdminServiceClient interface {
+	// Установка ручного курса валюты на время ttl_seconds (заменяет действующий ручной курс)
+	SetRateOverride(ctx context.Context, in *SetRateOverrideRequest, opts ...grpc.CallOption) (*RateOverride, error)
+	// Досрочная отмена ручного курса валюты
+	ClearRateOverride(ctx context.Context, in *ClearRateOverrideRequest, opts ...grpc.CallOption) (*RateOverride, error)
+	// Список действующих ручных курсов
+	ListRateOverrides(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RateOverridesResponse, error)
+	// Список курсов поставщиков на карантине (подозрительные значения, ожидающие проверки)
+	ListQuarantinedRates(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*QuarantinedRatesResponse, error)
+	// Решение по курсу на карантине: принятый курс публикуется как курс поставщика, отклоненный - отбрасывается
+	ResolveQuarantinedRate(ctx context.Context, in *ResolveQuarantinedRateRequest, opts ...grpc.CallOption) (*QuarantinedRate, error)
+}
+
+type rateAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRateAdminServiceClient(cc grpc.ClientConnInterface) RateAdminServiceClient {
+	return &rateAdminServiceClient{cc}
+}
+
+func (c *rateAdminServiceClient) SetRateOverride(ctx context.Context, in *SetRateOverrideRequest, opts ...grpc.CallOption) (*RateOverride, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateOverride)
+	err := c.cc.Invoke(ctx, RateAdminService_SetRateOverride_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateAdminServiceClient) ClearRateOverride(ctx context.Context, in *ClearRateOverrideRequest, opts ...grpc.CallOption) (*RateOverride, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateOverride)
+	err := c.cc.Invoke(ctx, RateAdminService_ClearRateOverride_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateAdminServiceClient) ListRateOverrides(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RateOverridesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateOverridesResponse)
+	err := c.cc.Invoke(ctx, RateAdminService_ListRateOverrides_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateAdminServiceClient) ListQuarantinedRates(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*QuarantinedRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuarantinedRatesResponse)
+	err := c.cc.Invoke(ctx, RateAdminService_ListQuarantinedRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateAdminServiceClient) ResolveQuarantinedRate(ctx context.Context, in *ResolveQuarantinedRateRequest, opts ...grpc.CallOption) (*QuarantinedRate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuarantinedRate)
+	err := c.cc.Invoke(ctx, RateAdminService_ResolveQuarantinedRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RateAdminServiceServer is the server API for RateAdminService service.
+// All implementations must embed UnimplementedRateAdminServiceServer
+// for forward compatibility.
+//
+// Ручное управление курсами (только для клиентов-администраторов)
+// Ручной курс имеет приоритет над поставщиками и действует ограниченное время
+type RateAdminServiceServer interface {
+	// Установка ручного курса валюты на время ttl_seconds (заменяет действующий ручной курс)
+	SetRateOverride(context.Context, *SetRateOverrideRequest) (*RateOverride, error)
+	// Досрочная отмена ручного курса валюты
+	ClearRateOverride(context.Context, *ClearRateOverrideRequest) (*RateOverride, error)
+	// Список действующих ручных курсов
+	ListRateOverrides(context.Context, *Empty) (*RateOverridesResponse, error)
+	// Список курсов поставщиков на карантине (подозрительные значения, ожидающие проверки)
+	ListQuarantinedRates(context.Context, *Empty) (*QuarantinedRatesResponse, error)
+	// Решение по курсу на карантине: принятый курс публикуется как курс поставщика, отклоненный - отбрасывается
+	ResolveQuarantinedRate(context.Context, *ResolveQuarantinedRateRequest) (*QuarantinedRate, error)
+	mustEmbedUnimplementedRateAdminServiceServer()
+}
+
+// UnimplementedRateAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRateAdminServiceServer struct{}
+
+func (UnimplementedRateAdminServiceServer) SetRateOverride(context.Context, *SetRateOverrideRequest) (*RateOverride, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRateOverride not implemented")
+}
+func (UnimplementedRateAdminServiceServer) ClearRateOverride(context.Context, *ClearRateOverrideRequest) (*RateOverride, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearRateOverride not implemented")
+}
+func (UnimplementedRateAdminServiceServer) ListRateOverrides(context.Context, *Empty) (*RateOverridesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRateOverrides not implemented")
+}
+func (UnimplementedRateAdminServiceServer) ListQuarantinedRates(context.Context, *Empty) (*QuarantinedRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuarantinedRates not implemented")
+}
+func (UnimplementedRateAdminServiceServer) ResolveQuarantinedRate(context.Context, *ResolveQuarantinedRateRequest) (*QuarantinedRate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveQuarantinedRate not implemented")
+}
+func (UnimplementedRateAdminServiceServer) mustEmbedUnimplementedRateAdminServiceServer() {}
+func (UnimplementedRateAdminServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeRateAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RateAdminServiceServer will
+// result in compilation errors.
+type UnsafeRateAdminServiceServer interface {
+	mustEmbedUnimplementedRateAdminServiceServer()
+}
+
+func RegisterRateAdminServiceServer(s grpc.ServiceRegistrar, srv RateAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRateAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RateAdminService_ServiceDesc, srv)
+}
+
+func _RateAdminService_SetRateOverride_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRateOverrideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateAdminServiceServer).SetRateOverride(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateAdminService_SetRateOverride_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateAdminServiceServer).SetRateOverride(ctx, req.(*SetRateOverrideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateAdminService_ClearRateOverride_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearRateOverrideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateAdminServiceServer).ClearRateOverride(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateAdminService_ClearRateOverride_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateAdminServiceServer).ClearRateOverride(ctx, req.(*ClearRateOverrideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateAdminService_ListRateOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateAdminServiceServer).ListRateOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateAdminService_ListRateOverrides_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateAdminServiceServer).ListRateOverrides(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateAdminService_ListQuarantinedRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateAdminServiceServer).ListQuarantinedRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateAdminService_ListQuarantinedRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateAdminServiceServer).ListQuarantinedRates(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateAdminService_ResolveQuarantinedRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveQuarantinedRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateAdminServiceServer).ResolveQuarantinedRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateAdminService_ResolveQuarantinedRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateAdminServiceServer).ResolveQuarantinedRate(ctx, req.(*ResolveQuarantinedRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RateAdminService_ServiceDesc is the grpc.ServiceDesc for RateAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RateAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "exchange.RateAdminService",
+	HandlerType: (*RateAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetRateOverride",
+			Handler:    _RateAdminService_SetRateOverride_Handler,
+		},
+		{
+			MethodName: "ClearRateOverride",
+			Handler:    _RateAdminService_ClearRateOverride_Handler,
+		},
+		{
+			MethodName: "ListRateOverrides",
+			Handler:    _RateAdminService_ListRateOverrides_Handler,
+		},
+		{
+			MethodName: "ListQuarantinedRates",
+			Handler:    _RateAdminService_ListQuarantinedRates_Handler,
+		},
+		{
+			MethodName: "ResolveQuarantinedRate",
+			Handler:    _RateAdminService_ResolveQuarantinedRate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "exchange.proto",